@@ -0,0 +1,246 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindinfo
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/sessionctx/variable"
+)
+
+// bindCacheKey is the sql digest of the bind records.
+type bindCacheKey string
+
+func calcBindCacheKVMem(key bindCacheKey, value []*BindRecord) int64 {
+	mem := int64(len(key))
+	for _, bindRecord := range value {
+		mem += int64(bindRecord.size())
+	}
+	return mem
+}
+
+// bindCacheEntry is the bind records of a sql digest.
+type bindCacheEntry struct {
+	bindRecords []*BindRecord
+	// lastUsed is the unix nano time when the bind records are used last time, it's accessed atomically.
+	lastUsed int64
+}
+
+// bindCache is the global binding cache, its memory usage is limited by memCapacity. The least recently used bind
+// records are evicted when the memory usage exceeds the capacity, and their keys are remembered so that they can be
+// loaded from the storage again when they are used.
+//
+// The cache is copy-on-write like the BindHandle uses it: the cache which is visible to the readers is never changed,
+// the writers change a copy of it. The reads only update the recency of the entries atomically, so they don't need
+// any lock.
+type bindCache struct {
+	entries     map[bindCacheKey]*bindCacheEntry
+	evicted     map[bindCacheKey]struct{}
+	memCapacity int64
+	memUsage    int64
+}
+
+func newBindCache(memCapacity int64) *bindCache {
+	return &bindCache{
+		entries:     make(map[bindCacheKey]*bindCacheEntry),
+		evicted:     make(map[bindCacheKey]struct{}),
+		memCapacity: memCapacity,
+	}
+}
+
+// newBindCacheWithQuota creates a bindCache whose capacity is the value of tidb_mem_quota_binding_cache.
+func newBindCacheWithQuota() *bindCache {
+	return newBindCache(variable.MemQuotaBindingCache.Load())
+}
+
+// get gets the bind records of the key and marks them as the most recently used ones.
+func (c *bindCache) get(key bindCacheKey) []*BindRecord {
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	atomic.StoreInt64(&entry.lastUsed, time.Now().UnixNano())
+	return entry.bindRecords
+}
+
+// set puts the bind records of the key into the cache and evicts the least recently used ones if the memory usage
+// exceeds the capacity. It returns false if the records alone exceed the capacity, in which case the key is evicted
+// instead, so the records are still loaded from the storage when they are used.
+func (c *bindCache) set(key bindCacheKey, value []*BindRecord) bool {
+	c.delete(key)
+	mem := calcBindCacheKVMem(key, value)
+	if mem > c.memCapacity {
+		c.evicted[key] = struct{}{}
+		return false
+	}
+	c.entries[key] = &bindCacheEntry{bindRecords: value, lastUsed: time.Now().UnixNano()}
+	c.memUsage += mem
+	c.evict()
+	return true
+}
+
+// delete removes the bind records of the key.
+func (c *bindCache) delete(key bindCacheKey) {
+	delete(c.evicted, key)
+	if entry, ok := c.entries[key]; ok {
+		c.memUsage -= calcBindCacheKVMem(key, entry.bindRecords)
+		delete(c.entries, key)
+	}
+}
+
+// evict removes the least recently used items until the memory usage is within the capacity.
+func (c *bindCache) evict() {
+	if c.memUsage <= c.memCapacity {
+		return
+	}
+	type usedKey struct {
+		key      bindCacheKey
+		lastUsed int64
+	}
+	keys := make([]usedKey, 0, len(c.entries))
+	for key, entry := range c.entries {
+		keys = append(keys, usedKey{key: key, lastUsed: atomic.LoadInt64(&entry.lastUsed)})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].lastUsed < keys[j].lastUsed })
+	for _, k := range keys {
+		if c.memUsage <= c.memCapacity {
+			break
+		}
+		bindRecords := c.entries[k.key].bindRecords
+		c.delete(k.key)
+		c.evicted[k.key] = struct{}{}
+		for _, bindRecord := range bindRecords {
+			updateMetrics(metrics.ScopeGlobal, bindRecord, nil, false)
+		}
+		metrics.BindCacheCounter.WithLabelValues(metrics.LblEvict).Inc()
+	}
+}
+
+// GetBindRecord gets the BindRecord of the (normdOrigSQL, db) from the cache.
+func (c *bindCache) GetBindRecord(hash, normdOrigSQL, db string) *BindRecord {
+	for _, bindRecord := range c.get(bindCacheKey(hash)) {
+		if bindRecord.OriginalSQL == normdOrigSQL {
+			return bindRecord
+		}
+	}
+	return nil
+}
+
+// IsEvicted checks whether the bind records of the hash are evicted from the cache.
+func (c *bindCache) IsEvicted(hash string) bool {
+	_, ok := c.evicted[bindCacheKey(hash)]
+	return ok
+}
+
+// GetAllBindRecords gets all the bind records in the cache without changing their recency.
+func (c *bindCache) GetAllBindRecords() []*BindRecord {
+	var bindRecords []*BindRecord
+	for _, entry := range c.entries {
+		bindRecords = append(bindRecords, entry.bindRecords...)
+	}
+	return bindRecords
+}
+
+// SetBindRecord sets the BindRecord to the cache, if there already exists a BindRecord, it will be overridden.
+// It returns false if the bind records of the hash exceed the capacity of the cache and are not cached.
+func (c *bindCache) SetBindRecord(hash string, meta *BindRecord) bool {
+	key := bindCacheKey(hash)
+	metas := c.get(key)
+	newMetas := make([]*BindRecord, 0, len(metas)+1)
+	replaced := false
+	for _, bindRecord := range metas {
+		if bindRecord.OriginalSQL == meta.OriginalSQL {
+			bindRecord = meta
+			replaced = true
+		}
+		newMetas = append(newMetas, bindRecord)
+	}
+	if !replaced {
+		newMetas = append(newMetas, meta)
+	}
+	return c.set(key, newMetas)
+}
+
+// RemoveBindRecord removes the BindRecord which has same originSQL with specified BindRecord.
+func (c *bindCache) RemoveBindRecord(hash string, meta *BindRecord) {
+	key := bindCacheKey(hash)
+	metas := c.get(key)
+	if metas == nil {
+		return
+	}
+	newMetas := make([]*BindRecord, 0, len(metas))
+	for _, bindRecord := range metas {
+		if bindRecord.isSame(meta) {
+			bindRecord = bindRecord.remove(meta)
+			if len(bindRecord.Bindings) == 0 {
+				continue
+			}
+		}
+		newMetas = append(newMetas, bindRecord)
+	}
+	if len(newMetas) == 0 {
+		c.delete(key)
+		return
+	}
+	c.set(key, newMetas)
+}
+
+// RemoveEvicted forgets the evicted bind records of the hash, it's used when they don't exist in the storage.
+func (c *bindCache) RemoveEvicted(hash string) {
+	delete(c.evicted, bindCacheKey(hash))
+}
+
+// SetMemCapacity sets the memory capacity of the cache, the least recently used items are evicted if the capacity
+// is decreased.
+func (c *bindCache) SetMemCapacity(capacity int64) {
+	c.memCapacity = capacity
+	c.evict()
+}
+
+// GetMemCapacity gets the memory capacity of the cache.
+func (c *bindCache) GetMemCapacity() int64 {
+	return c.memCapacity
+}
+
+// GetMemUsage gets the memory usage of the cache.
+func (c *bindCache) GetMemUsage() int64 {
+	return c.memUsage
+}
+
+// Size returns the number of the bind records in the cache.
+func (c *bindCache) Size() int {
+	size := 0
+	for _, entry := range c.entries {
+		size += len(entry.bindRecords)
+	}
+	return size
+}
+
+// Copy copies the cache with the same recency, the bind records are shared.
+func (c *bindCache) Copy() *bindCache {
+	newCache := newBindCache(c.memCapacity)
+	for key, entry := range c.entries {
+		bindRecords := make([]*BindRecord, len(entry.bindRecords))
+		copy(bindRecords, entry.bindRecords)
+		newCache.entries[key] = &bindCacheEntry{bindRecords: bindRecords, lastUsed: atomic.LoadInt64(&entry.lastUsed)}
+	}
+	for key := range c.evicted {
+		newCache.evicted[key] = struct{}{}
+	}
+	newCache.memUsage = c.memUsage
+	return newCache
+}
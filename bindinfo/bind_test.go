@@ -25,6 +25,7 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/terror"
@@ -958,6 +959,36 @@ func (s *testSuite) TestCapturePlanBaseline(c *C) {
 	c.Assert(rows[0][1], Equals, "SELECT /*+ use_index(@`sel_1` `test`.`t` )*/ * FROM `test`.`t` WHERE `a` > 10")
 }
 
+func (s *testSuite) TestCreateBindingFromHistory(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	s.cleanBindingEnv(tk)
+	stmtsummary.StmtSummaryByDigestMap.Clear()
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, key(a), key(b))")
+
+	_, err := s.domain.BindHandle().CreateBindRecordFromHistory("not_exist_digest")
+	c.Assert(err, ErrorMatches, "can't find any plans for 'not_exist_digest'")
+
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil), IsTrue)
+	tk.MustExec("select * from t where a > 1 and b > 1")
+	rows := tk.MustQuery("select plan_digest from information_schema.statements_summary where query_sample_text = 'select * from t where a > 1 and b > 1'").Rows()
+	c.Assert(len(rows), Equals, 1)
+	planDigest := rows[0][0].(string)
+
+	record, err := s.domain.BindHandle().CreateBindRecordFromHistory(planDigest)
+	c.Assert(err, IsNil)
+	c.Assert(record.OriginalSQL, Equals, "select * from `test` . `t` where `a` > ? and `b` > ?")
+	rows = tk.MustQuery("show global bindings").Rows()
+	c.Assert(len(rows), Equals, 1)
+	c.Assert(rows[0][0], Equals, "select * from `test` . `t` where `a` > ? and `b` > ?")
+	c.Assert(rows[0][1], Equals, "SELECT /*+ use_index(@`sel_1` `test`.`t` )*/ * FROM `test`.`t` WHERE `a` > 1 AND `b` > 1")
+	c.Assert(rows[0][8], Equals, bindinfo.History)
+
+	tk.MustExec("select * from t where a > 2 and b > 2")
+	tk.MustQuery("select @@last_plan_from_binding").Check(testkit.Rows("1"))
+}
+
 func (s *testSuite) TestCaptureDBCaseSensitivity(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	s.cleanBindingEnv(tk)
@@ -2114,3 +2145,131 @@ func (s *testSuite) TestTemporaryTable(c *C) {
 	tk.MustGetErrCode("create binding for update t set a = 1 where b = 1 and c > 1 using update /*+ use_index(t, c) */ t set a = 1 where b = 1 and c > 1", errno.ErrOptOnTemporaryTable)
 	tk.MustGetErrCode("create binding for delete from t where b = 1 and c > 1 using delete /*+ use_index(t, c) */ from t where b = 1 and c > 1", errno.ErrOptOnTemporaryTable)
 }
+
+func (s *testSuite) TestSessionSQLHints(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	s.cleanBindingEnv(tk)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, index ia(a), index ib(b))")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+
+	sql := "select * from t where a = 1 and b > 1"
+	_, digest := parser.NormalizeDigest(sql)
+	tk.MustQuery(sql)
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.IndexNames[0], Equals, "t:ia")
+
+	tk.MustExec(fmt.Sprintf("set session sql_hints = '%s: use_index(t, ib); %s: use_index(t, ia)'", digest.String(), "0123"))
+	// The statements of the same digest are hinted.
+	tk.MustQuery("select * from t where a = 2 and b > 2")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.IndexNames[0], Equals, "t:ib")
+	tk.MustQuery("select @@last_plan_from_binding").Check(testkit.Rows("0"))
+	c.Assert(tk.MustUseIndex(sql, "ib(b)"), IsTrue)
+	tk.MustQuery("select * from t where a = 1")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.IndexNames[0], Equals, "t:ia")
+	// The statement isn't changed by the injected hints.
+	stmt, err := parser.New().ParseOneStmt(sql, "", "")
+	c.Assert(err, IsNil)
+	_, err = tk.Se.ExecuteStmt(context.Background(), stmt)
+	c.Assert(err, IsNil)
+	c.Assert(stmt.(*ast.SelectStmt).TableHints, HasLen, 0)
+
+	// Other sessions aren't affected.
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.MustExec("use test")
+	tk1.MustQuery(sql)
+	c.Assert(tk1.Se.GetSessionVars().StmtCtx.IndexNames[0], Equals, "t:ia")
+
+	tk.MustGetErrCode("set session sql_hints = 'use_index(t, ib)'", errno.ErrWrongValueForVar)
+	tk.MustGetErrCode("set session sql_hints = ':use_index(t, ib)'", errno.ErrWrongValueForVar)
+	tk.MustGetErrCode(fmt.Sprintf("set session sql_hints = '%s: no_such_hint(t)'", digest.String()), errno.ErrWrongValueForVar)
+	tk.MustGetErrCode(fmt.Sprintf("set session sql_hints = '%s: '", digest.String()), errno.ErrWrongValueForVar)
+	tk.MustGetErrCode(fmt.Sprintf("set global sql_hints = '%s: use_index(t, ib)'", digest.String()), errno.ErrLocalVariable)
+	tk.MustQuery(sql)
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.IndexNames[0], Equals, "t:ib")
+
+	tk.MustExec("set session sql_hints = ''")
+	tk.MustQuery(sql)
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.IndexNames[0], Equals, "t:ia")
+}
+
+func (s *testSuite) TestBindCacheEviction(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	s.cleanBindingEnv(tk)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int, index idx(a))")
+	tk.MustQuery("select @@global.tidb_mem_quota_binding_cache").Check(testkit.Rows("67108864"))
+	defer func() {
+		tk.MustExec("set global tidb_mem_quota_binding_cache = default")
+		s.cleanBindingEnv(tk)
+	}()
+
+	bindHandle := s.domain.BindHandle()
+	createBinding := func(col string) {
+		tk.MustExec(fmt.Sprintf("create global binding for select * from t where %s = 1 using select /*+ use_index(t, idx) */ * from t where %s = 1", col, col))
+	}
+	getBinding := func(col string) *bindinfo.BindRecord {
+		sql, hash := normalizeWithDefaultDB(c, fmt.Sprintf("select * from t where %s = 1", col), "test")
+		return bindHandle.GetBindRecord(hash, sql, "test")
+	}
+	// The bind records of the same length are used to make the cache hold exactly two records.
+	createBinding("a")
+	recordMem := bindHandle.MemUsage()
+	c.Assert(recordMem > 0, IsTrue)
+	tk.MustExec(fmt.Sprintf("set global tidb_mem_quota_binding_cache = %d", 2*recordMem))
+	s.cleanBindingEnv(tk)
+
+	pb := &dto.Metric{}
+	err := metrics.BindCacheCounter.WithLabelValues(metrics.LblEvict).Write(pb)
+	c.Assert(err, IsNil)
+	evicted := pb.GetCounter().GetValue()
+
+	createBinding("a")
+	createBinding("b")
+	c.Assert(bindHandle.Size(), Equals, 2)
+	c.Assert(bindHandle.MemUsage(), Equals, 2*recordMem)
+	// Touch the binding on `a` to make the binding on `b` the least recently used one.
+	c.Assert(getBinding("a"), NotNil)
+	createBinding("c")
+	c.Assert(bindHandle.Size(), Equals, 2)
+	c.Assert(bindHandle.MemUsage(), Equals, 2*recordMem)
+	err = metrics.BindCacheCounter.WithLabelValues(metrics.LblEvict).Write(pb)
+	c.Assert(err, IsNil)
+	c.Assert(pb.GetCounter().GetValue(), Equals, evicted+1)
+	c.Assert(getBinding("a"), NotNil)
+	c.Assert(getBinding("c"), NotNil)
+	// The evicted binding is loaded from the storage again, and the binding on `a` is evicted.
+	c.Assert(getBinding("b"), NotNil)
+	c.Assert(bindHandle.Size(), Equals, 2)
+	c.Assert(bindHandle.MemUsage(), Equals, 2*recordMem)
+	err = metrics.BindCacheCounter.WithLabelValues(metrics.LblEvict).Write(pb)
+	c.Assert(err, IsNil)
+	c.Assert(pb.GetCounter().GetValue(), Equals, evicted+2)
+	tk.MustExec("select * from t where a = 1")
+	tk.MustQuery("select @@last_plan_from_binding").Check(testkit.Rows("1"))
+	c.Assert(bindHandle.Size(), Equals, 2)
+	c.Assert(getBinding("a"), NotNil)
+
+	// The least recently used bindings are evicted when the capacity is decreased.
+	tk.MustExec(fmt.Sprintf("set global tidb_mem_quota_binding_cache = %d", recordMem))
+	c.Assert(bindHandle.Update(false), IsNil)
+	c.Assert(bindHandle.Size(), Equals, 1)
+	c.Assert(bindHandle.GetAllBindRecord()[0].OriginalSQL, Equals, "select * from `test` . `t` where `a` = ?")
+
+	// The dropped binding isn't loaded again.
+	tk.MustExec("drop global binding for select * from t where b = 1")
+	c.Assert(bindHandle.Update(false), IsNil)
+	c.Assert(getBinding("b"), IsNil)
+	c.Assert(bindHandle.Size(), Equals, 1)
+
+	// The bind records larger than the capacity are not cached, but they are still used.
+	tk.MustExec("set global tidb_mem_quota_binding_cache = 1")
+	s.cleanBindingEnv(tk)
+	createBinding("a")
+	c.Assert(bindHandle.Size(), Equals, 0)
+	c.Assert(bindHandle.MemUsage(), Equals, int64(0))
+	tk.MustExec("select * from t where a = 1")
+	tk.MustQuery("select @@last_plan_from_binding").Check(testkit.Rows("1"))
+	c.Assert(bindHandle.Size(), Equals, 0)
+}
@@ -42,6 +42,8 @@ const (
 	Capture = "capture"
 	// Evolve indicates the binding is evolved by TiDB from old bindings.
 	Evolve = "evolve"
+	// History indicates the binding is created from a plan in statements_summary by its plan digest.
+	History = "history"
 	// Builtin indicates the binding is a builtin record for internal locking purpose. It is also the status for the builtin binding.
 	Builtin = "builtin"
 )
@@ -247,6 +249,15 @@ func (br *BindRecord) metrics() ([]float64, []int) {
 	return sizes, count
 }
 
+// size calculates the memory size of a bind record.
+func (br *BindRecord) size() float64 {
+	res := float64(len(br.OriginalSQL) + len(br.Db))
+	for _, binding := range br.Bindings {
+		res += binding.size()
+	}
+	return res
+}
+
 // size calculates the memory size of a bind info.
 func (b *Binding) size() float64 {
 	res := len(b.BindSQL) + len(b.Status) + 2*int(unsafe.Sizeof(b.CreateTime)) + len(b.Charset) + len(b.Collation)
@@ -23,6 +23,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/parser"
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/mysql"
@@ -49,7 +50,8 @@ type BindHandle struct {
 		sessionctx.Context
 	}
 
-	// bindInfo caches the sql bind info from storage.
+	// bindInfo caches the sql bind info from storage. The memory usage of the cache is limited by
+	// tidb_mem_quota_binding_cache, the evicted bindings are loaded from storage again when they are used.
 	//
 	// The Mutex protects that there is only one goroutine changes the content
 	// of atomic.Value.
@@ -103,7 +105,7 @@ type bindRecordUpdate struct {
 func NewBindHandle(ctx sessionctx.Context) *BindHandle {
 	handle := &BindHandle{}
 	handle.sctx.Context = ctx
-	handle.bindInfo.Value.Store(make(cache, 32))
+	handle.bindInfo.Value.Store(newBindCacheWithQuota())
 	handle.bindInfo.parser = parser.New()
 	handle.invalidBindRecordMap.Value.Store(make(map[string]*bindRecordUpdate))
 	handle.invalidBindRecordMap.flushFunc = func(record *BindRecord) error {
@@ -142,7 +144,8 @@ func (h *BindHandle) Update(fullLoad bool) (err error) {
 		return err
 	}
 
-	newCache := h.bindInfo.Value.Load().(cache).copy()
+	newCache := h.bindInfo.Value.Load().(*bindCache).Copy()
+	newCache.SetMemCapacity(variable.MemQuotaBindingCache.Load())
 	defer func() {
 		h.bindInfo.lastUpdateTime = lastUpdateTime
 		h.bindInfo.Value.Store(newCache)
@@ -163,15 +166,21 @@ func (h *BindHandle) Update(fullLoad bool) (err error) {
 		if meta.Bindings[0].UpdateTime.Compare(lastUpdateTime) > 0 {
 			lastUpdateTime = meta.Bindings[0].UpdateTime
 		}
+		// The evicted bind record is loaded from storage as a whole when it is used.
+		if newCache.IsEvicted(hash) {
+			continue
+		}
 
-		oldRecord := newCache.getBindRecord(hash, meta.OriginalSQL, meta.Db)
+		oldRecord := newCache.GetBindRecord(hash, meta.OriginalSQL, meta.Db)
 		newRecord := merge(oldRecord, meta).removeDeletedBindings()
 		if len(newRecord.Bindings) > 0 {
-			newCache.setBindRecord(hash, newRecord)
+			if !newCache.SetBindRecord(hash, newRecord) {
+				logBindCacheExceeded(newRecord)
+			}
 		} else {
-			newCache.removeDeletedBindRecord(hash, newRecord)
+			newCache.RemoveBindRecord(hash, newRecord)
 		}
-		updateMetrics(metrics.ScopeGlobal, oldRecord, newCache.getBindRecord(hash, meta.OriginalSQL, meta.Db), true)
+		updateMetrics(metrics.ScopeGlobal, oldRecord, newCache.GetBindRecord(hash, meta.OriginalSQL, meta.Db), true)
 	}
 	return nil
 }
@@ -473,25 +482,83 @@ func (h *BindHandle) AddDropInvalidBindTask(invalidBindRecord *BindRecord) {
 
 // Size returns the size of bind info cache.
 func (h *BindHandle) Size() int {
-	size := 0
-	for _, bindRecords := range h.bindInfo.Load().(cache) {
-		size += len(bindRecords)
-	}
-	return size
+	return h.bindInfo.Load().(*bindCache).Size()
+}
+
+// MemUsage returns the memory usage of bind info cache.
+func (h *BindHandle) MemUsage() int64 {
+	return h.bindInfo.Load().(*bindCache).GetMemUsage()
 }
 
 // GetBindRecord returns the BindRecord of the (normdOrigSQL,db) if BindRecord exist.
 func (h *BindHandle) GetBindRecord(hash, normdOrigSQL, db string) *BindRecord {
-	return h.bindInfo.Load().(cache).getBindRecord(hash, normdOrigSQL, db)
+	c := h.bindInfo.Load().(*bindCache)
+	bindRecord := c.GetBindRecord(hash, normdOrigSQL, db)
+	if bindRecord != nil {
+		metrics.BindCacheCounter.WithLabelValues(metrics.LblHit).Inc()
+		return bindRecord
+	}
+	metrics.BindCacheCounter.WithLabelValues(metrics.LblMiss).Inc()
+	if c.IsEvicted(hash) {
+		bindRecord = h.loadEvictedBindRecord(hash, normdOrigSQL, db)
+	}
+	return bindRecord
+}
+
+// loadEvictedBindRecord loads the evicted BindRecord of the (normdOrigSQL,db) from storage and puts it into the cache.
+func (h *BindHandle) loadEvictedBindRecord(hash, normdOrigSQL, db string) *BindRecord {
+	h.bindInfo.Lock()
+	defer h.bindInfo.Unlock()
+	oldCache := h.bindInfo.Value.Load().(*bindCache)
+	// The bind record may have been loaded by the others.
+	if !oldCache.IsEvicted(hash) {
+		return oldCache.GetBindRecord(hash, normdOrigSQL, db)
+	}
+
+	exec := h.sctx.Context.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(context.TODO(), `SELECT original_sql, bind_sql, default_db, status, create_time, update_time, charset, collation, source
+	FROM mysql.bind_info WHERE original_sql = %? ORDER BY update_time`, normdOrigSQL)
+	if err != nil {
+		logutil.BgLogger().Warn("[sql-bind] failed to load the evicted bind record", zap.Error(err))
+		return nil
+	}
+	// No need to acquire the session context lock for ExecRestrictedStmt, it
+	// uses another background session.
+	rows, _, err := exec.ExecRestrictedStmt(context.Background(), stmt)
+	if err != nil {
+		logutil.BgLogger().Warn("[sql-bind] failed to load the evicted bind record", zap.Error(err))
+		return nil
+	}
+	var bindRecord *BindRecord
+	for _, row := range rows {
+		_, meta, err := h.newBindRecord(row)
+		if err != nil {
+			logutil.BgLogger().Debug("[sql-bind] failed to generate bind record from data row", zap.Error(err))
+			continue
+		}
+		bindRecord = merge(bindRecord, meta)
+	}
+
+	newCache := oldCache.Copy()
+	if bindRecord != nil {
+		bindRecord = bindRecord.removeDeletedBindings()
+	}
+	if bindRecord == nil || len(bindRecord.Bindings) == 0 {
+		newCache.RemoveEvicted(hash)
+		h.bindInfo.Value.Store(newCache)
+		return nil
+	}
+	if !newCache.SetBindRecord(hash, bindRecord) {
+		logBindCacheExceeded(bindRecord)
+	}
+	h.bindInfo.Value.Store(newCache)
+	updateMetrics(metrics.ScopeGlobal, nil, newCache.GetBindRecord(hash, normdOrigSQL, db), false)
+	return bindRecord
 }
 
 // GetAllBindRecord returns all bind records in cache.
 func (h *BindHandle) GetAllBindRecord() (bindRecords []*BindRecord) {
-	bindRecordMap := h.bindInfo.Load().(cache)
-	for _, bindRecord := range bindRecordMap {
-		bindRecords = append(bindRecords, bindRecord...)
-	}
-	return bindRecords
+	return h.bindInfo.Load().(*bindCache).GetAllBindRecords()
 }
 
 // newBindRecord builds BindRecord from a tuple in storage.
@@ -521,31 +588,45 @@ func (h *BindHandle) newBindRecord(row chunk.Row) (string, *BindRecord, error) {
 // setBindRecord sets the BindRecord to the cache, if there already exists a BindRecord,
 // it will be overridden.
 func (h *BindHandle) setBindRecord(hash string, meta *BindRecord) {
-	newCache := h.bindInfo.Value.Load().(cache).copy()
-	oldRecord := newCache.getBindRecord(hash, meta.OriginalSQL, meta.Db)
-	newCache.setBindRecord(hash, meta)
+	newCache := h.bindInfo.Value.Load().(*bindCache).Copy()
+	oldRecord := newCache.GetBindRecord(hash, meta.OriginalSQL, meta.Db)
+	if !newCache.SetBindRecord(hash, meta) {
+		logBindCacheExceeded(meta)
+	}
 	h.bindInfo.Value.Store(newCache)
-	updateMetrics(metrics.ScopeGlobal, oldRecord, meta, false)
+	updateMetrics(metrics.ScopeGlobal, oldRecord, newCache.GetBindRecord(hash, meta.OriginalSQL, meta.Db), false)
 }
 
 // appendBindRecord addes the BindRecord to the cache, all the stale BindRecords are
 // removed from the cache after this operation.
 func (h *BindHandle) appendBindRecord(hash string, meta *BindRecord) {
-	newCache := h.bindInfo.Value.Load().(cache).copy()
-	oldRecord := newCache.getBindRecord(hash, meta.OriginalSQL, meta.Db)
+	// The evicted bind record is loaded from storage as a whole when it is used.
+	if h.bindInfo.Value.Load().(*bindCache).IsEvicted(hash) {
+		return
+	}
+	newCache := h.bindInfo.Value.Load().(*bindCache).Copy()
+	oldRecord := newCache.GetBindRecord(hash, meta.OriginalSQL, meta.Db)
 	newRecord := merge(oldRecord, meta)
-	newCache.setBindRecord(hash, newRecord)
+	if !newCache.SetBindRecord(hash, newRecord) {
+		logBindCacheExceeded(newRecord)
+	}
 	h.bindInfo.Value.Store(newCache)
-	updateMetrics(metrics.ScopeGlobal, oldRecord, newRecord, false)
+	updateMetrics(metrics.ScopeGlobal, oldRecord, newCache.GetBindRecord(hash, meta.OriginalSQL, meta.Db), false)
 }
 
 // removeBindRecord removes the BindRecord from the cache.
 func (h *BindHandle) removeBindRecord(hash string, meta *BindRecord) {
-	newCache := h.bindInfo.Value.Load().(cache).copy()
-	oldRecord := newCache.getBindRecord(hash, meta.OriginalSQL, meta.Db)
-	newCache.removeDeletedBindRecord(hash, meta)
+	newCache := h.bindInfo.Value.Load().(*bindCache).Copy()
+	oldRecord := newCache.GetBindRecord(hash, meta.OriginalSQL, meta.Db)
+	newCache.RemoveBindRecord(hash, meta)
 	h.bindInfo.Value.Store(newCache)
-	updateMetrics(metrics.ScopeGlobal, oldRecord, newCache.getBindRecord(hash, meta.OriginalSQL, meta.Db), false)
+	updateMetrics(metrics.ScopeGlobal, oldRecord, newCache.GetBindRecord(hash, meta.OriginalSQL, meta.Db), false)
+}
+
+func logBindCacheExceeded(meta *BindRecord) {
+	logutil.BgLogger().Warn("[sql-bind] the memory usage of the bind record exceeds the capacity of the binding cache, it is not cached",
+		zap.String("originalSQL", meta.OriginalSQL), zap.String("db", meta.Db),
+		zap.Int64("capacity", variable.MemQuotaBindingCache.Load()))
 }
 
 // removeDeletedBindRecord removes the BindRecord which has same originSQL and db with specified BindRecord.
@@ -581,16 +662,6 @@ func (c cache) setBindRecord(hash string, meta *BindRecord) {
 	c[hash] = append(c[hash], meta)
 }
 
-func (c cache) copy() cache {
-	newCache := make(cache, len(c))
-	for k, v := range c {
-		bindRecords := make([]*BindRecord, len(v))
-		copy(bindRecords, v)
-		newCache[k] = bindRecords
-	}
-	return newCache
-}
-
 func copyBindRecordUpdateMap(oldMap map[string]*bindRecordUpdate) map[string]*bindRecordUpdate {
 	newMap := make(map[string]*bindRecordUpdate, len(oldMap))
 	for k, v := range oldMap {
@@ -614,39 +685,74 @@ func (h *BindHandle) CaptureBaselines() {
 	parser4Capture := parser.New()
 	bindableStmts := stmtsummary.StmtSummaryByDigestMap.GetMoreThanOnceBindableStmt()
 	for _, bindableStmt := range bindableStmts {
-		stmt, err := parser4Capture.ParseOneStmt(bindableStmt.Query, bindableStmt.Charset, bindableStmt.Collation)
+		record, err := h.newBindRecordFromBindableStmt(parser4Capture, bindableStmt, Capture)
 		if err != nil {
 			logutil.BgLogger().Debug("[sql-bind] parse SQL failed in baseline capture", zap.String("SQL", bindableStmt.Query), zap.Error(err))
 			continue
 		}
-		if insertStmt, ok := stmt.(*ast.InsertStmt); ok && insertStmt.Select == nil {
-			continue
-		}
-		dbName := utilparser.GetDefaultDB(stmt, bindableStmt.Schema)
-		normalizedSQL, digest := parser.NormalizeDigest(utilparser.RestoreWithDefaultDB(stmt, dbName, bindableStmt.Query))
-		if r := h.GetBindRecord(digest.String(), normalizedSQL, dbName); r != nil && r.HasUsingBinding() {
+		if record == nil {
 			continue
 		}
-		bindSQL := GenerateBindSQL(context.TODO(), stmt, bindableStmt.PlanHint, true, dbName)
-		if bindSQL == "" {
+		if r := h.GetBindRecord(parser.DigestNormalized(record.OriginalSQL).String(), record.OriginalSQL, record.Db); r != nil && r.HasUsingBinding() {
 			continue
 		}
-		charset, collation := h.sctx.GetSessionVars().GetCharsetInfo()
-		binding := Binding{
-			BindSQL:   bindSQL,
-			Status:    Using,
-			Charset:   charset,
-			Collation: collation,
-			Source:    Capture,
-		}
 		// We don't need to pass the `sctx` because the BindSQL has been validated already.
-		err = h.CreateBindRecord(nil, &BindRecord{OriginalSQL: normalizedSQL, Db: dbName, Bindings: []Binding{binding}})
+		err = h.CreateBindRecord(nil, record)
 		if err != nil {
 			logutil.BgLogger().Debug("[sql-bind] create bind record failed in baseline capture", zap.String("SQL", bindableStmt.Query), zap.Error(err))
 		}
 	}
 }
 
+// CreateBindRecordFromHistory creates a global binding which pins the plan with digest `planDigest`.
+// The plan is looked up in statements_summary, and the binding hints are reconstructed from the hints
+// recorded along with the plan, so that a known-good plan can be pinned without writing hints by hand.
+func (h *BindHandle) CreateBindRecordFromHistory(planDigest string) (*BindRecord, error) {
+	bindableStmt := stmtsummary.StmtSummaryByDigestMap.GetBindableStmtByPlanDigest(planDigest)
+	if bindableStmt == nil {
+		return nil, errors.Errorf("can't find any plans for '%s'", planDigest)
+	}
+	record, err := h.newBindRecordFromBindableStmt(parser.New(), bindableStmt, History)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, errors.Errorf("can't create binding for the plan '%s' of query '%s'", planDigest, bindableStmt.Query)
+	}
+	// We don't need to pass the `sctx` because the hints are generated from an executed plan.
+	if err = h.CreateBindRecord(nil, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// newBindRecordFromBindableStmt builds a BindRecord from the statement extracted from statements_summary.
+// It returns nil if the statement is not bindable.
+func (h *BindHandle) newBindRecordFromBindableStmt(p *parser.Parser, bindableStmt *stmtsummary.BindableStmt, source string) (*BindRecord, error) {
+	stmt, err := p.ParseOneStmt(bindableStmt.Query, bindableStmt.Charset, bindableStmt.Collation)
+	if err != nil {
+		return nil, err
+	}
+	if insertStmt, ok := stmt.(*ast.InsertStmt); ok && insertStmt.Select == nil {
+		return nil, nil
+	}
+	dbName := utilparser.GetDefaultDB(stmt, bindableStmt.Schema)
+	normalizedSQL, _ := parser.NormalizeDigest(utilparser.RestoreWithDefaultDB(stmt, dbName, bindableStmt.Query))
+	bindSQL := GenerateBindSQL(context.TODO(), stmt, bindableStmt.PlanHint, true, dbName)
+	if bindSQL == "" {
+		return nil, nil
+	}
+	charset, collation := h.sctx.GetSessionVars().GetCharsetInfo()
+	binding := Binding{
+		BindSQL:   bindSQL,
+		Status:    Using,
+		Charset:   charset,
+		Collation: collation,
+		Source:    source,
+	}
+	return &BindRecord{OriginalSQL: normalizedSQL, Db: dbName, Bindings: []Binding{binding}}, nil
+}
+
 func getHintsForSQL(sctx sessionctx.Context, sql string) (string, error) {
 	origVals := sctx.GetSessionVars().UsePlanBaselines
 	sctx.GetSessionVars().UsePlanBaselines = false
@@ -808,25 +914,22 @@ const (
 )
 
 func (h *BindHandle) getOnePendingVerifyJob() (string, string, Binding) {
-	cache := h.bindInfo.Value.Load().(cache)
-	for _, bindRecords := range cache {
-		for _, bindRecord := range bindRecords {
-			for _, bind := range bindRecord.Bindings {
-				if bind.Status == PendingVerify {
-					return bindRecord.OriginalSQL, bindRecord.Db, bind
-				}
-				if bind.Status != Rejected {
-					continue
-				}
-				dur, err := bind.SinceUpdateTime()
-				// Should not happen.
-				if err != nil {
-					continue
-				}
-				// Rejected and retry it now.
-				if dur > nextVerifyDuration {
-					return bindRecord.OriginalSQL, bindRecord.Db, bind
-				}
+	for _, bindRecord := range h.bindInfo.Value.Load().(*bindCache).GetAllBindRecords() {
+		for _, bind := range bindRecord.Bindings {
+			if bind.Status == PendingVerify {
+				return bindRecord.OriginalSQL, bindRecord.Db, bind
+			}
+			if bind.Status != Rejected {
+				continue
+			}
+			dur, err := bind.SinceUpdateTime()
+			// Should not happen.
+			if err != nil {
+				continue
+			}
+			// Rejected and retry it now.
+			if dur > nextVerifyDuration {
+				return bindRecord.OriginalSQL, bindRecord.Db, bind
 			}
 		}
 	}
@@ -939,7 +1042,7 @@ func (h *BindHandle) HandleEvolvePlanTask(sctx sessionctx.Context, adminEvolve b
 // Clear resets the bind handle. It is only used for test.
 func (h *BindHandle) Clear() {
 	h.bindInfo.Lock()
-	h.bindInfo.Store(make(cache))
+	h.bindInfo.Store(newBindCacheWithQuota())
 	h.bindInfo.lastUpdateTime = types.ZeroTimestamp
 	h.bindInfo.Unlock()
 	h.invalidBindRecordMap.Store(make(map[string]*bindRecordUpdate))
@@ -957,7 +1060,7 @@ func (h *BindHandle) FlushBindings() error {
 // It is used to maintain consistency between cache and mysql.bind_info if the table is deleted or truncated.
 func (h *BindHandle) ReloadBindings() error {
 	h.bindInfo.Lock()
-	h.bindInfo.Store(make(cache))
+	h.bindInfo.Store(newBindCacheWithQuota())
 	h.bindInfo.lastUpdateTime = types.ZeroTimestamp
 	h.bindInfo.Unlock()
 	return h.Update(true)
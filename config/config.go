@@ -52,6 +52,10 @@ const (
 	DefIndexLimit = 64
 	// DefMaxOfIndexLimit is the maximum limitation of index on a single table for TiDB.
 	DefMaxOfIndexLimit = 64 * 8
+	// DefDDLGeneralWorkerCount is the default count of the general DDL workers.
+	DefDDLGeneralWorkerCount = 1
+	// DefMaxOfDDLGeneralWorkerCount is the maximum count of the general DDL workers.
+	DefMaxOfDDLGeneralWorkerCount = 32
 	// DefPort is the default port of TiDB
 	DefPort = 4000
 	// DefStatusPort is the default status port of TiDB
@@ -126,11 +130,19 @@ type Config struct {
 	IndexLimit                 int                `toml:"index-limit" json:"index-limit"`
 	TableColumnCountLimit      uint32             `toml:"table-column-count-limit" json:"table-column-count-limit"`
 	GracefulWaitBeforeShutdown int                `toml:"graceful-wait-before-shutdown" json:"graceful-wait-before-shutdown"`
+	// GracefulShutdownTimeout is the max seconds to wait for the in-flight transactions to finish when shutting down,
+	// the remaining connections are killed after it.
+	GracefulShutdownTimeout int `toml:"graceful-shutdown-timeout" json:"graceful-shutdown-timeout"`
+	// GracefulShutdownRedirect indicates whether to send the ER_SERVER_SHUTDOWN error to the connections closed when
+	// shutting down, so the proxies know to reconnect to other TiDB servers.
+	GracefulShutdownRedirect bool `toml:"graceful-shutdown-redirect" json:"graceful-shutdown-redirect"`
 	// AlterPrimaryKey is used to control alter primary key feature.
 	AlterPrimaryKey bool `toml:"alter-primary-key" json:"alter-primary-key"`
 	// TreatOldVersionUTF8AsUTF8MB4 is use to treat old version table/column UTF8 charset as UTF8MB4. This is for compatibility.
 	// Currently not support dynamic modify, because this need to reload all old version schema.
 	TreatOldVersionUTF8AsUTF8MB4 bool `toml:"treat-old-version-utf8-as-utf8mb4" json:"treat-old-version-utf8-as-utf8mb4"`
+	// DDLGeneralWorkerCount is the count of workers which handle the general DDL jobs concurrently on the DDL owner.
+	DDLGeneralWorkerCount uint `toml:"ddl-general-worker-count" json:"ddl-general-worker-count"`
 	// EnableTableLock indicate whether enable table lock.
 	// TODO: remove this after table lock features stable.
 	EnableTableLock     bool        `toml:"enable-table-lock" json:"enable-table-lock"`
@@ -318,6 +330,10 @@ type Log struct {
 	ExpensiveThreshold  uint   `toml:"expensive-threshold" json:"expensive-threshold"`
 	QueryLogMaxLen      uint64 `toml:"query-log-max-len" json:"query-log-max-len"`
 	RecordPlanInSlowLog uint32 `toml:"record-plan-in-slow-log" json:"record-plan-in-slow-log"`
+	// RedactSensitiveColumns are the sensitive columns whose bound literals are masked in the slow log, the statement
+	// summary and the error messages. Each item is either "db.table" for all the columns of the table, or
+	// "db.table.column" for a single column.
+	RedactSensitiveColumns []string `toml:"redact-sensitive-columns" json:"redact-sensitive-columns"`
 }
 
 func (l *Log) getDisableTimestamp() bool {
@@ -365,6 +381,8 @@ type Security struct {
 	SpilledFileEncryptionMethod string `toml:"spilled-file-encryption-method" json:"spilled-file-encryption-method"`
 	// EnableSEM prevents SUPER users from having full access.
 	EnableSEM bool `toml:"enable-sem" json:"enable-sem"`
+	// ProbeUser is the user of the health checks, its connections only answer COM_PING after the authentication.
+	ProbeUser string `toml:"probe-user" json:"probe-user"`
 }
 
 // The ErrConfigValidationFailed error is used so that external callers can do a type assertion
@@ -425,6 +443,18 @@ type Performance struct {
 	IndexUsageSyncLease   string  `toml:"index-usage-sync-lease" json:"index-usage-sync-lease"`
 	GOGC                  int     `toml:"gogc" json:"gogc"`
 	EnforceMPP            bool    `toml:"enforce-mpp" json:"enforce-mpp"`
+	// RegionCacheWarmupTables is the max number of the recently accessed tables persisted on shutdown,
+	// whose regions are loaded into the region cache after restarts. 0 means disabling it.
+	RegionCacheWarmupTables uint `toml:"region-cache-warmup-tables" json:"region-cache-warmup-tables"`
+	// TxnSummaryCapacity is the max number of the recently committed transactions recorded in the
+	// information_schema.transaction_summary table. 0 means disabling it.
+	TxnSummaryCapacity uint `toml:"txn-summary-capacity" json:"txn-summary-capacity"`
+	// WriteConflictHistoryCapacity is the max number of the recent write conflicts recorded in the
+	// information_schema.write_conflicts table. 0 means disabling it.
+	WriteConflictHistoryCapacity uint `toml:"write-conflict-history-capacity" json:"write-conflict-history-capacity"`
+	// ServerMemoryQuotaFromCgroup indicates whether the server memory quota is derived from the cgroup memory limit
+	// when server-memory-quota is 0.
+	ServerMemoryQuotaFromCgroup bool `toml:"server-memory-quota-from-cgroup" json:"server-memory-quota-from-cgroup"`
 }
 
 // PlanCache is the PlanCache section of the config.
@@ -542,6 +572,8 @@ type Experimental struct {
 	AllowsExpressionIndex bool `toml:"allow-expression-index" json:"allow-expression-index"`
 	// Whether enable global kill.
 	EnableGlobalKill bool `toml:"enable-global-kill" json:"-"`
+	// Whether allocate the auto_increment values of the tables with AUTO_ID_CACHE 1 from the DDL owner.
+	EnableCentralAutoID bool `toml:"enable-central-autoid" json:"enable-central-autoid"`
 }
 
 var defTiKVCfg = tikvcfg.DefaultConfig()
@@ -553,6 +585,7 @@ var defaultConf = Config{
 	Store:                        "unistore",
 	Path:                         "/tmp/tidb",
 	RunDDL:                       true,
+	DDLGeneralWorkerCount:        DefDDLGeneralWorkerCount,
 	SplitTable:                   true,
 	Lease:                        "45s",
 	TokenLimit:                   1000,
@@ -578,6 +611,8 @@ var defaultConf = Config{
 	TxnLocalLatches:              defTiKVCfg.TxnLocalLatches,
 	LowerCaseTableNames:          2,
 	GracefulWaitBeforeShutdown:   0,
+	GracefulShutdownTimeout:      15,
+	GracefulShutdownRedirect:     false,
 	ServerVersion:                "",
 	Log: Log{
 		Level:               "info",
@@ -623,9 +658,13 @@ var defaultConf = Config{
 		MaxTxnTTL:             defTiKVCfg.MaxTxnTTL, // 1hour
 		MemProfileInterval:    "1m",
 		// TODO: set indexUsageSyncLease to 60s.
-		IndexUsageSyncLease: "0s",
-		GOGC:                100,
-		EnforceMPP:          false,
+		IndexUsageSyncLease:          "0s",
+		GOGC:                         100,
+		EnforceMPP:                   false,
+		RegionCacheWarmupTables:      0,
+		TxnSummaryCapacity:           100,
+		WriteConflictHistoryCapacity: 10,
+		ServerMemoryQuotaFromCgroup:  false,
 	},
 	ProxyProtocol: ProxyProtocol{
 		Networks:      "",
@@ -665,6 +704,7 @@ var defaultConf = Config{
 	Experimental: Experimental{
 		AllowsExpressionIndex: false,
 		EnableGlobalKill:      false,
+		EnableCentralAutoID:   false,
 	},
 	EnableCollectExecutionInfo: true,
 	EnableTelemetry:            true,
@@ -848,9 +888,23 @@ func (c *Config) Valid() error {
 	if c.IndexLimit < DefIndexLimit || c.IndexLimit > DefMaxOfIndexLimit {
 		return fmt.Errorf("index-limit should be [%d, %d]", DefIndexLimit, DefMaxOfIndexLimit)
 	}
+	if c.DDLGeneralWorkerCount < 1 || c.DDLGeneralWorkerCount > DefMaxOfDDLGeneralWorkerCount {
+		return fmt.Errorf("ddl-general-worker-count should be [%d, %d]", 1, DefMaxOfDDLGeneralWorkerCount)
+	}
 	if c.Log.File.MaxSize > MaxLogFileSize {
 		return fmt.Errorf("invalid max log file size=%v which is larger than max=%v", c.Log.File.MaxSize, MaxLogFileSize)
 	}
+	for _, item := range c.Log.RedactSensitiveColumns {
+		parts := strings.Split(item, ".")
+		if len(parts) < 2 || len(parts) > 3 {
+			return fmt.Errorf("invalid redact-sensitive-columns item %q, it should be \"db.table\" or \"db.table.column\"", item)
+		}
+		for _, part := range parts {
+			if part == "" {
+				return fmt.Errorf("invalid redact-sensitive-columns item %q, it should be \"db.table\" or \"db.table.column\"", item)
+			}
+		}
+	}
 	c.OOMAction = strings.ToLower(c.OOMAction)
 	if c.OOMAction != OOMActionLog && c.OOMAction != OOMActionCancel {
 		return fmt.Errorf("unsupported OOMAction %v, TiDB only supports [%v, %v]", c.OOMAction, OOMActionLog, OOMActionCancel)
@@ -511,6 +511,31 @@ func (s *testConfigSuite) TestIndexLimit(c *C) {
 	checkValid(DefMaxOfIndexLimit+1, false)
 }
 
+func (s *testConfigSuite) TestRedactSensitiveColumns(c *C) {
+	conf := NewConfig()
+	checkValid := func(items []string, shouldBeValid bool) {
+		conf.Log.RedactSensitiveColumns = items
+		c.Assert(conf.Valid() == nil, Equals, shouldBeValid)
+	}
+	checkValid(nil, true)
+	checkValid([]string{"test.t", "test.t1.a"}, true)
+	checkValid([]string{"t"}, false)
+	checkValid([]string{"test..a"}, false)
+	checkValid([]string{"test.t.a.b"}, false)
+}
+
+func (s *testConfigSuite) TestDDLGeneralWorkerCount(c *C) {
+	conf := NewConfig()
+	checkValid := func(cnt uint, shouldBeValid bool) {
+		conf.DDLGeneralWorkerCount = cnt
+		c.Assert(conf.Valid() == nil, Equals, shouldBeValid)
+	}
+	checkValid(DefDDLGeneralWorkerCount, true)
+	checkValid(0, false)
+	checkValid(DefMaxOfDDLGeneralWorkerCount, true)
+	checkValid(DefMaxOfDDLGeneralWorkerCount+1, false)
+}
+
 func (s *testConfigSuite) TestTableColumnCountLimit(c *C) {
 	conf := NewConfig()
 	checkValid := func(tableColumnLimit int, shouldBeValid bool) {
@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/config"
 	ddlutil "github.com/pingcap/tidb/ddl/util"
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
@@ -170,7 +171,7 @@ func checkAddColumn(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.Colu
 	return tblInfo, columnInfo, col, pos, offset, nil
 }
 
-func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+func (w *worker) onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
 	// Handle the rolling back job.
 	if job.IsRollingback() {
 		ver, err = onDropColumn(t, job)
@@ -236,6 +237,13 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 		// Update the job state when all affairs done.
 		job.SchemaState = model.StateWriteReorganization
 	case model.StateWriteReorganization:
+		if columnInfo.IsGenerated() && columnInfo.GeneratedStored {
+			var done bool
+			done, ver, err = w.doReorgWorkForAddStoredGeneratedColumn(d, t, job, tblInfo, columnInfo)
+			if !done {
+				return ver, errors.Trace(err)
+			}
+		}
 		// reorganization -> public
 		// Adjust table column offset.
 		adjustColumnInfoInAddColumn(tblInfo, offset)
@@ -255,6 +263,53 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 	return ver, errors.Trace(err)
 }
 
+// doReorgWorkForAddStoredGeneratedColumn backfills the values of the stored generated column being added into the
+// existing rows. The rows written after the column becomes writable have the values already, and are skipped.
+func (w *worker) doReorgWorkForAddStoredGeneratedColumn(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo,
+	columnInfo *model.ColumnInfo) (done bool, ver int64, err error) {
+	tbl, err := getTable(d.store, job.SchemaID, tblInfo)
+	if err != nil {
+		return false, ver, errors.Trace(err)
+	}
+	reorgInfo, err := getReorgInfo(d, t, job, tbl, BuildElements(columnInfo, nil))
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, ver, errors.Trace(err)
+	}
+
+	err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (addColumnErr error) {
+		defer util.Recover(metrics.LabelDDL, "onAddColumn",
+			func() {
+				addColumnErr = errCancelledDDLJob.GenWithStack("add table `%v` column `%v` panic", tblInfo.Name, columnInfo.Name)
+			}, false)
+		return w.updateColumnAndIndexes(tbl, nil, columnInfo, nil, reorgInfo)
+	})
+	if err != nil {
+		if errWaitReorgTimeout.Equal(err) {
+			// If timeout, we should return, check for the owner and re-wait job done.
+			return false, ver, nil
+		}
+		if kv.IsTxnRetryableError(err) {
+			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+			w.reorgCtx.cleanNotifyReorgCancel()
+			return false, ver, errors.Trace(err)
+		}
+		if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+			logutil.BgLogger().Warn("[ddl] run add column job failed, RemoveDDLReorgHandle failed, can't convert job to rollback",
+				zap.String("job", job.String()), zap.Error(err1))
+		}
+		logutil.BgLogger().Warn("[ddl] run add column job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+		ver, err = convertAddColumnJob2RollbackJob(t, job, tblInfo, columnInfo, err)
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return false, ver, errors.Trace(err)
+	}
+	// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+	w.reorgCtx.cleanNotifyReorgCancel()
+	return true, ver, nil
+}
+
 func checkAddColumns(t *meta.Meta, job *model.Job) (*model.TableInfo, []*model.ColumnInfo, []*model.ColumnInfo, []*ast.ColumnPosition, []int, []bool, error) {
 	schemaID := job.SchemaID
 	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
@@ -1230,8 +1285,10 @@ func (w *worker) updateColumnAndIndexes(t table.Table, oldCol, col *model.Column
 
 type updateColumnWorker struct {
 	*backfillWorker
+	// oldColInfo is nil when adding a stored generated column, whose values are evaluated by newColExpr.
 	oldColInfo    *model.ColumnInfo
 	newColInfo    *model.ColumnInfo
+	newColExpr    expression.Expression
 	metricCounter prometheus.Counter
 
 	// The following attributes are used to reduce memory allocation.
@@ -1245,6 +1302,17 @@ type updateColumnWorker struct {
 }
 
 func newUpdateColumnWorker(sessCtx sessionctx.Context, worker *worker, id int, t table.PhysicalTable, oldCol, newCol *model.ColumnInfo, decodeColMap map[int64]decoder.Column, sqlMode mysql.SQLMode) *updateColumnWorker {
+	if oldCol == nil {
+		// The stored generated column being added isn't decoded, otherwise its default value is filled into the
+		// rows which haven't been backfilled. It's evaluated on the other columns of every row instead.
+		colMap := make(map[int64]decoder.Column, len(decodeColMap))
+		for id, col := range decodeColMap {
+			if id != newCol.ID {
+				colMap[id] = col
+			}
+		}
+		decodeColMap = colMap
+	}
 	rowDecoder := decoder.NewRowDecoder(t, t.WritableCols(), decodeColMap)
 	return &updateColumnWorker{
 		backfillWorker: newBackfillWorker(sessCtx, worker, id, t),
@@ -1339,9 +1407,21 @@ func (w *updateColumnWorker) getRowRecord(handle kv.Handle, recordKey []byte, ra
 		oldWarn = oldWarn[:0]
 	}
 	w.sessCtx.GetSessionVars().StmtCtx.SetWarnings(oldWarn)
-	newColVal, err := table.CastValue(w.sessCtx, w.rowMap[w.oldColInfo.ID], w.newColInfo, false, false)
-	if err != nil {
-		return w.reformatErrors(err)
+	var newColVal types.Datum
+	if w.oldColInfo != nil {
+		newColVal, err = table.CastValue(w.sessCtx, w.rowMap[w.oldColInfo.ID], w.newColInfo, false, false)
+		if err != nil {
+			return w.reformatErrors(err)
+		}
+	} else {
+		// The new column is a stored generated column being added, which may depend on the virtual generated columns.
+		if _, err = w.rowDecoder.EvalRemainedExprColumnMap(w.sessCtx, timeutil.SystemLocation(), w.rowMap); err != nil {
+			return errors.Trace(err)
+		}
+		newColVal, err = w.evalNewGeneratedColumn()
+		if err != nil {
+			return errors.Trace(err)
+		}
 	}
 	if w.sessCtx.GetSessionVars().StmtCtx.GetWarnings() != nil && len(w.sessCtx.GetSessionVars().StmtCtx.GetWarnings()) != 0 {
 		warn := w.sessCtx.GetSessionVars().StmtCtx.GetWarnings()
@@ -1379,8 +1459,31 @@ func (w *updateColumnWorker) getRowRecord(handle kv.Handle, recordKey []byte, ra
 	return nil
 }
 
+// evalNewGeneratedColumn evaluates the value of the stored generated column being added on the decoded row.
+func (w *updateColumnWorker) evalNewGeneratedColumn() (types.Datum, error) {
+	if w.newColExpr == nil {
+		col := table.FindCol(w.table.WritableCols(), w.newColInfo.Name.L)
+		if col == nil || col.GeneratedExpr == nil {
+			return types.Datum{}, errors.Errorf("stored generated column %s not found", w.newColInfo.Name)
+		}
+		expr, err := expression.RewriteSimpleExprWithTableInfo(w.sessCtx, w.table.Meta(), col.GeneratedExpr)
+		if err != nil {
+			return types.Datum{}, errors.Trace(err)
+		}
+		w.newColExpr = expr
+	}
+	val, err := w.newColExpr.Eval(w.rowDecoder.CurrentRowWithDefaultVal())
+	if err != nil {
+		return types.Datum{}, errors.Trace(err)
+	}
+	return table.CastValue(w.sessCtx, val, w.newColInfo, false, false)
+}
+
 // reformatErrors casted error because `convertTo` function couldn't package column name and datum value for some errors.
 func (w *updateColumnWorker) reformatErrors(err error) error {
+	if w.oldColInfo == nil {
+		return err
+	}
 	// Since row count is not precious in concurrent reorganization, here we substitute row count with datum value.
 	if types.ErrTruncated.Equal(err) {
 		err = types.ErrTruncated.GenWithStack("Data truncated for column '%s', value is '%s'", w.oldColInfo.Name, w.rowMap[w.oldColInfo.ID])
@@ -0,0 +1,254 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// buildConstraintInfo builds the ConstraintInfo of the check constraint on the table, the name is generated if it's
+// not specified. The ID and the state are left to the caller.
+func buildConstraintInfo(ctx sessionctx.Context, tblInfo *model.TableInfo, constr *ast.Constraint) (*model.ConstraintInfo, error) {
+	name := constr.Name
+	if name == "" {
+		for i := 1; ; i++ {
+			name = fmt.Sprintf("%s_chk_%d", tblInfo.Name.O, i)
+			if tblInfo.FindConstraintInfoByName(name) == nil {
+				break
+			}
+		}
+	} else if tblInfo.FindConstraintInfoByName(name) != nil {
+		return nil, ErrCheckConstraintDupName.GenWithStackByArgs(name)
+	}
+
+	if err := checkIllegalFn4Generated(name, typeCheckConstraint, constr.Expr); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var dependedCols []model.CIStr
+	for _, colName := range findColumnNamesInExpr(constr.Expr) {
+		col := model.FindColumnInfo(tblInfo.Columns, colName.Name.L)
+		if col == nil || col.Hidden {
+			return nil, ErrBadField.GenWithStackByArgs(colName.Name.O, fmt.Sprintf("check constraint %s expression", name))
+		}
+		if constr.InColumn && col.Name.L != strings.ToLower(constr.InColumnName) {
+			return nil, ErrColumnCheckConstraintReferencesOtherColumn.GenWithStackByArgs(name)
+		}
+		if mysql.HasAutoIncrementFlag(col.Flag) {
+			return nil, ErrCheckConstraintRefersAutoIncrementColumn.GenWithStackByArgs(name)
+		}
+		found := false
+		for _, dependedCol := range dependedCols {
+			if dependedCol.L == col.Name.L {
+				found = true
+				break
+			}
+		}
+		if !found {
+			dependedCols = append(dependedCols, col.Name)
+		}
+	}
+
+	var sb strings.Builder
+	restoreFlags := format.RestoreStringSingleQuotes | format.RestoreKeyWordLowercase | format.RestoreNameBackQuotes |
+		format.RestoreSpacesAroundBinaryOperation
+	if err := constr.Expr.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Make sure the expression can be evaluated on the rows of the table.
+	if _, err := expression.ParseSimpleExprWithTableInfo(ctx, sb.String(), tblInfo); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return &model.ConstraintInfo{
+		Name:           model.NewCIStr(name),
+		Table:          tblInfo.Name,
+		ConstraintCols: dependedCols,
+		Enforced:       constr.Enforced,
+		InColumn:       constr.InColumn,
+		ExprString:     sb.String(),
+	}, nil
+}
+
+// checkColumnReferredByCheckConstraint returns an error if the column is referred by a check constraint, in which case
+// the column can't be dropped or renamed.
+func checkColumnReferredByCheckConstraint(tblInfo *model.TableInfo, colName model.CIStr) error {
+	for _, constraintInfo := range tblInfo.Constraints {
+		for _, col := range constraintInfo.ConstraintCols {
+			if col.L == colName.L {
+				return ErrDependentByCheckConstraint.GenWithStackByArgs(constraintInfo.Name.O, colName.O)
+			}
+		}
+	}
+	return nil
+}
+
+func removeConstraintInfo(tblInfo *model.TableInfo, constrName model.CIStr) {
+	constraints := tblInfo.Constraints[:0]
+	for _, constraintInfo := range tblInfo.Constraints {
+		if constraintInfo.Name.L != constrName.L {
+			constraints = append(constraints, constraintInfo)
+		}
+	}
+	tblInfo.Constraints = constraints
+}
+
+func (w *worker) onAddCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	schemaID := job.SchemaID
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	constraintInfo := &model.ConstraintInfo{}
+	err = job.DecodeArgs(constraintInfo)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	// The jobs of a table run one by one, so the constraint that isn't public is added by this job.
+	if existing := tblInfo.FindConstraintInfoByName(constraintInfo.Name.L); existing != nil {
+		if existing.State == model.StatePublic {
+			job.State = model.JobStateCancelled
+			return ver, ErrCheckConstraintDupName.GenWithStackByArgs(constraintInfo.Name.O)
+		}
+		constraintInfo = existing
+	} else {
+		tblInfo.MaxConstraintID++
+		constraintInfo.ID = tblInfo.MaxConstraintID
+		constraintInfo.State = model.StateNone
+		tblInfo.Constraints = append(tblInfo.Constraints, constraintInfo)
+	}
+
+	originalState := constraintInfo.State
+	switch constraintInfo.State {
+	case model.StateNone:
+		// none -> write only, the written rows are checked since then.
+		constraintInfo.State = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != constraintInfo.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateWriteOnly
+	case model.StateWriteOnly:
+		// write only -> public, after verifying the existing rows.
+		if constraintInfo.Enforced {
+			dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+			if err != nil {
+				return ver, errors.Trace(err)
+			}
+			err = w.verifyRemainRecordsForCheckConstraint(dbInfo, tblInfo, constraintInfo)
+			if err != nil {
+				if table.ErrCheckConstraintViolated.Equal(err) {
+					return convertAddCheckConstraintJob2RollbackJob(t, job, tblInfo, constraintInfo.Name, err)
+				}
+				return ver, errors.Trace(err)
+			}
+		}
+		constraintInfo.State = model.StatePublic
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != constraintInfo.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// Finish this job.
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("constraint", constraintInfo.State)
+	}
+	return ver, errors.Trace(err)
+}
+
+// verifyRemainRecordsForCheckConstraint returns ErrCheckConstraintViolated if any existing row violates the constraint.
+func (w *worker) verifyRemainRecordsForCheckConstraint(dbInfo *model.DBInfo, tblInfo *model.TableInfo, constraintInfo *model.ConstraintInfo) error {
+	ctx, err := w.sessPool.get()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer w.sessPool.put(ctx)
+
+	// The expression is a part of the SQL, so the '%' in it should be escaped.
+	sql := "select 1 from %n.%n where not (" + strings.ReplaceAll(constraintInfo.ExprString, "%", "%%") + ") limit 1"
+	exec := ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(context.Background(), sql, dbInfo.Name.L, tblInfo.Name.L)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rows, _, err := exec.ExecRestrictedStmt(context.Background(), stmt)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(rows) > 0 {
+		return table.ErrCheckConstraintViolated.GenWithStackByArgs(constraintInfo.Name.O)
+	}
+	return nil
+}
+
+// convertAddCheckConstraintJob2RollbackJob removes the adding constraint and finishes the job as rolled back.
+func convertAddCheckConstraintJob2RollbackJob(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, constrName model.CIStr, err error) (int64, error) {
+	removeConstraintInfo(tblInfo, constrName)
+	ver, err1 := updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err1 != nil {
+		return ver, errors.Trace(err1)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	return ver, errors.Trace(err)
+}
+
+func onDropCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	schemaID := job.SchemaID
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	var constrName model.CIStr
+	err = job.DecodeArgs(&constrName)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	constraintInfo := tblInfo.FindConstraintInfoByName(constrName.L)
+	if constraintInfo == nil {
+		job.State = model.JobStateCancelled
+		return ver, ErrCheckConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+
+	switch constraintInfo.State {
+	case model.StatePublic:
+		// Removing the constraint only makes the writes less restricted, so it's safe to be done in one step.
+		// public -> none
+		removeConstraintInfo(tblInfo, constrName)
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// Finish this job.
+		job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+		return ver, nil
+	default:
+		return ver, ErrInvalidDDLState.GenWithStackByArgs("constraint", constraintInfo.State)
+	}
+}
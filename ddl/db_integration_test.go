@@ -1442,6 +1442,46 @@ func (s *testIntegrationSuite2) TestAddIndexAfterAddColumn(c *C) {
 	tk.MustGetErrCode(sql, errno.ErrTooManyKeyParts)
 }
 
+func (s *testIntegrationSuite2) TestMultiSchemaChange(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t_multi")
+	tk.MustExec("create table t_multi (a int, b int)")
+	tk.MustExec("insert into t_multi values (1, 1), (2, 2)")
+
+	// The columns and the indexes added before the failed index are kept.
+	tk.MustGetErrCode("alter table t_multi add column c int default 5 first, add index idx_b(b), add unique index idx_c(c)", errno.ErrDupEntry)
+	tk.MustQuery("select * from t_multi order by a").Check(testkit.Rows("5 1 1", "5 2 2"))
+	tk.MustQuery("select distinct index_name from information_schema.statistics where table_name = 't_multi'").Check(testkit.Rows("idx_b"))
+	tk.MustExec("alter table t_multi drop index idx_b")
+	tk.MustExec("alter table t_multi drop column c")
+
+	tk.MustExec("alter table t_multi add column c int default 5 first, add index idx_c(c, b), add unique index uk_b(b), add column d int after a")
+	tk.MustQuery("select * from t_multi order by a").Check(testkit.Rows("5 1 <nil> 1", "5 2 <nil> 2"))
+	tk.MustQuery("select index_name, column_name from information_schema.statistics where table_name = 't_multi' order by index_name, seq_in_index").Check(
+		testkit.Rows("idx_c c", "idx_c b", "uk_b b"))
+	tk.MustQuery("select a from t_multi use index (idx_c) where c = 5 and b = 2").Check(testkit.Rows("2"))
+	tk.MustGetErrCode("insert into t_multi values (6, 7, 8, 2)", errno.ErrDupEntry)
+	tk.MustExec("admin check table t_multi")
+
+	// The anonymous indexes are named after the first column.
+	tk.MustExec("alter table t_multi add column e int, add index(e), add index(e, a)")
+	tk.MustQuery("select index_name from information_schema.statistics where table_name = 't_multi' and column_name = 'e' order by index_name").Check(
+		testkit.Rows("e", "e_2"))
+	tk.MustExec("admin check table t_multi")
+	// The columns and indexes are added by the jobs the binlog, TiCDC and BR know.
+	tk.MustQuery("select job_type from information_schema.ddl_jobs where table_name = 't_multi' order by job_id desc limit 3").Check(
+		testkit.Rows("add index", "add index", "add multi-columns"))
+
+	tk.MustGetErrCode("alter table t_multi add column f int, add index idx_f(f), add index idx_f(a)", errno.ErrDupKeyName)
+	tk.MustGetErrCode("alter table t_multi add column f int, add index idx_b(g)", errno.ErrKeyColumnDoesNotExits)
+	tk.MustGetErrCode("alter table t_multi add column f int, drop column e", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table t_multi add index idx_f(a), add primary key(a)", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("alter table t_multi add index if not exists idx_c(a), add index idx_f(a)")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1061 index already exist idx_c"))
+	tk.MustExec("admin check table t_multi")
+}
+
 func (s *testIntegrationSuite3) TestResolveCharset(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -2483,6 +2523,37 @@ func (s *testIntegrationSuite3) TestCreateTableWithAutoIdCache(c *C) {
 	c.Assert(err.Error(), Equals, "table option auto_id_cache overflows int64")
 }
 
+// TestCreateTableWithAutoIdCacheOne tests the auto_increment values of the tables with AUTO_ID_CACHE 1 are strictly
+// increasing across the sessions, they're allocated by the centralized allocator service.
+func (s *testIntegrationSuite3) TestCreateTableWithAutoIdCacheOne(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Experimental.EnableCentralAutoID = true
+	})
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int auto_increment key, b int) auto_id_cache 1")
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.MustExec("use test")
+	for i := 0; i < 3; i++ {
+		tk.MustExec("insert into t(b) values(0)")
+		tk1.MustExec("insert into t(b) values(1)")
+	}
+	tk.MustQuery("select a, b from t order by a").Check(testkit.Rows("1 0", "2 1", "3 0", "4 1", "5 0", "6 1"))
+	tk.MustQuery("show table t next_row_id").Check(testkit.Rows("test t a 7 AUTO_INCREMENT"))
+
+	tk.MustExec("insert into t values(100, 2)")
+	tk1.MustExec("insert into t(b) values(3)")
+	tk.MustQuery("select a from t where b = 3").Check(testkit.Rows("101"))
+
+	// The lease is dropped after the DDL, the values are still increasing.
+	tk.MustExec("alter table t auto_increment = 200")
+	tk1.MustExec("insert into t(b) values(4)")
+	tk.MustQuery("select a from t where b = 4").Check(testkit.Rows("4001"))
+	tk.MustExec("drop table t")
+}
+
 func (s *testIntegrationSuite4) TestAlterIndexVisibility(c *C) {
 	config.UpdateGlobal(func(conf *config.Config) {
 		conf.Experimental.AllowsExpressionIndex = true
@@ -1607,6 +1607,139 @@ func (s *testIntegrationSuite5) TestMultiPartitionDropAndTruncate(c *C) {
 	result.Check(testkit.Rows(`2010`))
 }
 
+func (s *testIntegrationSuite5) TestAlterTableReorganizePartition(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t_reorg")
+	tk.MustExec(`create table t_reorg (a int, b varchar(20), unique key idx_a(a), key idx_b(b))
+	partition by range(a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20),
+		partition p2 values less than (30)
+	);`)
+	tk.MustExec(`insert into t_reorg values (1, "a"), (5, "b"), (11, "c"), (15, "d"), (25, "e")`)
+
+	// It's disabled by default.
+	tk.MustExec(`alter table t_reorg reorganize partition p0 into (partition p0a values less than (10))`)
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 8200 Reorganize Partition is disabled, please set 'tidb_enable_reorganize_partition' if you need to enable it"))
+	tk.MustQuery("select count(*) from information_schema.partitions where table_name = 't_reorg'").Check(testkit.Rows("3"))
+	tk.MustExec("set @@tidb_enable_reorganize_partition=1")
+
+	// Split a partition.
+	tk.MustExec(`alter table t_reorg reorganize partition p0 into (
+		partition p0a values less than (5),
+		partition p0b values less than (10)
+	);`)
+	tk.MustQuery("select a from t_reorg partition (p0a)").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t_reorg partition (p0b)").Check(testkit.Rows("5"))
+	tk.MustQuery("select b from t_reorg use index (idx_a) where a = 5").Check(testkit.Rows("b"))
+	tk.MustExec("admin check table t_reorg")
+
+	// Merge the partitions.
+	tk.MustExec(`alter table t_reorg reorganize partition p0b, p1 into (
+		partition p1 values less than (20)
+	);`)
+	tk.MustQuery("select a from t_reorg partition (p1) order by a").Check(testkit.Rows("5", "11", "15"))
+	tk.MustQuery("select a from t_reorg use index (idx_b) where b = 'c'").Check(testkit.Rows("11"))
+	tk.MustGetErrCode(`insert into t_reorg values (15, "f")`, tmysql.ErrDupEntry)
+	tk.MustExec("admin check table t_reorg")
+
+	// Extend the last partition.
+	tk.MustExec(`alter table t_reorg reorganize partition p2 into (
+		partition p2 values less than (30),
+		partition p3 values less than (maxvalue)
+	);`)
+	tk.MustExec(`insert into t_reorg values (100, "f")`)
+	tk.MustQuery("select a from t_reorg partition (p3)").Check(testkit.Rows("100"))
+	tk.MustQuery("select count(*) from t_reorg").Check(testkit.Rows("6"))
+	tk.MustExec("admin check table t_reorg")
+
+	tk.MustGetErrCode(`alter table t_reorg reorganize partition p9 into (
+		partition p9 values less than (10));`, tmysql.ErrDropPartitionNonExistent)
+	tk.MustGetErrCode(`alter table t_reorg reorganize partition p0a, p2 into (
+		partition p2 values less than (30));`, tmysql.ErrConsecutiveReorgPartitions)
+	tk.MustGetErrCode(`alter table t_reorg reorganize partition p1 into (
+		partition p1 values less than (15));`, tmysql.ErrReorgOutsideRange)
+	tk.MustGetErrCode(`alter table t_reorg reorganize partition p1 into (
+		partition p1 values less than (25));`, tmysql.ErrReorgOutsideRange)
+	tk.MustGetErrCode(`alter table t_reorg reorganize partition p1 into (
+		partition p1a values less than (18),
+		partition p1b values less than (16));`, tmysql.ErrRangeNotIncreasing)
+	tk.MustGetErrCode(`alter table t_reorg reorganize partition p1 into (
+		partition p0a values less than (20));`, tmysql.ErrSameNamePartition)
+	tk.MustGetErrCode("alter table t_reorg reorganize partition", tmysql.ErrReorgNoParam)
+}
+
+func (s *testSerialDBSuite1) TestReorganizePartitionWithConcurrentDML(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t_reorg")
+	tk.MustExec(`create table t_reorg (a int, b int, unique key idx_a(a), key idx_b(b))
+	partition by range(a) (
+		partition p0 values less than (100),
+		partition p1 values less than (200)
+	);`)
+	for i := 0; i < 100; i += 10 {
+		tk.MustExec(fmt.Sprintf("insert into t_reorg values (%d, %d)", i, i))
+	}
+	tk.MustExec("set @@tidb_enable_reorganize_partition=1")
+
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.MustExec("use test")
+	var checkErr error
+	executed := make(map[model.SchemaState]bool)
+	hook := &ddl.TestDDLCallback{}
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.Type != ddl.ActionReorganizePartition || checkErr != nil || executed[job.SchemaState] {
+			return
+		}
+		executed[job.SchemaState] = true
+		// Each state runs some DML once, the rows written by the reorganization are checked at last.
+		switch job.SchemaState {
+		case model.StateDeleteOnly:
+			_, checkErr = tk1.Exec("insert into t_reorg values (1, 1)")
+		case model.StateWriteOnly:
+			_, checkErr = tk1.Exec("insert into t_reorg values (51, 51)")
+		case model.StateWriteReorganization:
+			if _, checkErr = tk1.Exec("update t_reorg set b = b + 1 where a in (10, 51)"); checkErr == nil {
+				_, checkErr = tk1.Exec("delete from t_reorg where a = 20")
+			}
+		case model.StateDeleteReorganization:
+			_, checkErr = tk1.Exec("insert ignore into t_reorg values (61, 61), (10, 10)")
+		}
+	}
+	originHook := s.dom.DDL().GetHook()
+	defer s.dom.DDL().(ddl.DDLForTest).SetHook(originHook)
+	s.dom.DDL().(ddl.DDLForTest).SetHook(hook)
+
+	tk.MustExec(`alter table t_reorg reorganize partition p0 into (
+		partition p0a values less than (50),
+		partition p0b values less than (100)
+	);`)
+	c.Assert(checkErr, IsNil)
+	tk.MustQuery("select a, b from t_reorg partition (p0a) order by a").Check(testkit.Rows("0 0", "1 1", "10 11", "30 30", "40 40"))
+	tk.MustQuery("select a, b from t_reorg partition (p0b) order by a").Check(testkit.Rows("50 50", "51 52", "60 60", "61 61", "70 70", "80 80", "90 90"))
+	tk.MustQuery("select a from t_reorg use index (idx_b) where b = 52").Check(testkit.Rows("51"))
+	tk.MustExec("admin check table t_reorg")
+
+	// Roll back the reorganization before the partitions are swapped.
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.Type == ddl.ActionReorganizePartition && job.SchemaState == model.StateWriteOnly {
+			job.State = model.JobStateRollingback
+			job.Error = mockTerrorMap[model.StateWriteOnly.String()]
+		}
+	}
+	_, err := tk.Exec(`alter table t_reorg reorganize partition p0a, p0b into (
+		partition p0 values less than (100)
+	);`)
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[ddl:1]MockRollingBackInCallBack-write only")
+	tk.MustQuery("select partition_name from information_schema.partitions where table_name = 't_reorg' and table_schema = 'test' order by partition_ordinal_position").Check(
+		testkit.Rows("p0a", "p0b", "p1"))
+	tk.MustQuery("select count(*) from t_reorg").Check(testkit.Rows("12"))
+	tk.MustExec("admin check table t_reorg")
+}
+
 func (s *testSerialDBSuite1) TestDropPartitionWithGlobalIndex(c *C) {
 	config.UpdateGlobal(func(conf *config.Config) {
 		conf.EnableGlobalIndex = true
@@ -3048,7 +3181,8 @@ func (s *testIntegrationSuite3) TestPartitionErrorCode(c *C) {
 	_, err = tk.Exec("alter table t_part coalesce partition 4;")
 	c.Assert(ddl.ErrCoalesceOnlyOnHashPartition.Equal(err), IsTrue)
 
-	tk.MustGetErrCode(`alter table t_part reorganize partition p0, p1 into (
+	tk.MustExec("set @@tidb_enable_reorganize_partition=1")
+	tk.MustGetErrCode(`alter table clients reorganize partition p0, p1 into (
 			partition p0 values less than (1980));`, tmysql.ErrUnsupportedDDLOperation)
 
 	tk.MustGetErrCode("alter table t_part check partition p0, p1;", tmysql.ErrUnsupportedDDLOperation)
@@ -3630,8 +3630,8 @@ func (s *testDBSuite3) TestGeneratedColumnDDL(c *C) {
 		{`create table test_gv_ddl_bad (a int, b int, c int as (a+b), primary key(c))`, errno.ErrUnsupportedOnGeneratedColumn},
 		{`create table test_gv_ddl_bad (a int, b int, c int as (a+b), primary key(a, c))`, errno.ErrUnsupportedOnGeneratedColumn},
 
-		// Add stored generated column through alter table.
-		{`alter table test_gv_ddl add column d int as (b+2) stored`, errno.ErrUnsupportedOnGeneratedColumn},
+		// Add stored generated column along with other columns through alter table.
+		{`alter table test_gv_ddl add column (d int as (b+2) stored, e int)`, errno.ErrUnsupportedOnGeneratedColumn},
 		{`alter table test_gv_ddl modify column b int as (a + 8) stored`, errno.ErrUnsupportedOnGeneratedColumn},
 
 		// Add generated column with incorrect parameter count.
@@ -3674,6 +3674,66 @@ func (s *testDBSuite3) TestGeneratedColumnDDL(c *C) {
 	tk.MustQuery("select * from t").Check(testkit.Rows("a\\b\\c\\"))
 }
 
+func (s *testDBSuite3) TestAddStoredGeneratedColumn(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test_db")
+	tk.MustExec("drop table if exists t, tp")
+	defer tk.MustExec("drop table if exists t, tp")
+	tk.MustExec("create table t (a int primary key, b int, c int as (b * 2))")
+	for i := 0; i < 10; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t (a, b) values (%d, %d)", i, i))
+	}
+
+	tk2 := testkit.NewTestKit(c, s.store)
+	tk2.MustExec("use test_db")
+	originHook := s.dom.DDL().GetHook()
+	defer s.dom.DDL().(ddl.DDLForTest).SetHook(originHook)
+	hook := &ddl.TestDDLCallback{}
+	var checkErr error
+	states := make(map[model.SchemaState]struct{})
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if checkErr != nil || job.Type != model.ActionAddColumn {
+			return
+		}
+		if _, ok := states[job.SchemaState]; ok {
+			return
+		}
+		states[job.SchemaState] = struct{}{}
+		// The rows written by the statements during the schema change get the values of the new column.
+		switch job.SchemaState {
+		case model.StateWriteOnly:
+			_, checkErr = tk2.Exec("insert into t (a, b) values (100, 100)")
+			if checkErr == nil {
+				_, checkErr = tk2.Exec("update t set b = 11 where a = 1")
+			}
+		case model.StateWriteReorganization:
+			_, checkErr = tk2.Exec("insert into t (a, b) values (101, 101)")
+			if checkErr == nil {
+				_, checkErr = tk2.Exec("update t set b = 12 where a = 2")
+			}
+		}
+	}
+	s.dom.DDL().(ddl.DDLForTest).SetHook(hook)
+	tk.MustExec("alter table t add column d int as (b + c) stored")
+	c.Assert(checkErr, IsNil)
+	s.dom.DDL().(ddl.DDLForTest).SetHook(originHook)
+
+	tk.MustQuery("select count(*) from t where d = b * 3").Check(testkit.Rows("12"))
+	tk.MustQuery("select a, b, d from t where a in (1, 2, 100, 101)").Check(testkit.Rows("1 11 33", "2 12 36", "100 100 300", "101 101 303"))
+	tk.MustExec("admin check table t")
+	tk.MustExec("insert into t (a, b) values (200, 1)")
+	tk.MustQuery("select d from t where a = 200").Check(testkit.Rows("3"))
+
+	// The job is rolled back if the values of some rows fail to be evaluated.
+	tk.MustExec("insert into t (a, b) values (300, 100)")
+	tk.MustGetErrCode("alter table t add column e tinyint as (b + 100) stored", errno.ErrDataOutOfRange)
+	tk.MustQuery("select count(*) from information_schema.columns where table_schema = 'test_db' and table_name = 't' and column_name = 'e'").Check(testkit.Rows("0"))
+	tk.MustExec("admin check table t")
+
+	tk.MustExec("create table tp (a int, b int) partition by hash(a) partitions 4")
+	tk.MustGetErrCode("alter table tp add column c int as (a + b) stored", errno.ErrUnsupportedOnGeneratedColumn)
+}
+
 func (s *testDBSuite4) TestComment(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
@@ -6134,6 +6194,66 @@ func (s *testDBSuite7) TestCreateTableIngoreCheckConstraint(c *C) {
 		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
 }
 
+func (s *testDBSuite7) TestCheckConstraintEnforcement(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use " + s.schemaName)
+	tk.MustExec("drop table if exists t_check, t_check_add")
+	defer tk.MustExec("drop table if exists t_check, t_check_add")
+	tk.MustExec("set @@global.tidb_check_constraint_enforcement = 'ON'")
+	defer tk.MustExec("set @@global.tidb_check_constraint_enforcement = default")
+	tk.MustGetErrCode("set @@session.tidb_check_constraint_enforcement = 'OFF'", errno.ErrGlobalVariable)
+
+	tk.MustExec("create table t_check (a int check (a > 0), b int, constraint b_gt_a check (b > a), c int check (c < 10) not enforced)")
+	tk.MustQuery("show create table t_check").Check(testutil.RowsWithSep("|", ""+
+		"t_check CREATE TABLE `t_check` (\n"+
+		"  `a` int(11) DEFAULT NULL,\n"+
+		"  `b` int(11) DEFAULT NULL,\n"+
+		"  `c` int(11) DEFAULT NULL,\n"+
+		"  CONSTRAINT `b_gt_a` CHECK ((`b` > `a`)),\n"+
+		"  CONSTRAINT `t_check_chk_1` CHECK ((`a` > 0)),\n"+
+		"  CONSTRAINT `t_check_chk_2` CHECK ((`c` < 10)) /*!80016 NOT ENFORCED */\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+
+	// Test the DML.
+	tk.MustExec("insert into t_check values (1, 2, 100), (null, null, null)")
+	tk.MustGetErrCode("insert into t_check values (0, 2, 1)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("insert into t_check values (2, 1, 1)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("update t_check set b = 0 where a = 1", errno.ErrCheckConstraintViolated)
+	tk.MustExec("insert ignore into t_check values (0, 2, 1), (3, 4, 1)")
+	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|3819|Check constraint 't_check_chk_1' is violated."))
+	tk.MustExec("update ignore t_check set b = 0")
+	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|",
+		"Warning|3819|Check constraint 'b_gt_a' is violated.", "Warning|3819|Check constraint 'b_gt_a' is violated."))
+	tk.MustQuery("select * from t_check order by a").Check(testkit.Rows("<nil> 0 <nil>", "1 2 100", "3 4 1"))
+	tk.MustExec("set @@global.tidb_check_constraint_enforcement = 'WARN'")
+	tk.MustExec("insert into t_check values (-1, 0, 1)")
+	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|3819|Check constraint 't_check_chk_1' is violated."))
+	tk.MustExec("set @@global.tidb_check_constraint_enforcement = 'OFF'")
+	tk.MustExec("insert into t_check values (-2, 0, 1)")
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	tk.MustExec("delete from t_check where a < 0")
+	tk.MustExec("set @@global.tidb_check_constraint_enforcement = 'ON'")
+
+	// Test the DDL.
+	tk.MustGetErrCode("create table t_check_add (a int check (b > 0), b int)", errno.ErrColumnCheckConstraintReferencesOtherColumn)
+	tk.MustGetErrCode("create table t_check_add (a int auto_increment primary key check (a > 0))", errno.ErrCheckConstraintRefersAutoIncrementColumn)
+	tk.MustGetErrCode("create table t_check_add (a int check (a > rand()))", errno.ErrCheckConstraintFunctionIsNotAllowed)
+	tk.MustGetErrCode("create table t_check_add (a int check (a > @x))", errno.ErrCheckConstraintVariables)
+	tk.MustGetErrCode("alter table t_check drop column b", errno.ErrDependentByCheckConstraint)
+	tk.MustGetErrCode("alter table t_check rename column a to d", errno.ErrDependentByCheckConstraint)
+	tk.MustGetErrCode("alter table t_check change column a d int", errno.ErrDependentByCheckConstraint)
+	tk.MustGetErrCode("alter table t_check add constraint c_lt_10 check (c < 10)", errno.ErrCheckConstraintViolated)
+	tk.MustExec("insert into t_check values (5, 6, 200)")
+	tk.MustExec("alter table t_check add constraint c_lt_1000 check (c < 1000)")
+	tk.MustGetErrCode("insert into t_check values (5, 6, 2000)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("alter table t_check add constraint c_lt_1000 check (c < 10000)", errno.ErrCheckConstraintDupName)
+	tk.MustExec("alter table t_check drop check c_lt_1000")
+	tk.MustExec("alter table t_check drop check b_gt_a")
+	tk.MustGetErrCode("alter table t_check drop check b_gt_a", errno.ErrCheckConstraintNotFound)
+	tk.MustExec("insert into t_check values (5, 4, 2000)")
+	tk.MustExec("alter table t_check drop column b")
+}
+
 func (s *testDBSuite6) TestAlterOrderBy(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
@@ -66,8 +66,23 @@ const (
 	// PartitionCountLimit is limit of the number of partitions in a table.
 	// Reference linking https://dev.mysql.com/doc/refman/5.7/en/partitioning-limitations.html.
 	PartitionCountLimit = 8192
+
+	// ActionReorganizePartition is the action type of the ALTER TABLE ... REORGANIZE PARTITION job.
+	// It isn't defined by the parser yet, so it is defined here with a value the parser doesn't use. The action
+	// types must be less than 64, the schema checker and the schema amender map them to the bits of an uint64.
+	// TODO: Move it to the parser once it's defined there.
+	ActionReorganizePartition model.ActionType = 62
 )
 
+// JobTypeString returns the name of the DDL action type, including the types which aren't defined by the parser.
+func JobTypeString(tp model.ActionType) string {
+	switch tp {
+	case ActionReorganizePartition:
+		return "reorganize partition"
+	}
+	return tp.String()
+}
+
 // OnExist specifies what to do when a new object has a name collision.
 type OnExist uint8
 
@@ -190,6 +205,9 @@ type ddl struct {
 	workers     map[workerType]*worker
 	sessPool    *sessionPool
 	delRangeMgr delRangeManager
+	// extraGeneralWorkers are the general workers except workers[generalWorker].
+	// They handle the general DDL jobs that don't conflict with each other concurrently.
+	extraGeneralWorkers []*worker
 }
 
 // ddlCtx is the context when we use worker to handle DDL jobs.
@@ -207,6 +225,14 @@ type ddlCtx struct {
 	tableLockCkr util.DeadTableLockChecker
 	etcdCli      *clientv3.Client
 
+	// runningJobs records the general DDL jobs claimed by the general workers.
+	runningJobs struct {
+		// Mutex is also used to serialize the transactions of the general workers.
+		sync.Mutex
+		// ids maps the job ID to the ID of the worker which handles the job.
+		ids map[int64]int32
+	}
+
 	// hook may be modified.
 	mu struct {
 		sync.RWMutex
@@ -303,6 +329,7 @@ func newDDL(ctx context.Context, options ...Option) *ddl {
 		tableLockCkr: deadLockCkr,
 		etcdCli:      opt.EtcdCli,
 	}
+	ddlCtx.runningJobs.ids = make(map[int64]int32)
 	ddlCtx.mu.hook = opt.Hook
 	ddlCtx.mu.interceptor = &BaseInterceptor{}
 	d := &ddl{
@@ -358,7 +385,13 @@ func (d *ddl) Start(ctxPool *pools.ResourcePool) error {
 		d.delRangeMgr = d.newDeleteRangeManager(ctxPool == nil)
 		d.workers[generalWorker] = newWorker(d.ctx, generalWorker, d.sessPool, d.delRangeMgr)
 		d.workers[addIdxWorker] = newWorker(d.ctx, addIdxWorker, d.sessPool, d.delRangeMgr)
-		for _, worker := range d.workers {
+		workers := []*worker{d.workers[generalWorker], d.workers[addIdxWorker]}
+		for i := uint(1); i < config.GetGlobalConfig().DDLGeneralWorkerCount; i++ {
+			w := newWorker(d.ctx, generalWorker, d.sessPool, d.delRangeMgr)
+			d.extraGeneralWorkers = append(d.extraGeneralWorkers, w)
+			workers = append(workers, w)
+		}
+		for _, worker := range workers {
 			worker.wg.Add(1)
 			w := worker
 			go w.start(d.ddlCtx)
@@ -398,6 +431,9 @@ func (d *ddl) close() {
 	for _, worker := range d.workers {
 		worker.close()
 	}
+	for _, worker := range d.extraGeneralWorkers {
+		worker.close()
+	}
 	// d.delRangeMgr using sessions from d.sessPool.
 	// Put it before d.sessPool.close to reduce the time spent by d.sessPool.close.
 	if d.delRangeMgr != nil {
@@ -512,6 +548,11 @@ func (d *ddl) asyncNotifyWorker(job *model.Job) {
 	}
 	if d.ownerManager.IsOwner() {
 		asyncNotify(worker.ddlJobCh)
+		if worker.tp == generalWorker {
+			for _, w := range d.extraGeneralWorkers {
+				asyncNotify(w.ddlJobCh)
+			}
+		}
 	} else {
 		d.asyncNotifyByEtcd(worker.addingDDLJobKey, job)
 	}
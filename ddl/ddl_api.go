@@ -679,7 +679,18 @@ func columnDefToCol(ctx sessionctx.Context, offset int, colDef *ast.ColumnDef, o
 			case ast.ColumnOptionFulltext:
 				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrTableCantHandleFt.GenWithStackByArgs())
 			case ast.ColumnOptionCheck:
-				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("CONSTRAINT CHECK"))
+				if variable.GetCheckConstraintLevel() == variable.CheckConstraintLevelOff {
+					ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("CONSTRAINT CHECK"))
+					break
+				}
+				constraints = append(constraints, &ast.Constraint{
+					Tp:           ast.ConstraintCheck,
+					Name:         v.ConstraintName,
+					Expr:         v.Expr,
+					Enforced:     v.Enforced,
+					InColumn:     true,
+					InColumnName: colDef.Name.Name.O,
+				})
 			}
 		}
 	}
@@ -1436,7 +1447,18 @@ func buildTableInfo(
 			continue
 		}
 		if constr.Tp == ast.ConstraintCheck {
-			ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("CONSTRAINT CHECK"))
+			if variable.GetCheckConstraintLevel() == variable.CheckConstraintLevelOff {
+				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("CONSTRAINT CHECK"))
+				continue
+			}
+			constraintInfo, err := buildConstraintInfo(ctx, tbInfo, constr)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			tbInfo.MaxConstraintID++
+			constraintInfo.ID = tbInfo.MaxConstraintID
+			constraintInfo.State = model.StatePublic
+			tbInfo.Constraints = append(tbInfo.Constraints, constraintInfo)
 			continue
 		}
 		// build index info.
@@ -2403,21 +2425,17 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 		if !ctx.GetSessionVars().EnableChangeMultiSchema {
 			return errRunMultiSchemaChanges
 		}
-		if isSameTypeMultiSpecs(validSpecs) {
-			switch validSpecs[0].Tp {
-			case ast.AlterTableAddColumns:
-				err = d.AddColumns(ctx, ident, validSpecs)
-			case ast.AlterTableDropColumn:
-				err = d.DropColumns(ctx, ident, validSpecs)
-			default:
-				return errRunMultiSchemaChanges
-			}
-			if err != nil {
-				return errors.Trace(err)
-			}
-			return nil
+		switch {
+		case isSameTypeMultiSpecs(validSpecs) && validSpecs[0].Tp == ast.AlterTableAddColumns:
+			err = d.AddColumns(ctx, ident, validSpecs)
+		case isSameTypeMultiSpecs(validSpecs) && validSpecs[0].Tp == ast.AlterTableDropColumn:
+			err = d.DropColumns(ctx, ident, validSpecs)
+		case isAddColumnsAndIndexesSpecs(validSpecs):
+			err = d.MultiSchemaChange(ctx, ident, validSpecs)
+		default:
+			return errRunMultiSchemaChanges
 		}
-		return errRunMultiSchemaChanges
+		return errors.Trace(err)
 	}
 
 	for _, spec := range validSpecs {
@@ -2434,7 +2452,7 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 		case ast.AlterTableCoalescePartitions:
 			err = d.CoalescePartitions(ctx, ident, spec)
 		case ast.AlterTableReorganizePartition:
-			err = errors.Trace(errUnsupportedReorganizePartition)
+			err = d.ReorganizePartitions(ctx, ident, spec)
 		case ast.AlterTableCheckPartitions:
 			err = errors.Trace(errUnsupportedCheckPartition)
 		case ast.AlterTableRebuildPartition:
@@ -2495,7 +2513,11 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 			case ast.ConstraintFulltext:
 				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrTableCantHandleFt)
 			case ast.ConstraintCheck:
-				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ADD CONSTRAINT CHECK"))
+				if variable.GetCheckConstraintLevel() == variable.CheckConstraintLevelOff {
+					ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ADD CONSTRAINT CHECK"))
+					break
+				}
+				err = d.CreateCheckConstraint(ctx, ident, constr)
 			default:
 				// Nothing to do now.
 			}
@@ -2575,7 +2597,11 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 		case ast.AlterTableAlterCheck:
 			ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ALTER CHECK"))
 		case ast.AlterTableDropCheck:
-			ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("DROP CHECK"))
+			if variable.GetCheckConstraintLevel() == variable.CheckConstraintLevelOff {
+				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("DROP CHECK"))
+				break
+			}
+			err = d.DropCheckConstraint(ctx, ident, model.NewCIStr(spec.Constraint.Name))
 		case ast.AlterTableWithValidation:
 			ctx.GetSessionVars().StmtCtx.AppendWarning(errUnsupportedAlterTableWithValidation)
 		case ast.AlterTableWithoutValidation:
@@ -2747,8 +2773,9 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 				return nil, errors.Trace(err)
 			}
 
-			if option.Stored {
-				return nil, ErrUnsupportedOnGeneratedColumn.GenWithStackByArgs("Adding generated stored column through ALTER TABLE")
+			// The stored values are backfilled in the reorganization, which doesn't support partitioned tables yet.
+			if option.Stored && t.Meta().GetPartitionInfo() != nil {
+				return nil, ErrUnsupportedOnGeneratedColumn.GenWithStackByArgs("Adding generated stored column to partitioned table through ALTER TABLE")
 			}
 
 			_, dependColNames := findDependedColumnNames(specNewColumn)
@@ -2793,7 +2820,7 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 	}
 	// Ignore table constraints now, they will be checked later.
 	// We use length(t.Cols()) as the default offset firstly, we will change the column's offset later.
-	col, _, err = buildColumnAndConstraint(
+	col, cts, err := buildColumnAndConstraint(
 		ctx,
 		len(t.Cols()),
 		specNewColumn,
@@ -2804,6 +2831,12 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	for _, constr := range cts {
+		if constr.Tp == ast.ConstraintCheck {
+			ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ADD COLUMN with CONSTRAINT CHECK"))
+			break
+		}
+	}
 
 	originDefVal, err := generateOriginDefaultValue(col.ToInfo())
 	if err != nil {
@@ -2839,7 +2872,12 @@ func (d *ddl) AddColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTab
 		SchemaName: schema.Name.L,
 		Type:       model.ActionAddColumn,
 		BinlogInfo: &model.HistoryInfo{},
-		Args:       []interface{}{col, spec.Position, 0},
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+		Args: []interface{}{col, spec.Position, 0},
 	}
 
 	err = d.doDDLJob(ctx, job)
@@ -2858,7 +2896,39 @@ func (d *ddl) AddColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alte
 	if err != nil {
 		return errors.Trace(err)
 	}
+	columns, positions, offsets, ifNotExists, err := checkAndCreateNewColumns(ctx, ti, schema, t, specs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	return d.doAddColumnsJob(ctx, schema, t.Meta(), columns, positions, offsets, ifNotExists)
+}
 
+func (d *ddl) doAddColumnsJob(ctx sessionctx.Context, schema *model.DBInfo, tblInfo *model.TableInfo, columns []*table.Column,
+	positions []*ast.ColumnPosition, offsets []int, ifNotExists []bool) error {
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAddColumns,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{columns, positions, offsets, ifNotExists},
+	}
+
+	err := d.doDDLJob(ctx, job)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// checkAndCreateNewColumns checks and creates the columns added by the specs, the existing columns are skipped if
+// the specs are `IF NOT EXISTS`.
+func checkAndCreateNewColumns(ctx sessionctx.Context, ti ast.Ident, schema *model.DBInfo, t table.Table, specs []*ast.AlterTableSpec) (
+	[]*table.Column, []*ast.ColumnPosition, []int, []bool, error) {
 	// Check all the columns at once.
 	addingColumnNames := make(map[string]bool)
 	dupColumnNames := make(map[string]bool)
@@ -2869,7 +2939,7 @@ func (d *ddl) AddColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alte
 				continue
 			}
 			if !spec.IfNotExists {
-				return errors.Trace(infoschema.ErrColumnExists.GenWithStackByArgs(specNewColumn.Name.Name.O))
+				return nil, nil, nil, nil, errors.Trace(infoschema.ErrColumnExists.GenWithStackByArgs(specNewColumn.Name.Name.O))
 			}
 			dupColumnNames[specNewColumn.Name.Name.L] = true
 		}
@@ -2878,52 +2948,163 @@ func (d *ddl) AddColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alte
 	positions := make([]*ast.ColumnPosition, 0, len(addingColumnNames))
 	offsets := make([]int, 0, len(addingColumnNames))
 	ifNotExists := make([]bool, 0, len(addingColumnNames))
-	newColumnsCount := 0
 	// Check the columns one by one.
 	for _, spec := range specs {
+		if spec.Tp != ast.AlterTableAddColumns {
+			continue
+		}
 		for _, specNewColumn := range spec.NewColumns {
 			if spec.IfNotExists && dupColumnNames[specNewColumn.Name.Name.L] {
-				err = infoschema.ErrColumnExists.GenWithStackByArgs(specNewColumn.Name.Name.O)
+				err := infoschema.ErrColumnExists.GenWithStackByArgs(specNewColumn.Name.Name.O)
 				ctx.GetSessionVars().StmtCtx.AppendNote(err)
 				continue
 			}
 			col, err := checkAndCreateNewColumn(ctx, ti, schema, spec, t, specNewColumn)
 			if err != nil {
-				return errors.Trace(err)
+				return nil, nil, nil, nil, errors.Trace(err)
 			}
 			// Added column has existed and if_not_exists flag is true.
 			if col == nil && spec.IfNotExists {
 				continue
 			}
+			if col.IsGenerated() && col.GeneratedStored {
+				return nil, nil, nil, nil, ErrUnsupportedOnGeneratedColumn.GenWithStackByArgs("Adding generated stored column along with other columns through ALTER TABLE")
+			}
 			columns = append(columns, col)
 			positions = append(positions, spec.Position)
 			offsets = append(offsets, 0)
 			ifNotExists = append(ifNotExists, spec.IfNotExists)
-			newColumnsCount++
 		}
 	}
-	if newColumnsCount == 0 {
-		return nil
-	}
-	if err = checkAddColumnTooManyColumns(len(t.Cols()) + newColumnsCount); err != nil {
-		return errors.Trace(err)
+	if err := checkAddColumnTooManyColumns(len(t.Cols()) + len(columns)); err != nil {
+		return nil, nil, nil, nil, errors.Trace(err)
 	}
+	return columns, positions, offsets, ifNotExists, nil
+}
 
-	job := &model.Job{
-		SchemaID:   schema.ID,
-		TableID:    t.Meta().ID,
-		SchemaName: schema.Name.L,
-		Type:       model.ActionAddColumns,
-		BinlogInfo: &model.HistoryInfo{},
-		Args:       []interface{}{columns, positions, offsets, ifNotExists},
+// isAddColumnsAndIndexesSpecs checks whether the specs only add columns and secondary indexes, which are run by a
+// multi-schema change job.
+func isAddColumnsAndIndexesSpecs(specs []*ast.AlterTableSpec) bool {
+	for _, spec := range specs {
+		switch spec.Tp {
+		case ast.AlterTableAddColumns:
+		case ast.AlterTableAddConstraint:
+			switch spec.Constraint.Tp {
+			case ast.ConstraintKey, ast.ConstraintIndex, ast.ConstraintUniq, ast.ConstraintUniqIndex, ast.ConstraintUniqKey:
+			default:
+				return false
+			}
+		default:
+			return false
+		}
 	}
+	return true
+}
 
-	err = d.doDDLJob(ctx, job)
+// addingIndex is an index added by MultiSchemaChange.
+type addingIndex struct {
+	keyType                 ast.IndexKeyType
+	name                    model.CIStr
+	indexPartSpecifications []*ast.IndexPartSpecification
+	option                  *ast.IndexOption
+}
+
+// MultiSchemaChange adds the columns and secondary indexes of the specs. All of them are checked first, then the
+// columns are added by one ActionAddColumns job and the indexes by one ActionAddIndex job each, so the binlog, TiCDC
+// and BR can replay them. It isn't atomic, the other sessions may see the columns before the indexes. If an index
+// can't be added, the columns and the indexes added before it are kept, because dropping them may drop the data
+// written by the other sessions. So it's only run if tidb_enable_change_multi_schema is on.
+func (d *ddl) MultiSchemaChange(ctx sessionctx.Context, ti ast.Ident, specs []*ast.AlterTableSpec) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
 	if err != nil {
 		return errors.Trace(err)
 	}
-	err = d.callHookOnChanged(err)
-	return errors.Trace(err)
+	columns, positions, offsets, ifNotExists, err := checkAndCreateNewColumns(ctx, ti, schema, t, specs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tblInfo := t.Meta()
+	allColumns := make([]*model.ColumnInfo, 0, len(tblInfo.Columns)+len(columns))
+	allColumns = append(allColumns, tblInfo.Columns...)
+	for _, col := range columns {
+		allColumns = append(allColumns, col.ColumnInfo)
+	}
+
+	indexes := make([]*addingIndex, 0, len(specs))
+	addingIndexNames := make(map[string]bool)
+	for _, spec := range specs {
+		if spec.Tp != ast.AlterTableAddConstraint {
+			continue
+		}
+		constr := spec.Constraint
+		unique := constr.Tp == ast.ConstraintUniq || constr.Tp == ast.ConstraintUniqIndex || constr.Tp == ast.ConstraintUniqKey
+		for _, part := range constr.Keys {
+			if part.Expr != nil {
+				// The hidden columns of the expression indexes aren't supported here.
+				return errRunMultiSchemaChanges
+			}
+		}
+		indexName := model.NewCIStr(constr.Name)
+		// Deal with anonymous index.
+		if len(indexName.L) == 0 {
+			colName := constr.Keys[0].Column.Name
+			indexName = getAnonymousIndex(t, colName, model.NewCIStr(""))
+			for id := 2; addingIndexNames[indexName.L]; id++ {
+				indexName = model.NewCIStr(fmt.Sprintf("%s_%d", colName.O, id))
+			}
+		}
+		if indexInfo := tblInfo.FindIndexByName(indexName.L); indexInfo != nil || addingIndexNames[indexName.L] {
+			err = ErrDupKeyName.GenWithStack("index already exist %s", indexName)
+			// IfNotExists should be not applied to the unique index.
+			if constr.IfNotExists && !unique {
+				ctx.GetSessionVars().StmtCtx.AppendNote(err)
+				continue
+			}
+			return err
+		}
+		if err = checkTooLongIndex(indexName); err != nil {
+			return errors.Trace(err)
+		}
+		// The indexes are checked before the columns are added, so a bad index doesn't leave the columns behind.
+		indexColumns, err := buildIndexColumns(allColumns, constr.Keys)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if unique && tblInfo.GetPartitionInfo() != nil && !config.GetGlobalConfig().EnableGlobalIndex {
+			ck, err := checkPartitionKeysConstraint(tblInfo.GetPartitionInfo(), indexColumns, tblInfo)
+			if err != nil {
+				return err
+			}
+			if !ck {
+				return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX")
+			}
+		}
+		keyType := ast.IndexKeyTypeNone
+		if unique {
+			keyType = ast.IndexKeyTypeUnique
+		}
+		addingIndexNames[indexName.L] = true
+		indexes = append(indexes, &addingIndex{
+			keyType:                 keyType,
+			name:                    indexName,
+			indexPartSpecifications: constr.Keys,
+			option:                  constr.Option,
+		})
+	}
+
+	if len(columns) > 0 {
+		err = d.doAddColumnsJob(ctx, schema, tblInfo, columns, positions, offsets, ifNotExists)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for _, idx := range indexes {
+		err = d.CreateIndex(ctx, ti, idx.keyType, idx.name, idx.indexPartSpecifications, idx.option, false)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
 }
 
 // AddTablePartitions will add a new partition to the table.
@@ -3020,6 +3201,91 @@ func (d *ddl) CoalescePartitions(ctx sessionctx.Context, ident ast.Ident, spec *
 	return errors.Trace(err)
 }
 
+// ReorganizePartitions splits or merges the consecutive range partitions into the new partitions, the data of
+// them is copied into the new partitions online.
+func (d *ddl) ReorganizePartitions(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	// The job type isn't known by the binlog, TiCDC and BR yet, so it's disabled by default.
+	if !ctx.GetSessionVars().TiDBEnableReorganizePartition {
+		ctx.GetSessionVars().StmtCtx.AppendWarning(errReorganizePartitionDisabled)
+		return nil
+	}
+	is := d.infoCache.GetLatest()
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return errors.Trace(infoschema.ErrDatabaseNotExists.GenWithStackByArgs(schema))
+	}
+	t, err := is.TableByName(ident.Schema, ident.Name)
+	if err != nil {
+		return errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ident.Schema, ident.Name))
+	}
+
+	meta := t.Meta()
+	pi := meta.GetPartitionInfo()
+	if pi == nil {
+		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+	if spec.OnAllPartitions {
+		return errors.Trace(ErrReorgNoParam)
+	}
+	// Only the range partitions partitioned by an expression are supported now, the partitions having a global
+	// index or TiFlash replicas aren't supported either.
+	if pi.Type != model.PartitionTypeRange || len(pi.Columns) > 0 || hasGlobalIndex(meta) || meta.TiFlashReplica != nil {
+		return errors.Trace(errUnsupportedReorganizePartition)
+	}
+
+	partNames := make([]string, len(spec.PartitionNames))
+	for i, partCIName := range spec.PartitionNames {
+		partNames[i] = partCIName.L
+	}
+	first, last, err := checkReorganizePartition(meta, partNames)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	partInfo, err := buildAddedPartitionInfo(ctx, meta, spec)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := d.assignPartitionIDs(partInfo.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+
+	// The new partitions take the place of the reorganized ones, check all the partitions are still valid.
+	clonedMeta := meta.Clone()
+	tmp := *partInfo
+	tmp.Definitions = make([]model.PartitionDefinition, 0, len(pi.Definitions)-(last-first+1)+len(partInfo.Definitions))
+	tmp.Definitions = append(tmp.Definitions, pi.Definitions[:first]...)
+	tmp.Definitions = append(tmp.Definitions, partInfo.Definitions...)
+	tmp.Definitions = append(tmp.Definitions, pi.Definitions[last+1:]...)
+	clonedMeta.Partition = &tmp
+	if err := checkPartitionDefinitionConstraints(ctx, clonedMeta); err != nil {
+		return errors.Trace(err)
+	}
+	// The total range can't be changed, except that the range of the last partition can be extended.
+	isUnsigned := isColUnsigned(meta.Columns, pi) && !ctx.GetSessionVars().SQLMode.HasNoUnsignedSubtractionMode()
+	oldBound := pi.Definitions[last].LessThan[0]
+	newBound := partInfo.Definitions[len(partInfo.Definitions)-1].LessThan[0]
+	cmp, err := compareRangeBound(ctx, newBound, oldBound, isUnsigned)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if cmp < 0 || (cmp > 0 && last != len(pi.Definitions)-1) {
+		return errors.Trace(ErrReorgOutsideRange)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       ActionReorganizePartition,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{partNames, partInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func (d *ddl) TruncateTablePartition(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
 	is := d.infoCache.GetLatest()
 	schema, ok := is.SchemaByName(ident.Schema)
@@ -3805,6 +4071,9 @@ func (d *ddl) getModifiableColumnJob(ctx sessionctx.Context, ident ast.Ident, or
 		if c != nil {
 			return nil, infoschema.ErrColumnExists.GenWithStackByArgs(newColName)
 		}
+		if err = checkColumnReferredByCheckConstraint(t.Meta(), originalColName); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 
 	// Constraints in the new column means adding new constraints. Errors should thrown,
@@ -4171,6 +4440,9 @@ func (d *ddl) RenameColumn(ctx sessionctx.Context, ident ast.Ident, spec *ast.Al
 	if fkInfo := getColumnForeignKeyInfo(oldColName.L, tbl.Meta().ForeignKeys); fkInfo != nil {
 		return errFKIncompatibleColumns.GenWithStackByArgs(oldColName, fkInfo.Name)
 	}
+	if err := checkColumnReferredByCheckConstraint(tbl.Meta(), oldColName); err != nil {
+		return errors.Trace(err)
+	}
 
 	// Check generated expression.
 	for _, col := range allCols {
@@ -5333,6 +5605,55 @@ func (d *ddl) DropForeignKey(ctx sessionctx.Context, ti ast.Ident, fkName model.
 	return errors.Trace(err)
 }
 
+// CreateCheckConstraint adds a check constraint to the table, the existing rows are verified before it takes effect.
+func (d *ddl) CreateCheckConstraint(ctx sessionctx.Context, ti ast.Ident, constr *ast.Constraint) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	constraintInfo, err := buildConstraintInfo(ctx, t.Meta(), constr)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    t.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAddCheckConstraint,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{constraintInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// DropCheckConstraint drops the check constraint of the table.
+func (d *ddl) DropCheckConstraint(ctx sessionctx.Context, ti ast.Ident, constrName model.CIStr) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if t.Meta().FindConstraintInfoByName(constrName.L) == nil {
+		return ErrCheckConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    t.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionDropCheckConstraint,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{constrName},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func (d *ddl) DropIndex(ctx sessionctx.Context, ti ast.Ident, indexName model.CIStr, ifExists bool) error {
 	is := d.infoCache.GetLatest()
 	schema, ok := is.SchemaByName(ti.Schema)
@@ -5406,6 +5727,9 @@ func isDroppableColumn(tblInfo *model.TableInfo, colName model.CIStr) error {
 	if ok, dep := hasDependentByGeneratedColumn(tblInfo, colName); ok {
 		return errDependentByGeneratedColumn.GenWithStackByArgs(dep)
 	}
+	if err := checkColumnReferredByCheckConstraint(tblInfo, colName); err != nil {
+		return errors.Trace(err)
+	}
 
 	if len(tblInfo.Columns) == 1 {
 		return ErrCantRemoveAllFields.GenWithStack("can't drop only column %s in table %s",
@@ -87,6 +87,9 @@ type worker struct {
 	reorgCtx        *reorgCtx    // reorgCtx is used for reorganization.
 	delRangeManager delRangeManager
 	logCtx          context.Context
+	// jobIdx is the index of the handling job in the DDL job queue.
+	// It's only valid in the transaction which gets the job.
+	jobIdx int64
 }
 
 func newWorker(ctx context.Context, tp workerType, sessPool *sessionPool, delRangeMgr delRangeManager) *worker {
@@ -317,6 +320,123 @@ func (w *worker) getFirstDDLJob(t *meta.Meta) (*model.Job, error) {
 	return job, errors.Trace(err)
 }
 
+// getRunnableDDLJob gets the DDL job which is going to be handled by the worker, and records its index in w.jobIdx.
+// The add index worker always handles the first job in its queue.
+// A general worker keeps handling the job claimed by it until the job is finished. If it hasn't claimed any job,
+// it claims the first job which isn't claimed by other workers and doesn't conflict with any job before it in the queue.
+// It must be called with d.runningJobs locked.
+func (w *worker) getRunnableDDLJob(d *ddlCtx, t *meta.Meta) (*model.Job, error) {
+	w.jobIdx = 0
+	if w.tp != generalWorker {
+		return w.getFirstDDLJob(t)
+	}
+
+	for jobID, workerID := range d.runningJobs.ids {
+		if workerID != w.id {
+			continue
+		}
+		job, idx, err := getDDLJobByID(t, jobID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if job != nil {
+			w.jobIdx = idx
+			return job, nil
+		}
+		// The claimed job has been finished, maybe by the previous DDL owner.
+		delete(d.runningJobs.ids, jobID)
+		break
+	}
+
+	var prevJobs []*model.Job
+	for i := int64(0); ; i++ {
+		job, err := t.GetDDLJobByIdx(i)
+		if job == nil || err != nil {
+			return nil, errors.Trace(err)
+		}
+		if _, ok := d.runningJobs.ids[job.ID]; !ok && !isJobConflictWithJobs(job, prevJobs) {
+			d.runningJobs.ids[job.ID] = w.id
+			w.jobIdx = i
+			return job, nil
+		}
+		prevJobs = append(prevJobs, job)
+	}
+}
+
+// getDDLJobByID gets the DDL job and its index in the DDL job queue by the job ID.
+func getDDLJobByID(t *meta.Meta, jobID int64) (*model.Job, int64, error) {
+	for i := int64(0); ; i++ {
+		job, err := t.GetDDLJobByIdx(i)
+		if job == nil || err != nil {
+			return nil, 0, errors.Trace(err)
+		}
+		if job.ID == jobID {
+			return job, i, nil
+		}
+	}
+}
+
+// releaseDDLJob releases the job claimed by the general worker.
+func (w *worker) releaseDDLJob(d *ddlCtx, job *model.Job) {
+	if w.tp != generalWorker {
+		return
+	}
+	d.runningJobs.Lock()
+	delete(d.runningJobs.ids, job.ID)
+	d.runningJobs.Unlock()
+}
+
+// jobScope is the scope of the schema objects which may be changed by a DDL job.
+type jobScope byte
+
+const (
+	// jobScopeTable means the job only changes the table of job.TableID.
+	jobScopeTable jobScope = iota
+	// jobScopeSchema means the job may change any table in the schema of job.SchemaID,
+	// or it needs the table names in the schema not to be changed by other jobs.
+	jobScopeSchema
+	// jobScopeGlobal means the job may change the objects in more than one schema.
+	jobScopeGlobal
+)
+
+func getJobScope(job *model.Job) jobScope {
+	switch job.Type {
+	case model.ActionRenameTable, model.ActionRenameTables, model.ActionExchangeTablePartition, model.ActionRecoverTable,
+		model.ActionLockTable, model.ActionUnlockTable:
+		return jobScopeGlobal
+	case model.ActionCreateSchema, model.ActionDropSchema, model.ActionModifySchemaCharsetAndCollate,
+		model.ActionCreateTable, model.ActionCreateView, model.ActionCreateSequence:
+		return jobScopeSchema
+	default:
+		return jobScopeTable
+	}
+}
+
+// isJobConflict checks whether the two DDL jobs can't be run concurrently.
+func isJobConflict(job1, job2 *model.Job) bool {
+	scope1, scope2 := getJobScope(job1), getJobScope(job2)
+	if scope1 == jobScopeGlobal || scope2 == jobScopeGlobal {
+		return true
+	}
+	if job1.SchemaID != job2.SchemaID {
+		return false
+	}
+	if scope1 == jobScopeSchema || scope2 == jobScopeSchema {
+		return true
+	}
+	return job1.TableID == job2.TableID
+}
+
+// isJobConflictWithJobs checks whether the job conflicts with any one of the jobs.
+func isJobConflictWithJobs(job *model.Job, jobs []*model.Job) bool {
+	for _, other := range jobs {
+		if isJobConflict(job, other) {
+			return true
+		}
+	}
+	return false
+}
+
 // handleUpdateJobError handles the too large DDL job.
 func (w *worker) handleUpdateJobError(t *meta.Meta, job *model.Job, err error) error {
 	if err == nil {
@@ -351,7 +471,7 @@ func (w *worker) updateDDLJob(t *meta.Meta, job *model.Job, meetErr bool) error
 			zap.String("job", job.String()))
 		updateRawArgs = false
 	}
-	return errors.Trace(t.UpdateDDLJob(0, job, updateRawArgs))
+	return errors.Trace(t.UpdateDDLJob(w.jobIdx, job, updateRawArgs))
 }
 
 func (w *worker) deleteRange(job *model.Job) error {
@@ -382,7 +502,8 @@ func (w *worker) finishDDLJob(t *meta.Meta, job *model.Job) (err error) {
 			// After rolling back an AddIndex operation, we need to use delete-range to delete the half-done index data.
 			err = w.deleteRange(job)
 		case model.ActionDropSchema, model.ActionDropTable, model.ActionTruncateTable, model.ActionDropIndex, model.ActionDropPrimaryKey,
-			model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionDropColumn, model.ActionDropColumns, model.ActionModifyColumn:
+			model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionDropColumn, model.ActionDropColumns, model.ActionModifyColumn,
+			ActionReorganizePartition:
 			err = w.deleteRange(job)
 		}
 	}
@@ -393,7 +514,7 @@ func (w *worker) finishDDLJob(t *meta.Meta, job *model.Job) (err error) {
 		return errors.Trace(err)
 	}
 
-	_, err = t.DeQueueDDLJob()
+	_, err = t.DeQueueDDLJobByIdx(w.jobIdx)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -466,6 +587,11 @@ func (w *worker) handleDDLJobQueue(d *ddlCtx) error {
 			runJobErr error
 		)
 		waitTime := 2 * d.lease
+		if w.tp == generalWorker {
+			// The general workers run their transactions one by one to avoid conflicting on the job queue
+			// and the schema version, then only the waiting for the schema changes runs concurrently.
+			d.runningJobs.Lock()
+		}
 		err := kv.RunInNewTxn(context.Background(), d.store, false, func(ctx context.Context, txn kv.Transaction) error {
 			// We are not owner, return and retry checking later.
 			if !d.isOwner() {
@@ -474,8 +600,8 @@ func (w *worker) handleDDLJobQueue(d *ddlCtx) error {
 
 			var err error
 			t := newMetaWithQueueTp(txn, w.typeStr())
-			// We become the owner. Get the first job and run it.
-			job, err = w.getFirstDDLJob(t)
+			// We become the owner. Get the runnable job and run it.
+			job, err = w.getRunnableDDLJob(d, t)
 			if job == nil || err != nil {
 				return errors.Trace(err)
 			}
@@ -528,6 +654,9 @@ func (w *worker) handleDDLJobQueue(d *ddlCtx) error {
 			writeBinlog(d.binlogCli, txn, job)
 			return nil
 		})
+		if w.tp == generalWorker {
+			d.runningJobs.Unlock()
+		}
 
 		if runJobErr != nil {
 			// wait a while to retry again. If we don't wait here, DDL will retry this job immediately,
@@ -564,6 +693,7 @@ func (w *worker) handleDDLJobQueue(d *ddlCtx) error {
 		d.mu.RUnlock()
 
 		if job.IsSynced() || job.IsCancelled() || job.IsRollbackDone() {
+			w.releaseDDLJob(d, job)
 			asyncNotify(d.ddlJobDoneCh)
 		}
 	}
@@ -722,8 +852,10 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onTruncateTablePartition(d, t, job)
 	case model.ActionExchangeTablePartition:
 		ver, err = w.onExchangeTablePartition(d, t, job)
+	case ActionReorganizePartition:
+		ver, err = w.onReorganizePartition(d, t, job)
 	case model.ActionAddColumn:
-		ver, err = onAddColumn(d, t, job)
+		ver, err = w.onAddColumn(d, t, job)
 	case model.ActionAddColumns:
 		ver, err = onAddColumns(d, t, job)
 	case model.ActionDropColumn:
@@ -746,6 +878,10 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onCreateForeignKey(t, job)
 	case model.ActionDropForeignKey:
 		ver, err = onDropForeignKey(t, job)
+	case model.ActionAddCheckConstraint:
+		ver, err = w.onAddCheckConstraint(t, job)
+	case model.ActionDropCheckConstraint:
+		ver, err = onDropCheckConstraint(t, job)
 	case model.ActionTruncateTable:
 		ver, err = onTruncateTable(d, t, job)
 	case model.ActionRebaseAutoID:
@@ -15,6 +15,7 @@ package ddl
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -26,6 +27,7 @@ import (
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/sessionctx"
@@ -203,6 +205,157 @@ func (s *testDDLSerialSuite) testRunWorker(c *C) {
 	c.Assert(worker, NotNil)
 }
 
+func (s *testDDLSuite) TestJobConflict(c *C) {
+	addColumn := &model.Job{SchemaID: 1, TableID: 2, Type: model.ActionAddColumn}
+	tests := []struct {
+		job      *model.Job
+		conflict bool
+	}{
+		{&model.Job{SchemaID: 1, TableID: 2, Type: model.ActionDropColumn}, true},
+		{&model.Job{SchemaID: 1, TableID: 3, Type: model.ActionDropColumn}, false},
+		{&model.Job{SchemaID: 4, TableID: 2, Type: model.ActionDropColumn}, false},
+		{&model.Job{SchemaID: 1, TableID: 5, Type: model.ActionCreateTable}, true},
+		{&model.Job{SchemaID: 4, TableID: 5, Type: model.ActionCreateTable}, false},
+		{&model.Job{SchemaID: 1, Type: model.ActionDropSchema}, true},
+		{&model.Job{SchemaID: 4, Type: model.ActionDropSchema}, false},
+		{&model.Job{SchemaID: 4, TableID: 5, Type: model.ActionRenameTable}, true},
+		{&model.Job{SchemaID: 4, TableID: 5, Type: model.ActionExchangeTablePartition}, true},
+	}
+	for _, t := range tests {
+		c.Assert(isJobConflict(addColumn, t.job), Equals, t.conflict, Commentf("%v", t.job))
+		c.Assert(isJobConflict(t.job, addColumn), Equals, t.conflict, Commentf("%v", t.job))
+	}
+}
+
+func (s *testDDLSuite) TestGetRunnableDDLJob(c *C) {
+	store := testCreateStore(c, "test_get_runnable_job")
+	defer func() {
+		err := store.Close()
+		c.Assert(err, IsNil)
+	}()
+
+	d := &ddlCtx{}
+	d.runningJobs.ids = make(map[int64]int32)
+	w1 := newWorker(context.Background(), generalWorker, nil, nil)
+	w2 := newWorker(context.Background(), generalWorker, nil, nil)
+	w3 := newWorker(context.Background(), generalWorker, nil, nil)
+	jobs := []*model.Job{
+		{ID: 1, SchemaID: 1, TableID: 2, Type: model.ActionAddColumn},
+		{ID: 2, SchemaID: 1, TableID: 2, Type: model.ActionDropColumn},
+		{ID: 3, SchemaID: 3, TableID: 4, Type: model.ActionAddColumn},
+		{ID: 4, SchemaID: 1, TableID: 5, Type: model.ActionCreateTable},
+	}
+	checkRunnableJob := func(t *meta.Meta, w *worker, expectedID, expectedIdx int64) {
+		job, err := w.getRunnableDDLJob(d, t)
+		c.Assert(err, IsNil)
+		if expectedID == 0 {
+			c.Assert(job, IsNil)
+			return
+		}
+		c.Assert(job, NotNil)
+		c.Assert(job.ID, Equals, expectedID)
+		c.Assert(w.jobIdx, Equals, expectedIdx)
+	}
+	err := kv.RunInNewTxn(context.Background(), store, false, func(ctx context.Context, txn kv.Transaction) error {
+		t := meta.NewMeta(txn)
+		for _, job := range jobs {
+			c.Assert(t.EnQueueDDLJob(job), IsNil)
+		}
+		checkRunnableJob(t, w1, 1, 0)
+		// Job 2 conflicts with job 1, so w2 gets job 3.
+		checkRunnableJob(t, w2, 3, 2)
+		// Job 4 conflicts with job 1 since it creates a table in the same schema.
+		checkRunnableJob(t, w3, 0, 0)
+		// The workers keep handling their claimed jobs.
+		checkRunnableJob(t, w1, 1, 0)
+		checkRunnableJob(t, w2, 3, 2)
+
+		// Finish job 1.
+		w1.jobIdx = 0
+		_, err := t.DeQueueDDLJobByIdx(w1.jobIdx)
+		c.Assert(err, IsNil)
+		w1.releaseDDLJob(d, jobs[0])
+		checkRunnableJob(t, w1, 2, 0)
+		checkRunnableJob(t, w2, 3, 1)
+		checkRunnableJob(t, w3, 0, 0)
+
+		// The claimed job is removed from the queue by others, the worker claims a new job.
+		_, err = t.DeQueueDDLJobByIdx(0)
+		c.Assert(err, IsNil)
+		checkRunnableJob(t, w1, 4, 1)
+		return nil
+	})
+	c.Assert(err, IsNil)
+}
+
+func (s *testDDLSerialSuite) TestConcurrentGeneralWorkers(c *C) {
+	store := testCreateStore(c, "test_concurrent_general_workers")
+	defer func() {
+		err := store.Close()
+		c.Assert(err, IsNil)
+	}()
+
+	originalCnt := config.GetGlobalConfig().DDLGeneralWorkerCount
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.DDLGeneralWorkerCount = 3
+	})
+	defer config.UpdateGlobal(func(conf *config.Config) {
+		conf.DDLGeneralWorkerCount = originalCnt
+	})
+	d := testNewDDLAndStart(
+		context.Background(),
+		c,
+		WithStore(store),
+		WithLease(testLease),
+	)
+	defer func() {
+		err := d.Stop()
+		c.Assert(err, IsNil)
+	}()
+	c.Assert(d.extraGeneralWorkers, HasLen, 2)
+
+	var wg sync.WaitGroup
+	dbInfos := make([]*model.DBInfo, 3)
+	tblInfos := make([]*model.TableInfo, len(dbInfos))
+	for i := range dbInfos {
+		dbInfos[i] = testSchemaInfo(c, d, fmt.Sprintf("test_concurrent_%d", i))
+		tblInfos[i] = testTableInfo(c, d, "t", 3)
+	}
+	errs := make([]error, len(dbInfos))
+	for i := range dbInfos {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := testNewContext(d)
+			job := &model.Job{
+				SchemaID:   dbInfos[i].ID,
+				Type:       model.ActionCreateSchema,
+				BinlogInfo: &model.HistoryInfo{},
+				Args:       []interface{}{dbInfos[i]},
+			}
+			if errs[i] = d.doDDLJob(ctx, job); errs[i] != nil {
+				return
+			}
+			job = &model.Job{
+				SchemaID:   dbInfos[i].ID,
+				TableID:    tblInfos[i].ID,
+				Type:       model.ActionCreateTable,
+				BinlogInfo: &model.HistoryInfo{},
+				Args:       []interface{}{tblInfos[i]},
+			}
+			errs[i] = d.doDDLJob(ctx, job)
+		}(i)
+	}
+	wg.Wait()
+	for i := range dbInfos {
+		c.Assert(errs[i], IsNil)
+		testCheckTableState(c, d, dbInfos[i], tblInfos[i], model.StatePublic)
+	}
+	d.runningJobs.Lock()
+	c.Assert(d.runningJobs.ids, HasLen, 0)
+	d.runningJobs.Unlock()
+}
+
 func (s *testDDLSuite) TestSchemaError(c *C) {
 	store := testCreateStore(c, "test_schema_error")
 	defer func() {
@@ -261,11 +261,13 @@ func checkModifyGeneratedColumn(tbl table.Table, oldCol, newCol *table.Column, n
 }
 
 type illegalFunctionChecker struct {
-	hasIllegalFunc bool
-	hasAggFunc     bool
-	hasRowVal      bool // hasRowVal checks whether the functional index refers to a row value
-	hasWindowFunc  bool
-	otherErr       error
+	hasIllegalFunc  bool
+	illegalFuncName string
+	hasVariable     bool
+	hasAggFunc      bool
+	hasRowVal       bool // hasRowVal checks whether the functional index refers to a row value
+	hasWindowFunc   bool
+	otherErr        error
 }
 
 func (c *illegalFunctionChecker) Enter(inNode ast.Node) (outNode ast.Node, skipChildren bool) {
@@ -275,6 +277,7 @@ func (c *illegalFunctionChecker) Enter(inNode ast.Node) (outNode ast.Node, skipC
 		_, IsFunctionBlocked := expression.IllegalFunctions4GeneratedColumns[node.FnName.L]
 		if IsFunctionBlocked || !expression.IsFunctionSupported(node.FnName.L) {
 			c.hasIllegalFunc = true
+			c.illegalFuncName = node.FnName.O
 			return inNode, true
 		}
 		err := expression.VerifyArgsWrapper(node.FnName.L, len(node.Args))
@@ -285,6 +288,14 @@ func (c *illegalFunctionChecker) Enter(inNode ast.Node) (outNode ast.Node, skipC
 	case *ast.SubqueryExpr, *ast.ValuesExpr, *ast.VariableExpr:
 		// Subquery & `values(x)` & variable is not allowed
 		c.hasIllegalFunc = true
+		switch inNode.(type) {
+		case *ast.SubqueryExpr:
+			c.illegalFuncName = "subquery"
+		case *ast.ValuesExpr:
+			c.illegalFuncName = ast.Values
+		default:
+			c.hasVariable = true
+		}
 		return inNode, true
 	case *ast.AggregateFuncExpr:
 		// Aggregate function is not allowed
@@ -307,6 +318,7 @@ func (c *illegalFunctionChecker) Leave(inNode ast.Node) (node ast.Node, ok bool)
 const (
 	typeColumn = iota
 	typeIndex
+	typeCheckConstraint
 )
 
 func checkIllegalFn4Generated(name string, genType int, expr ast.ExprNode) error {
@@ -321,12 +333,18 @@ func checkIllegalFn4Generated(name string, genType int, expr ast.ExprNode) error
 			return ErrGeneratedColumnFunctionIsNotAllowed.GenWithStackByArgs(name)
 		case typeIndex:
 			return ErrFunctionalIndexFunctionIsNotAllowed.GenWithStackByArgs(name)
+		case typeCheckConstraint:
+			if c.hasVariable {
+				return ErrCheckConstraintVariables.GenWithStackByArgs(name)
+			}
+			return ErrCheckConstraintFunctionIsNotAllowed.GenWithStackByArgs(name, c.illegalFuncName)
 		}
 	}
 	if c.hasAggFunc {
 		return ErrInvalidGroupFuncUse
 	}
-	if c.hasRowVal {
+	// The row values can be compared in the check constraints.
+	if c.hasRowVal && genType != typeCheckConstraint {
 		switch genType {
 		case typeColumn:
 			return ErrGeneratedColumnRowValueIsNotAllowed.GenWithStackByArgs(name)
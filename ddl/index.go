@@ -1393,13 +1393,18 @@ func (w *worker) updateReorgInfoForPartitions(t table.PartitionedTable, reorg *r
 	}
 
 	var pid int64
-	for i, pi := range partitionIDs {
-		if pi == reorg.PhysicalTableID {
+	for i, id := range partitionIDs {
+		if id == reorg.PhysicalTableID {
 			if i == len(partitionIDs)-1 {
 				return true, nil
 			}
+			pid = partitionIDs[i+1]
+			break
 		}
-		pid = partitionIDs[i+1]
+	}
+	if pid == 0 {
+		// Fatal error, should not run here.
+		return false, errors.Errorf("partition id not found %d", reorg.PhysicalTableID)
 	}
 
 	currentVer, err := getValidCurrentVersion(reorg.d.store)
@@ -36,10 +36,12 @@ import (
 	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	driver "github.com/pingcap/tidb/types/parser_driver"
@@ -48,8 +50,11 @@ import (
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
+	decoder "github.com/pingcap/tidb/util/rowDecoder"
 	"github.com/pingcap/tidb/util/slice"
 	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/client-go/v2/tikv"
 	"go.uber.org/zap"
 )
@@ -1040,6 +1045,428 @@ func (w *worker) onDropTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (
 	return ver, errors.Trace(err)
 }
 
+// checkReorganizePartition checks if the partitions to reorganize exist and are in consecutive order,
+// it returns the offsets of the first and the last ones of them.
+func checkReorganizePartition(meta *model.TableInfo, partLowerNames []string) (first, last int, _ error) {
+	defs := meta.Partition.Definitions
+	for i, pn := range partLowerNames {
+		offset := -1
+		for j := range defs {
+			if defs[j].Name.L == pn {
+				offset = j
+				break
+			}
+		}
+		if offset < 0 {
+			return 0, 0, errors.Trace(ErrDropPartitionNonExistent.GenWithStackByArgs("REORGANIZE"))
+		}
+		if i == 0 {
+			first = offset
+		} else if offset != first+i {
+			return 0, 0, errors.Trace(ErrConsecutiveReorgPartitions)
+		}
+	}
+	return first, first + len(partLowerNames) - 1, nil
+}
+
+// compareRangeBound compares the `less than value` of two range partitions.
+func compareRangeBound(ctx sessionctx.Context, a, b string, unsigned bool) (int, error) {
+	aIsMax, bIsMax := strings.EqualFold(a, partitionMaxValue), strings.EqualFold(b, partitionMaxValue)
+	if aIsMax || bIsMax {
+		switch {
+		case aIsMax && bIsMax:
+			return 0, nil
+		case aIsMax:
+			return 1, nil
+		default:
+			return -1, nil
+		}
+	}
+	aValue, _, err := getRangeValue(ctx, a, unsigned)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	bValue, _, err := getRangeValue(ctx, b, unsigned)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if unsigned {
+		return types.CompareUint64(aValue.(uint64), bValue.(uint64)), nil
+	}
+	return types.CompareInt64(aValue.(int64), bValue.(int64)), nil
+}
+
+// setPartitionStates sets the states of the partitions in the partition info.
+func setPartitionStates(pi *model.PartitionInfo, defs []model.PartitionDefinition, state model.SchemaState) {
+	for _, def := range defs {
+		pi.SetStateByID(def.ID, state)
+	}
+}
+
+// replaceReorganizedPartitions replaces the reorganized partitions in `Definitions` by the new partitions.
+func replaceReorganizedPartitions(pi *model.PartitionInfo) {
+	first := -1
+	for i, def := range pi.Definitions {
+		if def.ID == pi.DroppingDefinitions[0].ID {
+			first = i
+			break
+		}
+	}
+	last := first + len(pi.DroppingDefinitions)
+	defs := make([]model.PartitionDefinition, 0, len(pi.Definitions)-len(pi.DroppingDefinitions)+len(pi.AddingDefinitions))
+	defs = append(defs, pi.Definitions[:first]...)
+	defs = append(defs, pi.AddingDefinitions...)
+	defs = append(defs, pi.Definitions[last:]...)
+	pi.Definitions = defs
+}
+
+// onReorganizePartition reorganizes the partitions into the new partitions.
+// The new partitions are kept in `AddingDefinitions` and the reorganized partitions are kept in `DroppingDefinitions`
+// during the job, the partitions which aren't public have their states in the partition info, the writes to the public
+// partitions are also applied to them, see tables.GetReorganizedTableInfo.
+// The new partitions go through the delete only, write only and write reorganization states like an index, the data
+// of the reorganized partitions is copied into them in the write reorganization state. Then they take the place of
+// the reorganized partitions in `Definitions`, and the reorganized partitions go through the write only and delete only
+// states before they are dropped.
+func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var partNames []string
+	partInfo := &model.PartitionInfo{}
+	if err := job.DecodeArgs(&partNames, &partInfo); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+	if job.IsRollingback() {
+		return rollbackReorganizePartition(t, job, tblInfo)
+	}
+
+	switch job.SchemaState {
+	case model.StateNone:
+		first, last, err := checkReorganizePartition(tblInfo, partNames)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		if len(pi.AddingDefinitions) > 0 || len(pi.DroppingDefinitions) > 0 {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(ErrInvalidDDLState.GenWithStackByArgs("partition", job.SchemaState))
+		}
+		pi.DroppingDefinitions = append([]model.PartitionDefinition(nil), pi.Definitions[first:last+1]...)
+		pi.AddingDefinitions = partInfo.Definitions
+		setPartitionStates(pi, pi.AddingDefinitions, model.StateDeleteOnly)
+		// none -> delete only
+		job.SchemaState = model.StateDeleteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
+	case model.StateDeleteOnly:
+		// delete only -> write only
+		setPartitionStates(pi, pi.AddingDefinitions, model.StateWriteOnly)
+		job.SchemaState = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	case model.StateWriteOnly:
+		// write only -> reorganization
+		setPartitionStates(pi, pi.AddingDefinitions, model.StateWriteReorganization)
+		job.SchemaState = model.StateWriteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	case model.StateWriteReorganization:
+		tbl, err := getTable(d.store, job.SchemaID, tblInfo)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		physicalTableIDs := getPartitionIDsFromDefinitions(pi.DroppingDefinitions)
+		// The element is only used to record the reorganization handle.
+		elements := []*meta.Element{{ID: tblInfo.Columns[0].ID, TypeKey: meta.ColumnElementKey}}
+		reorgInfo, err := getReorgInfoFromPartitions(d, t, job, tbl, physicalTableIDs, elements)
+		if err != nil || reorgInfo.first {
+			// If we run reorg firstly, we should update the job snapshot version
+			// and then run the reorg next time.
+			return ver, errors.Trace(err)
+		}
+		err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (reorgErr error) {
+			defer tidbutil.Recover(metrics.LabelDDL, "onReorganizePartition",
+				func() {
+					reorgErr = errCancelledDDLJob.GenWithStack("reorganize partition panic")
+				}, false)
+			return w.reorgPartitionsData(tbl.(table.PartitionedTable), physicalTableIDs, reorgInfo)
+		})
+		if err != nil {
+			if errWaitReorgTimeout.Equal(err) {
+				// if timeout, we should return, check for the owner and re-wait job done.
+				return ver, nil
+			}
+			if kv.ErrKeyExists.Equal(err) || errCancelledDDLJob.Equal(err) || errCantDecodeRecord.Equal(err) ||
+				table.ErrNoPartitionForGivenValue.Equal(err) {
+				logutil.BgLogger().Warn("[ddl] run reorganize partition job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+				ver, err = convertReorgPartitionJob2RollbackJob(t, job, tblInfo, err)
+				if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+					logutil.BgLogger().Warn("[ddl] run reorganize partition job failed, convert job to rollback, RemoveDDLReorgHandle failed", zap.String("job", job.String()), zap.Error(err1))
+				}
+			}
+			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+			w.reorgCtx.cleanNotifyReorgCancel()
+			return ver, errors.Trace(err)
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+
+		// The new partitions are public now, the reorganized partitions still receive the writes
+		// in case some servers read them.
+		replaceReorganizedPartitions(pi)
+		pi.States = nil
+		setPartitionStates(pi, pi.DroppingDefinitions, model.StateWriteOnly)
+		job.SchemaState = model.StateDeleteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		asyncNotifyEvent(d, &util.Event{Tp: model.ActionAddTablePartition, TableInfo: tblInfo, PartInfo: &model.PartitionInfo{Definitions: pi.AddingDefinitions}})
+	case model.StateDeleteReorganization:
+		physicalTableIDs := getPartitionIDsFromDefinitions(pi.DroppingDefinitions)
+		if pi.GetStateByID(physicalTableIDs[0]) == model.StateWriteOnly {
+			// write only -> delete only for the reorganized partitions.
+			setPartitionStates(pi, pi.DroppingDefinitions, model.StateDeleteOnly)
+			ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+			return ver, errors.Trace(err)
+		}
+		err = dropRuleBundles(d, physicalTableIDs)
+		if err != nil {
+			return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+		}
+		pi.AddingDefinitions = nil
+		pi.DroppingDefinitions = nil
+		pi.States = nil
+		// used by ApplyDiff in updateSchemaVersion
+		job.CtxVars = []interface{}{physicalTableIDs}
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+		// A background job will be created to delete old partition data.
+		job.Args = []interface{}{physicalTableIDs}
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("partition", job.SchemaState)
+	}
+	return ver, errors.Trace(err)
+}
+
+// rollbackReorganizePartition removes the new partitions, whose states have been changed to delete only.
+func rollbackReorganizePartition(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo) (ver int64, err error) {
+	pi := tblInfo.Partition
+	physicalTableIDs := getPartitionIDsFromDefinitions(pi.AddingDefinitions)
+	pi.AddingDefinitions = nil
+	pi.DroppingDefinitions = nil
+	pi.States = nil
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	// A background job will be created to delete the data copied into the new partitions.
+	job.Args = []interface{}{physicalTableIDs}
+	return ver, nil
+}
+
+// reorgPartitionsData copies the data of the reorganized partitions into the new partitions.
+func (w *worker) reorgPartitionsData(tbl table.PartitionedTable, physicalTableIDs []int64, reorgInfo *reorgInfo) error {
+	var err error
+	var finish bool
+	for !finish {
+		p := tbl.GetPartition(reorgInfo.PhysicalTableID)
+		if p == nil {
+			return errCancelledDDLJob.GenWithStack("Can not find partition id %d for table %d", reorgInfo.PhysicalTableID, tbl.Meta().ID)
+		}
+		logutil.BgLogger().Info("[ddl] start to reorganize partition", zap.String("job", reorgInfo.Job.String()), zap.String("reorgInfo", reorgInfo.String()))
+		err = w.writePhysicalTableRecord(p, typeReorgPartitionWorker, nil, nil, nil, reorgInfo)
+		if err != nil {
+			break
+		}
+		finish, err = w.updateReorgInfoForPartitions(tbl, reorgInfo, physicalTableIDs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(err)
+}
+
+type reorgPartitionWorker struct {
+	*backfillWorker
+	// reorgTable is the table whose reorganized partitions are replaced by the new partitions.
+	reorgTable    table.PartitionedTable
+	metricCounter prometheus.Counter
+
+	// The following attributes are used to reduce memory allocation.
+	rowRecords  []*partitionRowRecord
+	rowDecoder  *decoder.RowDecoder
+	rowMap      map[int64]types.Datum
+	defaultVals []types.Datum
+}
+
+type partitionRowRecord struct {
+	handle kv.Handle
+	key    kv.Key        // It's the record key in the new partition.
+	vals   []byte        // It's the record.
+	row    []types.Datum // It's the decoded record, used to build the index entries.
+	part   table.PhysicalTable
+}
+
+func newReorgPartitionWorker(sessCtx sessionctx.Context, worker *worker, id int, t table.PhysicalTable, decodeColMap map[int64]decoder.Column, reorgInfo *reorgInfo) (*reorgPartitionWorker, error) {
+	reorgTblInfo, _ := tables.GetReorganizedTableInfo(t.Meta())
+	if reorgTblInfo == nil {
+		return nil, errors.Errorf("table %d has no partition being reorganized", t.Meta().ID)
+	}
+	reorgTbl, err := getTable(reorgInfo.d.store, reorgInfo.Job.SchemaID, reorgTblInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &reorgPartitionWorker{
+		backfillWorker: newBackfillWorker(sessCtx, worker, id, t),
+		reorgTable:     reorgTbl.(table.PartitionedTable),
+		metricCounter:  metrics.BackfillTotalCounter.WithLabelValues("reorg_partition_speed"),
+		rowDecoder:     decoder.NewRowDecoder(t, t.WritableCols(), decodeColMap),
+		rowMap:         make(map[int64]types.Datum, len(decodeColMap)),
+		defaultVals:    make([]types.Datum, len(t.WritableCols())),
+	}, nil
+}
+
+func (w *reorgPartitionWorker) AddMetricInfo(cnt float64) {
+	w.metricCounter.Add(cnt)
+}
+
+func (w *reorgPartitionWorker) fetchRowColVals(txn kv.Transaction, taskRange reorgBackfillTask) ([]*partitionRowRecord, kv.Key, bool, error) {
+	w.rowRecords = w.rowRecords[:0]
+	startTime := time.Now()
+
+	// taskDone means that the added handle is out of taskRange.endHandle.
+	taskDone := false
+	var lastAccessedHandle kv.Key
+	err := iterateSnapshotRows(w.sessCtx.GetStore(), w.priority, w.table, txn.StartTS(), taskRange.startKey, taskRange.endKey,
+		func(handle kv.Handle, recordKey kv.Key, rawRow []byte) (bool, error) {
+			taskDone = recordKey.Cmp(taskRange.endKey) > 0
+			if taskDone || len(w.rowRecords) >= w.batchCnt {
+				return false, nil
+			}
+
+			if err1 := w.getRowRecord(handle, rawRow); err1 != nil {
+				return false, errors.Trace(err1)
+			}
+			lastAccessedHandle = recordKey
+			if recordKey.Cmp(taskRange.endKey) == 0 {
+				// If taskRange.endIncluded == false, we will not reach here when handle == taskRange.endHandle.
+				taskDone = true
+				return false, nil
+			}
+			return true, nil
+		})
+
+	if len(w.rowRecords) == 0 {
+		taskDone = true
+	}
+
+	logutil.BgLogger().Debug("[ddl] txn fetches handle info", zap.Uint64("txnStartTS", txn.StartTS()), zap.String("taskRange", taskRange.String()), zap.Duration("takeTime", time.Since(startTime)))
+	nextKey := taskRange.endKey.Next()
+	if !taskDone {
+		nextKey = lastAccessedHandle.Next()
+	}
+	return w.rowRecords, nextKey, taskDone, errors.Trace(err)
+}
+
+func (w *reorgPartitionWorker) getRowRecord(handle kv.Handle, rawRow []byte) error {
+	_, err := w.rowDecoder.DecodeAndEvalRowWithMap(w.sessCtx, handle, rawRow, time.UTC, timeutil.SystemLocation(), w.rowMap)
+	if err != nil {
+		return errors.Trace(errCantDecodeRecord.GenWithStackByArgs("partition", err))
+	}
+	cols := w.table.WritableCols()
+	row := make([]types.Datum, len(cols))
+	for i, col := range cols {
+		val, ok := w.rowMap[col.ID]
+		if !ok {
+			// The column is added after the row is written.
+			val, err = tables.GetColDefaultValue(w.sessCtx, col, w.defaultVals)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		row[i] = val
+	}
+	for id := range w.rowMap {
+		delete(w.rowMap, id)
+	}
+
+	// The partition is located by the public columns.
+	p, err := w.reorgTable.GetPartitionByRow(w.sessCtx, row[:len(w.table.Cols())])
+	if err != nil {
+		return errors.Trace(err)
+	}
+	w.rowRecords = append(w.rowRecords, &partitionRowRecord{
+		handle: handle,
+		key:    tablecodec.EncodeRecordKey(p.RecordPrefix(), handle),
+		vals:   rawRow,
+		row:    row,
+		part:   p,
+	})
+	return nil
+}
+
+// BackfillDataInTxn copies the records and builds their index entries in the new partitions in a transaction.
+// The records already written into the new partitions by the DML statements are skipped.
+func (w *reorgPartitionWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (taskCtx backfillTaskContext, errInTxn error) {
+	oprStartTime := time.Now()
+	errInTxn = kv.RunInNewTxn(context.Background(), w.sessCtx.GetStore(), true, func(ctx context.Context, txn kv.Transaction) error {
+		taskCtx.addedCount = 0
+		taskCtx.scanCount = 0
+		txn.SetOption(kv.Priority, w.priority)
+
+		rowRecords, nextKey, taskDone, err := w.fetchRowColVals(txn, handleRange)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		taskCtx.nextKey = nextKey
+		taskCtx.done = taskDone
+
+		isCommonHandle := w.table.Meta().IsCommonHandle
+		for _, record := range rowRecords {
+			taskCtx.scanCount++
+
+			_, err = txn.Get(ctx, record.key)
+			if err == nil {
+				continue
+			}
+			if !kv.IsErrNotFound(err) {
+				return errors.Trace(err)
+			}
+			if err = txn.Set(record.key, record.vals); err != nil {
+				return errors.Trace(err)
+			}
+			for _, idx := range record.part.Indices() {
+				if !tables.IsIndexWritable(idx) || (isCommonHandle && idx.Meta().Primary) {
+					continue
+				}
+				idxVals, err := idx.FetchValues(record.row, nil)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				rsData := tables.TryGetHandleRestoredDataWrapper(record.part, record.row, nil, idx.Meta())
+				if _, err = idx.Create(w.sessCtx, txn, idxVals, record.handle, rsData); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			taskCtx.addedCount++
+		}
+		return nil
+	})
+	logSlowOperations(time.Since(oprStartTime), "reorgPartitionBackfillDataInTxn", 3000)
+
+	return
+}
+
 // onTruncateTablePartition truncates old partition meta.
 func onTruncateTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (int64, error) {
 	var ver int64
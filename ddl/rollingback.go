@@ -151,28 +151,39 @@ func rollingbackModifyColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 	return ver, errCancelledDDLJob
 }
 
-func rollingbackAddColumn(t *meta.Meta, job *model.Job) (ver int64, err error) {
-	tblInfo, columnInfo, col, _, _, err := checkAddColumn(t, job)
-	if err != nil {
-		return ver, errors.Trace(err)
-	}
-	if columnInfo == nil {
-		job.State = model.JobStateCancelled
-		return ver, errCancelledDDLJob
-	}
-
+func convertAddColumnJob2RollbackJob(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, columnInfo *model.ColumnInfo, err error) (ver int64, _ error) {
 	originalState := columnInfo.State
 	columnInfo.State = model.StateDeleteOnly
 	job.SchemaState = model.StateDeleteOnly
 
-	job.Args = []interface{}{col.Name}
-	ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State)
-	if err != nil {
-		return ver, errors.Trace(err)
+	job.Args = []interface{}{columnInfo.Name}
+	ver, err1 := updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State)
+	if err1 != nil {
+		return ver, errors.Trace(err1)
 	}
 
 	job.State = model.JobStateRollingback
-	return ver, errCancelledDDLJob
+	return ver, errors.Trace(err)
+}
+
+func rollingbackAddColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	// If the value of SnapshotVer isn't zero, it means the work is backfilling the stored generated column.
+	if job.SchemaState == model.StateWriteReorganization && job.SnapshotVer != 0 {
+		// The backfill workers are started, need to ask them to exit.
+		logutil.Logger(w.logCtx).Info("[ddl] run the cancelling DDL job", zap.String("job", job.String()))
+		w.reorgCtx.notifyReorgCancel()
+		return w.onAddColumn(d, t, job)
+	}
+
+	tblInfo, columnInfo, _, _, _, err := checkAddColumn(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if columnInfo == nil {
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	}
+	return convertAddColumnJob2RollbackJob(t, job, tblInfo, columnInfo, errCancelledDDLJob)
 }
 
 func rollingbackAddColumns(t *meta.Meta, job *model.Job) (ver int64, err error) {
@@ -330,6 +341,62 @@ func rollingbackAddTablePartition(t *meta.Meta, job *model.Job) (ver int64, err
 	return convertAddTablePartitionJob2RollbackJob(t, job, errCancelledDDLJob, tblInfo)
 }
 
+func convertReorgPartitionJob2RollbackJob(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, otherwiseErr error) (ver int64, err error) {
+	// The new partitions only handle the deletes before they are removed.
+	setPartitionStates(tblInfo.Partition, tblInfo.Partition.AddingDefinitions, model.StateDeleteOnly)
+	job.SchemaState = model.StateDeleteOnly
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.State = model.JobStateRollingback
+	return ver, errors.Trace(otherwiseErr)
+}
+
+func rollingbackReorganizePartition(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	switch job.SchemaState {
+	case model.StateNone:
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	case model.StateDeleteOnly, model.StateWriteOnly, model.StateWriteReorganization:
+		// If the value of SnapshotVer isn't zero, it means the work is copying the data.
+		if job.SchemaState == model.StateWriteReorganization && job.SnapshotVer != 0 {
+			// reorganize partition workers are started. need to ask them to exit.
+			logutil.Logger(w.logCtx).Info("[ddl] run the cancelling DDL job", zap.String("job", job.String()))
+			w.reorgCtx.notifyReorgCancel()
+			return w.onReorganizePartition(d, t, job)
+		}
+		tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		return convertReorgPartitionJob2RollbackJob(t, job, tblInfo, errCancelledDDLJob)
+	default:
+		// The new partitions have been public, the job can't be cancelled.
+		job.State = model.JobStateRunning
+		return ver, nil
+	}
+}
+
+func rollingbackAddCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	// The constraint hasn't been added yet.
+	if job.SchemaState == model.StateNone {
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	constraintInfo := &model.ConstraintInfo{}
+	err = job.DecodeArgs(constraintInfo)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	return convertAddCheckConstraintJob2RollbackJob(t, job, tblInfo, constraintInfo.Name, errCancelledDDLJob)
+}
+
 func rollingbackDropTableOrView(t *meta.Meta, job *model.Job) error {
 	tblInfo, err := checkTableExistAndCancelNonExistJob(t, job, job.SchemaID)
 	if err != nil {
@@ -406,7 +473,7 @@ func rollingbackTruncateTable(t *meta.Meta, job *model.Job) (ver int64, err erro
 func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
 	switch job.Type {
 	case model.ActionAddColumn:
-		ver, err = rollingbackAddColumn(t, job)
+		ver, err = rollingbackAddColumn(w, d, t, job)
 	case model.ActionAddColumns:
 		ver, err = rollingbackAddColumns(t, job)
 	case model.ActionAddIndex:
@@ -415,6 +482,8 @@ func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 		ver, err = rollingbackAddIndex(w, d, t, job, true)
 	case model.ActionAddTablePartition:
 		ver, err = rollingbackAddTablePartition(t, job)
+	case model.ActionAddCheckConstraint:
+		ver, err = rollingbackAddCheckConstraint(t, job)
 	case model.ActionDropColumn:
 		ver, err = rollingbackDropColumn(t, job)
 	case model.ActionDropColumns:
@@ -433,12 +502,14 @@ func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 		ver, err = rollingbackTruncateTable(t, job)
 	case model.ActionModifyColumn:
 		ver, err = rollingbackModifyColumn(w, d, t, job)
+	case ActionReorganizePartition:
+		ver, err = rollingbackReorganizePartition(w, d, t, job)
 	case model.ActionRebaseAutoID, model.ActionShardRowID, model.ActionAddForeignKey,
 		model.ActionDropForeignKey, model.ActionRenameTable, model.ActionRenameTables,
 		model.ActionModifyTableCharsetAndCollate, model.ActionTruncateTablePartition,
 		model.ActionModifySchemaCharsetAndCollate, model.ActionRepairTable,
 		model.ActionModifyTableAutoIdCache, model.ActionAlterIndexVisibility,
-		model.ActionExchangeTablePartition:
+		model.ActionExchangeTablePartition, model.ActionDropCheckConstraint:
 		ver, err = cancelOnlyNotHandledJob(job)
 	default:
 		job.State = model.JobStateCancelled
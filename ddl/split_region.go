@@ -15,12 +15,19 @@ package ddl
 
 import (
 	"context"
+	"math"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/opcode"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/generatedexpr"
 	"github.com/pingcap/tidb/util/logutil"
 	tikverr "github.com/tikv/client-go/v2/error"
 	"go.uber.org/zap"
@@ -101,7 +108,7 @@ func preSplitPhysicalTableByShardRowID(ctx context.Context, store kv.SplittableS
 		logutil.BgLogger().Warn("[ddl] pre split some table regions failed",
 			zap.Stringer("table", tbInfo.Name), zap.Int("successful region count", len(regionIDs)), zap.Error(err))
 	}
-	regionIDs = append(regionIDs, splitIndexRegion(store, tbInfo, scatter)...)
+	regionIDs = append(regionIDs, splitIndexRegion(store, tbInfo, physicalID, scatter)...)
 	return regionIDs
 }
 
@@ -118,11 +125,22 @@ func splitRecordRegion(ctx context.Context, store kv.SplittableStore, tableID in
 	return 0
 }
 
-func splitIndexRegion(store kv.SplittableStore, tblInfo *model.TableInfo, scatter bool) []uint64 {
+func splitIndexRegion(store kv.SplittableStore, tblInfo *model.TableInfo, physicalID int64, scatter bool) []uint64 {
 	splitKeys := make([][]byte, 0, len(tblInfo.Indices))
 	for _, idx := range tblInfo.Indices {
-		indexPrefix := tablecodec.EncodeTableIndexPrefix(tblInfo.ID, idx.ID)
+		indexID := physicalID
+		if idx.Global {
+			indexID = tblInfo.ID
+		}
+		indexPrefix := tablecodec.EncodeTableIndexPrefix(indexID, idx.ID)
 		splitKeys = append(splitKeys, indexPrefix)
+		for _, val := range preSplitIndexValues(tblInfo, idx) {
+			encoded, err := codec.EncodeKey(nil, nil, val)
+			if err != nil {
+				continue
+			}
+			splitKeys = append(splitKeys, tablecodec.EncodeIndexSeekKey(indexID, idx.ID, encoded))
+		}
 	}
 	regionIDs, err := store.SplitRegions(context.Background(), splitKeys, scatter, &tblInfo.ID)
 	if err != nil {
@@ -132,6 +150,208 @@ func splitIndexRegion(store kv.SplittableStore, tblInfo *model.TableInfo, scatte
 	return regionIDs
 }
 
+// preSplitIndexValues returns the values to pre-split the index into 2^PreSplitRegions regions, so the bulk load
+// after the table is created doesn't hotspot on the index regions. The values are evenly distributed in the declared
+// value domain of the first column of the index, i.e. the range of an integer column declared by the CHECK
+// constraints (see newIntDomain) or the elements of an enum column. It returns nil if the first column doesn't have such a domain.
+func preSplitIndexValues(tblInfo *model.TableInfo, idx *model.IndexInfo) []types.Datum {
+	col := tblInfo.Columns[idx.Columns[0].Offset]
+	regions := uint64(1) << tblInfo.PreSplitRegions
+	switch col.Tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+		d := newIntDomain(tblInfo, col)
+		if !d.bounded() {
+			return nil
+		}
+		width := d.upper - d.lower
+		step := width/regions + 1
+		vals := make([]types.Datum, 0, regions-1)
+		for i := uint64(1); i < regions; i++ {
+			offset := step * i
+			if offset/step != i || offset > width {
+				break
+			}
+			vals = append(vals, d.datum(d.lower+offset))
+		}
+		return vals
+	case mysql.TypeEnum:
+		// The enum values are stored as the 1-based indexes of the elements.
+		elems := uint64(len(col.Elems))
+		vals := make([]types.Datum, 0, regions-1)
+		var last uint64
+		for i := uint64(1); i < regions; i++ {
+			v := i*elems/regions + 1
+			if v > last && v <= elems {
+				vals = append(vals, types.NewUintDatum(v))
+				last = v
+			}
+		}
+		return vals
+	}
+	return nil
+}
+
+// intDomain is the value domain of an integer column. The bounds are inclusive and encoded as order-preserving
+// uint64 keys, so the signed and unsigned columns are split in the same way.
+type intDomain struct {
+	unsigned     bool
+	lower, upper uint64
+	// hasLower and hasUpper are set if the bound is declared.
+	hasLower, hasUpper bool
+}
+
+// newIntDomain returns the value domain of the integer column. Both bounds are declared for TINYINT and SMALLINT,
+// whose type range is small enough to be the domain, and the lower bound is declared for the unsigned types. The
+// other bounds are taken from the comparisons of the column with integer constants in the enforced CHECK
+// constraints, e.g. `check (a between 1 and 1000000)`.
+func newIntDomain(tblInfo *model.TableInfo, col *model.ColumnInfo) *intDomain {
+	d := &intDomain{unsigned: mysql.HasUnsignedFlag(col.Flag)}
+	if d.unsigned {
+		d.lower, d.upper = 0, types.IntergerUnsignedUpperBound(col.Tp)
+	} else {
+		d.lower, d.upper = d.signedKey(types.IntergerSignedLowerBound(col.Tp)), d.signedKey(types.IntergerSignedUpperBound(col.Tp))
+	}
+	smallType := col.Tp == mysql.TypeTiny || col.Tp == mysql.TypeShort
+	d.hasLower, d.hasUpper = d.unsigned || smallType, smallType
+	for _, constr := range tblInfo.Constraints {
+		if constr.State != model.StatePublic || !constr.Enforced {
+			continue
+		}
+		expr, err := generatedexpr.ParseExpression(constr.ExprString)
+		if err != nil {
+			continue
+		}
+		d.restrict(expr, col.Name)
+	}
+	return d
+}
+
+func (d *intDomain) bounded() bool {
+	return d.hasLower && d.hasUpper && d.lower < d.upper
+}
+
+func (d *intDomain) signedKey(v int64) uint64 {
+	return uint64(v) ^ (1 << 63)
+}
+
+func (d *intDomain) datum(key uint64) types.Datum {
+	if d.unsigned {
+		return types.NewUintDatum(key)
+	}
+	return types.NewIntDatum(int64(key ^ (1 << 63)))
+}
+
+// key returns the key of the integer constant, ok is false if it's not an integer in the range of the column.
+func (d *intDomain) key(expr ast.ExprNode) (key uint64, ok bool) {
+	neg := false
+	if unary, isUnary := expr.(*ast.UnaryOperationExpr); isUnary && unary.Op == opcode.Minus {
+		neg, expr = true, unary.V
+	}
+	val, isVal := expr.(ast.ValueExpr)
+	if !isVal {
+		return 0, false
+	}
+	switch v := val.GetValue().(type) {
+	case int64:
+		if neg {
+			v = -v
+		}
+		if d.unsigned {
+			return uint64(v), v >= 0
+		}
+		return d.signedKey(v), true
+	case uint64:
+		if neg || !d.unsigned {
+			return 0, false
+		}
+		return v, true
+	}
+	return 0, false
+}
+
+// restrictLower and restrictUpper narrow the domain, which starts as the range of the type.
+func (d *intDomain) restrictLower(key uint64) {
+	if key > d.lower {
+		d.lower = key
+	}
+	d.hasLower = true
+}
+
+func (d *intDomain) restrictUpper(key uint64) {
+	if key < d.upper {
+		d.upper = key
+	}
+	d.hasUpper = true
+}
+
+// restrict narrows the domain by the comparisons of the column with the constants in the expression, the other
+// conditions are ignored. Only the comparisons joined by AND are used, which all rows must satisfy.
+func (d *intDomain) restrict(expr ast.ExprNode, colName model.CIStr) {
+	isCol := func(e ast.ExprNode) bool {
+		c, ok := e.(*ast.ColumnNameExpr)
+		return ok && c.Name.Name.L == colName.L
+	}
+	switch x := expr.(type) {
+	case *ast.ParenthesesExpr:
+		d.restrict(x.Expr, colName)
+	case *ast.BetweenExpr:
+		if x.Not || !isCol(x.Expr) {
+			return
+		}
+		if key, ok := d.key(x.Left); ok {
+			d.restrictLower(key)
+		}
+		if key, ok := d.key(x.Right); ok {
+			d.restrictUpper(key)
+		}
+	case *ast.BinaryOperationExpr:
+		op, other := x.Op, x.R
+		switch {
+		case x.Op == opcode.LogicAnd:
+			d.restrict(x.L, colName)
+			d.restrict(x.R, colName)
+			return
+		case isCol(x.L):
+		case isCol(x.R):
+			// Make it `col op constant`.
+			other = x.L
+			switch op {
+			case opcode.LT:
+				op = opcode.GT
+			case opcode.LE:
+				op = opcode.GE
+			case opcode.GT:
+				op = opcode.LT
+			case opcode.GE:
+				op = opcode.LE
+			}
+		default:
+			return
+		}
+		key, ok := d.key(other)
+		if !ok {
+			return
+		}
+		switch op {
+		case opcode.EQ:
+			d.restrictLower(key)
+			d.restrictUpper(key)
+		case opcode.GE:
+			d.restrictLower(key)
+		case opcode.GT:
+			if key < math.MaxUint64 {
+				d.restrictLower(key + 1)
+			}
+		case opcode.LE:
+			d.restrictUpper(key)
+		case opcode.LT:
+			if key > 0 {
+				d.restrictUpper(key - 1)
+			}
+		}
+	}
+}
+
 func waitScatterRegionFinish(ctx context.Context, store kv.SplittableStore, regionIDs ...uint64) {
 	for _, regionID := range regionIDs {
 		err := store.WaitScatterRegionFinish(ctx, regionID, 0)
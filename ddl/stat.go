@@ -71,7 +71,7 @@ func (d *ddl) Stats(vars *variable.SessionVars) (map[string]interface{}, error)
 	// TODO: Add all job information if needed.
 	job := ddlInfo.Jobs[0]
 	m[ddlJobID] = job.ID
-	m[ddlJobAction] = job.Type.String()
+	m[ddlJobAction] = JobTypeString(job.Type)
 	m[ddlJobStartTS] = job.StartTS / 1e9 // unit: second
 	m[ddlJobState] = job.State.String()
 	m[ddlJobRows] = job.RowCount
@@ -271,14 +271,58 @@ func (s *schemaVersionSyncer) UpdateSelfVersion(ctx context.Context, version int
 // OwnerUpdateGlobalVersion implements SchemaSyncer.OwnerUpdateGlobalVersion interface.
 func (s *schemaVersionSyncer) OwnerUpdateGlobalVersion(ctx context.Context, version int64) error {
 	startTime := time.Now()
-	ver := strconv.FormatInt(version, 10)
-	// TODO: If the version is larger than the original global version, we need set the version.
-	// Otherwise, we'd better set the original global version.
-	err := PutKVToEtcd(ctx, s.etcdCli, putKeyRetryUnlimited, DDLGlobalSchemaVersion, ver)
+	err := s.casGlobalVersion(ctx, version)
 	metrics.OwnerHandleSyncerHistogram.WithLabelValues(metrics.OwnerUpdateGlobalVersion, metrics.RetLabel(err)).Observe(time.Since(startTime).Seconds())
 	return errors.Trace(err)
 }
 
+// casGlobalVersion sets the global version to the version if it's larger than the stored one. The general DDL
+// jobs may be run concurrently, so a slower worker mustn't overwrite a newer version with its older one. It
+// retries until the version is set, the stored version isn't smaller, or the ctx is done.
+func (s *schemaVersionSyncer) casGlobalVersion(ctx context.Context, version int64) error {
+	ver := strconv.FormatInt(version, 10)
+	var err error
+	for i := 0; ; i++ {
+		if isContextDone(ctx) {
+			return errors.Trace(ctx.Err())
+		}
+		var done bool
+		done, err = s.tryCASGlobalVersion(ctx, version, ver)
+		if err == nil && done {
+			return nil
+		}
+		if err != nil {
+			logutil.BgLogger().Warn("[ddl] etcd-cli put global version failed", zap.String("value", ver), zap.Error(err), zap.Int("retryCnt", i))
+		}
+		time.Sleep(keyOpRetryInterval)
+	}
+}
+
+// tryCASGlobalVersion tries to set the global version once. It returns true if the version is set or the stored
+// version is already not smaller than it, and false if the stored version is changed concurrently.
+func (s *schemaVersionSyncer) tryCASGlobalVersion(ctx context.Context, version int64, ver string) (bool, error) {
+	childCtx, cancel := context.WithTimeout(ctx, keyOpDefaultTimeout)
+	defer cancel()
+	resp, err := s.etcdCli.Get(childCtx, DDLGlobalSchemaVersion)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	cmp := clientv3.Compare(clientv3.CreateRevision(DDLGlobalSchemaVersion), "=", 0)
+	if len(resp.Kvs) > 0 {
+		kv := resp.Kvs[0]
+		storedVer, err := strconv.ParseInt(string(kv.Value), 10, 64)
+		if err == nil && storedVer >= version {
+			return true, nil
+		}
+		cmp = clientv3.Compare(clientv3.ModRevision(DDLGlobalSchemaVersion), "=", kv.ModRevision)
+	}
+	txnResp, err := s.etcdCli.Txn(childCtx).If(cmp).Then(clientv3.OpPut(DDLGlobalSchemaVersion, ver)).Commit()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return txnResp.Succeeded, nil
+}
+
 // removeSelfVersionPath remove the self path from etcd.
 func (s *schemaVersionSyncer) removeSelfVersionPath() error {
 	startTime := time.Now()
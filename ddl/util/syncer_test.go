@@ -169,6 +169,17 @@ func TestSyncerSimple(t *testing.T) {
 		t.Fatalf(checkErr)
 	}
 
+	// The global version doesn't go backwards.
+	err = d.SchemaSyncer().OwnerUpdateGlobalVersion(ctx, currentVer-1)
+	if err != nil {
+		t.Fatalf("update latest schema version failed %v", err)
+	}
+	resp, err = cli.Get(ctx, DDLGlobalSchemaVersion)
+	if err != nil {
+		t.Fatalf("client get global version failed %v", err)
+	}
+	checkRespKV(t, 1, DDLGlobalSchemaVersion, fmt.Sprintf("%v", currentVer), resp.Kvs...)
+
 	// for CheckAllVersions
 	childCtx, cancel := goctx.WithTimeout(ctx, 200*time.Millisecond)
 	err = d.SchemaSyncer().OwnerCheckAllVersions(childCtx, currentVer)
@@ -15,6 +15,7 @@ package distsql
 
 import (
 	"bytes"
+	"container/heap"
 	"context"
 	"fmt"
 	"sort"
@@ -59,6 +60,7 @@ var (
 	_ SelectResult = (*selectResult)(nil)
 	_ SelectResult = (*streamResult)(nil)
 	_ SelectResult = (*serialSelectResults)(nil)
+	_ SelectResult = (*sortedSelectResults)(nil)
 )
 
 // SelectResult is an iterator of coprocessor partial results.
@@ -121,6 +123,130 @@ func (ssr *serialSelectResults) Close() (err error) {
 	return
 }
 
+// NewSortedSelectResults creates a SelectResult which merges the SelectResults whose rows are sorted by the columns
+// of keyOffsets in the same direction, the rows are returned in the same order.
+func NewSortedSelectResults(selectResults []SelectResult, fieldTypes []*types.FieldType, keyOffsets []int, desc bool) SelectResult {
+	keyCmpFuncs := make([]chunk.CompareFunc, 0, len(keyOffsets))
+	for _, offset := range keyOffsets {
+		keyCmpFuncs = append(keyCmpFuncs, chunk.GetCompareFunc(fieldTypes[offset]))
+	}
+	return &sortedSelectResults{
+		selectResults: selectResults,
+		fieldTypes:    fieldTypes,
+		keyOffsets:    keyOffsets,
+		keyCmpFuncs:   keyCmpFuncs,
+		desc:          desc,
+	}
+}
+
+// sortedSelectResults merges the sorted SelectResults by a heap of their current rows.
+type sortedSelectResults struct {
+	selectResults []SelectResult
+	fieldTypes    []*types.FieldType
+	keyOffsets    []int
+	keyCmpFuncs   []chunk.CompareFunc
+	desc          bool
+
+	initialized bool
+	chks        []*chunk.Chunk
+	rowIdxs     []int
+	// heap is the indices of the SelectResults which have remaining rows.
+	heap []int
+}
+
+func (ssr *sortedSelectResults) Len() int {
+	return len(ssr.heap)
+}
+
+func (ssr *sortedSelectResults) Less(i, j int) bool {
+	l, r := ssr.heap[i], ssr.heap[j]
+	lRow, rRow := ssr.chks[l].GetRow(ssr.rowIdxs[l]), ssr.chks[r].GetRow(ssr.rowIdxs[r])
+	for k, offset := range ssr.keyOffsets {
+		cmp := ssr.keyCmpFuncs[k](lRow, offset, rRow, offset)
+		if cmp != 0 {
+			return (cmp < 0) != ssr.desc
+		}
+	}
+	// Keep the rows of the same keys in the order of the SelectResults.
+	return l < r
+}
+
+func (ssr *sortedSelectResults) Swap(i, j int) {
+	ssr.heap[i], ssr.heap[j] = ssr.heap[j], ssr.heap[i]
+}
+
+func (ssr *sortedSelectResults) Push(x interface{}) {
+	ssr.heap = append(ssr.heap, x.(int))
+}
+
+func (ssr *sortedSelectResults) Pop() interface{} {
+	x := ssr.heap[len(ssr.heap)-1]
+	ssr.heap = ssr.heap[:len(ssr.heap)-1]
+	return x
+}
+
+// fetch reads the next chunk of the i-th SelectResult, it returns false if the SelectResult is drained.
+func (ssr *sortedSelectResults) fetch(ctx context.Context, i int) (bool, error) {
+	if err := ssr.selectResults[i].Next(ctx, ssr.chks[i]); err != nil {
+		return false, err
+	}
+	ssr.rowIdxs[i] = 0
+	return ssr.chks[i].NumRows() > 0, nil
+}
+
+func (ssr *sortedSelectResults) NextRaw(context.Context) ([]byte, error) {
+	return nil, errors.New("sortedSelectResults doesn't support NextRaw")
+}
+
+func (ssr *sortedSelectResults) Next(ctx context.Context, chk *chunk.Chunk) error {
+	chk.Reset()
+	if !ssr.initialized {
+		ssr.initialized = true
+		ssr.chks = make([]*chunk.Chunk, len(ssr.selectResults))
+		ssr.rowIdxs = make([]int, len(ssr.selectResults))
+		ssr.heap = make([]int, 0, len(ssr.selectResults))
+		for i := range ssr.selectResults {
+			ssr.chks[i] = chunk.NewChunkWithCapacity(ssr.fieldTypes, chk.Capacity())
+			hasRows, err := ssr.fetch(ctx, i)
+			if err != nil {
+				return err
+			}
+			if hasRows {
+				ssr.heap = append(ssr.heap, i)
+			}
+		}
+		heap.Init(ssr)
+	}
+	for !chk.IsFull() && len(ssr.heap) > 0 {
+		i := ssr.heap[0]
+		chk.AppendRow(ssr.chks[i].GetRow(ssr.rowIdxs[i]))
+		ssr.rowIdxs[i]++
+		if ssr.rowIdxs[i] < ssr.chks[i].NumRows() {
+			heap.Fix(ssr, 0)
+			continue
+		}
+		hasRows, err := ssr.fetch(ctx, i)
+		if err != nil {
+			return err
+		}
+		if hasRows {
+			heap.Fix(ssr, 0)
+		} else {
+			heap.Pop(ssr)
+		}
+	}
+	return nil
+}
+
+func (ssr *sortedSelectResults) Close() (err error) {
+	for _, r := range ssr.selectResults {
+		if rerr := r.Close(); rerr != nil {
+			err = rerr
+		}
+	}
+	return
+}
+
 type selectResult struct {
 	label string
 	resp  kv.Response
@@ -479,6 +605,11 @@ func (s *selectResultRuntimeStats) mergeCopRuntimeStats(copStats *copr.CopRuntim
 	}
 }
 
+// GetProcessTime returns the processing time of the coprocessor tasks reported by the storage.
+func (s *selectResultRuntimeStats) GetProcessTime() time.Duration {
+	return s.totalProcessTime
+}
+
 func (s *selectResultRuntimeStats) Clone() execdetails.RuntimeStats {
 	newRs := selectResultRuntimeStats{
 		copRespTime:  make([]time.Duration, 0, len(s.copRespTime)),
@@ -17,9 +17,12 @@ import (
 	"context"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/store/copr"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tipb/go-tipb"
@@ -50,3 +53,62 @@ func (s *testSuite) TestUpdateCopRuntimeStats(c *C) {
 	sr.updateCopRuntimeStats(context.Background(), &copr.CopRuntimeStats{ExecDetails: execdetails.ExecDetails{CalleeAddress: "callee"}}, 0)
 	c.Assert(ctx.GetSessionVars().StmtCtx.RuntimeStatsColl.GetOrCreateCopStats(1234, "tikv").String(), Equals, "tikv_task:{time:1ns, loops:1}")
 }
+
+type mockSortedSelectResult struct {
+	SelectResult
+	chks []*chunk.Chunk
+}
+
+func (r *mockSortedSelectResult) Next(_ context.Context, chk *chunk.Chunk) error {
+	chk.Reset()
+	if len(r.chks) > 0 {
+		chk.Append(r.chks[0], 0, r.chks[0].NumRows())
+		r.chks = r.chks[1:]
+	}
+	return nil
+}
+
+func (r *mockSortedSelectResult) Close() error {
+	return nil
+}
+
+func (s *testSuite) TestSortedSelectResults(c *C) {
+	fieldTypes := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong), types.NewFieldType(mysql.TypeLonglong)}
+	newChunk := func(rows ...[2]int64) *chunk.Chunk {
+		chk := chunk.NewChunkWithCapacity(fieldTypes, len(rows))
+		for _, row := range rows {
+			chk.AppendInt64(0, row[0])
+			chk.AppendInt64(1, row[1])
+		}
+		return chk
+	}
+	check := func(result SelectResult, expected [][2]int64) {
+		var rows [][2]int64
+		chk := chunk.NewChunkWithCapacity(fieldTypes, 2)
+		for {
+			c.Assert(result.Next(context.Background(), chk), IsNil)
+			if chk.NumRows() == 0 {
+				break
+			}
+			for i := 0; i < chk.NumRows(); i++ {
+				rows = append(rows, [2]int64{chk.GetRow(i).GetInt64(0), chk.GetRow(i).GetInt64(1)})
+			}
+		}
+		c.Assert(rows, DeepEquals, expected)
+		c.Assert(result.Close(), IsNil)
+	}
+
+	// The rows of each result are sorted by the second column.
+	result := NewSortedSelectResults([]SelectResult{
+		&mockSortedSelectResult{chks: []*chunk.Chunk{newChunk([2]int64{1, 1}, [2]int64{1, 4}), newChunk([2]int64{1, 6})}},
+		&mockSortedSelectResult{},
+		&mockSortedSelectResult{chks: []*chunk.Chunk{newChunk([2]int64{2, 2}, [2]int64{2, 4}, [2]int64{2, 5})}},
+	}, fieldTypes, []int{1}, false)
+	check(result, [][2]int64{{1, 1}, {2, 2}, {1, 4}, {2, 4}, {2, 5}, {1, 6}})
+
+	result = NewSortedSelectResults([]SelectResult{
+		&mockSortedSelectResult{chks: []*chunk.Chunk{newChunk([2]int64{1, 6}, [2]int64{1, 1})}},
+		&mockSortedSelectResult{chks: []*chunk.Chunk{newChunk([2]int64{2, 5}), newChunk([2]int64{2, 2})}},
+	}, fieldTypes, []int{1}, true)
+	check(result, [][2]int64{{1, 6}, {2, 5}, {2, 2}, {1, 1}})
+}
@@ -0,0 +1,340 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/coprocessor"
+	"github.com/pingcap/kvproto/pkg/tikvpb"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/domain/infosync"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	centralAutoIDMaxRetry       = 2
+	centralAutoIDRetryInterval  = 200 * time.Millisecond
+	centralAutoIDCheckInterval  = time.Second
+	centralAutoIDForwardTimeout = time.Second
+	// centralAutoIDUnreachableBackoff is the time the requests aren't forwarded after the owner is unreachable, the
+	// IDs are allocated from the storage by the server itself meanwhile.
+	centralAutoIDUnreachableBackoff = 10 * time.Second
+	centralAutoIDTokenLen           = 32
+)
+
+// errCentralAutoIDUnauthorized is returned if the request of the centralized auto ID allocator doesn't carry the token
+// of the cluster.
+var errCentralAutoIDUnauthorized = errors.New("the request of the centralized auto ID allocator is unauthorized")
+
+// CentralAutoIDService returns the centralized auto ID allocator service, it's only available on the DDL owner.
+func (do *Domain) CentralAutoIDService() *autoid.CentralService {
+	return do.centralAutoIDService
+}
+
+// CentralAutoIDToken returns the token shared by the servers of the cluster, it's generated by the first server which
+// needs it and persisted in the meta of the storage.
+func (do *Domain) CentralAutoIDToken(ctx context.Context) (string, error) {
+	do.centralAutoIDTokenMu.Lock()
+	defer do.centralAutoIDTokenMu.Unlock()
+	if len(do.centralAutoIDToken) > 0 {
+		return do.centralAutoIDToken, nil
+	}
+	var token []byte
+	err := kv.RunInNewTxn(ctx, do.store, true, func(ctx context.Context, txn kv.Transaction) error {
+		m := meta.NewMeta(txn)
+		var err error
+		token, err = m.GetCentralAutoIDToken()
+		if err != nil || len(token) > 0 {
+			return err
+		}
+		raw := make([]byte, centralAutoIDTokenLen)
+		if _, err = rand.Read(raw); err != nil {
+			return errors.Trace(err)
+		}
+		token = []byte(hex.EncodeToString(raw))
+		return m.SetCentralAutoIDToken(token)
+	})
+	if err != nil {
+		return "", err
+	}
+	do.centralAutoIDToken = string(token)
+	return do.centralAutoIDToken, nil
+}
+
+// HandleCentralAutoIDBatch handles the batch of the requests forwarded by the other servers through the RPC server.
+// The batch is rejected unless it carries the token of the cluster and the server is still the owner it's sent to.
+// Rebasing isn't accepted, the other servers rebase the storage and let the service drop its lease instead.
+func (do *Domain) HandleCentralAutoIDBatch(ctx context.Context, batch *autoid.CentralBatchRequest) (*autoid.CentralBatchResponse, error) {
+	if !config.GetGlobalConfig().Experimental.EnableCentralAutoID {
+		return nil, errors.New("the centralized auto ID allocator is disabled")
+	}
+	expected, err := do.CentralAutoIDToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(batch.Token), []byte(expected)) != 1 {
+		return nil, errCentralAutoIDUnauthorized
+	}
+	ownerManager := do.ddl.OwnerManager()
+	if batch.OwnerID != ownerManager.ID() || !ownerManager.IsOwner() {
+		return nil, errors.New("the server is not the DDL owner")
+	}
+	// The owner key is checked once per batch to turn the requests away early. It doesn't fence anything, a server
+	// that has lost the owner lease may still pass it, the allocations are fenced by the service against the end of
+	// the IDs persisted in the storage.
+	ownerID, err := ownerManager.GetOwnerID(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if ownerID != ownerManager.ID() {
+		do.centralAutoIDService.Reset()
+		return nil, errors.New("the server is not the DDL owner")
+	}
+	resp := &autoid.CentralBatchResponse{Responses: make([]*autoid.CentralResponse, 0, len(batch.Requests))}
+	for _, req := range batch.Requests {
+		var r *autoid.CentralResponse
+		if req.Op == autoid.CentralOpRebase {
+			r = &autoid.CentralResponse{Error: "the centralized auto ID allocator doesn't accept rebasing remotely"}
+		} else if r, err = do.centralAutoIDService.Handle(ctx, req); err != nil {
+			r = &autoid.CentralResponse{Error: err.Error()}
+		}
+		resp.Responses = append(resp.Responses, r)
+	}
+	return resp, nil
+}
+
+// centralAutoIDClient sends the requests of the centralized auto ID allocator to the DDL owner. The requests are
+// handled locally if the current server is the owner, otherwise they're forwarded to the RPC server of the owner,
+// which is secured by the cluster TLS like the other RPCs between the servers. The concurrent requests are forwarded
+// in batches, a batch is sent while the previous one is in flight.
+type centralAutoIDClient struct {
+	do *Domain
+
+	mu      sync.Mutex
+	pending *centralAutoIDBatch
+	sending bool
+	// unreachableUntil is the time until which the requests fail with autoid.ErrCentralUnreachable without being sent.
+	unreachableUntil time.Time
+	// conn is the connection to the RPC server at addr.
+	conn *grpc.ClientConn
+	addr string
+}
+
+type centralAutoIDBatch struct {
+	reqs  []*autoid.CentralRequest
+	resps []*autoid.CentralResponse
+	err   error
+	done  chan struct{}
+}
+
+// Call implements autoid.CentralClient Call interface.
+func (c *centralAutoIDClient) Call(ctx context.Context, req *autoid.CentralRequest) (*autoid.CentralResponse, error) {
+	if c.do.ddl.OwnerManager().IsOwner() {
+		return c.do.centralAutoIDService.Handle(ctx, req)
+	}
+	// The leases must not be used if the server becomes the owner again, another owner may have allocated IDs.
+	c.do.centralAutoIDService.Reset()
+	if req.Op == autoid.CentralOpRebase {
+		if err := autoid.RebaseCentralStorage(c.do.store, req); err != nil {
+			return nil, err
+		}
+		dropReq := *req
+		dropReq.Op = autoid.CentralOpDropLease
+		req = &dropReq
+	}
+
+	c.mu.Lock()
+	if time.Now().Before(c.unreachableUntil) {
+		c.mu.Unlock()
+		return nil, errors.Annotate(autoid.ErrCentralUnreachable, "the DDL owner was unreachable recently")
+	}
+	if c.pending == nil {
+		c.pending = &centralAutoIDBatch{done: make(chan struct{})}
+	}
+	batch, idx := c.pending, len(c.pending.reqs)
+	batch.reqs = append(batch.reqs, req)
+	leader := !c.sending
+	c.sending = true
+	c.mu.Unlock()
+	if leader {
+		c.sendBatches()
+	}
+
+	select {
+	case <-batch.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if batch.err != nil {
+		return nil, batch.err
+	}
+	resp := batch.resps[idx]
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return resp, nil
+}
+
+// sendBatches sends the pending batches until there is none.
+func (c *centralAutoIDClient) sendBatches() {
+	for {
+		c.mu.Lock()
+		batch := c.pending
+		c.pending = nil
+		if batch == nil {
+			c.sending = false
+			c.mu.Unlock()
+			return
+		}
+		c.mu.Unlock()
+		batch.resps, batch.err = c.forwardWithRetry(batch.reqs)
+		close(batch.done)
+	}
+}
+
+// forwardWithRetry forwards the requests to the owner. If it fails, the owner is regarded as unreachable for
+// centralAutoIDUnreachableBackoff, and the error is caused by autoid.ErrCentralUnreachable.
+func (c *centralAutoIDClient) forwardWithRetry(reqs []*autoid.CentralRequest) ([]*autoid.CentralResponse, error) {
+	var err error
+	for i := 0; i < centralAutoIDMaxRetry; i++ {
+		if i > 0 {
+			time.Sleep(centralAutoIDRetryInterval)
+		}
+		var resps []*autoid.CentralResponse
+		if resps, err = c.forward(reqs); err == nil {
+			return resps, nil
+		}
+		logutil.BgLogger().Warn("forward the requests to the centralized auto ID allocator failed",
+			zap.Int("requests", len(reqs)), zap.Int("retry", i), zap.Error(err))
+	}
+	c.mu.Lock()
+	c.unreachableUntil = time.Now().Add(centralAutoIDUnreachableBackoff)
+	c.mu.Unlock()
+	return nil, errors.Annotate(autoid.ErrCentralUnreachable, err.Error())
+}
+
+// getConn returns the connection to the RPC server at addr, the connection to the previous owner is closed.
+func (c *centralAutoIDClient) getConn(addr string) (*grpc.ClientConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil && c.addr == addr {
+		return c.conn, nil
+	}
+	opt := grpc.WithInsecure()
+	security := config.GetGlobalConfig().Security
+	if len(security.ClusterSSLCA) != 0 {
+		clusterSecurity := security.ClusterSecurity()
+		tlsConfig, err := clusterSecurity.ToTLSConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		opt = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+	conn, err := grpc.Dial(addr, opt)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if c.conn != nil {
+		terror.Log(c.conn.Close())
+	}
+	c.conn, c.addr = conn, addr
+	return conn, nil
+}
+
+func (c *centralAutoIDClient) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		terror.Log(c.conn.Close())
+		c.conn = nil
+	}
+}
+
+// forward sends the requests to the RPC server of the DDL owner.
+func (c *centralAutoIDClient) forward(reqs []*autoid.CentralRequest) ([]*autoid.CentralResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), centralAutoIDForwardTimeout)
+	defer cancel()
+	token, err := c.do.CentralAutoIDToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ownerID, err := c.do.ddl.OwnerManager().GetOwnerID(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	info, err := infosync.GetServerInfoByID(ctx, ownerID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data, err := json.Marshal(&autoid.CentralBatchRequest{Token: token, OwnerID: ownerID, Requests: reqs})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	addr := net.JoinHostPort(info.IP, strconv.FormatUint(uint64(info.StatusPort), 10))
+	conn, err := c.getConn(addr)
+	if err != nil {
+		return nil, err
+	}
+	copResp, err := tikvpb.NewTikvClient(conn).Coprocessor(ctx, &coprocessor.Request{Tp: kv.ReqTypeCentralAutoID, Data: data})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if copResp.OtherError != "" {
+		return nil, errors.Errorf("the centralized auto ID allocator on %s returns error: %s", addr, copResp.OtherError)
+	}
+	resp := &autoid.CentralBatchResponse{}
+	if err = json.Unmarshal(copResp.Data, resp); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(resp.Responses) != len(reqs) {
+		return nil, errors.Errorf("the centralized auto ID allocator on %s returns %d responses for %d requests",
+			addr, len(resp.Responses), len(reqs))
+	}
+	return resp.Responses, nil
+}
+
+// centralAutoIDKeeper drops the leases of the centralized auto ID allocator once the server is no longer the DDL
+// owner.
+func (do *Domain) centralAutoIDKeeper() {
+	defer do.wg.Done()
+	ticker := time.NewTicker(centralAutoIDCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if !do.ddl.OwnerManager().IsOwner() {
+				do.centralAutoIDService.Reset()
+			}
+		case <-do.exit:
+			return
+		}
+	}
+}
@@ -41,10 +41,12 @@ import (
 	"github.com/pingcap/tidb/infoschema/perfschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/owner"
 	"github.com/pingcap/tidb/privilege/privileges"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics/handle"
 	"github.com/pingcap/tidb/telemetry"
@@ -85,6 +87,17 @@ type Domain struct {
 	statsUpdating        sync2.AtomicInt32
 	cancel               context.CancelFunc
 	indexUsageSyncLease  time.Duration
+	// tableAccess maps the accessed table IDs to their last access time, it's used to warm up the region cache.
+	tableAccess sync.Map
+	// tableAccessCount is the number of the tables in tableAccess, the map is pruned when it grows too large.
+	tableAccessCount sync2.AtomicInt64
+	tableAccessMu    sync.Mutex
+	// centralAutoIDService allocates the IDs of the tables with AUTO_ID_CACHE 1 when the server is the DDL owner.
+	centralAutoIDService *autoid.CentralService
+	centralAutoIDClient  *centralAutoIDClient
+	// centralAutoIDToken caches the token of the centralized auto ID allocator service, see CentralAutoIDToken.
+	centralAutoIDToken   string
+	centralAutoIDTokenMu sync.Mutex
 
 	serverID             uint64
 	serverIDSession      *concurrency.Session
@@ -265,7 +278,7 @@ func (do *Domain) tryLoadSchemaDiffs(m *meta.Meta, usedVersion, newVersion int64
 		}
 		phyTblIDs = append(phyTblIDs, IDs...)
 		for i := 0; i < len(IDs); i++ {
-			actions = append(actions, uint64(1<<diff.Type))
+			actions = append(actions, uint64(1)<<diff.Type)
 		}
 	}
 	is := builder.Build()
@@ -618,6 +631,11 @@ func (do *Domain) Close() {
 		do.info.RemoveMinStartTS()
 	}
 	close(do.exit)
+	autoid.UnregisterCentralClient(do.store)
+	if do.centralAutoIDClient != nil {
+		do.centralAutoIDClient.close()
+	}
+	do.persistRegionCacheSnapshot()
 	if do.etcdClient != nil {
 		terror.Log(errors.Trace(do.etcdClient.Close()))
 	}
@@ -717,6 +735,9 @@ func (do *Domain) Init(ddlLease time.Duration, sysFactory func(*Domain) (pools.R
 		ddl.WithHook(callback),
 		ddl.WithLease(ddlLease),
 	)
+	do.centralAutoIDService = autoid.NewCentralService(do.store)
+	do.centralAutoIDClient = &centralAutoIDClient{do: do}
+	autoid.RegisterCentralClient(do.store, do.centralAutoIDClient)
 	err = do.ddl.Start(sysCtxPool)
 	if err != nil {
 		return err
@@ -754,6 +775,8 @@ func (do *Domain) Init(ddlLease time.Duration, sysFactory func(*Domain) (pools.R
 		}
 	}
 
+	// The statement instance IDs are prefixed by the ID of this server, so they are unique across the cluster.
+	stmtctx.SetInstanceID(do.ddl.GetID())
 	do.info, err = infosync.GlobalInfoSyncerInit(ctx, do.ddl.GetID(), do.ServerID, do.etcdClient, skipRegisterToDashboard)
 	if err != nil {
 		return err
@@ -776,11 +799,19 @@ func (do *Domain) Init(ddlLease time.Duration, sysFactory func(*Domain) (pools.R
 	do.wg.Add(1)
 	go do.infoSyncerKeeper()
 
+	do.wg.Add(1)
+	go do.centralAutoIDKeeper()
+
 	if !skipRegisterToDashboard {
 		do.wg.Add(1)
 		go do.topologySyncerKeeper()
 	}
 
+	if config.GetGlobalConfig().Performance.RegionCacheWarmupTables > 0 {
+		do.wg.Add(1)
+		go do.warmUpRegionCacheLoop()
+	}
+
 	return nil
 }
 
@@ -892,10 +923,12 @@ func (do *Domain) LoadPrivilegeLoop(ctx sessionctx.Context) error {
 				if count > 10 {
 					time.Sleep(time.Duration(count) * time.Second)
 				}
-				continue
+				// The notifications may be lost while the watch channel is closed, so the privileges are loaded
+				// anyway instead of waiting for the next notification.
+			} else {
+				count = 0
 			}
 
-			count = 0
 			err := do.privHandle.Update(ctx)
 			metrics.LoadPrivilegeCounter.WithLabelValues(metrics.RetLabel(err)).Inc()
 			if err != nil {
@@ -1085,6 +1118,48 @@ func (do *Domain) TelemetryReportLoop(ctx sessionctx.Context) {
 	}()
 }
 
+// HealthReportLoop creates a goroutine that generates the health report of the cluster daily when
+// tidb_enable_health_report is on, it should be called only once in BootstrapSession. The owner checks every
+// HealthReportCheckInterval whether a report is due by the last report in mysql.health_report.
+func (do *Domain) HealthReportLoop(ctx sessionctx.Context) {
+	ctx.GetSessionVars().InRestrictedSQL = true
+	do.wg.Add(1)
+	go func() {
+		defer func() {
+			do.wg.Done()
+			logutil.BgLogger().Info("HealthReportLoop exited.")
+			util.Recover(metrics.LabelDomain, "HealthReportLoop", nil, false)
+		}()
+		owner := do.newOwnerManager(telemetry.HealthReportPrompt, telemetry.HealthReportOwnerKey)
+		for {
+			select {
+			case <-do.exit:
+				owner.Cancel()
+				return
+			case <-time.After(telemetry.HealthReportCheckInterval):
+				if !owner.IsOwner() {
+					continue
+				}
+				enabled, err := telemetry.IsHealthReportEnabled(ctx)
+				if err != nil || !enabled {
+					continue
+				}
+				due, err := telemetry.IsHealthReportDue(ctx, time.Now())
+				if err != nil {
+					logutil.BgLogger().Warn("HealthReportLoop check the last health report failed", zap.Error(err))
+					continue
+				}
+				if !due {
+					continue
+				}
+				if err = telemetry.RunHealthReport(ctx); err != nil {
+					logutil.BgLogger().Warn("HealthReportLoop generate health report failed", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
 // TelemetryRotateSubWindowLoop create a goroutine that rotates the telemetry window regularly.
 func (do *Domain) TelemetryRotateSubWindowLoop(ctx sessionctx.Context) {
 	ctx.GetSessionVars().InRestrictedSQL = true
@@ -1155,6 +1230,12 @@ func (do *Domain) UpdateTableStatsLoop(ctx sessionctx.Context) error {
 		go do.loadStatsWorker()
 	}
 	owner := do.newOwnerManager(handle.StatsPrompt, handle.StatsOwnerKey)
+	if do.etcdClient != nil {
+		do.ddl.OwnerManager().SetCampaignCheck(do.checkBackgroundCandidates)
+		owner.SetCampaignCheck(do.checkBackgroundCandidates)
+		do.wg.Add(1)
+		go do.serviceScopeWorker()
+	}
 	if do.indexUsageSyncLease > 0 {
 		do.wg.Add(1)
 		go do.syncIndexUsageWorker(owner)
@@ -1172,6 +1253,55 @@ func (do *Domain) UpdateTableStatsLoop(ctx sessionctx.Context) error {
 	return nil
 }
 
+const serviceScopeCheckInterval = 30 * time.Second
+
+// serviceScopeWorker stores tidb_service_scope of the server to etcd, so that the other servers know which servers
+// are of the background scope.
+func (do *Domain) serviceScopeWorker() {
+	defer util.Recover(metrics.LabelDomain, "serviceScopeWorker", nil, false)
+	ticker := time.NewTicker(serviceScopeCheckInterval)
+	defer func() {
+		ticker.Stop()
+		do.wg.Done()
+		logutil.BgLogger().Info("serviceScopeWorker exited.")
+	}()
+	for {
+		select {
+		case <-ticker.C:
+			if err := do.info.UpdateServiceScope(context.Background()); err != nil {
+				logutil.BgLogger().Warn("update the service scope failed", zap.Error(err))
+			}
+		case <-do.exit:
+			return
+		}
+	}
+}
+
+// checkBackgroundCandidates is the campaign check of the owners. The servers not of the background scope don't campaign
+// the owners while another candidate is of the background scope, and resign the owners if they are, so the background
+// work moves to the dedicated servers and stays there. They still campaign if no background server is campaigning.
+func (do *Domain) checkBackgroundCandidates(ctx context.Context, candidates []string) bool {
+	if variable.ServiceScope.Load() == variable.ServiceScopeBackground || len(candidates) == 0 {
+		return true
+	}
+	servers, err := infosync.GetAllServerInfo(ctx)
+	if err != nil {
+		logutil.BgLogger().Warn("get all server info failed", zap.Error(err))
+		return true
+	}
+	return !hasBackgroundCandidate(candidates, servers)
+}
+
+// hasBackgroundCandidate checks whether the candidates contain an alive server of the background scope.
+func hasBackgroundCandidate(candidates []string, servers map[string]*infosync.ServerInfo) bool {
+	for _, id := range candidates {
+		if info, ok := servers[id]; ok && info.ServiceScope == variable.ServiceScopeBackground {
+			return true
+		}
+	}
+	return false
+}
+
 func (do *Domain) newOwnerManager(prompt, ownerKey string) owner.Manager {
 	id := do.ddl.OwnerManager().ID()
 	var statsOwner owner.Manager
@@ -1349,18 +1479,14 @@ func (do *Domain) ExpensiveQueryHandle() *expensivequery.Handle {
 const (
 	privilegeKey   = "/tidb/privilege"
 	sysVarCacheKey = "/tidb/sysvars"
+
+	notifyUpdatePrivilegeRetryCnt = 3
 )
 
 // NotifyUpdatePrivilege updates privilege key in etcd, TiDB client that watches
 // the key will get notification.
 func (do *Domain) NotifyUpdatePrivilege(ctx sessionctx.Context) {
-	if do.etcdClient != nil {
-		row := do.etcdClient.KV
-		_, err := row.Put(context.Background(), privilegeKey, "")
-		if err != nil {
-			logutil.BgLogger().Warn("notify update privilege failed", zap.Error(err))
-		}
-	}
+	do.NotifyUpdatePrivilegeToCluster()
 	// update locally
 	exec := ctx.(sqlexec.RestrictedSQLExecutor)
 	if stmt, err := exec.ParseWithParams(context.Background(), `FLUSH PRIVILEGES`); err == nil {
@@ -1371,6 +1497,17 @@ func (do *Domain) NotifyUpdatePrivilege(ctx sessionctx.Context) {
 	}
 }
 
+// NotifyUpdatePrivilegeToCluster only updates privilege key in etcd, so all the TiDB servers reload the privileges.
+func (do *Domain) NotifyUpdatePrivilegeToCluster() {
+	if do.etcdClient == nil {
+		return
+	}
+	err := ddlutil.PutKVToEtcd(context.Background(), do.etcdClient, notifyUpdatePrivilegeRetryCnt, privilegeKey, "")
+	if err != nil {
+		logutil.BgLogger().Warn("notify update privilege failed", zap.Error(err))
+	}
+}
+
 // NotifyUpdateSysVarCache updates the sysvar cache key in etcd, which other TiDB
 // clients are subscribed to for updates. For the caller, the cache is also built
 // synchronously so that the effect is immediate.
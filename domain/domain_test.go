@@ -18,6 +18,7 @@ import (
 	"crypto/tls"
 	"math"
 	"net"
+	"path/filepath"
 	"runtime"
 	"testing"
 	"time"
@@ -29,6 +30,7 @@ import (
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/errno"
@@ -273,6 +275,31 @@ func (msm *mockSessionManager) ServerID() uint64 {
 	return 1
 }
 
+func TestNotifyUpdatePrivilegeToCluster(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("integration.NewClusterV3 will create file contains a colon which is not allowed on Windows")
+	}
+	if !unixSocketAvailable() {
+		return
+	}
+	clus := integration.NewClusterV3(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+	cli := clus.RandClient()
+	do := &Domain{etcdClient: cli}
+	watchCh := cli.Watch(context.Background(), privilegeKey)
+	for i := 0; i < 2; i++ {
+		do.NotifyUpdatePrivilegeToCluster()
+		select {
+		case resp := <-watchCh:
+			if len(resp.Events) != 1 {
+				t.Fatalf("expect 1 event, got %d", len(resp.Events))
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("the privilege key isn't updated")
+		}
+	}
+}
+
 func (*testSuite) TestT(c *C) {
 	defer testleak.AfterTest(c)()
 	store, err := mockstore.NewMockStore()
@@ -496,11 +523,95 @@ func (*testSuite) TestSessionPool(c *C) {
 	c.Assert(tr, IsNil)
 }
 
+func (*testSuite) TestRegionCacheWarmup(c *C) {
+	defer testleak.AfterTest(c)()
+	// The snapshot is persisted in a directory of this test, so it doesn't affect the other tests.
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Performance.RegionCacheWarmupTables = 4
+		conf.TempStoragePath = c.MkDir()
+	})
+	store, err := mockstore.NewMockStore()
+	c.Assert(err, IsNil)
+	defer func() {
+		err := store.Close()
+		c.Assert(err, IsNil)
+	}()
+	dom := NewDomain(store, 0, 0, 0, mockFactory)
+	err = dom.Init(0, sysMockFactory)
+	c.Assert(err, IsNil)
+	defer dom.Close()
+
+	ctx := mock.NewContext()
+	ctx.Store = dom.Store()
+	err = dom.DDL().CreateSchema(ctx, model.NewCIStr("warmup"), &ast.CharsetOpt{Chs: "utf8", Col: "utf8_bin"})
+	c.Assert(err, IsNil)
+	err = dom.DDL().CreateTable(ctx, &ast.CreateTableStmt{Table: &ast.TableName{
+		Schema: model.NewCIStr("warmup"),
+		Name:   model.NewCIStr("t")}})
+	c.Assert(err, IsNil)
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("warmup"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tblID := tbl.Meta().ID
+
+	// The recently accessed tables come first.
+	dom.RecordTableAccess([]int64{tblID - 1})
+	time.Sleep(time.Millisecond)
+	dom.RecordTableAccess([]int64{tblID})
+	time.Sleep(time.Millisecond)
+	dom.RecordTableAccess([]int64{tblID + 1})
+	// The access time isn't refreshed in a short interval.
+	dom.RecordTableAccess([]int64{tblID - 1})
+	c.Assert(dom.recentTables(10), DeepEquals, []int64{tblID + 1, tblID, tblID - 1})
+	c.Assert(dom.recentTables(1), DeepEquals, []int64{tblID + 1})
+
+	path := filepath.Join(c.MkDir(), "snapshot", "region_cache_snapshot.json")
+	tableIDs, err := loadRegionCacheSnapshot(path)
+	c.Assert(err, IsNil)
+	c.Assert(tableIDs, HasLen, 0)
+	c.Assert(saveRegionCacheSnapshot(path, dom.recentTables(10)), IsNil)
+	tableIDs, err = loadRegionCacheSnapshot(path)
+	c.Assert(err, IsNil)
+	c.Assert(tableIDs, DeepEquals, []int64{tblID + 1, tblID, tblID - 1})
+
+	// The tables which don't exist are skipped.
+	regions, err := dom.warmUpRegionCache([]int64{tblID + 1000})
+	c.Assert(err, IsNil)
+	c.Assert(regions, Equals, 0)
+	regions, err = dom.warmUpRegionCache(tableIDs)
+	c.Assert(err, IsNil)
+	c.Assert(regions, Equals, 1)
+
+	// The records are pruned to the recently accessed tables when they grow too large.
+	for i := int64(0); i < 5; i++ {
+		time.Sleep(time.Millisecond)
+		dom.RecordTableAccess([]int64{tblID + 100 + i})
+	}
+	c.Assert(dom.tableAccessCount.Get(), Equals, int64(8))
+	time.Sleep(time.Millisecond)
+	dom.RecordTableAccess([]int64{tblID + 105})
+	c.Assert(dom.tableAccessCount.Get(), Equals, int64(4))
+	c.Assert(dom.recentTables(10), DeepEquals, []int64{tblID + 105, tblID + 104, tblID + 103, tblID + 102})
+}
+
 func (*testSuite) TestErrorCode(c *C) {
 	c.Assert(int(terror.ToSQLError(ErrInfoSchemaExpired).Code), Equals, errno.ErrInfoSchemaExpired)
 	c.Assert(int(terror.ToSQLError(ErrInfoSchemaChanged).Code), Equals, errno.ErrInfoSchemaChanged)
 }
 
+func (*testSuite) TestHasBackgroundCandidate(c *C) {
+	servers := map[string]*infosync.ServerInfo{
+		"a": {ID: "a"},
+		"b": {ID: "b", ServiceScope: variable.ServiceScopeBackground},
+	}
+	c.Assert(hasBackgroundCandidate([]string{"a", "b"}, servers), IsTrue)
+	c.Assert(hasBackgroundCandidate([]string{"a"}, servers), IsFalse)
+	// The candidate whose server information has expired isn't alive.
+	c.Assert(hasBackgroundCandidate([]string{"a", "c"}, servers), IsFalse)
+	servers["b"].ServiceScope = ""
+	c.Assert(hasBackgroundCandidate([]string{"a", "b"}, servers), IsFalse)
+}
+
 func (*testSuite) TestServerIDConstant(c *C) {
 	c.Assert(lostConnectionToPDTimeout, Less, serverIDTTL)
 }
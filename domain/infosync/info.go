@@ -24,6 +24,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -39,6 +40,7 @@ import (
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/owner"
 	"github.com/pingcap/tidb/sessionctx/binloginfo"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	util2 "github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/dbterror"
@@ -95,6 +97,10 @@ type InfoSyncer struct {
 	topologySession *concurrency.Session
 	prometheusAddr  string
 	modifyTime      time.Time
+	// mu protects serviceScope, which is stored along with info. info is shared with the readers without a lock, so the
+	// changes of tidb_service_scope aren't written into it.
+	mu           sync.Mutex
+	serviceScope string
 }
 
 // ServerInfo is server static information.
@@ -109,6 +115,9 @@ type ServerInfo struct {
 	BinlogStatus   string            `json:"binlog_status"`
 	StartTimestamp int64             `json:"start_timestamp"`
 	Labels         map[string]string `json:"labels"`
+	// ServiceScope is the value of tidb_service_scope. It's refreshed by UpdateServiceScope in the server information
+	// stored in etcd, the one of the current server keeps the value when the server starts.
+	ServiceScope string `json:"service_scope"`
 	// ServerID is a function, to always retrieve latest serverID from `Domain`,
 	//   which will be changed on occasions such as connection to PD is restored after broken.
 	ServerIDGetter func() uint64 `json:"-"`
@@ -163,11 +172,13 @@ func setGlobalInfoSyncer(is *InfoSyncer) {
 
 // GlobalInfoSyncerInit return a new InfoSyncer. It is exported for testing.
 func GlobalInfoSyncerInit(ctx context.Context, id string, serverIDGetter func() uint64, etcdCli *clientv3.Client, skipRegisterToDashBoard bool) (*InfoSyncer, error) {
+	info := getServerInfo(id, serverIDGetter)
 	is := &InfoSyncer{
 		etcdCli:        etcdCli,
-		info:           getServerInfo(id, serverIDGetter),
+		info:           info,
 		serverInfoPath: fmt.Sprintf("%s/%s", ServerInformationPath, id),
 		minStartTSPath: fmt.Sprintf("%s/%s", ServerMinStartTSPath, id),
+		serviceScope:   info.ServiceScope,
 	}
 	err := is.init(ctx, skipRegisterToDashBoard)
 	if err != nil {
@@ -450,7 +461,11 @@ func (is *InfoSyncer) StoreServerInfo(ctx context.Context) error {
 	if is.etcdCli == nil {
 		return nil
 	}
-	infoBuf, err := is.info.Marshal()
+	is.mu.Lock()
+	info := *is.info
+	info.ServiceScope = is.serviceScope
+	is.mu.Unlock()
+	infoBuf, err := info.Marshal()
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -459,6 +474,19 @@ func (is *InfoSyncer) StoreServerInfo(ctx context.Context) error {
 	return err
 }
 
+// UpdateServiceScope stores the server information to etcd again if tidb_service_scope is changed.
+func (is *InfoSyncer) UpdateServiceScope(ctx context.Context) error {
+	scope := variable.ServiceScope.Load()
+	is.mu.Lock()
+	changed := is.serviceScope != scope
+	is.serviceScope = scope
+	is.mu.Unlock()
+	if !changed {
+		return nil
+	}
+	return is.StoreServerInfo(ctx)
+}
+
 // RemoveServerInfo remove self server static information from etcd.
 func (is *InfoSyncer) RemoveServerInfo() {
 	if is.etcdCli == nil {
@@ -786,6 +814,7 @@ func getServerInfo(id string, serverIDGetter func() uint64) *ServerInfo {
 		BinlogStatus:   binloginfo.GetStatus().String(),
 		StartTimestamp: time.Now().Unix(),
 		Labels:         cfg.Labels,
+		ServiceScope:   variable.ServiceScope.Load(),
 		ServerIDGetter: serverIDGetter,
 	}
 	info.Version = mysql.ServerVersion
@@ -0,0 +1,239 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/tikv/client-go/v2/tikv"
+	"go.uber.org/zap"
+)
+
+// tableAccessRecordInterval is the minimum interval to refresh the access time of a table, it avoids
+// updating the record for every statement.
+const tableAccessRecordInterval = time.Second
+
+// tableAccessPruneFactor bounds the access records, they are pruned to the max number of the persisted tables
+// when there are more records than tableAccessPruneFactor times of it.
+const tableAccessPruneFactor = 2
+
+// regionCacheWarmupMaxSleep is the max backoff time in milliseconds to load the regions of a table.
+const regionCacheWarmupMaxSleep = 20000
+
+// regionCacheSnapshot is the content persisted in the region cache snapshot file.
+type regionCacheSnapshot struct {
+	// TableIDs are the recently accessed tables, ordered by the access time from new to old.
+	TableIDs []int64 `json:"table_ids"`
+}
+
+// RecordTableAccess records the tables accessed by a statement, the regions of the recently accessed
+// tables are loaded into the region cache after the server restarts.
+func (do *Domain) RecordTableAccess(tableIDs []int64) {
+	n := config.GetGlobalConfig().Performance.RegionCacheWarmupTables
+	if n == 0 {
+		return
+	}
+	now := time.Now().UnixNano()
+	for _, id := range tableIDs {
+		if last, ok := do.tableAccess.Load(id); ok {
+			if now-last.(int64) >= int64(tableAccessRecordInterval) {
+				do.tableAccess.Store(id, now)
+			}
+			continue
+		}
+		if _, loaded := do.tableAccess.LoadOrStore(id, now); loaded {
+			continue
+		}
+		if do.tableAccessCount.Add(1) > int64(tableAccessPruneFactor*n) {
+			do.pruneTableAccess(int(n))
+		}
+	}
+}
+
+// pruneTableAccess keeps the n recently accessed tables and removes the others, so the tables which are
+// rarely accessed or dropped don't make the records grow without bound.
+func (do *Domain) pruneTableAccess(n int) {
+	do.tableAccessMu.Lock()
+	defer do.tableAccessMu.Unlock()
+	// The records may have been pruned by another statement.
+	if do.tableAccessCount.Get() <= int64(tableAccessPruneFactor*n) {
+		return
+	}
+	recent := make(map[int64]struct{}, n)
+	for _, id := range do.recentTables(n) {
+		recent[id] = struct{}{}
+	}
+	do.tableAccess.Range(func(key, _ interface{}) bool {
+		if _, ok := recent[key.(int64)]; !ok {
+			do.tableAccess.Delete(key)
+			do.tableAccessCount.Add(-1)
+		}
+		return true
+	})
+}
+
+// recentTables returns at most n recently accessed tables, ordered by the access time from new to old.
+func (do *Domain) recentTables(n int) []int64 {
+	type access struct {
+		id   int64
+		time int64
+	}
+	var accesses []access
+	do.tableAccess.Range(func(key, value interface{}) bool {
+		accesses = append(accesses, access{id: key.(int64), time: value.(int64)})
+		return true
+	})
+	sort.Slice(accesses, func(i, j int) bool {
+		return accesses[i].time > accesses[j].time
+	})
+	if len(accesses) > n {
+		accesses = accesses[:n]
+	}
+	ids := make([]int64, 0, len(accesses))
+	for _, a := range accesses {
+		ids = append(ids, a.id)
+	}
+	return ids
+}
+
+// regionCacheSnapshotPath returns the path of the snapshot file. It's in the temporary storage path, which is
+// distinguished by the host and ports of the instance, so the instances on the same machine don't share it.
+func regionCacheSnapshotPath() string {
+	return filepath.Join(config.GetGlobalConfig().TempStoragePath, disk.RegionCacheSnapshotFile)
+}
+
+// saveRegionCacheSnapshot persists the tables into the file. It writes a temporary file first and
+// then renames it, so a crash during writing doesn't leave a broken snapshot.
+func saveRegionCacheSnapshot(path string, tableIDs []int64) error {
+	data, err := json.Marshal(&regionCacheSnapshot{TableIDs: tableIDs})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Trace(err)
+	}
+	tmpPath := path + ".tmp"
+	if err = os.WriteFile(tmpPath, data, 0644); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(os.Rename(tmpPath, path))
+}
+
+// loadRegionCacheSnapshot loads the tables persisted in the file, it returns nothing if the file doesn't exist.
+func loadRegionCacheSnapshot(path string) ([]int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Trace(err)
+	}
+	snapshot := &regionCacheSnapshot{}
+	if err = json.Unmarshal(data, snapshot); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return snapshot.TableIDs, nil
+}
+
+// persistRegionCacheSnapshot saves the recently accessed tables when the domain is closed.
+func (do *Domain) persistRegionCacheSnapshot() {
+	n := config.GetGlobalConfig().Performance.RegionCacheWarmupTables
+	if n == 0 {
+		return
+	}
+	tableIDs := do.recentTables(int(n))
+	if len(tableIDs) == 0 {
+		return
+	}
+	if err := saveRegionCacheSnapshot(regionCacheSnapshotPath(), tableIDs); err != nil {
+		logutil.BgLogger().Warn("save region cache snapshot failed", zap.Error(err))
+	}
+}
+
+// warmUpRegionCacheLoop loads the regions of the tables persisted before the last shutdown into the region cache.
+func (do *Domain) warmUpRegionCacheLoop() {
+	defer do.wg.Done()
+	n := config.GetGlobalConfig().Performance.RegionCacheWarmupTables
+	tableIDs, err := loadRegionCacheSnapshot(regionCacheSnapshotPath())
+	if err != nil {
+		logutil.BgLogger().Warn("load region cache snapshot failed", zap.Error(err))
+		return
+	}
+	if len(tableIDs) > int(n) {
+		tableIDs = tableIDs[:n]
+	}
+	if len(tableIDs) == 0 {
+		return
+	}
+	startTime := time.Now()
+	regions, err := do.warmUpRegionCache(tableIDs)
+	if err != nil {
+		logutil.BgLogger().Warn("warm up region cache failed", zap.Error(err))
+		return
+	}
+	logutil.BgLogger().Info("warm up region cache finished", zap.Int("tables", len(tableIDs)),
+		zap.Int("regions", regions), zap.Duration("take time", time.Since(startTime)))
+}
+
+// warmUpRegionCache loads the regions of the tables, including their partitions, into the region cache,
+// and returns the number of the loaded regions. The tables which no longer exist are skipped.
+func (do *Domain) warmUpRegionCache(tableIDs []int64) (int, error) {
+	store, ok := do.store.(tikv.Storage)
+	if !ok {
+		return 0, nil
+	}
+	is := do.InfoSchema()
+	var physicalIDs []int64
+	for _, id := range tableIDs {
+		if tbl, ok := is.TableByID(id); ok {
+			if pi := tbl.Meta().GetPartitionInfo(); pi != nil {
+				for _, def := range pi.Definitions {
+					physicalIDs = append(physicalIDs, def.ID)
+				}
+				continue
+			}
+			physicalIDs = append(physicalIDs, id)
+		} else if tbl, _, _ := is.FindTableByPartitionID(id); tbl != nil {
+			physicalIDs = append(physicalIDs, id)
+		}
+	}
+
+	regions := 0
+	for _, id := range physicalIDs {
+		select {
+		case <-do.exit:
+			return regions, nil
+		default:
+		}
+		// The range covers both the records and the indexes of the table.
+		startKey, endKey := tablecodec.GenTablePrefix(id), tablecodec.GenTablePrefix(id+1)
+		bo := tikv.NewBackofferWithVars(context.Background(), regionCacheWarmupMaxSleep, nil)
+		locs, err := store.GetRegionCache().LoadRegionsInKeyRange(bo, startKey, endKey)
+		if err != nil {
+			return regions, errors.Trace(err)
+		}
+		regions += len(locs)
+	}
+	return regions, nil
+}
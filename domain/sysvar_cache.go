@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
@@ -167,6 +168,20 @@ func checkEnableServerGlobalVar(name, sVal string) {
 		err = stmtsummary.StmtSummaryByDigestMap.SetMaxStmtCount(sVal, false)
 	case variable.TiDBStmtSummaryMaxSQLLength:
 		err = stmtsummary.StmtSummaryByDigestMap.SetMaxSQLLength(sVal, false)
+	case variable.TiDBStmtSummarySLOLatency:
+		var val int64
+		val, err = strconv.ParseInt(sVal, 10, 64)
+		if err != nil {
+			break
+		}
+		stmtsummary.StmtSummaryByDigestMap.SetSLOLatency(time.Duration(val) * time.Millisecond)
+	case variable.TiDBStmtSummarySLOObjective:
+		var val float64
+		val, err = strconv.ParseFloat(sVal, 64)
+		if err != nil {
+			break
+		}
+		stmtsummary.StmtSummaryByDigestMap.SetSLOObjective(val)
 	case variable.TiDBCapturePlanBaseline:
 		variable.CapturePlanBaseline.Set(sVal, false)
 	case variable.TiDBEnableTopSQL:
@@ -199,6 +214,40 @@ func checkEnableServerGlobalVar(name, sVal string) {
 			break
 		}
 		variable.TopSQLVariable.ReportIntervalSeconds.Store(val)
+	case variable.TiDBTopSQLMaxOverheadPercentage:
+		var val float64
+		val, err = strconv.ParseFloat(sVal, 64)
+		if err != nil {
+			break
+		}
+		variable.TopSQLVariable.MaxOverheadPercentage.Store(val)
+	case variable.TiDBTopSQLAdaptiveInterval:
+		variable.TopSQLVariable.AdaptiveInterval.Store(variable.TiDBOptOn(sVal))
+	case variable.TiDBAdmissionCPUThreshold:
+		var val float64
+		val, err = strconv.ParseFloat(sVal, 64)
+		if err != nil {
+			break
+		}
+		variable.AdmissionCPUThreshold.Store(val)
+	case variable.TiDBAdmissionMaxQueueTime:
+		var val int64
+		val, err = strconv.ParseInt(sVal, 10, 64)
+		if err != nil {
+			break
+		}
+		variable.AdmissionMaxQueueTime.Store(val)
+	case variable.TiDBCheckConstraintEnforcement:
+		variable.CheckConstraintEnforcement.Store(int32(variable.TiDBOptCheckConstraintLevel(sVal)))
+	case variable.TiDBEnableGOGCTuner:
+		variable.EnableGOGCTuner.Store(variable.TiDBOptOn(sVal))
+	case variable.TiDBMemQuotaBindingCache:
+		var val int64
+		val, err = strconv.ParseInt(sVal, 10, 64)
+		if err != nil {
+			break
+		}
+		variable.MemQuotaBindingCache.Store(val)
 	}
 	if err != nil {
 		logutil.BgLogger().Error(fmt.Sprintf("load global variable %s error", name), zap.Error(err))
@@ -45,16 +45,19 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/admission"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/hint"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/plancodec"
+	"github.com/pingcap/tidb/util/redact"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/stmtsummary"
 	"github.com/pingcap/tidb/util/stringutil"
 	"github.com/pingcap/tidb/util/topsql"
+	"github.com/pingcap/tidb/util/topsql/tracecpu"
 	tikverr "github.com/tikv/client-go/v2/error"
 	"github.com/tikv/client-go/v2/oracle"
 	"github.com/tikv/client-go/v2/util"
@@ -84,6 +87,8 @@ type recordSet struct {
 	stmt       *ExecStmt
 	lastErr    error
 	txnStartTS uint64
+	// firstChunk is the preallocated chunk returned by the first call of NewChunk.
+	firstChunk *chunk.Chunk
 }
 
 func (a *recordSet) Fields() []*ast.ResultField {
@@ -162,6 +167,10 @@ func (a *recordSet) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 
 // NewChunk create a chunk base on top-level executor's newFirstChunk().
 func (a *recordSet) NewChunk() *chunk.Chunk {
+	if chk := a.firstChunk; chk != nil {
+		a.firstChunk = nil
+		return chk
+	}
 	return newFirstChunk(a.executor)
 }
 
@@ -215,6 +224,9 @@ type ExecStmt struct {
 	OutputNames []*types.FieldName
 	PsStmt      *plannercore.CachedPrepareStmt
 	Ti          *TelemetryInfo
+
+	// redactedText caches the result of getRedactedText, the text is needed several times after the execution.
+	redactedText *string
 }
 
 // PointGet short path for point exec directly from plan, keep only necessary steps
@@ -259,10 +271,15 @@ func (a *ExecStmt) PointGet(ctx context.Context, is infoschema.InfoSchema) (*rec
 		terror.Call(pointExecutor.Close)
 		return nil, err
 	}
+	if pointExecutor.firstChunk == nil {
+		pointExecutor.firstChunk = newFirstChunk(pointExecutor)
+	}
+	pointExecutor.firstChunk.Reset()
 	return &recordSet{
 		executor:   pointExecutor,
 		stmt:       a,
 		txnStartTS: startTs,
+		firstChunk: pointExecutor.firstChunk,
 	}, nil
 }
 
@@ -307,11 +324,218 @@ func (a *ExecStmt) setPlanLabelForTopSQL(ctx context.Context) context.Context {
 	if a.Plan == nil || !variable.TopSQLEnabled() {
 		return ctx
 	}
-	normalizedSQL, sqlDigest := a.Ctx.GetSessionVars().StmtCtx.SQLDigest()
+	stmtCtx := a.Ctx.GetSessionVars().StmtCtx
+	normalizedSQL, sqlDigest := stmtCtx.SQLDigest()
 	normalizedPlan, planDigest := getPlanDigest(a.Ctx, a.Plan)
 	return topsql.AttachSQLInfo(ctx, normalizedSQL, sqlDigest, normalizedPlan, planDigest)
 }
 
+// recordCopCPUTimeForTopSQL records the CPU time of the coprocessor tasks reported by the storage to Top SQL, so that
+// the regressions of the pushed down plan nodes are visible.
+func (a *ExecStmt) recordCopCPUTimeForTopSQL() {
+	stmtCtx := a.Ctx.GetSessionVars().StmtCtx
+	if a.Plan == nil || stmtCtx.RuntimeStatsColl == nil || !variable.TopSQLEnabled() {
+		return
+	}
+	cpuTimeByPlanNode := make(map[string]time.Duration)
+	collectCopCPUTime(stmtCtx.RuntimeStatsColl, a.Plan, plannercore.NormalizedPlanNodeIDs(a.Plan), cpuTimeByPlanNode)
+	if len(cpuTimeByPlanNode) == 0 {
+		return
+	}
+	_, sqlDigest := stmtCtx.SQLDigest()
+	_, planDigest := getPlanDigest(a.Ctx, a.Plan)
+	if sqlDigest == nil {
+		return
+	}
+	var planDigestBytes []byte
+	if planDigest != nil {
+		planDigestBytes = planDigest.Bytes()
+	}
+	sessVars := a.Ctx.GetSessionVars()
+	topsql.RecordCopCPUTime(sqlDigest.Bytes(), planDigestBytes, sessVars.CurrentDB, topsql.UserName(sessVars.User), cpuTimeByPlanNode)
+}
+
+// recordExecutionForTopSQL records the latency, the processed rows and the wait time of the execution to Top SQL, so
+// that the statements can be ranked by the execution count, the latency and the wait time besides the CPU time.
+func (a *ExecStmt) recordExecutionForTopSQL() {
+	if a.Plan == nil || !variable.TopSQLEnabled() {
+		return
+	}
+	sessVars := a.Ctx.GetSessionVars()
+	stmtCtx := sessVars.StmtCtx
+	_, sqlDigest := stmtCtx.SQLDigest()
+	if sqlDigest == nil {
+		return
+	}
+	_, planDigest := getPlanDigest(a.Ctx, a.Plan)
+	var planDigestBytes []byte
+	if planDigest != nil {
+		planDigestBytes = planDigest.Bytes()
+	}
+	// The rows processed are the affected rows of the write statements and the returned rows of the read statements.
+	rows := stmtCtx.AffectedRows()
+	if stmtCtx.RuntimeStatsColl != nil && stmtCtx.RuntimeStatsColl.ExistsRootStats(a.Plan.ID()) {
+		if actRows := stmtCtx.RuntimeStatsColl.GetRootStats(a.Plan.ID()).GetActRows(); actRows > 0 {
+			rows += uint64(actRows)
+		}
+	}
+	latency := time.Since(sessVars.StartTime) + sessVars.DurationParse
+	topsql.RecordExecution(sqlDigest.Bytes(), planDigestBytes, sessVars.CurrentDB, topsql.UserName(sessVars.User), stmtCtx.StmtInstanceID(), latency, rows, getWaitTime(sessVars, latency))
+}
+
+// getWaitTime breaks down the time that the execution spends on waiting by tracecpu.WaitType. The coprocessor queue
+// time and the backoff time are summed over the coprocessor tasks, which run in parallel, so they are capped by the
+// latency of the execution to stay comparable with the other wait types.
+func getWaitTime(sessVars *variable.SessionVars, latency time.Duration) [tracecpu.NumWaitTypes]time.Duration {
+	var waitTime [tracecpu.NumWaitTypes]time.Duration
+	execDetail := sessVars.StmtCtx.GetExecDetails()
+	waitTime[tracecpu.WaitLock] = execDetail.LockKeysDuration
+	waitTime[tracecpu.WaitCopQueue] = execDetail.TimeDetail.WaitTime
+	waitTime[tracecpu.WaitTSO] = sessVars.DurationWaitTS
+	waitTime[tracecpu.WaitBackoff] = execDetail.BackoffTime
+	if execDetail.CommitDetail != nil {
+		waitTime[tracecpu.WaitLock] += execDetail.CommitDetail.LocalLatchTime
+		waitTime[tracecpu.WaitTSO] += execDetail.CommitDetail.GetCommitTsTime
+	}
+	for _, tp := range []tracecpu.WaitType{tracecpu.WaitCopQueue, tracecpu.WaitBackoff} {
+		if waitTime[tp] > latency {
+			waitTime[tp] = latency
+		}
+	}
+	return waitTime
+}
+
+// collectCopCPUTime walks the plan tree to collect the CPU time of the pushed down plan nodes into cpuTimeByPlanNode,
+// which is keyed by the identities in nodeIDs.
+func collectCopCPUTime(statsColl *execdetails.RuntimeStatsColl, p plannercore.Plan, nodeIDs map[int]string, cpuTimeByPlanNode map[string]time.Duration) {
+	switch x := p.(type) {
+	case *plannercore.Insert:
+		if x.SelectPlan != nil {
+			collectCopCPUTime(statsColl, x.SelectPlan, nodeIDs, cpuTimeByPlanNode)
+		}
+	case *plannercore.Update:
+		collectCopCPUTime(statsColl, x.SelectPlan, nodeIDs, cpuTimeByPlanNode)
+	case *plannercore.Delete:
+		collectCopCPUTime(statsColl, x.SelectPlan, nodeIDs, cpuTimeByPlanNode)
+	case *plannercore.PhysicalTableReader:
+		collectReaderCPUTime(statsColl, x.ID(), []plannercore.PhysicalPlan{x.GetTablePlan()}, nodeIDs, cpuTimeByPlanNode)
+	case *plannercore.PhysicalIndexReader:
+		collectReaderCPUTime(statsColl, x.ID(), []plannercore.PhysicalPlan{x.IndexPlans[len(x.IndexPlans)-1]}, nodeIDs, cpuTimeByPlanNode)
+	case *plannercore.PhysicalIndexLookUpReader:
+		collectReaderCPUTime(statsColl, x.ID(), []plannercore.PhysicalPlan{x.IndexPlans[len(x.IndexPlans)-1], x.TablePlans[len(x.TablePlans)-1]}, nodeIDs, cpuTimeByPlanNode)
+	case *plannercore.PhysicalIndexMergeReader:
+		pushedDownPlans := make([]plannercore.PhysicalPlan, 0, len(x.PartialPlans)+1)
+		for _, partialPlans := range x.PartialPlans {
+			pushedDownPlans = append(pushedDownPlans, partialPlans[len(partialPlans)-1])
+		}
+		if len(x.TablePlans) > 0 {
+			pushedDownPlans = append(pushedDownPlans, x.TablePlans[len(x.TablePlans)-1])
+		}
+		collectReaderCPUTime(statsColl, x.ID(), pushedDownPlans, nodeIDs, cpuTimeByPlanNode)
+	case plannercore.PhysicalPlan:
+		for _, child := range x.Children() {
+			collectCopCPUTime(statsColl, child, nodeIDs, cpuTimeByPlanNode)
+		}
+	}
+}
+
+// collectReaderCPUTime collects the CPU time of the coprocessor tasks of a reader. The storage doesn't report the CPU
+// time of each executor, so the processing time of the tasks, which excludes the waiting time, is divided among the
+// pushed down plan nodes in proportion to their own execution time.
+func collectReaderCPUTime(statsColl *execdetails.RuntimeStatsColl, readerID int, pushedDownPlans []plannercore.PhysicalPlan,
+	nodeIDs map[int]string, cpuTimeByPlanNode map[string]time.Duration) {
+	if !statsColl.ExistsRootStats(readerID) {
+		return
+	}
+	processTime := statsColl.GetRootStats(readerID).GetProcessTime()
+	if processTime <= 0 {
+		return
+	}
+	selfTimeByPlanNode := make(map[string]time.Duration)
+	for _, p := range pushedDownPlans {
+		collectPushedDownSelfTime(statsColl, p, nodeIDs, selfTimeByPlanNode)
+	}
+	var totalSelfTime time.Duration
+	for _, selfTime := range selfTimeByPlanNode {
+		totalSelfTime += selfTime
+	}
+	if totalSelfTime <= 0 {
+		return
+	}
+	for planNode, selfTime := range selfTimeByPlanNode {
+		cpuTimeByPlanNode[planNode] += time.Duration(float64(processTime) * float64(selfTime) / float64(totalSelfTime))
+	}
+}
+
+// collectPushedDownSelfTime collects the execution time of the pushed down plan tree, and returns the total time of p.
+// The time of an executor reported by the storage includes the time of its children, so the children's time is
+// subtracted.
+func collectPushedDownSelfTime(statsColl *execdetails.RuntimeStatsColl, p plannercore.PhysicalPlan, nodeIDs map[int]string, selfTimeByPlanNode map[string]time.Duration) time.Duration {
+	var childrenTime time.Duration
+	for _, child := range p.Children() {
+		childrenTime += collectPushedDownSelfTime(statsColl, child, nodeIDs, selfTimeByPlanNode)
+	}
+	copStats := statsColl.GetCopStats(p.ID())
+	if copStats == nil {
+		return childrenTime
+	}
+	totalTime := copStats.GetTimeProcessed()
+	if selfTime := totalTime - childrenTime; selfTime > 0 {
+		nodeID, ok := nodeIDs[p.ID()]
+		if !ok {
+			nodeID = p.TP()
+		}
+		selfTimeByPlanNode[nodeID] += selfTime
+	}
+	return totalTime
+}
+
+// admit waits for the admission control to execute the statement, it's queued by its priority when the CPU of the
+// instance is saturated.
+func (a *ExecStmt) admit(ctx context.Context) error {
+	sessVars := a.Ctx.GetSessionVars()
+	if sessVars.InRestrictedSQL {
+		return nil
+	}
+	switch a.Plan.(type) {
+	case *plannercore.Simple, *plannercore.Set:
+		// Don't queue the statements like COMMIT and KILL, they are cheap and may release the resources.
+		return nil
+	}
+	if sessVars.InTxn() {
+		// The statements in a transaction may hold the locks, queuing them blocks the other transactions.
+		return nil
+	}
+	queueTime := admission.Admit(ctx, a.admissionPriority(), &sessVars.Killed)
+	if queueTime == 0 {
+		return nil
+	}
+	sessVars.StmtCtx.AdmissionQueueTime = queueTime
+	if atomic.LoadUint32(&sessVars.Killed) == 1 {
+		return ErrQueryInterrupted
+	}
+	return ctx.Err()
+}
+
+// admissionPriority returns the priority of the statement in the admission control. It's decided like the priority set
+// by `a.buildExecutor`, which runs after the admission, except that the prepared statements of EXECUTE aren't lowered.
+func (a *ExecStmt) admissionPriority() mysql.PriorityEnum {
+	sessVars := a.Ctx.GetSessionVars()
+	if priority := sessVars.StmtCtx.Priority; priority != mysql.NoPriority {
+		return priority
+	}
+	if _, ok := a.Plan.(*plannercore.Execute); ok || sessVars.SnapshotTS != 0 {
+		return mysql.NoPriority
+	}
+	if useMaxTS, err := plannercore.IsPointGetWithPKOrUniqueKeyByAutoCommit(a.Ctx, a.Plan); err == nil && useMaxTS {
+		return mysql.HighPriority
+	}
+	if a.LowerPriority {
+		return mysql.LowPriority
+	}
+	return mysql.NoPriority
+}
+
 // Exec builds an Executor from a plan. If the Executor doesn't return result,
 // like the INSERT, UPDATE statements, it executes in this function, if the Executor returns
 // result, execution is done after this function returns, in the returned sqlexec.RecordSet Next method.
@@ -377,6 +601,12 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 		sctx.GetSessionVars().StmtCtx.MemTracker.SetBytesLimit(sctx.GetSessionVars().StmtCtx.MemQuotaQuery)
 	}
 
+	// Admit the statement before `a.buildExecutor`, which may start the transaction, so the queued statements don't
+	// hold the transactions or the timestamps.
+	if err = a.admit(ctx); err != nil {
+		return nil, err
+	}
+
 	e, err := a.buildExecutor()
 	if err != nil {
 		return nil, err
@@ -889,6 +1119,8 @@ func (a *ExecStmt) FinishExecuteStmt(txnTS uint64, err error, hasMoreResults boo
 	// `LowSlowQuery` and `SummaryStmt` must be called before recording `PrevStmt`.
 	a.LogSlowQuery(txnTS, succ, hasMoreResults)
 	a.SummaryStmt(succ)
+	a.recordCopCPUTimeForTopSQL()
+	a.recordExecutionForTopSQL()
 	if sessVars.StmtCtx.IsTiFlash.Load() {
 		if succ {
 			totalTiFlashQuerySuccCounter.Inc()
@@ -897,6 +1129,11 @@ func (a *ExecStmt) FinishExecuteStmt(txnTS uint64, err error, hasMoreResults boo
 		}
 	}
 	sessVars.PrevStmt = FormatSQL(a.GetTextToLog())
+	if !sessVars.InRestrictedSQL && len(sessVars.StmtCtx.TableIDs) > 0 {
+		if dom := domain.GetDomain(a.Ctx); dom != nil {
+			dom.RecordTableAccess(sessVars.StmtCtx.TableIDs)
+		}
+	}
 
 	executeDuration := time.Since(sessVars.StartTime) - sessVars.DurationCompile
 	if sessVars.InRestrictedSQL {
@@ -979,6 +1216,7 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 	_, planDigest := getPlanDigest(a.Ctx, a.Plan)
 	slowItems := &variable.SlowQueryLogItems{
 		TxnTS:             txnTS,
+		StmtInstanceID:    sessVars.StmtCtx.StmtInstanceID(),
 		SQL:               sql.String(),
 		Digest:            digest.String(),
 		TimeTotal:         costTime,
@@ -986,6 +1224,7 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 		TimeCompile:       sessVars.DurationCompile,
 		TimeOptimize:      sessVars.DurationOptimization,
 		TimeWaitTS:        sessVars.DurationWaitTS,
+		TimeAdmission:     sessVars.StmtCtx.AdmissionQueueTime,
 		IndexNames:        indexNames,
 		StatsInfos:        statsInfos,
 		CopTasks:          copTaskInfo,
@@ -993,7 +1232,7 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 		MemMax:            memMax,
 		DiskMax:           diskMax,
 		Succ:              succ,
-		Plan:              getPlanTree(a.Ctx, a.Plan),
+		Plan:              getPlanTree(a.Ctx, a.Plan, a.needRedactPlan()),
 		PlanDigest:        planDigest.String(),
 		Prepared:          a.isPreparedStmt,
 		HasMoreResults:    hasMoreResults,
@@ -1006,6 +1245,9 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 		WriteSQLRespTotal: stmtDetail.WriteSQLRespDuration,
 		ExecRetryCount:    a.retryCount,
 	}
+	if rpcStats, ok := a.GoCtx.Value(execdetails.StmtRPCStatsKey).(*execdetails.StmtRPCStats); ok {
+		slowItems.RPCStats = rpcStats.SlowLogString()
+	}
 	if a.retryCount > 0 {
 		slowItems.ExecRetryTime = costTime - sessVars.DurationParse - sessVars.DurationCompile - time.Since(a.retryStartTime)
 	}
@@ -1055,12 +1297,12 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 }
 
 // getPlanTree will try to get the select plan tree if the plan is select or the select plan of delete/update/insert statement.
-func getPlanTree(sctx sessionctx.Context, p plannercore.Plan) string {
+func getPlanTree(sctx sessionctx.Context, p plannercore.Plan, redact bool) string {
 	cfg := config.GetGlobalConfig()
 	if atomic.LoadUint32(&cfg.Log.RecordPlanInSlowLog) == 0 {
 		return ""
 	}
-	planTree, _ := getEncodedPlan(sctx, p, false, nil)
+	planTree, _ := getEncodedPlan(sctx, p, false, nil, redact)
 	if len(planTree) == 0 {
 		return planTree
 	}
@@ -1079,8 +1321,9 @@ func getPlanDigest(sctx sessionctx.Context, p plannercore.Plan) (string, *parser
 	return normalized, planDigest
 }
 
-// getEncodedPlan gets the encoded plan, and generates the hint string if indicated.
-func getEncodedPlan(sctx sessionctx.Context, p plannercore.Plan, genHint bool, n ast.StmtNode) (encodedPlan, hintStr string) {
+// getEncodedPlan gets the encoded plan, and generates the hint string if indicated. The constants in the plan are
+// masked if redact is true.
+func getEncodedPlan(sctx sessionctx.Context, p plannercore.Plan, genHint bool, n ast.StmtNode, redact bool) (encodedPlan, hintStr string) {
 	var hintSet bool
 	encodedPlan = sctx.GetSessionVars().StmtCtx.GetEncodedPlan()
 	hintStr, hintSet = sctx.GetSessionVars().StmtCtx.GetPlanHint()
@@ -1088,7 +1331,11 @@ func getEncodedPlan(sctx sessionctx.Context, p plannercore.Plan, genHint bool, n
 		return
 	}
 	if len(encodedPlan) == 0 {
-		encodedPlan = plannercore.EncodePlan(p)
+		if redact {
+			encodedPlan = plannercore.EncodeRedactedPlan(p)
+		} else {
+			encodedPlan = plannercore.EncodePlan(p)
+		}
 		sctx.GetSessionVars().StmtCtx.SetEncodedPlan(encodedPlan)
 	}
 	if genHint {
@@ -1140,8 +1387,9 @@ func (a *ExecStmt) SummaryStmt(succ bool) {
 	sessVars.SetPrevStmtDigest(digest.String())
 
 	// No need to encode every time, so encode lazily.
+	redactPlan := a.needRedactPlan()
 	planGenerator := func() (string, string) {
-		return getEncodedPlan(a.Ctx, a.Plan, !sessVars.InRestrictedSQL, a.StmtNode)
+		return getEncodedPlan(a.Ctx, a.Plan, !sessVars.InRestrictedSQL, a.StmtNode, redactPlan)
 	}
 	// Generating plan digest is slow, only generate it once if it's 'Point_Get'.
 	// If it's a point get, different SQLs leads to different plans, so SQL digest
@@ -1218,8 +1466,96 @@ func (a *ExecStmt) GetTextToLog() string {
 		sql, _ = sessVars.StmtCtx.SQLDigest()
 	} else if sensitiveStmt, ok := a.StmtNode.(ast.SensitiveStmtNode); ok {
 		sql = sensitiveStmt.SecureText()
+	} else if redactedSQL, ok := a.getRedactedText(); ok {
+		sql = redactedSQL
 	} else {
 		sql = sessVars.StmtCtx.OriginalSQL + sessVars.PreparedParams.String()
 	}
 	return sql
 }
+
+// needRedactPlan returns whether the constants in the encoded plan should be masked. They are masked if the query text
+// to log is redacted, so that the plan doesn't leak the values masked in the text.
+func (a *ExecStmt) needRedactPlan() bool {
+	if a.Ctx.GetSessionVars().EnableRedactLog {
+		return true
+	}
+	_, ok := a.getRedactedText()
+	return ok
+}
+
+// getRedactedText returns the query text whose literals and arguments bound to the sensitive columns configured by
+// `log.redact-sensitive-columns` are masked. ok is false if nothing needs to be masked.
+func (a *ExecStmt) getRedactedText() (sql string, ok bool) {
+	policy := redact.GlobalPolicy()
+	if policy == nil {
+		return "", false
+	}
+	if a.redactedText != nil {
+		return *a.redactedText, len(*a.redactedText) > 0
+	}
+	sql, ok = a.redactText(policy)
+	a.redactedText = &sql
+	return sql, ok
+}
+
+func (a *ExecStmt) redactText(policy *redact.Policy) (string, bool) {
+	sessVars := a.Ctx.GetSessionVars()
+	sql, maskedParams, ok := policy.RedactSQL(a.getStmtNodeToRedact(), sessVars.CurrentDB, sessVars.SQLMode, a.getTableInfo)
+	if !ok {
+		return "", false
+	}
+	params := sessVars.PreparedParams
+	if len(maskedParams) > 0 {
+		params = make(variable.PreparedParams, len(sessVars.PreparedParams))
+		copy(params, sessVars.PreparedParams)
+		for order := range maskedParams {
+			if order < len(params) {
+				params[order] = types.NewStringDatum("?")
+			}
+		}
+	}
+	return sql + params.String(), true
+}
+
+// RedactError masks the values of the sensitive columns in the returned error and the warnings of the statement.
+func (a *ExecStmt) RedactError(err error) error {
+	policy := redact.GlobalPolicy()
+	if policy == nil {
+		return err
+	}
+	sc := a.Ctx.GetSessionVars().StmtCtx
+	if err == nil && sc.WarningCount() == 0 {
+		return err
+	}
+	stmtNode, currentDB := a.getStmtNodeToRedact(), a.Ctx.GetSessionVars().CurrentDB
+	if sc.WarningCount() > 0 {
+		warns := sc.GetWarnings()
+		for i := range warns {
+			warns[i].Err = policy.RedactError(warns[i].Err, stmtNode, currentDB, a.getTableInfo)
+		}
+		sc.SetWarnings(warns)
+	}
+	return policy.RedactError(err, stmtNode, currentDB, a.getTableInfo)
+}
+
+// getStmtNodeToRedact returns the prepared statement for the EXECUTE statement, or the statement itself.
+func (a *ExecStmt) getStmtNodeToRedact() ast.StmtNode {
+	if execStmt, ok := a.StmtNode.(*ast.ExecuteStmt); ok {
+		if prepared, err := planner.GetPreparedStmt(execStmt, a.Ctx.GetSessionVars()); err == nil {
+			return prepared.PreparedAst.Stmt
+		}
+	}
+	return a.StmtNode
+}
+
+func (a *ExecStmt) getTableInfo(db, tbl string) *model.TableInfo {
+	if a.InfoSchema == nil {
+		return nil
+	}
+	t, err := a.InfoSchema.TableByName(model.NewCIStr(db), model.NewCIStr(tbl))
+	if err != nil {
+		return nil
+	}
+	return t.Meta()
+}
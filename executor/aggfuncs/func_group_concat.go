@@ -273,6 +273,14 @@ type sortRow struct {
 	byItems []*types.Datum
 }
 
+func (row sortRow) memSize() (memSize int64) {
+	memSize += int64(row.buffer.Cap())
+	for _, dt := range row.byItems {
+		memSize += GetDatumMemSize(dt)
+	}
+	return memSize
+}
+
 type topNRows struct {
 	rows []sortRow
 	desc []bool
@@ -335,10 +343,7 @@ func (h *topNRows) tryToAdd(row sortRow) (truncated bool, memDelta int64) {
 		h.currSize += h.sepSize
 	}
 	heap.Push(h, row)
-	memDelta += int64(row.buffer.Cap())
-	for _, dt := range row.byItems {
-		memDelta += GetDatumMemSize(dt)
-	}
+	memDelta += row.memSize()
 	if h.currSize <= h.limitSize {
 		return false, memDelta
 	}
@@ -350,10 +355,7 @@ func (h *topNRows) tryToAdd(row sortRow) (truncated bool, memDelta int64) {
 			h.rows[0].buffer.Truncate(h.rows[0].buffer.Len() - int(debt))
 		} else {
 			h.currSize -= uint64(h.rows[0].buffer.Len()) + h.sepSize
-			memDelta -= int64(h.rows[0].buffer.Cap())
-			for _, dt := range h.rows[0].byItems {
-				memDelta -= GetDatumMemSize(dt)
-			}
+			memDelta -= h.rows[0].memSize()
 			heap.Pop(h)
 			h.isSepTruncated = true
 		}
@@ -470,9 +472,25 @@ func (e *groupConcatOrder) UpdatePartialResult(sctx sessionctx.Context, rowsInGr
 }
 
 func (e *groupConcatOrder) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
-	// If order by exists, the parallel hash aggregation is forbidden in executorBuilder.buildHashAgg.
-	// So MergePartialResult will not be called.
-	return 0, dbterror.ClassOptimizer.NewStd(mysql.ErrInternal).GenWithStack("groupConcatOrder.MergePartialResult should not be called")
+	p1, p2 := (*partialResult4GroupConcatOrder)(src), (*partialResult4GroupConcatOrder)(dst)
+	p2.topN.sctx = sctx
+	// The rows are moved from src to dst, whose memory has been tracked, so only the rows popped by dst are counted.
+	for _, row := range p1.topN.rows {
+		memDelta -= row.memSize()
+		truncated, sortRowMemSize := p2.topN.tryToAdd(row)
+		memDelta += sortRowMemSize
+		if p2.topN.err != nil {
+			return memDelta, p2.topN.err
+		}
+		if truncated {
+			if err := e.handleTruncateError(sctx); err != nil {
+				return memDelta, err
+			}
+		}
+	}
+	// The rows popped by src are after the rows kept by it in the order, so they are not kept by dst either.
+	p2.topN.isSepTruncated = p2.topN.isSepTruncated || p1.topN.isSepTruncated
+	return memDelta, nil
 }
 
 // SetTruncated will be called in `executorBuilder#buildHashAgg` with duck-type.
@@ -584,7 +602,7 @@ func (e *groupConcatDistinctOrder) UpdatePartialResult(sctx sessionctx.Context,
 }
 
 func (e *groupConcatDistinctOrder) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
-	// If order by exists, the parallel hash aggregation is forbidden in executorBuilder.buildHashAgg.
+	// If distinct exists, the parallel hash aggregation is forbidden in executorBuilder.buildHashAgg.
 	// So MergePartialResult will not be called.
 	return 0, dbterror.ClassOptimizer.NewStd(mysql.ErrInternal).GenWithStack("groupConcatDistinctOrder.MergePartialResult should not be called")
 }
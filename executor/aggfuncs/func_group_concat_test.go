@@ -33,6 +33,10 @@ import (
 func (s *testSuite) TestMergePartialResult4GroupConcat(c *C) {
 	test := buildAggTester(ast.AggFuncGroupConcat, mysql.TypeString, 5, "0 1 2 3 4", "2 3 4", "0 1 2 3 4 2 3 4")
 	s.testMergePartialResult(c, test)
+
+	test = buildAggTester(ast.AggFuncGroupConcat, mysql.TypeString, 5, "4 3 2 1 0", "4 3 2", "4 4 3 3 2 2 1 0")
+	test.orderBy = true
+	s.testMergePartialResult(c, test)
 }
 
 func (s *testSuite) TestGroupConcat(c *C) {
@@ -22,11 +22,21 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/selection"
+	"github.com/pingcap/tidb/util/tdigest"
 )
 
 const (
 	// DefSliceSize represents size of an empty Slice
 	DefSliceSize = int64(unsafe.Sizeof([]interface{}{}))
+	// DefPartialResult4ApproxPercentileIntSize is the size of partialResult4ApproxPercentileInt
+	DefPartialResult4ApproxPercentileIntSize = int64(unsafe.Sizeof(partialResult4ApproxPercentileInt{}))
+	// DefPartialResult4ApproxPercentileRealSize is the size of partialResult4ApproxPercentileReal
+	DefPartialResult4ApproxPercentileRealSize = int64(unsafe.Sizeof(partialResult4ApproxPercentileReal{}))
+
+	// percentileExactLimit is the max number of the int or real values kept by a group of APPROX_PERCENTILE, the
+	// values beyond it are summarized by a t-digest of percentileCompression.
+	percentileExactLimit  = 8192
+	percentileCompression = 100
 )
 
 var (
@@ -121,23 +131,121 @@ func (p partialResult4PercentileDuration) MemSize() int64 {
 	return DefSliceSize + int64(len(p))*DefInt64Size
 }
 
+// partialResult4ApproxPercentileInt keeps the exact values until there are more than percentileExactLimit of them,
+// then the values are summarized by a t-digest to bound the memory, and the percentile becomes approximate.
+type partialResult4ApproxPercentileInt struct {
+	values partialResult4PercentileInt
+	digest *tdigest.TDigest
+}
+
+func (p *partialResult4ApproxPercentileInt) MemSize() int64 {
+	memSize := DefPartialResult4ApproxPercentileIntSize + int64(len(p.values))*DefInt64Size
+	if p.digest != nil {
+		memSize += p.digest.MemSize()
+	}
+	return memSize
+}
+
+func (p *partialResult4ApproxPercentileInt) toDigest() {
+	p.digest = tdigest.New(percentileCompression)
+	for _, v := range p.values {
+		p.digest.Add(float64(v))
+	}
+	p.values = nil
+}
+
+func (p *partialResult4ApproxPercentileInt) add(v int64) {
+	if p.digest != nil {
+		p.digest.Add(float64(v))
+		return
+	}
+	p.values = append(p.values, v)
+	if len(p.values) > percentileExactLimit {
+		p.toDigest()
+	}
+}
+
+func (p *partialResult4ApproxPercentileInt) merge(src *partialResult4ApproxPercentileInt) {
+	if p.digest == nil && src.digest == nil && len(p.values)+len(src.values) <= percentileExactLimit {
+		p.values = append(p.values, src.values...)
+		return
+	}
+	if p.digest == nil {
+		p.toDigest()
+	}
+	if src.digest != nil {
+		p.digest.Merge(src.digest)
+	}
+	for _, v := range src.values {
+		p.digest.Add(float64(v))
+	}
+}
+
+// partialResult4ApproxPercentileReal is the same as partialResult4ApproxPercentileInt for the real values.
+type partialResult4ApproxPercentileReal struct {
+	values partialResult4PercentileReal
+	digest *tdigest.TDigest
+}
+
+func (p *partialResult4ApproxPercentileReal) MemSize() int64 {
+	memSize := DefPartialResult4ApproxPercentileRealSize + int64(len(p.values))*DefFloat64Size
+	if p.digest != nil {
+		memSize += p.digest.MemSize()
+	}
+	return memSize
+}
+
+func (p *partialResult4ApproxPercentileReal) toDigest() {
+	p.digest = tdigest.New(percentileCompression)
+	for _, v := range p.values {
+		p.digest.Add(v)
+	}
+	p.values = nil
+}
+
+func (p *partialResult4ApproxPercentileReal) add(v float64) {
+	if p.digest != nil {
+		p.digest.Add(v)
+		return
+	}
+	p.values = append(p.values, v)
+	if len(p.values) > percentileExactLimit {
+		p.toDigest()
+	}
+}
+
+func (p *partialResult4ApproxPercentileReal) merge(src *partialResult4ApproxPercentileReal) {
+	if p.digest == nil && src.digest == nil && len(p.values)+len(src.values) <= percentileExactLimit {
+		p.values = append(p.values, src.values...)
+		return
+	}
+	if p.digest == nil {
+		p.toDigest()
+	}
+	if src.digest != nil {
+		p.digest.Merge(src.digest)
+	}
+	for _, v := range src.values {
+		p.digest.Add(v)
+	}
+}
+
 type percentileOriginal4Int struct {
 	basePercentile
 }
 
 func (e *percentileOriginal4Int) AllocPartialResult() (pr PartialResult, memDelta int64) {
-	// TODO: Preserve appropriate capacity for data
-	pr = PartialResult(&partialResult4PercentileInt{})
-	return pr, DefSliceSize
+	p := &partialResult4ApproxPercentileInt{}
+	return PartialResult(p), p.MemSize()
 }
 
 func (e *percentileOriginal4Int) ResetPartialResult(pr PartialResult) {
-	p := (*partialResult4PercentileInt)(pr)
-	*p = partialResult4PercentileInt{}
+	p := (*partialResult4ApproxPercentileInt)(pr)
+	*p = partialResult4ApproxPercentileInt{}
 }
 
 func (e *percentileOriginal4Int) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
-	p := (*partialResult4PercentileInt)(pr)
+	p := (*partialResult4ApproxPercentileInt)(pr)
 	startMem := p.MemSize()
 	for _, row := range rowsInGroup {
 		v, isNull, err := e.args[0].EvalInt(sctx, row)
@@ -147,30 +255,40 @@ func (e *percentileOriginal4Int) UpdatePartialResult(sctx sessionctx.Context, ro
 		if isNull {
 			continue
 		}
-		*p = append(*p, v)
+		p.add(v)
 	}
 	endMem := p.MemSize()
 	return endMem - startMem, nil
 }
 
 func (e *percentileOriginal4Int) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
-	p1, p2 := (*partialResult4PercentileInt)(src), (*partialResult4PercentileInt)(dst)
-	mergeBuff := make([]int64, len(*p1)+len(*p2))
-	copy(mergeBuff, *p2)
-	copy(mergeBuff[len(*p2):], *p1)
-	*p1 = nil
-	*p2 = mergeBuff
-	return 0, nil
+	p1, p2 := (*partialResult4ApproxPercentileInt)(src), (*partialResult4ApproxPercentileInt)(dst)
+	startMem := p2.MemSize()
+	p2.merge(p1)
+	*p1 = partialResult4ApproxPercentileInt{}
+	return p2.MemSize() - startMem, nil
 }
 
 func (e *percentileOriginal4Int) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
-	p := (*partialResult4PercentileInt)(pr)
-	if len(*p) == 0 {
+	p := (*partialResult4ApproxPercentileInt)(pr)
+	if p.digest != nil {
+		v := math.Round(p.digest.Quantile(float64(e.percent) / 100))
+		// The estimation is between the min and the max, the boundaries are only exceeded by the float64 rounding.
+		if v >= math.MaxInt64 {
+			chk.AppendInt64(e.ordinal, math.MaxInt64)
+		} else if v <= math.MinInt64 {
+			chk.AppendInt64(e.ordinal, math.MinInt64)
+		} else {
+			chk.AppendInt64(e.ordinal, int64(v))
+		}
+		return nil
+	}
+	if len(p.values) == 0 {
 		chk.AppendNull(e.ordinal)
 		return nil
 	}
-	index := percentile(p, e.percent)
-	chk.AppendInt64(e.ordinal, (*p)[index])
+	index := percentile(p.values, e.percent)
+	chk.AppendInt64(e.ordinal, p.values[index])
 	return nil
 }
 
@@ -179,18 +297,17 @@ type percentileOriginal4Real struct {
 }
 
 func (e *percentileOriginal4Real) AllocPartialResult() (pr PartialResult, memDelta int64) {
-	// TODO: Preserve appropriate capacity for data
-	pr = PartialResult(&partialResult4PercentileReal{})
-	return pr, DefSliceSize
+	p := &partialResult4ApproxPercentileReal{}
+	return PartialResult(p), p.MemSize()
 }
 
 func (e *percentileOriginal4Real) ResetPartialResult(pr PartialResult) {
-	p := (*partialResult4PercentileReal)(pr)
-	*p = partialResult4PercentileReal{}
+	p := (*partialResult4ApproxPercentileReal)(pr)
+	*p = partialResult4ApproxPercentileReal{}
 }
 
 func (e *percentileOriginal4Real) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) (memDelta int64, err error) {
-	p := (*partialResult4PercentileReal)(pr)
+	p := (*partialResult4ApproxPercentileReal)(pr)
 	startMem := p.MemSize()
 	for _, row := range rowsInGroup {
 		v, isNull, err := e.args[0].EvalReal(sctx, row)
@@ -200,30 +317,32 @@ func (e *percentileOriginal4Real) UpdatePartialResult(sctx sessionctx.Context, r
 		if isNull {
 			continue
 		}
-		*p = append(*p, v)
+		p.add(v)
 	}
 	endMem := p.MemSize()
 	return endMem - startMem, nil
 }
 
 func (e *percentileOriginal4Real) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) (memDelta int64, err error) {
-	p1, p2 := (*partialResult4PercentileReal)(src), (*partialResult4PercentileReal)(dst)
-	mergeBuff := make([]float64, len(*p1)+len(*p2))
-	copy(mergeBuff, *p2)
-	copy(mergeBuff[len(*p2):], *p1)
-	*p1 = nil
-	*p2 = mergeBuff
-	return 0, nil
+	p1, p2 := (*partialResult4ApproxPercentileReal)(src), (*partialResult4ApproxPercentileReal)(dst)
+	startMem := p2.MemSize()
+	p2.merge(p1)
+	*p1 = partialResult4ApproxPercentileReal{}
+	return p2.MemSize() - startMem, nil
 }
 
 func (e *percentileOriginal4Real) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
-	p := (*partialResult4PercentileReal)(pr)
-	if len(*p) == 0 {
+	p := (*partialResult4ApproxPercentileReal)(pr)
+	if p.digest != nil {
+		chk.AppendFloat64(e.ordinal, p.digest.Quantile(float64(e.percent)/100))
+		return nil
+	}
+	if len(p.values) == 0 {
 		chk.AppendNull(e.ordinal)
 		return nil
 	}
-	index := percentile(*p, e.percent)
-	chk.AppendFloat64(e.ordinal, (*p)[index])
+	index := percentile(p.values, e.percent)
+	chk.AppendFloat64(e.ordinal, p.values[index])
 	return nil
 }
 
@@ -14,12 +14,17 @@
 package aggfuncs_test
 
 import (
+	"math"
 	"time"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/executor/aggfuncs"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 )
 
 func (s *testSuite) TestPercentile(c *C) {
@@ -35,3 +40,55 @@ func (s *testSuite) TestPercentile(c *C) {
 		s.testAggFunc(c, test)
 	}
 }
+
+func (s *testSuite) TestApproxPercentileDigest(c *C) {
+	const numRows = 100000
+	for _, tp := range []byte{mysql.TypeLonglong, mysql.TypeDouble} {
+		ft := types.NewFieldType(tp)
+		args := []expression.Expression{
+			&expression.Column{RetType: ft, Index: 0},
+			&expression.Constant{Value: types.NewIntDatum(90), RetType: types.NewFieldType(mysql.TypeLong)},
+		}
+		desc, err := aggregation.NewAggFuncDesc(s.ctx, ast.AggFuncApproxPercentile, args, false)
+		c.Assert(err, IsNil)
+		partialDesc, finalDesc := desc.Split([]int{0, 1})
+		partialFunc := aggfuncs.Build(s.ctx, partialDesc, 0)
+		finalFunc := aggfuncs.Build(s.ctx, finalDesc, 0)
+		finalPr, _ := finalFunc.AllocPartialResult()
+
+		// The values are summarized by several partial workers.
+		dataGen := getDataGenFunc(ft)
+		var memUsage int64
+		for worker := 0; worker < 4; worker++ {
+			srcChk := chunk.NewChunkWithCapacity([]*types.FieldType{ft}, numRows/4)
+			for i := worker; i < numRows; i += 4 {
+				d := dataGen(i)
+				srcChk.AppendDatum(0, &d)
+			}
+			rows := make([]chunk.Row, 0, srcChk.NumRows())
+			for i := 0; i < srcChk.NumRows(); i++ {
+				rows = append(rows, srcChk.GetRow(i))
+			}
+			partialPr, memDelta := partialFunc.AllocPartialResult()
+			memUsage += memDelta
+			memDelta, err = partialFunc.UpdatePartialResult(s.ctx, rows, partialPr)
+			c.Assert(err, IsNil)
+			memUsage += memDelta
+			memDelta, err = finalFunc.MergePartialResult(s.ctx, partialPr, finalPr)
+			c.Assert(err, IsNil)
+			memUsage += memDelta
+		}
+		// The memory is bounded by the digest instead of the number of the values.
+		c.Assert(memUsage < numRows*8/4, IsTrue, Commentf("memory usage %d", memUsage))
+
+		resultChk := chunk.NewChunkWithCapacity([]*types.FieldType{desc.RetTp}, 1)
+		c.Assert(finalFunc.AppendFinalResult2Chunk(s.ctx, finalPr, resultChk), IsNil)
+		var v float64
+		if tp == mysql.TypeLonglong {
+			v = float64(resultChk.GetRow(0).GetInt64(0))
+		} else {
+			v = resultChk.GetRow(0).GetFloat64(0)
+		}
+		c.Assert(math.Abs(v-numRows*0.9) < numRows*0.01, IsTrue, Commentf("the 90th percentile %v", v))
+	}
+}
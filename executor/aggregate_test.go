@@ -661,6 +661,37 @@ func (s *testSuiteAgg) TestGroupConcatAggr(c *C) {
 	rows.Check(testkit.Rows("01234567", "12345"))
 }
 
+func (s *testSuiteAgg) TestParallelGroupConcatOrder(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c varchar(10))")
+	vals := make([]string, 0, 300)
+	for i := 0; i < 300; i++ {
+		vals = append(vals, fmt.Sprintf("(%d, %d, '%d')", i%3, (i*7)%300, i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(vals, ","))
+	tk.MustExec("set @@tidb_init_chunk_size = 1")
+
+	sqls := []string{
+		"select a, group_concat(c order by b desc separator '-') from t group by a order by a",
+		"select a, group_concat(b, c order by c, b) from t group by a order by a",
+		"select group_concat(c order by b) from t",
+	}
+	for _, maxLen := range []int{1024, 40, 7} {
+		tk.MustExec(fmt.Sprintf("set @@group_concat_max_len = %d", maxLen))
+		for _, sql := range sqls {
+			tk.MustExec("set @@tidb_hashagg_partial_concurrency = 1, @@tidb_hashagg_final_concurrency = 1")
+			expected := tk.MustQuery(sql).Rows()
+			tk.MustExec("set @@tidb_hashagg_partial_concurrency = 4, @@tidb_hashagg_final_concurrency = 4")
+			tk.MustQuery(sql).Check(expected)
+		}
+	}
+	// The hash aggregation is executed in parallel.
+	rows := tk.MustQuery("explain analyze " + sqls[0]).Rows()
+	c.Assert(strings.Contains(fmt.Sprintf("%v", rows), "partial_worker"), IsTrue)
+}
+
 func (s *testSuiteAgg) TestSelectDistinct(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
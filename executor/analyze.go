@@ -721,6 +721,19 @@ type AnalyzeColumnsExec struct {
 	baseModifyCnt           int64
 }
 
+// columnBucketNum returns the bucket number of the column histogram, which is either specified for the column, or
+// selected adaptively from the samples, or the one in the analyze options.
+func (e *AnalyzeColumnsExec) columnBucketNum(colID int64, collector *statistics.SampleCollector) (int, error) {
+	if numBuckets, ok := e.ColumnBuckets[colID]; ok {
+		return int(numBuckets), nil
+	}
+	numBuckets := int(e.opts[ast.AnalyzeOptNumBuckets])
+	if !e.AdaptiveBuckets {
+		return numBuckets, nil
+	}
+	return statistics.AdaptiveBucketCount(e.ctx.GetSessionVars().StmtCtx, collector.Samples, numBuckets)
+}
+
 func (e *AnalyzeColumnsExec) open(ranges []*ranger.Range) error {
 	e.resultHandler = &tableResultHandler{}
 	firstPartRanges, secondPartRanges := distsql.SplitRangesAcrossInt64Boundary(ranges, true, false, !hasPkHist(e.handleCols))
@@ -1323,10 +1336,17 @@ workLoop:
 				TotalSize: task.rootRowCollector.TotalSizes[task.slicePos],
 			}
 		}
+		numBuckets := int(e.opts[ast.AnalyzeOptNumBuckets])
 		if task.isColumn {
 			collectors[task.slicePos] = collector
+			var err error
+			numBuckets, err = e.columnBucketNum(task.id, collector)
+			if err != nil {
+				resultCh <- err
+				continue
+			}
 		}
-		hist, topn, err := statistics.BuildHistAndTopN(e.ctx, int(e.opts[ast.AnalyzeOptNumBuckets]), int(e.opts[ast.AnalyzeOptNumTopN]), task.id, collector, task.tp, task.isColumn)
+		hist, topn, err := statistics.BuildHistAndTopN(e.ctx, numBuckets, int(e.opts[ast.AnalyzeOptNumTopN]), task.id, collector, task.tp, task.isColumn)
 		if err != nil {
 			resultCh <- err
 			continue
@@ -1452,12 +1472,15 @@ func (e *AnalyzeColumnsExec) buildStats(ranges []*ranger.Range, needExtStats boo
 			}
 		}
 		var hg *statistics.Histogram
-		var err error
 		var topn *statistics.TopN
+		numBuckets, err := e.columnBucketNum(col.ID, collectors[i])
+		if err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
 		if e.StatsVersion < 2 {
-			hg, err = statistics.BuildColumn(e.ctx, int64(e.opts[ast.AnalyzeOptNumBuckets]), col.ID, collectors[i], &col.FieldType)
+			hg, err = statistics.BuildColumn(e.ctx, int64(numBuckets), col.ID, collectors[i], &col.FieldType)
 		} else {
-			hg, topn, err = statistics.BuildHistAndTopN(e.ctx, int(e.opts[ast.AnalyzeOptNumBuckets]), int(e.opts[ast.AnalyzeOptNumTopN]), col.ID, collectors[i], &col.FieldType, true)
+			hg, topn, err = statistics.BuildHistAndTopN(e.ctx, numBuckets, int(e.opts[ast.AnalyzeOptNumTopN]), col.ID, collectors[i], &col.FieldType, true)
 			topNs = append(topNs, topn)
 		}
 		if err != nil {
@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
@@ -221,6 +222,52 @@ func (s *testSuite1) TestAnalyzeParameters(c *C) {
 	c.Assert(width, Equals, int32(20480))
 }
 
+func (s *testSuite1) TestAnalyzeAdaptiveBuckets(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	// The column a is uniform, while half of the column b is 0.
+	values := make([]string, 0, 2000)
+	for i := 0; i < 2000; i++ {
+		b := 0
+		if i%2 == 1 {
+			b = i
+		}
+		values = append(values, fmt.Sprintf("(%d, %d)", i, b))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	tk.MustExec("set @@tidb_analyze_version = 1")
+	is := tk.Se.(sessionctx.Context).GetInfoSchema().(infoschema.InfoSchema)
+	table, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tableInfo := table.Meta()
+	getBucketNums := func() (int, int) {
+		tbl := s.dom.StatsHandle().GetTableStats(tableInfo)
+		return tbl.Columns[tableInfo.Columns[0].ID].Len(), tbl.Columns[tableInfo.Columns[1].ID].Len()
+	}
+
+	tk.MustExec("analyze table t with 0 topn")
+	fixedA, fixedB := getBucketNums()
+	tk.MustExec("set @@tidb_analyze_adaptive_buckets = 1")
+	defer tk.MustExec("set @@tidb_analyze_adaptive_buckets = default")
+	tk.MustExec("analyze table t with 0 topn")
+	adaptiveA, adaptiveB := getBucketNums()
+	c.Assert(adaptiveA, Less, fixedA)
+	c.Assert(adaptiveB, Greater, fixedB)
+
+	// The bucket number specified by the user is used.
+	tk.MustExec("analyze table t with 4 buckets, 0 topn")
+	numA, numB := getBucketNums()
+	c.Assert(numA, Equals, 4)
+	c.Assert(numB, LessEqual, 4)
+	tk.MustExec("analyze table t update histogram on a with 8 buckets, 0 topn")
+	numA, numB = getBucketNums()
+	c.Assert(numA, Equals, 8)
+	c.Assert(numB, Greater, fixedB)
+	tk.MustGetErrCode("analyze table t update histogram on c with 8 buckets", errno.ErrBadField)
+}
+
 func (s *testSuite1) TestAnalyzeTooLongColumns(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -182,7 +182,7 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 	case *plannercore.PhysicalShow:
 		return b.buildShow(v)
 	case *plannercore.Simple:
-		return b.buildSimple(v)
+		return b.wrapPrivilegeChangeRecorder(v.Statement, b.buildSimple(v))
 	case *plannercore.PhysicalSimpleWrapper:
 		return b.buildSimple(&v.Inner)
 	case *plannercore.Set:
@@ -1083,6 +1083,9 @@ func (b *executorBuilder) buildMergeJoin(v *plannercore.PhysicalMergeJoin) Execu
 			defaultValues = make([]types.Datum, rightExec.Schema().Len())
 		}
 	}
+	if v.Concurrency > 1 {
+		return b.buildParallelMergeJoin(v, leftExec, rightExec, defaultValues)
+	}
 
 	e := &MergeJoinExec{
 		stmtCtx:      b.ctx.GetSessionVars().StmtCtx,
@@ -1133,6 +1136,38 @@ func (b *executorBuilder) buildMergeJoin(v *plannercore.PhysicalMergeJoin) Execu
 	return e
 }
 
+func (b *executorBuilder) buildParallelMergeJoin(v *plannercore.PhysicalMergeJoin, leftExec, rightExec Executor, defaultValues []types.Datum) Executor {
+	e := &ParallelMergeJoinExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), leftExec, rightExec),
+		concurrency:  v.Concurrency,
+		compareFuncs: v.CompareFuncs,
+		joiners:      make([]joiner, v.Concurrency),
+		desc:         v.Desc,
+	}
+	childrenUsedSchema := markChildrenUsedCols(v.Schema(), v.Children()[0].Schema(), v.Children()[1].Schema())
+	for i := range e.joiners {
+		e.joiners[i] = newJoiner(b.ctx, v.JoinType, v.JoinType == plannercore.RightOuterJoin, defaultValues,
+			v.OtherConditions, retTypes(leftExec), retTypes(rightExec), childrenUsedSchema)
+	}
+	innerFilters := v.RightConditions
+	if v.JoinType == plannercore.RightOuterJoin {
+		e.outerIdx = 1
+		e.outerKeys, e.innerKeys = v.RightJoinKeys, v.LeftJoinKeys
+		e.outerFilters, innerFilters = v.RightConditions, v.LeftConditions
+	} else {
+		e.outerKeys, e.innerKeys = v.LeftJoinKeys, v.RightJoinKeys
+		e.outerFilters = v.LeftConditions
+	}
+	// optimizer should guarantee that filters on inner table are pushed down
+	// to tikv or extracted to a Selection.
+	if len(innerFilters) != 0 {
+		b.err = errors.Annotate(ErrBuildExecutor, "merge join's inner filter should be empty.")
+		return nil
+	}
+	executorCounterMergeJoinExec.Inc()
+	return e
+}
+
 func (b *executorBuilder) buildSideEstCount(v *plannercore.PhysicalHashJoin) float64 {
 	buildSide := v.Children()[v.InnerChildIdx]
 	if v.UseOuterToBuild {
@@ -1219,11 +1254,26 @@ func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) Executo
 		}
 	}
 	e.buildSideEstCount = b.buildSideEstCount(v)
+	otherConditions := v.OtherConditions
+	if v.IsNullAware {
+		// The null-aware equal conditions are evaluated by the joiners along with the other conditions to get the
+		// null semantics of `not in`, so they are resolved on the joined rows here.
+		e.isNullAware = true
+		e.naKeyOnly = len(v.EqualConditions) == 1 && len(v.OtherConditions) == 0
+		lLen := v.Children()[0].Schema().Len()
+		otherConditions = make([]expression.Expression, 0, len(v.EqualConditions)+len(v.OtherConditions))
+		for i, cond := range v.EqualConditions {
+			rCol := v.RightJoinKeys[i].Clone().(*expression.Column)
+			rCol.Index += lLen
+			otherConditions = append(otherConditions, expression.NewFunctionInternal(b.ctx, cond.FuncName.L, cond.GetType(), v.LeftJoinKeys[i], rCol))
+		}
+		otherConditions = append(otherConditions, v.OtherConditions...)
+	}
 	childrenUsedSchema := markChildrenUsedCols(v.Schema(), v.Children()[0].Schema(), v.Children()[1].Schema())
 	e.joiners = make([]joiner, e.concurrency)
 	for i := uint(0); i < e.concurrency; i++ {
 		e.joiners[i] = newJoiner(b.ctx, v.JoinType, v.InnerChildIdx == 0, defaultValues,
-			v.OtherConditions, lhsTypes, rhsTypes, childrenUsedSchema)
+			otherConditions, lhsTypes, rhsTypes, childrenUsedSchema)
 	}
 	executorCountHashJoinExec.Inc()
 
@@ -1245,6 +1295,9 @@ func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) Executo
 	for _, key := range e.probeKeys {
 		e.probeTypes[key.Index].Flag = key.RetType.Flag
 	}
+	if b.ctx.GetSessionVars().UseDynamicPartitionPrune() {
+		e.probePruner = b.buildProbePartitionPruner(e)
+	}
 	return e
 }
 
@@ -1285,7 +1338,7 @@ func (b *executorBuilder) buildHashAgg(v *plannercore.PhysicalHashAgg) Executor
 		e.defaultVal = chunk.NewChunkWithCapacity(retTypes(e), 1)
 	}
 	for _, aggDesc := range v.AggFuncs {
-		if aggDesc.HasDistinct || len(aggDesc.OrderByItems) > 0 {
+		if aggDesc.HasDistinct {
 			e.isUnparallelExec = true
 		}
 	}
@@ -1582,7 +1635,16 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 			strings.ToLower(infoschema.ClusterTableTiDBTrx),
 			strings.ToLower(infoschema.TableDeadlocks),
 			strings.ToLower(infoschema.ClusterTableDeadlocks),
-			strings.ToLower(infoschema.TableDataLockWaits):
+			strings.ToLower(infoschema.TableDataLockWaits),
+			strings.ToLower(infoschema.TableKeywords),
+			strings.ToLower(infoschema.TableSQLFeatures),
+			strings.ToLower(infoschema.TableTransactionSummary),
+			strings.ToLower(infoschema.TableUserAttributes),
+			strings.ToLower(infoschema.TableTiDBIndexUsage),
+			strings.ToLower(infoschema.TableWriteConflicts),
+			strings.ToLower(infoschema.TableViewTableUsage),
+			strings.ToLower(infoschema.TableTiDBViewValidity),
+			strings.ToLower(infoschema.TableTiDBEffectivePrivileges):
 			return &MemTableReaderExec{
 				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
 				table:        v.Table,
@@ -2497,6 +2559,10 @@ func constructDAGReq(ctx sessionctx.Context, plans []plannercore.PhysicalPlan, s
 		dagReq.CollectExecutionSummaries = &collExec
 	}
 	dagReq.Flags = sc.PushDownFlags()
+	// Pass the SQL mode down so the pushed down expressions follow the same
+	// modes as TiDB, e.g. NO_ZERO_DATE and NO_UNSIGNED_SUBTRACTION.
+	sqlMode := uint64(ctx.GetSessionVars().SQLMode)
+	dagReq.SqlMode = &sqlMode
 	if storeType == kv.TiFlash {
 		var executors []*tipb.Executor
 		executors, streaming, err = constructDistExecForTiFlash(ctx, plans[0])
@@ -2887,6 +2953,7 @@ func (b *executorBuilder) buildTableReader(v *plannercore.PhysicalTableReader) E
 	}
 
 	ret.ranges = ts.Ranges
+	ret.estChecker = newEstDeviationChecker(b.ctx, v)
 	sctx := b.ctx.GetSessionVars().StmtCtx
 	sctx.TableIDs = append(sctx.TableIDs, ts.Table.ID)
 
@@ -3099,6 +3166,22 @@ func buildNoRangeIndexReader(b *executorBuilder, v *plannercore.PhysicalIndexRea
 		dagReq.OutputOffsets = append(dagReq.OutputOffsets, uint32(col.Index))
 	}
 
+	if is.MergePrefixLen > 0 {
+		e.mergePrefixLen = is.MergePrefixLen
+		e.mergeKeyOffsets = make([]int, 0, len(is.ByItems))
+		for _, item := range is.ByItems {
+			col, ok := item.Expr.(*expression.Column)
+			offset := -1
+			if ok {
+				offset = v.Schema().ColumnIndex(col)
+			}
+			if offset == -1 {
+				return nil, errors.Errorf("can't find the merge column %s in the schema of the index reader", item.Expr.String())
+			}
+			e.mergeKeyOffsets = append(e.mergeKeyOffsets, offset)
+		}
+	}
+
 	return e, nil
 }
 
@@ -3116,6 +3199,7 @@ func (b *executorBuilder) buildIndexReader(v *plannercore.PhysicalIndexReader) E
 	}
 
 	ret.ranges = is.Ranges
+	ret.estChecker = newEstDeviationChecker(b.ctx, v)
 	sctx := b.ctx.GetSessionVars().StmtCtx
 	sctx.IndexNames = append(sctx.IndexNames, is.Table.Name.O+":"+is.Index.Name.O)
 
@@ -3277,6 +3361,7 @@ func (b *executorBuilder) buildIndexLookUpReader(v *plannercore.PhysicalIndexLoo
 	ts := v.TablePlans[0].(*plannercore.PhysicalTableScan)
 
 	ret.ranges = is.Ranges
+	ret.estChecker = newEstDeviationChecker(b.ctx, v)
 	executorCounterIndexLookUpExecutor.Inc()
 
 	sctx := b.ctx.GetSessionVars().StmtCtx
@@ -3365,6 +3450,7 @@ func buildNoRangeIndexMergeReader(b *executorBuilder, v *plannercore.PhysicalInd
 		columns:           ts.Columns,
 		partialStreamings: partialStreamings,
 		tableStreaming:    tableStreaming,
+		isIntersection:    v.IsIntersectionType,
 		partialPlans:      v.PartialPlans,
 		tblPlans:          v.TablePlans,
 		dataReaderBuilder: &dataReaderBuilder{executorBuilder: b},
@@ -16,6 +16,7 @@ package executor
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/failpoint"
@@ -26,6 +27,9 @@ import (
 	"github.com/pingcap/tidb/planner"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
 )
 
 var (
@@ -78,7 +82,7 @@ func (c *Compiler) Compile(ctx context.Context, stmtNode ast.StmtNode) (*ExecStm
 	if c.Ctx.GetSessionVars().StmtCtx.Priority == mysql.NoPriority {
 		lowerPriority = needLowerPriority(finalPlan)
 	}
-	return &ExecStmt{
+	stmt := &ExecStmt{
 		GoCtx:         ctx,
 		SnapshotTS:    ret.LastSnapshotTS,
 		IsStaleness:   ret.IsStaleness,
@@ -91,7 +95,95 @@ func (c *Compiler) Compile(ctx context.Context, stmtNode ast.StmtNode) (*ExecStm
 		Ctx:           c.Ctx,
 		OutputNames:   names,
 		Ti:            &TelemetryInfo{},
-	}, nil
+	}
+	if err = checkMaxEstimatedCost(ctx, stmt); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// checkMaxEstimatedCost rejects or logs the statement whose estimated cost exceeds tidb_max_estimated_cost, or
+// whose estimated row count exceeds tidb_max_estimated_rows, according to tidb_max_estimated_cost_action.
+func checkMaxEstimatedCost(ctx context.Context, stmt *ExecStmt) error {
+	sessVars := stmt.Ctx.GetSessionVars()
+	if (sessVars.MaxEstimatedCost <= 0 && sessVars.MaxEstimatedRows <= 0) || sessVars.InRestrictedSQL {
+		return nil
+	}
+	physicalPlan := getCostBasedPhysicalPlan(stmt.Plan)
+	if physicalPlan == nil {
+		return nil
+	}
+	// The cost and the row count are read from the chosen plan, so that the cached plans are checked as well.
+	cost, rows := getEstimatedCostAndRows(physicalPlan)
+	var err error
+	if sessVars.MaxEstimatedCost > 0 && cost > sessVars.MaxEstimatedCost {
+		err = ErrMaxEstimatedCostExceeded.GenWithStackByArgs(cost, sessVars.MaxEstimatedCost)
+	} else if sessVars.MaxEstimatedRows > 0 && rows > float64(sessVars.MaxEstimatedRows) {
+		err = ErrMaxEstimatedRowsExceeded.GenWithStackByArgs(rows, sessVars.MaxEstimatedRows)
+	}
+	if err == nil {
+		return nil
+	}
+	if sessVars.MaxEstimatedCostAction == variable.MaxEstimatedCostActionCancel {
+		return err
+	}
+	logutil.Logger(ctx).Warn("estimated cost of the statement exceeds the limit",
+		zap.Uint64("conn", sessVars.ConnectionID),
+		zap.Float64("cost", cost),
+		zap.Float64("max-cost", sessVars.MaxEstimatedCost),
+		zap.Float64("rows", rows),
+		zap.Int64("max-rows", sessVars.MaxEstimatedRows),
+		zap.Stringer("sql", FormatSQL(stmt.GetTextToLog())))
+	sessVars.StmtCtx.AppendWarning(err)
+	return nil
+}
+
+// getCostBasedPhysicalPlan returns the physical plan which reads data and is planned by the
+// cost-based optimizer, so that its estimated cost should be checked. It returns nil if there is no such plan.
+func getCostBasedPhysicalPlan(p plannercore.Plan) plannercore.PhysicalPlan {
+	switch x := p.(type) {
+	case plannercore.PhysicalPlan:
+		return x
+	case *plannercore.Execute:
+		return getCostBasedPhysicalPlan(x.Plan)
+	case *plannercore.Insert:
+		return x.SelectPlan
+	case *plannercore.Delete:
+		return x.SelectPlan
+	case *plannercore.Update:
+		return x.SelectPlan
+	}
+	return nil
+}
+
+// getEstimatedCostAndRows returns the estimated cost and the max estimated row count of the operators in the
+// physical plan. The cost of an operator includes the cost of its children, but the operators added after the
+// optimization, e.g. the projections, don't carry a cost, so the max cost in the plan tree is used.
+func getEstimatedCostAndRows(p plannercore.PhysicalPlan) (cost float64, rows float64) {
+	cost = p.Cost()
+	if p.Stats() != nil {
+		rows = p.StatsCount()
+	}
+	children := p.Children()
+	switch x := p.(type) {
+	case *plannercore.PhysicalTableReader:
+		children = x.TablePlans
+	case *plannercore.PhysicalIndexReader:
+		children = x.IndexPlans
+	case *plannercore.PhysicalIndexLookUpReader:
+		children = append(append([]plannercore.PhysicalPlan{}, x.IndexPlans...), x.TablePlans...)
+	case *plannercore.PhysicalIndexMergeReader:
+		children = append([]plannercore.PhysicalPlan{}, x.TablePlans...)
+		for _, partialPlans := range x.PartialPlans {
+			children = append(children, partialPlans...)
+		}
+	}
+	for _, child := range children {
+		childCost, childRows := getEstimatedCostAndRows(child)
+		cost = math.Max(cost, childCost)
+		rows = math.Max(rows, childRows)
+	}
+	return cost, rows
 }
 
 // needLowerPriority checks whether it's needed to lower the execution priority
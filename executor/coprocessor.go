@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/kvproto/pkg/coprocessor"
 	"github.com/pingcap/kvproto/pkg/tikvpb"
 	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/planner/core"
@@ -159,6 +160,10 @@ func (h *CoprocessorDAGHandler) buildDAGExecutor(req *coprocessor.Request) (Exec
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if dagReq.SqlMode != nil {
+		stmtCtx.SQLMode = mysql.SQLMode(dagReq.GetSqlMode())
+		h.sctx.GetSessionVars().SQLMode = stmtCtx.SQLMode
+	}
 	h.dagReq = dagReq
 	is := h.sctx.GetInfoSchema().(infoschema.InfoSchema)
 	// Build physical plan.
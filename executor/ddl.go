@@ -75,6 +75,10 @@ func (e *DDLExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 	}
 	e.done = true
 
+	if err = e.checkDDLPolicy(ctx); err != nil {
+		return err
+	}
+
 	// For each DDL, we should commit the previous transaction and create a new transaction.
 	// An exception is create local temporary table.
 	if s, ok := e.stmt.(*ast.CreateTableStmt); ok {
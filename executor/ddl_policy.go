@@ -0,0 +1,173 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	filter "github.com/pingcap/tidb-tools/pkg/table-filter"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/timeutil"
+	"go.uber.org/zap"
+)
+
+// ddlObject is a schema or a table touched by a DDL statement, table is empty for the schema level DDL.
+type ddlObject struct {
+	schema string
+	table  string
+}
+
+func (o ddlObject) String() string {
+	if o.table == "" {
+		return o.schema
+	}
+	return o.schema + "." + o.table
+}
+
+// getDDLObjects returns the objects touched by the DDL statement.
+func getDDLObjects(stmt ast.StmtNode, currentDB string) []ddlObject {
+	var objects []ddlObject
+	addTable := func(tn *ast.TableName) {
+		if tn == nil {
+			return
+		}
+		schema := tn.Schema.O
+		if schema == "" {
+			schema = currentDB
+		}
+		objects = append(objects, ddlObject{schema: schema, table: tn.Name.O})
+	}
+	switch x := stmt.(type) {
+	case *ast.AlterDatabaseStmt:
+		if x.AlterDefaultDatabase {
+			objects = append(objects, ddlObject{schema: currentDB})
+		} else {
+			objects = append(objects, ddlObject{schema: x.Name})
+		}
+	case *ast.CreateDatabaseStmt:
+		objects = append(objects, ddlObject{schema: x.Name})
+	case *ast.DropDatabaseStmt:
+		objects = append(objects, ddlObject{schema: x.Name})
+	case *ast.AlterTableStmt:
+		addTable(x.Table)
+		for _, spec := range x.Specs {
+			// EXCHANGE PARTITION and RENAME TO touch another table.
+			if spec.Tp == ast.AlterTableExchangePartition || spec.Tp == ast.AlterTableRenameTable {
+				addTable(spec.NewTable)
+			}
+		}
+	case *ast.CreateIndexStmt:
+		addTable(x.Table)
+	case *ast.CreateTableStmt:
+		addTable(x.Table)
+	case *ast.CreateViewStmt:
+		addTable(x.ViewName)
+	case *ast.DropIndexStmt:
+		addTable(x.Table)
+	case *ast.DropTableStmt:
+		for _, tn := range x.Tables {
+			addTable(tn)
+		}
+	case *ast.RecoverTableStmt:
+		addTable(x.Table)
+	case *ast.FlashBackTableStmt:
+		addTable(x.Table)
+	case *ast.RenameTableStmt:
+		for _, t2t := range x.TableToTables {
+			addTable(t2t.OldTable)
+			addTable(t2t.NewTable)
+		}
+	case *ast.TruncateTableStmt:
+		addTable(x.Table)
+	case *ast.RepairTableStmt:
+		addTable(x.Table)
+	case *ast.CreateSequenceStmt:
+		addTable(x.Name)
+	case *ast.DropSequenceStmt:
+		for _, tn := range x.Sequences {
+			addTable(tn)
+		}
+	case *ast.AlterSequenceStmt:
+		addTable(x.Name)
+	}
+	return objects
+}
+
+// inTimeWindow checks whether the time of the day is in [start, end), the window wraps around midnight if start > end.
+func inTimeWindow(now, start, end time.Duration) bool {
+	if start <= end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+// checkDDLPolicy returns an error if a policy in mysql.ddl_policy forbids the DDL on the objects at the current time,
+// the users with the DDL_BREAK_GLASS privilege (implied by SUPER as the other dynamic privileges) are not restricted.
+func (e *DDLExec) checkDDLPolicy(ctx context.Context) error {
+	sessVars := e.ctx.GetSessionVars()
+	if sessVars.InRestrictedSQL || sessVars.User == nil {
+		return nil
+	}
+	objects := getDDLObjects(e.stmt, sessVars.CurrentDB)
+	if len(objects) == 0 {
+		return nil
+	}
+	checker := privilege.GetPrivilegeManager(e.ctx)
+	if checker == nil || checker.RequestDynamicVerification(sessVars.ActiveRoles, "DDL_BREAK_GLASS", false) {
+		return nil
+	}
+
+	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(ctx, "SELECT ID, OBJECT, START_TIME, END_TIME FROM mysql.ddl_policy")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		// The table doesn't exist before the cluster is upgraded.
+		if infoschema.ErrTableNotExists.Equal(err) {
+			return nil
+		}
+		return errors.Trace(err)
+	}
+	// The window is in the time zone of the server, so it can't be skipped by changing the session time zone.
+	now := time.Now().In(timeutil.SystemLocation())
+	timeOfDay := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute +
+		time.Duration(now.Second())*time.Second
+	for _, row := range rows {
+		start, end := row.GetDuration(2, 0), row.GetDuration(3, 0)
+		if !inTimeWindow(timeOfDay, start.Duration, end.Duration) {
+			continue
+		}
+		f, err := filter.Parse([]string{row.GetString(1)})
+		if err != nil {
+			logutil.Logger(ctx).Warn("invalid object of the DDL policy", zap.Int64("id", row.GetInt64(0)),
+				zap.String("object", row.GetString(1)), zap.Error(err))
+			continue
+		}
+		f = filter.CaseInsensitive(f)
+		for _, obj := range objects {
+			if (obj.table == "" && f.MatchSchema(obj.schema)) || (obj.table != "" && f.MatchTable(obj.schema, obj.table)) {
+				return ErrDDLPolicyViolated.GenWithStackByArgs(obj.String(), start.String(), end.String(), row.GetInt64(0))
+			}
+		}
+	}
+	return nil
+}
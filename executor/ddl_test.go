@@ -23,6 +23,7 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
@@ -31,11 +32,13 @@ import (
 	ddlutil "github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/meta/autoid"
 	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/table"
@@ -1532,3 +1535,58 @@ func (s *testRecoverTable) TestRenameMultiTables(c *C) {
 	tk.MustExec("drop database rename2")
 	tk.MustExec("drop database rename3")
 }
+
+func (s *testSuite6) TestDDLPolicy(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("drop database if exists ddl_prod")
+	tk.MustExec("create database ddl_prod")
+	tk.MustExec("drop user if exists 'ddl_user'@'%'")
+	tk.MustExec("create user 'ddl_user'@'%'")
+	// The SUPER privilege implies DDL_BREAK_GLASS like the other dynamic privileges.
+	tk.MustExec("grant create, drop, alter, insert on *.* to 'ddl_user'@'%'")
+	tk.MustExec("insert into mysql.ddl_policy (OBJECT, START_TIME, END_TIME) values ('ddl_prod.*', '00:00:00', '24:00:00')")
+	defer tk.MustExec("delete from mysql.ddl_policy")
+
+	tk1 := testkit.NewTestKit(c, s.store)
+	se, err := session.CreateSession4Test(s.store)
+	c.Assert(err, IsNil)
+	c.Assert(se.Auth(&auth.UserIdentity{Username: "ddl_user", Hostname: "%"}, nil, nil), IsTrue)
+	tk1.Se = se
+	for _, sql := range []string{
+		"create table ddl_prod.t (a int)",
+		"drop database ddl_prod",
+		"rename table test.t_policy to ddl_prod.t_policy",
+		"alter table test.t_policy rename to ddl_prod.t_policy",
+		"alter table test.t_policy exchange partition p0 with table ddl_prod.t_policy",
+	} {
+		err := tk1.ExecToErr(sql)
+		c.Assert(executor.ErrDDLPolicyViolated.Equal(err), IsTrue, Commentf("err %v", err))
+	}
+	// The objects not matched by the policy are not restricted.
+	tk1.MustExec("create table test.t_policy (a int)")
+	tk1.MustExec("drop table test.t_policy")
+	start := time.Now().Add(2 * time.Hour).Format("15:04:05")
+	end := time.Now().Add(3 * time.Hour).Format("15:04:05")
+	tk.MustExec(fmt.Sprintf("update mysql.ddl_policy set START_TIME = '%s', END_TIME = '%s'", start, end))
+	// The DDL is allowed out of the time window.
+	tk1.MustExec("create table ddl_prod.t (a int)")
+	// The window wraps around midnight.
+	tk.MustExec(fmt.Sprintf("update mysql.ddl_policy set START_TIME = '%s', END_TIME = '%s'", end, start))
+	err = tk1.ExecToErr("drop table ddl_prod.t")
+	c.Assert(executor.ErrDDLPolicyViolated.Equal(err), IsTrue, Commentf("err %v", err))
+	// The window is not in the session time zone, in which the DDL would be out of the window.
+	_, offset := time.Now().Zone()
+	offset = (offset/60+150+12*60)%(24*60) - 12*60
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	tk1.MustExec(fmt.Sprintf("set @@time_zone = '%s%02d:%02d'", sign, offset/60, offset%60))
+	err = tk1.ExecToErr("drop table ddl_prod.t")
+	c.Assert(executor.ErrDDLPolicyViolated.Equal(err), IsTrue, Commentf("err %v", err))
+
+	tk.MustExec("grant DDL_BREAK_GLASS on *.* to 'ddl_user'@'%'")
+	tk1.MustExec("drop table ddl_prod.t")
+	tk1.MustExec("drop database ddl_prod")
+	tk.MustExec("drop user 'ddl_user'@'%'")
+}
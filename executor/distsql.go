@@ -178,11 +178,17 @@ type IndexReaderExecutor struct {
 	// outputColumns are only required by union scan.
 	outputColumns []*expression.Column
 
-	feedback  *statistics.QueryFeedback
-	streaming bool
+	feedback   *statistics.QueryFeedback
+	estChecker *estDeviationChecker
+	streaming  bool
 
 	keepOrder bool
 	desc      bool
+	// mergePrefixLen and mergeKeyOffsets are set when the ranges are grouped by the values of the first mergePrefixLen
+	// index columns, the groups are read separately and the rows are merged by the columns of mergeKeyOffsets.
+	mergePrefixLen  int
+	mergeKeyOffsets []int
+	kvRangeGroups   [][]kv.KeyRange
 
 	corColInFilter bool
 	corColInAccess bool
@@ -204,6 +210,9 @@ func (e *IndexReaderExecutor) Close() error {
 	err := e.result.Close()
 	e.result = nil
 	e.ctx.StoreQueryFeedback(e.feedback)
+	if e.table != nil && e.index != nil {
+		e.storeIndexUsage(e.table.Meta().ID, e.index.ID)
+	}
 	return err
 }
 
@@ -217,8 +226,10 @@ func (e *IndexReaderExecutor) Next(ctx context.Context, req *chunk.Chunk) error
 	err := e.result.Next(ctx, req)
 	if err != nil {
 		e.feedback.Invalidate()
+		return err
 	}
-	return err
+	e.estChecker.check(e.ctx, e.table, req.NumRows())
+	return nil
 }
 
 func (e *IndexReaderExecutor) buildKeyRanges(sc *stmtctx.StatementContext, ranges []*ranger.Range, physicalID int64) ([]kv.KeyRange, error) {
@@ -230,6 +241,7 @@ func (e *IndexReaderExecutor) buildKeyRanges(sc *stmtctx.StatementContext, range
 
 // Open implements the Executor Open interface.
 func (e *IndexReaderExecutor) Open(ctx context.Context) error {
+	e.estChecker.reset()
 	var err error
 	if e.corColInAccess {
 		e.ranges, err = rebuildIndexRanges(e.ctx, e.plans[0].(*plannercore.PhysicalIndexScan), e.idxCols, e.colLens)
@@ -252,6 +264,8 @@ func (e *IndexReaderExecutor) Open(ctx context.Context) error {
 			}
 			kvRanges = append(kvRanges, kvRange...)
 		}
+	} else if e.mergePrefixLen > 0 {
+		kvRanges, err = e.buildKVRangeGroups(sc)
 	} else {
 		kvRanges, err = e.buildKeyRanges(sc, e.ranges, e.physicalTableID)
 	}
@@ -262,6 +276,27 @@ func (e *IndexReaderExecutor) Open(ctx context.Context) error {
 	return e.open(ctx, kvRanges)
 }
 
+// buildKVRangeGroups builds the key ranges of each range group, and returns all the key ranges.
+func (e *IndexReaderExecutor) buildKVRangeGroups(sc *stmtctx.StatementContext) ([]kv.KeyRange, error) {
+	groups, err := ranger.GroupRangesByPrefix(sc, e.ranges, e.mergePrefixLen)
+	if err != nil {
+		return nil, err
+	}
+	// The feedback can't be collected from the merged results.
+	e.feedback.Invalidate()
+	e.kvRangeGroups = make([][]kv.KeyRange, 0, len(groups))
+	var kvRanges []kv.KeyRange
+	for _, group := range groups {
+		groupKVRanges, err := e.buildKeyRanges(sc, group, e.physicalTableID)
+		if err != nil {
+			return nil, err
+		}
+		e.kvRangeGroups = append(e.kvRangeGroups, groupKVRanges)
+		kvRanges = append(kvRanges, groupKVRanges...)
+	}
+	return kvRanges, nil
+}
+
 func (e *IndexReaderExecutor) open(ctx context.Context, kvRanges []kv.KeyRange) error {
 	var err error
 	if e.corColInFilter {
@@ -284,6 +319,26 @@ func (e *IndexReaderExecutor) open(ctx context.Context, kvRanges []kv.KeyRange)
 
 	e.memTracker = memory.NewTracker(e.id, -1)
 	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	if len(e.kvRangeGroups) > 1 {
+		results := make([]distsql.SelectResult, 0, len(e.kvRangeGroups))
+		for _, groupKVRanges := range e.kvRangeGroups {
+			result, err := e.buildSelectResult(ctx, groupKVRanges)
+			if err != nil {
+				for _, r := range results {
+					terror.Call(r.Close)
+				}
+				return err
+			}
+			results = append(results, result)
+		}
+		e.result = distsql.NewSortedSelectResults(results, retTypes(e), e.mergeKeyOffsets, e.desc)
+		return nil
+	}
+	e.result, err = e.buildSelectResult(ctx, kvRanges)
+	return err
+}
+
+func (e *IndexReaderExecutor) buildSelectResult(ctx context.Context, kvRanges []kv.KeyRange) (distsql.SelectResult, error) {
 	var builder distsql.RequestBuilder
 	builder.SetKeyRanges(kvRanges).
 		SetDAGRequest(e.dagPB).
@@ -299,14 +354,14 @@ func (e *IndexReaderExecutor) open(ctx context.Context, kvRanges []kv.KeyRange)
 	kvReq, err := builder.Build()
 	if err != nil {
 		e.feedback.Invalidate()
-		return err
+		return nil, err
 	}
-	e.result, err = e.SelectResult(ctx, e.ctx, kvReq, retTypes(e), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
+	result, err := e.SelectResult(ctx, e.ctx, kvReq, retTypes(e), e.feedback, getPhysicalPlanIDs(e.plans), e.id)
 	if err != nil {
 		e.feedback.Invalidate()
-		return err
+		return nil, err
 	}
-	return nil
+	return result, nil
 }
 
 // IndexLookUpExecutor implements double read for index scan.
@@ -342,6 +397,7 @@ type IndexLookUpExecutor struct {
 	resultCh   chan *lookupTableTask
 	resultCurr *lookupTableTask
 	feedback   *statistics.QueryFeedback
+	estChecker *estDeviationChecker
 
 	// memTracker is used to track the memory usage of this executor.
 	memTracker *memory.Tracker
@@ -389,6 +445,7 @@ type checkIndexValue struct {
 
 // Open implements the Executor Open interface.
 func (e *IndexLookUpExecutor) Open(ctx context.Context) error {
+	e.estChecker.reset()
 	var err error
 	if e.corColInAccess {
 		e.ranges, err = rebuildIndexRanges(e.ctx, e.idxPlans[0].(*plannercore.PhysicalIndexScan), e.idxCols, e.colLens)
@@ -689,6 +746,7 @@ func (e *IndexLookUpExecutor) Close() error {
 	e.workerStarted = false
 	e.memTracker = nil
 	e.resultCurr = nil
+	e.storeIndexUsage(e.table.Meta().ID, e.index.ID)
 	return nil
 }
 
@@ -711,6 +769,7 @@ func (e *IndexLookUpExecutor) Next(ctx context.Context, req *chunk.Chunk) error
 			return err
 		}
 		if resultTask == nil {
+			e.estChecker.check(e.ctx, e.table, req.NumRows())
 			return nil
 		}
 		if resultTask.cursor < len(resultTask.rows) {
@@ -718,6 +777,7 @@ func (e *IndexLookUpExecutor) Next(ctx context.Context, req *chunk.Chunk) error
 			req.AppendRows(resultTask.rows[resultTask.cursor : resultTask.cursor+numToAppend])
 			resultTask.cursor += numToAppend
 			if req.IsFull() {
+				e.estChecker.check(e.ctx, e.table, req.NumRows())
 				return nil
 			}
 		}
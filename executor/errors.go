@@ -59,6 +59,9 @@ var (
 	ErrCTEMaxRecursionDepth          = dbterror.ClassExecutor.NewStd(mysql.ErrCTEMaxRecursionDepth)
 	ErrDataInConsistentExtraIndex    = dbterror.ClassExecutor.NewStd(mysql.ErrDataInConsistentExtraIndex)
 	ErrDataInConsistentMisMatchIndex = dbterror.ClassExecutor.NewStd(mysql.ErrDataInConsistentMisMatchIndex)
+	ErrMaxEstimatedCostExceeded      = dbterror.ClassExecutor.NewStd(mysql.ErrMaxEstimatedCostExceeded)
+	ErrMaxEstimatedRowsExceeded      = dbterror.ClassExecutor.NewStd(mysql.ErrMaxEstimatedRowsExceeded)
+	ErrDDLPolicyViolated             = dbterror.ClassExecutor.NewStd(mysql.ErrDDLPolicyViolated)
 
 	errUnsupportedFlashbackTmpTable = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("Recover/flashback table is not supported on temporary tables", nil))
 	errTruncateWrongInsertValue     = dbterror.ClassTable.NewStdErr(mysql.ErrTruncatedWrongValue, parser_mysql.Message("Incorrect %-.32s value: '%-.128s' for column '%.192s' at row %d", nil))
@@ -0,0 +1,77 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb/domain"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// estDeviationMinRows avoids reporting the deviation of the small tables, their statistics are cheap to be stale.
+const estDeviationMinRows = 1000
+
+// estDeviationChecker checks whether the actual rows read by a reader exceed its estimated rows by far, which implies
+// the statistics of the table are stale. The deviation is reported as a warning and the table is marked to be analyzed
+// by the auto analyze, the running query is not re-optimized.
+type estDeviationChecker struct {
+	estRows float64
+	ratio   float64
+	actRows int64
+	// checked is set once the deviation is reported, the deviation is reported at most once for a reader.
+	checked bool
+}
+
+// newEstDeviationChecker returns nil if tidb_opt_estimation_deviation_ratio is 0 or the plan is estimated by the
+// pseudo statistics.
+func newEstDeviationChecker(sctx sessionctx.Context, p plannercore.PhysicalPlan) *estDeviationChecker {
+	ratio := sctx.GetSessionVars().EstimationDeviationRatio
+	if ratio <= 0 || p.Stats().StatsVersion == statistics.PseudoVersion {
+		return nil
+	}
+	return &estDeviationChecker{estRows: p.StatsCount(), ratio: ratio}
+}
+
+// reset clears the actual rows when the reader is opened again, e.g. on the inner side of an Apply, since the
+// estimated rows are of a single execution.
+func (c *estDeviationChecker) reset() {
+	if c != nil {
+		c.actRows = 0
+	}
+}
+
+func (c *estDeviationChecker) check(sctx sessionctx.Context, tbl table.Table, rows int) {
+	if c == nil || c.checked || tbl == nil {
+		return
+	}
+	c.actRows += int64(rows)
+	if c.actRows < estDeviationMinRows || float64(c.actRows) <= c.estRows*c.ratio {
+		return
+	}
+	c.checked = true
+	tblName := tbl.Meta().Name.O
+	sctx.GetSessionVars().StmtCtx.AppendWarning(fmt.Errorf("the actual rows of table %s exceed the estimated rows %.2f by %v times, its statistics may be stale",
+		tblName, c.estRows, c.ratio))
+	logutil.BgLogger().Info("the actual rows exceed the estimated rows", zap.String("table", tblName),
+		zap.Float64("estRows", c.estRows), zap.Int64("actRows", c.actRows))
+	if h := domain.GetDomain(sctx).StatsHandle(); h != nil {
+		h.MarkEstimationDeviated(getFeedbackStatsTableID(sctx, tbl))
+	}
+}
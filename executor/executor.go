@@ -37,6 +37,7 @@ import (
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/expression"
@@ -81,6 +82,7 @@ var (
 	_ Executor = &LimitExec{}
 	_ Executor = &MaxOneRowExec{}
 	_ Executor = &MergeJoinExec{}
+	_ Executor = &ParallelMergeJoinExec{}
 	_ Executor = &ProjectionExec{}
 	_ Executor = &SelectionExec{}
 	_ Executor = &SelectLockExec{}
@@ -221,6 +223,16 @@ func (e *baseExecutor) updateDeltaForTableID(id int64) {
 	txnCtx.UpdateDeltaForTable(id, 0, 0, map[int64]int64{})
 }
 
+// storeIndexUsage stores the usage of the index read by the executor, the rows read are only known when the runtime
+// stats are collected.
+func (e *baseExecutor) storeIndexUsage(tblID, idxID int64) {
+	actRows := int64(0)
+	if e.runtimeStats != nil {
+		actRows = e.runtimeStats.GetActRows()
+	}
+	e.ctx.StoreIndexUsage(tblID, idxID, actRows)
+}
+
 func newBaseExecutor(ctx sessionctx.Context, schema *expression.Schema, id int, children ...Executor) baseExecutor {
 	e := baseExecutor{
 		children:     children,
@@ -495,7 +507,7 @@ func (e *DDLJobRetriever) appendJobToChunk(req *chunk.Chunk, job *model.Job, che
 	req.AppendInt64(0, job.ID)
 	req.AppendString(1, schemaName)
 	req.AppendString(2, tableName)
-	req.AppendString(3, job.Type.String())
+	req.AppendString(3, ddl.JobTypeString(job.Type))
 	req.AppendString(4, job.SchemaState.String())
 	req.AppendInt64(5, job.SchemaID)
 	req.AppendInt64(6, job.TableID)
@@ -1240,6 +1252,12 @@ type SelectionExec struct {
 	inputRow    chunk.Row
 	childResult *chunk.Chunk
 
+	// selIdx is the selection vector of childResult built from selected, selCursor points to the
+	// first row in selIdx which is not returned yet. They are only used in batched mode. The selected
+	// rows are still copied into the result chunk, in batch, rather than passed up by Chunk.SetSel.
+	selIdx    []int
+	selCursor int
+
 	memTracker *memory.Tracker
 }
 
@@ -1264,6 +1282,8 @@ func (e *SelectionExec) open(ctx context.Context) error {
 	e.batched = expression.Vectorizable(e.filters)
 	if e.batched {
 		e.selected = make([]bool, 0, chunk.InitialCapacity)
+		e.selIdx = make([]int, 0, chunk.InitialCapacity)
+		e.selCursor = 0
 	}
 	e.inputIter = chunk.NewIterator4Chunk(e.childResult)
 	e.inputRow = e.inputIter.End()
@@ -1277,6 +1297,7 @@ func (e *SelectionExec) Close() error {
 		e.childResult = nil
 	}
 	e.selected = nil
+	e.selIdx = nil
 	return e.baseExecutor.Close()
 }
 
@@ -1289,16 +1310,21 @@ func (e *SelectionExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	}
 
 	for {
-		for ; e.inputRow != e.inputIter.End(); e.inputRow = e.inputIter.Next() {
+		if e.selCursor < len(e.selIdx) {
+			n := len(e.selIdx) - e.selCursor
+			if remain := req.RequiredRows() - req.NumRows(); n > remain {
+				n = remain
+			}
+			if req.NumRows() == 0 && n == e.childResult.NumRows() && e.childResult.Sel() == nil {
+				// All the rows are selected, take them without copying.
+				req.SwapColumns(e.childResult)
+			} else {
+				req.AppendBySel(e.childResult, e.selIdx[e.selCursor:e.selCursor+n])
+			}
+			e.selCursor += n
 			if req.IsFull() {
 				return nil
 			}
-
-			if !e.selected[e.inputRow.Idx()] {
-				continue
-			}
-
-			req.AppendRow(e.inputRow)
 		}
 		mSize := e.childResult.MemoryUsage()
 		err := Next(ctx, e.children[0], e.childResult)
@@ -1314,7 +1340,12 @@ func (e *SelectionExec) Next(ctx context.Context, req *chunk.Chunk) error {
 		if err != nil {
 			return err
 		}
-		e.inputRow = e.inputIter.Begin()
+		e.selIdx, e.selCursor = e.selIdx[:0], 0
+		for i, selected := range e.selected {
+			if selected {
+				e.selIdx = append(e.selIdx, i)
+			}
+		}
 	}
 }
 
@@ -1656,6 +1687,7 @@ func (e *UnionExec) Close() error {
 // Before every execution, we must clear statement context.
 func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 	vars := ctx.GetSessionVars()
+	vars.RefreshTimeZone()
 	sc := &stmtctx.StatementContext{
 		TimeZone:      vars.Location(),
 		MemTracker:    memory.NewTracker(memory.LabelForSQLText, vars.MemQuotaQuery),
@@ -71,6 +71,7 @@ import (
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/mock"
+	"github.com/pingcap/tidb/util/redact"
 	"github.com/pingcap/tidb/util/rowcodec"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
@@ -358,6 +359,7 @@ func (s *testSuiteP1) TestShow(c *C) {
 		"RESTRICTED_VARIABLES_ADMIN Server Admin ",
 		"RESTRICTED_USER_ADMIN Server Admin ",
 		"RESTRICTED_CONNECTION_ADMIN Server Admin ",
+		"DDL_BREAK_GLASS Server Admin ",
 	))
 	c.Assert(len(tk.MustQuery("show table status").Rows()), Equals, 1)
 }
@@ -5371,6 +5373,39 @@ func (s *testSplitTable) TestShowTableRegion(c *C) {
 		c.Assert(rows[3+4*i][1], Equals, fmt.Sprintf("t_%d_r_6917529027641081856", p.ID))
 	}
 
+	// Test pre-split index regions in the declared value domain of the first index column.
+	tk.MustExec("set @@global.tidb_check_constraint_enforcement = 'ON'")
+	tk.MustExec("drop table if exists t_pre")
+	tk.MustExec("create table t_pre (a int, b tinyint unsigned, c enum('a', 'b', 'c', 'd', 'e', 'f', 'g', 'h'), d varchar(10), e bigint unsigned, f bigint, " +
+		"index ia(a), index ib(b, a), index ic(c), index id(d), index ie(e), index i_f(f), check (a between -4000000 and 3999999), check (1000 > e)) " +
+		"shard_row_id_bits = 2 pre_split_regions=2;")
+	tk.MustExec("set @@global.tidb_check_constraint_enforcement = default")
+	tbl = testGetTableByName(c, tk.Se, "test", "t_pre")
+	for i, tt := range []struct {
+		idx       string
+		splitKeys []string
+	}{
+		// The int domain declared by the CHECK constraint is split at -2000000, 0 and 2000000.
+		{"ia", []string{"037fffffffffe17b80", "038000000000000000", "0380000000001e8480"}},
+		// The whole range of the tinyint type is the domain.
+		{"ib", []string{"040000000000000040", "040000000000000080", "0400000000000000c0"}},
+		// The enum domain is split at the 3rd, 5th and 7th elements.
+		{"ic", []string{"040000000000000003", "040000000000000005", "040000000000000007"}},
+		// The varchar column doesn't have a declared value domain.
+		{"id", nil},
+		// The unsigned bigint domain [0, 999] is split at 250, 500 and 750.
+		{"ie", []string{"0400000000000000fa", "0400000000000001f4", "0400000000000002ee"}},
+		// The range of the bigint type is too wide to be split without a CHECK constraint.
+		{"i_f", nil},
+	} {
+		rows = tk.MustQuery("show table t_pre index " + tt.idx + " regions").Rows()
+		c.Assert(rows, HasLen, len(tt.splitKeys)+1)
+		c.Assert(rows[0][1], Equals, fmt.Sprintf("t_%d_i_%d_", tbl.Meta().ID, i+1))
+		for j, key := range tt.splitKeys {
+			c.Assert(rows[j+1][1], Equals, fmt.Sprintf("t_%d_i_%d_%s", tbl.Meta().ID, i+1, key))
+		}
+	}
+
 	defer atomic.StoreUint32(&ddl.EnableSplitTableRegion, 0)
 
 	// Test split partition table.
@@ -5704,6 +5739,60 @@ func (s *testSuite) TestOOMPanicAction(c *C) {
 	c.Assert(err.Error(), Matches, "Out Of Memory Quota!.*")
 }
 
+func (s *testSuite) TestMaxEstimatedCost(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t1")
+	tk.MustExec("create table t (a int primary key, b int)")
+	tk.MustExec("create table t1 (a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	tk.MustQuery("select @@tidb_max_estimated_cost, @@tidb_max_estimated_cost_action").Check(testkit.Rows("0 CANCEL"))
+
+	tk.MustExec("set @@tidb_max_estimated_cost = 1000")
+	_, err := tk.Exec("select * from t")
+	c.Assert(executor.ErrMaxEstimatedCostExceeded.Equal(err), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("insert into t1 select * from t")
+	c.Assert(executor.ErrMaxEstimatedCostExceeded.Equal(err), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("update t set b = b + 1 where b > 0")
+	c.Assert(executor.ErrMaxEstimatedCostExceeded.Equal(err), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("delete from t where b > 0")
+	c.Assert(executor.ErrMaxEstimatedCostExceeded.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustExec("prepare stmt from 'select * from t where b > ?'")
+	tk.MustExec("set @a = 0")
+	_, err = tk.Exec("execute stmt using @a")
+	c.Assert(executor.ErrMaxEstimatedCostExceeded.Equal(err), IsTrue, Commentf("err %v", err))
+	// The statements which are not planned by the cost-based optimizer are not affected.
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 1"))
+	tk.MustExec("insert into t values (3, 3)")
+	tk.MustQuery("explain select * from t")
+	tk.MustQuery("select @@tidb_max_estimated_cost").Check(testkit.Rows("1000"))
+
+	tk.MustExec("set @@tidb_max_estimated_cost_action = 'warn'")
+	tk.MustQuery("select * from t").Sort().Check(testkit.Rows("1 1", "2 2", "3 3"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
+	c.Assert(executor.ErrMaxEstimatedCostExceeded.Equal(tk.Se.GetSessionVars().StmtCtx.GetWarnings()[0].Err), IsTrue)
+	tk.MustExec("update t set b = b + 1 where b > 0")
+	tk.MustQuery("select b from t").Sort().Check(testkit.Rows("2", "3", "4"))
+
+	tk.MustExec("set @@tidb_max_estimated_cost = 0")
+	tk.MustQuery("select * from t").Sort().Check(testkit.Rows("1 2", "2 3", "3 4"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(0))
+	_, err = tk.Exec("set @@tidb_max_estimated_cost_action = 'log'")
+	c.Assert(err, NotNil)
+
+	// The estimated row count of any operator is checked by tidb_max_estimated_rows.
+	tk.MustExec("set @@tidb_max_estimated_cost_action = 'cancel'")
+	tk.MustQuery("select @@tidb_max_estimated_rows").Check(testkit.Rows("0"))
+	tk.MustExec("set @@tidb_max_estimated_rows = 100")
+	_, err = tk.Exec("select count(*) from t")
+	c.Assert(executor.ErrMaxEstimatedRowsExceeded.Equal(err), IsTrue, Commentf("err %v", err))
+	_, err = tk.Exec("delete from t where b > 0")
+	c.Assert(executor.ErrMaxEstimatedRowsExceeded.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustQuery("select * from t where a in (1, 2)").Sort().Check(testkit.Rows("1 2", "2 3"))
+	tk.MustExec("set @@tidb_max_estimated_rows = 0")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("3"))
+}
+
 type testRecoverTable struct {
 	store   kv.Storage
 	dom     *domain.Domain
@@ -6148,6 +6237,40 @@ func (s *testSuiteP2) TestPointGetPreparedPlan(c *C) {
 	tk1.ResultSetToResult(rs, Commentf("%v", rs)).Check(testkit.Rows("3 3 3 10"))
 }
 
+func (s *testSuiteP2) TestPointGetPreparedPlanReuseExecutor(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b timestamp)")
+	tk.MustExec("set @@time_zone = '+00:00'")
+	tk.MustExec("insert into t (a, b) values (1, '2021-01-01 00:00:00'), (2, '2021-01-02 00:00:00')")
+
+	psID, _, _, err := tk.Se.PrepareStmt("select * from t where a = ?")
+	c.Assert(err, IsNil)
+	ps := tk.Se.GetSessionVars().PreparedStmts[psID].(*plannercore.CachedPrepareStmt)
+	ps.PreparedAst.UseCache = false
+
+	ctx := context.Background()
+	rs, err := tk.Se.ExecutePreparedStmt(ctx, psID, []types.Datum{types.NewDatum(1)})
+	c.Assert(err, IsNil)
+	tk.ResultSetToResult(rs, Commentf("%v", rs)).Check(testkit.Rows("1 2021-01-01 00:00:00"))
+	rs, err = tk.Se.ExecutePreparedStmt(ctx, psID, []types.Datum{types.NewDatum(2)})
+	c.Assert(err, IsNil)
+	tk.ResultSetToResult(rs, Commentf("%v", rs)).Check(testkit.Rows("2 2021-01-02 00:00:00"))
+	exec := ps.Executor
+	c.Assert(exec, NotNil)
+
+	// The executor is reused, while the row decoder is rebuilt for the new time zone.
+	tk.MustExec("set @@time_zone = '+08:00'")
+	rs, err = tk.Se.ExecutePreparedStmt(ctx, psID, []types.Datum{types.NewDatum(1)})
+	c.Assert(err, IsNil)
+	tk.ResultSetToResult(rs, Commentf("%v", rs)).Check(testkit.Rows("1 2021-01-01 08:00:00"))
+	rs, err = tk.Se.ExecutePreparedStmt(ctx, psID, []types.Datum{types.NewDatum(3)})
+	c.Assert(err, IsNil)
+	tk.ResultSetToResult(rs, Commentf("%v", rs)).Check(nil)
+	c.Assert(ps.Executor, Equals, exec)
+}
+
 func (s *testSuiteP2) TestPointGetPreparedPlanWithCommitMode(c *C) {
 	tk1 := testkit.NewTestKit(c, s.store)
 	tk1.MustExec("drop database if exists ps_text")
@@ -7098,6 +7221,89 @@ func (s *testSlowQuery) TestSlowQueryPrepared(c *C) {
 		))
 }
 
+func (s *testSlowQuery) TestSlowQueryRedactSensitiveColumns(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	originCfg := config.GetGlobalConfig()
+	newCfg := *originCfg
+
+	f, err := os.CreateTemp("", "tidb-slow-*.log")
+	c.Assert(err, IsNil)
+	f.Close()
+	newCfg.Log.SlowQueryFile = f.Name()
+	config.StoreGlobalConfig(&newCfg)
+	redact.SetGlobalPolicy(redact.NewPolicy([]string{"test.t_redact.ssn"}))
+	defer func() {
+		tk.MustExec("set tidb_slow_log_threshold=300;")
+		redact.SetGlobalPolicy(nil)
+		config.StoreGlobalConfig(originCfg)
+		os.Remove(newCfg.Log.SlowQueryFile)
+	}()
+	err = logutil.InitLogger(newCfg.Log.ToLogConfig())
+	c.Assert(err, IsNil)
+
+	// The statements summary only records the statements of the users.
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil), IsTrue)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t_redact")
+	tk.MustExec("create table t_redact (id int primary key, ssn varchar(20), unique key uk_ssn(ssn))")
+	tk.MustExec("set tidb_slow_log_threshold=0;")
+	tk.MustExec("insert into t_redact values (1, '123-45-6789')")
+	tk.MustExec("select * from t_redact where ssn = '123-45-6789' and id = 1")
+	tk.MustExec("select * from t_redact where ssn like '987-65-4321%'")
+	tk.MustExec("select * from t_redact where id > 24680")
+	tk.MustExec(`prepare mystmt from 'select * from t_redact where id = ? and ssn = ?';`)
+	tk.MustExec("set @id = 1, @ssn = '123-45-6789';")
+	tk.MustExec("execute mystmt using @id, @ssn;")
+	tk.MustExec("set tidb_slow_log_threshold=300;")
+	tk.MustQuery("select query from `information_schema`.`slow_query` " +
+		"where query like '%t_redact%' order by time").
+		Check(testkit.Rows(
+			"INSERT INTO `t_redact` VALUES (1,?);",
+			"SELECT * FROM `t_redact` WHERE `ssn`=? AND `id`=1;",
+			"SELECT * FROM `t_redact` WHERE `ssn` LIKE ?;",
+			"select * from t_redact where id > 24680;",
+			"select * from t_redact where id = ? and ssn = ?;",
+			"select * from t_redact where id = ? and ssn = ? [arguments: (1, ?)];",
+		))
+
+	// The constants are masked in the plans of the redacted statements in the slow log and the statements summary, the
+	// plans of the other statements are kept.
+	checkPlan := func(sql string, plan string) {
+		if strings.Contains(sql, "LIKE") {
+			c.Assert(strings.Contains(plan, "uk_ssn"), IsTrue, Commentf("%s\n%s", sql, plan))
+		}
+		for _, value := range []string{"123-45-6789", "987-65-4321"} {
+			c.Assert(strings.Contains(plan, value), IsFalse, Commentf("%s\n%s", sql, plan))
+		}
+	}
+	for _, row := range tk.MustQuery("select query, plan from `information_schema`.`slow_query` " +
+		"where query like '%t_redact%' and query not like '%24680%'").Rows() {
+		checkPlan(row[0].(string), row[1].(string))
+	}
+	for _, row := range tk.MustQuery("select query_sample_text, plan from `information_schema`.`statements_summary` " +
+		"where digest_text like '%t_redact%' and query_sample_text not like '%24680%'").Rows() {
+		checkPlan(row[0].(string), row[1].(string))
+	}
+	rows := tk.MustQuery("select plan from `information_schema`.`slow_query` where query like '%24680%'").Rows()
+	c.Assert(rows, HasLen, 1)
+	c.Assert(strings.Contains(rows[0][0].(string), "24680"), IsTrue, Commentf("%v", rows[0][0]))
+	rows = tk.MustQuery("select plan from `information_schema`.`statements_summary` where query_sample_text like '%24680%'").Rows()
+	c.Assert(rows, HasLen, 1)
+	c.Assert(strings.Contains(rows[0][0].(string), "24680"), IsTrue, Commentf("%v", rows[0][0]))
+
+	// The duplicate entry of the sensitive column is masked in the error.
+	_, err = tk.Exec("insert into t_redact values (2, '123-45-6789')")
+	c.Assert(err.Error(), Equals, "[kv:1062]Duplicate entry '?' for key 'uk_ssn'")
+	_, err = tk.Exec("insert into t_redact values (1, '000-00-0000')")
+	c.Assert(err.Error(), Equals, "[kv:1062]Duplicate entry '1' for key 'PRIMARY'")
+	tk.MustExec("insert ignore into t_redact values (2, '123-45-6789')")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1062 Duplicate entry '?' for key 'uk_ssn'"))
+
+	// The values read from the sensitive table are masked in the warnings too.
+	tk.MustQuery("select cast(ssn as signed) from t_redact where id = 1").Check(testkit.Rows("123"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1292 Truncated incorrect INTEGER value: '?'"))
+}
+
 func (s *testSlowQuery) TestLogSlowLogIndex(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	f, err := os.CreateTemp("", "tidb-slow-*.log")
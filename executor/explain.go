@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/execdetails"
 )
 
 // ExplainExec represents an explain executor.
@@ -100,12 +101,27 @@ func (e *ExplainExec) generateExplainInfo(ctx context.Context) (rows [][]string,
 	if err = e.executeAnalyzeExec(ctx); err != nil {
 		return nil, err
 	}
+	e.registerRPCStats(ctx)
 	if err = e.explain.RenderResult(); err != nil {
 		return nil, err
 	}
 	return e.explain.Rows, nil
 }
 
+// registerRPCStats attaches the RPC requests sent by the statement to the root operator.
+func (e *ExplainExec) registerRPCStats(ctx context.Context) {
+	if e.analyzeExec == nil {
+		return
+	}
+	rpcStats, ok := ctx.Value(execdetails.StmtRPCStatsKey).(*execdetails.StmtRPCStats)
+	if !ok || len(rpcStats.Stats()) == 0 {
+		return
+	}
+	if coll := e.ctx.GetSessionVars().StmtCtx.RuntimeStatsColl; coll != nil {
+		coll.RegisterStats(e.analyzeExec.base().id, rpcStats.Clone())
+	}
+}
+
 // getAnalyzeExecToExecutedNoDelay gets the analyze DML executor to execute in handleNoDelay function.
 // For explain analyze insert/update/delete statement, the analyze executor should be executed in handleNoDelay
 // function and then commit transaction if needed.
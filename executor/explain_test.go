@@ -15,6 +15,7 @@ package executor_test
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/pingcap/parser/auth"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/testkit"
 )
 
@@ -248,6 +250,31 @@ func (s *testSuite2) TestExplainAnalyzeActRowsNotEmpty(c *C) {
 	s.checkActRowsNotEmpty(c, tk, "explain analyze select * from t t1, t t2 where t1.b = t2.a and t1.b = 2333")
 }
 
+func (s *testSuite2) TestExplainAnalyzeRPCStats(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+
+	check := func(sql string, rpcStats string) {
+		ctx := context.WithValue(context.Background(), execdetails.StmtRPCStatsKey, execdetails.NewStmtRPCStats())
+		rs, err := tk.Se.Execute(ctx, sql)
+		c.Assert(err, IsNil)
+		rows := tk.ResultSetToResultWithCtx(ctx, rs[0], Commentf("sql: %s", sql)).Rows()
+		c.Assert(rows[0][5], Matches, ".*rpc_stats:{"+rpcStats+"}.*", Commentf("sql: %s", sql))
+	}
+	check("explain analyze select * from t where a = 1", "Get:{num_rpc:1, total_time:[^}]*}")
+	check("explain analyze select * from t where a in (1, 2)", "BatchGet:{num_rpc:1, total_time:[^}]*}")
+	check("explain analyze select * from t where b = 1", "Cop:{num_rpc:1, total_time:[^}]*}")
+	tk.MustExec("begin")
+	check("explain analyze update t set b = 3 where a = 1", "Get:{num_rpc:1, total_time:[^}]*}")
+	tk.MustExec("rollback")
+
+	// The RPC stats are not shown without the context value.
+	rows := tk.MustQuery("explain analyze select * from t where a = 1").Rows()
+	c.Assert(rows[0][5], Not(Matches), ".*rpc_stats.*")
+}
+
 func (s *testSuite2) checkActRowsNotEmpty(c *C, tk *testkit.TestKit, sql string) {
 	actRowsCol := 2
 	rows := tk.MustQuery(sql).Rows()
@@ -18,6 +18,7 @@ import (
 	"strings"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/executor"
@@ -447,3 +448,86 @@ func (s *testSuite3) TestGrantDynamicPrivs(c *C) {
 	tk.MustQuery("SELECT Grant_Priv FROM mysql.user WHERE `Host` = '%' AND `User` = 'dyn'").Check(testkit.Rows("Y"))
 	tk.MustQuery("SELECT WITH_GRANT_OPTION FROM mysql.global_grants WHERE `Host` = '%' AND `User` = 'dyn' AND Priv='CONNECTION_ADMIN'").Check(testkit.Rows("Y"))
 }
+
+func (s *testSuite3) TestPrivilegeChanges(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{Username: "root", Hostname: "%"}, nil, nil), IsTrue)
+	tk.MustExec("create user 'pc_user'@'%' identified by '123'")
+	tk.MustExec("create role 'pc_role'")
+	createUser := tk.MustQuery("show create user 'pc_user'@'%'").Rows()[0][0].(string)
+	authString := tk.MustQuery("select authentication_string from mysql.user where user = 'pc_user'").Rows()[0][0].(string)
+	redactedCreateUser := strings.Replace(createUser, authString, "***", 1)
+	tk.MustExec("grant select on test.* to 'pc_user'@'%'")
+	tk.MustExec("grant insert on test.* to 'pc_user'@'%'")
+	tk.MustExec("grant 'pc_role' to 'pc_user'@'%'")
+	grants := tk.MustQuery("show grants for 'pc_user'@'%'").Rows()
+	tk.MustExec("drop user 'pc_user'@'%'")
+
+	query := "select stmt, before_grants, after_grants, rollback_stmts from mysql.privilege_changes where `user` = 'pc_user' order by id"
+	rows := tk.MustQuery(query).Rows()
+	c.Assert(rows, HasLen, 5)
+	// Neither the password nor its hash is recorded.
+	c.Assert(rows[0][0], Equals, "create user {pc_user@% password = ***}")
+	c.Assert(rows[0][1], Equals, "")
+	c.Assert(rows[0][2], Equals, redactedCreateUser+";\nGRANT USAGE ON *.* TO 'pc_user'@'%';")
+	for _, row := range rows {
+		for _, col := range row {
+			c.Assert(strings.Contains(col.(string), authString), IsFalse)
+		}
+	}
+	c.Assert(rows[0][3], Equals, "DROP USER 'pc_user'@'%';")
+	c.Assert(rows[1][3], Equals, "REVOKE Select ON test.* FROM 'pc_user'@'%';")
+	c.Assert(rows[2][1], Equals, rows[1][2])
+	c.Assert(rows[2][3], Equals, "REVOKE Select,Insert ON test.* FROM 'pc_user'@'%';\nGRANT Select ON test.* TO 'pc_user'@'%';")
+	c.Assert(rows[3][3], Equals, "REVOKE 'pc_role'@'%' FROM 'pc_user'@'%';")
+	c.Assert(rows[4][2], Equals, "")
+
+	// Roll back the changes in the reverse order.
+	rollback := func(stmts interface{}) {
+		for _, stmt := range strings.Split(stmts.(string), "\n") {
+			tk.MustExec(stmt)
+		}
+	}
+	// The dropped user can't be recreated until the redacted password hash is replaced.
+	_, err := tk.Exec(strings.Split(rows[4][3].(string), "\n")[0])
+	c.Assert(err, NotNil)
+	rollback(strings.Replace(rows[4][3].(string), "'***'", "'"+authString+"'", 1))
+	tk.MustQuery("show create user 'pc_user'@'%'").Check(testkit.Rows(createUser))
+	tk.MustQuery("show grants for 'pc_user'@'%'").Check(grants)
+	for i := 3; i >= 1; i-- {
+		rollback(rows[i][3])
+	}
+	tk.MustQuery("show grants for 'pc_user'@'%'").Check(testkit.Rows("GRANT USAGE ON *.* TO 'pc_user'@'%'"))
+
+	// The password changes are recorded, but they can't be rolled back.
+	tk.MustExec("alter user 'pc_user'@'%' identified by '456'")
+	rows = tk.MustQuery(query + " desc limit 1").Rows()
+	c.Assert(rows[0][0], Equals, "alter user {pc_user@% password = ***}")
+	c.Assert(rows[0][1], Equals, rows[0][2])
+	c.Assert(rows[0][3], Equals, "")
+
+	tk.MustExec("drop user 'pc_user'@'%', 'pc_role'")
+	tk.MustQuery("select operator, `user` from mysql.privilege_changes where stmt like 'drop user%pc_role%' order by `user`").
+		Check(testkit.Rows("root@% pc_role", "root@% pc_user"))
+	// The statements failing without any change are not recorded.
+	_, err = tk.Exec("drop user 'pc_role'")
+	c.Assert(err, NotNil)
+	tk.MustQuery("select count(*) from mysql.privilege_changes where `user` = 'pc_role'").Check(testkit.Rows("2"))
+
+	// The history is append-only, even root can't modify it.
+	for _, sql := range []string{
+		"update mysql.privilege_changes set stmt = ''",
+		"delete from mysql.privilege_changes",
+		"insert into mysql.privilege_changes (stmt) values ('')",
+		"replace into mysql.privilege_changes (id, stmt) values (1, '')",
+		"truncate table mysql.privilege_changes",
+		"drop table mysql.privilege_changes",
+		"alter table mysql.privilege_changes drop column stmt",
+		"rename table mysql.privilege_changes to mysql.privilege_changes_bak",
+	} {
+		_, err = tk.Exec(sql)
+		c.Assert(err, NotNil, Commentf("sql %s", sql))
+	}
+	tk.MustQuery("select count(*) from mysql.privilege_changes where `user` = 'pc_role'").Check(testkit.Rows("2"))
+}
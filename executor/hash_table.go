@@ -78,6 +78,11 @@ type hashRowContainer struct {
 	hashTable baseHashTable
 
 	rowContainer *chunk.RowContainer
+
+	// recordNullKeyRows indicates whether to record the rows whose join keys contain null in nullKeyRows.
+	// It's only needed by the null-aware anti semi join.
+	recordNullKeyRows bool
+	nullKeyRows       []chunk.RowPtr
 }
 
 func newHashRowContainer(sCtx sessionctx.Context, estCount int, hCtx *hashContext) *hashRowContainer {
@@ -166,7 +171,13 @@ func (c *hashRowContainer) PutChunkSelected(chk *chunk.Chunk, selected, ignoreNu
 		}
 	}
 	for i := 0; i < numRows; i++ {
-		if (selected != nil && !selected[i]) || c.hCtx.hasNull[i] {
+		if selected != nil && !selected[i] {
+			continue
+		}
+		if c.hCtx.hasNull[i] {
+			if c.recordNullKeyRows {
+				c.nullKeyRows = append(c.nullKeyRows, chunk.RowPtr{ChkIdx: chkIdx, RowIdx: uint32(i)})
+			}
 			continue
 		}
 		key := c.hCtx.hashVals[i].Sum64()
@@ -176,6 +187,29 @@ func (c *hashRowContainer) PutChunkSelected(chk *chunk.Chunk, selected, ignoreNu
 	return nil
 }
 
+// GetNullKeyRows returns the rows whose join keys contain null, they are only recorded if recordNullKeyRows is true.
+func (c *hashRowContainer) GetNullKeyRows() (rows []chunk.Row, err error) {
+	rows = make([]chunk.Row, 0, len(c.nullKeyRows))
+	for _, ptr := range c.nullKeyRows {
+		row, err := c.rowContainer.GetRow(ptr)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// NumNullKeyRows returns the number of the recorded rows whose join keys contain null.
+func (c *hashRowContainer) NumNullKeyRows() int {
+	return len(c.nullKeyRows)
+}
+
+// NumRows returns the number of rows in the rowContainer, including the rows not put into the hash table.
+func (c *hashRowContainer) NumRows() int {
+	return c.rowContainer.NumRow()
+}
+
 // NumChunks returns the number of chunks in the rowContainer
 func (c *hashRowContainer) NumChunks() int {
 	return c.rowContainer.NumChunks()
@@ -51,7 +51,7 @@ var (
 // IndexMergeReaderExecutor accesses a table with multiple index/table scan.
 // There are three types of workers:
 // 1. partialTableWorker/partialIndexWorker, which are used to fetch the handles
-// 2. indexMergeProcessWorker, which is used to do the `Union` operation.
+// 2. indexMergeProcessWorker, which is used to do the `Union` or `Intersection` operation.
 // 3. indexMergeTableScanWorker, which is used to get the table tuples with the given handles.
 //
 // The execution flow is really like IndexLookUpReader. However, it uses multiple index scans
@@ -63,6 +63,8 @@ var (
 //    1. check whether it has been accessed.
 //    2. if not, record it and send it to the indexMergeTableScanWorker.
 //    3. if accessed, just ignore it.
+//    For the `Intersection` operation, a handle is sent to the indexMergeTableScanWorker once it has been
+//    fetched by all the partial workers.
 type IndexMergeReaderExecutor struct {
 	baseExecutor
 
@@ -77,6 +79,8 @@ type IndexMergeReaderExecutor struct {
 	columns           []*model.ColumnInfo
 	partialStreamings []bool
 	tableStreaming    bool
+	// isIntersection indicates whether the handles fetched by the partial workers are intersected.
+	isIntersection bool
 	*dataReaderBuilder
 
 	// fields about accessing partition tables
@@ -633,6 +637,12 @@ func (e *IndexMergeReaderExecutor) Close() error {
 	e.finished = nil
 	e.workerStarted = false
 	// TODO: how to store e.feedbacks
+	// The rows read by each index are unknown, the rows returned by the executor are counted for all of them.
+	for _, idx := range e.indexes {
+		if idx != nil {
+			e.storeIndexUsage(e.table.Meta().ID, idx.ID)
+		}
+	}
 	return nil
 }
 
@@ -666,6 +676,19 @@ func (w *indexMergeProcessWorker) fetchLoop(ctx context.Context, fetchCh <-chan
 		hMap := distinctHandles[tblID]
 
 		for _, h := range handles {
+			if w.indexMerge.isIntersection {
+				// The handles fetched by a partial worker are distinct since its ranges don't overlap,
+				// so the count of a handle is the number of the partial workers which have fetched it.
+				cnt := 1
+				if v, ok := hMap.Get(h); ok {
+					cnt = v.(int) + 1
+				}
+				hMap.Set(h, cnt)
+				if cnt == len(w.indexMerge.partialPlans) {
+					fhs = append(fhs, h)
+				}
+				continue
+			}
 			if _, ok := hMap.Get(h); !ok {
 				fhs = append(fhs, h)
 				hMap.Set(h, true)
@@ -169,3 +169,43 @@ func (s *testSuite1) TestPartitionTableRandomIndexMerge(c *C) {
 		tk.MustQuery("select /*+ USE_INDEX_MERGE(tpk, a, b) */ * from tpk where " + cond).Sort().Check(result)
 	}
 }
+
+func (s *testSuite1) TestIndexMergeIntersection(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, tp")
+	tk.MustExec("create table t (a int, b int, c int, key(a), key(b), key(c))")
+	tk.MustExec("insert into t values (1, 1, 1), (1, 2, 2), (2, 2, 3), (1, 2, 4), (3, 3, 5)")
+	tk.MustQuery("explain format='brief' select /*+ use_index_merge(t, a, b) */ * from t where a = 1 and b = 2 and c > 2").Check(testkit.Rows(
+		"IndexMerge 0.01 root  type: intersection",
+		"├─IndexRangeScan(Build) 10.00 cop[tikv] table:t, index:a(a) range:[1,1], keep order:false, stats:pseudo",
+		"├─IndexRangeScan(Build) 10.00 cop[tikv] table:t, index:b(b) range:[2,2], keep order:false, stats:pseudo",
+		"└─Selection(Probe) 0.01 cop[tikv]  gt(test.t.c, 2)",
+		"  └─TableRowIDScan 0.01 cop[tikv] table:t keep order:false, stats:pseudo"))
+	tk.MustQuery("select /*+ use_index_merge(t, a, b) */ * from t where a = 1 and b = 2").Sort().Check(testkit.Rows("1 2 2", "1 2 4"))
+	tk.MustQuery("select /*+ use_index_merge(t, a, b) */ * from t where a = 1 and b = 2 and c > 2").Check(testkit.Rows("1 2 4"))
+	tk.MustQuery("select /*+ use_index_merge(t, a, b, c) */ c from t where a = 1 and b = 2 and c > 2").Check(testkit.Rows("4"))
+	tk.MustQuery("select /*+ use_index_merge(t, a, b) */ * from t where a = 1 and b = 3").Check(testkit.Rows())
+	// The intersection is inapplicable if only one index is specified.
+	tk.MustQuery("select /*+ use_index_merge(t, a) */ * from t where a = 1 and b = 2").Sort().Check(testkit.Rows("1 2 2", "1 2 4"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 IndexMerge is inapplicable or disabled"))
+
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+	tk.MustExec(`create table tp (a int, b int, key(a), key(b))
+		partition by range (a) (
+		partition p1 values less than (10),
+		partition p2 values less than (20),
+		partition p3 values less than (30),
+		partition p4 values less than (40))`)
+	values := make([]string, 0, 128)
+	for i := 0; i < 128; i++ {
+		values = append(values, fmt.Sprintf("(%v, %v)", rand.Intn(40), rand.Intn(40)))
+	}
+	tk.MustExec(fmt.Sprintf("insert into tp values %v", strings.Join(values, ", ")))
+	for i := 0; i < 64; i++ {
+		la, lb := rand.Intn(40), rand.Intn(40)
+		cond := fmt.Sprintf("a between %v and %v and b between %v and %v", la, la+10, lb, lb+10)
+		result := tk.MustQuery("select /*+ use_index(tp) */ * from tp where " + cond).Sort().Rows()
+		tk.MustQuery("select /*+ use_index_merge(tp, a, b) */ * from tp where " + cond).Sort().Check(result)
+	}
+}
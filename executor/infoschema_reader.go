@@ -30,10 +30,13 @@ import (
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/charset"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/ddl/placement"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/domain/infosync"
@@ -53,7 +56,9 @@ import (
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/conflicthistory"
 	"github.com/pingcap/tidb/util/deadlockhistory"
+	"github.com/pingcap/tidb/util/hint"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/pdapi"
 	"github.com/pingcap/tidb/util/resourcegrouptag"
@@ -62,6 +67,7 @@ import (
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/stmtsummary"
 	"github.com/pingcap/tidb/util/stringutil"
+	"github.com/pingcap/tidb/util/txnsummary"
 	"go.etcd.io/etcd/clientv3"
 	"go.uber.org/zap"
 )
@@ -107,6 +113,10 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			e.setDataFromIndexes(sctx, dbs)
 		case infoschema.TableViews:
 			e.setDataFromViews(sctx, dbs)
+		case infoschema.TableViewTableUsage:
+			e.setDataFromViewTableUsage(sctx, dbs)
+		case infoschema.TableTiDBViewValidity:
+			e.setDataForViewValidity(ctx, sctx, is, dbs)
 		case infoschema.TableEngines:
 			e.setDataFromEngines()
 		case infoschema.TableCharacterSets:
@@ -162,6 +172,20 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			err = e.setDataForClusterDeadlock(sctx)
 		case infoschema.TableDataLockWaits:
 			err = e.setDataForTableDataLockWaits(sctx)
+		case infoschema.TableKeywords:
+			e.setDataForKeywords()
+		case infoschema.TableSQLFeatures:
+			e.setDataForSQLFeatures(sctx)
+		case infoschema.TableTransactionSummary:
+			err = e.setDataForTransactionSummary(sctx)
+		case infoschema.TableWriteConflicts:
+			err = e.setDataForWriteConflicts(sctx)
+		case infoschema.TableUserAttributes:
+			err = e.setDataForUserAttributes(sctx)
+		case infoschema.TableTiDBEffectivePrivileges:
+			e.setDataForEffectivePrivileges(sctx)
+		case infoschema.TableTiDBIndexUsage:
+			err = e.setDataForIndexUsage(sctx, dbs)
 		}
 		if err != nil {
 			return nil, err
@@ -956,6 +980,125 @@ func (e *memtableRetriever) setDataFromViews(ctx sessionctx.Context, schemas []*
 	e.rows = rows
 }
 
+// viewTableNamesCollector collects the tables and views referenced by a view definition, the CTEs are excluded.
+type viewTableNamesCollector struct {
+	defaultDB model.CIStr
+	cteNames  map[string]struct{}
+	names     [][2]model.CIStr
+	visited   map[[2]string]struct{}
+}
+
+// Enter implements ast.Visitor interface.
+func (c *viewTableNamesCollector) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.SelectStmt:
+		c.addCTENames(x.With)
+	case *ast.SetOprStmt:
+		c.addCTENames(x.With)
+	case *ast.TableName:
+		schema := x.Schema
+		if schema.L == "" {
+			if _, ok := c.cteNames[x.Name.L]; ok {
+				return in, true
+			}
+			schema = c.defaultDB
+		}
+		key := [2]string{schema.L, x.Name.L}
+		if _, ok := c.visited[key]; !ok {
+			c.visited[key] = struct{}{}
+			c.names = append(c.names, [2]model.CIStr{schema, x.Name})
+		}
+	}
+	return in, false
+}
+
+// Leave implements ast.Visitor interface.
+func (c *viewTableNamesCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (c *viewTableNamesCollector) addCTENames(with *ast.WithClause) {
+	if with == nil {
+		return
+	}
+	for _, cte := range with.CTEs {
+		c.cteNames[cte.Name.L] = struct{}{}
+	}
+}
+
+// setDataFromViewTableUsage returns the tables and views referenced directly by the views, the indirect dependencies
+// can be found by joining the table with itself.
+func (e *memtableRetriever) setDataFromViewTableUsage(ctx sessionctx.Context, schemas []*model.DBInfo) {
+	checker := privilege.GetPrivilegeManager(ctx)
+	charset, collation := ctx.GetSessionVars().GetCharsetInfo()
+	p := parser.New()
+	p.SetParserConfig(ctx.GetSessionVars().BuildParserConfig())
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		for _, table := range schema.Tables {
+			if !table.IsView() {
+				continue
+			}
+			if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, schema.Name.L, table.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			stmt, err := p.ParseOneStmt(table.View.SelectStmt, charset, collation)
+			if err != nil {
+				ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+				continue
+			}
+			collector := &viewTableNamesCollector{
+				defaultDB: schema.Name,
+				cteNames:  make(map[string]struct{}),
+				visited:   make(map[[2]string]struct{}),
+			}
+			stmt.Accept(collector)
+			for _, name := range collector.names {
+				record := types.MakeDatums(
+					infoschema.CatalogVal, // VIEW_CATALOG
+					schema.Name.O,         // VIEW_SCHEMA
+					table.Name.O,          // VIEW_NAME
+					infoschema.CatalogVal, // TABLE_CATALOG
+					name[0].O,             // TABLE_SCHEMA
+					name[1].O,             // TABLE_NAME
+				)
+				rows = append(rows, record)
+			}
+		}
+	}
+	e.rows = rows
+}
+
+// setDataForViewValidity checks whether the views can still be queried after the schema changes of the underlying
+// tables and views. The views are built like they're queried, so a view over an invalid view is also invalid.
+func (e *memtableRetriever) setDataForViewValidity(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema, schemas []*model.DBInfo) {
+	checker := privilege.GetPrivilegeManager(sctx)
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		for _, table := range schema.Tables {
+			if !table.IsView() {
+				continue
+			}
+			if checker != nil && !checker.RequestVerification(sctx.GetSessionVars().ActiveRoles, schema.Name.L, table.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			isValid, reason := "YES", interface{}(nil)
+			planBuilder, _ := plannercore.NewPlanBuilder(sctx, is, &hint.BlockHintProcessor{})
+			if _, err := planBuilder.BuildDataSourceFromView(ctx, schema.Name, table); err != nil {
+				isValid, reason = "NO", err.Error()
+			}
+			record := types.MakeDatums(
+				schema.Name.O, // TABLE_SCHEMA
+				table.Name.O,  // TABLE_NAME
+				isValid,       // IS_VALID
+				reason,        // REASON
+			)
+			rows = append(rows, record)
+		}
+	}
+	e.rows = rows
+}
+
 func (e *memtableRetriever) dataForTiKVStoreStatus(ctx sessionctx.Context) (err error) {
 	tikvStore, ok := ctx.GetStore().(helper.Storage)
 	if !ok {
@@ -1126,6 +1269,60 @@ func (e *memtableRetriever) setDataFromEngines() {
 	e.rows = rows
 }
 
+func (e *memtableRetriever) setDataForKeywords() {
+	rows := make([][]types.Datum, 0, len(infoschema.Keywords))
+	for _, kw := range infoschema.Keywords {
+		reserved := 0
+		if kw.Reserved {
+			reserved = 1
+		}
+		rows = append(rows, types.MakeDatums(kw.Word, reserved))
+	}
+	e.rows = rows
+}
+
+func (e *memtableRetriever) setDataForSQLFeatures(ctx sessionctx.Context) {
+	vars := ctx.GetSessionVars()
+	features := []struct {
+		name      string
+		supported bool
+		comment   string
+	}{
+		{"CLUSTERED INDEX", true, "Controlled by tidb_enable_clustered_index for new tables"},
+		{"COMMON TABLE EXPRESSION", true, "Including recursive CTE"},
+		{"GENERATED COLUMN", true, ""},
+		{"JSON", true, ""},
+		{"LIST PARTITION", vars.EnableListTablePartition, "Controlled by tidb_enable_list_partition"},
+		{"RANGE PARTITION", true, ""},
+		{"HASH PARTITION", true, ""},
+		{"ROLE", true, ""},
+		{"SEQUENCE", true, ""},
+		{"VIEW", true, ""},
+		{"WINDOW FUNCTION", vars.EnableWindowFunction, "Controlled by tidb_enable_window_function"},
+		{"GLOBAL TEMPORARY TABLE", vars.EnableGlobalTemporaryTable, "Controlled by tidb_enable_global_temporary_table"},
+		{"TABLE LOCK", config.TableLockEnabled(), "Controlled by enable-table-lock in the config file"},
+		{"CHECK CONSTRAINT", false, "Parsed but ignored"},
+		{"FOREIGN KEY", false, "Parsed but not enforced"},
+		{"FULLTEXT INDEX", false, ""},
+		{"SPATIAL", false, ""},
+		{"EVENT", false, ""},
+		{"STORED PROCEDURE", false, ""},
+		{"TRIGGER", false, ""},
+		{"USER DEFINED FUNCTION", false, ""},
+		{"SAVEPOINT", false, ""},
+		{"XA TRANSACTION", false, ""},
+	}
+	rows := make([][]types.Datum, 0, len(features))
+	for _, feature := range features {
+		supported := "NO"
+		if feature.supported {
+			supported = "YES"
+		}
+		rows = append(rows, types.MakeDatums(feature.name, supported, feature.comment))
+	}
+	e.rows = rows
+}
+
 func (e *memtableRetriever) setDataFromCharacterSets() {
 	charsets := charset.GetSupportedCharsets()
 	var rows = make([][]types.Datum, 0, len(charsets))
@@ -2079,6 +2276,141 @@ func (e *memtableRetriever) setDataForDeadlock(ctx sessionctx.Context) error {
 	return nil
 }
 
+func (e *memtableRetriever) setDataForTransactionSummary(ctx sessionctx.Context) error {
+	if !hasPriv(ctx, mysql.ProcessPriv) {
+		return plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+
+	e.rows = txnsummary.GlobalTxnSummary.GetAllDatum()
+	return nil
+}
+
+// setDataForUserAttributes shows the "metadata" of mysql.user.User_attributes. CREATE/ALTER USER ... ATTRIBUTE isn't
+// supported because the grammar is missing in github.com/pingcap/parser, so the attributes can only be written by
+// updating mysql.user directly.
+func (e *memtableRetriever) setDataForUserAttributes(ctx sessionctx.Context) error {
+	exec := ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(context.TODO(), `SELECT User, Host, JSON_EXTRACT(User_attributes, '$.metadata') FROM %n.%n`, mysql.SystemDB, mysql.UserTable)
+	if err != nil {
+		return err
+	}
+	rows, _, err := exec.ExecRestrictedStmt(context.TODO(), stmt)
+	if err != nil {
+		return err
+	}
+
+	// Seeing the attributes of the other users requires the SELECT privilege on mysql.user or the CREATE USER privilege.
+	loginUser := ctx.GetSessionVars().User
+	seeAll := true
+	if checker := privilege.GetPrivilegeManager(ctx); checker != nil {
+		activeRoles := ctx.GetSessionVars().ActiveRoles
+		seeAll = checker.RequestVerification(activeRoles, mysql.SystemDB, mysql.UserTable, "", mysql.SelectPriv) ||
+			checker.RequestVerification(activeRoles, "", "", "", mysql.CreateUserPriv)
+	}
+	e.rows = make([][]types.Datum, 0, len(rows))
+	for _, row := range rows {
+		user, host := row.GetString(0), row.GetString(1)
+		if !seeAll && (loginUser == nil || user != loginUser.AuthUsername || host != loginUser.AuthHostname) {
+			continue
+		}
+		var attribute interface{}
+		if !row.IsNull(2) {
+			attribute = row.GetJSON(2).String()
+		}
+		e.rows = append(e.rows, types.MakeDatums(user, host, attribute))
+	}
+	return nil
+}
+
+// setDataForIndexUsage returns the usage of all the indexes, the ones never used have zero counts. The usage is
+// collected in memory and dumped to mysql.schema_index_usage every index-usage-sync-lease, which is 0 and disables the
+// collection by default. While the collection is disabled, the counts of the indexes without recorded usage are NULL,
+// since they're unknown rather than zero.
+func (e *memtableRetriever) setDataForIndexUsage(ctx sessionctx.Context, schemas []*model.DBInfo) error {
+	exec := ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(context.TODO(), "SELECT TABLE_ID, INDEX_ID, QUERY_COUNT, ROWS_SELECTED, LAST_USED_AT FROM %n.%n", mysql.SystemDB, "schema_index_usage")
+	if err != nil {
+		return err
+	}
+	usageRows, _, err := exec.ExecRestrictedStmt(context.TODO(), stmt)
+	if err != nil {
+		return err
+	}
+	usages := make(map[[2]int64]chunk.Row, len(usageRows))
+	for _, row := range usageRows {
+		usages[[2]int64{row.GetInt64(0), row.GetInt64(1)}] = row
+	}
+
+	collected := variable.IndexUsageSyncLease.Load() > 0
+	checker := privilege.GetPrivilegeManager(ctx)
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		if util.IsMemDB(schema.Name.L) {
+			continue
+		}
+		for _, tb := range schema.Tables {
+			if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, schema.Name.L, tb.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			for _, idxInfo := range tb.Indices {
+				if idxInfo.State != model.StatePublic {
+					continue
+				}
+				queryCount, rowsSelected, lastUsedAt := interface{}(nil), interface{}(nil), interface{}(nil)
+				if collected {
+					queryCount, rowsSelected = int64(0), int64(0)
+				}
+				if usage, ok := usages[[2]int64{tb.ID, idxInfo.ID}]; ok {
+					queryCount, rowsSelected = usage.GetInt64(2), usage.GetInt64(3)
+					if !usage.IsNull(4) {
+						lastUsedAt = usage.GetTime(4)
+					}
+				}
+				record := types.MakeDatums(
+					schema.Name.O,  // TABLE_SCHEMA
+					tb.Name.O,      // TABLE_NAME
+					idxInfo.Name.O, // INDEX_NAME
+					queryCount,     // QUERY_COUNT
+					rowsSelected,   // ROWS_SELECTED
+					lastUsedAt,     // LAST_USED_AT
+				)
+				rows = append(rows, record)
+			}
+		}
+	}
+	e.rows = rows
+	return nil
+}
+
+func (e *memtableRetriever) setDataForWriteConflicts(ctx sessionctx.Context) error {
+	if !hasPriv(ctx, mysql.ProcessPriv) {
+		return plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+
+	e.rows = conflicthistory.GlobalConflictHistory.GetAllDatum()
+	return nil
+}
+
+func (e *memtableRetriever) setDataForEffectivePrivileges(ctx sessionctx.Context) {
+	checker := privilege.GetPrivilegeManager(ctx)
+	if checker == nil {
+		return
+	}
+	// Seeing the privileges of the other users requires the SELECT privilege on mysql.user or the CREATE USER privilege.
+	loginUser := ctx.GetSessionVars().User
+	activeRoles := ctx.GetSessionVars().ActiveRoles
+	seeAll := checker.RequestVerification(activeRoles, mysql.SystemDB, mysql.UserTable, "", mysql.SelectPriv) ||
+		checker.RequestVerification(activeRoles, "", "", "", mysql.CreateUserPriv)
+	rows := checker.EffectivePrivilegesTable()
+	e.rows = make([][]types.Datum, 0, len(rows))
+	for _, row := range rows {
+		if !seeAll && (loginUser == nil || row[0].GetString() != loginUser.AuthUsername || row[1].GetString() != loginUser.AuthHostname) {
+			continue
+		}
+		e.rows = append(e.rows, row)
+	}
+}
+
 func (e *memtableRetriever) setDataForClusterDeadlock(ctx sessionctx.Context) error {
 	err := e.setDataForDeadlock(ctx)
 	if err != nil {
@@ -20,13 +20,16 @@ import (
 	"runtime/trace"
 	"time"
 
+	"github.com/cznic/mathutil"
 	"github.com/opentracing/opentracing-go"
+	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
@@ -111,7 +114,7 @@ func (e *InsertExec) exec(ctx context.Context, rows [][]types.Datum) error {
 	return nil
 }
 
-func prefetchUniqueIndices(ctx context.Context, txn kv.Transaction, rows []toBeCheckedRow) (map[string][]byte, error) {
+func prefetchUniqueIndices(ctx context.Context, txn kv.BatchGetter, rows []toBeCheckedRow) (map[string][]byte, error) {
 	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
 		span1 := span.Tracer().StartSpan("prefetchUniqueIndices", opentracing.ChildOf(span.Context()))
 		defer span1.Finish()
@@ -143,7 +146,7 @@ func prefetchUniqueIndices(ctx context.Context, txn kv.Transaction, rows []toBeC
 	return txn.BatchGet(ctx, batchKeys)
 }
 
-func prefetchConflictedOldRows(ctx context.Context, txn kv.Transaction, rows []toBeCheckedRow, values map[string][]byte) error {
+func prefetchConflictedOldRows(ctx context.Context, txn kv.BatchGetter, rows []toBeCheckedRow, values map[string][]byte) error {
 	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
 		span1 := span.Tracer().StartSpan("prefetchConflictedOldRows", opentracing.ChildOf(span.Context()))
 		defer span1.Finish()
@@ -166,7 +169,7 @@ func prefetchConflictedOldRows(ctx context.Context, txn kv.Transaction, rows []t
 	return err
 }
 
-func prefetchDataCache(ctx context.Context, txn kv.Transaction, rows []toBeCheckedRow) error {
+func prefetchDataCache(ctx context.Context, txn kv.BatchGetter, rows []toBeCheckedRow) error {
 	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
 		span1 := span.Tracer().StartSpan("prefetchDataCache", opentracing.ChildOf(span.Context()))
 		defer span1.Finish()
@@ -219,16 +222,61 @@ func (e *InsertExec) batchUpdateDupRows(ctx context.Context, newRows [][]types.D
 			defer snapshot.SetOption(kv.CollectRuntimeStats, nil)
 		}
 	}
+	batchSize := e.ctx.GetSessionVars().OnDupBatchSize
+	if batchSize <= 0 || batchSize > len(toBeCheckedRows) {
+		batchSize = len(toBeCheckedRows)
+	}
 	prefetchStart := time.Now()
 	// Use BatchGet to fill cache.
 	// It's an optimization and could be removed without affecting correctness.
-	if err = prefetchDataCache(ctx, txn, toBeCheckedRows); err != nil {
+	if err = prefetchDataCache(ctx, txn, toBeCheckedRows[:batchSize]); err != nil {
 		return err
 	}
 	if e.stats != nil {
 		e.stats.Prefetch += time.Since(prefetchStart)
 	}
-	for i, r := range toBeCheckedRows {
+	snapshot := txn.GetSnapshot()
+	for begin := 0; begin < len(toBeCheckedRows); begin += batchSize {
+		end := mathutil.Min(begin+batchSize, len(toBeCheckedRows))
+		// Prefetch the next batch from the snapshot while the current batch is being updated, the snapshot
+		// cache is shared with the transaction but the memory buffer can't be accessed concurrently.
+		var prefetchCh chan error
+		if end < len(toBeCheckedRows) && snapshot != nil {
+			next := toBeCheckedRows[end:mathutil.Min(end+batchSize, len(toBeCheckedRows))]
+			prefetchCh = make(chan error, 1)
+			go util.WithRecovery(func() {
+				prefetchCh <- prefetchDataCache(ctx, snapshot, next)
+			}, func(r interface{}) {
+				if r != nil {
+					prefetchCh <- errors.Errorf("%v", r)
+				}
+			})
+		}
+		err = e.updateDupRowsInBatch(ctx, txn, toBeCheckedRows[begin:end], newRows, begin)
+		if prefetchCh != nil {
+			prefetchStart = time.Now()
+			if prefetchErr := <-prefetchCh; err == nil {
+				err = prefetchErr
+			}
+			if e.stats != nil {
+				e.stats.Prefetch += time.Since(prefetchStart)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if e.stats != nil {
+		e.stats.CheckInsertTime += time.Since(start)
+	}
+	return nil
+}
+
+// updateDupRowsInBatch updates the rows if they are duplicate with rows in table, or inserts them otherwise.
+// offset is the index of the first row of the batch in newRows.
+func (e *InsertExec) updateDupRowsInBatch(ctx context.Context, txn kv.Transaction, toBeCheckedRows []toBeCheckedRow, newRows [][]types.Datum, offset int) error {
+	for j, r := range toBeCheckedRows {
+		i := offset + j
 		if r.handleKey != nil {
 			handle, err := tablecodec.DecodeRowKey(r.handleKey.newKey)
 			if err != nil {
@@ -285,9 +333,6 @@ func (e *InsertExec) batchUpdateDupRows(ctx context.Context, newRows [][]types.D
 			}
 		}
 	}
-	if e.stats != nil {
-		e.stats.CheckInsertTime += time.Since(start)
-	}
 	return nil
 }
 
@@ -1108,6 +1108,14 @@ func (e *InsertValues) addRecord(ctx context.Context, row []types.Datum) error {
 
 func (e *InsertValues) addRecordWithAutoIDHint(ctx context.Context, row []types.Datum, reserveAutoIDCount int) (err error) {
 	vars := e.ctx.GetSessionVars()
+	if err = checkRowConstraints(e.ctx, e.Table, row); err != nil {
+		// For `INSERT IGNORE` and `LOAD DATA`, the row violating the check constraints is skipped.
+		if table.ErrCheckConstraintViolated.Equal(err) && vars.StmtCtx.DupKeyAsWarning {
+			vars.StmtCtx.AppendWarning(err)
+			return nil
+		}
+		return err
+	}
 	if !vars.ConstraintCheckInPlace {
 		vars.PresumeKeyNotExists = true
 	}
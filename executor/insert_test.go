@@ -235,6 +235,26 @@ func (s *testSuite8) TestClusterIndexInsertOnDuplicateKey(c *C) {
 	c.Assert(err, ErrorMatches, ".*Duplicate entry 'a-b-c' for.*")
 }
 
+func (s *testSuite8) TestInsertOnDuplicateKeyInBatches(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t1")
+	tk.MustExec("create table t(a int primary key, b int, c int, unique key(b))")
+	tk.MustExec("insert into t values (1, 1, 1), (3, 3, 3), (5, 5, 5)")
+	tk.MustExec("set @@tidb_on_dup_batch_size = 2")
+	defer tk.MustExec("set @@tidb_on_dup_batch_size = default")
+
+	// The rows conflict with the existing rows and the rows inserted by the previous batches.
+	tk.MustExec("insert into t values (1, 10, 1), (2, 2, 2), (30, 3, 3), (2, 20, 2), (6, 6, 6), (7, 5, 7), (6, 60, 6) on duplicate key update c = c + 10")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(12))
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1 11", "2 2 12", "3 3 13", "5 5 15", "6 6 16"))
+
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("insert into t1 values (1, 100), (2, 200), (4, 400), (4, 401), (5, 500)")
+	tk.MustExec("insert into t (a, b, c) select a, b, a from t1 on duplicate key update c = values(b)")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1 100", "2 2 200", "3 3 13", "4 400 401", "5 5 500", "6 6 16"))
+}
+
 func (s *testSuite10) TestPaddingCommonHandle(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
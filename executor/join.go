@@ -87,10 +87,23 @@ type HashJoinExec struct {
 	prepared    bool
 	isOuterJoin bool
 
+	// isNullAware indicates the join is a null-aware anti semi join, whose join keys are the operands of
+	// `not in (subq)` and may be null. See joinNAAJMatchProbeSideRow2Chunk for details.
+	isNullAware bool
+	// naKeyOnly indicates the null-aware join has only one join key and no other conditions, so a null in the
+	// join key is enough to decide the result to be null if the build side is not empty.
+	naKeyOnly bool
+	// nullKeyBuildRows stores the build side rows whose join keys contain null for the null-aware join.
+	nullKeyBuildRows []chunk.Row
+
 	// joinWorkerWaitGroup is for sync multiple join workers.
 	joinWorkerWaitGroup sync.WaitGroup
 	finished            atomic.Value
 
+	// probePruner prunes the partitions read by the probe side by the build side rows if it's set, and the probe
+	// side is opened after the build side finishes then.
+	probePruner *probePartitionPruner
+
 	stats *hashJoinRuntimeStats
 }
 
@@ -148,13 +161,21 @@ func (e *HashJoinExec) Close() error {
 	if e.stats != nil && e.rowContainer != nil {
 		e.stats.hashStat = e.rowContainer.stat
 	}
+	if e.probePruner != nil && !e.probePruner.opened {
+		return e.buildSideExec.Close()
+	}
 	err := e.baseExecutor.Close()
 	return err
 }
 
 // Open implements the Executor Open interface.
 func (e *HashJoinExec) Open(ctx context.Context) error {
-	if err := e.baseExecutor.Open(ctx); err != nil {
+	if e.probePruner != nil {
+		e.probePruner.reset()
+		if err := e.buildSideExec.Open(ctx); err != nil {
+			return err
+		}
+	} else if err := e.baseExecutor.Open(ctx); err != nil {
 		return err
 	}
 
@@ -188,6 +209,18 @@ func (e *HashJoinExec) Open(ctx context.Context) error {
 // and sends the chunks to multiple channels which will be read by multiple join workers.
 func (e *HashJoinExec) fetchProbeSideChunks(ctx context.Context) {
 	hasWaitedForBuild := false
+	if e.probePruner != nil {
+		skip, err := e.openPrunedProbeSide(ctx)
+		if err != nil {
+			e.joinResultCh <- &hashjoinWorkerResult{
+				err: err,
+			}
+			return
+		} else if skip {
+			return
+		}
+		hasWaitedForBuild = true
+	}
 	for {
 		if e.finished.Load().(bool) {
 			return
@@ -240,6 +273,28 @@ func (e *HashJoinExec) fetchProbeSideChunks(ctx context.Context) {
 	}
 }
 
+// openPrunedProbeSide waits for the build side, and opens the probe side reading only the partitions located by
+// the build side rows. It returns true if no probe side row needs to be read.
+func (e *HashJoinExec) openPrunedProbeSide(ctx context.Context) (skip bool, err error) {
+	emptyBuild, err := e.wait4BuildSide()
+	if err != nil || emptyBuild {
+		return true, err
+	}
+	partitions := e.probePruner.prune()
+	if e.stats != nil {
+		e.stats.probePartitions = partitions
+		e.stats.totalProbePartitions = len(e.probePruner.partitions)
+	}
+	if partitions == 0 {
+		return true, nil
+	}
+	if err = e.probeSideExec.Open(ctx); err != nil {
+		return true, err
+	}
+	e.probePruner.opened = true
+	return false, nil
+}
+
 func (e *HashJoinExec) wait4BuildSide() (emptyBuild bool, err error) {
 	select {
 	case <-e.closeCh:
@@ -546,6 +601,96 @@ func (e *HashJoinExec) joinMatchedProbeSideRow2Chunk(workerID uint, probeKey uin
 	return true, joinResult
 }
 
+// joinNAAJMatchProbeSideRow2Chunk joins a probe side row for the null-aware anti semi join. The joiners evaluate
+// the null-aware equal conditions along with the other conditions, so the result of `not in` is:
+// 1. false, if any build side row matches the probe side row. If the join key of the probe side row is not null,
+//    only the build side rows with the same join key or with null in their join keys can match it;
+// 2. null, if no build side row matches the probe side row, but some conditions are evaluated to null for the
+//    build side rows whose join keys contain null, or for any build side row if the probe side key contains null;
+// 3. true, otherwise.
+func (e *HashJoinExec) joinNAAJMatchProbeSideRow2Chunk(workerID uint, probeKey uint64, probeKeyNull bool, probeSideRow chunk.Row,
+	hCtx *hashContext, joinResult *hashjoinWorkerResult) (bool, *hashjoinWorkerResult) {
+	var (
+		ok, matched, hasNull bool
+		buildSideRows        []chunk.Row
+		err                  error
+	)
+	if !probeKeyNull {
+		buildSideRows, _, err = e.rowContainer.GetMatchedRowsAndPtrs(probeKey, probeSideRow, hCtx)
+		if err != nil {
+			joinResult.err = err
+			return false, joinResult
+		}
+		ok, matched, hasNull, joinResult = e.tryToMatchNAAJInners(workerID, probeSideRow, buildSideRows, joinResult)
+		if !ok || matched {
+			return ok, joinResult
+		}
+		if e.naKeyOnly && len(e.nullKeyBuildRows) > 0 {
+			e.joiners[workerID].onMissMatch(true, probeSideRow, joinResult.chk)
+			return true, joinResult
+		}
+		ok, matched, hasNull, joinResult = e.tryToMatchNAAJInners(workerID, probeSideRow, e.nullKeyBuildRows, joinResult)
+		if !ok || matched {
+			return ok, joinResult
+		}
+		e.joiners[workerID].onMissMatch(hasNull, probeSideRow, joinResult.chk)
+		return true, joinResult
+	}
+	if e.naKeyOnly || e.rowContainer.NumRows() == 0 {
+		e.joiners[workerID].onMissMatch(e.rowContainer.NumRows() > 0, probeSideRow, joinResult.chk)
+		return true, joinResult
+	}
+	// The probe side key contains null, so all the build side rows need to be checked.
+	hasAnyNull := false
+	for chkIdx := 0; chkIdx < e.rowContainer.NumChunks(); chkIdx++ {
+		numRows := e.rowContainer.NumRowsOfChunk(chkIdx)
+		buildSideRows = buildSideRows[:0]
+		for rowIdx := 0; rowIdx < numRows; rowIdx++ {
+			row, err := e.rowContainer.GetRow(chunk.RowPtr{ChkIdx: uint32(chkIdx), RowIdx: uint32(rowIdx)})
+			if err != nil {
+				joinResult.err = err
+				return false, joinResult
+			}
+			buildSideRows = append(buildSideRows, row)
+		}
+		ok, matched, hasNull, joinResult = e.tryToMatchNAAJInners(workerID, probeSideRow, buildSideRows, joinResult)
+		if !ok || matched {
+			return ok, joinResult
+		}
+		hasAnyNull = hasAnyNull || hasNull
+	}
+	e.joiners[workerID].onMissMatch(hasAnyNull, probeSideRow, joinResult.chk)
+	return true, joinResult
+}
+
+// tryToMatchNAAJInners tries to match the probe side row with the build side rows for the null-aware join,
+// onMissMatch is left to the caller since there may be more build side rows to check.
+func (e *HashJoinExec) tryToMatchNAAJInners(workerID uint, probeSideRow chunk.Row, buildSideRows []chunk.Row,
+	joinResult *hashjoinWorkerResult) (ok, hasMatch, hasNull bool, _ *hashjoinWorkerResult) {
+	if len(buildSideRows) == 0 {
+		return true, false, false, joinResult
+	}
+	iter := chunk.NewIterator4Slice(buildSideRows)
+	for iter.Begin(); iter.Current() != iter.End(); {
+		matched, isNull, err := e.joiners[workerID].tryToMatchInners(probeSideRow, iter, joinResult.chk)
+		if err != nil {
+			joinResult.err = err
+			return false, false, false, joinResult
+		}
+		hasMatch = hasMatch || matched
+		hasNull = hasNull || isNull
+
+		if joinResult.chk.IsFull() {
+			e.joinResultCh <- joinResult
+			ok, joinResult = e.getNewJoinResult(workerID)
+			if !ok {
+				return false, false, false, joinResult
+			}
+		}
+	}
+	return true, hasMatch, hasNull, joinResult
+}
+
 func (e *HashJoinExec) getNewJoinResult(workerID uint) (bool, *hashjoinWorkerResult) {
 	joinResult := &hashjoinWorkerResult{
 		src: e.joinChkResourceCh[workerID],
@@ -589,8 +734,14 @@ func (e *HashJoinExec) join2Chunk(workerID uint, probeSideChk *chunk.Chunk, hCtx
 			joinResult.err = ErrQueryInterrupted
 			return false, joinResult
 		}
-		if !selected[i] || hCtx.hasNull[i] { // process unmatched probe side rows
+		if !selected[i] || (hCtx.hasNull[i] && !e.isNullAware) { // process unmatched probe side rows
 			e.joiners[workerID].onMissMatch(false, probeSideChk.GetRow(i), joinResult.chk)
+		} else if e.isNullAware {
+			probeKey, probeRow := hCtx.hashVals[i].Sum64(), probeSideChk.GetRow(i)
+			ok, joinResult = e.joinNAAJMatchProbeSideRow2Chunk(workerID, probeKey, hCtx.hasNull[i], probeRow, hCtx, joinResult)
+			if !ok {
+				return false, joinResult
+			}
 		} else { // process matched probe side rows
 			probeKey, probeRow := hCtx.hashVals[i].Sum64(), probeSideChk.GetRow(i)
 			ok, joinResult = e.joinMatchedProbeSideRow2Chunk(workerID, probeKey, probeRow, hCtx, joinResult)
@@ -741,6 +892,7 @@ func (e *HashJoinExec) buildHashTableForList(buildSideResultCh <-chan *chunk.Chu
 	var err error
 	var selected []bool
 	e.rowContainer = newHashRowContainer(e.ctx, int(e.buildSideEstCount), hCtx)
+	e.rowContainer.recordNullKeyRows = e.isNullAware
 	e.rowContainer.GetMemTracker().AttachTo(e.memTracker)
 	e.rowContainer.GetMemTracker().SetLabel(memory.LabelForBuildSideResult)
 	e.rowContainer.GetDiskTracker().AttachTo(e.diskTracker)
@@ -759,6 +911,9 @@ func (e *HashJoinExec) buildHashTableForList(buildSideResultCh <-chan *chunk.Chu
 		if e.finished.Load().(bool) {
 			return nil
 		}
+		if e.probePruner != nil {
+			e.probePruner.collect(e.ctx, chk)
+		}
 		if !e.useOuterToBuild {
 			err = e.rowContainer.PutChunk(chk, e.isNullEQ)
 		} else {
@@ -779,7 +934,10 @@ func (e *HashJoinExec) buildHashTableForList(buildSideResultCh <-chan *chunk.Chu
 			return err
 		}
 	}
-	return nil
+	if e.isNullAware {
+		e.nullKeyBuildRows, err = e.rowContainer.GetNullKeyRows()
+	}
+	return err
 }
 
 // NestedLoopApplyExec is the executor for apply.
@@ -1099,6 +1257,10 @@ type hashJoinRuntimeStats struct {
 	probe                  int64
 	concurrent             int
 	maxFetchAndProbe       int64
+	// probePartitions and totalProbePartitions are the numbers of partitions read by the probe side after and
+	// before the runtime partition pruning.
+	probePartitions      int
+	totalProbePartitions int
 }
 
 func (e *hashJoinRuntimeStats) setMaxFetchAndProbeTime(t int64) {
@@ -1146,6 +1308,13 @@ func (e *hashJoinRuntimeStats) String() string {
 		}
 		buf.WriteString("}")
 	}
+	if e.totalProbePartitions > 0 {
+		buf.WriteString(", probe_partitions:{read:")
+		buf.WriteString(strconv.Itoa(e.probePartitions))
+		buf.WriteString(", total:")
+		buf.WriteString(strconv.Itoa(e.totalProbePartitions))
+		buf.WriteString("}")
+	}
 	return buf.String()
 }
 
@@ -1157,6 +1326,8 @@ func (e *hashJoinRuntimeStats) Clone() execdetails.RuntimeStats {
 		probe:                  e.probe,
 		concurrent:             e.concurrent,
 		maxFetchAndProbe:       e.maxFetchAndProbe,
+		probePartitions:        e.probePartitions,
+		totalProbePartitions:   e.totalProbePartitions,
 	}
 }
 
@@ -1173,4 +1344,6 @@ func (e *hashJoinRuntimeStats) Merge(rs execdetails.RuntimeStats) {
 	if e.maxFetchAndProbe < tmp.maxFetchAndProbe {
 		e.maxFetchAndProbe = tmp.maxFetchAndProbe
 	}
+	e.probePartitions += tmp.probePartitions
+	e.totalProbePartitions += tmp.totalProbePartitions
 }
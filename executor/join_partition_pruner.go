@@ -0,0 +1,191 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"github.com/pingcap/parser/mysql"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// probePartitionPruner prunes the partitions read by the probe side of a hash join in the dynamic prune mode.
+// All the partition columns of the probe side table are equal to some build side join keys, so only the
+// partitions located by the build side rows may contain the matched rows. The probe side is opened after the
+// build side finishes, and only reads these partitions.
+type probePartitionPruner struct {
+	tbl    table.PartitionedTable
+	reader *TableReaderExecutor
+	// partitions are the partitions left by the condition based pruning.
+	partitions []table.PhysicalTable
+	// buildKeyColIdx and partColOffsets are the build side column indices and the table column offsets of
+	// the partition columns.
+	buildKeyColIdx []int
+	partColOffsets []int
+	buildTypes     []*types.FieldType
+	locateKey      []types.Datum
+	located        map[int64]struct{}
+	// skip is set when the partitions can't be pruned any more, e.g. a build side row fails to be located or
+	// all the partitions are located.
+	skip bool
+	// opened indicates whether the probe side is opened.
+	opened bool
+}
+
+// buildProbePartitionPruner returns the pruner for the probe side of the hash join, or nil if it can't be pruned
+// at runtime. It requires the probe side to be a table reader of a partitioned table in the dynamic prune mode,
+// and the unmatched probe side rows not to be outputted.
+func (b *executorBuilder) buildProbePartitionPruner(e *HashJoinExec) *probePartitionPruner {
+	if e.isNullAware {
+		return nil
+	}
+	switch e.joinType {
+	case plannercore.InnerJoin, plannercore.SemiJoin:
+	case plannercore.LeftOuterJoin, plannercore.RightOuterJoin:
+		if !e.useOuterToBuild {
+			return nil
+		}
+	default:
+		return nil
+	}
+	for _, nullEQ := range e.isNullEQ {
+		if nullEQ {
+			return nil
+		}
+	}
+	reader, ok := e.probeSideExec.(*TableReaderExecutor)
+	if !ok {
+		return nil
+	}
+	rangeBuilder, ok := reader.kvRangeBuilder.(kvRangeBuilderFromRangeAndPartition)
+	if !ok {
+		return nil
+	}
+	tbl, ok := reader.table.(table.PartitionedTable)
+	if !ok {
+		return nil
+	}
+	type partitionExpr interface {
+		PartitionExpr() (*tables.PartitionExpr, error)
+	}
+	pe, err := tbl.(partitionExpr).PartitionExpr()
+	if err != nil {
+		return nil
+	}
+
+	cols := tbl.Cols()
+	buildKeyColIdx := make([]int, 0, len(pe.ColumnOffset))
+	for _, offset := range pe.ColumnOffset {
+		idx := -1
+		for i, probeKey := range e.probeKeys {
+			probeCol := reader.Schema().Columns[probeKey.Index]
+			if probeCol.ID != cols[offset].ID {
+				continue
+			}
+			buildType := e.buildTypes[e.buildKeys[i].Index]
+			// The build side values are used to locate the partitions directly, so only the integer keys with the
+			// same signedness as the partition column are supported.
+			if buildType.EvalType() != types.ETInt || cols[offset].FieldType.EvalType() != types.ETInt ||
+				mysql.HasUnsignedFlag(buildType.Flag) != mysql.HasUnsignedFlag(cols[offset].Flag) {
+				continue
+			}
+			idx = e.buildKeys[i].Index
+			break
+		}
+		if idx == -1 {
+			return nil
+		}
+		buildKeyColIdx = append(buildKeyColIdx, idx)
+	}
+	if len(buildKeyColIdx) == 0 {
+		return nil
+	}
+	return &probePartitionPruner{
+		tbl:            tbl,
+		reader:         reader,
+		partitions:     rangeBuilder.partitions,
+		buildKeyColIdx: buildKeyColIdx,
+		partColOffsets: pe.ColumnOffset,
+		buildTypes:     e.buildTypes,
+		locateKey:      make([]types.Datum, len(cols)),
+	}
+}
+
+// reset prepares the pruner for a new execution.
+func (p *probePartitionPruner) reset() {
+	p.located = make(map[int64]struct{}, len(p.partitions))
+	p.skip = false
+	p.opened = false
+	p.reader.kvRangeBuilder = kvRangeBuilderFromRangeAndPartition{
+		sctx:       p.reader.ctx,
+		partitions: p.partitions,
+	}
+}
+
+// collect locates the partitions of the build side rows.
+func (p *probePartitionPruner) collect(sctx sessionctx.Context, chk *chunk.Chunk) {
+	if p.skip {
+		return
+	}
+	for i := 0; i < chk.NumRows(); i++ {
+		row := chk.GetRow(i)
+		hasNull := false
+		for j, idx := range p.buildKeyColIdx {
+			// The rows with null keys never match.
+			if row.IsNull(idx) {
+				hasNull = true
+				break
+			}
+			p.locateKey[p.partColOffsets[j]] = row.GetDatum(idx, p.buildTypes[idx])
+		}
+		if hasNull {
+			continue
+		}
+		partition, err := p.tbl.GetPartitionByRow(sctx, p.locateKey)
+		if err != nil {
+			// No row in the table has the keys.
+			if table.ErrNoPartitionForGivenValue.Equal(err) {
+				continue
+			}
+			p.skip = true
+			return
+		}
+		p.located[partition.GetPhysicalID()] = struct{}{}
+		if len(p.located) == len(p.tbl.Meta().Partition.Definitions) {
+			p.skip = true
+			return
+		}
+	}
+}
+
+// prune restricts the probe side reader to the located partitions, and returns the number of partitions to read.
+func (p *probePartitionPruner) prune() int {
+	if p.skip {
+		return len(p.partitions)
+	}
+	partitions := make([]table.PhysicalTable, 0, len(p.located))
+	for _, partition := range p.partitions {
+		if _, ok := p.located[partition.GetPhysicalID()]; ok {
+			partitions = append(partitions, partition)
+		}
+	}
+	p.reader.kvRangeBuilder = kvRangeBuilderFromRangeAndPartition{
+		sctx:       p.reader.ctx,
+		partitions: partitions,
+	}
+	return len(partitions)
+}
@@ -2609,3 +2609,43 @@ func (s *testSuiteJoinSerial) TestIssue25902(c *C) {
 	tk.MustQuery("select * from tt1 where ts in (select ts from tt2);").Check(testkit.Rows())
 	tk.MustExec("set @@session.time_zone = @tmp;")
 }
+
+func (s *testSuiteJoin3) TestNullAwareAntiJoin(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int, c int)")
+	tk.MustExec("create table t2(a int, b int, c int)")
+	tk.MustExec("insert into t1 values(1, 1, 1), (2, 2, 2), (null, 3, 3), (4, null, 4), (null, null, 5)")
+	tk.MustExec("insert into t2 values(1, 1, 1), (null, 2, 2), (3, null, 3)")
+
+	queries := []string{
+		"select * from t1 where a not in (select a from t2)",
+		"select * from t1 where a not in (select a from t2 where a is not null)",
+		"select * from t1 where a not in (select a from t2 where a > 100)",
+		"select * from t1 where a not in (select a from t2 where t2.c > t1.c)",
+		"select * from t1 where (a, b) not in (select a, b from t2)",
+		"select * from t1 where (a, b) not in (select a, b from t2 where t2.c < t1.c)",
+		"select a, a not in (select a from t2) from t1",
+		"select a, a not in (select a from t2 where a is not null) from t1",
+		"select a, a not in (select a from t2 where t2.c > t1.c) from t1",
+		"select a, b, (a, b) not in (select a, b from t2) from t1",
+		"select a, b, (a, b) not in (select b, a from t2 where t2.c <= t1.c) from t1",
+	}
+	results := make([][][]interface{}, 0, len(queries))
+	for _, q := range queries {
+		results = append(results, tk.MustQuery(q).Sort().Rows())
+	}
+	tk.MustExec("set @@tidb_enable_null_aware_anti_join = 1")
+	for i, q := range queries {
+		rows := tk.MustQuery("explain format = 'brief' " + q).Rows()
+		c.Assert(rows[0][4], Matches, "Null-aware anti.*", Commentf("sql: %s", q))
+		tk.MustQuery(q).Sort().Check(results[i])
+	}
+	tk.MustQuery("select * from t1 where a not in (select a from t2 where a is not null)").Sort().Check(testkit.Rows("2 2 2", "4 <nil> 4"))
+	tk.MustQuery("select a, a not in (select a from t2) from t1").Sort().Check(testkit.Rows("1 0", "2 <nil>", "4 <nil>", "<nil> <nil>", "<nil> <nil>"))
+
+	// The build side is empty.
+	tk.MustExec("delete from t2")
+	tk.MustQuery("select a, a not in (select a from t2) from t1").Sort().Check(testkit.Rows("1 1", "2 1", "4 1", "<nil> 1", "<nil> 1"))
+	tk.MustQuery("select * from t1 where a not in (select a from t2)").Check(testkit.Rows("1 1 1", "2 2 2", "<nil> 3 3", "4 <nil> 4", "<nil> <nil> 5"))
+}
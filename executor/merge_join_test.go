@@ -990,3 +990,77 @@ func (s *testSuite2) TestShuffleMergeJoinWithOtherConditions(c *C) {
 		`2`,
 	))
 }
+
+func (s *testSuite2) TestParallelMergeJoin(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int, index idx(a, b))")
+	tk.MustExec("create table t2(a int, b int, index idx(a, b))")
+	for i := 0; i < 300; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t1 values(%d, %d)", i%50, i))
+		tk.MustExec(fmt.Sprintf("insert into t2 values(%d, %d)", i%70+20, i%7))
+	}
+	tk.MustExec("insert into t1 values(null, 1), (null, 2)")
+	tk.MustExec("insert into t2 values(null, 1), (1000, 3)")
+	// The skewed outer key is cut into several tasks, which share the same inner group.
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t1 values(25, %d)", i+1000))
+	}
+	// The outer rows are cut into many tasks, some of them share the same inner group.
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+
+	queries := []string{
+		"select /*+ TIDB_SMJ(t1, t2) */ * from t1 use index(idx) join t2 use index(idx) on t1.a = t2.a",
+		"select /*+ TIDB_SMJ(t1, t2) */ * from t1 use index(idx) join t2 use index(idx) on t1.a = t2.a order by t1.a desc",
+		"select /*+ TIDB_SMJ(t1, t2) */ * from t1 use index(idx) left join t2 use index(idx) on t1.a = t2.a and t1.b > t2.b",
+		"select /*+ TIDB_SMJ(t1, t2) */ * from t1 use index(idx) right join t2 use index(idx) on t1.a = t2.a and t1.b < 100",
+		"select /*+ TIDB_SMJ(t1, t2) */ * from t1 use index(idx) where exists (select * from t2 use index(idx) where t1.a = t2.a and t1.b > t2.b)",
+		"select /*+ TIDB_SMJ(t1, t2) */ * from t1 use index(idx) where not exists (select * from t2 use index(idx) where t1.a = t2.a and t1.b > t2.b)",
+		"select /*+ TIDB_SMJ(t1, t2) */ t1.a, t1.a in (select t2.a from t2 use index(idx) where t1.b > t2.b) from t1 use index(idx)",
+	}
+	for _, query := range queries {
+		tk.MustExec("set @@tidb_parallel_merge_join_concurrency = 1")
+		expected := checkMergeAndRun(tk, c, query).Rows()
+		tk.MustExec("set @@tidb_parallel_merge_join_concurrency = 4")
+		checkMergeAndRun(tk, c, query).Check(expected)
+		for _, row := range tk.MustQuery("explain analyze " + query).Rows() {
+			if strings.Contains(row[0].(string), "MergeJoin") {
+				c.Assert(row[5], Matches, ".*Concurrency:4.*", Commentf("%s", query))
+			}
+		}
+	}
+}
+
+func (s *testSerialSuite1) TestParallelMergeJoinInDisk(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = true
+		conf.OOMAction = config.OOMActionLog
+	})
+
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int, index idx(a, b))")
+	tk.MustExec("create table t2(a int, b int, index idx(a, b))")
+	for i := 0; i < 300; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t1 values(%d, %d)", i%50, i))
+		tk.MustExec(fmt.Sprintf("insert into t2 values(%d, %d)", i%70+20, i%7))
+	}
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t1 values(25, %d)", i+1000))
+	}
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+
+	query := "select /*+ TIDB_SMJ(t1, t2) */ * from t1 use index(idx) left join t2 use index(idx) on t1.a = t2.a and t1.b > t2.b"
+	expected := checkMergeAndRun(tk, c, query).Rows()
+	tk.MustExec("set @@tidb_parallel_merge_join_concurrency = 4")
+	// The rate limit action of the coprocessor absorbs the exceeding, which is disabled to trigger the spilling.
+	tk.MustExec("set @@tidb_enable_rate_limit_action = 0")
+	tk.MustExec("set @@tidb_mem_quota_query = 1")
+	checkMergeAndRun(tk, c, query).Check(expected)
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.MemTracker.BytesConsumed(), Equals, int64(0))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.BytesConsumed(), Equals, int64(0))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), Greater, int64(0))
+}
@@ -0,0 +1,848 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+// ParallelMergeJoinExec executes the merge join in parallel.
+// The ordered outer input is cut into the ranges of maxChunkSize rows, each range is packed into a task together with
+// the inner rows of the same join keys, and the tasks are joined by the workers concurrently. The results are returned
+// in the order of the tasks, so the output is ordered in the same way as MergeJoinExec. The inner rows of the tasks are
+// kept in the row containers, which are spilled to disk together once the memory quota is exceeded.
+//
+//                       +-------------+
+//                       | Main Thread |  <--- resultTaskCh (the tasks in order)
+//                       +------+------+                  ^
+//                              ^ task.resultCh           |
+//                 +------------+------------+            |
+//            +----+---+               +-----+--+         |
+//            | worker |   .......     | worker |         |
+//            +----+---+               +-----+--+         |
+//                 ^                         ^            |
+//                 +------------+------------+            |
+//                              | taskCh                  |
+//                    +---------+--------+                |
+//                    | fetchAndSplit    +----------------+
+//                    +---------+--------+
+//                              ^
+//                    +---------+--------+
+//                    |  outer  |  inner |
+//                    +------------------+
+type ParallelMergeJoinExec struct {
+	baseExecutor
+
+	concurrency  int
+	compareFuncs []expression.CompareFunc
+	joiners      []joiner
+	desc         bool
+
+	outerIdx     int
+	outerKeys    []*expression.Column
+	innerKeys    []*expression.Column
+	outerFilters expression.CNFExprs
+	innerTypes   []*types.FieldType
+
+	prepared     bool
+	taskCh       chan *mergeJoinTask
+	resultTaskCh chan *mergeJoinTask
+	curTask      *mergeJoinTask
+	closeCh      chan struct{}
+	wg           sync.WaitGroup
+
+	memTracker  *memory.Tracker
+	diskTracker *disk.Tracker
+	// spillAction is nil if oom-use-tmp-storage is disabled.
+	spillAction *parallelMergeJoinSpillAction
+}
+
+// mergeJoinTask is a range of the outer rows and the inner rows whose join keys are in the range.
+type mergeJoinTask struct {
+	outerRows     []chunk.Row
+	outerSelected []bool
+	// group is the inner group shared with the previous tasks, its rows are followed by the rows in inner.
+	group *mergeJoinInnerGroup
+	inner mergeJoinInnerRows
+	// memUsage is the memory usage of the outer chunks fetched for the task, which is released after the task is joined.
+	memUsage int64
+
+	// resultCh receives the joined chunks, it's closed after the task is joined or err is set.
+	resultCh chan *chunk.Chunk
+	// giveBackCh receives the chunks consumed by the main thread, it belongs to the worker joining the task.
+	giveBackCh chan *chunk.Chunk
+	err        error
+}
+
+// numInner returns the number of the inner rows of the task.
+func (task *mergeJoinTask) numInner() int {
+	if task.group == nil {
+		return task.inner.numRows
+	}
+	return task.group.numRows + task.inner.numRows
+}
+
+// mergeJoinInnerRows holds the inner rows, which are added in full chunks, so the i-th row is at
+// RowPtr{i / maxChunkSize, i % maxChunkSize}. chk is the chunk being filled by the fetcher.
+type mergeJoinInnerRows struct {
+	rc      *chunk.RowContainer
+	chk     *chunk.Chunk
+	numRows int
+}
+
+// mergeJoinInnerGroup is the inner rows matching the last outer row of a task. An outer group may be cut into several
+// tasks, in which case the inner group is copied once and shared by all of them. It's released once the splitter and
+// all the tasks referring it drop their references.
+type mergeJoinInnerGroup struct {
+	mergeJoinInnerRows
+	refs atomic.Int32
+}
+
+// Open implements the Executor Open interface.
+func (e *ParallelMergeJoinExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.innerTypes = retTypes(e.children[1-e.outerIdx])
+	e.diskTracker = disk.NewTracker(e.id, -1)
+	e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+	e.spillAction = nil
+	if config.GetGlobalConfig().OOMUseTmpStorage {
+		e.spillAction = &parallelMergeJoinSpillAction{}
+		e.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(e.spillAction)
+	}
+	e.prepared = false
+	e.curTask = nil
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *ParallelMergeJoinExec) Close() error {
+	var firstErr error
+	if e.prepared {
+		close(e.closeCh)
+		e.wg.Wait()
+		// The tasks not consumed by the main thread are left in resultTaskCh.
+		if e.curTask != nil {
+			firstErr = e.releaseInner(e.curTask)
+		}
+		for task := range e.resultTaskCh {
+			if err := e.releaseInner(task); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		e.prepared = false
+	}
+	e.curTask = nil
+	if e.runtimeStats != nil {
+		runtimeStats := &execdetails.RuntimeStatsWithConcurrencyInfo{}
+		runtimeStats.SetConcurrencyInfo(execdetails.NewConcurrencyInfo("Concurrency", e.concurrency))
+		e.ctx.GetSessionVars().StmtCtx.RuntimeStatsColl.RegisterStats(e.id, runtimeStats)
+	}
+	e.memTracker = nil
+	e.diskTracker = nil
+	e.spillAction = nil
+	if err := e.baseExecutor.Close(); err != nil {
+		return err
+	}
+	return firstErr
+}
+
+func (e *ParallelMergeJoinExec) prepare(ctx context.Context) {
+	e.taskCh = make(chan *mergeJoinTask, e.concurrency)
+	e.resultTaskCh = make(chan *mergeJoinTask, e.concurrency)
+	e.closeCh = make(chan struct{})
+	e.wg.Add(e.concurrency + 1)
+	go e.fetchAndSplit(ctx)
+	for i := 0; i < e.concurrency; i++ {
+		w := &mergeJoinWorker{
+			e:       e,
+			joiner:  e.joiners[i],
+			chkCh:   make(chan *chunk.Chunk, 1),
+			chk:     newFirstChunk(e),
+			closeCh: e.closeCh,
+		}
+		go w.run(ctx)
+	}
+	e.prepared = true
+}
+
+// Next implements the Executor Next interface.
+func (e *ParallelMergeJoinExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if !e.prepared {
+		e.prepare(ctx)
+	}
+	for {
+		if e.curTask == nil {
+			task, ok := <-e.resultTaskCh
+			if !ok {
+				return nil
+			}
+			e.curTask = task
+		}
+		chk, ok := <-e.curTask.resultCh
+		if ok {
+			req.SwapColumns(chk)
+			e.curTask.giveBackCh <- chk
+			return nil
+		}
+		if e.curTask.err != nil {
+			return e.curTask.err
+		}
+		e.memTracker.Consume(-e.curTask.memUsage)
+		err := e.releaseInner(e.curTask)
+		e.curTask = nil
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// newInner creates the row container holding the inner rows of a task.
+func (e *ParallelMergeJoinExec) newInner() *chunk.RowContainer {
+	rc := chunk.NewRowContainer(e.innerTypes, e.maxChunkSize)
+	rc.GetMemTracker().AttachTo(e.memTracker)
+	rc.GetMemTracker().SetLabel(memory.LabelForInnerTable)
+	rc.GetDiskTracker().AttachTo(e.diskTracker)
+	rc.GetDiskTracker().SetLabel(memory.LabelForInnerTable)
+	if e.spillAction != nil {
+		e.spillAction.register(rc)
+	}
+	return rc
+}
+
+func (e *ParallelMergeJoinExec) closeInner(rc *chunk.RowContainer) error {
+	if rc == nil {
+		return nil
+	}
+	if e.spillAction != nil {
+		e.spillAction.unregister(rc)
+	}
+	err := rc.Close()
+	rc.GetMemTracker().Detach()
+	rc.GetDiskTracker().Detach()
+	return err
+}
+
+// releaseInner releases the inner rows of the task and its reference to the inner group.
+func (e *ParallelMergeJoinExec) releaseInner(task *mergeJoinTask) error {
+	err := e.closeInner(task.inner.rc)
+	task.inner = mergeJoinInnerRows{}
+	if task.group != nil {
+		if err1 := e.releaseGroup(task.group); err == nil {
+			err = err1
+		}
+		task.group = nil
+	}
+	return err
+}
+
+// releaseGroup drops a reference to the inner group, the group is closed once it isn't referred.
+func (e *ParallelMergeJoinExec) releaseGroup(group *mergeJoinInnerGroup) error {
+	if group.refs.Dec() > 0 {
+		return nil
+	}
+	return e.closeInner(group.rc)
+}
+
+// appendInner appends the inner row, the chunk is added to the container once it's full.
+func (e *ParallelMergeJoinExec) appendInner(rows *mergeJoinInnerRows, row chunk.Row) error {
+	if rows.chk == nil {
+		rows.chk = chunk.NewChunkWithCapacity(e.innerTypes, e.maxChunkSize)
+	}
+	rows.chk.AppendRow(row)
+	rows.numRows++
+	if rows.chk.NumRows() < e.maxChunkSize {
+		return nil
+	}
+	return e.flushInner(rows)
+}
+
+func (e *ParallelMergeJoinExec) flushInner(rows *mergeJoinInnerRows) error {
+	if rows.chk == nil || rows.chk.NumRows() == 0 {
+		return nil
+	}
+	// The chunk is referred by the container, so a new one is allocated for the next rows.
+	chk := rows.chk
+	rows.chk = nil
+	return rows.rc.Add(chk)
+}
+
+// getInnerRow returns the i-th inner row, the rows not added to the container yet are read from chk.
+func (e *ParallelMergeJoinExec) getInnerRow(rows *mergeJoinInnerRows, i int) (chunk.Row, error) {
+	if rows.chk != nil {
+		if flushed := rows.numRows - rows.chk.NumRows(); i >= flushed {
+			return rows.chk.GetRow(i - flushed), nil
+		}
+	}
+	return rows.rc.GetRow(chunk.RowPtr{ChkIdx: uint32(i / e.maxChunkSize), RowIdx: uint32(i % e.maxChunkSize)})
+}
+
+// getInner returns the i-th inner row of the task, the rows of the shared inner group come first.
+func (e *ParallelMergeJoinExec) getInner(task *mergeJoinTask, i int) (chunk.Row, error) {
+	if task.group != nil {
+		if i < task.group.numRows {
+			return e.getInnerRow(&task.group.mergeJoinInnerRows, i)
+		}
+		i -= task.group.numRows
+	}
+	return e.getInnerRow(&task.inner, i)
+}
+
+func (e *ParallelMergeJoinExec) compare(outerRow, innerRow chunk.Row) (int, error) {
+	for i := range e.outerKeys {
+		cmp, _, err := e.compareFuncs[i](e.ctx, e.outerKeys[i], e.innerKeys[i], outerRow, innerRow)
+		if err != nil {
+			return 0, err
+		}
+		if cmp != 0 {
+			if e.desc {
+				return -int(cmp), nil
+			}
+			return int(cmp), nil
+		}
+	}
+	return 0, nil
+}
+
+// sendTask sends the task to the main thread and the workers, it returns false if the executor is closed.
+func (e *ParallelMergeJoinExec) sendTask(task *mergeJoinTask) bool {
+	// The task is sent to the main thread first, so the main thread always waits for a task which is sent to the workers.
+	select {
+	case <-e.closeCh:
+		// The task is never seen by the main thread, so its inner rows are released here.
+		terror.Log(e.releaseInner(task))
+		return false
+	case e.resultTaskCh <- task:
+	}
+	if task.err != nil {
+		return false
+	}
+	select {
+	case <-e.closeCh:
+		return false
+	case e.taskCh <- task:
+	}
+	return true
+}
+
+func (e *ParallelMergeJoinExec) fetchAndSplit(ctx context.Context) {
+	s := &mergeJoinRangeSplitter{
+		e:     e,
+		outer: mergeJoinInput{exec: e.children[e.outerIdx], keys: e.outerKeys, filters: e.outerFilters},
+		inner: mergeJoinInput{exec: e.children[1-e.outerIdx], keys: e.innerKeys, copied: true},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err := errors.Errorf("%v", r)
+			logutil.Logger(ctx).Error("parallel merge join fetcher panicked", zap.Error(err), zap.Stack("stack"))
+			e.sendTask(newMergeJoinErrTask(err))
+		}
+		if s.lastInnerGroup != nil {
+			terror.Log(e.releaseGroup(s.lastInnerGroup))
+		}
+		close(e.taskCh)
+		close(e.resultTaskCh)
+		e.wg.Done()
+	}()
+	for {
+		task, err := s.nextTask(ctx)
+		if err != nil {
+			e.sendTask(newMergeJoinErrTask(err))
+			return
+		}
+		if task == nil || !e.sendTask(task) {
+			return
+		}
+	}
+}
+
+func newMergeJoinErrTask(err error) *mergeJoinTask {
+	task := &mergeJoinTask{resultCh: make(chan *chunk.Chunk), err: err}
+	close(task.resultCh)
+	return task
+}
+
+// mergeJoinInput is an ordered input of the parallel merge join.
+type mergeJoinInput struct {
+	exec    Executor
+	keys    []*expression.Column
+	filters expression.CNFExprs
+	// copied means the rows are copied into the tasks, so chk is reused. Otherwise a new chunk is fetched every time
+	// since the rows are referred by the tasks.
+	copied bool
+
+	// chk is read from pos.
+	chk       *chunk.Chunk
+	selected  []bool
+	pos       int
+	exhausted bool
+}
+
+// current returns the current row of the input, it fetches the next chunk if the current one is consumed.
+func (in *mergeJoinInput) current(ctx context.Context, e *ParallelMergeJoinExec, task *mergeJoinTask) (chunk.Row, bool, error) {
+	for !in.exhausted && (in.chk == nil || in.pos >= in.chk.NumRows()) {
+		if in.chk == nil || !in.copied {
+			in.chk = newFirstChunk(in.exec)
+		}
+		if err := Next(ctx, in.exec, in.chk); err != nil {
+			return chunk.Row{}, false, err
+		}
+		in.pos = 0
+		in.exhausted = in.chk.NumRows() == 0
+		if !in.copied {
+			memUsage := in.chk.MemoryUsage()
+			task.memUsage += memUsage
+			e.memTracker.Consume(memUsage)
+		}
+		if in.filters != nil {
+			var err error
+			in.selected, err = expression.VectorizedFilter(e.ctx, in.filters, chunk.NewIterator4Chunk(in.chk), nil)
+			if err != nil {
+				return chunk.Row{}, false, err
+			}
+		}
+	}
+	if in.exhausted {
+		return chunk.Row{}, false, nil
+	}
+	return in.chk.GetRow(in.pos), true, nil
+}
+
+func (in *mergeJoinInput) hasNullInJoinKey(row chunk.Row) bool {
+	for _, col := range in.keys {
+		if row.IsNull(col.Index) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeJoinRangeSplitter cuts the ordered inputs of the parallel merge join into the tasks.
+type mergeJoinRangeSplitter struct {
+	e     *ParallelMergeJoinExec
+	outer mergeJoinInput
+	inner mergeJoinInput
+
+	// lastInnerGroup holds the inner rows matching the last outer row of the previous task, which are shared with the
+	// next task if the outer group is cut. The splitter holds a reference to it.
+	lastInnerGroup *mergeJoinInnerGroup
+}
+
+// nextTask returns the next task, or nil if the outer input is exhausted. The inner rows out of the ranges of the
+// outer rows are skipped since they never match any outer row.
+func (s *mergeJoinRangeSplitter) nextTask(ctx context.Context) (task *mergeJoinTask, err error) {
+	task = &mergeJoinTask{resultCh: make(chan *chunk.Chunk)}
+	for len(task.outerRows) < s.e.maxChunkSize {
+		row, ok, err := s.outer.current(ctx, s.e, task)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		task.outerRows = append(task.outerRows, row)
+		task.outerSelected = append(task.outerSelected, s.outer.selected == nil || s.outer.selected[s.outer.pos])
+		s.outer.pos++
+	}
+	if len(task.outerRows) == 0 {
+		s.e.memTracker.Consume(-task.memUsage)
+		return nil, nil
+	}
+	defer func() {
+		if err != nil {
+			terror.Log(s.e.releaseInner(task))
+		}
+	}()
+
+	first, last := task.outerRows[0], task.outerRows[len(task.outerRows)-1]
+	// groupStart is the index of the first inner row matching the last outer row.
+	groupStart := 0
+	if group := s.lastInnerGroup; group != nil {
+		// The reference of the splitter is taken over by the task.
+		s.lastInnerGroup = nil
+		task.group = group
+		row, err := s.e.getInner(task, 0)
+		if err != nil {
+			return nil, err
+		}
+		cmp, err := s.e.compare(first, row)
+		if err != nil {
+			return nil, err
+		}
+		if cmp != 0 {
+			task.group = nil
+			if err = s.e.releaseGroup(group); err != nil {
+				return nil, err
+			}
+		} else if cmp, err = s.e.compare(last, row); err != nil {
+			return nil, err
+		} else if cmp != 0 {
+			groupStart = group.numRows
+		}
+	}
+	task.inner.rc = s.e.newInner()
+	for {
+		row, ok, err := s.inner.current(ctx, s.e, task)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		if s.inner.hasNullInJoinKey(row) {
+			s.inner.pos++
+			continue
+		}
+		cmp, err := s.e.compare(last, row)
+		if err != nil {
+			return nil, err
+		}
+		if cmp < 0 {
+			// The row belongs to the next tasks.
+			break
+		}
+		s.inner.pos++
+		if cmp > 0 {
+			cmpFirst, err := s.e.compare(first, row)
+			if err != nil {
+				return nil, err
+			}
+			if cmpFirst > 0 {
+				continue
+			}
+		}
+		if err = s.e.appendInner(&task.inner, row); err != nil {
+			return nil, err
+		}
+		if cmp > 0 {
+			groupStart = task.numInner()
+		}
+	}
+
+	if numInner := task.numInner(); groupStart < numInner {
+		if task.group != nil && groupStart == 0 && task.inner.numRows == 0 {
+			// The last outer row still matches the shared inner group, so it's shared with the next task as well.
+			task.group.refs.Inc()
+			s.lastInnerGroup = task.group
+		} else {
+			group := &mergeJoinInnerGroup{mergeJoinInnerRows: mergeJoinInnerRows{rc: s.e.newInner()}}
+			group.refs.Store(1)
+			s.lastInnerGroup = group
+			for i := groupStart; i < numInner; i++ {
+				row, err := s.e.getInner(task, i)
+				if err != nil {
+					return nil, err
+				}
+				if err = s.e.appendInner(&group.mergeJoinInnerRows, row); err != nil {
+					return nil, err
+				}
+			}
+			// The group is read by several workers, so all of its rows are added to the container.
+			if err = s.e.flushInner(&group.mergeJoinInnerRows); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err = s.e.flushInner(&task.inner); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// mergeJoinWorker joins the tasks of the parallel merge join.
+type mergeJoinWorker struct {
+	e      *ParallelMergeJoinExec
+	joiner joiner
+	// chk is the chunk to fill, it's given back through chkCh after it's sent to and consumed by the main thread.
+	chkCh   chan *chunk.Chunk
+	chk     *chunk.Chunk
+	closeCh <-chan struct{}
+}
+
+func (w *mergeJoinWorker) run(ctx context.Context) {
+	defer w.e.wg.Done()
+	for {
+		select {
+		case <-w.closeCh:
+			return
+		case task, ok := <-w.e.taskCh:
+			if !ok || !w.joinTask(ctx, task) {
+				return
+			}
+		}
+	}
+}
+
+// joinTask joins the task and sends the results to task.resultCh, it returns false if the executor is closed.
+func (w *mergeJoinWorker) joinTask(ctx context.Context, task *mergeJoinTask) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			task.err = errors.Errorf("%v", r)
+			logutil.Logger(ctx).Error("parallel merge join worker panicked", zap.Error(task.err), zap.Stack("stack"))
+			ok = false
+		}
+		close(task.resultCh)
+	}()
+	task.giveBackCh = w.chkCh
+	ok, task.err = w.join(task)
+	if !ok || task.err != nil {
+		return ok
+	}
+	if w.chk.NumRows() > 0 {
+		return w.flush(task)
+	}
+	return true
+}
+
+// flush sends the filled chunk to the main thread and waits for it to be given back.
+func (w *mergeJoinWorker) flush(task *mergeJoinTask) bool {
+	select {
+	case <-w.closeCh:
+		return false
+	case task.resultCh <- w.chk:
+	}
+	select {
+	case <-w.closeCh:
+		return false
+	case w.chk = <-w.chkCh:
+		w.chk.Reset()
+		return true
+	}
+}
+
+func (w *mergeJoinWorker) join(task *mergeJoinTask) (bool, error) {
+	// The inner rows in [innerPos, groupEnd) are the inner group matching the current outer row.
+	innerPos, groupEnd := 0, 0
+	numInner := task.numInner()
+	// innerRow is the inner row at innerPos.
+	var innerRow chunk.Row
+	loadedPos := -1
+	for i, outer := range task.outerRows {
+		matched := false
+		if task.outerSelected[i] {
+			cmp := -1
+			for innerPos < numInner {
+				var err error
+				if loadedPos != innerPos {
+					if innerRow, err = w.e.getInner(task, innerPos); err != nil {
+						return false, err
+					}
+					loadedPos = innerPos
+				}
+				if cmp, err = w.e.compare(outer, innerRow); err != nil {
+					return false, err
+				}
+				if cmp <= 0 {
+					break
+				}
+				innerPos++
+			}
+			matched = innerPos < numInner && cmp == 0
+		}
+		if !matched {
+			w.joiner.onMissMatch(false, outer, w.chk)
+		} else {
+			if groupEnd <= innerPos {
+				for groupEnd = innerPos + 1; groupEnd < numInner; groupEnd++ {
+					row, err := w.e.getInner(task, groupEnd)
+					if err != nil {
+						return false, err
+					}
+					cmp, err := w.e.compare(outer, row)
+					if err != nil {
+						return false, err
+					}
+					if cmp != 0 {
+						break
+					}
+				}
+			}
+			iter := &mergeJoinInnerIter{e: w.e, task: task, begin: innerPos, end: groupEnd}
+			if ok, err := w.matchInners(task, outer, iter); !ok || err != nil {
+				return ok, err
+			}
+		}
+		if w.chk.IsFull() && !w.flush(task) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (w *mergeJoinWorker) matchInners(task *mergeJoinTask, outer chunk.Row, iter chunk.Iterator) (bool, error) {
+	hasMatch, hasNull := false, false
+	for iter.Begin(); iter.Current() != iter.End(); {
+		matched, isNull, err := w.joiner.tryToMatchInners(outer, iter, w.chk)
+		if err != nil {
+			return false, err
+		}
+		hasMatch = hasMatch || matched
+		hasNull = hasNull || isNull
+		if w.chk.IsFull() && !w.flush(task) {
+			return false, nil
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return false, err
+	}
+	if !hasMatch {
+		w.joiner.onMissMatch(hasNull, outer, w.chk)
+	}
+	return true, nil
+}
+
+// mergeJoinInnerIter iterates the inner rows of a task in [begin, end).
+type mergeJoinInnerIter struct {
+	e          *ParallelMergeJoinExec
+	task       *mergeJoinTask
+	begin, end int
+	cur        int
+	row        chunk.Row
+	err        error
+}
+
+// Begin implements the chunk.Iterator interface.
+func (it *mergeJoinInnerIter) Begin() chunk.Row {
+	it.cur = it.begin - 1
+	return it.Next()
+}
+
+// Next implements the chunk.Iterator interface.
+func (it *mergeJoinInnerIter) Next() chunk.Row {
+	if it.cur >= it.end {
+		return it.End()
+	}
+	it.cur++
+	if it.cur >= it.end {
+		return it.End()
+	}
+	it.row, it.err = it.e.getInner(it.task, it.cur)
+	if it.err != nil {
+		it.ReachEnd()
+		return it.End()
+	}
+	return it.row
+}
+
+// End implements the chunk.Iterator interface.
+func (it *mergeJoinInnerIter) End() chunk.Row {
+	return chunk.Row{}
+}
+
+// Len implements the chunk.Iterator interface.
+func (it *mergeJoinInnerIter) Len() int {
+	return it.end - it.begin
+}
+
+// Current implements the chunk.Iterator interface.
+func (it *mergeJoinInnerIter) Current() chunk.Row {
+	if it.cur < it.begin || it.cur >= it.end {
+		return it.End()
+	}
+	return it.row
+}
+
+// ReachEnd implements the chunk.Iterator interface.
+func (it *mergeJoinInnerIter) ReachEnd() {
+	it.cur = it.end
+}
+
+// Error implements the chunk.Iterator interface.
+func (it *mergeJoinInnerIter) Error() error {
+	return it.err
+}
+
+// parallelMergeJoinSpillAction spills the inner rows of all the tasks of a ParallelMergeJoinExec to disk once the memory
+// quota is exceeded, the containers created after that are spilled on creation. If it's triggered again after the
+// spilling, the fallback action is called.
+type parallelMergeJoinSpillAction struct {
+	memory.BaseOOMAction
+	triggered atomic.Bool
+	spilled   atomic.Bool
+	mu        struct {
+		sync.Mutex
+		containers map[*chunk.RowContainer]struct{}
+	}
+}
+
+// Action implements the memory.ActionOnExceed interface.
+func (a *parallelMergeJoinSpillAction) Action(t *memory.Tracker) {
+	if a.triggered.CAS(false, true) {
+		logutil.BgLogger().Info("memory exceeds quota, spill the inner rows of parallel merge join to disk now.",
+			zap.Int64("consumed", t.BytesConsumed()), zap.Int64("quota", t.GetBytesLimit()))
+		// The action may be triggered by a container holding its own lock, so the containers are spilled in another
+		// goroutine.
+		go a.spillAll()
+		return
+	}
+	if !a.spilled.Load() || !t.CheckExceed() {
+		return
+	}
+	if fallback := a.GetFallback(); fallback != nil {
+		fallback.Action(t)
+	}
+}
+
+func (a *parallelMergeJoinSpillAction) spillAll() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for rc := range a.mu.containers {
+		rc.SpillToDisk()
+	}
+	a.spilled.Store(true)
+}
+
+func (a *parallelMergeJoinSpillAction) register(rc *chunk.RowContainer) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.mu.containers == nil {
+		a.mu.containers = make(map[*chunk.RowContainer]struct{})
+	}
+	a.mu.containers[rc] = struct{}{}
+	if a.triggered.Load() {
+		rc.SpillToDisk()
+	}
+}
+
+func (a *parallelMergeJoinSpillAction) unregister(rc *chunk.RowContainer) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.mu.containers, rc)
+}
+
+// SetLogHook implements the memory.ActionOnExceed interface, it does nothing.
+func (a *parallelMergeJoinSpillAction) SetLogHook(hook func(uint64)) {}
+
+// GetPriority implements the memory.ActionOnExceed interface.
+func (a *parallelMergeJoinSpillAction) GetPriority() int64 {
+	return memory.DefSpillPriority
+}
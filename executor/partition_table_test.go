@@ -684,6 +684,75 @@ func (s *partitionTableSuite) TestDynamicPruningUnderIndexJoin(c *C) {
 		tk.MustQuery(`select /*+ INL_JOIN(touter, tnormal) */ tnormal.* from touter join tnormal use index(idx_b) on touter.b = tnormal.b`).Sort().Rows())
 }
 
+func (s *partitionTableSuite) TestDynamicPruningUnderHashJoin(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+
+	tk.MustExec("create database pruning_under_hash_join")
+	tk.MustExec("use pruning_under_hash_join")
+	tk.MustExec("set @@tidb_partition_prune_mode = 'dynamic'")
+
+	tk.MustExec(`create table tnormal (a int, b int, primary key(a))`)
+	tk.MustExec(`create table thash (a int, b int, primary key(a)) partition by hash(a) partitions 4`)
+	tk.MustExec(`create table trange (a int, b int, primary key(a)) partition by range(a) (
+		partition p0 values less than (100), partition p1 values less than (200), partition p2 values less than (300))`)
+	tk.MustExec(`create table touter (a int, b int)`)
+
+	vals := make([]string, 0, 300)
+	for i := 0; i < 300; i++ {
+		vals = append(vals, fmt.Sprintf("(%v, %v)", i, i))
+	}
+	tk.MustExec(`insert into tnormal values ` + strings.Join(vals, ", "))
+	tk.MustExec(`insert into thash values ` + strings.Join(vals, ", "))
+	tk.MustExec(`insert into trange values ` + strings.Join(vals, ", "))
+	tk.MustExec(`insert into touter values (0, 4), (1, 8), (2, 12), (3, null), (4, 1000), (5, 150), (100, 1), (101, 2), (102, 3)`)
+
+	probePartitions := func(sql string) string {
+		for _, row := range tk.MustQuery("explain analyze " + sql).Rows() {
+			if strings.Contains(row[0].(string), "HashJoin") {
+				info := row[5].(string)
+				if idx := strings.Index(info, "probe_partitions:"); idx >= 0 {
+					return info[idx:]
+				}
+				return ""
+			}
+		}
+		return ""
+	}
+	check := func(sql, normalSQL, partitions string) {
+		tk.MustQuery(sql).Sort().Check(tk.MustQuery(normalSQL).Sort().Rows())
+		c.Assert(probePartitions(sql), Equals, partitions, Commentf("sql: %s", sql))
+	}
+
+	// The keys 4, 8, 12 are all in the partition p0 of thash, null and 1000 never match.
+	check(`select /*+ HASH_JOIN(touter, thash) */ * from touter join thash on touter.b = thash.a where touter.a < 5`,
+		`select /*+ HASH_JOIN(touter, tnormal) */ * from touter join tnormal on touter.b = tnormal.a where touter.a < 5`,
+		"probe_partitions:{read:1, total:4}")
+	// The key 1000 is out of the range of trange.
+	check(`select /*+ HASH_JOIN(touter, trange) */ * from touter join trange on touter.b = trange.a where touter.a < 6`,
+		`select /*+ HASH_JOIN(touter, tnormal) */ * from touter join tnormal on touter.b = tnormal.a where touter.a < 6`,
+		"probe_partitions:{read:2, total:3}")
+	// The keys are located in the partitions left by the pruning on the conditions.
+	check(`select /*+ HASH_JOIN(touter, trange) */ * from touter join trange on touter.b = trange.a where touter.a < 6 and trange.a >= 100`,
+		`select /*+ HASH_JOIN(touter, tnormal) */ * from touter join tnormal on touter.b = tnormal.a where touter.a < 6 and tnormal.a >= 100`,
+		"probe_partitions:{read:1, total:2}")
+	// No partition is located.
+	check(`select /*+ HASH_JOIN(touter, trange) */ * from touter join trange on touter.b = trange.a where touter.a = 4`,
+		`select /*+ HASH_JOIN(touter, tnormal) */ * from touter join tnormal on touter.b = tnormal.a where touter.a = 4`,
+		"probe_partitions:{read:0, total:3}")
+	// All the partitions are located.
+	check(`select /*+ HASH_JOIN(touter, thash) */ * from touter join thash on touter.b = thash.a where touter.a < 3 or touter.a >= 100`,
+		`select /*+ HASH_JOIN(touter, tnormal) */ * from touter join tnormal on touter.b = tnormal.a where touter.a < 3 or touter.a >= 100`,
+		"probe_partitions:{read:4, total:4}")
+	// The join keys don't contain the partition column.
+	check(`select /*+ HASH_JOIN(touter, thash) */ * from touter join thash on touter.b = thash.b where touter.a < 5`,
+		`select /*+ HASH_JOIN(touter, tnormal) */ * from touter join tnormal on touter.b = tnormal.b where touter.a < 5`,
+		"")
+	// The unmatched probe side rows are outputted by the outer join.
+	check(`select /*+ HASH_JOIN(touter, thash) */ * from thash left join touter on touter.b = thash.a and touter.a < 5`,
+		`select /*+ HASH_JOIN(touter, tnormal) */ * from tnormal left join touter on touter.b = tnormal.a and touter.a < 5`,
+		"")
+}
+
 func (s *partitionTableSuite) TestIssue25527(c *C) {
 	if israce.RaceEnabled {
 		c.Skip("exhaustive types test, skip race test")
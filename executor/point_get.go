@@ -16,6 +16,7 @@ package executor
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
@@ -86,6 +87,7 @@ type PointGetExecutor struct {
 	lock         bool
 	lockWaitTime int64
 	rowDecoder   *rowcodec.ChunkDecoder
+	decoderLoc   *time.Location
 
 	columns []*model.ColumnInfo
 	// virtualColumnIndex records all the indices of virtual columns and sort them in definition
@@ -95,12 +97,23 @@ type PointGetExecutor struct {
 	// virtualColumnRetFieldTypes records the RetFieldTypes of virtual columns.
 	virtualColumnRetFieldTypes []*types.FieldType
 
+	// firstChunk is preallocated for the result set of the short path of the point get, which
+	// is reused together with the executor by the executions of a prepared statement.
+	firstChunk *chunk.Chunk
+
 	stats *runtimeStatsWithSnapshot
 }
 
 // Init set fields needed for PointGetExecutor reuse, this does NOT change baseExecutor field
 func (e *PointGetExecutor) Init(p *plannercore.PointGetPlan, startTs uint64) {
-	decoder := NewRowDecoder(e.ctx, p.Schema(), p.TblInfo)
+	// The row decoder and the virtual columns only depend on the table and the time zone,
+	// so they are kept when the executor of a prepared statement is reused.
+	if loc := e.ctx.GetSessionVars().TimeZone; e.rowDecoder == nil || e.tblInfo != p.TblInfo || e.decoderLoc != loc {
+		e.rowDecoder = NewRowDecoder(e.ctx, p.Schema(), p.TblInfo)
+		e.decoderLoc = loc
+		e.columns = p.Columns
+		e.buildVirtualColumnInfo()
+	}
 	e.tblInfo = p.TblInfo
 	e.handle = p.Handle
 	e.idxInfo = p.IndexInfo
@@ -109,10 +122,7 @@ func (e *PointGetExecutor) Init(p *plannercore.PointGetPlan, startTs uint64) {
 	e.done = false
 	e.lock = p.Lock
 	e.lockWaitTime = p.LockWaitTime
-	e.rowDecoder = decoder
 	e.partInfo = p.PartitionInfo
-	e.columns = p.Columns
-	e.buildVirtualColumnInfo()
 }
 
 // buildVirtualColumnInfo saves virtual column indices and sort them in definition order
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/executor"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
 	txninfo "github.com/pingcap/tidb/session/txninfo"
@@ -195,6 +196,42 @@ func (s *testSuite12) TestPreparedStmtWithHint(c *C) {
 	c.Check(sm.killed, Equals, true)
 }
 
+func (s *testSerialSuite) TestMaxEstimatedCostWithPlanCache(c *C) {
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	tk := testkit.NewTestKit(c, store)
+	defer func() {
+		dom.Close()
+		store.Close()
+	}()
+	orgEnable := plannercore.PreparedPlanCacheEnabled()
+	defer func() {
+		plannercore.SetPreparedPlanCache(orgEnable)
+	}()
+	plannercore.SetPreparedPlanCache(true)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	tk.MustExec("prepare stmt from 'select * from t where b > ?'")
+	tk.MustExec("set @a = 0")
+	tk.MustQuery("execute stmt using @a").Sort().Check(testkit.Rows("1 1", "2 2"))
+	tk.MustQuery("execute stmt using @a").Sort().Check(testkit.Rows("1 1", "2 2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The cached plan is checked as well.
+	tk.MustExec("set @@tidb_max_estimated_cost = 1000")
+	_, err = tk.Exec("execute stmt using @a")
+	c.Assert(executor.ErrMaxEstimatedCostExceeded.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("set @@tidb_max_estimated_cost = 0")
+	tk.MustExec("set @@tidb_max_estimated_rows = 100")
+	_, err = tk.Exec("execute stmt using @a")
+	c.Assert(executor.ErrMaxEstimatedRowsExceeded.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustExec("set @@tidb_max_estimated_rows = 0")
+	tk.MustQuery("execute stmt using @a").Sort().Check(testkit.Rows("1 1", "2 2"))
+}
+
 func (s *testSerialSuite) TestPlanCacheClusterIndex(c *C) {
 	store, dom, err := newStoreWithBootstrap()
 	c.Assert(err, IsNil)
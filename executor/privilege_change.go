@@ -0,0 +1,282 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"go.uber.org/zap"
+)
+
+// redactedAuthString replaces the authentication string of the users in mysql.privilege_changes, so that the
+// password hashes are not leaked by the history.
+const redactedAuthString = "***"
+
+// PrivilegeChangeRecordExec executes an account-management statement, and records the grants of
+// the affected users before and after it, together with the statements rolling the change back,
+// into mysql.privilege_changes.
+type PrivilegeChangeRecordExec struct {
+	baseExecutor
+
+	stmt  ast.StmtNode
+	users []*auth.UserIdentity
+	done  bool
+}
+
+// accountSnapshot is the account definition and the grants of a user at some time.
+type accountSnapshot struct {
+	exists     bool
+	authPlugin string
+	require    string
+	grants     []string
+}
+
+// createUser is the same as the result of SHOW CREATE USER, except that the authentication string is redacted.
+func (s *accountSnapshot) createUser(user *auth.UserIdentity) string {
+	return fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED WITH '%s' AS '%s' REQUIRE %s PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK",
+		user.Username, user.Hostname, s.authPlugin, redactedAuthString, s.require)
+}
+
+func (s *accountSnapshot) String(user *auth.UserIdentity) string {
+	if !s.exists {
+		return ""
+	}
+	return strings.Join(append([]string{s.createUser(user)}, s.grants...), ";\n") + ";"
+}
+
+// wrapPrivilegeChangeRecorder wraps the executor of an account-management statement to record its changes.
+func (b *executorBuilder) wrapPrivilegeChangeRecorder(stmt ast.StmtNode, e Executor) Executor {
+	if e == nil || b.ctx.GetSessionVars().InRestrictedSQL {
+		return e
+	}
+	users := privilegeChangeUsers(b.ctx, stmt)
+	if len(users) == 0 {
+		return e
+	}
+	return &PrivilegeChangeRecordExec{
+		baseExecutor: newBaseExecutor(b.ctx, e.Schema(), e.base().id, e),
+		stmt:         stmt,
+		users:        users,
+	}
+}
+
+// privilegeChangeUsers returns the users whose account or grants may be changed by the statement.
+func privilegeChangeUsers(sctx sessionctx.Context, stmt ast.StmtNode) []*auth.UserIdentity {
+	var users []*auth.UserIdentity
+	switch s := stmt.(type) {
+	case *ast.CreateUserStmt:
+		for _, spec := range s.Specs {
+			users = append(users, spec.User)
+		}
+	case *ast.AlterUserStmt:
+		if s.CurrentAuth != nil {
+			users = append(users, &auth.UserIdentity{CurrentUser: true})
+		}
+		for _, spec := range s.Specs {
+			users = append(users, spec.User)
+		}
+	case *ast.DropUserStmt:
+		users = append(users, s.UserList...)
+	case *ast.RenameUserStmt:
+		for _, userToUser := range s.UserToUsers {
+			users = append(users, userToUser.OldUser, userToUser.NewUser)
+		}
+	case *ast.SetPwdStmt:
+		if s.User == nil {
+			users = append(users, &auth.UserIdentity{CurrentUser: true})
+		} else {
+			users = append(users, s.User)
+		}
+	case *ast.GrantStmt:
+		for _, spec := range s.Users {
+			users = append(users, spec.User)
+		}
+	case *ast.RevokeStmt:
+		for _, spec := range s.Users {
+			users = append(users, spec.User)
+		}
+	case *ast.GrantRoleStmt:
+		users = append(users, s.Users...)
+	case *ast.RevokeRoleStmt:
+		users = append(users, s.Users...)
+	}
+
+	result := make([]*auth.UserIdentity, 0, len(users))
+	for _, user := range users {
+		if user.CurrentUser {
+			current := sctx.GetSessionVars().User
+			if current == nil {
+				continue
+			}
+			user = &auth.UserIdentity{Username: current.AuthUsername, Hostname: current.AuthHostname}
+		}
+		result = append(result, user)
+	}
+	return result
+}
+
+// Next implements the Executor Next interface.
+func (e *PrivilegeChangeRecordExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	before, err := e.takeSnapshots()
+	if err != nil {
+		// The account may be unable to show its grants, e.g. when skip-grant-table is on, the statement
+		// should still be executed.
+		logutil.Logger(ctx).Warn("take account snapshots failed, skip recording the privilege change", zap.Error(err))
+		return Next(ctx, e.children[0], req)
+	}
+	execErr := Next(ctx, e.children[0], req)
+	after, err := e.takeSnapshots()
+	if err != nil {
+		logutil.Logger(ctx).Error("take account snapshots failed", zap.Error(err))
+		return execErr
+	}
+	for i, user := range e.users {
+		// Some users may have been changed even if the statement fails.
+		if execErr != nil && before[i].String(user) == after[i].String(user) {
+			continue
+		}
+		if err := e.recordChange(user, before[i], after[i]); err != nil {
+			logutil.Logger(ctx).Error("record privilege change failed", zap.Stringer("user", user), zap.Error(err))
+			e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+		}
+	}
+	return execErr
+}
+
+func (e *PrivilegeChangeRecordExec) takeSnapshots() ([]*accountSnapshot, error) {
+	snapshots := make([]*accountSnapshot, 0, len(e.users))
+	for _, user := range e.users {
+		snapshot, err := takeAccountSnapshot(e.ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+func takeAccountSnapshot(sctx sessionctx.Context, user *auth.UserIdentity) (*accountSnapshot, error) {
+	checker := privilege.GetPrivilegeManager(sctx)
+	if checker == nil {
+		return nil, errors.New("miss privilege checker")
+	}
+	authplugin, require, exists, err := getUserAuthInfo(sctx, user.Username, user.Hostname)
+	if err != nil || !exists {
+		return &accountSnapshot{}, err
+	}
+	grants, err := checker.ShowGrants(sctx, user, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &accountSnapshot{exists: true, authPlugin: authplugin, require: require, grants: grants}, nil
+}
+
+func (e *PrivilegeChangeRecordExec) recordChange(user *auth.UserIdentity, before, after *accountSnapshot) error {
+	stmtText := e.stmt.Text()
+	if sensitiveStmt, ok := e.stmt.(ast.SensitiveStmtNode); ok {
+		stmtText = sensitiveStmt.SecureText()
+	}
+	var operator string
+	if current := e.ctx.GetSessionVars().User; current != nil {
+		operator = current.String()
+	}
+	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(context.TODO(),
+		"INSERT HIGH_PRIORITY INTO %n.%n (OPERATOR, USER, HOST, STMT, BEFORE_GRANTS, AFTER_GRANTS, ROLLBACK_STMTS) VALUES (%?, %?, %?, %?, %?, %?, %?)",
+		mysql.SystemDB, privileges.PrivilegeChangesTable, operator, user.Username, user.Hostname, stmtText,
+		before.String(user), after.String(user), strings.Join(rollbackPrivilegeChange(user, before, after), "\n"))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt)
+	return errors.Trace(err)
+}
+
+// rollbackPrivilegeChange generates the statements which change the account from the snapshot after back
+// to the snapshot before. The passwords are not recorded, so a dropped user is recreated with the redacted
+// authentication string, which has to be replaced before the statement can be executed, and the password
+// changes are not rolled back.
+func rollbackPrivilegeChange(user *auth.UserIdentity, before, after *accountSnapshot) []string {
+	switch {
+	case !before.exists && !after.exists:
+		return nil
+	case !before.exists:
+		return []string{sqlexec.MustEscapeSQL("DROP USER %?@%?;", user.Username, user.Hostname)}
+	case !after.exists:
+		stmts := []string{before.createUser(user) + ";"}
+		for _, grant := range before.grants {
+			stmts = append(stmts, grant+";")
+		}
+		return stmts
+	}
+
+	var stmts []string
+	if before.require != after.require {
+		stmts = append(stmts, fmt.Sprintf("ALTER USER '%s'@'%s' REQUIRE %s;", user.Username, user.Hostname, before.require))
+	}
+	beforeGrants := make(map[string]struct{}, len(before.grants))
+	for _, grant := range before.grants {
+		beforeGrants[grant] = struct{}{}
+	}
+	afterGrants := make(map[string]struct{}, len(after.grants))
+	for _, grant := range after.grants {
+		afterGrants[grant] = struct{}{}
+		if _, ok := beforeGrants[grant]; !ok {
+			stmts = append(stmts, revokeStmtOfGrant(grant)+";")
+		}
+	}
+	for _, grant := range before.grants {
+		if _, ok := afterGrants[grant]; !ok {
+			stmts = append(stmts, grant+";")
+		}
+	}
+	return stmts
+}
+
+// revokeStmtOfGrant converts a result row of SHOW GRANTS to the statement revoking it, e.g.
+// "GRANT SELECT ON test.* TO 'u'@'%'" is converted to "REVOKE SELECT ON test.* FROM 'u'@'%'", and
+// "GRANT 'r'@'%' TO 'u'@'%'" is converted to "REVOKE 'r'@'%' FROM 'u'@'%'".
+func revokeStmtOfGrant(grant string) string {
+	grant = strings.TrimPrefix(grant, "GRANT ")
+	withGrantOption := strings.HasSuffix(grant, " WITH GRANT OPTION")
+	grant = strings.TrimSuffix(grant, " WITH GRANT OPTION")
+	idx := strings.LastIndex(grant, " TO ")
+	if idx < 0 {
+		return "REVOKE " + grant
+	}
+	privs, users := grant[:idx], grant[idx+len(" TO "):]
+	if withGrantOption {
+		if onIdx := strings.Index(privs, " ON "); onIdx >= 0 {
+			privs = privs[:onIdx] + ",GRANT OPTION" + privs[onIdx:]
+		}
+	}
+	return "REVOKE " + privs + " FROM " + users
+}
@@ -1443,6 +1443,13 @@ func (s *testSerialSuite) TestSetTopSQLVariables(c *C) {
 	tk.MustQuery("select @@global.tidb_top_sql_report_interval_seconds;").Check(testkit.Rows("120"))
 	c.Assert(variable.TopSQLVariable.ReportIntervalSeconds.Load(), Equals, int64(120))
 
+	tk.MustExec("set @@global.tidb_top_sql_adaptive_interval='On';")
+	tk.MustQuery("select @@global.tidb_top_sql_adaptive_interval;").Check(testkit.Rows("1"))
+	c.Assert(variable.TopSQLVariable.AdaptiveInterval.Load(), IsTrue)
+	tk.MustExec("set @@global.tidb_top_sql_adaptive_interval='off';")
+	tk.MustQuery("select @@global.tidb_top_sql_adaptive_interval;").Check(testkit.Rows("0"))
+	c.Assert(variable.TopSQLVariable.AdaptiveInterval.Load(), IsFalse)
+
 	// Test for hide top sql variable in show variable.
 	tk.MustQuery("show variables like '%top_sql%'").Check(testkit.Rows())
 	tk.MustQuery("show global variables like '%top_sql%'").Check(testkit.Rows())
@@ -959,6 +959,16 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 		}
 	}
 
+	for _, constraint := range tableInfo.Constraints {
+		if constraint.State != model.StatePublic {
+			continue
+		}
+		buf.WriteString(fmt.Sprintf(",\n  CONSTRAINT %s CHECK ((%s))", stringutil.Escape(constraint.Name.O, sqlMode), constraint.ExprString))
+		if !constraint.Enforced {
+			buf.WriteString(" /*!80016 NOT ENFORCED */")
+		}
+	}
+
 	buf.WriteString("\n")
 
 	switch tableInfo.TempTableType {
@@ -1332,52 +1342,75 @@ func (e *ShowExec) fetchShowCreateUser() error {
 		}
 	}
 
-	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+	authplugin, require, exists, err := getUserAuthInfo(e.ctx, userName, hostName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		// FIXME: the error returned is not escaped safely
+		return ErrCannotUser.GenWithStackByArgs("SHOW CREATE USER",
+			fmt.Sprintf("'%s'@'%s'", e.User.Username, e.User.Hostname))
+	}
+	var resourceLimits string
+	maxQueries, maxUpdates := checker.GetResourceLimits(userName, hostName)
+	if maxQueries > 0 {
+		resourceLimits += fmt.Sprintf(" MAX_QUERIES_PER_HOUR %d", maxQueries)
+	}
+	if maxUpdates > 0 {
+		resourceLimits += fmt.Sprintf(" MAX_UPDATES_PER_HOUR %d", maxUpdates)
+	}
+	if len(resourceLimits) > 0 {
+		resourceLimits = " WITH" + resourceLimits
+	}
+	// FIXME: the returned string is not escaped safely
+	showStr := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED WITH '%s' AS '%s' REQUIRE %s%s PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK",
+		e.User.Username, e.User.Hostname, authplugin, checker.GetEncodedPassword(e.User.Username, e.User.Hostname), require, resourceLimits)
+	e.appendRow([]interface{}{showStr})
+	return nil
+}
+
+// getUserAuthInfo gets the authentication plugin and the TLS requirement of the user.
+func getUserAuthInfo(sctx sessionctx.Context, userName, hostName string) (authplugin, require string, exists bool, err error) {
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
 
 	stmt, err := exec.ParseWithParams(context.TODO(), `SELECT plugin FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.UserTable, userName, hostName)
 	if err != nil {
-		return errors.Trace(err)
+		return "", "", false, errors.Trace(err)
 	}
 	rows, _, err := exec.ExecRestrictedStmt(context.TODO(), stmt)
 	if err != nil {
-		return errors.Trace(err)
+		return "", "", false, errors.Trace(err)
 	}
 
 	if len(rows) == 0 {
-		// FIXME: the error returned is not escaped safely
-		return ErrCannotUser.GenWithStackByArgs("SHOW CREATE USER",
-			fmt.Sprintf("'%s'@'%s'", e.User.Username, e.User.Hostname))
+		return "", "", false, nil
 	}
 
-	authplugin := mysql.AuthNativePassword
+	authplugin = mysql.AuthNativePassword
 	if len(rows) == 1 && rows[0].GetString(0) != "" {
 		authplugin = rows[0].GetString(0)
 	}
 
 	stmt, err = exec.ParseWithParams(context.TODO(), `SELECT Priv FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.GlobalPrivTable, userName, hostName)
 	if err != nil {
-		return errors.Trace(err)
+		return "", "", false, errors.Trace(err)
 	}
 	rows, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt)
 	if err != nil {
-		return errors.Trace(err)
+		return "", "", false, errors.Trace(err)
 	}
 
-	require := "NONE"
+	require = "NONE"
 	if len(rows) == 1 {
 		privData := rows[0].GetString(0)
 		var privValue privileges.GlobalPrivValue
 		err = gjson.Unmarshal(hack.Slice(privData), &privValue)
 		if err != nil {
-			return errors.Trace(err)
+			return "", "", false, errors.Trace(err)
 		}
 		require = privValue.RequireStr()
 	}
-	// FIXME: the returned string is not escaped safely
-	showStr := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED WITH '%s' AS '%s' REQUIRE %s PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK",
-		e.User.Username, e.User.Hostname, authplugin, checker.GetEncodedPassword(e.User.Username, e.User.Hostname), require)
-	e.appendRow([]interface{}{showStr})
-	return nil
+	return authplugin, require, true, nil
 }
 
 func (e *ShowExec) fetchShowGrants() error {
@@ -139,6 +139,7 @@ func (e *ShowExec) appendTableForStatsMeta(dbName, tblName, partitionName string
 		e.versionToTime(statsTbl.Version),
 		statsTbl.ModifyCount,
 		statsTbl.Count,
+		statsTbl.Snapshot,
 	})
 }
 
@@ -15,6 +15,7 @@ package executor_test
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 
 	. "github.com/pingcap/check"
@@ -43,6 +44,38 @@ func (s *testShowStatsSuite) TestShowStatsMeta(c *C) {
 	c.Assert(result.Rows()[0][1], Equals, "t")
 }
 
+func (s *testShowStatsSuite) TestShowStatsMetaSnapshot(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t1")
+	tk.MustExec("create table t (a int, b int, index idx(b))")
+	tk.MustExec("create table t1 (a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	tk.MustExec("analyze table t, t1")
+	rows := tk.MustQuery("show stats_meta").Sort().Rows()
+	c.Assert(len(rows), Equals, 2)
+	snapshot := rows[0][6].(string)
+	c.Assert(snapshot, Not(Equals), "0")
+	// All the tables of the analyze job are read at the same snapshot.
+	c.Assert(rows[1][6], Equals, snapshot)
+	tk.MustQuery("select count(*) from mysql.stats_meta where snapshot = " + snapshot).Check(testkit.Rows("2"))
+
+	tk.MustExec("insert into t values (3, 3)")
+	tk.MustExec("analyze table t")
+	rows = tk.MustQuery("show stats_meta where table_name = 't'").Rows()
+	c.Assert(len(rows), Equals, 1)
+	newSnapshot := rows[0][6].(string)
+	old, err := strconv.ParseUint(snapshot, 10, 64)
+	c.Assert(err, IsNil)
+	cur, err := strconv.ParseUint(newSnapshot, 10, 64)
+	c.Assert(err, IsNil)
+	c.Assert(cur > old, IsTrue)
+	// The snapshot of t1 isn't changed since it isn't analyzed again.
+	rows = tk.MustQuery("show stats_meta where table_name = 't1'").Rows()
+	c.Assert(len(rows), Equals, 1)
+	c.Assert(rows[0][6], Equals, snapshot)
+}
+
 func (s *testShowStatsSuite) TestShowStatsHistograms(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
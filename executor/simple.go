@@ -573,7 +573,7 @@ func (e *SimpleExec) executeBegin(ctx context.Context, s *ast.BeginStmt) error {
 	// always create a new Txn instead of reusing it.
 	if s.ReadOnly {
 		enableNoopFuncs := e.ctx.GetSessionVars().EnableNoopFuncs
-		if !enableNoopFuncs && s.AsOf == nil {
+		if !enableNoopFuncs && s.AsOf == nil && !e.ctx.GetSessionVars().IsReplicaReadInReadOnlyTxn() {
 			return expression.ErrFunctionsNoopImpl.GenWithStackByArgs("READ ONLY")
 		}
 		if s.AsOf != nil {
@@ -630,6 +630,11 @@ func (e *SimpleExec) executeBegin(ctx context.Context, s *ast.BeginStmt) error {
 	if s.CausalConsistencyOnly {
 		txn.SetOption(kv.GuaranteeLinearizability, false)
 	}
+	if s.ReadOnly && e.ctx.GetSessionVars().IsReplicaReadInReadOnlyTxn() {
+		// The reads of the read-only transaction are routed to the followers, and the writes are rejected.
+		e.ctx.GetSessionVars().TxnCtx.IsReadOnly = true
+		txn.SetOption(kv.ReplicaRead, kv.ReplicaReadFollower)
+	}
 	return nil
 }
 
@@ -722,6 +727,27 @@ func (e *SimpleExec) executeRollback(s *ast.RollbackStmt) error {
 	return nil
 }
 
+// resourceLimit is a resource limit of the account, which is stored in the column of mysql.user.
+type resourceLimit struct {
+	column string
+	value  int64
+}
+
+// resourceOptions2Limits converts the resource options of CREATE USER and ALTER USER to the resource limits. Only
+// MAX_QUERIES_PER_HOUR and MAX_UPDATES_PER_HOUR are supported, the other options are ignored.
+func resourceOptions2Limits(options []*ast.ResourceOption) []resourceLimit {
+	limits := make([]resourceLimit, 0, len(options))
+	for _, option := range options {
+		switch option.Type {
+		case ast.MaxQueriesPerHour:
+			limits = append(limits, resourceLimit{column: "max_questions", value: option.Count})
+		case ast.MaxUpdatesPerHour:
+			limits = append(limits, resourceLimit{column: "max_updates", value: option.Count})
+		}
+	}
+	return limits
+}
+
 func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStmt) error {
 	// Check `CREATE USER` privilege.
 	if !config.GetGlobalConfig().Security.SkipGrantTable {
@@ -747,12 +773,21 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 	if err != nil {
 		return err
 	}
+	var maxQueries, maxUpdates int64
+	for _, limit := range resourceOptions2Limits(s.ResourceOptions) {
+		switch limit.column {
+		case "max_questions":
+			maxQueries = limit.value
+		case "max_updates":
+			maxUpdates = limit.value
+		}
+	}
 
 	sql := new(strings.Builder)
 	if s.IsCreateRole {
 		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin, Account_locked) VALUES `, mysql.SystemDB, mysql.UserTable)
 	} else {
-		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin) VALUES `, mysql.SystemDB, mysql.UserTable)
+		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin, max_questions, max_updates) VALUES `, mysql.SystemDB, mysql.UserTable)
 	}
 
 	users := make([]*auth.UserIdentity, 0, len(s.Specs))
@@ -788,7 +823,7 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 		if s.IsCreateRole {
 			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?, %?)`, spec.User.Hostname, spec.User.Username, pwd, authPlugin, "Y")
 		} else {
-			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?)`, spec.User.Hostname, spec.User.Username, pwd, authPlugin)
+			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?, %?, %?)`, spec.User.Hostname, spec.User.Username, pwd, authPlugin, maxQueries, maxUpdates)
 		}
 		users = append(users, spec.User)
 	}
@@ -857,6 +892,7 @@ func (e *SimpleExec) executeAlterUser(s *ast.AlterUserStmt) error {
 	if err != nil {
 		return err
 	}
+	limits := resourceOptions2Limits(s.ResourceOptions)
 
 	failedUsers := make([]string, 0, len(s.Specs))
 	checker := privilege.GetPrivilegeManager(e.ctx)
@@ -946,6 +982,24 @@ func (e *SimpleExec) executeAlterUser(s *ast.AlterUserStmt) error {
 				failedUsers = append(failedUsers, spec.User.String())
 			}
 		}
+
+		if len(limits) > 0 {
+			assignments := make([]string, 0, len(limits))
+			args := []interface{}{mysql.SystemDB, mysql.UserTable}
+			for _, limit := range limits {
+				assignments = append(assignments, "%n=%?")
+				args = append(args, limit.column, limit.value)
+			}
+			args = append(args, spec.User.Hostname, spec.User.Username)
+			stmt, err := exec.ParseWithParams(context.TODO(), "UPDATE %n.%n SET "+strings.Join(assignments, ",")+" WHERE Host=%? and User=%?", args...)
+			if err != nil {
+				return err
+			}
+			_, _, err = exec.ExecRestrictedStmt(context.TODO(), stmt)
+			if err != nil {
+				failedUsers = append(failedUsers, spec.User.String())
+			}
+		}
 	}
 	if len(failedUsers) > 0 {
 		// Commit the transaction even if we returns error
@@ -1510,7 +1564,15 @@ func (e *SimpleExec) executeFlush(s *ast.FlushStmt) error {
 		}
 		defer sysSessionPool.Put(ctx)
 		err = dom.PrivilegeHandle().Update(ctx.(sessionctx.Context))
-		return err
+		if err != nil {
+			return err
+		}
+		// The privilege tables may be modified directly, so the other servers reload the privileges too. The
+		// internal FLUSH PRIVILEGES of NotifyUpdatePrivilege has notified them.
+		if !e.ctx.GetSessionVars().InRestrictedSQL {
+			dom.NotifyUpdatePrivilegeToCluster()
+		}
+		return nil
 	case ast.FlushTiDBPlugin:
 		dom := domain.GetDomain(e.ctx)
 		for _, pluginName := range s.Plugins {
@@ -480,6 +480,22 @@ func (s *testSuite7) TestUser(c *C) {
 	tk.MustQuery(querySQL).Check(testkit.Rows("*6BB4837EB74329105EE4568DDA7DC67ED2CA2AD9"))
 }
 
+func (s *testSuite7) TestUserResourceLimits(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create user 'test_limits'@'%' with max_queries_per_hour 10 max_updates_per_hour 2 max_user_connections 3")
+	defer tk.MustExec("drop user 'test_limits'@'%'")
+	tk.MustQuery("select max_questions, max_updates from mysql.user where user = 'test_limits'").Check(testkit.Rows("10 2"))
+	tk.MustQuery("show create user 'test_limits'@'%'").Check(testkit.Rows("CREATE USER 'test_limits'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE WITH MAX_QUERIES_PER_HOUR 10 MAX_UPDATES_PER_HOUR 2 PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"))
+
+	// Only the specified limits are changed.
+	tk.MustExec("alter user 'test_limits'@'%' with max_updates_per_hour 5")
+	tk.MustQuery("select max_questions, max_updates from mysql.user where user = 'test_limits'").Check(testkit.Rows("10 5"))
+	tk.MustExec("alter user 'test_limits'@'%' with max_queries_per_hour 0")
+	tk.MustQuery("show create user 'test_limits'@'%'").Check(testkit.Rows("CREATE USER 'test_limits'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE WITH MAX_UPDATES_PER_HOUR 5 PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"))
+	tk.MustExec("alter user 'test_limits'@'%' with max_updates_per_hour 0")
+	tk.MustQuery("show create user 'test_limits'@'%'").Check(testkit.Rows("CREATE USER 'test_limits'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"))
+}
+
 func (s *testSuite3) TestSetPwd(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 
@@ -594,6 +594,7 @@ type slowQueryTuple struct {
 	user                      string
 	host                      string
 	connID                    uint64
+	stmtInstanceID            string
 	execRetryCount            uint64
 	execRetryTime             float64
 	queryTime                 float64
@@ -604,6 +605,7 @@ type slowQueryTuple struct {
 	preprocSubQueryTime       float64
 	optimizeTime              float64
 	waitTSTime                float64
+	admissionQueueTime        float64
 	preWriteTime              float64
 	waitPrewriteBinlogTime    float64
 	commitTime                float64
@@ -706,6 +708,8 @@ func (st *slowQueryTuple) setFieldValue(tz *time.Location, field, value string,
 		}
 	case variable.SlowLogConnIDStr:
 		st.connID, err = strconv.ParseUint(value, 10, 64)
+	case variable.SlowLogStmtInstanceIDStr:
+		st.stmtInstanceID = value
 	case variable.SlowLogExecRetryCount:
 		st.execRetryCount, err = strconv.ParseUint(value, 10, 64)
 	case variable.SlowLogExecRetryTime:
@@ -720,6 +724,8 @@ func (st *slowQueryTuple) setFieldValue(tz *time.Location, field, value string,
 		st.optimizeTime, err = strconv.ParseFloat(value, 64)
 	case variable.SlowLogWaitTSTimeStr:
 		st.waitTSTime, err = strconv.ParseFloat(value, 64)
+	case variable.SlowLogAdmissionQueueTimeStr:
+		st.admissionQueueTime, err = strconv.ParseFloat(value, 64)
 	case execdetails.PreWriteTimeStr:
 		st.preWriteTime, err = strconv.ParseFloat(value, 64)
 	case execdetails.WaitPrewriteBinlogTimeStr:
@@ -858,6 +864,7 @@ func (st *slowQueryTuple) convertToDatumRow() []types.Datum {
 	record = append(record, types.NewFloat64Datum(st.preprocSubQueryTime))
 	record = append(record, types.NewFloat64Datum(st.optimizeTime))
 	record = append(record, types.NewFloat64Datum(st.waitTSTime))
+	record = append(record, types.NewFloat64Datum(st.admissionQueueTime))
 	record = append(record, types.NewFloat64Datum(st.preWriteTime))
 	record = append(record, types.NewFloat64Datum(st.waitPrewriteBinlogTime))
 	record = append(record, types.NewFloat64Datum(st.commitTime))
@@ -927,6 +934,7 @@ func (st *slowQueryTuple) convertToDatumRow() []types.Datum {
 	record = append(record, types.NewStringDatum(st.planDigest))
 	record = append(record, types.NewStringDatum(st.prevStmt))
 	record = append(record, types.NewStringDatum(st.sql))
+	record = append(record, types.NewStringDatum(st.stmtInstanceID))
 	return record
 }
 
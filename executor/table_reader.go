@@ -86,6 +86,7 @@ type TableReaderExecutor struct {
 	// for unsigned int.
 	resultHandler *tableResultHandler
 	feedback      *statistics.QueryFeedback
+	estChecker    *estDeviationChecker
 	plans         []plannercore.PhysicalPlan
 	tablePlan     plannercore.PhysicalPlan
 
@@ -137,6 +138,7 @@ func (e *TableReaderExecutor) Open(ctx context.Context) error {
 
 	e.memTracker = memory.NewTracker(e.id, -1)
 	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.estChecker.reset()
 
 	var err error
 	if e.corColInFilter {
@@ -233,6 +235,7 @@ func (e *TableReaderExecutor) Next(ctx context.Context, req *chunk.Chunk) error
 		e.feedback.Invalidate()
 		return err
 	}
+	e.estChecker.check(e.ctx, e.table, req.NumRows())
 
 	err := FillVirtualColumnValue(e.virtualColumnRetFieldTypes, e.virtualColumnIndex, e.schema, e.columns, e.ctx, req)
 	if err != nil {
@@ -18,12 +18,17 @@ import (
 
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/executor/aggfuncs"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/memory"
 )
 
 // WindowExec is the executor for window functions.
@@ -35,45 +40,112 @@ type WindowExec struct {
 	childResult *chunk.Chunk
 	// executed indicates the child executor is drained or something unexpected happened.
 	executed bool
-	// resultChunks stores the chunks to return
-	resultChunks []*chunk.Chunk
-	// remainingRowsInChunk indicates how many rows the resultChunks[i] is not prepared.
-	remainingRowsInChunk []int
+	// partition stores the rows of the current partition, it's spilled to disk when the memory usage exceeds
+	// tidb_mem_quota_query and oom-use-tmp-storage is enabled.
+	partition *chunk.RowContainer
+	// partitionChk is the chunk being filled with the rows of the current partition, it's added to partition once
+	// it's full, so all the chunks in partition except the last one have maxChunkSize rows.
+	partitionChk *chunk.Chunk
+	// rows provides the accesses to the rows in partition for the processor.
+	rows *partitionRows
+	// emittedRows indicates how many rows of the current partition have been appended to the results.
+	emittedRows uint64
+	// inputColIdxs is the indexes of the child columns which are output before the window functions.
+	inputColIdxs []int
 
 	numWindowFuncs int
 	processor      windowProcessor
+
+	memTracker  *memory.Tracker
+	diskTracker *disk.Tracker
+}
+
+// Open implements the Executor Open interface.
+func (e *WindowExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.memTracker = memory.NewTracker(e.id, -1)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.diskTracker = disk.NewTracker(e.id, -1)
+	e.diskTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.DiskTracker)
+
+	fieldTypes := retTypes(e.children[0])
+	e.partition = chunk.NewRowContainer(fieldTypes, e.maxChunkSize)
+	e.partition.GetMemTracker().AttachTo(e.memTracker)
+	e.partition.GetMemTracker().SetLabel(memory.LabelForRowChunks)
+	e.partition.GetDiskTracker().AttachTo(e.diskTracker)
+	e.partition.GetDiskTracker().SetLabel(memory.LabelForRowChunks)
+	if config.GetGlobalConfig().OOMUseTmpStorage {
+		actionSpill := e.partition.ActionSpill()
+		failpoint.Inject("testWindowRowContainerSpill", func(val failpoint.Value) {
+			if val.(bool) {
+				actionSpill = e.partition.ActionSpillForTest()
+			}
+		})
+		e.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(actionSpill)
+	}
+	e.partitionChk = e.partition.AllocChunk()
+	e.rows = &partitionRows{fieldTypes: fieldTypes, chunkSize: uint64(e.maxChunkSize)}
+	e.emittedRows = 0
+
+	columns := e.Schema().Columns[:len(e.Schema().Columns)-e.numWindowFuncs]
+	e.inputColIdxs = make([]int, 0, len(columns))
+	for _, col := range columns {
+		e.inputColIdxs = append(e.inputColIdxs, col.Index)
+	}
+	e.childResult = nil
+	e.executed = false
+	e.groupChecker.reset()
+	return nil
 }
 
 // Close implements the Executor Close interface.
 func (e *WindowExec) Close() error {
+	if e.partition != nil {
+		failpoint.Inject("testWindowRowContainerSpill", func(val failpoint.Value) {
+			if val.(bool) {
+				e.partition.ActionSpill().WaitForTest()
+			}
+		})
+		if err := e.partition.Close(); err != nil {
+			return err
+		}
+		e.partition = nil
+	}
+	e.partitionChk = nil
+	e.rows = nil
+	e.memTracker = nil
+	e.diskTracker = nil
 	return errors.Trace(e.baseExecutor.Close())
 }
 
 // Next implements the Executor Next interface.
 func (e *WindowExec) Next(ctx context.Context, chk *chunk.Chunk) error {
 	chk.Reset()
-	for !e.executed && !e.preparedChunkAvailable() {
-		err := e.consumeOneGroup(ctx)
-		if err != nil {
+	for !chk.IsFull() {
+		if e.emittedRows == e.rows.numRows {
+			if e.executed {
+				break
+			}
+			if err := e.consumeOneGroup(ctx); err != nil {
+				e.executed = true
+				return err
+			}
+			continue
+		}
+		if err := e.appendResult2Chunk(chk); err != nil {
 			e.executed = true
 			return err
 		}
 	}
-	if len(e.resultChunks) > 0 {
-		chk.SwapColumns(e.resultChunks[0])
-		e.resultChunks[0] = nil // GC it. TODO: Reuse it.
-		e.resultChunks = e.resultChunks[1:]
-		e.remainingRowsInChunk = e.remainingRowsInChunk[1:]
-	}
 	return nil
 }
 
-func (e *WindowExec) preparedChunkAvailable() bool {
-	return len(e.resultChunks) > 0 && e.remainingRowsInChunk[0] == 0
-}
-
 func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
-	var groupRows []chunk.Row
+	if err := e.resetPartition(); err != nil {
+		return err
+	}
 	if e.groupChecker.isExhausted() {
 		eof, err := e.fetchChild(ctx)
 		if err != nil {
@@ -81,7 +153,7 @@ func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
 		}
 		if eof {
 			e.executed = true
-			return e.consumeGroupRows(groupRows)
+			return e.consumeGroupRows()
 		}
 		_, err = e.groupChecker.splitIntoGroups(e.childResult)
 		if err != nil {
@@ -89,8 +161,8 @@ func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
 		}
 	}
 	begin, end := e.groupChecker.getNextGroup()
-	for i := begin; i < end; i++ {
-		groupRows = append(groupRows, e.childResult.GetRow(i))
+	if err := e.appendGroupRows(begin, end); err != nil {
+		return err
 	}
 
 	for meetLastGroup := end == e.childResult.NumRows(); meetLastGroup; {
@@ -101,7 +173,7 @@ func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
 		}
 		if eof {
 			e.executed = true
-			return e.consumeGroupRows(groupRows)
+			return e.consumeGroupRows()
 		}
 
 		isFirstGroupSameAsPrev, err := e.groupChecker.splitIntoGroups(e.childResult)
@@ -111,86 +183,218 @@ func (e *WindowExec) consumeOneGroup(ctx context.Context) error {
 
 		if isFirstGroupSameAsPrev {
 			begin, end = e.groupChecker.getNextGroup()
-			for i := begin; i < end; i++ {
-				groupRows = append(groupRows, e.childResult.GetRow(i))
+			if err = e.appendGroupRows(begin, end); err != nil {
+				return err
 			}
 			meetLastGroup = end == e.childResult.NumRows()
 		}
 	}
-	return e.consumeGroupRows(groupRows)
+	return e.consumeGroupRows()
 }
 
-func (e *WindowExec) consumeGroupRows(groupRows []chunk.Row) (err error) {
-	remainingRowsInGroup := len(groupRows)
-	if remainingRowsInGroup == 0 {
-		return nil
-	}
-	for i := 0; i < len(e.resultChunks); i++ {
-		remained := mathutil.Min(e.remainingRowsInChunk[i], remainingRowsInGroup)
-		e.remainingRowsInChunk[i] -= remained
-		remainingRowsInGroup -= remained
+// resetPartition clears the rows and the partial results of the last partition.
+func (e *WindowExec) resetPartition() error {
+	e.processor.resetPartialResult()
+	e.emittedRows = 0
+	e.rows.numRows = 0
+	return e.partition.Reset()
+}
 
-		// TODO: Combine these three methods.
-		// The old implementation needs the processor has these three methods
-		// but now it does not have to.
-		groupRows, err = e.processor.consumeGroupRows(e.ctx, groupRows)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		_, err = e.processor.appendResult2Chunk(e.ctx, groupRows, e.resultChunks[i], remained)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		if remainingRowsInGroup == 0 {
-			e.processor.resetPartialResult()
-			break
+// appendGroupRows copies the rows in [begin, end) of the child chunk to the current partition.
+func (e *WindowExec) appendGroupRows(begin, end int) error {
+	for begin < end {
+		numRows := mathutil.Min(end-begin, e.maxChunkSize-e.partitionChk.NumRows())
+		e.partitionChk.Append(e.childResult, begin, begin+numRows)
+		begin += numRows
+		if e.partitionChk.NumRows() == e.maxChunkSize {
+			if err := e.partition.Add(e.partitionChk); err != nil {
+				return err
+			}
+			e.partitionChk = e.partition.AllocChunk()
 		}
 	}
 	return nil
 }
 
+// consumeGroupRows lets the processor consume the rows of the current partition, whose results are appended to the
+// chunks by appendResult2Chunk later.
+func (e *WindowExec) consumeGroupRows() (err error) {
+	if e.partitionChk.NumRows() > 0 {
+		if err = e.partition.Add(e.partitionChk); err != nil {
+			return err
+		}
+		e.partitionChk = e.partition.AllocChunk()
+	}
+	failpoint.Inject("testWindowRowContainerSpill", func(val failpoint.Value) {
+		if val.(bool) {
+			// Wait for the spilling to finish, so the processor reads the partition from disk.
+			e.partition.ActionSpill().WaitForTest()
+		}
+	})
+	if err = e.rows.reset(e.partition); err != nil {
+		return err
+	}
+	if e.rows.numRows == 0 {
+		return nil
+	}
+	return e.processor.consumeGroupRows(e.ctx, e.rows)
+}
+
+// appendResult2Chunk appends the next rows of the current partition with the results of the window functions to chk.
+func (e *WindowExec) appendResult2Chunk(chk *chunk.Chunk) error {
+	e.rows.checkSpilled()
+	remained := mathutil.Min(chk.RequiredRows()-chk.NumRows(), int(e.rows.numRows-e.emittedRows))
+	for i := 0; i < remained; i++ {
+		chk.AppendPartialRowByColIdxs(0, e.rows.getRow(e.emittedRows+uint64(i)), e.inputColIdxs)
+	}
+	if e.rows.err != nil {
+		return e.rows.err
+	}
+	e.emittedRows += uint64(remained)
+	if err := e.processor.appendResult2Chunk(e.ctx, e.rows, chk, remained); err != nil {
+		return err
+	}
+	// The errors of reading the rows in the processor are recorded in rows.
+	return e.rows.err
+}
+
 func (e *WindowExec) fetchChild(ctx context.Context) (EOF bool, err error) {
-	childResult := newFirstChunk(e.children[0])
-	err = Next(ctx, e.children[0], childResult)
+	// The rows are copied to the partition, so the child chunk can be reused.
+	if e.childResult == nil {
+		e.childResult = newFirstChunk(e.children[0])
+	}
+	err = Next(ctx, e.children[0], e.childResult)
 	if err != nil {
 		return false, errors.Trace(err)
 	}
 	// No more data.
-	numRows := childResult.NumRows()
-	if numRows == 0 {
-		return true, nil
-	}
+	return e.childResult.NumRows() == 0, nil
+}
 
-	resultChk := chunk.New(e.retFieldTypes, 0, numRows)
-	err = e.copyChk(childResult, resultChk)
-	if err != nil {
-		return false, err
-	}
-	e.resultChunks = append(e.resultChunks, resultChk)
-	e.remainingRowsInChunk = append(e.remainingRowsInChunk, numRows)
+// maxPartitionCachedChunks is the max number of the chunks read from disk that are cached by partitionRows.
+const maxPartitionCachedChunks = 4
+
+// partitionRows provides the accesses to the rows of the current partition for the window processors. The rows are
+// stored in a RowContainer, which may have been spilled to disk.
+type partitionRows struct {
+	container  *chunk.RowContainer
+	fieldTypes []*types.FieldType
+	chunkSize  uint64
+	numRows    uint64
+	// inMemory indicates the partition isn't spilled, in which case rows are all the rows of the partition.
+	inMemory bool
+	rows     []chunk.Row
+	// cachedChunks caches the chunks read from disk in the LRU order, the most recently used one is the first. The
+	// frames of the sliding windows may span several chunks, so a few chunks are cached.
+	cachedChunks []cachedChunk
+	// buf is reused by getRows to collect the rows read from disk.
+	buf []chunk.Row
+	// err is the first error met by getRow. getRow can't return the error since it's used as the callback of
+	// SlidingWindowAggFunc.Slide, so the processors should check it after reading the rows.
+	err error
+}
 
-	e.childResult = childResult
-	return false, nil
+type cachedChunk struct {
+	chkIdx int
+	chk    *chunk.Chunk
 }
 
-func (e *WindowExec) copyChk(src, dst *chunk.Chunk) error {
-	columns := e.Schema().Columns[:len(e.Schema().Columns)-e.numWindowFuncs]
-	for i, col := range columns {
-		if err := dst.MakeRefTo(i, src, col.Index); err != nil {
+func (r *partitionRows) reset(container *chunk.RowContainer) error {
+	r.container = container
+	r.numRows = uint64(container.NumRow())
+	r.rows = r.rows[:0]
+	r.clearCachedChunks()
+	r.err = nil
+	r.inMemory = !container.AlreadySpilled()
+	if !r.inMemory {
+		return nil
+	}
+	for i := 0; i < container.NumChunks(); i++ {
+		chk, err := container.GetChunk(i)
+		if err != nil {
 			return err
 		}
+		for j := 0; j < chk.NumRows(); j++ {
+			r.rows = append(r.rows, chk.GetRow(j))
+		}
 	}
 	return nil
 }
 
+// checkSpilled drops the references to the in-memory rows once the container is spilled, so that their memory can be
+// released. The rows are read from disk afterwards.
+func (r *partitionRows) checkSpilled() {
+	if r.inMemory && r.container.AlreadySpilled() {
+		r.inMemory = false
+		r.rows = nil
+	}
+}
+
+func (r *partitionRows) clearCachedChunks() {
+	for i := range r.cachedChunks {
+		r.cachedChunks[i] = cachedChunk{}
+	}
+	r.cachedChunks = r.cachedChunks[:0]
+}
+
+func (r *partitionRows) getRow(i uint64) chunk.Row {
+	if r.inMemory {
+		return r.rows[i]
+	}
+	chk, err := r.getChunk(int(i / r.chunkSize))
+	if err != nil {
+		if r.err == nil {
+			r.err = err
+		}
+		// Return a placeholder row to keep the caller going, the error is checked later.
+		return chunk.MutRowFromTypes(r.fieldTypes).ToRow()
+	}
+	return chk.GetRow(int(i % r.chunkSize))
+}
+
+// getChunk gets the chkIdx-th chunk from the cache, or reads it from disk and caches it.
+func (r *partitionRows) getChunk(chkIdx int) (*chunk.Chunk, error) {
+	for i, cached := range r.cachedChunks {
+		if cached.chkIdx == chkIdx {
+			copy(r.cachedChunks[1:i+1], r.cachedChunks[:i])
+			r.cachedChunks[0] = cached
+			return cached.chk, nil
+		}
+	}
+	chk, err := r.container.GetChunk(chkIdx)
+	if err != nil {
+		return nil, err
+	}
+	// Evict the least recently used chunk if the cache is full.
+	if len(r.cachedChunks) < maxPartitionCachedChunks {
+		r.cachedChunks = append(r.cachedChunks, cachedChunk{})
+	}
+	copy(r.cachedChunks[1:], r.cachedChunks[:len(r.cachedChunks)-1])
+	r.cachedChunks[0] = cachedChunk{chkIdx: chkIdx, chk: chk}
+	return chk, nil
+}
+
+// getRows gets the rows in [begin, end), the returned slice is only valid until the next call.
+func (r *partitionRows) getRows(begin, end uint64) ([]chunk.Row, error) {
+	r.checkSpilled()
+	if r.inMemory {
+		return r.rows[begin:end], nil
+	}
+	r.buf = r.buf[:0]
+	for i := begin; i < end; i++ {
+		r.buf = append(r.buf, r.getRow(i))
+	}
+	return r.buf, r.err
+}
+
 // windowProcessor is the interface for processing different kinds of windows.
 type windowProcessor interface {
 	// consumeGroupRows updates the result for an window function using the input rows
 	// which belong to the same partition.
-	consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error)
-	// appendResult2Chunk appends the final results to chunk.
+	consumeGroupRows(ctx sessionctx.Context, rows *partitionRows) error
+	// appendResult2Chunk appends the final results of the next remained rows in the partition to chunk.
 	// It is called when there are no more rows in current partition.
-	appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error)
+	appendResult2Chunk(ctx sessionctx.Context, rows *partitionRows, chk *chunk.Chunk, remained int) error
 	// resetPartialResult resets the partial result to the original state for a specific window function.
 	resetPartialResult()
 }
@@ -200,30 +404,36 @@ type aggWindowProcessor struct {
 	partialResults []aggfuncs.PartialResult
 }
 
-func (p *aggWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	for i, windowFunc := range p.windowFuncs {
-		// @todo Add memory trace
-		_, err := windowFunc.UpdatePartialResult(ctx, rows, p.partialResults[i])
+func (p *aggWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows *partitionRows) error {
+	// The rows are consumed chunk by chunk, so a spilled partition isn't read into memory at once.
+	for begin := uint64(0); begin < rows.numRows; begin += rows.chunkSize {
+		groupRows, err := rows.getRows(begin, mathutil.MinUint64(begin+rows.chunkSize, rows.numRows))
 		if err != nil {
-			return nil, err
+			return err
+		}
+		for i, windowFunc := range p.windowFuncs {
+			// @todo Add memory trace
+			_, err = windowFunc.UpdatePartialResult(ctx, groupRows, p.partialResults[i])
+			if err != nil {
+				return err
+			}
 		}
 	}
-	rows = rows[:0]
-	return rows, nil
+	return nil
 }
 
-func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
+func (p *aggWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows *partitionRows, chk *chunk.Chunk, remained int) error {
 	for remained > 0 {
 		for i, windowFunc := range p.windowFuncs {
 			// TODO: We can extend the agg func interface to avoid the `for` loop  here.
 			err := windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 		}
 		remained--
 	}
-	return rows, nil
+	return nil
 }
 
 func (p *aggWindowProcessor) resetPartialResult() {
@@ -286,12 +496,12 @@ func (p *rowFrameWindowProcessor) getEndOffset(numRows uint64) uint64 {
 	return 0
 }
 
-func (p *rowFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	return rows, nil
+func (p *rowFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows *partitionRows) error {
+	return nil
 }
 
-func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
-	numRows := uint64(len(rows))
+func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows *partitionRows, chk *chunk.Chunk, remained int) error {
+	numRows := rows.numRows
 	var (
 		err                      error
 		initializedSlidingWindow bool
@@ -319,16 +529,14 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 			for i, windowFunc := range p.windowFuncs {
 				slidingWindowAggFunc := slidingWindowAggFuncs[i]
 				if slidingWindowAggFunc != nil && initializedSlidingWindow {
-					err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-						return rows[u]
-					}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+					err = slidingWindowAggFunc.Slide(ctx, rows.getRow, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 					if err != nil {
-						return nil, err
+						return err
 					}
 				}
 				err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			}
 			continue
@@ -337,9 +545,7 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 		for i, windowFunc := range p.windowFuncs {
 			slidingWindowAggFunc := slidingWindowAggFuncs[i]
 			if slidingWindowAggFunc != nil && initializedSlidingWindow {
-				err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-					return rows[u]
-				}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+				err = slidingWindowAggFunc.Slide(ctx, rows.getRow, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 			} else {
 				// For MinMaxSlidingWindowAggFuncs, it needs the absolute value of each start of window, to compare
 				// whether elements inside deque are out of current window.
@@ -347,14 +553,18 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 					// Store start inside MaxMinSlidingWindowAggFunc.windowInfo
 					minMaxSlidingWindowAggFunc.SetWindowStart(start)
 				}
-				_, err = windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i])
+				var frameRows []chunk.Row
+				frameRows, err = rows.getRows(start, end)
+				if err == nil {
+					_, err = windowFunc.UpdatePartialResult(ctx, frameRows, p.partialResults[i])
+				}
 			}
 			if err != nil {
-				return nil, err
+				return err
 			}
 			err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if slidingWindowAggFunc == nil {
 				windowFunc.ResetPartialResult(p.partialResults[i])
@@ -367,7 +577,7 @@ func (p *rowFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, row
 	for i, windowFunc := range p.windowFuncs {
 		windowFunc.ResetPartialResult(p.partialResults[i])
 	}
-	return rows, nil
+	return nil
 }
 
 func (p *rowFrameWindowProcessor) resetPartialResult() {
@@ -387,16 +597,16 @@ type rangeFrameWindowProcessor struct {
 	expectedCmpResult int64
 }
 
-func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows []chunk.Row) (uint64, error) {
+func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows *partitionRows) (uint64, error) {
 	if p.start.UnBounded {
 		return 0, nil
 	}
-	numRows := uint64(len(rows))
+	numRows := rows.numRows
 	for ; p.lastStartOffset < numRows; p.lastStartOffset++ {
 		var res int64
 		var err error
 		for i := range p.orderByCols {
-			res, _, err = p.start.CmpFuncs[i](ctx, p.orderByCols[i], p.start.CalcFuncs[i], rows[p.lastStartOffset], rows[p.curRowIdx])
+			res, _, err = p.start.CmpFuncs[i](ctx, p.orderByCols[i], p.start.CalcFuncs[i], rows.getRow(p.lastStartOffset), rows.getRow(p.curRowIdx))
 			if err != nil {
 				return 0, err
 			}
@@ -413,8 +623,8 @@ func (p *rangeFrameWindowProcessor) getStartOffset(ctx sessionctx.Context, rows
 	return p.lastStartOffset, nil
 }
 
-func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []chunk.Row) (uint64, error) {
-	numRows := uint64(len(rows))
+func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows *partitionRows) (uint64, error) {
+	numRows := rows.numRows
 	if p.end.UnBounded {
 		return numRows, nil
 	}
@@ -422,7 +632,7 @@ func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []
 		var res int64
 		var err error
 		for i := range p.orderByCols {
-			res, _, err = p.end.CmpFuncs[i](ctx, p.end.CalcFuncs[i], p.orderByCols[i], rows[p.curRowIdx], rows[p.lastEndOffset])
+			res, _, err = p.end.CmpFuncs[i](ctx, p.end.CalcFuncs[i], p.orderByCols[i], rows.getRow(p.curRowIdx), rows.getRow(p.lastEndOffset))
 			if err != nil {
 				return 0, err
 			}
@@ -439,7 +649,7 @@ func (p *rangeFrameWindowProcessor) getEndOffset(ctx sessionctx.Context, rows []
 	return p.lastEndOffset, nil
 }
 
-func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk, remained int) ([]chunk.Row, error) {
+func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, rows *partitionRows, chk *chunk.Chunk, remained int) error {
 	var (
 		err                      error
 		initializedSlidingWindow bool
@@ -459,11 +669,11 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 	for ; remained > 0; lastStart, lastEnd = start, end {
 		start, err = p.getStartOffset(ctx, rows)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		end, err = p.getEndOffset(ctx, rows)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		p.curRowIdx++
 		remained--
@@ -473,16 +683,14 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 			for i, windowFunc := range p.windowFuncs {
 				slidingWindowAggFunc := slidingWindowAggFuncs[i]
 				if slidingWindowAggFunc != nil && initializedSlidingWindow {
-					err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-						return rows[u]
-					}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+					err = slidingWindowAggFunc.Slide(ctx, rows.getRow, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 					if err != nil {
-						return nil, err
+						return err
 					}
 				}
 				err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 				if err != nil {
-					return nil, err
+					return err
 				}
 			}
 			continue
@@ -491,21 +699,23 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 		for i, windowFunc := range p.windowFuncs {
 			slidingWindowAggFunc := slidingWindowAggFuncs[i]
 			if slidingWindowAggFunc != nil && initializedSlidingWindow {
-				err = slidingWindowAggFunc.Slide(ctx, func(u uint64) chunk.Row {
-					return rows[u]
-				}, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
+				err = slidingWindowAggFunc.Slide(ctx, rows.getRow, lastStart, lastEnd, shiftStart, shiftEnd, p.partialResults[i])
 			} else {
 				if minMaxSlidingWindowAggFunc, ok := windowFunc.(aggfuncs.MaxMinSlidingWindowAggFunc); ok {
 					minMaxSlidingWindowAggFunc.SetWindowStart(start)
 				}
-				_, err = windowFunc.UpdatePartialResult(ctx, rows[start:end], p.partialResults[i])
+				var frameRows []chunk.Row
+				frameRows, err = rows.getRows(start, end)
+				if err == nil {
+					_, err = windowFunc.UpdatePartialResult(ctx, frameRows, p.partialResults[i])
+				}
 			}
 			if err != nil {
-				return nil, err
+				return err
 			}
 			err = windowFunc.AppendFinalResult2Chunk(ctx, p.partialResults[i], chk)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			if slidingWindowAggFunc == nil {
 				windowFunc.ResetPartialResult(p.partialResults[i])
@@ -518,11 +728,11 @@ func (p *rangeFrameWindowProcessor) appendResult2Chunk(ctx sessionctx.Context, r
 	for i, windowFunc := range p.windowFuncs {
 		windowFunc.ResetPartialResult(p.partialResults[i])
 	}
-	return rows, nil
+	return nil
 }
 
-func (p *rangeFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows []chunk.Row) ([]chunk.Row, error) {
-	return rows, nil
+func (p *rangeFrameWindowProcessor) consumeGroupRows(ctx sessionctx.Context, rows *partitionRows) error {
+	return nil
 }
 
 func (p *rangeFrameWindowProcessor) resetPartialResult() {
@@ -14,9 +14,12 @@
 package executor_test
 
 import (
+	"bytes"
 	"fmt"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/util/testkit"
 )
 
@@ -470,3 +473,52 @@ func (s *testSuite7) TestIssue24264(c *C) {
 		"8297270320597030697",
 		"<nil>"))
 }
+
+func (s *testSerialSuite1) TestWindowInDisk(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = true
+	})
+	c.Assert(failpoint.Enable("github.com/pingcap/tidb/executor/testWindowRowContainerSpill", "return(true)"), IsNil)
+	defer func() {
+		c.Assert(failpoint.Disable("github.com/pingcap/tidb/executor/testWindowRowContainerSpill"), IsNil)
+	}()
+
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@tidb_window_concurrency = 1")
+	tk.MustExec("set @@tidb_enable_pipelined_window_function = 0")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c varchar(20))")
+	var buf bytes.Buffer
+	buf.WriteString("insert into t values ")
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(fmt.Sprintf("(%v, %v, '%v')", i%3, i, i))
+	}
+	tk.MustExec(buf.String())
+
+	sqls := []string{
+		"select a, b, c, row_number() over w, rank() over w, lead(c, 40) over w, ntile(7) over w from t window w as (partition by a order by b)",
+		"select a, b, sum(b) over w, count(c) over w, max(b) over w from t window w as (partition by a order by b rows between 50 preceding and 20 following)",
+		"select a, b, first_value(c) over w, nth_value(c, 2) over w from t window w as (partition by a order by b rows between 3 preceding and current row)",
+		"select a, b, sum(b) over w, min(b) over w from t window w as (partition by a order by b range between 100 preceding and 40 following)",
+		"select a, b, count(b) over w, max(c) over w from t window w as (partition by a order by b rows between 150 preceding and 10 preceding)",
+		"select b, avg(b) over w, last_value(c) over w from t window w as (order by b rows between unbounded preceding and 64 following)",
+		"select a, sum(b) over (partition by a), count(*) over () from t",
+	}
+	for _, sql := range sqls {
+		tk.MustExec("set @@tidb_mem_quota_query = default")
+		expected := tk.MustQuery(sql).Sort().Rows()
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), Equals, int64(0))
+
+		tk.MustExec("set @@tidb_mem_quota_query = 1")
+		tk.MustQuery(sql).Sort().Check(expected)
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.MemTracker.BytesConsumed(), Equals, int64(0))
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.BytesConsumed(), Equals, int64(0))
+		c.Assert(tk.Se.GetSessionVars().StmtCtx.DiskTracker.MaxConsumed(), Greater, int64(0))
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
@@ -170,6 +171,10 @@ func updateRecord(ctx context.Context, sctx sessionctx.Context, h kv.Handle, old
 		}
 	}
 
+	if err := checkRowConstraints(sctx, t, newData); err != nil {
+		return false, err
+	}
+
 	// 5. If handle changed, remove the old then add the new record, otherwise update the record.
 	if handleChanged {
 		// For `UPDATE IGNORE`/`INSERT IGNORE ON DUPLICATE KEY UPDATE`
@@ -246,3 +251,27 @@ func resetErrDataTooLong(colName string, rowIdx int, err error) error {
 	newErr := types.ErrDataTooLong.GenWithStack("Data too long for column '%v' at row %v", colName, rowIdx)
 	return newErr
 }
+
+// checkRowConstraints checks the row against the check constraints of the table according to the enforcement level,
+// a violation is reported as a warning when the level is WARN.
+func checkRowConstraints(sctx sessionctx.Context, t table.Table, row []types.Datum) error {
+	level := variable.GetCheckConstraintLevel()
+	if level == variable.CheckConstraintLevelOff {
+		return nil
+	}
+	ct, ok := t.(table.CheckConstraintTable)
+	if !ok {
+		return nil
+	}
+	constraints, err := ct.WritableConstraints(sctx)
+	if err != nil {
+		return err
+	}
+	vars := sctx.GetSessionVars()
+	err = table.CheckRowConstraints(sctx, constraints, row)
+	if err != nil && level == variable.CheckConstraintLevelWarn && table.ErrCheckConstraintViolated.Equal(err) {
+		vars.StmtCtx.AppendWarning(err)
+		return nil
+	}
+	return err
+}
@@ -141,6 +141,10 @@ func (a *AggFuncDesc) Split(ordinal []int) (partialAggDesc, finalAggDesc *AggFun
 		if finalAggDesc.Name == ast.AggFuncGroupConcat || finalAggDesc.Name == ast.AggFuncApproxPercentile {
 			finalAggDesc.Args = append(finalAggDesc.Args, a.Args[len(a.Args)-1]) // separator
 		}
+		if finalAggDesc.Name == ast.AggFuncGroupConcat {
+			// The final phase merges the sorted partial results of the parallel hash aggregation by the order.
+			finalAggDesc.OrderByItems = a.OrderByItems
+		}
 	}
 	return
 }
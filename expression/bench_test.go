@@ -1814,6 +1814,49 @@ func (s *testVectorizeSuite2) TestVecEvalBool(c *C) {
 	}
 }
 
+func (s *testVectorizeSuite2) TestVecEvalBoolByKernel(c *C) {
+	ctx := mock.NewContext()
+	intTp, realTp := types.NewFieldType(mysql.TypeLonglong), types.NewFieldType(mysql.TypeDouble)
+	input := chunk.New([]*types.FieldType{intTp, realTp}, 1024, 1024)
+	for i := 0; i < 1024; i++ {
+		if i%7 == 0 {
+			input.AppendNull(0)
+			input.AppendNull(1)
+			continue
+		}
+		input.AppendInt64(0, int64(i%100-50))
+		input.AppendFloat64(1, float64(i%100-50)/2)
+	}
+	intCol := &Column{Index: 0, RetType: intTp}
+	realCol := &Column{Index: 1, RetType: realTp}
+	for _, funcName := range []string{ast.LT, ast.LE, ast.GT, ast.GE, ast.EQ, ast.NE} {
+		for _, args := range [][]Expression{
+			{intCol, &Constant{Value: types.NewIntDatum(10), RetType: intTp}},
+			{&Constant{Value: types.NewIntDatum(-10), RetType: intTp}, intCol},
+			{realCol, &Constant{Value: types.NewFloat64Datum(3.5), RetType: realTp}},
+			{&Constant{Value: types.NewFloat64Datum(-3.5), RetType: realTp}, realCol},
+		} {
+			expr, err := NewFunction(ctx, funcName, types.NewFieldType(mysql.TypeLonglong), args...)
+			c.Assert(err, IsNil)
+			sel := []int{1, 2, 3}
+			_, ok := filterByKernel(ctx, expr, input, sel, make([]bool, 1024))
+			c.Assert(ok, IsTrue)
+
+			exprs := []Expression{expr, &Constant{Value: types.NewIntDatum(1), RetType: intTp}}
+			selected, nulls, err := VecEvalBool(ctx, exprs, input, nil, nil)
+			c.Assert(err, IsNil)
+			it := chunk.NewIterator4Chunk(input)
+			for row := it.Begin(); row != it.End(); row = it.Next() {
+				ok, _, err := EvalBool(ctx, exprs, row)
+				c.Assert(err, IsNil)
+				c.Assert(ok, Equals, selected[row.Idx()])
+				// Like other filters of int type in VecEvalBool, the null rows are marked.
+				c.Assert(nulls[row.Idx()], Equals, row.IsNull(0))
+			}
+		}
+	}
+}
+
 func BenchmarkVecEvalBool(b *testing.B) {
 	ctx := mock.NewContext()
 	selected := make([]bool, 0, 1024)
@@ -882,6 +882,9 @@ var funcs = map[string]functionClass{
 	ast.JSONKeys:          &jsonKeysFunctionClass{baseFunctionClass{ast.JSONKeys, 1, 2}},
 	ast.JSONLength:        &jsonLengthFunctionClass{baseFunctionClass{ast.JSONLength, 1, 2}},
 
+	JSONSchemaValid:            &jsonSchemaValidFunctionClass{baseFunctionClass{JSONSchemaValid, 2, 2}},
+	JSONSchemaValidationReport: &jsonSchemaValidationReportFunctionClass{baseFunctionClass{JSONSchemaValidationReport, 2, 2}},
+
 	// TiDB internal function.
 	ast.TiDBDecodeKey: &tidbDecodeKeyFunctionClass{baseFunctionClass{ast.TiDBDecodeKey, 1, 1}},
 	// This function is used to show tidb-server version info.
@@ -893,6 +896,9 @@ var funcs = map[string]functionClass{
 	ast.NextVal: &nextValFunctionClass{baseFunctionClass{ast.NextVal, 1, 1}},
 	ast.LastVal: &lastValFunctionClass{baseFunctionClass{ast.LastVal, 1, 1}},
 	ast.SetVal:  &setValFunctionClass{baseFunctionClass{ast.SetVal, 2, 2}},
+
+	// Full-text search function.
+	MatchAgainst: &matchAgainstFunctionClass{baseFunctionClass{MatchAgainst, 3, -1}},
 }
 
 // IsFunctionSupported check if given function name is a builtin sql function.
@@ -904,7 +910,7 @@ func IsFunctionSupported(name string) bool {
 // GetBuiltinList returns a list of builtin functions
 func GetBuiltinList() []string {
 	res := make([]string, 0, len(funcs))
-	notImplementedFunctions := []string{ast.RowFunc, ast.IsTruthWithNull}
+	notImplementedFunctions := []string{ast.RowFunc, ast.IsTruthWithNull, MatchAgainst}
 	for funcName := range funcs {
 		skipFunc := false
 		// Skip not implemented functions
@@ -580,6 +580,11 @@ func (b *builtinCastIntAsTimeSig) evalTime(row chunk.Row) (res types.Time, isNul
 
 	if b.args[0].GetType().Tp == mysql.TypeYear {
 		res, err = types.ParseTimeFromYear(b.ctx.GetSessionVars().StmtCtx, val)
+		if err == nil {
+			// ParseTimeFromYear does not know the target type, apply it and its fsp here.
+			res.SetType(b.tp.Tp)
+			res.SetFsp(int8(b.tp.Decimal))
+		}
 	} else {
 		res, err = types.ParseTimeFromNum(b.ctx.GetSessionVars().StmtCtx, val, b.tp.Tp, int8(b.tp.Decimal))
 	}
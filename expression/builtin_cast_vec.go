@@ -385,6 +385,11 @@ func (b *builtinCastIntAsTimeSig) vecEvalTime(input *chunk.Chunk, result *chunk.
 
 		if b.args[0].GetType().Tp == mysql.TypeYear {
 			tm, err = types.ParseTimeFromYear(stmt, i64s[i])
+			if err == nil {
+				// ParseTimeFromYear does not know the target type, apply it and its fsp here.
+				tm.SetType(b.tp.Tp)
+				tm.SetFsp(fsp)
+			}
 		} else {
 			tm, err = types.ParseTimeFromNum(stmt, i64s[i], b.tp.Tp, fsp)
 		}
@@ -1285,6 +1285,19 @@ func RefineComparedConstant(ctx sessionctx.Context, targetFieldType types.FieldT
 			ParamMarker:  con.ParamMarker,
 		}, false
 	}
+	// An integral string constant compared with a YEAR column is converted to the YEAR value for all the operators,
+	// e.g. `year_col >= '0'` is rewritten to `year_col >= 2000` as `year_col = '0'` is.
+	if targetFieldType.Tp == mysql.TypeYear && con.GetType().EvalType() == types.ETString {
+		doubleDatum, err := dt.ConvertTo(sc, types.NewFieldType(mysql.TypeDouble))
+		if err == nil && doubleDatum.GetFloat64() == math.Trunc(doubleDatum.GetFloat64()) {
+			return &Constant{
+				Value:        intDatum,
+				RetType:      &targetFieldType,
+				DeferredExpr: con.DeferredExpr,
+				ParamMarker:  con.ParamMarker,
+			}, false
+		}
+	}
 	switch op {
 	case opcode.LT, opcode.GE:
 		resultExpr := NewFunctionInternal(ctx, ast.Ceil, types.NewFieldType(mysql.TypeUnspecified), con)
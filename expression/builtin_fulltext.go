@@ -0,0 +1,99 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/fulltext"
+)
+
+// MatchAgainst is the function name of `MATCH (col1, col2, ...) AGAINST (expr [modifier])`. Its arguments are the
+// search modifier, the search string and the columns.
+// It is evaluated row by row by tokenizing the columns, FULLTEXT indexes are not supported and never used.
+const MatchAgainst = "match_against"
+
+var (
+	_ functionClass = &matchAgainstFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinMatchAgainstSig{}
+)
+
+type matchAgainstFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *matchAgainstFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	argTps = append(argTps, types.ETInt)
+	for range args[1:] {
+		argTps = append(argTps, types.ETString)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETReal, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinMatchAgainstSig{baseBuiltinFunc: bf}
+	return sig, nil
+}
+
+type builtinMatchAgainstSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinMatchAgainstSig) Clone() builtinFunc {
+	newSig := &builtinMatchAgainstSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals MATCH (col1, col2, ...) AGAINST (expr [modifier]) by scanning the columns, it returns the relevance
+// of the row for the search string, 0 means the row doesn't match.
+// See https://dev.mysql.com/doc/refman/5.7/en/fulltext-search.html
+func (b *builtinMatchAgainstSig) evalReal(row chunk.Row) (float64, bool, error) {
+	modifier, _, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil {
+		return 0, true, err
+	}
+	against, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, err != nil, err
+	}
+	tokenizer := fulltext.GetTokenizer(b.ctx.GetSessionVars().FullTextTokenizer)
+	if tokenizer == nil {
+		return 0, true, errors.Errorf("unknown full-text tokenizer %s", b.ctx.GetSessionVars().FullTextTokenizer)
+	}
+	docs := make([]string, 0, len(b.args)-2)
+	for _, arg := range b.args[2:] {
+		doc, isNull, err := arg.EvalString(b.ctx, row)
+		if err != nil {
+			return 0, true, err
+		}
+		if !isNull {
+			docs = append(docs, doc)
+		}
+	}
+	query := fulltext.ParseQuery(tokenizer, against, ast.FulltextSearchModifier(modifier).IsBooleanMode())
+	return query.Relevance(tokenizer.Tokenize(strings.Join(docs, " "))), false, nil
+}
@@ -0,0 +1,160 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+const (
+	// JSONSchemaValid is the function name of `JSON_SCHEMA_VALID(schema, document)`.
+	JSONSchemaValid = "json_schema_valid"
+	// JSONSchemaValidationReport is the function name of `JSON_SCHEMA_VALIDATION_REPORT(schema, document)`.
+	JSONSchemaValidationReport = "json_schema_validation_report"
+)
+
+var (
+	_ functionClass = &jsonSchemaValidFunctionClass{}
+	_ functionClass = &jsonSchemaValidationReportFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinJSONSchemaValidSig{}
+	_ builtinFunc = &builtinJSONSchemaValidationReportSig{}
+)
+
+// verifyJSONSchemaArgs checks the arguments of the JSON Schema functions are JSON documents or strings.
+func verifyJSONSchemaArgs(funcName string, args []Expression) error {
+	for i, arg := range args {
+		if evalType := arg.GetType().EvalType(); evalType != types.ETJson && evalType != types.ETString {
+			return json.ErrInvalidJSONData.GenWithStackByArgs(i+1, funcName)
+		}
+	}
+	return nil
+}
+
+// validateJSONSchema evaluates the schema and the document in the arguments and validates the document.
+func validateJSONSchema(ctx sessionctx.Context, funcName string, args []Expression, row chunk.Row) (*json.SchemaValidationFailure, bool, error) {
+	schema, isNull, err := args[0].EvalJSON(ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	if schema.TypeCode != json.TypeCodeObject {
+		return nil, true, json.ErrInvalidJSONType.GenWithStackByArgs(1, funcName, "object")
+	}
+	doc, isNull, err := args[1].EvalJSON(ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	failure, err := json.ValidateSchema(schema, doc)
+	if err != nil {
+		return nil, true, err
+	}
+	return failure, false, nil
+}
+
+type jsonSchemaValidFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonSchemaValidFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if err := verifyJSONSchemaArgs(c.funcName, args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, types.ETJson, types.ETJson)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 1
+	sig := &builtinJSONSchemaValidSig{bf}
+	return sig, nil
+}
+
+type builtinJSONSchemaValidSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONSchemaValidSig) Clone() builtinFunc {
+	newSig := &builtinJSONSchemaValidSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals JSON_SCHEMA_VALID(schema, document), it returns 1 if the document is valid against the schema.
+// See https://dev.mysql.com/doc/refman/8.0/en/json-validation-functions.html#function_json-schema-valid
+func (b *builtinJSONSchemaValidSig) evalInt(row chunk.Row) (int64, bool, error) {
+	failure, isNull, err := validateJSONSchema(b.ctx, JSONSchemaValid, b.args, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if failure != nil {
+		return 0, false, nil
+	}
+	return 1, false, nil
+}
+
+type jsonSchemaValidationReportFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonSchemaValidationReportFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if err := verifyJSONSchemaArgs(c.funcName, args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETJson, types.ETJson, types.ETJson)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinJSONSchemaValidationReportSig{bf}
+	return sig, nil
+}
+
+type builtinJSONSchemaValidationReportSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONSchemaValidationReportSig) Clone() builtinFunc {
+	newSig := &builtinJSONSchemaValidationReportSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals JSON_SCHEMA_VALIDATION_REPORT(schema, document), it returns an object which reports where the
+// document fails the schema.
+// See https://dev.mysql.com/doc/refman/8.0/en/json-validation-functions.html#function_json-schema-validation-report
+func (b *builtinJSONSchemaValidationReportSig) evalJSON(row chunk.Row) (json.BinaryJSON, bool, error) {
+	failure, isNull, err := validateJSONSchema(b.ctx, JSONSchemaValidationReport, b.args, row)
+	if isNull || err != nil {
+		return json.BinaryJSON{}, isNull, err
+	}
+	if failure == nil {
+		return json.CreateBinary(map[string]interface{}{"valid": true}), false, nil
+	}
+	return json.CreateBinary(map[string]interface{}{
+		"valid":                 false,
+		"reason":                failure.Reason(),
+		"schema-location":       failure.SchemaLocation,
+		"document-location":     failure.DocumentLocation,
+		"schema-failed-keyword": failure.FailedKeyword,
+	}), false, nil
+}
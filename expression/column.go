@@ -224,26 +224,68 @@ func (col *Column) Equal(_ sessionctx.Context, expr Expression) bool {
 
 // VecEvalInt evaluates this expression in a vectorized manner.
 func (col *Column) VecEvalInt(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
-	if col.RetType.Hybrid() {
-		it := chunk.NewIterator4Chunk(input)
-		result.ResizeInt64(0, false)
-		for row := it.Begin(); row != it.End(); row = it.Next() {
-			v, null, err := col.EvalInt(ctx, row)
-			if err != nil {
-				return err
-			}
-			if null {
-				result.AppendNull()
-			} else {
-				result.AppendInt64(v)
-			}
-		}
+	if col.RetType.Tp == mysql.TypeBit {
+		return col.vecEvalBitAsInt(ctx, input, result)
+	}
+	if col.RetType.Tp == mysql.TypeEnum || col.RetType.Tp == mysql.TypeSet {
+		col.vecEvalEnumSetAsInt(input, result)
 		return nil
 	}
 	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
 	return nil
 }
 
+// vecEvalEnumSetAsInt decodes the numbers of the ENUM/SET values in the column.
+func (col *Column) vecEvalEnumSetAsInt(input *chunk.Chunk, result *chunk.Column) {
+	n := input.NumRows()
+	src := input.Column(col.Index)
+	sel := input.Sel()
+	isEnum := col.RetType.Tp == mysql.TypeEnum
+	result.ResizeInt64(n, false)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		j := i
+		if sel != nil {
+			j = sel[i]
+		}
+		if src.IsNull(j) {
+			result.SetNull(i, true)
+			continue
+		}
+		if isEnum {
+			i64s[i] = int64(src.GetEnum(j).Value)
+		} else {
+			i64s[i] = int64(src.GetSet(j).Value)
+		}
+	}
+}
+
+// vecEvalBitAsInt decodes the big-endian bytes of the BIT column to the integers.
+func (col *Column) vecEvalBitAsInt(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	src := input.Column(col.Index)
+	sel := input.Sel()
+	sc := ctx.GetSessionVars().StmtCtx
+	result.ResizeInt64(n, false)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		j := i
+		if sel != nil {
+			j = sel[i]
+		}
+		if src.IsNull(j) {
+			result.SetNull(i, true)
+			continue
+		}
+		v, err := types.BinaryLiteral(src.GetBytes(j)).ToInt(sc)
+		if err != nil {
+			return err
+		}
+		i64s[i] = int64(v)
+	}
+	return nil
+}
+
 // VecEvalReal evaluates this expression in a vectorized manner.
 func (col *Column) VecEvalReal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
 	n := input.NumRows()
@@ -278,26 +320,39 @@ func (col *Column) VecEvalReal(ctx sessionctx.Context, input *chunk.Chunk, resul
 
 // VecEvalString evaluates this expression in a vectorized manner.
 func (col *Column) VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
-	if col.RetType.Hybrid() {
-		it := chunk.NewIterator4Chunk(input)
-		result.ReserveString(input.NumRows())
-		for row := it.Begin(); row != it.End(); row = it.Next() {
-			v, null, err := col.EvalString(ctx, row)
-			if err != nil {
-				return err
-			}
-			if null {
-				result.AppendNull()
-			} else {
-				result.AppendString(v)
-			}
-		}
+	if col.RetType.Tp == mysql.TypeEnum || col.RetType.Tp == mysql.TypeSet {
+		col.vecEvalEnumSetAsString(input, result)
 		return nil
 	}
+	// The string of a BIT value is its bytes, which are stored in the column as they are.
 	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
 	return nil
 }
 
+// vecEvalEnumSetAsString decodes the names of the ENUM/SET values in the column.
+func (col *Column) vecEvalEnumSetAsString(input *chunk.Chunk, result *chunk.Column) {
+	n := input.NumRows()
+	src := input.Column(col.Index)
+	sel := input.Sel()
+	isEnum := col.RetType.Tp == mysql.TypeEnum
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		j := i
+		if sel != nil {
+			j = sel[i]
+		}
+		if src.IsNull(j) {
+			result.AppendNull()
+			continue
+		}
+		if isEnum {
+			result.AppendString(src.GetEnum(j).Name)
+		} else {
+			result.AppendString(src.GetSet(j).Name)
+		}
+	}
+}
+
 // VecEvalDecimal evaluates this expression in a vectorized manner.
 func (col *Column) VecEvalDecimal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
 	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
@@ -193,6 +193,20 @@ func (s *testEvaluatorSuite) TestColHybird(c *C) {
 		c.Assert(v, Equals, result.GetInt64(i))
 	}
 
+	// the nulls and the selected rows
+	input.AppendNull(0)
+	input.SetSel([]int{1, 5, 1024})
+	c.Assert(col.VecEvalInt(ctx, input, result), IsNil)
+	c.Assert(result.Int64s(), DeepEquals, []int64{1, 5, 0})
+	c.Assert(result.IsNull(1), IsFalse)
+	c.Assert(result.IsNull(2), IsTrue)
+	result, err = newBuffer(types.ETString, 1024)
+	c.Assert(err, IsNil)
+	c.Assert(col.VecEvalString(ctx, input, result), IsNil)
+	c.Assert(result.GetString(1), Equals, "\x05")
+	c.Assert(result.IsNull(2), IsTrue)
+	input.SetSel(nil)
+
 	// enum
 	ft = types.NewFieldType(mysql.TypeEnum)
 	col.RetType = ft
@@ -210,6 +224,27 @@ func (s *testEvaluatorSuite) TestColHybird(c *C) {
 		c.Assert(err, IsNil)
 		c.Assert(v, Equals, result.GetString(i))
 	}
+	result, err = newBuffer(types.ETInt, 1024)
+	c.Assert(err, IsNil)
+	c.Assert(col.VecEvalInt(ctx, input, result), IsNil)
+	for row, i := it.Begin(), 0; row != it.End(); row, i = it.Next(), i+1 {
+		v, _, err := col.EvalInt(ctx, row)
+		c.Assert(err, IsNil)
+		c.Assert(v, Equals, result.GetInt64(i))
+	}
+
+	// the nulls and the selected rows
+	input.AppendNull(0)
+	input.SetSel([]int{1, 5, 1024})
+	c.Assert(col.VecEvalInt(ctx, input, result), IsNil)
+	c.Assert(result.Int64s(), DeepEquals, []int64{1, 5, 0})
+	c.Assert(result.IsNull(1), IsFalse)
+	c.Assert(result.IsNull(2), IsTrue)
+	result, err = newBuffer(types.ETString, 1024)
+	c.Assert(err, IsNil)
+	c.Assert(col.VecEvalString(ctx, input, result), IsNil)
+	c.Assert(result.GetString(1), Equals, "5")
+	c.Assert(result.IsNull(2), IsTrue)
 
 	// set
 	ft = types.NewFieldType(mysql.TypeSet)
@@ -228,4 +263,25 @@ func (s *testEvaluatorSuite) TestColHybird(c *C) {
 		c.Assert(err, IsNil)
 		c.Assert(v, Equals, result.GetString(i))
 	}
+	result, err = newBuffer(types.ETInt, 1024)
+	c.Assert(err, IsNil)
+	c.Assert(col.VecEvalInt(ctx, input, result), IsNil)
+	for row, i := it.Begin(), 0; row != it.End(); row, i = it.Next(), i+1 {
+		v, _, err := col.EvalInt(ctx, row)
+		c.Assert(err, IsNil)
+		c.Assert(v, Equals, result.GetInt64(i))
+	}
+
+	// the nulls and the selected rows
+	input.AppendNull(0)
+	input.SetSel([]int{1, 5, 1024})
+	c.Assert(col.VecEvalInt(ctx, input, result), IsNil)
+	c.Assert(result.Int64s(), DeepEquals, []int64{1, 5, 0})
+	c.Assert(result.IsNull(1), IsFalse)
+	c.Assert(result.IsNull(2), IsTrue)
+	result, err = newBuffer(types.ETString, 1024)
+	c.Assert(err, IsNil)
+	c.Assert(col.VecEvalString(ctx, input, result), IsNil)
+	c.Assert(result.GetString(1), Equals, "5")
+	c.Assert(result.IsNull(2), IsTrue)
 }
@@ -144,6 +144,15 @@ func (pc *PbConverter) encodeDatum(ft *types.FieldType, d types.Datum) (tipb.Exp
 	case types.KindMysqlEnum:
 		tp = tipb.ExprType_MysqlEnum
 		val = codec.EncodeUint(nil, d.GetUint64())
+	case types.KindMysqlBit:
+		// The BIT values are evaluated as the unsigned integers.
+		v, err := d.GetBinaryLiteral().ToInt(pc.sc)
+		if err != nil {
+			logutil.BgLogger().Error("encode bit", zap.Error(err))
+			return tp, nil, false
+		}
+		tp = tipb.ExprType_Uint64
+		val = codec.EncodeUint(nil, v)
 	default:
 		return tp, nil, false
 	}
@@ -209,12 +218,16 @@ func (pc PbConverter) columnToPBExpr(column *Column) *tipb.Expr {
 		return nil
 	}
 	switch column.GetType().Tp {
-	case mysql.TypeBit, mysql.TypeSet, mysql.TypeGeometry, mysql.TypeUnspecified:
+	case mysql.TypeSet, mysql.TypeGeometry, mysql.TypeUnspecified:
 		return nil
 	case mysql.TypeEnum:
 		if !IsPushDownEnabled("enum", kv.UnSpecified) {
 			return nil
 		}
+	case mysql.TypeBit:
+		if !IsPushDownEnabled("bit", kv.UnSpecified) {
+			return nil
+		}
 	}
 
 	if pc.client.IsRequestTypeSupported(kv.ReqTypeDAG, kv.ReqSubTypeBasic) {
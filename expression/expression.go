@@ -335,6 +335,11 @@ func VecEvalBool(ctx sessionctx.Context, exprList CNFExprs, input *chunk.Chunk,
 	isZero := allocZeroSlice(n)
 	defer deallocateZeroSlice(isZero)
 	for _, expr := range exprList {
+		if filtered, ok := filterByKernel(ctx, expr, input, sel, nulls); ok {
+			sel = filtered
+			input.SetSel(sel)
+			continue
+		}
 		tp := expr.GetType()
 		eType := tp.EvalType()
 		if CanImplicitEvalReal(expr) {
@@ -394,6 +399,84 @@ func VecEvalBool(ctx sessionctx.Context, exprList CNFExprs, input *chunk.Chunk,
 	return selected, nulls, nil
 }
 
+// filterByKernel filters the rows in sel by the fixed-width filter kernels of chunk.Column if the expression is a
+// comparison between a signed integer or double column and a constant. The kernels compare the values in the column
+// directly, rather than evaluating the comparison into a buffer and converting it to bool.
+// The returned bool indicates whether the expression is handled.
+func filterByKernel(ctx sessionctx.Context, expr Expression, input *chunk.Chunk, sel []int, nulls []bool) ([]int, bool) {
+	sf, ok := expr.(*ScalarFunction)
+	if !ok {
+		return nil, false
+	}
+	var op chunk.CompareOp
+	isInt := true
+	switch sf.Function.(type) {
+	case *builtinLTIntSig:
+		op = chunk.CompareLT
+	case *builtinLEIntSig:
+		op = chunk.CompareLE
+	case *builtinGTIntSig:
+		op = chunk.CompareGT
+	case *builtinGEIntSig:
+		op = chunk.CompareGE
+	case *builtinEQIntSig:
+		op = chunk.CompareEQ
+	case *builtinNEIntSig:
+		op = chunk.CompareNE
+	case *builtinLTRealSig:
+		op, isInt = chunk.CompareLT, false
+	case *builtinLERealSig:
+		op, isInt = chunk.CompareLE, false
+	case *builtinGTRealSig:
+		op, isInt = chunk.CompareGT, false
+	case *builtinGERealSig:
+		op, isInt = chunk.CompareGE, false
+	case *builtinEQRealSig:
+		op, isInt = chunk.CompareEQ, false
+	case *builtinNERealSig:
+		op, isInt = chunk.CompareNE, false
+	default:
+		return nil, false
+	}
+	args := sf.GetArgs()
+	col, isCol := args[0].(*Column)
+	con, isCon := args[1].(*Constant)
+	if !isCol || !isCon {
+		col, isCol = args[1].(*Column)
+		con, isCon = args[0].(*Constant)
+		if !isCol || !isCon {
+			return nil, false
+		}
+		op = op.Reverse()
+	}
+	if con.DeferredExpr != nil || con.ParamMarker != nil {
+		return nil, false
+	}
+	if isInt {
+		switch col.RetType.Tp {
+		case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear:
+		default:
+			return nil, false
+		}
+		if mysql.HasUnsignedFlag(col.RetType.Flag) || mysql.HasUnsignedFlag(con.RetType.Flag) {
+			return nil, false
+		}
+		v, isNull, err := con.EvalInt(ctx, chunk.Row{})
+		if err != nil || isNull {
+			return nil, false
+		}
+		return input.Column(col.Index).FilterInt64(op, v, sel, nulls), true
+	}
+	if col.RetType.Tp != mysql.TypeDouble {
+		return nil, false
+	}
+	v, isNull, err := con.EvalReal(ctx, chunk.Row{})
+	if err != nil || isNull {
+		return nil, false
+	}
+	return input.Column(col.Index).FilterFloat64(op, v, sel, nulls), true
+}
+
 func toBool(sc *stmtctx.StatementContext, tp *types.FieldType, eType types.EvalType, buf *chunk.Column, sel []int, isZero []int8) error {
 	switch eType {
 	case types.ETInt:
@@ -1201,6 +1284,11 @@ func canExprPushDown(expr Expression, pc PbConverter, storeType kv.StoreType) bo
 				pc.sc.AppendWarning(errors.New("Expr '" + expr.String() + "' can not be pushed to TiFlash because it contains Enum type"))
 			}
 			return false
+		case mysql.TypeBit:
+			if pc.sc.InExplainStmt {
+				pc.sc.AppendWarning(errors.New("Expr '" + expr.String() + "' can not be pushed to TiFlash because it contains Bit type"))
+			}
+			return false
 		default:
 		}
 	}
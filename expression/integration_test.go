@@ -4640,6 +4640,45 @@ func (s *testIntegrationSuite) TestFuncJSON(c *C) {
 	tk.MustQuery("select json_array(a+b) = json_array(c) from tx1").Check(testkit.Rows("0"))
 }
 
+func (s *testIntegrationSuite) TestJSONSchemaValidation(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	defer s.cleanEnv(c)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int, doc json)")
+	tk.MustExec(`insert into t values (1, '{"latitude": 63.444697, "longitude": 10.445118}'), (2, '{"latitude": 91, "longitude": 0}'), (3, null)`)
+
+	schema := `{"type": "object", "properties": {"latitude": {"type": "number", "minimum": -90, "maximum": 90}, ` +
+		`"longitude": {"type": "number", "minimum": -180, "maximum": 180}}, "required": ["latitude", "longitude"]}`
+	tk.MustQuery("select id, json_schema_valid(?, doc) from t order by id", schema).Check(testkit.Rows("1 1", "2 0", "3 <nil>"))
+	tk.MustQuery("select json_schema_validation_report(?, doc) from t order by id", schema).Check(testkit.Rows(
+		`{"valid": true}`,
+		`{"document-location": "#/latitude", "reason": "The JSON document location '#/latitude' failed requirement 'maximum' at JSON Schema location '#/properties/latitude'", `+
+			`"schema-failed-keyword": "maximum", "schema-location": "#/properties/latitude", "valid": false}`,
+		"<nil>",
+	))
+	tk.MustQuery(`select json_schema_valid('{"required": ["a"]}', '{}'), json_schema_valid(cast('{"type": "array"}' as json), '[]')`).
+		Check(testkit.Rows("0 1"))
+
+	// The schema must be a JSON object.
+	err := tk.QueryToErr(`select json_schema_valid('[]', '{}')`)
+	c.Assert(err.Error(), Equals, "[json:3853]Invalid JSON type in argument 1 to function json_schema_valid; an object is required.")
+	err = tk.QueryToErr(`select json_schema_validation_report('{"type"', '{}')`)
+	c.Assert(err.Error(), Matches, `\[json:3140\]Invalid JSON text.*`)
+	err = tk.QueryToErr(`select json_schema_valid('{"$ref": "http://example.com/schema"}', '{}')`)
+	c.Assert(err.Error(), Equals, "[json:1235]This version of TiDB doesn't yet support 'references in JSON Schema'")
+	tk.MustGetErrCode(`select json_schema_valid(1, '{}')`, mysql.ErrInvalidJSONData)
+	tk.MustGetErrCode(`select json_schema_valid('{}')`, mysql.ErrWrongParamcountToNativeFct)
+
+	// The schema can be enforced by a check constraint.
+	tk.MustExec("set @@global.tidb_check_constraint_enforcement = 'ON'")
+	defer tk.MustExec("set @@global.tidb_check_constraint_enforcement = default")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec(`create table t(doc json, check (json_schema_valid('{"type": "object", "required": ["name"]}', doc)))`)
+	tk.MustExec(`insert into t values ('{"name": "a"}')`)
+	tk.MustGetErrCode(`insert into t values ('{"age": 1}')`, errno.ErrCheckConstraintViolated)
+}
+
 func (s *testIntegrationSuite) TestColumnInfoModified(c *C) {
 	testKit := testkit.NewTestKit(c, s.store)
 	defer s.cleanEnv(c)
@@ -9168,6 +9207,56 @@ func (s *testIntegrationSuite) Test23262(c *C) {
 	tk.MustQuery("select * from t where a='2'").Check(testkit.Rows("2002"))
 }
 
+func (s *testIntegrationSuite) TestCompareYearWithConstants(c *C) {
+	defer s.cleanEnv(c)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a year, key(a))")
+	tk.MustExec("insert into t values(2021), (2000), (0)")
+	for _, hint := range []string{"use_index(t)", "use_index(t, a)"} {
+		// The int constants in the IN list are adjusted to the YEAR values as `a = 21` does.
+		tk.MustQuery(fmt.Sprintf("select /*+ %s */ a from t where a in (21, 0)", hint)).Sort().Check(testkit.Rows("0", "2021"))
+		tk.MustQuery(fmt.Sprintf("select /*+ %s */ a from t where a not in (21, 0)", hint)).Check(testkit.Rows("2000"))
+		tk.MustQuery(fmt.Sprintf("select /*+ %s */ a from t where a in (21, 2000, '0')", hint)).Sort().Check(testkit.Rows("2000", "2021"))
+		// The string constants are adjusted to the YEAR values for the range comparisons and BETWEEN as well.
+		tk.MustQuery(fmt.Sprintf("select /*+ %s */ a from t where a >= '0'", hint)).Sort().Check(testkit.Rows("2000", "2021"))
+		tk.MustQuery(fmt.Sprintf("select /*+ %s */ a from t where a between '20' and '22'", hint)).Check(testkit.Rows("2021"))
+		tk.MustQuery(fmt.Sprintf("select /*+ %s */ a from t where a not between '20' and '22'", hint)).Sort().Check(testkit.Rows("0", "2000"))
+		tk.MustQuery(fmt.Sprintf("select /*+ %s */ a from t where a between '0' and 1", hint)).Check(testkit.Rows("2000"))
+		tk.MustQuery(fmt.Sprintf("select /*+ %s */ a from t where a between 1999.5 and '00'", hint)).Check(testkit.Rows("2000"))
+	}
+	tk.MustQuery("select a, a in (21, 0), a between '20' and '22' from t order by a").Check(testkit.Rows("0 1 0", "2000 0 0", "2021 1 1"))
+}
+
+func (s *testIntegrationSuite) TestCastYearAsTime(c *C) {
+	defer s.cleanEnv(c)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a year)")
+	tk.MustExec("insert into t values(2021), (0)")
+	tk.MustQuery("select cast(a as date), cast(a as datetime), cast(a as datetime(3)) from t order by a").Check(testkit.Rows(
+		"0000-00-00 0000-00-00 00:00:00 0000-00-00 00:00:00.000",
+		"2021-00-00 2021-00-00 00:00:00 2021-00-00 00:00:00.000"))
+}
+
+func (s *testIntegrationSuite) TestPushDownWithSQLMode(c *C) {
+	defer s.cleanEnv(c)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int unsigned, b int unsigned)")
+	tk.MustExec("insert into t values(1, 2)")
+	tk.MustExec("set @@sql_mode = 'NO_UNSIGNED_SUBTRACTION'")
+	// The selection is evaluated by the coprocessor, which must see the session SQL mode.
+	tk.MustQuery("select a - b from t where a - b < 0").Check(testkit.Rows("-1"))
+	tk.MustExec("set @@sql_mode = default")
+	err := tk.QueryToErr("select a - b from t where a - b < 0")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Matches, ".*BIGINT UNSIGNED value is out of range.*")
+}
+
 func (s *testIntegrationSerialSuite) TestPartitionPruningRelaxOP(c *C) {
 	// Discovered while looking at issue 19941 (not completely related)
 	// relaxOP relax the op > to >= and < to <=
@@ -9812,3 +9901,91 @@ func (s *testIntegrationSuite2) TestIssue25526(c *C) {
 	rows := tk.MustQuery("select tbl_6.col_31 from tbl_6 where col_31 in (select col_102 from tbl_17 where tbl_17.col_102 = 9999 and tbl_17.col_105 = 0);")
 	rows.Check(testkit.Rows())
 }
+
+func (s *testIntegrationSuite) TestMatchAgainst(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, title varchar(100), body text)")
+	tk.MustExec(`insert into t values (1, 'MySQL Tutorial', 'DBMS stands for DataBase, the database tutorial'),
+		(2, 'How To Use MySQL Well', 'After you went through a tutorial'),
+		(3, 'Optimizing MySQL', 'In this tutorial we will show how to optimize a database'),
+		(4, 'TiDB', 'A distributed database compatible with MySQL'),
+		(5, 'NULL body', null)`)
+
+	// Natural language mode ranks the rows by the relevance.
+	tk.MustQuery("select id, match (title, body) against ('database') as score from t where match (title, body) against ('database') order by score desc, id").
+		Check(testkit.Rows("1 2", "3 1", "4 1"))
+	tk.MustQuery("select id from t where match (title, body) against ('tutorial' in natural language mode) order by match (title, body) against ('tutorial') desc, id").
+		Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select id from t where match (body) against ('nothing')").Check(testkit.Rows())
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 MATCH ... AGAINST is evaluated without a FULLTEXT index, all the rows are scanned"))
+	tk.MustQuery("select id, match (body) against ('database') from t where id = 5").Check(testkit.Rows("5 0"))
+
+	// Boolean mode.
+	tk.MustQuery("select id from t where match (title, body) against ('+mysql -tutorial' in boolean mode)").Check(testkit.Rows("4"))
+	tk.MustQuery("select id from t where match (title, body) against ('+optim*' in boolean mode)").Check(testkit.Rows("3"))
+	tk.MustQuery(`select id from t where match (title, body) against ('"distributed database"' in boolean mode)`).Check(testkit.Rows("4"))
+	tk.MustQuery("select id from t where match (title, body) against ('+database +tutorial' in boolean mode) order by id").Check(testkit.Rows("1", "3"))
+
+	// The search string must be constant.
+	tk.MustExec("set @word = 'tidb'")
+	tk.MustQuery("select id from t where match (title) against (@word)").Check(testkit.Rows("4"))
+	tk.MustGetErrCode("select id from t where match (title) against (body)", errno.ErrWrongArguments)
+	tk.MustGetErrCode("select id from t where match (title) against ('mysql' with query expansion)", errno.ErrNotSupportedYet)
+
+	// The ngram tokenizer splits the CJK texts.
+	tk.MustExec("insert into t values (6, '分布式数据库', '兼容 MySQL 协议')")
+	tk.MustQuery("select id from t where match (title) against ('数据库')").Check(testkit.Rows())
+	tk.MustExec("set @@tidb_fulltext_tokenizer = 'ngram'")
+	tk.MustQuery("select id from t where match (title) against ('数据库')").Check(testkit.Rows("6"))
+	tk.MustQuery("select id from t where match (title, body) against ('+分布 +协议' in boolean mode)").Check(testkit.Rows("6"))
+	tk.MustGetErrCode("set @@tidb_fulltext_tokenizer = 'unknown'", errno.ErrWrongValueForVar)
+	tk.MustExec("set @@tidb_fulltext_tokenizer = default")
+}
+
+func (s *testIntegrationSuite) TestBitPushDown(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int primary key, a bit(8), b bit(64))")
+	tk.MustExec("insert into t values (1, b'101', 1), (2, 3, 18446744073709551615), (3, b'11111111', 0), (4, null, null)")
+
+	for _, ca := range []struct {
+		cond string
+		ids  []string
+	}{
+		{"a = 5", []string{"1"}},
+		{"a = b'101'", []string{"1"}},
+		{"a > 3", []string{"1", "3"}},
+		{"a in (3, 5)", []string{"1", "2"}},
+		{"a < b", []string{"2"}},
+		{"b = 18446744073709551615", []string{"2"}},
+		{"a & 1 = 1", []string{"1", "2", "3"}},
+		{"a | 2 = 7", []string{"1"}},
+		{"a ^ 1 = 4", []string{"1"}},
+		{"~a = 18446744073709551610", []string{"1"}},
+		{"cast(a as signed) = 255", []string{"3"}},
+		{"a + 0 = 3", []string{"2"}},
+		{"hex(a) = 'FF'", []string{"3"}},
+		{"a is null", []string{"4"}},
+	} {
+		sql := "select id from t where " + ca.cond
+		ops := tk.MustQuery("explain " + sql).Rows()
+		c.Assert(ops[2][0], Matches, ".*Selection.*", Commentf("%v", ops))
+		c.Assert(ops[2][2], Equals, "cop[tikv]", Commentf("%v", ops))
+		tk.MustQuery(sql + " order by id").Check(testkit.Rows(ca.ids...))
+	}
+
+	// The BIT columns aren't pushed down once bit is in the blacklist.
+	tk.MustExec("insert into mysql.expr_pushdown_blacklist(name) values('bit')")
+	tk.MustExec("admin reload expr_pushdown_blacklist")
+	defer func() {
+		tk.MustExec("delete from mysql.expr_pushdown_blacklist where name = 'bit'")
+		tk.MustExec("admin reload expr_pushdown_blacklist")
+	}()
+	ops := tk.MustQuery("explain select id from t where a = 5").Rows()
+	c.Assert(ops[1][0], Matches, ".*Selection.*", Commentf("%v", ops))
+	c.Assert(ops[1][2], Equals, "root", Commentf("%v", ops))
+	tk.MustQuery("select id from t where a = 5").Check(testkit.Rows("1"))
+}
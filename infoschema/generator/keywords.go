@@ -0,0 +1,150 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build ignore
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const header = `// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by go generate in infoschema/generator; DO NOT EDIT.
+
+package infoschema
+
+// Keywords lists the keywords shown in INFORMATION_SCHEMA.KEYWORDS. It's generated from the ReservedKeyword,
+// UnReservedKeyword and TiDBKeyword token sections of parser.y.
+var Keywords = []KeywordInfo{
+`
+
+const (
+	sectionNone = iota
+	sectionReserved
+	sectionUnreserved
+)
+
+// sectionStarts are the comments which start the token sections in parser.y.
+var sectionStarts = map[string]int{
+	"The following tokens belong to ReservedKeyword.":   sectionReserved,
+	"The following tokens belong to UnReservedKeyword.": sectionUnreserved,
+	"The following tokens belong to TiDBKeyword.":       sectionUnreserved,
+	"The following tokens belong to NotKeywordToken.":   sectionNone,
+}
+
+// tokenRe matches the token declarations like `add "ADD"`.
+var tokenRe = regexp.MustCompile(`^\s*\w+\s+"(\w+)"\s*$`)
+
+// parserDir returns the directory of the parser module required by go.mod.
+func parserDir() (string, error) {
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Dir}}", "github.com/pingcap/parser").Output()
+	if err != nil {
+		return "", err
+	}
+	dir := strings.TrimSpace(string(out))
+	if dir == "" {
+		return "", fmt.Errorf("the parser module isn't downloaded, run `go mod download` first")
+	}
+	return dir, nil
+}
+
+func parseKeywords(fileName string) (map[string]bool, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	keywords := make(map[string]bool)
+	section := sectionNone
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "%") {
+			section = sectionNone
+			continue
+		}
+		started := false
+		for start, s := range sectionStarts {
+			if strings.Contains(line, start) {
+				section, started = s, true
+				break
+			}
+		}
+		if started || section == sectionNone {
+			continue
+		}
+		if m := tokenRe.FindStringSubmatch(line); m != nil {
+			word := strings.ToUpper(m[1])
+			keywords[word] = keywords[word] || section == sectionReserved
+		}
+	}
+	return keywords, scanner.Err()
+}
+
+func main() {
+	dir, err := parserDir()
+	if err != nil {
+		log.Fatalln("parserDir", err)
+	}
+	keywords, err := parseKeywords(filepath.Join(dir, "parser.y"))
+	if err != nil {
+		log.Fatalln("parseKeywords", err)
+	}
+	if len(keywords) == 0 {
+		log.Fatalln("no keywords are found in parser.y")
+	}
+	words := make([]string, 0, len(keywords))
+	for word := range keywords {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	w := new(bytes.Buffer)
+	w.WriteString(header)
+	for _, word := range words {
+		fmt.Fprintf(w, "\t{%q, %v},\n", word, keywords[word])
+	}
+	w.WriteString("}\n")
+	data, err := format.Source(w.Bytes())
+	if err != nil {
+		log.Println("[Warn] keywords_generated.go: gofmt failed", err)
+		data = w.Bytes() // write original data for debugging
+	}
+	if err := os.WriteFile("keywords_generated.go", data, 0644); err != nil {
+		log.Fatalln("WriteFile", err)
+	}
+}
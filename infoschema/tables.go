@@ -172,6 +172,24 @@ const (
 	TableDeadlocks = "DEADLOCKS"
 	// TableDataLockWaits is current lock waiting status table.
 	TableDataLockWaits = "DATA_LOCK_WAITS"
+	// TableKeywords is the string constant of the keywords table.
+	TableKeywords = "KEYWORDS"
+	// TableSQLFeatures is the string constant of the SQL features table.
+	TableSQLFeatures = "SQL_FEATURES"
+	// TableTransactionSummary is the string constant of the recently committed transactions table.
+	TableTransactionSummary = "TRANSACTION_SUMMARY"
+	// TableUserAttributes is the string constant of the user attributes table.
+	TableUserAttributes = "USER_ATTRIBUTES"
+	// TableTiDBIndexUsage is the string constant of the index usage table.
+	TableTiDBIndexUsage = "TIDB_INDEX_USAGE"
+	// TableWriteConflicts is the string constant of the recent write conflicts table.
+	TableWriteConflicts = "WRITE_CONFLICTS"
+	// TableViewTableUsage is the string constant of the view dependencies table.
+	TableViewTableUsage = "VIEW_TABLE_USAGE"
+	// TableTiDBViewValidity is the string constant of the view validity table.
+	TableTiDBViewValidity = "TIDB_VIEW_VALIDITY"
+	// TableTiDBEffectivePrivileges is the string constant of the effective privileges table.
+	TableTiDBEffectivePrivileges = "TIDB_EFFECTIVE_PRIVILEGES"
 )
 
 var tableIDMap = map[string]int64{
@@ -251,6 +269,15 @@ var tableIDMap = map[string]int64{
 	TableDataLockWaits:                      autoid.InformationSchemaDBID + 74,
 	TableStatementsSummaryEvicted:           autoid.InformationSchemaDBID + 75,
 	ClusterTableStatementsSummaryEvicted:    autoid.InformationSchemaDBID + 76,
+	TableKeywords:                           autoid.InformationSchemaDBID + 77,
+	TableSQLFeatures:                        autoid.InformationSchemaDBID + 78,
+	TableTransactionSummary:                 autoid.InformationSchemaDBID + 79,
+	TableUserAttributes:                     autoid.InformationSchemaDBID + 80,
+	TableTiDBIndexUsage:                     autoid.InformationSchemaDBID + 81,
+	TableWriteConflicts:                     autoid.InformationSchemaDBID + 82,
+	TableViewTableUsage:                     autoid.InformationSchemaDBID + 83,
+	TableTiDBViewValidity:                   autoid.InformationSchemaDBID + 84,
+	TableTiDBEffectivePrivileges:            autoid.InformationSchemaDBID + 85,
 }
 
 type columnInfo struct {
@@ -624,6 +651,22 @@ var tableViewsCols = []columnInfo{
 	{name: "COLLATION_CONNECTION", tp: mysql.TypeVarchar, size: 32, flag: mysql.NotNullFlag},
 }
 
+var tableViewTableUsageCols = []columnInfo{
+	{name: "VIEW_CATALOG", tp: mysql.TypeVarchar, size: 512, flag: mysql.NotNullFlag},
+	{name: "VIEW_SCHEMA", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "VIEW_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "TABLE_CATALOG", tp: mysql.TypeVarchar, size: 512, flag: mysql.NotNullFlag},
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+}
+
+var tableTiDBViewValidityCols = []columnInfo{
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "IS_VALID", tp: mysql.TypeVarchar, size: 3, flag: mysql.NotNullFlag, comment: "Whether the view can be queried with the current schema"},
+	{name: "REASON", tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The error of querying the view if it's invalid"},
+}
+
 var tableRoutinesCols = []columnInfo{
 	{name: "SPECIFIC_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
 	{name: "ROUTINE_CATALOG", tp: mysql.TypeVarchar, size: 512, flag: mysql.NotNullFlag},
@@ -789,6 +832,7 @@ var slowQueryCols = []columnInfo{
 	{name: variable.SlowLogPreProcSubQueryTimeStr, tp: mysql.TypeDouble, size: 22},
 	{name: variable.SlowLogOptimizeTimeStr, tp: mysql.TypeDouble, size: 22},
 	{name: variable.SlowLogWaitTSTimeStr, tp: mysql.TypeDouble, size: 22},
+	{name: variable.SlowLogAdmissionQueueTimeStr, tp: mysql.TypeDouble, size: 22},
 	{name: execdetails.PreWriteTimeStr, tp: mysql.TypeDouble, size: 22},
 	{name: execdetails.WaitPrewriteBinlogTimeStr, tp: mysql.TypeDouble, size: 22},
 	{name: execdetails.CommitTimeStr, tp: mysql.TypeDouble, size: 22},
@@ -842,6 +886,7 @@ var slowQueryCols = []columnInfo{
 	{name: variable.SlowLogPlanDigest, tp: mysql.TypeVarchar, size: 128},
 	{name: variable.SlowLogPrevStmt, tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
 	{name: variable.SlowLogQuerySQLStr, tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
+	{name: variable.SlowLogStmtInstanceIDStr, tp: mysql.TypeVarchar, size: 64},
 }
 
 // TableTiDBHotRegionsCols is TiDB hot region mem table columns.
@@ -1218,6 +1263,7 @@ var tableStatementsSummaryCols = []columnInfo{
 	{name: stmtsummary.PrevSampleTextStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The previous statement before commit"},
 	{name: stmtsummary.PlanDigestStr, tp: mysql.TypeVarchar, size: 64, comment: "Digest of its execution plan"},
 	{name: stmtsummary.PlanStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "Sampled execution plan"},
+	{name: stmtsummary.MaxLatencyStmtInstanceIDStr, tp: mysql.TypeVarchar, size: 64, comment: "Statement instance ID of the execution with max latency"},
 }
 
 var tableStorageStatsCols = []columnInfo{
@@ -1384,6 +1430,62 @@ var tableDeadlocksCols = []columnInfo{
 	// {name: "ALL_SQL_DIGESTS", tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "A list of the digests of SQL statements that the transaction has executed"},
 }
 
+var tableTransactionSummaryCols = []columnInfo{
+	{name: "START_TS", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "The start ts of the transaction"},
+	{name: "COMMIT_TS", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "The commit ts of the transaction"},
+	{name: "SESSION_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag, comment: "Which session this transaction belongs to"},
+	{name: "FINISH_TIME", tp: mysql.TypeTimestamp, decimal: 6, size: 26, comment: "The physical time when the transaction is committed"},
+	{name: execdetails.GetCommitTSTimeStr, tp: mysql.TypeDouble, size: 22, comment: "The time of getting the commit ts in seconds"},
+	{name: execdetails.PreWriteTimeStr, tp: mysql.TypeDouble, size: 22, comment: "The time of the prewrite phase in seconds"},
+	{name: execdetails.CommitTimeStr, tp: mysql.TypeDouble, size: 22, comment: "The time of the commit phase in seconds"},
+	{name: execdetails.ResolveLockTimeStr, tp: mysql.TypeDouble, size: 22, comment: "The time of resolving the locks in seconds"},
+	{name: execdetails.LocalLatchWaitTimeStr, tp: mysql.TypeDouble, size: 22, comment: "The time of waiting for the local latches in seconds"},
+	{name: execdetails.CommitBackoffTimeStr, tp: mysql.TypeDouble, size: 22, comment: "The backoff time of the commit in seconds"},
+	{name: execdetails.BackoffTypesStr, tp: mysql.TypeVarchar, size: 64, comment: "The backoff types of the commit"},
+	{name: execdetails.WriteKeysStr, tp: mysql.TypeLonglong, size: 22, comment: "The number of the written keys"},
+	{name: execdetails.WriteSizeStr, tp: mysql.TypeLonglong, size: 22, comment: "The size of the written keys and values in bytes"},
+	{name: execdetails.PrewriteRegionStr, tp: mysql.TypeLonglong, size: 22, comment: "The number of the prewritten regions"},
+	{name: execdetails.TxnRetryStr, tp: mysql.TypeLonglong, size: 22, comment: "The retry count of the transaction"},
+}
+
+var tableWriteConflictsCols = []columnInfo{
+	{name: "OCCUR_TIME", tp: mysql.TypeTimestamp, decimal: 6, size: 26, comment: "The physical time when the write conflict occurs"},
+	{name: "START_TS", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "The start ts of the transaction which fails to commit"},
+	{name: "SESSION_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag, comment: "Which session the failed transaction belongs to"},
+	{name: "KEY", tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The conflicting key"},
+	{name: "KEY_INFO", tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The conflicting key decoded into the table, index and row info"},
+	{name: "CONFLICT_START_TS", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "The start ts of the transaction which has written the key"},
+	{name: "CONFLICT_COMMIT_TS", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "The commit ts of the transaction which has written the key"},
+	{name: "CONFLICT_SQL_DIGESTS", tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The digests of the statements of the transaction which has written the key, if it is committed by this instance recently"},
+}
+
+var tableUserAttributesCols = []columnInfo{
+	{name: "USER", tp: mysql.TypeVarchar, size: 32, flag: mysql.NotNullFlag},
+	{name: "HOST", tp: mysql.TypeVarchar, size: 255, flag: mysql.NotNullFlag},
+	{name: "ATTRIBUTE", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
+}
+
+var tableTiDBIndexUsageCols = []columnInfo{
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "INDEX_NAME", tp: mysql.TypeVarchar, size: 64},
+	{name: "QUERY_COUNT", tp: mysql.TypeLonglong, size: 21, comment: "The number of the queries reading the index, NULL if the usage isn't collected"},
+	{name: "ROWS_SELECTED", tp: mysql.TypeLonglong, size: 21, comment: "The number of the rows read from the index, NULL if the usage isn't collected"},
+	{name: "LAST_USED_AT", tp: mysql.TypeDatetime, size: 19, comment: "The last time the index is read"},
+}
+
+var tableTiDBEffectivePrivilegesCols = []columnInfo{
+	{name: "USER", tp: mysql.TypeVarchar, size: 32, flag: mysql.NotNullFlag},
+	{name: "HOST", tp: mysql.TypeVarchar, size: 255, flag: mysql.NotNullFlag},
+	{name: "TABLE_SCHEMA", tp: mysql.TypeVarchar, size: 64, comment: "The database of the privilege, NULL for the global privileges"},
+	{name: "TABLE_NAME", tp: mysql.TypeVarchar, size: 64, comment: "The table of the privilege, NULL for the global and database privileges"},
+	{name: "COLUMN_NAME", tp: mysql.TypeVarchar, size: 64, comment: "The column of the privilege, NULL except for the column privileges"},
+	{name: "PRIVILEGE_TYPE", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "IS_GRANTABLE", tp: mysql.TypeVarchar, size: 3, flag: mysql.NotNullFlag},
+	{name: "GRANTED_BY_ROLE", tp: mysql.TypeVarchar, size: 512, comment: "The role the privilege is inherited from, NULL if it's granted to the user directly"},
+	{name: "ROLE_PATH", tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The chain of the roles from the user to GRANTED_BY_ROLE"},
+}
+
 var tableDataLockWaitsCols = []columnInfo{
 	{name: "KEY", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag, comment: "The key that's being waiting on"},
 	{name: "TRX_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Current transaction that's waiting for the lock"},
@@ -1391,6 +1493,17 @@ var tableDataLockWaitsCols = []columnInfo{
 	{name: "SQL_DIGEST", tp: mysql.TypeVarchar, size: 64, comment: "Digest of the SQL that's trying to acquire the lock"},
 }
 
+var tableKeywordsCols = []columnInfo{
+	{name: "WORD", tp: mysql.TypeVarchar, size: 128},
+	{name: "RESERVED", tp: mysql.TypeLong, size: 11},
+}
+
+var tableSQLFeaturesCols = []columnInfo{
+	{name: "FEATURE_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "IS_SUPPORTED", tp: mysql.TypeVarchar, size: 3, flag: mysql.NotNullFlag},
+	{name: "COMMENTS", tp: mysql.TypeVarchar, size: 256},
+}
+
 var tableStatementsSummaryEvictedCols = []columnInfo{
 	{name: "BEGIN_TIME", tp: mysql.TypeTimestamp, size: 26},
 	{name: "END_TIME", tp: mysql.TypeTimestamp, size: 26},
@@ -1779,6 +1892,15 @@ var tableNameToColumns = map[string][]columnInfo{
 	TableTiDBTrx:                            tableTiDBTrxCols,
 	TableDeadlocks:                          tableDeadlocksCols,
 	TableDataLockWaits:                      tableDataLockWaitsCols,
+	TableKeywords:                           tableKeywordsCols,
+	TableSQLFeatures:                        tableSQLFeaturesCols,
+	TableTransactionSummary:                 tableTransactionSummaryCols,
+	TableUserAttributes:                     tableUserAttributesCols,
+	TableTiDBIndexUsage:                     tableTiDBIndexUsageCols,
+	TableWriteConflicts:                     tableWriteConflictsCols,
+	TableViewTableUsage:                     tableViewTableUsageCols,
+	TableTiDBViewValidity:                   tableTiDBViewValidityCols,
+	TableTiDBEffectivePrivileges:            tableTiDBEffectivePrivilegesCols,
 }
 
 func createInfoSchemaTable(_ autoid.Allocators, meta *model.TableInfo) (table.Table, error) {
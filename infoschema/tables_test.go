@@ -22,6 +22,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 	"unsafe"
@@ -52,6 +53,7 @@ import (
 	"github.com/pingcap/tidb/store/mockstore/mockstorage"
 	"github.com/pingcap/tidb/store/mockstore/unistore"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/conflicthistory"
 	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/pdapi"
 	"github.com/pingcap/tidb/util/resourcegrouptag"
@@ -59,6 +61,7 @@ import (
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
 	"github.com/pingcap/tidb/util/testutil"
+	"github.com/pingcap/tidb/util/txnsummary"
 	"github.com/tikv/client-go/v2/tikv"
 	"google.golang.org/grpc"
 )
@@ -591,6 +594,7 @@ func prepareSlowLogfile(c *C, slowLogFileName string) {
 # Txn_start_ts: 406315658548871171
 # User@Host: root[root] @ localhost [127.0.0.1]
 # Conn_ID: 6
+# Stmt_instance_id: 8a6dbc5e-6c4d-4f5b-9d3a-2c1e0f7b4a91:42
 # Exec_retry_time: 0.12 Exec_retry_count: 57
 # Query_time: 4.895492
 # Parse_time: 0.4
@@ -598,6 +602,7 @@ func prepareSlowLogfile(c *C, slowLogFileName string) {
 # Rewrite_time: 0.000000003 Preproc_subqueries: 2 Preproc_subqueries_time: 0.000000002
 # Optimize_time: 0.00000001
 # Wait_TS: 0.000000003
+# Admission_queue_time: 0.5
 # LockKeys_time: 1.71 Request_count: 1 Prewrite_time: 0.19 Wait_prewrite_binlog_time: 0.21 Commit_time: 0.01 Commit_backoff_time: 0.18 Backoff_types: [txnLock] Resolve_lock_time: 0.03 Write_keys: 15 Write_size: 480 Prewrite_region: 1 Txn_retry: 8
 # Cop_time: 0.3824278 Process_time: 0.161 Request_count: 1 Total_keys: 100001 Process_keys: 100000
 # Rocksdb_delete_skipped_count: 100 Rocksdb_key_skipped_count: 10 Rocksdb_block_cache_hit_count: 10 Rocksdb_block_read_count: 10 Rocksdb_block_read_byte: 100
@@ -680,10 +685,10 @@ func (s *testTableSuite) TestSlowQuery(c *C) {
 	tk.MustExec("set time_zone = '+08:00';")
 	re := tk.MustQuery("select * from information_schema.slow_query")
 	re.Check(testutil.RowsWithSep("|",
-		"2019-02-12 19:33:56.571953|406315658548871171|root|localhost|6|57|0.12|4.895492|0.4|0.2|0.000000003|2|0.000000002|0.00000001|0.000000003|0.19|0.21|0.01|0|0.18|[txnLock]|0.03|0|15|480|1|8|0.3824278|0.161|0.101|0.092|1.71|1|100001|100000|100|10|10|10|100|test||0|42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772|t1:1,t2:2|0.1|0.2|0.03|127.0.0.1:20160|0.05|0.6|0.8|0.0.0.0:20160|70724|65536|0|0|0|0||0|1|1|0|abcd|60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4|update t set i = 2;|select * from t_slim;"))
+		"2019-02-12 19:33:56.571953|406315658548871171|root|localhost|6|57|0.12|4.895492|0.4|0.2|0.000000003|2|0.000000002|0.00000001|0.000000003|0.5|0.19|0.21|0.01|0|0.18|[txnLock]|0.03|0|15|480|1|8|0.3824278|0.161|0.101|0.092|1.71|1|100001|100000|100|10|10|10|100|test||0|42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772|t1:1,t2:2|0.1|0.2|0.03|127.0.0.1:20160|0.05|0.6|0.8|0.0.0.0:20160|70724|65536|0|0|0|0||0|1|1|0|abcd|60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4|update t set i = 2;|select * from t_slim;|8a6dbc5e-6c4d-4f5b-9d3a-2c1e0f7b4a91:42"))
 	tk.MustExec("set time_zone = '+00:00';")
 	re = tk.MustQuery("select * from information_schema.slow_query")
-	re.Check(testutil.RowsWithSep("|", "2019-02-12 11:33:56.571953|406315658548871171|root|localhost|6|57|0.12|4.895492|0.4|0.2|0.000000003|2|0.000000002|0.00000001|0.000000003|0.19|0.21|0.01|0|0.18|[txnLock]|0.03|0|15|480|1|8|0.3824278|0.161|0.101|0.092|1.71|1|100001|100000|100|10|10|10|100|test||0|42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772|t1:1,t2:2|0.1|0.2|0.03|127.0.0.1:20160|0.05|0.6|0.8|0.0.0.0:20160|70724|65536|0|0|0|0||0|1|1|0|abcd|60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4|update t set i = 2;|select * from t_slim;"))
+	re.Check(testutil.RowsWithSep("|", "2019-02-12 11:33:56.571953|406315658548871171|root|localhost|6|57|0.12|4.895492|0.4|0.2|0.000000003|2|0.000000002|0.00000001|0.000000003|0.5|0.19|0.21|0.01|0|0.18|[txnLock]|0.03|0|15|480|1|8|0.3824278|0.161|0.101|0.092|1.71|1|100001|100000|100|10|10|10|100|test||0|42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772|t1:1,t2:2|0.1|0.2|0.03|127.0.0.1:20160|0.05|0.6|0.8|0.0.0.0:20160|70724|65536|0|0|0|0||0|1|1|0|abcd|60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4|update t set i = 2;|select * from t_slim;|8a6dbc5e-6c4d-4f5b-9d3a-2c1e0f7b4a91:42"))
 
 	// Test for long query.
 	f, err := os.OpenFile(slowLogFileName, os.O_CREATE|os.O_WRONLY, 0644)
@@ -1685,6 +1690,27 @@ func (s *testTableSuite) TestTrx(c *C) {
 		"425070846483628033 2021-05-20 21:16:35.778000 <nil> LockWaiting 2021-05-20 13:18:30.123456 0 0 10 user1 db1 [sql1, sql2]"))
 }
 
+func (s *testTableSuite) TestKeywordsAndSQLFeatures(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustQuery("select * from information_schema.keywords where word in ('SELECT', 'ACCOUNT', 'ADMIN') order by word").Check(
+		testkit.Rows("ACCOUNT 0", "ADMIN 0", "SELECT 1"))
+	tk.MustQuery("select count(*) from information_schema.keywords").Check(testkit.Rows(strconv.Itoa(len(infoschema.Keywords))))
+
+	// Reserved keywords can't be used as an identifier without quotes.
+	p := parser.New()
+	for _, kw := range infoschema.Keywords {
+		_, err := p.ParseOneStmt(fmt.Sprintf("create table t (%s int)", kw.Word), "", "")
+		c.Assert(err != nil, Equals, kw.Reserved, Commentf("keyword %s", kw.Word))
+	}
+
+	tk.MustExec("set @@tidb_enable_window_function = 0")
+	tk.MustQuery("select is_supported from information_schema.sql_features where feature_name = 'WINDOW FUNCTION'").Check(testkit.Rows("NO"))
+	tk.MustExec("set @@tidb_enable_window_function = 1")
+	tk.MustQuery("select is_supported from information_schema.sql_features where feature_name = 'WINDOW FUNCTION'").Check(testkit.Rows("YES"))
+	tk.MustQuery("select is_supported, comments from information_schema.sql_features where feature_name = 'FOREIGN KEY'").Check(
+		testkit.Rows("NO Parsed but not enforced"))
+}
+
 func (s *testTableSuite) TestInfoschemaDeadlockPrivilege(c *C) {
 	tk := s.newTestKitWithRoot(c)
 	tk.MustExec("create user 'testuser'@'localhost'")
@@ -1706,6 +1732,171 @@ func (s *testTableSuite) TestInfoschemaDeadlockPrivilege(c *C) {
 	_ = tk.MustQuery("select * from information_schema.deadlocks")
 }
 
+func (s *testTableSuite) TestTransactionSummary(c *C) {
+	txnsummary.GlobalTxnSummary.Resize(10)
+	defer txnsummary.GlobalTxnSummary.Resize(0)
+	defer txnsummary.GlobalTxnSummary.Clear()
+
+	tk := s.newTestKitWithRoot(c)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	tk.MustQuery("select write_keys, prewrite_region, txn_retry, commit_ts > start_ts, session_id = connection_id() " +
+		"from information_schema.transaction_summary " +
+		"where start_ts = json_extract(@@tidb_last_txn_info, '$.start_ts')").Check(testkit.Rows("2 1 0 1 1"))
+
+	tk.MustExec("create user 'txn_summary'@'localhost'")
+	c.Assert(tk.Se.Auth(&auth.UserIdentity{
+		Username: "txn_summary",
+		Hostname: "localhost",
+	}, nil, nil), IsTrue)
+	err := tk.QueryToErr("select * from information_schema.transaction_summary")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[planner:1227]Access denied; you need (at least one of) the PROCESS privilege(s) for this operation")
+}
+
+func (s *testTableSuite) TestWriteConflicts(c *C) {
+	txnsummary.GlobalTxnSummary.Resize(10)
+	defer txnsummary.GlobalTxnSummary.Resize(0)
+	defer txnsummary.GlobalTxnSummary.Clear()
+	conflicthistory.GlobalConflictHistory.Resize(10)
+	defer conflicthistory.GlobalConflictHistory.Resize(0)
+	defer conflicthistory.GlobalConflictHistory.Clear()
+
+	tk := s.newTestKitWithRoot(c)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, unique key ub(b))")
+	tk.MustExec("insert into t values (1, 1)")
+	tk2 := s.newTestKitWithRoot(c)
+	tk2.MustExec("use test")
+
+	tk.MustExec("begin optimistic")
+	tk.MustExec("update t set b = 10 where a = 1")
+	tk2.MustExec("update t set b = 20 where a = 1")
+	conflictStartTS := tk2.Se.GetSessionVars().TxnCtx.StartTS
+	_, digest := parser.NormalizeDigest("update t set b = 20 where a = 1")
+	err := tk.ExecToErr("commit")
+	c.Assert(kv.ErrWriteConflict.Equal(err), IsTrue)
+	c.Assert(err.Error(), Matches, ".*key=\\{tableID=[0-9]+, tableName=test.t, indexID=1, indexName=ub, indexValues=\\{1, \\}\\}.*")
+	c.Assert(err.Error(), Matches, ".*conflictTxnSQLDigests=\\["+digest.String()+"\\].*")
+
+	tableID := tk.MustQuery("select tidb_table_id from information_schema.tables where table_schema = 'test' and table_name = 't'").Rows()[0][0]
+	tk.MustQuery("select key_info, conflict_start_ts = ?, conflict_sql_digests, session_id = connection_id() from information_schema.write_conflicts",
+		conflictStartTS).Check(testkit.Rows(
+		fmt.Sprintf("{tableID=%v, tableName=test.t, indexID=1, indexName=ub, indexValues={1, }} 1 [%s] 1", tableID, digest.String())))
+}
+
+func (s *testTableSuite) TestUserAttributes(c *C) {
+	tk := s.newTestKitWithRoot(c)
+	tk.MustExec("create user 'attr_payments'@'%', 'attr_ads'@'localhost', 'attr_none'@'%'")
+	defer tk.MustExec("drop user 'attr_payments'@'%', 'attr_ads'@'localhost', 'attr_none'@'%'")
+	tk.MustExec(`update mysql.user set user_attributes = '{"metadata": {"team": "payments", "owner": "alice"}}' where user = 'attr_payments'`)
+	tk.MustExec(`update mysql.user set user_attributes = '{"metadata": {"team": "ads"}}' where user = 'attr_ads'`)
+	tk.MustQuery("select * from information_schema.user_attributes where user like 'attr\\_%' order by user").Check(testkit.Rows(
+		`attr_ads localhost {"team": "ads"}`,
+		"attr_none % <nil>",
+		`attr_payments % {"owner": "alice", "team": "payments"}`,
+	))
+	// Filter the accounts by the attributes.
+	tk.MustQuery(`select user, host from information_schema.user_attributes where json_extract(attribute, '$.team') = 'payments'`).
+		Check(testkit.Rows("attr_payments %"))
+
+	// The other users' attributes are invisible without the privileges.
+	tk1 := s.newTestKitWithRoot(c)
+	c.Assert(tk1.Se.Auth(&auth.UserIdentity{Username: "attr_ads", Hostname: "localhost"}, nil, nil), IsTrue)
+	tk1.MustQuery("select * from information_schema.user_attributes").Check(testkit.Rows(`attr_ads localhost {"team": "ads"}`))
+}
+
+func (s *testTableSuite) TestViewDependencies(c *C) {
+	tk := s.newTestKitWithRoot(c)
+	tk.MustExec("create database view_deps")
+	defer tk.MustExec("drop database view_deps")
+	tk.MustExec("use view_deps")
+	tk.MustExec("create table t1 (a int, b int)")
+	tk.MustExec("create table t2 (a int)")
+	tk.MustExec("create view v1 as select t1.a, t1.b from t1 join t2 on t1.a = t2.a")
+	tk.MustExec("create view v2 as with cte as (select a from v1) select a from cte")
+	tk.MustExec("create view v3 as select a from t2")
+	tk.MustQuery("select view_name, table_schema, table_name from information_schema.view_table_usage " +
+		"where view_schema = 'view_deps' order by view_name, table_name").Check(testkit.Rows(
+		"v1 view_deps t1",
+		"v1 view_deps t2",
+		"v2 view_deps v1",
+		"v3 view_deps t2",
+	))
+	tk.MustQuery("select table_name, is_valid, reason from information_schema.tidb_view_validity " +
+		"where table_schema = 'view_deps' order by table_name").Check(testkit.Rows(
+		"v1 YES <nil>",
+		"v2 YES <nil>",
+		"v3 YES <nil>",
+	))
+
+	// Dropping a column referenced by v1 makes both v1 and v2 on top of it invalid.
+	tk.MustExec("alter table t1 drop column b")
+	tk.MustQuery("select table_name, is_valid, reason from information_schema.tidb_view_validity " +
+		"where table_schema = 'view_deps' order by table_name").Check(testkit.Rows(
+		"v1 NO [planner:1356]View 'view_deps.v1' references invalid table(s) or column(s) or function(s) or definer/invoker of view lack rights to use them",
+		"v2 NO [planner:1356]View 'view_deps.v2' references invalid table(s) or column(s) or function(s) or definer/invoker of view lack rights to use them",
+		"v3 YES <nil>",
+	))
+	tk.MustExec("drop table t2")
+	tk.MustQuery("select table_name, is_valid from information_schema.tidb_view_validity " +
+		"where table_schema = 'view_deps' order by table_name").Check(testkit.Rows("v1 NO", "v2 NO", "v3 NO"))
+	// The dependencies are kept after the tables are dropped.
+	tk.MustQuery("select count(*) from information_schema.view_table_usage where view_schema = 'view_deps' and table_name = 't2'").
+		Check(testkit.Rows("2"))
+
+	// The views are invisible without the privileges.
+	tk.MustExec("create user 'view_deps'@'localhost'")
+	defer tk.MustExec("drop user 'view_deps'@'localhost'")
+	tk.MustExec("grant select on view_deps.v3 to 'view_deps'@'localhost'")
+	tk1 := s.newTestKitWithRoot(c)
+	c.Assert(tk1.Se.Auth(&auth.UserIdentity{Username: "view_deps", Hostname: "localhost"}, nil, nil), IsTrue)
+	tk1.MustQuery("select view_name, table_name from information_schema.view_table_usage").Check(testkit.Rows("v3 t2"))
+	tk1.MustQuery("select table_name, is_valid from information_schema.tidb_view_validity").Check(testkit.Rows("v3 NO"))
+}
+
+func (s *testTableSuite) TestEffectivePrivileges(c *C) {
+	tk := s.newTestKitWithRoot(c)
+	tk.MustExec("create user 'eff_user'@'localhost', 'eff_other'@'%'")
+	tk.MustExec("create role 'eff_r1', 'eff_r2'")
+	tk.MustExec("create table test.eff_t (a int)")
+	defer tk.MustExec("drop table test.eff_t")
+	defer tk.MustExec("drop user 'eff_user'@'localhost', 'eff_other'@'%', 'eff_r1', 'eff_r2'")
+	tk.MustExec("grant process on *.* to 'eff_user'@'localhost' with grant option")
+	tk.MustExec("grant update (a) on test.eff_t to 'eff_r1'")
+	tk.MustExec("grant select on test.* to 'eff_r2'")
+	tk.MustExec("grant backup_admin on *.* to 'eff_r2'")
+	tk.MustExec("grant 'eff_r2' to 'eff_r1'")
+	tk.MustExec("grant 'eff_r1' to 'eff_user'@'localhost'")
+	tk.MustExec("grant 'eff_r2' to 'eff_other'@'%'")
+
+	// The privileges inherited from the roles are listed with the chain of the roles.
+	tk.MustQuery("select * from information_schema.tidb_effective_privileges where user = 'eff_user' order by privilege_type").Check(testkit.Rows(
+		"eff_user localhost <nil> <nil> <nil> BACKUP_ADMIN NO 'eff_r2'@'%' 'eff_r1'@'%' -> 'eff_r2'@'%'",
+		"eff_user localhost <nil> <nil> <nil> PROCESS YES <nil> <nil>",
+		"eff_user localhost test <nil> <nil> SELECT NO 'eff_r2'@'%' 'eff_r1'@'%' -> 'eff_r2'@'%'",
+		"eff_user localhost test eff_t a UPDATE NO 'eff_r1'@'%' 'eff_r1'@'%'",
+	))
+	tk.MustQuery("select user, granted_by_role from information_schema.tidb_effective_privileges " +
+		"where table_schema = 'test' and privilege_type = 'SELECT' and user like 'eff\\_%' order by user").Check(testkit.Rows(
+		"eff_other 'eff_r2'@'%'",
+		"eff_r1 'eff_r2'@'%'",
+		"eff_r2 <nil>",
+		"eff_user 'eff_r2'@'%'",
+	))
+
+	// The other users' privileges are invisible without the privileges.
+	tk1 := s.newTestKitWithRoot(c)
+	c.Assert(tk1.Se.Auth(&auth.UserIdentity{Username: "eff_other", Hostname: "localhost"}, nil, nil), IsTrue)
+	tk1.MustQuery("select user, privilege_type, role_path from information_schema.tidb_effective_privileges order by privilege_type").Check(testkit.Rows(
+		"eff_other BACKUP_ADMIN 'eff_r2'@'%'",
+		"eff_other SELECT 'eff_r2'@'%'",
+	))
+}
+
 func (s *testDataLockWaitSuite) SetUpSuite(c *C) {
 	testleak.BeforeTest()
 
@@ -204,6 +204,8 @@ const (
 	ReqTypeDAG      = 103
 	ReqTypeAnalyze  = 104
 	ReqTypeChecksum = 105
+	// ReqTypeCentralAutoID is only sent between the TiDB servers, see autoid.CentralBatchRequest.
+	ReqTypeCentralAutoID = 1001
 
 	ReqSubTypeBasic      = 0
 	ReqSubTypeDesc       = 10000
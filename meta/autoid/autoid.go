@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/metrics"
@@ -460,7 +461,11 @@ func NewAllocatorsFromTblInfo(store kv.Storage, schemaID int64, tblInfo *model.T
 
 	hasRowID := !tblInfo.PKIsHandle && !tblInfo.IsCommonHandle
 	hasAutoIncID := tblInfo.GetAutoIncrementColInfo() != nil
-	if hasRowID || hasAutoIncID {
+	if hasAutoIncID && tblInfo.AutoIdCache == 1 && config.GetGlobalConfig().Experimental.EnableCentralAutoID && getCentralClient(store) != nil {
+		// AUTO_ID_CACHE 1 means the auto_increment values are strictly increasing across the TiDB servers, they are
+		// allocated by the centralized allocator service if it's enabled.
+		allocs = append(allocs, newCentralAllocator(store, dbID, tblInfo))
+	} else if hasRowID || hasAutoIncID {
 		alloc := NewAllocator(store, dbID, tblInfo.IsAutoIncColUnsigned(), RowIDAllocType, idCacheOpt)
 		allocs = append(allocs, alloc)
 	}
@@ -26,10 +26,13 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/types"
 )
 
 func TestT(t *testing.T) {
@@ -920,3 +923,138 @@ func (*testSuite) TestAllocComputationIssue(c *C) {
 	c.Assert(min, Equals, int64(7))
 	c.Assert(max, Equals, int64(13))
 }
+
+type localCentralClient struct {
+	service *autoid.CentralService
+}
+
+func (c *localCentralClient) Call(ctx context.Context, req *autoid.CentralRequest) (*autoid.CentralResponse, error) {
+	if c.service == nil {
+		return nil, autoid.ErrCentralUnreachable
+	}
+	return c.service.Handle(ctx, req)
+}
+
+// TestCentralAllocator tests the tables with AUTO_ID_CACHE 1 allocate the strictly increasing IDs from the
+// centralized allocator service.
+func (*testSuite) TestCentralAllocator(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Experimental.EnableCentralAutoID = true
+	})
+	store, err := mockstore.NewMockStore()
+	c.Assert(err, IsNil)
+	defer func() {
+		err := store.Close()
+		c.Assert(err, IsNil)
+	}()
+	dbID, tblID := int64(1), int64(2)
+	tblInfo := &model.TableInfo{
+		ID:          tblID,
+		Name:        model.NewCIStr("t"),
+		AutoIdCache: 1,
+		PKIsHandle:  true,
+		Columns: []*model.ColumnInfo{{
+			ID:        1,
+			Name:      model.NewCIStr("a"),
+			FieldType: *types.NewFieldType(mysql.TypeLong),
+		}},
+	}
+	tblInfo.Columns[0].Flag |= mysql.AutoIncrementFlag | mysql.PriKeyFlag
+	err = kv.RunInNewTxn(context.Background(), store, false, func(ctx context.Context, txn kv.Transaction) error {
+		m := meta.NewMeta(txn)
+		err = m.CreateDatabase(&model.DBInfo{ID: dbID, Name: model.NewCIStr("a")})
+		c.Assert(err, IsNil)
+		err = m.CreateTableOrView(dbID, tblInfo)
+		c.Assert(err, IsNil)
+		return nil
+	})
+	c.Assert(err, IsNil)
+
+	service := autoid.NewCentralService(store)
+	autoid.RegisterCentralClient(store, &localCentralClient{service: service})
+	defer autoid.UnregisterCentralClient(store)
+
+	// The allocators of two servers.
+	ctx := context.Background()
+	alloc1 := autoid.NewAllocatorsFromTblInfo(store, dbID, tblInfo).Get(autoid.RowIDAllocType)
+	alloc2 := autoid.NewAllocatorsFromTblInfo(store, dbID, tblInfo).Get(autoid.RowIDAllocType)
+	for i := int64(1); i <= 10; i += 2 {
+		_, id, err := alloc1.Alloc(ctx, tblID, 1, 1, 1)
+		c.Assert(err, IsNil)
+		c.Assert(id, Equals, i)
+		_, id, err = alloc2.Alloc(ctx, tblID, 1, 1, 1)
+		c.Assert(err, IsNil)
+		c.Assert(id, Equals, i+1)
+	}
+	next, err := alloc1.NextGlobalAutoID(tblID)
+	c.Assert(err, IsNil)
+	c.Assert(next, Equals, int64(11))
+
+	// The IDs are leased from the storage in batches.
+	err = kv.RunInNewTxn(context.Background(), store, false, func(ctx context.Context, txn kv.Transaction) error {
+		id, err := meta.NewMeta(txn).GetAutoTableID(dbID, tblID)
+		c.Assert(err, IsNil)
+		c.Assert(id, Equals, int64(4000))
+		return nil
+	})
+	c.Assert(err, IsNil)
+
+	err = alloc2.Rebase(tblID, 100, false)
+	c.Assert(err, IsNil)
+	_, id, err := alloc1.Alloc(ctx, tblID, 1, 1, 1)
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, int64(101))
+
+	// The lease is dropped after the table info changes, the IDs are still increasing.
+	tblInfo.UpdateTS++
+	alloc3 := autoid.NewAllocatorsFromTblInfo(store, dbID, tblInfo).Get(autoid.RowIDAllocType)
+	_, id, err = alloc3.Alloc(ctx, tblID, 1, 1, 1)
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, int64(4001))
+
+	// The servers not hosting the service rebase the storage and let the service drop its lease.
+	err = autoid.RebaseCentralStorage(store, &autoid.CentralRequest{DBID: dbID, TableID: tblID, Base: 10000})
+	c.Assert(err, IsNil)
+	_, err = service.Handle(ctx, &autoid.CentralRequest{Op: autoid.CentralOpDropLease, TableID: tblID})
+	c.Assert(err, IsNil)
+	_, id, err = alloc3.Alloc(ctx, tblID, 1, 1, 1)
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, int64(10001))
+
+	// The IDs are allocated from the storage one by one if the service is unreachable, the lease of the service is
+	// dropped by its next allocation.
+	client := &localCentralClient{}
+	autoid.RegisterCentralClient(store, client)
+	_, id, err = alloc3.Alloc(ctx, tblID, 1, 1, 1)
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, int64(14001))
+	_, id, err = alloc3.Alloc(ctx, tblID, 1, 1, 1)
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, int64(14002))
+	client.service = service
+	_, id, err = alloc3.Alloc(ctx, tblID, 1, 1, 1)
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, int64(14003))
+
+	// Fall back to the allocator of the server if the service is disabled.
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Experimental.EnableCentralAutoID = false
+	})
+	alloc4 := autoid.NewAllocatorsFromTblInfo(store, dbID, tblInfo).Get(autoid.RowIDAllocType)
+	_, id, err = alloc4.Alloc(ctx, tblID, 1, 1, 1)
+	c.Assert(err, IsNil)
+	c.Assert(id, Equals, int64(18003))
+
+	// The services of the old and the new owners fence each other, the stale lease isn't used after the new owner
+	// has leased IDs.
+	oldService, newService := autoid.NewCentralService(store), autoid.NewCentralService(store)
+	req := &autoid.CentralRequest{Op: autoid.CentralOpAlloc, DBID: dbID, TableID: tblID, TableUpdateTS: tblInfo.UpdateTS, N: 1, Increment: 1, Offset: 1}
+	last := id
+	for _, service := range []*autoid.CentralService{oldService, newService, oldService, newService} {
+		resp, err := service.Handle(ctx, req)
+		c.Assert(err, IsNil)
+		c.Assert(resp.Max, Greater, last)
+		last = resp.Max
+	}
+}
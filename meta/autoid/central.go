@@ -0,0 +1,390 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autoid
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// The operations of the centralized allocator service. CentralOpRebase is only handled by the server hosting the
+// service, the other servers rebase the storage by RebaseCentralStorage and then send CentralOpDropLease.
+const (
+	CentralOpAlloc        = "alloc"
+	CentralOpRebase       = "rebase"
+	CentralOpNextGlobalID = "next_global_id"
+	CentralOpDropLease    = "drop_lease"
+)
+
+// Test needs to change it, so it's a variable.
+var centralStep = int64(4000)
+
+// errCentralLeaseStale is returned if another server has leased the IDs of the table after the lease of the service.
+var errCentralLeaseStale = errors.New("the lease of the centralized auto ID allocator is stale")
+
+// ErrCentralUnreachable is the cause of the errors returned by CentralClient if the service can't be reached. The IDs
+// are allocated from the storage by the server itself then.
+var ErrCentralUnreachable = errors.New("the centralized auto ID allocator service is unreachable")
+
+// CentralRequest is the request sent to the centralized allocator service of the tables with AUTO_ID_CACHE 1.
+type CentralRequest struct {
+	Op      string `json:"op"`
+	DBID    int64  `json:"db_id"`
+	TableID int64  `json:"table_id"`
+	// TableUpdateTS is the update timestamp of the table info. The service drops its lease of the table once a newer
+	// table info is seen, because the IDs may have been allocated or rebased in another way by DDL.
+	TableUpdateTS uint64 `json:"table_update_ts"`
+	IsUnsigned    bool   `json:"is_unsigned"`
+	// N, Increment and Offset are the arguments of CentralOpAlloc.
+	N         uint64 `json:"n,omitempty"`
+	Increment int64  `json:"increment,omitempty"`
+	Offset    int64  `json:"offset,omitempty"`
+	// Base and AllocIDs are the arguments of CentralOpRebase.
+	Base     int64 `json:"base,omitempty"`
+	AllocIDs bool  `json:"alloc_ids,omitempty"`
+}
+
+// CentralResponse is the response of the centralized allocator service. The allocated range is (Min, Max], and Max is
+// the next global ID for CentralOpNextGlobalID.
+type CentralResponse struct {
+	Min   int64  `json:"min"`
+	Max   int64  `json:"max"`
+	Error string `json:"error,omitempty"`
+}
+
+// CentralBatchRequest is a batch of the requests forwarded to the server hosting the centralized allocator service.
+type CentralBatchRequest struct {
+	// Token is shared by the servers of the cluster, the batches without it are rejected.
+	Token string `json:"token"`
+	// OwnerID is the ID of the server which the requests are sent to, the server rejects the batch if it isn't the
+	// owner anymore.
+	OwnerID  string            `json:"owner_id"`
+	Requests []*CentralRequest `json:"requests"`
+}
+
+// CentralBatchResponse is the response of CentralBatchRequest, the responses are in the order of the requests.
+type CentralBatchResponse struct {
+	Responses []*CentralResponse `json:"responses"`
+}
+
+// CentralClient sends the requests to the centralized allocator service, which is hosted by a single TiDB server of
+// the cluster.
+type CentralClient interface {
+	Call(ctx context.Context, req *CentralRequest) (*CentralResponse, error)
+}
+
+// centralClients maps the UUID of the store to its CentralClient.
+var centralClients sync.Map
+
+// RegisterCentralClient registers the CentralClient of the store, the tables with AUTO_ID_CACHE 1 allocate IDs from
+// it.
+func RegisterCentralClient(store kv.Storage, client CentralClient) {
+	centralClients.Store(store.UUID(), client)
+}
+
+// UnregisterCentralClient unregisters the CentralClient of the store.
+func UnregisterCentralClient(store kv.Storage) {
+	centralClients.Delete(store.UUID())
+}
+
+func getCentralClient(store kv.Storage) CentralClient {
+	if client, ok := centralClients.Load(store.UUID()); ok {
+		return client.(CentralClient)
+	}
+	return nil
+}
+
+// CentralService is the centralized allocator service. The IDs of a table are allocated by a single allocator, which
+// leases the IDs from the storage in batches, so the IDs are strictly increasing across the TiDB servers.
+type CentralService struct {
+	store kv.Storage
+	mu    sync.Mutex
+	// allocs maps the table ID to its allocator.
+	allocs map[int64]*centralTableAlloc
+}
+
+type centralTableAlloc struct {
+	// mu serializes the allocations of the table, so the lease checked by the fence is the one the IDs come from.
+	mu            sync.Mutex
+	tableUpdateTS uint64
+	alloc         Allocator
+}
+
+// NewCentralService creates a CentralService.
+func NewCentralService(store kv.Storage) *CentralService {
+	return &CentralService{store: store, allocs: make(map[int64]*centralTableAlloc)}
+}
+
+// Reset drops the leases of all the tables. It must be called when the server stops hosting the service, so that the
+// leases are not used after another server has allocated IDs.
+func (s *CentralService) Reset() {
+	s.mu.Lock()
+	s.allocs = make(map[int64]*centralTableAlloc)
+	s.mu.Unlock()
+}
+
+func (s *CentralService) getAlloc(req *CentralRequest) *centralTableAlloc {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.allocs[req.TableID]
+	if !ok || a.tableUpdateTS < req.TableUpdateTS {
+		// The new allocator starts from the end of the last lease persisted in the storage, so the IDs are still
+		// increasing, but the rest of the last lease is skipped.
+		a = &centralTableAlloc{
+			tableUpdateTS: req.TableUpdateTS,
+			alloc:         NewAllocator(s.store, req.DBID, req.IsUnsigned, RowIDAllocType, CustomAutoIncCacheOption(centralStep)),
+		}
+		s.allocs[req.TableID] = a
+	}
+	return a
+}
+
+func (s *CentralService) dropLease(tableID int64) {
+	s.mu.Lock()
+	delete(s.allocs, tableID)
+	s.mu.Unlock()
+}
+
+// dropStaleLease drops the lease of the table if it's still the given one.
+func (s *CentralService) dropStaleLease(tableID int64, a *centralTableAlloc) {
+	s.mu.Lock()
+	if s.allocs[tableID] == a {
+		delete(s.allocs, tableID)
+	}
+	s.mu.Unlock()
+}
+
+// alloc allocates the IDs from the lease of the table. The lease is fenced for every allocation: the allocated IDs
+// are only returned if the end of the lease is still the end persisted in the storage. If another server has leased
+// IDs of the table since, e.g. it has become the owner while this server hasn't noticed that it isn't, the lease
+// is dropped and the IDs are allocated from a new lease, which starts after the IDs leased by the other server.
+func (s *CentralService) alloc(ctx context.Context, req *CentralRequest) (min, max int64, err error) {
+	for i := 0; i < 2; i++ {
+		a := s.getAlloc(req)
+		a.mu.Lock()
+		min, max, err = a.alloc.Alloc(ctx, req.TableID, req.N, req.Increment, req.Offset)
+		if err == nil {
+			err = s.fence(ctx, req, a.alloc.End())
+		}
+		a.mu.Unlock()
+		if err == nil || errors.Cause(err) != errCentralLeaseStale {
+			return min, max, err
+		}
+		s.dropStaleLease(req.TableID, a)
+	}
+	return 0, 0, err
+}
+
+// fence checks the end of the lease is the end persisted in the storage, and writes it back in the same transaction.
+// The write makes the transaction conflict with the one of another server leasing the IDs of the table concurrently,
+// so the IDs of a lease are never returned after the IDs of a newer lease are.
+func (s *CentralService) fence(ctx context.Context, req *CentralRequest, leaseEnd int64) error {
+	return kv.RunInNewTxn(ctx, s.store, true, func(ctx context.Context, txn kv.Transaction) error {
+		m := meta.NewMeta(txn)
+		end, err := GetAutoID(m, req.DBID, req.TableID, RowIDAllocType)
+		if err != nil {
+			return err
+		}
+		if end != leaseEnd {
+			return errors.Annotatef(errCentralLeaseStale, "table %d, lease end %d, persisted end %d", req.TableID, leaseEnd, end)
+		}
+		_, err = GenerateAutoID(m, req.DBID, req.TableID, 0, RowIDAllocType)
+		return err
+	})
+}
+
+// Handle handles the request.
+func (s *CentralService) Handle(ctx context.Context, req *CentralRequest) (*CentralResponse, error) {
+	if req.TableID == 0 {
+		return nil, errInvalidTableID.GenWithStack("Invalid tableID")
+	}
+	if req.Op == CentralOpDropLease {
+		// The next allocator starts from the end persisted in the storage, which may have been rebased.
+		s.dropLease(req.TableID)
+		return &CentralResponse{}, nil
+	}
+	if req.Op == CentralOpAlloc {
+		min, max, err := s.alloc(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return &CentralResponse{Min: min, Max: max}, nil
+	}
+	a := s.getAlloc(req)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	alloc := a.alloc
+	switch req.Op {
+	case CentralOpRebase:
+		if err := alloc.Rebase(req.TableID, req.Base, req.AllocIDs); err != nil {
+			return nil, err
+		}
+		return &CentralResponse{}, nil
+	case CentralOpNextGlobalID:
+		// The rest of the lease will be allocated first.
+		if alloc.End() != 0 {
+			return &CentralResponse{Max: alloc.Base() + 1}, nil
+		}
+		next, err := alloc.NextGlobalAutoID(req.TableID)
+		if err != nil {
+			return nil, err
+		}
+		return &CentralResponse{Max: next}, nil
+	}
+	return nil, errors.Errorf("unknown centralized allocator operation %s", req.Op)
+}
+
+// RebaseCentralStorage rebases the end of the IDs persisted in the storage for CentralOpRebase. It's used by the
+// servers not hosting the service, and CentralOpDropLease must be sent afterwards, so that the service allocates the
+// IDs after the new base.
+func RebaseCentralStorage(store kv.Storage, req *CentralRequest) error {
+	alloc := NewAllocator(store, req.DBID, req.IsUnsigned, RowIDAllocType, CustomAutoIncCacheOption(1))
+	return alloc.Rebase(req.TableID, req.Base, false)
+}
+
+// centralAllocator allocates the IDs of the tables with AUTO_ID_CACHE 1 from the centralized allocator service. If the
+// service is unreachable, the IDs are allocated from the storage one by one like the allocator with AUTO_ID_CACHE 1 on
+// each server does. They're still increasing, because the service checks the end of its lease against the storage
+// before answering and skips the rest of the lease once the storage is moved.
+type centralAllocator struct {
+	store         kv.Storage
+	dbID          int64
+	tableUpdateTS uint64
+	isUnsigned    bool
+
+	mu sync.Mutex
+	// base is the max ID allocated by the server, it's only used by Base().
+	base int64
+	// local allocates the IDs from the storage when the service is unreachable, it's created on the first use.
+	local Allocator
+}
+
+func newCentralAllocator(store kv.Storage, dbID int64, tblInfo *model.TableInfo) *centralAllocator {
+	return &centralAllocator{
+		store:         store,
+		dbID:          dbID,
+		tableUpdateTS: tblInfo.UpdateTS,
+		isUnsigned:    tblInfo.IsAutoIncColUnsigned(),
+	}
+}
+
+func (alloc *centralAllocator) call(ctx context.Context, req *CentralRequest) (*CentralResponse, error) {
+	client := getCentralClient(alloc.store)
+	if client == nil {
+		return nil, errors.Annotate(ErrCentralUnreachable, "no client is registered")
+	}
+	req.DBID, req.TableUpdateTS, req.IsUnsigned = alloc.dbID, alloc.tableUpdateTS, alloc.isUnsigned
+	return client.Call(ctx, req)
+}
+
+// localAlloc returns the allocator which allocates the IDs from the storage one by one.
+func (alloc *centralAllocator) localAlloc() Allocator {
+	alloc.mu.Lock()
+	defer alloc.mu.Unlock()
+	if alloc.local == nil {
+		alloc.local = NewAllocator(alloc.store, alloc.dbID, alloc.isUnsigned, RowIDAllocType, CustomAutoIncCacheOption(1))
+	}
+	return alloc.local
+}
+
+func isCentralUnreachable(err error) bool {
+	return errors.Cause(err) == ErrCentralUnreachable
+}
+
+// Alloc implements autoid.Allocator Alloc interface.
+func (alloc *centralAllocator) Alloc(ctx context.Context, tableID int64, n uint64, increment, offset int64) (int64, int64, error) {
+	if tableID == 0 {
+		return 0, 0, errInvalidTableID.GenWithStackByArgs("Invalid tableID")
+	}
+	if n == 0 {
+		return 0, 0, nil
+	}
+	if !validIncrementAndOffset(increment, offset) {
+		return 0, 0, errInvalidIncrementAndOffset.GenWithStackByArgs(increment, offset)
+	}
+	resp, err := alloc.call(ctx, &CentralRequest{Op: CentralOpAlloc, TableID: tableID, N: n, Increment: increment, Offset: offset})
+	if isCentralUnreachable(err) {
+		logutil.Logger(ctx).Warn("allocate the auto IDs from the storage because the centralized allocator is unreachable",
+			zap.Int64("tableID", tableID), zap.Error(err))
+		resp = &CentralResponse{}
+		resp.Min, resp.Max, err = alloc.localAlloc().Alloc(ctx, tableID, n, increment, offset)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	alloc.mu.Lock()
+	alloc.base = resp.Max
+	alloc.mu.Unlock()
+	return resp.Min, resp.Max, nil
+}
+
+// AllocSeqCache implements autoid.Allocator AllocSeqCache interface.
+func (alloc *centralAllocator) AllocSeqCache(sequenceID int64) (int64, int64, int64, error) {
+	return 0, 0, 0, errors.New("unsupported")
+}
+
+// Rebase implements autoid.Allocator Rebase interface.
+func (alloc *centralAllocator) Rebase(tableID, newBase int64, allocIDs bool) error {
+	if tableID == 0 {
+		return errInvalidTableID.GenWithStack("Invalid tableID")
+	}
+	_, err := alloc.call(context.Background(), &CentralRequest{Op: CentralOpRebase, TableID: tableID, Base: newBase, AllocIDs: allocIDs})
+	if isCentralUnreachable(err) {
+		// The lease of the service is dropped by its next allocation once the storage is moved.
+		return alloc.localAlloc().Rebase(tableID, newBase, allocIDs)
+	}
+	return err
+}
+
+// RebaseSeq implements autoid.Allocator RebaseSeq interface.
+func (alloc *centralAllocator) RebaseSeq(tableID, newBase int64) (int64, bool, error) {
+	return 0, false, errors.New("unsupported")
+}
+
+// Base implements autoid.Allocator Base interface.
+func (alloc *centralAllocator) Base() int64 {
+	alloc.mu.Lock()
+	defer alloc.mu.Unlock()
+	return alloc.base
+}
+
+// End implements autoid.Allocator End interface.
+func (alloc *centralAllocator) End() int64 {
+	// It doesn't matter because nothing is cached by the server.
+	return 0
+}
+
+// NextGlobalAutoID implements autoid.Allocator NextGlobalAutoID interface.
+func (alloc *centralAllocator) NextGlobalAutoID(tableID int64) (int64, error) {
+	resp, err := alloc.call(context.Background(), &CentralRequest{Op: CentralOpNextGlobalID, TableID: tableID})
+	if isCentralUnreachable(err) {
+		return alloc.localAlloc().NextGlobalAutoID(tableID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return resp.Max, nil
+}
+
+// GetType implements autoid.Allocator GetType interface.
+func (alloc *centralAllocator) GetType() AllocatorType {
+	return RowIDAllocType
+}
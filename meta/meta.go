@@ -68,6 +68,9 @@ var (
 	mRandomIDPrefix   = "TARID"
 	mBootstrapKey     = []byte("BootstrapKey")
 	mSchemaDiffPrefix = "Diff"
+	// mCentralAutoIDTokenKey is the secret shared by the TiDB servers to authenticate the requests of the centralized
+	// auto ID allocator service.
+	mCentralAutoIDTokenKey = []byte("CentralAutoIDToken")
 )
 
 var (
@@ -627,6 +630,29 @@ func (m *Meta) DeQueueDDLJob() (*model.Job, error) {
 	return m.deQueueDDLJob(m.jobListKey)
 }
 
+// DeQueueDDLJobByIdx removes the DDL job by the index from the list.
+// The length of jobListKeys can only be 1 or 0.
+// If its length is 1, we need to replace m.jobListKey with jobListKeys[0].
+// Otherwise, we use m.jobListKey directly.
+func (m *Meta) DeQueueDDLJobByIdx(index int64, jobListKeys ...JobListKeyType) (*model.Job, error) {
+	listKey := m.jobListKey
+	if len(jobListKeys) != 0 {
+		listKey = jobListKeys[0]
+	}
+	if index == 0 {
+		return m.deQueueDDLJob(listKey)
+	}
+
+	value, err := m.txn.LRemoveAt(listKey, index)
+	if err != nil || value == nil {
+		return nil, errors.Trace(err)
+	}
+
+	job := &model.Job{}
+	err = job.Decode(value)
+	return job, errors.Trace(err)
+}
+
 func (m *Meta) getDDLJob(key []byte, index int64) (*model.Job, error) {
 	value, err := m.txn.LIndex(key, index)
 	if err != nil || value == nil {
@@ -912,6 +938,18 @@ func (m *Meta) FinishBootstrap(version int64) error {
 	return errors.Trace(err)
 }
 
+// GetCentralAutoIDToken returns the token of the centralized auto ID allocator service, it's nil if it's not set.
+func (m *Meta) GetCentralAutoIDToken() ([]byte, error) {
+	value, err := m.txn.Get(mCentralAutoIDTokenKey)
+	return value, errors.Trace(err)
+}
+
+// SetCentralAutoIDToken sets the token of the centralized auto ID allocator service.
+func (m *Meta) SetCentralAutoIDToken(token []byte) error {
+	err := m.txn.Set(mCentralAutoIDTokenKey, token)
+	return errors.Trace(err)
+}
+
 // ElementKeyType is a key type of the element.
 type ElementKeyType []byte
 
@@ -473,6 +473,25 @@ func (s *testSuite) TestDDL(c *C) {
 	expectJobs := []*model.Job{job, job1}
 	c.Assert(jobs, DeepEquals, expectJobs)
 
+	// Test DeQueueDDLJobByIdx.
+	job2 := &model.Job{ID: 3}
+	err = t.EnQueueDDLJob(job2)
+	c.Assert(err, IsNil)
+	v, err = t.DeQueueDDLJobByIdx(1)
+	c.Assert(err, IsNil)
+	c.Assert(v, DeepEquals, job1)
+	v, err = t.GetDDLJobByIdx(1)
+	c.Assert(err, IsNil)
+	c.Assert(v, DeepEquals, job2)
+	v, err = t.DeQueueDDLJobByIdx(1)
+	c.Assert(err, IsNil)
+	c.Assert(v, DeepEquals, job2)
+	n, err = t.DDLJobQueueLen()
+	c.Assert(err, IsNil)
+	c.Assert(n, Equals, int64(1))
+	err = t.EnQueueDDLJob(job1)
+	c.Assert(err, IsNil)
+
 	err = txn.Commit(context.Background())
 	c.Assert(err, IsNil)
 
@@ -40,4 +40,19 @@ var (
 			Name:      "bind_memory_usage",
 			Help:      "Memory usage of sql bind",
 		}, []string{LabelScope, LblType})
+
+	BindCacheCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "bindinfo",
+			Name:      "bind_cache_counter",
+			Help:      "Counter of the hits, misses and evictions of the global sql bind cache",
+		}, []string{LblType})
+)
+
+// Label constants of BindCacheCounter.
+const (
+	LblHit   = "hit"
+	LblMiss  = "miss"
+	LblEvict = "evict"
 )
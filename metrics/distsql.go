@@ -60,4 +60,11 @@ var (
 			Help:      "coprocessor cache hit, evict and miss number",
 			Buckets:   prometheus.ExponentialBuckets(1, 2, 16),
 		}, []string{LblType})
+	DistSQLCopEffectiveTimeoutGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "distsql",
+			Name:      "copr_effective_timeout_seconds",
+			Help:      "The adaptive timeout (s) of the coprocessor requests on each store.",
+		}, []string{LblStore, LblType})
 )
@@ -137,6 +137,15 @@ var (
 		},
 	)
 
+	ProbeConnCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "probe_connection_total",
+			Help:      "Counter of the connections of the probe user, which are served without sessions.",
+		},
+	)
+
 	GetTokenDurationHistogram = prometheus.NewHistogram(
 		prometheus.HistogramOpts{
 			Namespace: "tidb",
@@ -213,6 +222,14 @@ var (
 		},
 	)
 
+	AdmissionQueueGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "admission_queue_depth",
+			Help:      "The number of statements queued by the admission control because the CPU is saturated.",
+		}, []string{LblType})
+
 	ConfigStatus = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "tidb",
@@ -155,4 +155,7 @@ const (
 	LblVersion     = "version"
 	LblHash        = "hash"
 	LblCTEType     = "cte_type"
+	LblSQLDigest   = "sql_digest"
+	LblGood        = "good"
+	LblBad         = "bad"
 )
@@ -37,4 +37,24 @@ var (
 			Help:      "Bucketed histogram of transaction write throughput (bytes/second).",
 			Buckets:   prometheus.ExponentialBuckets(64, 1.3, 40), // 64 bytes/s ~ 2.3MB/s
 		})
+
+	// StmtSLOCounter counts the statements of each SQL digest that meet or violate the statement SLO. The digests
+	// beyond tidb_stmt_summary_max_stmt_count are counted as "other".
+	StmtSLOCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "sli",
+			Name:      "stmt_slo_total",
+			Help:      "Counter of statements of each SQL digest that meet (good) or violate (bad) the statement SLO.",
+		}, []string{LblSQLDigest, LblResult})
+
+	// StmtSLOObjective records the target ratio of the executions meeting the statement SLO, by which the error
+	// budget burn rate is computed from StmtSLOCounter.
+	StmtSLOObjective = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "sli",
+			Name:      "stmt_slo_objective",
+			Help:      "Target ratio of the executions meeting the statement SLO.",
+		})
 )
@@ -93,4 +93,21 @@ var (
 			Help:      "Bucketed histogram of some stats in fast analyze.",
 			Buckets:   prometheus.ExponentialBuckets(1, 2, 16),
 		}, []string{LblSQLType, LblType})
+
+	SyncLoadHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "tidb",
+			Subsystem: "statistics",
+			Name:      "sync_load_duration_seconds",
+			Help:      "Bucketed histogram of the waiting time (s) of loading the unloaded histograms synchronously.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 20), // 0.5ms ~ 262s
+		})
+
+	SyncLoadCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "statistics",
+			Name:      "sync_load_total",
+			Help:      "Counter of loading the unloaded histograms synchronously.",
+		}, []string{LblType})
 )
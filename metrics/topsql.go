@@ -29,4 +29,20 @@ var (
 			Help:      "Bucket histogram of reporting records/sql/plan count to the top-sql agent.",
 			Buckets:   prometheus.ExponentialBuckets(1, 2, 20), // 1 ~ 524288
 		}, []string{LblType})
+
+	TopSQLOverheadGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "topsql",
+			Name:      "overhead",
+			Help:      "The overhead of the top-sql profiler, cpu is the CPU percentage of one core and memory is the bytes of the profile data.",
+		}, []string{LblType})
+
+	TopSQLShedCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "topsql",
+			Name:      "shed_total",
+			Help:      "Counter of the top-sql collection pauses caused by exceeding the max overhead.",
+		})
 )
@@ -59,8 +59,18 @@ type Manager interface {
 	ResignOwner(ctx context.Context) error
 	// Cancel cancels this etcd ownerManager campaign.
 	Cancel()
+	// SetCampaignCheck sets the check of the other candidates campaigning the owner. The manager doesn't campaign while
+	// the check fails, and it resigns the owner if the check fails when it's the owner.
+	SetCampaignCheck(check CampaignCheck)
 }
 
+// CampaignCheck checks whether the manager can campaign the owner, candidates are the IDs of the other managers
+// campaigning the owner.
+type CampaignCheck func(ctx context.Context, candidates []string) bool
+
+// CampaignCheckInterval is the interval of the campaign check. It's exported for testing.
+var CampaignCheckInterval = 10 * time.Second
+
 const (
 	// NewSessionDefaultRetryCnt is the default retry times when create new session.
 	NewSessionDefaultRetryCnt = 3
@@ -87,6 +97,8 @@ type ownerManager struct {
 	cancel    context.CancelFunc
 	elec      unsafe.Pointer
 	wg        sync.WaitGroup
+	// campaignCheck is the CampaignCheck set by SetCampaignCheck.
+	campaignCheck atomic.Value
 }
 
 // NewOwnerManager creates a new Manager.
@@ -121,6 +133,34 @@ func (m *ownerManager) Cancel() {
 	m.wg.Wait()
 }
 
+// SetCampaignCheck implements Manager.SetCampaignCheck interface.
+func (m *ownerManager) SetCampaignCheck(check CampaignCheck) {
+	m.campaignCheck.Store(check)
+}
+
+// passCampaignCheck returns whether the manager passes the campaign check. It passes if the candidates can't be got,
+// so that the owner isn't lost because of the check.
+func (m *ownerManager) passCampaignCheck(ctx context.Context) bool {
+	check, ok := m.campaignCheck.Load().(CampaignCheck)
+	if !ok || check == nil {
+		return true
+	}
+	childCtx, cancel := context.WithTimeout(ctx, keyOpDefaultTimeout)
+	resp, err := m.etcdCli.Get(childCtx, m.key+"/", clientv3.WithPrefix())
+	cancel()
+	if err != nil {
+		logutil.Logger(m.logCtx).Warn("get the candidates of the owner failed", zap.Error(err))
+		return true
+	}
+	candidates := make([]string, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		if id := string(kv.Value); id != m.id {
+			candidates = append(candidates, id)
+		}
+	}
+	return check(ctx, candidates)
+}
+
 // ManagerSessionTTL is the etcd session's TTL in seconds. It's exported for testing.
 var ManagerSessionTTL = 60
 
@@ -270,6 +310,14 @@ func (m *ownerManager) campaignLoop(etcdSession *concurrency.Session) {
 			continue
 		}
 
+		if !m.passCampaignCheck(ctx) {
+			select {
+			case <-time.After(CampaignCheckInterval):
+			case <-ctx.Done():
+			}
+			continue
+		}
+
 		elec := concurrency.NewElection(etcdSession, m.key)
 		err = elec.Campaign(ctx, m.id)
 		if err != nil {
@@ -336,8 +384,17 @@ func (m *ownerManager) watchOwner(ctx context.Context, etcdSession *concurrency.
 	logCtx := logutil.WithKeyValue(context.Background(), "owner info", logPrefix)
 	logutil.BgLogger().Debug(logPrefix)
 	watchCh := m.etcdCli.Watch(ctx, key)
+	ticker := time.NewTicker(CampaignCheckInterval)
+	defer ticker.Stop()
 	for {
 		select {
+		case <-ticker.C:
+			if !m.passCampaignCheck(ctx) {
+				logutil.Logger(logCtx).Info("the campaign check fails, resign the owner")
+				if err := m.ResignOwner(ctx); err != nil {
+					logutil.Logger(logCtx).Warn("resign the owner failed", zap.Error(err))
+				}
+			}
 		case resp, ok := <-watchCh:
 			if !ok {
 				metrics.WatchOwnerCounter.WithLabelValues(m.prompt, metrics.WatcherClosed).Inc()
@@ -246,6 +246,68 @@ func TestCluster(t *testing.T) {
 	}
 }
 
+func TestCampaignCheck(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("integration.NewClusterV3 will create file contains a colon which is not allowed on Windows")
+	}
+	orignalInterval := owner.CampaignCheckInterval
+	owner.CampaignCheckInterval = 50 * time.Millisecond
+	defer func() {
+		owner.CampaignCheckInterval = orignalInterval
+	}()
+	clus := integration.NewClusterV3(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+	cli := clus.RandClient()
+
+	// m1 doesn't campaign while m2 is a candidate.
+	m1 := owner.NewOwnerManager(goctx.Background(), cli, "test", "m1", "/test/owner")
+	m1.SetCampaignCheck(func(ctx context.Context, candidates []string) bool {
+		for _, id := range candidates {
+			if id == "m2" {
+				return false
+			}
+		}
+		return true
+	})
+	defer m1.Cancel()
+	if err := m1.CampaignOwner(); err != nil {
+		t.Fatal(err)
+	}
+	if !waitOwner(m1, true) {
+		t.Fatal("m1 should be the owner")
+	}
+
+	// m1 resigns the owner after m2 campaigns, and doesn't campaign again.
+	m2 := owner.NewOwnerManager(goctx.Background(), cli, "test", "m2", "/test/owner")
+	defer m2.Cancel()
+	if err := m2.CampaignOwner(); err != nil {
+		t.Fatal(err)
+	}
+	if !waitOwner(m2, true) {
+		t.Fatal("m2 should be the owner")
+	}
+	time.Sleep(10 * owner.CampaignCheckInterval)
+	if m1.IsOwner() || !m2.IsOwner() {
+		t.Fatalf("m2 should still be the owner, m1 is owner: %v, m2 is owner: %v", m1.IsOwner(), m2.IsOwner())
+	}
+
+	// m1 campaigns again after m2 exits.
+	m2.Cancel()
+	if !waitOwner(m1, true) {
+		t.Fatal("m1 should be the owner")
+	}
+}
+
+func waitOwner(m owner.Manager, isOwner bool) bool {
+	for i := 0; i < 6000; i++ {
+		if m.IsOwner() == isOwner {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return false
+}
+
 func deleteLeader(cli *clientv3.Client, prefixKey string) error {
 	session, err := concurrency.NewSession(cli)
 	if err != nil {
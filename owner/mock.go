@@ -78,6 +78,9 @@ func (m *mockManager) CampaignOwner() error {
 	return nil
 }
 
+// SetCampaignCheck implements Manager.SetCampaignCheck interface, the mock manager doesn't check.
+func (m *mockManager) SetCampaignCheck(check CampaignCheck) {}
+
 // ResignOwner lets the owner start a new election.
 func (m *mockManager) ResignOwner(ctx context.Context) error {
 	if m.IsOwner() {
@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/kvcache"
+	"github.com/pingcap/tidb/util/timeutil"
 	atomic2 "go.uber.org/atomic"
 )
 
@@ -71,6 +72,7 @@ type pstmtPlanCacheKey struct {
 	schemaVersion        int64
 	sqlMode              mysql.SQLMode
 	timezoneOffset       int
+	tzVersion            uint64
 	isolationReadEngines map[kv.StoreType]struct{}
 	selectLimit          uint64
 
@@ -82,7 +84,7 @@ func (key *pstmtPlanCacheKey) Hash() []byte {
 	if len(key.hash) == 0 {
 		var (
 			dbBytes    = hack.Slice(key.database)
-			bufferSize = len(dbBytes) + 8*6 + 3*8
+			bufferSize = len(dbBytes) + 8*7 + 3*8
 		)
 		if key.hash == nil {
 			key.hash = make([]byte, 0, bufferSize)
@@ -93,6 +95,7 @@ func (key *pstmtPlanCacheKey) Hash() []byte {
 		key.hash = codec.EncodeInt(key.hash, key.schemaVersion)
 		key.hash = codec.EncodeInt(key.hash, int64(key.sqlMode))
 		key.hash = codec.EncodeInt(key.hash, int64(key.timezoneOffset))
+		key.hash = codec.EncodeUint(key.hash, key.tzVersion)
 		if _, ok := key.isolationReadEngines[kv.TiDB]; ok {
 			key.hash = append(key.hash, kv.TiDB.Name()...)
 		}
@@ -136,6 +139,7 @@ func NewPSTMTPlanCacheKey(sessionVars *variable.SessionVars, pstmtID uint32, sch
 		schemaVersion:        schemaVersion,
 		sqlMode:              sessionVars.SQLMode,
 		timezoneOffset:       timezoneOffset,
+		tzVersion:            timeutil.LocationVersion(),
 		isolationReadEngines: make(map[kv.StoreType]struct{}),
 		selectLimit:          sessionVars.SelectLimit,
 	}
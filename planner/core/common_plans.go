@@ -45,6 +45,7 @@ import (
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/texttree"
+	"github.com/pingcap/tidb/util/tracing"
 	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 )
@@ -879,6 +880,11 @@ type AnalyzeInfo struct {
 	TableID       statistics.AnalyzeTableID
 	Incremental   bool
 	StatsVersion  int
+	// ColumnBuckets is the bucket number of the column histograms specified by
+	// "ANALYZE TABLE ... UPDATE HISTOGRAM ON ... WITH n BUCKETS", which is keyed by the column ID.
+	ColumnBuckets map[int64]uint64
+	// AdaptiveBuckets indicates whether to select the bucket number of the other column histograms adaptively.
+	AdaptiveBuckets bool
 }
 
 // AnalyzeColumnsTask is used for analyze columns.
@@ -980,6 +986,14 @@ type SelectInto struct {
 	IntoOpt    *ast.SelectIntoOption
 }
 
+// ExplainFormatStatsTrace is the explain format which outputs the steps of the cardinality estimation,
+// including the statistics consulted, the formulas applied and the intermediate selectivities.
+const ExplainFormatStatsTrace = "stats_trace"
+
+// ExplainFormatIndexCandidates is the explain format which outputs the access paths of the tables and why each of
+// them is pruned, rejected or chosen by the optimizer.
+const ExplainFormatIndexCandidates = "index_candidates"
+
 // Explain represents a explain plan.
 type Explain struct {
 	baseSchemaProducer
@@ -993,6 +1007,11 @@ type Explain struct {
 	Rows           [][]string
 	ExplainRows    [][]string
 	explainedPlans map[int]bool
+	// StatsTrace is the cardinality estimation steps recorded when optimizing the statement in the stats_trace format.
+	StatsTrace []*tracing.StatsTraceRecord
+	// IndexCandidates is the decisions on the access paths recorded when optimizing the statement in the
+	// index_candidates format.
+	IndexCandidates []*tracing.IndexCandidateRecord
 
 	ctes []*PhysicalCTE
 }
@@ -1026,6 +1045,10 @@ func (e *Explain) prepareSchema() error {
 		fieldNames = []string{"dot contents"}
 	case format == ast.ExplainFormatHint:
 		fieldNames = []string{"hint"}
+	case format == ExplainFormatStatsTrace:
+		fieldNames = []string{"operator", "step", "object", "detail", "result"}
+	case format == ExplainFormatIndexCandidates:
+		fieldNames = []string{"table", "candidate", "required property", "access conditions", "match order", "single scan", "cost", "result", "reason"}
 	default:
 		return errors.Errorf("explain format '%s' is not supported now", e.Format)
 	}
@@ -1069,6 +1092,15 @@ func (e *Explain) RenderResult() error {
 		hints := GenHintsFromPhysicalPlan(e.TargetPlan)
 		hints = append(hints, hint.ExtractTableHintsFromStmtNode(e.ExecStmt, nil)...)
 		e.Rows = append(e.Rows, []string{hint.RestoreOptimizerHints(hints)})
+	case ExplainFormatStatsTrace:
+		for _, r := range e.StatsTrace {
+			e.Rows = append(e.Rows, []string{r.Operator, r.Step, r.Object, r.Detail, strconv.FormatFloat(r.Result, 'f', 4, 64)})
+		}
+	case ExplainFormatIndexCandidates:
+		for _, r := range e.IndexCandidates {
+			e.Rows = append(e.Rows, []string{r.Table, r.Candidate, r.RequiredProp, r.AccessConds, strconv.FormatBool(r.MatchOrder),
+				strconv.FormatBool(r.SingleScan), r.Cost, r.Result, r.Reason})
+		}
 	default:
 		return errors.Errorf("explain format '%s' is not supported now", e.Format)
 	}
@@ -17,6 +17,7 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"hash"
+	"strconv"
 	"sync"
 
 	"github.com/pingcap/failpoint"
@@ -34,12 +35,24 @@ var encoderPool = sync.Pool{
 type planEncoder struct {
 	buf          bytes.Buffer
 	encodedPlans map[int]bool
+	// redact indicates to encode the normalized operator info, in which the constants are masked.
+	redact bool
 
 	ctes []*PhysicalCTE
 }
 
 // EncodePlan is used to encodePlan the plan to the plan tree with compressing.
 func EncodePlan(p Plan) string {
+	return encodePlan(p, false)
+}
+
+// EncodeRedactedPlan is like EncodePlan, but the constants in the operator info are masked like in the normalized
+// plan. It's used when the constants of the query may be sensitive.
+func EncodeRedactedPlan(p Plan) string {
+	return encodePlan(p, true)
+}
+
+func encodePlan(p Plan, redact bool) string {
 	if explain, ok := p.(*Explain); ok {
 		p = explain.TargetPlan
 	}
@@ -54,6 +67,7 @@ func EncodePlan(p Plan) string {
 			selectPlan.statsInfo().RowCount = float64(val.(int))
 		})
 	}
+	pn.redact = redact
 	return pn.encodePlanTree(p)
 }
 
@@ -80,7 +94,7 @@ func (pn *planEncoder) encodeCTEPlan() {
 		if statsInfo := x.statsInfo(); statsInfo != nil {
 			rowCount = x.statsInfo().RowCount
 		}
-		plancodec.EncodePlanNode(0, x.CTE.IDForStorage, plancodec.TypeCTEDefinition, rowCount, taskTypeInfo, x.ExplainInfo(), actRows, analyzeInfo, memoryInfo, diskInfo, &pn.buf)
+		plancodec.EncodePlanNode(0, x.CTE.IDForStorage, plancodec.TypeCTEDefinition, rowCount, taskTypeInfo, pn.explainInfo(x), actRows, analyzeInfo, memoryInfo, diskInfo, &pn.buf)
 		pn.encodePlan(x.SeedPlan, true, kv.TiKV, 1)
 		if x.RecurPlan != nil {
 			pn.encodePlan(x.RecurPlan, true, kv.TiKV, 1)
@@ -96,7 +110,7 @@ func (pn *planEncoder) encodePlan(p Plan, isRoot bool, store kv.StoreType, depth
 	if statsInfo := p.statsInfo(); statsInfo != nil {
 		rowCount = p.statsInfo().RowCount
 	}
-	plancodec.EncodePlanNode(depth, p.ID(), p.TP(), rowCount, taskTypeInfo, p.ExplainInfo(), actRows, analyzeInfo, memoryInfo, diskInfo, &pn.buf)
+	plancodec.EncodePlanNode(depth, p.ID(), p.TP(), rowCount, taskTypeInfo, pn.explainInfo(p), actRows, analyzeInfo, memoryInfo, diskInfo, &pn.buf)
 	pn.encodedPlans[p.ID()] = true
 	depth++
 
@@ -134,6 +148,18 @@ func (pn *planEncoder) encodePlan(p Plan, isRoot bool, store kv.StoreType, depth
 	}
 }
 
+// explainInfo returns the operator info of the plan. If the plan is redacted, it's the normalized operator info, which
+// is empty for the plans that aren't physical.
+func (pn *planEncoder) explainInfo(p Plan) string {
+	if !pn.redact {
+		return p.ExplainInfo()
+	}
+	if physicalPlan, ok := p.(PhysicalPlan); ok {
+		return physicalPlan.ExplainNormalizedInfo()
+	}
+	return ""
+}
+
 var digesterPool = sync.Pool{
 	New: func() interface{} {
 		return &planDigester{
@@ -204,6 +230,44 @@ func (d *planDigester) normalizePlan(p PhysicalPlan, isRoot bool, store kv.Store
 	}
 }
 
+// NormalizedPlanNodeIDs returns the identities of the plan nodes keyed by their plan IDs. An identity is the type of
+// the node and its row in the normalized plan, e.g. "TableFullScan#3", so it's stable for the same plan digest while
+// the plan IDs are not.
+func NormalizedPlanNodeIDs(p Plan) map[int]string {
+	ids := make(map[int]string)
+	if selectPlan := getSelectPlan(p); selectPlan != nil {
+		normalizedPlanNodeIDs(selectPlan, ids)
+	}
+	return ids
+}
+
+// normalizedPlanNodeIDs walks the plan tree in the same order as planDigester.normalizePlan.
+func normalizedPlanNodeIDs(p PhysicalPlan, ids map[int]string) {
+	ids[p.ID()] = p.TP() + "#" + strconv.Itoa(len(ids))
+	for _, child := range p.Children() {
+		if _, ok := ids[child.ID()]; ok {
+			continue
+		}
+		normalizedPlanNodeIDs(child, ids)
+	}
+	switch x := p.(type) {
+	case *PhysicalTableReader:
+		normalizedPlanNodeIDs(x.tablePlan, ids)
+	case *PhysicalIndexReader:
+		normalizedPlanNodeIDs(x.indexPlan, ids)
+	case *PhysicalIndexLookUpReader:
+		normalizedPlanNodeIDs(x.indexPlan, ids)
+		normalizedPlanNodeIDs(x.tablePlan, ids)
+	case *PhysicalIndexMergeReader:
+		for _, p := range x.partialPlans {
+			normalizedPlanNodeIDs(p, ids)
+		}
+		if x.tablePlan != nil {
+			normalizedPlanNodeIDs(x.tablePlan, ids)
+		}
+	}
+}
+
 func getSelectPlan(p Plan) PhysicalPlan {
 	var selectPlan PhysicalPlan
 	if physicalPlan, ok := p.(PhysicalPlan); ok {
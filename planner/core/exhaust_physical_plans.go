@@ -204,7 +204,10 @@ func (p *LogicalJoin) GetMergeJoin(prop *property.PhysicalProperty, schema *expr
 			RightJoinKeys:   rightKeys,
 			IsNullEQ:        newIsNullEQ,
 		}
-		mergeJoin := PhysicalMergeJoin{basePhysicalJoin: baseJoin}.Init(p.ctx, statsInfo.ScaleByExpectCnt(prop.ExpectedCnt), p.blockOffset)
+		mergeJoin := PhysicalMergeJoin{
+			basePhysicalJoin: baseJoin,
+			Concurrency:      p.ctx.GetSessionVars().ParallelMergeJoinConcurrency,
+		}.Init(p.ctx, statsInfo.ScaleByExpectCnt(prop.ExpectedCnt), p.blockOffset)
 		mergeJoin.SetSchema(schema)
 		mergeJoin.OtherConditions = p.moveEqualToOtherConditions(offsets)
 		mergeJoin.initCompareFuncs()
@@ -339,7 +342,11 @@ func (p *LogicalJoin) getEnforcedMergeJoin(prop *property.PhysicalProperty, sche
 		IsNullEQ:        newNullEQ,
 		OtherConditions: otherConditions,
 	}
-	enforcedPhysicalMergeJoin := PhysicalMergeJoin{basePhysicalJoin: baseJoin, Desc: desc}.Init(p.ctx, statsInfo.ScaleByExpectCnt(prop.ExpectedCnt), p.blockOffset)
+	enforcedPhysicalMergeJoin := PhysicalMergeJoin{
+		basePhysicalJoin: baseJoin,
+		Desc:             desc,
+		Concurrency:      p.ctx.GetSessionVars().ParallelMergeJoinConcurrency,
+	}.Init(p.ctx, statsInfo.ScaleByExpectCnt(prop.ExpectedCnt), p.blockOffset)
 	enforcedPhysicalMergeJoin.SetSchema(schema)
 	enforcedPhysicalMergeJoin.childrenReqProps = []*property.PhysicalProperty{lProp, rProp}
 	enforcedPhysicalMergeJoin.initCompareFuncs()
@@ -367,8 +374,14 @@ func (p *LogicalJoin) getHashJoins(prop *property.PhysicalProperty) []PhysicalPl
 	}
 	joins := make([]PhysicalPlan, 0, 2)
 	switch p.JoinType {
-	case SemiJoin, AntiSemiJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin:
+	case SemiJoin, LeftOuterSemiJoin:
 		joins = append(joins, p.getHashJoin(prop, 1, false))
+	case AntiSemiJoin, AntiLeftOuterSemiJoin:
+		hashJoin := p.getHashJoin(prop, 1, false)
+		if p.ctx.GetSessionVars().EnableNullAwareAntiJoin {
+			p.tryToBuildNullAwareJoinKeys(hashJoin)
+		}
+		joins = append(joins, hashJoin)
 	case LeftOuterJoin:
 		if ForceUseOuterBuild4Test {
 			joins = append(joins, p.getHashJoin(prop, 1, true))
@@ -407,6 +420,52 @@ func (p *LogicalJoin) getHashJoin(prop *property.PhysicalProperty, innerIdx int,
 	return hashJoin
 }
 
+// tryToBuildNullAwareJoinKeys uses the column equal conditions converted from `not in (subq)` as the join keys of
+// the anti semi join, if there are no other equal conditions. These conditions have nullable operands and are kept
+// in OtherConditions, so the join would be a CARTESIAN one without them. The executor evaluates them with
+// the null semantics of `not in` when the hash join is marked as null-aware.
+func (p *LogicalJoin) tryToBuildNullAwareJoinKeys(hashJoin *PhysicalHashJoin) {
+	if len(p.EqualConditions) > 0 {
+		return
+	}
+	lSchema, rSchema := p.children[0].Schema(), p.children[1].Schema()
+	var (
+		naEQConds          []*expression.ScalarFunction
+		lKeys, rKeys       []*expression.Column
+		remainedOtherConds = make([]expression.Expression, 0, len(p.OtherConditions))
+	)
+	for _, cond := range p.OtherConditions {
+		if !expression.IsEQCondFromIn(cond) {
+			remainedOtherConds = append(remainedOtherConds, cond)
+			continue
+		}
+		sf := cond.(*expression.ScalarFunction)
+		lCol, lOK := sf.GetArgs()[0].(*expression.Column)
+		rCol, rOK := sf.GetArgs()[1].(*expression.Column)
+		if !lOK || !rOK {
+			return
+		}
+		if lSchema.Contains(rCol) && rSchema.Contains(lCol) {
+			lCol, rCol = rCol, lCol
+		}
+		if !lSchema.Contains(lCol) || !rSchema.Contains(rCol) {
+			return
+		}
+		newCond := expression.NewFunctionInternal(p.ctx, ast.EQ, sf.GetType(), lCol, rCol).(*expression.ScalarFunction)
+		naEQConds = append(naEQConds, newCond)
+		lKeys = append(lKeys, lCol)
+		rKeys = append(rKeys, rCol)
+	}
+	if len(naEQConds) == 0 {
+		return
+	}
+	hashJoin.IsNullAware = true
+	hashJoin.EqualConditions = naEQConds
+	hashJoin.LeftJoinKeys, hashJoin.RightJoinKeys = lKeys, rKeys
+	hashJoin.IsNullEQ = make([]bool, len(naEQConds))
+	hashJoin.OtherConditions = remainedOtherConds
+}
+
 // When inner plan is TableReader, the parameter `ranges` will be nil. Because pk only have one column. So all of its range
 // is generated during execution time.
 func (p *LogicalJoin) constructIndexJoin(
@@ -434,11 +493,16 @@ func (p *LogicalJoin) constructIndexJoin(
 	if hasNullEQ {
 		return nil
 	}
-	chReqProps := make([]*property.PhysicalProperty, 2)
-	chReqProps[outerIdx] = &property.PhysicalProperty{TaskTp: property.RootTaskType, ExpectedCnt: math.MaxFloat64, SortItems: prop.SortItems}
+	outerExpectedCnt := math.MaxFloat64
 	if prop.ExpectedCnt < p.stats.RowCount {
 		expCntScale := prop.ExpectedCnt / p.stats.RowCount
-		chReqProps[outerIdx].ExpectedCnt = p.children[outerIdx].statsInfo().RowCount * expCntScale
+		outerExpectedCnt = p.children[outerIdx].statsInfo().RowCount * expCntScale
+	}
+	outerProps := []*property.PhysicalProperty{{TaskTp: property.RootTaskType, ExpectedCnt: outerExpectedCnt, SortItems: prop.SortItems}}
+	// The outer child can also be executed by MPP on TiFlash, then the lookups of the inner child are still sent to
+	// TiKV. It avoids shuffling the whole inner table when the join keys are selective.
+	if prop.IsEmpty() && p.ctx.GetSessionVars().IsMPPAllowed() && canOuterChildBeMPP(p.children[outerIdx]) {
+		outerProps = append(outerProps, &property.PhysicalProperty{TaskTp: property.MppTaskType, ExpectedCnt: outerExpectedCnt, MPPPartitionTp: property.AnyType})
 	}
 	newInnerKeys := make([]*expression.Column, 0, len(innerJoinKeys))
 	newOuterKeys := make([]*expression.Column, 0, len(outerJoinKeys))
@@ -502,20 +566,62 @@ func (p *LogicalJoin) constructIndexJoin(
 		DefaultValues:   p.DefaultValues,
 	}
 
-	join := PhysicalIndexJoin{
-		basePhysicalJoin: baseJoin,
-		innerTask:        innerTask,
-		KeyOff2IdxOff:    newKeyOff,
-		Ranges:           ranges,
-		CompareFilters:   compareFilters,
-		OuterHashKeys:    outerHashKeys,
-		InnerHashKeys:    innerHashKeys,
-	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), p.blockOffset, chReqProps...)
-	if path != nil {
-		join.IdxColLens = path.IdxColLens
+	joins := make([]PhysicalPlan, 0, len(outerProps))
+	for _, outerProp := range outerProps {
+		chReqProps := make([]*property.PhysicalProperty, 2)
+		chReqProps[outerIdx] = outerProp
+		join := PhysicalIndexJoin{
+			basePhysicalJoin: baseJoin,
+			innerTask:        innerTask,
+			KeyOff2IdxOff:    newKeyOff,
+			Ranges:           ranges,
+			CompareFilters:   compareFilters,
+			OuterHashKeys:    outerHashKeys,
+			InnerHashKeys:    innerHashKeys,
+		}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), p.blockOffset, chReqProps...)
+		if path != nil {
+			join.IdxColLens = path.IdxColLens
+		}
+		join.SetSchema(p.schema)
+		joins = append(joins, join)
 	}
-	join.SetSchema(p.schema)
-	return []PhysicalPlan{join}
+	return joins
+}
+
+// canOuterChildBeMPP checks whether the outer child of an index join can be executed by MPP on TiFlash. Unlike
+// canPushToCop, it never raises a warning: the MPP joins have already checked the same children and warned the
+// user, so the outer child is silently kept in TiKV if any data source of it can't be read by MPP.
+func canOuterChildBeMPP(outerChild LogicalPlan) bool {
+	switch x := outerChild.(type) {
+	case *DataSource:
+		if x.isPartition {
+			return false
+		}
+		for _, col := range x.schema.Columns {
+			if col.VirtualExpr != nil {
+				return false
+			}
+		}
+		for _, path := range x.possibleAccessPaths {
+			if path.StoreType == kv.TiFlash {
+				return true
+			}
+		}
+		return false
+	case *LogicalAggregation:
+		if x.noCopPushDown {
+			return false
+		}
+	case *LogicalSelection, *LogicalProjection, *LogicalJoin:
+	default:
+		return false
+	}
+	for _, child := range outerChild.Children() {
+		if !canOuterChildBeMPP(child) {
+			return false
+		}
+	}
+	return true
 }
 
 func (p *LogicalJoin) constructIndexMergeJoin(
@@ -144,6 +144,15 @@ func (p *PhysicalIndexScan) OperatorInfo(normalized bool) string {
 	if p.Desc {
 		buffer.WriteString("desc, ")
 	}
+	if len(p.ByItems) > 0 {
+		buffer.WriteString("merge by:[")
+		if normalized {
+			explainNormalizedByItems(buffer, p.ByItems)
+		} else {
+			explainByItems(buffer, p.ByItems)
+		}
+		buffer.WriteString("], ")
+	}
 	if p.stats.StatsVersion == statistics.PseudoVersion && !normalized {
 		buffer.WriteString("stats:pseudo, ")
 	}
@@ -407,6 +416,9 @@ func (p *PhysicalIndexLookUpReader) accessObject(sctx sessionctx.Context) string
 
 // ExplainInfo implements Plan interface.
 func (p *PhysicalIndexMergeReader) ExplainInfo() string {
+	if p.IsIntersectionType {
+		return "type: intersection"
+	}
 	return ""
 }
 
@@ -593,6 +605,9 @@ func (p *PhysicalHashJoin) explainInfo(normalized bool) string {
 	if len(p.EqualConditions) == 0 {
 		buffer.WriteString("CARTESIAN ")
 	}
+	if p.IsNullAware {
+		buffer.WriteString("Null-aware ")
+	}
 
 	buffer.WriteString(p.JoinType.String())
 
@@ -1116,6 +1116,8 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 		}
 	case *ast.PositionExpr:
 		er.positionToScalarFunc(v)
+	case *ast.MatchAgainst:
+		er.matchAgainstToScalarFunc(v)
 	case *ast.IsNullExpr:
 		er.isNullToExpression(v)
 	case *ast.IsTruthExpr:
@@ -1375,6 +1377,38 @@ func (er *expressionRewriter) positionToScalarFunc(v *ast.PositionExpr) {
 	}
 }
 
+// matchAgainstToScalarFunc rewrites `MATCH (col1, col2, ...) AGAINST (expr [modifier])` to the scalar function
+// MatchAgainst(modifier, expr, col1, col2, ...).
+func (er *expressionRewriter) matchAgainstToScalarFunc(v *ast.MatchAgainst) {
+	if v.Modifier.WithQueryExpansion() {
+		er.err = ErrNotSupportedYet.GenWithStackByArgs("MATCH ... AGAINST with QUERY EXPANSION")
+		return
+	}
+	stkLen := len(er.ctxStack)
+	argsLen := len(v.ColumnNames) + 1
+	against := er.ctxStack[stkLen-1]
+	// The search string must be constant during the query evaluation.
+	if len(expression.ExtractColumns(against)) > 0 || len(expression.ExtractCorColumns(against)) > 0 {
+		er.err = ErrWrongArguments.GenWithStackByArgs("AGAINST")
+		return
+	}
+	args := make([]expression.Expression, 0, argsLen+1)
+	args = append(args, &expression.Constant{
+		Value:   types.NewIntDatum(int64(v.Modifier)),
+		RetType: types.NewFieldType(mysql.TypeLonglong),
+	}, against)
+	args = append(args, er.ctxStack[stkLen-argsLen:stkLen-1]...)
+	function, err := er.newFunction(expression.MatchAgainst, types.NewFieldType(mysql.TypeDouble), args...)
+	if err != nil {
+		er.err = err
+		return
+	}
+	er.ctxStackPop(argsLen)
+	er.ctxStackAppend(function, types.EmptyName)
+	// FULLTEXT indexes aren't supported, so warn the users that the search reads all the rows.
+	er.sctx.GetSessionVars().StmtCtx.AppendWarning(errors.New("MATCH ... AGAINST is evaluated without a FULLTEXT index, all the rows are scanned"))
+}
+
 func (er *expressionRewriter) isTrueToScalarFunc(v *ast.IsTruthExpr) {
 	stkLen := len(er.ctxStack)
 	op := ast.IsTruthWithoutNull
@@ -1419,6 +1453,9 @@ func (er *expressionRewriter) inToExpression(lLen int, not bool, tp *types.Field
 				if isExceptional {
 					args[i] = c
 				}
+				if leftFt.Tp == mysql.TypeYear {
+					args[i] = adjustYearConstant(args[i].(*expression.Constant))
+				}
 			}
 		}
 	}
@@ -1456,6 +1493,19 @@ func (er *expressionRewriter) inToExpression(lLen int, not bool, tp *types.Field
 	er.ctxStackAppend(function, types.EmptyName)
 }
 
+// adjustYearConstant adjusts the int constant compared with a YEAR column to the YEAR value in the same way as
+// `year_col = int_const` does, e.g. 21 is adjusted to 2021 while 0 is kept as 0000.
+func adjustYearConstant(c *expression.Constant) *expression.Constant {
+	if c.GetType().EvalType() != types.ETInt || c.Value.IsNull() {
+		return c
+	}
+	adjusted, err := types.AdjustYear(c.Value.GetInt64(), false)
+	if err != nil || adjusted == c.Value.GetInt64() {
+		return c
+	}
+	return &expression.Constant{Value: types.NewIntDatum(adjusted), RetType: c.RetType}
+}
+
 func (er *expressionRewriter) caseToExpression(v *ast.CaseExpr) {
 	stkLen := len(er.ctxStack)
 	argsLen := 2 * len(v.WhenClauses)
@@ -1580,6 +1630,11 @@ func (er *expressionRewriter) rowToScalarFunc(v *ast.RowExpr) {
 func (er *expressionRewriter) wrapExpWithCast() (expr, lexp, rexp expression.Expression) {
 	stkLen := len(er.ctxStack)
 	expr, lexp, rexp = er.ctxStack[stkLen-3], er.ctxStack[stkLen-2], er.ctxStack[stkLen-1]
+	if expr.GetType().Tp == mysql.TypeYear && isNumericOrStringConstant(lexp) && isNumericOrStringConstant(rexp) {
+		// Leave the YEAR column compared with the constants to the compare functions, which refine the
+		// constants to the YEAR values like `year_col >= const` does, e.g. '21' is compared as 2021.
+		return
+	}
 	var castFunc func(sessionctx.Context, expression.Expression) expression.Expression
 	switch expression.ResolveType4Between([3]expression.Expression{expr, lexp, rexp}) {
 	case types.ETInt:
@@ -1616,6 +1671,18 @@ func (er *expressionRewriter) wrapExpWithCast() (expr, lexp, rexp expression.Exp
 	return
 }
 
+func isNumericOrStringConstant(e expression.Expression) bool {
+	c, ok := e.(*expression.Constant)
+	if !ok {
+		return false
+	}
+	switch c.GetType().EvalType() {
+	case types.ETInt, types.ETReal, types.ETDecimal, types.ETString:
+		return true
+	}
+	return false
+}
+
 func (er *expressionRewriter) betweenToExpression(v *ast.BetweenExpr) {
 	stkLen := len(er.ctxStack)
 	er.err = expression.CheckArgsNotMultiColumnRow(er.ctxStack[stkLen-3:]...)
@@ -15,7 +15,10 @@ package core
 
 import (
 	"context"
+	"fmt"
 	"math"
+	"strconv"
+	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/ast"
@@ -34,6 +37,7 @@ import (
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/set"
+	"github.com/pingcap/tidb/util/tracing"
 	"go.uber.org/zap"
 	"golang.org/x/tools/container/intsets"
 )
@@ -287,6 +291,9 @@ func (p *baseLogicalPlan) findBestTask(prop *property.PhysicalProperty, planCoun
 	if prop == nil {
 		return nil, 1, nil
 	}
+	if tracer := p.ctx.GetSessionVars().StmtCtx.StatsTrace; tracer != nil {
+		defer tracer.EnterOperator(p.self.ExplainID().String())()
+	}
 	// Look up the task with this prop in the task map.
 	// It's used to reduce double counting.
 	bestTask = p.getTask(prop)
@@ -419,8 +426,14 @@ type candidatePath struct {
 	columnSet    *intsets.Sparse // columnSet is the set of columns that occurred in the access conditions.
 	isSingleScan bool
 	isMatchProp  bool
+	// mergePrefixLen is the number of the leading index columns whose values are listed by IN, the ranges of each
+	// value list are scanned separately and merged to match the required order when it's greater than 0.
+	mergePrefixLen int
 }
 
+// maxMergedRangeGroups is the max number of the range groups scanned separately and merged to match the required order.
+const maxMergedRangeGroups = 64
+
 // compareColumnSet will compares the two set. The last return value is used to indicate
 // if they are comparable, it is false when both two sets have columns that do not occur in the other.
 // When the second return value is true, the value of first:
@@ -513,8 +526,16 @@ func (ds *DataSource) getIndexCandidate(path *util.AccessPath, prop *property.Ph
 		for i, col := range path.IdxCols {
 			if col.Equal(nil, prop.SortItems[0].Col) {
 				candidate.isMatchProp = matchIndicesProp(path.IdxCols[i:], path.IdxColLens[i:], prop.SortItems)
+				// The leading columns listed by IN break the order, e.g. `a in (1, 2) order by b` on index (a, b),
+				// but the rows of each value list are still in order and can be merged.
+				if candidate.isMatchProp && i > path.EqCondCount {
+					candidate.isMatchProp = isSingleScan && ds.canMergeRangeGroups(path, i)
+					if candidate.isMatchProp {
+						candidate.mergePrefixLen = i
+					}
+				}
 				break
-			} else if i >= path.EqCondCount {
+			} else if i >= path.EqOrInCondCount {
 				break
 			}
 		}
@@ -524,6 +545,18 @@ func (ds *DataSource) getIndexCandidate(path *util.AccessPath, prop *property.Ph
 	return candidate
 }
 
+// canMergeRangeGroups checks whether the ranges of the path can be grouped by the values of the first prefixLen index
+// columns, and be scanned separately and merged by the IndexReader.
+func (ds *DataSource) canMergeRangeGroups(path *util.AccessPath, prefixLen int) bool {
+	sc := ds.ctx.GetSessionVars().StmtCtx
+	// The rows read by UnionScan are merged in the order of the index.
+	if ds.tableInfo.GetPartitionInfo() != nil || sc.TblInfo2UnionScan[ds.tableInfo] {
+		return false
+	}
+	groups, err := ranger.GroupRangesByPrefix(sc, path.Ranges, prefixLen)
+	return err == nil && len(groups) <= maxMergedRangeGroups
+}
+
 func (ds *DataSource) getIndexMergeCandidate(path *util.AccessPath) *candidatePath {
 	candidate := &candidatePath{path: path}
 	return candidate
@@ -572,6 +605,8 @@ func (ds *DataSource) skylinePruning(prop *property.PhysicalProperty) []*candida
 				// 4. The needed columns are all covered by index columns(and handleCol).
 				currentCandidate = ds.getIndexCandidate(path, prop, coveredByIdx)
 			} else {
+				ds.traceIndexCandidate(prop, ds.getIndexCandidate(path, prop, coveredByIdx), "", tracing.IndexCandidatePruned,
+					"no access conditions, no order to match and not a covering index")
 				continue
 			}
 		}
@@ -583,9 +618,11 @@ func (ds *DataSource) skylinePruning(prop *property.PhysicalProperty) []*candida
 			result := compareCandidates(candidates[i], currentCandidate)
 			if result == 1 {
 				pruned = true
+				ds.traceIndexCandidate(prop, currentCandidate, "", tracing.IndexCandidatePruned, skylineReason(candidates[i], currentCandidate))
 				// We can break here because the current candidate cannot prune others anymore.
 				break
 			} else if result == -1 {
+				ds.traceIndexCandidate(prop, candidates[i], "", tracing.IndexCandidatePruned, skylineReason(currentCandidate, candidates[i]))
 				candidates = append(candidates[:i], candidates[i+1:]...)
 			}
 		}
@@ -599,6 +636,7 @@ func (ds *DataSource) skylinePruning(prop *property.PhysicalProperty) []*candida
 		for i, c := range candidates {
 			for _, ran := range c.path.Ranges {
 				if ran.IsFullRange() {
+					ds.traceIndexCandidate(prop, c, "", tracing.IndexCandidatePruned, "full range scan is avoided by tidb_opt_prefer_range_scan")
 					candidates = append(candidates[:i], candidates[i+1:]...)
 					return candidates
 				}
@@ -609,6 +647,117 @@ func (ds *DataSource) skylinePruning(prop *property.PhysicalProperty) []*candida
 	return candidates
 }
 
+// accessPathName returns the name of the access path shown in the index_candidates explain format.
+func accessPathName(path *util.AccessPath) string {
+	switch {
+	case path.PartialIndexPaths != nil:
+		names := make([]string, 0, len(path.PartialIndexPaths))
+		for _, partPath := range path.PartialIndexPaths {
+			names = append(names, accessPathName(partPath))
+		}
+		if path.IndexMergeIsIntersection {
+			return "index merge intersection(" + strings.Join(names, ", ") + ")"
+		}
+		return "index merge(" + strings.Join(names, ", ") + ")"
+	case path.IsTablePath():
+		if path.StoreType == kv.TiFlash {
+			return "table scan(tiflash)"
+		}
+		return "table scan"
+	default:
+		return path.Index.Name.O
+	}
+}
+
+// skylineReason describes the factors that `better` is better than `worse` on in the skyline pruning.
+func skylineReason(better, worse *candidatePath) string {
+	factors := make([]string, 0, 3)
+	if setsResult, _ := compareColumnSet(better.columnSet, worse.columnSet); setsResult > 0 {
+		factors = append(factors, "more access condition columns")
+	}
+	if compareBool(better.isSingleScan, worse.isSingleScan) > 0 {
+		factors = append(factors, "no double read")
+	}
+	if compareBool(better.isMatchProp, worse.isMatchProp) > 0 {
+		factors = append(factors, "matches the required order")
+	}
+	return fmt.Sprintf("dominated by %s: %s", accessPathName(better.path), strings.Join(factors, ", "))
+}
+
+// traceIndexCandidate records the decision on the candidate for the index_candidates explain format.
+func (ds *DataSource) traceIndexCandidate(prop *property.PhysicalProperty, candidate *candidatePath, cost, result, reason string) {
+	tracer := ds.ctx.GetSessionVars().StmtCtx.IndexCandidatesTrace
+	if tracer == nil {
+		return
+	}
+	table := ds.tableInfo.Name.O
+	if ds.TableAsName != nil && ds.TableAsName.L != "" {
+		table = ds.TableAsName.O
+	}
+	// The prop is nil if the path is pruned before finding the best task.
+	var requiredProp string
+	if prop != nil {
+		requiredProp = prop.TaskTp.String()
+	}
+	if prop != nil && len(prop.SortItems) > 0 {
+		items := make([]string, 0, len(prop.SortItems))
+		for _, item := range prop.SortItems {
+			if item.Desc {
+				items = append(items, item.Col.String()+" desc")
+			} else {
+				items = append(items, item.Col.String())
+			}
+		}
+		requiredProp += ", order by " + strings.Join(items, ", ")
+	}
+	tracer.Record(tracing.IndexCandidateRecord{
+		Table:        table,
+		Candidate:    accessPathName(candidate.path),
+		RequiredProp: requiredProp,
+		AccessConds:  string(expression.SortedExplainExpressionList(candidate.path.AccessConds)),
+		MatchOrder:   candidate.isMatchProp,
+		SingleScan:   candidate.isSingleScan,
+		Cost:         cost,
+		Result:       result,
+		Reason:       reason,
+	})
+}
+
+// candidateTasks records the tasks built for the candidates when the index_candidates trace is enabled, it's nil
+// otherwise.
+type candidateTasks map[*candidatePath]task
+
+func (ct candidateTasks) set(candidate *candidatePath, t task) {
+	if ct != nil {
+		ct[candidate] = t
+	}
+}
+
+// traceCandidateTasks records the costs of the candidates, the candidate whose task is `best` is chosen.
+func (ds *DataSource) traceCandidateTasks(prop *property.PhysicalProperty, candidates []*candidatePath, tasks candidateTasks, best task) {
+	var bestName string
+	for _, candidate := range candidates {
+		if t, ok := tasks[candidate]; ok && t == best && !best.invalid() {
+			bestName = accessPathName(candidate.path)
+		}
+	}
+	for _, candidate := range candidates {
+		t, ok := tasks[candidate]
+		if !ok {
+			continue
+		}
+		switch {
+		case t.invalid():
+			ds.traceIndexCandidate(prop, candidate, "", tracing.IndexCandidateRejected, "can't satisfy the required property")
+		case t == best:
+			ds.traceIndexCandidate(prop, candidate, strconv.FormatFloat(t.cost(), 'f', 2, 64), tracing.IndexCandidateChosen, "")
+		default:
+			ds.traceIndexCandidate(prop, candidate, strconv.FormatFloat(t.cost(), 'f', 2, 64), tracing.IndexCandidateRejected,
+				fmt.Sprintf("the cost is higher than %s", bestName))
+		}
+	}
+}
+
 // findBestTask implements the PhysicalPlan interface.
 // It will enumerate all the available indices and choose a plan with least cost.
 func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter *PlanCounterTp) (t task, cntPlan int64, err error) {
@@ -618,6 +767,9 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 		planCounter.Dec(1)
 		return nil, 1, nil
 	}
+	if tracer := ds.ctx.GetSessionVars().StmtCtx.StatsTrace; tracer != nil {
+		defer tracer.EnterOperator(ds.ExplainID().String())()
+	}
 
 	t = ds.getTask(prop)
 	if t != nil {
@@ -674,6 +826,15 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 
 	t = invalidTask
 	candidates := ds.skylinePruning(prop)
+	var tasks candidateTasks
+	if ds.ctx.GetSessionVars().StmtCtx.IndexCandidatesTrace != nil {
+		tasks = make(candidateTasks, len(candidates))
+		defer func() {
+			if err == nil {
+				ds.traceCandidateTasks(prop, candidates, tasks, t)
+			}
+		}()
+	}
 
 	cntPlan = 0
 	for _, candidate := range candidates {
@@ -683,6 +844,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 			if err != nil {
 				return nil, 0, err
 			}
+			tasks.set(candidate, idxMergeTask)
 			if !idxMergeTask.invalid() {
 				cntPlan += 1
 				planCounter.Dec(1)
@@ -717,6 +879,16 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 					break
 				}
 			}
+			// The constants compared with the BIT columns may be converted from strings, e.g. `bit_col in ('0')`,
+			// which the fast plan refuses to convert to point get. They can't be told apart here, so we do not
+			// build [batch] point get on the BIT columns at all.
+			tblCols := ds.table.Meta().Columns
+			for _, idxCol := range path.Index.Columns {
+				if tblCols[idxCol.Offset].Tp == mysql.TypeBit {
+					canConvertPointGet = false
+					break
+				}
+			}
 		}
 		var hashPartColName *ast.ColumnName
 		if tblInfo := ds.table.Meta(); canConvertPointGet && tblInfo.GetPartitionInfo() != nil {
@@ -760,6 +932,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 				} else {
 					pointGetTask = ds.convertToBatchPointGet(prop, candidate, hashPartColName)
 				}
+				tasks.set(candidate, pointGetTask)
 				if !pointGetTask.invalid() {
 					cntPlan += 1
 					planCounter.Dec(1)
@@ -775,9 +948,11 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 		}
 		if path.IsTablePath() {
 			if ds.preferStoreType&preferTiFlash != 0 && path.StoreType == kv.TiKV {
+				ds.traceIndexCandidate(prop, candidate, "", tracing.IndexCandidatePruned, "TiKV is ignored by the read_from_storage hint")
 				continue
 			}
 			if ds.preferStoreType&preferTiKV != 0 && path.StoreType == kv.TiFlash {
+				ds.traceIndexCandidate(prop, candidate, "", tracing.IndexCandidatePruned, "TiFlash is ignored by the read_from_storage hint")
 				continue
 			}
 			var tblTask task
@@ -789,6 +964,7 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 			if err != nil {
 				return nil, 0, err
 			}
+			tasks.set(candidate, tblTask)
 			if !tblTask.invalid() {
 				cntPlan += 1
 				planCounter.Dec(1)
@@ -803,12 +979,14 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty, planCounter
 		}
 		// TiFlash storage do not support index scan.
 		if ds.preferStoreType&preferTiFlash != 0 {
+			ds.traceIndexCandidate(prop, candidate, "", tracing.IndexCandidatePruned, "TiFlash doesn't support index scan")
 			continue
 		}
 		idxTask, err := ds.convertToIndexScan(prop, candidate)
 		if err != nil {
 			return nil, 0, err
 		}
+		tasks.set(candidate, idxTask)
 		if !idxTask.invalid() {
 			cntPlan += 1
 			planCounter.Dec(1)
@@ -863,6 +1041,7 @@ func (ds *DataSource) convertToIndexMergeScan(prop *property.PhysicalProperty, c
 	totalCost += partialCost
 	cop.tablePlan = ts
 	cop.idxMergePartPlans = scans
+	cop.idxMergeIsIntersection = path.IndexMergeIsIntersection
 	cop.cst = totalCost
 	task = cop.convertToRootTask(ds.ctx)
 	return task, nil
@@ -1080,6 +1259,13 @@ func (ds *DataSource) convertToIndexScan(prop *property.PhysicalProperty, candid
 	}
 	path := candidate.path
 	is, cost, _ := ds.getOriginalPhysicalIndexScan(prop, path, candidate.isMatchProp, candidate.isSingleScan)
+	if candidate.mergePrefixLen > 0 {
+		is.MergePrefixLen = candidate.mergePrefixLen
+		is.ByItems = make([]*util.ByItems, 0, len(prop.SortItems))
+		for _, item := range prop.SortItems {
+			is.ByItems = append(is.ByItems, &util.ByItems{Expr: item.Col, Desc: item.Desc})
+		}
+	}
 	cop := &copTask{
 		indexPlan:   is,
 		tblColHists: ds.TblColHists,
@@ -1737,7 +1923,8 @@ func (ds *DataSource) convertToPointGet(prop *property.PhysicalProperty, candida
 }
 
 func (ds *DataSource) convertToBatchPointGet(prop *property.PhysicalProperty, candidate *candidatePath, hashPartColName *ast.ColumnName) task {
-	if !prop.IsEmpty() && !candidate.isMatchProp {
+	// BatchPointGet keeps the order of the index, the ranges can't be merged.
+	if !prop.IsEmpty() && (!candidate.isMatchProp || candidate.mergePrefixLen > 0) {
 		return invalidTask
 	}
 	if prop.TaskTp == property.CopDoubleReadTaskType && candidate.isSingleScan ||
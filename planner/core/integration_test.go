@@ -554,6 +554,59 @@ func (s *testIntegrationSerialSuite) TestMPPJoin(c *C) {
 	}
 }
 
+func (s *testIntegrationSerialSuite) TestIndexJoinWithMPPOuterChild(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists fact_t, dim_t, d2_t")
+	tk.MustExec("create table fact_t(id int, dim_k int, d2_k int, col int)")
+	tk.MustExec("create table dim_t(dim_k int primary key, value int)")
+	tk.MustExec("create table d2_t(d2_k int, value int)")
+
+	// Create virtual tiflash replica info, dim_t is only in TiKV.
+	dom := domain.GetDomain(tk.Se)
+	is := dom.InfoSchema()
+	db, exists := is.SchemaByName(model.NewCIStr("test"))
+	c.Assert(exists, IsTrue)
+	for _, tblInfo := range db.Tables {
+		if tblInfo.Name.L == "fact_t" || tblInfo.Name.L == "d2_t" {
+			tblInfo.TiFlashReplica = &model.TiFlashReplicaInfo{
+				Count:     1,
+				Available: true,
+			}
+		}
+	}
+
+	tk.MustExec("set @@session.tidb_isolation_read_engines = 'tiflash,tikv'")
+	tk.MustExec("set @@session.tidb_allow_mpp = 1")
+	// The selective outer child is executed by MPP, and the inner child is looked up in TiKV.
+	tk.MustQuery("explain format = 'brief' select count(*) from fact_t join dim_t on fact_t.dim_k = dim_t.dim_k where fact_t.col = 1").Check(testkit.Rows(
+		"StreamAgg 1.00 root  funcs:count(1)->Column#8",
+		"└─IndexJoin 12.49 root  inner join, inner:TableReader, outer key:test.fact_t.dim_k, inner key:test.dim_t.dim_k, equal cond:eq(test.fact_t.dim_k, test.dim_t.dim_k)",
+		"  ├─TableReader(Build) 9.99 root  data:ExchangeSender",
+		"  │ └─ExchangeSender 9.99 cop[tiflash]  ExchangeType: PassThrough",
+		"  │   └─Selection 9.99 cop[tiflash]  eq(test.fact_t.col, 1), not(isnull(test.fact_t.dim_k))",
+		"  │     └─TableFullScan 10000.00 cop[tiflash] table:fact_t keep order:false, stats:pseudo",
+		"  └─TableReader(Probe) 1.00 root  data:TableRangeScan",
+		"    └─TableRangeScan 1.00 cop[tikv] table:dim_t range: decided by [test.fact_t.dim_k], keep order:false, stats:pseudo",
+	))
+	// The outer child can be a join executed by MPP.
+	tk.MustQuery("explain format = 'brief' select /*+ inl_join(dim_t) */ count(*) from fact_t join d2_t on fact_t.d2_k = d2_t.d2_k join dim_t on fact_t.dim_k = dim_t.dim_k").Check(testkit.Rows(
+		"HashAgg 1.00 root  funcs:count(1)->Column#11",
+		"└─IndexJoin 15593.77 root  inner join, inner:TableReader, outer key:test.fact_t.dim_k, inner key:test.dim_t.dim_k, equal cond:eq(test.fact_t.dim_k, test.dim_t.dim_k)",
+		"  ├─TableReader(Build) 12475.01 root  data:ExchangeSender",
+		"  │ └─ExchangeSender 12475.01 cop[tiflash]  ExchangeType: PassThrough",
+		"  │   └─HashJoin 12475.01 cop[tiflash]  inner join, equal:[eq(test.fact_t.d2_k, test.d2_t.d2_k)]",
+		"  │     ├─ExchangeReceiver(Build) 9980.01 cop[tiflash]  ",
+		"  │     │ └─ExchangeSender 9980.01 cop[tiflash]  ExchangeType: Broadcast",
+		"  │     │   └─Selection 9980.01 cop[tiflash]  not(isnull(test.fact_t.d2_k)), not(isnull(test.fact_t.dim_k))",
+		"  │     │     └─TableFullScan 10000.00 cop[tiflash] table:fact_t keep order:false, stats:pseudo",
+		"  │     └─Selection(Probe) 9990.00 cop[tiflash]  not(isnull(test.d2_t.d2_k))",
+		"  │       └─TableFullScan 10000.00 cop[tiflash] table:d2_t keep order:false, stats:pseudo",
+		"  └─TableReader(Probe) 1.00 root  data:TableRangeScan",
+		"    └─TableRangeScan 1.00 cop[tikv] table:dim_t range: decided by [test.fact_t.dim_k], keep order:false, stats:pseudo",
+	))
+}
+
 func (s *testIntegrationSerialSuite) TestMPPOuterJoinBuildSideForBroadcastJoin(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -1599,6 +1652,79 @@ func (s *testIntegrationSuite) TestApproxCountDistinctInPartitionTable(c *C) {
 	tk.MustQuery("select approx_count_distinct(a), b from t group by b order by b desc").Check(testkit.Rows("1 2", "3 1"))
 }
 
+func (s *testIntegrationSuite) TestAggPushDownAcrossJoinByCost(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int)")
+	tk.MustExec("create table t2(a int, b int)")
+	// t1.a has 5 distinct values and t2.a has 100 distinct values.
+	vals1 := make([]string, 0, 100)
+	vals2 := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		vals1 = append(vals1, fmt.Sprintf("(%d, %d)", i%5, i))
+		vals2 = append(vals2, fmt.Sprintf("(%d, %d)", i, i))
+	}
+	tk.MustExec("insert into t1 values " + strings.Join(vals1, ", "))
+	tk.MustExec("insert into t2 values " + strings.Join(vals2, ", "))
+	tk.MustExec("analyze table t1, t2")
+	tk.MustExec("set session tidb_opt_agg_push_down = 1")
+
+	// The aggregation is pushed down to t1 since it shrinks the rows of t1.
+	tk.MustQuery("explain format = 'brief' select t1.a, sum(t1.b) from t1 join t2 on t1.a = t2.a group by t1.a").Check(testkit.Rows(
+		"Projection 5.00 root  test.t1.a, Column#7",
+		"└─HashAgg 5.00 root  group by:test.t1.a, funcs:sum(Column#8)->Column#7, funcs:firstrow(Column#9)->test.t1.a",
+		"  └─HashJoin 5.00 root  inner join, equal:[eq(test.t2.a, test.t1.a)]",
+		"    ├─HashAgg(Build) 5.00 root  group by:test.t1.a, funcs:sum(Column#10)->Column#8, funcs:firstrow(test.t1.a)->Column#9, funcs:firstrow(test.t1.a)->test.t1.a",
+		"    │ └─TableReader 5.00 root  data:HashAgg",
+		"    │   └─HashAgg 5.00 cop[tikv]  group by:test.t1.a, funcs:sum(test.t1.b)->Column#10",
+		"    │     └─Selection 100.00 cop[tikv]  not(isnull(test.t1.a))",
+		"    │       └─TableFullScan 100.00 cop[tikv] table:t1 keep order:false",
+		"    └─TableReader(Probe) 100.00 root  data:Selection",
+		"      └─Selection 100.00 cop[tikv]  not(isnull(test.t2.a))",
+		"        └─TableFullScan 100.00 cop[tikv] table:t2 keep order:false"))
+	// The aggregation is not pushed down to t2 since it hardly shrinks the rows of t2.
+	tk.MustQuery("explain format = 'brief' select t2.a, sum(t2.b) from t1 join t2 on t1.a = t2.a group by t2.a").Check(testkit.Rows(
+		"Projection 100.00 root  test.t2.a, Column#7",
+		"└─HashAgg 100.00 root  group by:Column#10, funcs:sum(Column#8)->Column#7, funcs:firstrow(Column#9)->test.t2.a",
+		"  └─Projection 100.00 root  cast(test.t2.b, decimal(32,0) BINARY)->Column#8, test.t2.a, test.t2.a",
+		"    └─HashJoin 100.00 root  inner join, equal:[eq(test.t1.a, test.t2.a)]",
+		"      ├─TableReader(Build) 100.00 root  data:Selection",
+		"      │ └─Selection 100.00 cop[tikv]  not(isnull(test.t2.a))",
+		"      │   └─TableFullScan 100.00 cop[tikv] table:t2 keep order:false",
+		"      └─TableReader(Probe) 100.00 root  data:Selection",
+		"        └─Selection 100.00 cop[tikv]  not(isnull(test.t1.a))",
+		"          └─TableFullScan 100.00 cop[tikv] table:t1 keep order:false"))
+	// tidb_opt_force_agg_push_down forces the aggregation to be pushed down.
+	tk.MustQuery("explain format = 'brief' select /*+ SET_VAR(tidb_opt_force_agg_push_down=1) */ t2.a, sum(t2.b) from t1 join t2 on t1.a = t2.a group by t2.a").Check(testkit.Rows(
+		"Projection 100.00 root  test.t2.a, Column#7",
+		"└─HashAgg 100.00 root  group by:test.t2.a, funcs:sum(Column#8)->Column#7, funcs:firstrow(Column#9)->test.t2.a",
+		"  └─HashJoin 100.00 root  inner join, equal:[eq(test.t1.a, test.t2.a)]",
+		"    ├─HashAgg(Build) 100.00 root  group by:Column#16, funcs:sum(Column#13)->Column#8, funcs:firstrow(Column#14)->Column#9, funcs:firstrow(Column#15)->test.t2.a",
+		"    │ └─Projection 100.00 root  cast(test.t2.b, decimal(32,0) BINARY)->Column#13, test.t2.a, test.t2.a, test.t2.a",
+		"    │   └─TableReader 100.00 root  data:Selection",
+		"    │     └─Selection 100.00 cop[tikv]  not(isnull(test.t2.a))",
+		"    │       └─TableFullScan 100.00 cop[tikv] table:t2 keep order:false",
+		"    └─TableReader(Probe) 100.00 root  data:Selection",
+		"      └─Selection 100.00 cop[tikv]  not(isnull(test.t1.a))",
+		"        └─TableFullScan 100.00 cop[tikv] table:t1 keep order:false"))
+	// The aggregation push down can be disabled for a statement by the SET_VAR hint.
+	tk.MustQuery("explain format = 'brief' select /*+ SET_VAR(tidb_opt_agg_push_down=0) */ t1.a, sum(t1.b) from t1 join t2 on t1.a = t2.a group by t1.a").Check(testkit.Rows(
+		"Projection 5.00 root  test.t1.a, Column#7",
+		"└─HashAgg 5.00 root  group by:Column#10, funcs:sum(Column#8)->Column#7, funcs:firstrow(Column#9)->test.t1.a",
+		"  └─Projection 100.00 root  cast(test.t1.b, decimal(32,0) BINARY)->Column#8, test.t1.a, test.t1.a",
+		"    └─HashJoin 100.00 root  inner join, equal:[eq(test.t1.a, test.t2.a)]",
+		"      ├─TableReader(Build) 100.00 root  data:Selection",
+		"      │ └─Selection 100.00 cop[tikv]  not(isnull(test.t2.a))",
+		"      │   └─TableFullScan 100.00 cop[tikv] table:t2 keep order:false",
+		"      └─TableReader(Probe) 100.00 root  data:Selection",
+		"        └─Selection 100.00 cop[tikv]  not(isnull(test.t1.a))",
+		"          └─TableFullScan 100.00 cop[tikv] table:t1 keep order:false"))
+	tk.MustQuery("select t1.a, sum(t1.b) from t1 join t2 on t1.a = t2.a group by t1.a").Sort().Check(testkit.Rows(
+		"0 950", "1 970", "2 990", "3 1010", "4 1030"))
+}
+
 func (s *testIntegrationSuite) TestApproxPercentile(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 
@@ -3524,6 +3650,136 @@ func (s *testIntegrationSuite) TestDecorrelateInnerJoinInSubquery(c *C) {
 	}
 }
 
+func (s *testIntegrationSuite) TestDecorrelateNonEqCorrelatedAgg(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2, t3")
+	tk.MustExec("create table t1(a int primary key, b int)")
+	tk.MustExec("create table t2(a int, b int)")
+	tk.MustExec("create table t3(a int, b int)")
+	tk.MustExec("insert into t1 values(1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into t2 values(1, 10), (2, 20), (null, 30)")
+	tk.MustExec("insert into t3 values(1, 0), (1, 0), (2, null)")
+	tk.MustExec("set @@tidb_opt_decorrelate_non_eq_agg = 1")
+
+	var input []string
+	var output []struct {
+		SQL  string
+		Plan []string
+	}
+	s.testData.GetTestCases(c, &input, &output)
+	for i, tt := range input {
+		s.testData.OnRecord(func() {
+			output[i].SQL = tt
+			output[i].Plan = s.testData.ConvertRowsToStrings(tk.MustQuery(tt).Rows())
+		})
+		tk.MustQuery(tt).Check(testkit.Rows(output[i].Plan...))
+	}
+
+	tk.MustQuery("select a, (select sum(t2.b) from t2 where t2.a < t1.a) from t1").Sort().Check(testkit.Rows("1 <nil>", "2 10", "3 30"))
+	tk.MustQuery("select a, (select count(*) from t2 where t2.a < t1.a) from t1").Sort().Check(testkit.Rows("1 0", "2 1", "3 2"))
+	tk.MustQuery("select b, (select avg(t2.b) from t2 where t2.a > t3.b) from t3").Sort().Check(testkit.Rows("0 15.0000", "0 15.0000", "<nil> <nil>"))
+
+	// The results of the window rewrite are the same as the ones of the apply.
+	tk.MustExec("insert into t2 values(2, 25), (3, null), (0, -5)")
+	tk.MustExec("insert into t3 values(3, 2), (null, 3), (4, -1)")
+	sqls := []string{
+		"select a, (select sum(t2.b) from t2 where t2.a <= t1.a) from t1",
+		"select a, (select count(t2.b) from t2 where t1.a > t2.a) from t1",
+		"select a, (select max(t2.b) from t2 where t2.a >= t1.a) from t1",
+		"select b, (select min(t2.b) from t2 where t2.a > t3.b and t2.b > 0) from t3",
+		"select b, (select count(*) from t2 where t2.a <= t3.b) from t3",
+		"select a, b, (select avg(t2.b) from t2 where t2.a < t3.b) from t3",
+		"select * from t3 where a < (select count(*) from t2 where t2.a >= t3.b)",
+		"select b, (select sum(t2.b + 1) - count(1) from t2 where t3.b < t2.a) from t3",
+	}
+	for _, sql := range sqls {
+		tk.MustExec("set @@tidb_opt_decorrelate_non_eq_agg = 1")
+		c.Assert(tk.HasPlan(sql, "Apply"), IsFalse, Commentf("sql: %s", sql))
+		c.Assert(tk.HasPlan(sql, "Window"), IsTrue, Commentf("sql: %s", sql))
+		rows := tk.MustQuery(sql).Sort().Rows()
+		tk.MustExec("set @@tidb_opt_decorrelate_scalar_subquery = 0")
+		c.Assert(tk.HasPlan(sql, "Apply"), IsTrue, Commentf("sql: %s", sql))
+		tk.MustQuery(sql).Sort().Check(rows)
+		tk.MustExec("set @@tidb_opt_decorrelate_scalar_subquery = 1")
+	}
+
+	// The compared DECIMAL columns are cast to a type which holds the values of both.
+	tk.MustExec("drop table if exists t4, t5")
+	tk.MustExec("create table t4(a decimal(10, 1), b int)")
+	tk.MustExec("create table t5(a decimal(5, 4), b int)")
+	tk.MustExec("insert into t4 values(1.5, 1), (123456789.5, 2), (null, 3)")
+	tk.MustExec("insert into t5 values(1.2345, 10), (1.5, 20), (9.9999, 30)")
+	sql := "select a, (select count(*) from t5 where t5.a < t4.a), (select sum(t5.b) from t5 where t5.a >= t4.a) from t4"
+	tk.MustExec("set @@tidb_opt_decorrelate_non_eq_agg = 1")
+	c.Assert(tk.HasPlan(sql, "Window"), IsTrue)
+	tk.MustQuery(sql).Sort().Check(testkit.Rows("1.5 1 50", "123456789.5 3 <nil>", "<nil> 0 <nil>"))
+	// The columns of different types aren't rewritten.
+	c.Assert(tk.HasPlan("select a, (select count(*) from t5 where t5.a < t4.b) from t4", "Apply"), IsTrue)
+
+	tk.MustExec("set @@tidb_opt_decorrelate_non_eq_agg = 0")
+	c.Assert(tk.HasPlan("select b, (select avg(t2.b) from t2 where t2.a > t3.b) from t3", "Apply"), IsTrue)
+}
+
+func (s *testIntegrationSuite) TestNullAwareAntiJoin(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int, c int not null)")
+	tk.MustExec("create table t2(a int, b int, c int not null)")
+	tk.MustExec("set @@tidb_enable_null_aware_anti_join = 1")
+
+	var input []string
+	var output []struct {
+		SQL  string
+		Plan []string
+	}
+	s.testData.GetTestCases(c, &input, &output)
+	for i, tt := range input {
+		s.testData.OnRecord(func() {
+			output[i].SQL = tt
+			output[i].Plan = s.testData.ConvertRowsToStrings(tk.MustQuery(tt).Rows())
+		})
+		tk.MustQuery(tt).Check(testkit.Rows(output[i].Plan...))
+	}
+}
+
+func (s *testIntegrationSuite) TestDecorrelateScalarSubqueryByCost(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int primary key, b int)")
+	tk.MustExec("create table t2(a int, b int, key(a))")
+	for i := 0; i < 20; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t1 values(%d, %d)", i, i))
+	}
+	vals := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		vals = append(vals, fmt.Sprintf("(%d, %d)", i, i*10))
+	}
+	tk.MustExec("insert into t2 values " + strings.Join(vals, ","))
+	tk.MustExec("analyze table t1, t2")
+
+	// All the outer rows look up the inner rows, the join reading the inner rows once is cheaper.
+	sql := "select a, (select max(t2.b) from t2 where t2.a = t1.a) from t1"
+	c.Assert(tk.HasPlan(sql, "Apply"), IsFalse)
+	tk.MustQuery(sql + " where t1.a < 2").Sort().Check(testkit.Rows("0 0", "1 10"))
+	// The per-row apply looking up the inner rows of an outer row by the index is cheaper.
+	c.Assert(tk.HasPlan(sql+" where t1.a = 1", "Apply"), IsTrue)
+	tk.MustQuery(sql + " where t1.a = 1").Check(testkit.Rows("1 10"))
+	// The inner rows can't be looked up without an index.
+	c.Assert(tk.HasPlan("select a, (select max(t2.a) from t2 where t2.b = t1.a) from t1 where t1.a = 1", "Apply"), IsFalse)
+
+	hinted := "select /*+ SET_VAR(tidb_opt_decorrelate_scalar_subquery=OFF) */ a, (select max(t2.b) from t2 where t2.a = t1.a) from t1"
+	c.Assert(tk.HasPlan(hinted, "Apply"), IsTrue)
+	tk.MustQuery(hinted + " where t1.a < 2").Sort().Check(testkit.Rows("0 0", "1 10"))
+	tk.MustExec("set @@tidb_opt_decorrelate_scalar_subquery = 0")
+	c.Assert(tk.HasPlan(sql, "Apply"), IsTrue)
+}
+
 func (s *testIntegrationSuite) TestIndexMergeTableFilter(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -3922,3 +4178,45 @@ func (s *testIntegrationSerialSuite) TestMergeContinuousSelections(c *C) {
 		res.Check(testkit.Rows(output[i].Plan...))
 	}
 }
+
+func (s *testIntegrationSuite) TestMergeRangeGroupsToMatchOrder(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, tp")
+	tk.MustExec("create table t(a int, b int, c int, d int, index idx(a, b, c))")
+	tk.MustExec("insert into t values (1, 5, 1, 1), (1, 2, 1, 1), (2, 4, 1, 1), (2, 1, 2, 1), (2, 1, 1, 1), (3, 3, 1, 1), (3, 6, 1, 1), (4, 0, 1, 1)")
+
+	tk.MustQuery("explain format = 'brief' select a, b from t where a in (1, 2, 3) order by b limit 3").Check(testkit.Rows(
+		"Limit 3.00 root  offset:0, count:3",
+		"└─IndexReader 3.00 root  index:Limit",
+		"  └─Limit 3.00 cop[tikv]  offset:0, count:3",
+		"    └─IndexRangeScan 3.00 cop[tikv] table:t, index:idx(a, b, c) range:[1,1], [2,2], [3,3], keep order:true, merge by:[test.t.b], stats:pseudo"))
+	tk.MustQuery("select a, b from t where a in (1, 2, 3) order by b limit 3").Check(testkit.Rows("2 1", "2 1", "1 2"))
+	tk.MustQuery("select a, b, c from t where a in (1, 2, 3) order by b desc, c desc").Check(testkit.Rows(
+		"3 6 1", "1 5 1", "2 4 1", "3 3 1", "1 2 1", "2 1 2", "2 1 1"))
+	tk.MustQuery("explain format = 'brief' select a, c from t where a in (1, 2) and b in (1, 4) order by c").Check(testkit.Rows(
+		"Projection 0.40 root  test.t.a, test.t.c",
+		"└─IndexReader 0.40 root  index:IndexRangeScan",
+		"  └─IndexRangeScan 0.40 cop[tikv] table:t, index:idx(a, b, c) range:[1 1,1 1], [1 4,1 4], [2 1,2 1], [2 4,2 4], keep order:true, merge by:[test.t.c], stats:pseudo"))
+	tk.MustQuery("select a, c from t where a in (1, 2) and b in (1, 4) order by c").Check(testkit.Rows("2 1", "2 1", "2 2"))
+
+	// The ranges can't be merged when reading the table rows.
+	tk.MustQuery("explain format = 'brief' select * from t where a in (1, 2, 3) order by b limit 3").Check(testkit.Rows(
+		"TopN 3.00 root  test.t.b, offset:0, count:3",
+		"└─IndexLookUp 3.00 root  ",
+		"  ├─TopN(Build) 3.00 cop[tikv]  test.t.b, offset:0, count:3",
+		"  │ └─IndexRangeScan 30.00 cop[tikv] table:t, index:idx(a, b, c) range:[1,1], [2,2], [3,3], keep order:false, stats:pseudo",
+		"  └─TableRowIDScan(Probe) 3.00 cop[tikv] table:t keep order:false, stats:pseudo"))
+	// The ranges can't be merged when the table has dirty content.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (1, 0, 0, 0)")
+	tk.MustQuery("select a, b from t where a in (1, 2, 3) order by b limit 3").Check(testkit.Rows("1 0", "2 1", "2 1"))
+	c.Assert(tk.MustUseIndex("select a, b from t where a in (1, 2, 3) order by b limit 3", "idx(a, b, c)"), IsTrue)
+	c.Assert(strings.Contains(fmt.Sprint(tk.MustQuery("explain format = 'brief' select a, b from t where a in (1, 2, 3) order by b limit 3").Rows()), "merge by"), IsFalse)
+	tk.MustExec("rollback")
+	// The ranges can't be merged on the partitioned table.
+	tk.MustExec("create table tp(a int, b int, index idx(a, b)) partition by hash(a) partitions 2")
+	tk.MustExec("insert into tp values (1, 2), (2, 1)")
+	c.Assert(strings.Contains(fmt.Sprint(tk.MustQuery("explain format = 'brief' select a, b from tp where a in (1, 2) order by b limit 1").Rows()), "merge by"), IsFalse)
+	tk.MustQuery("select a, b from tp where a in (1, 2) order by b limit 1").Check(testkit.Rows("2 1"))
+}
@@ -2030,6 +2030,16 @@ func (a *havingWindowAndOrderbyExprResolver) Leave(n ast.Node) (node ast.Node, o
 		if a.inWindowSpec {
 			a.popCurClause()
 		}
+	case *ast.MatchAgainst:
+		if a.curClause == orderByClause {
+			// The columns of MATCH are bare column names, append them to the select fields if they aren't selected,
+			// so that they can be resolved from the schema of the projection.
+			for _, name := range v.ColumnNames {
+				if _, a.err = a.resolveFromPlan(&ast.ColumnNameExpr{Name: name}, a.p); a.err != nil {
+					return node, false
+				}
+			}
+		}
 	case *ast.ColumnNameExpr:
 		resolveFieldsFirst := true
 		if a.inAggFunc || a.inWindowFunc || a.inWindowSpec || (a.curClause == orderByClause && a.inExpr) || a.curClause == fieldList {
@@ -4712,7 +4722,8 @@ func (b *PlanBuilder) buildUpdateLists(ctx context.Context, tableList []*ast.Tab
 			return nil, nil, false, infoschema.ErrTableNotExists.GenWithStackByArgs(tn.DBInfo.Name.O, tableInfo.Name.O)
 		}
 		for i, colInfo := range tableInfo.Columns {
-			if !colInfo.IsGenerated() {
+			// The non-public generated columns are filled by the table when the rows are written.
+			if !colInfo.IsGenerated() || colInfo.State != model.StatePublic {
 				continue
 			}
 			columnFullName := fmt.Sprintf("%s.%s.%s", tn.DBInfo.Name.L, tn.Name.L, colInfo.Name.L)
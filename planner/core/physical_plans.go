@@ -326,6 +326,8 @@ type PhysicalIndexMergeReader struct {
 	partialPlans []PhysicalPlan
 	// tablePlan is a PhysicalTableScan to get the table tuples. Current, it must be not nil.
 	tablePlan PhysicalPlan
+	// IsIntersectionType means the handles fetched by the partial plans are intersected, otherwise they are united.
+	IsIntersectionType bool
 
 	// Used by partition table.
 	PartitionInfo PartitionInfo
@@ -382,6 +384,11 @@ type PhysicalIndexScan struct {
 	isPartition bool
 	Desc        bool
 	KeepOrder   bool
+	// MergePrefixLen is the number of the leading index columns whose values are listed by IN. When it's greater than
+	// 0, the ranges are grouped by the values of these columns, each group is scanned separately in the order of the
+	// index and the rows are merged by ByItems.
+	MergePrefixLen int
+	ByItems        []*util.ByItems
 	// DoubleRead means if the index executor will read kv two times.
 	// If the query requires the columns that don't belong to index, DoubleRead will be true.
 	DoubleRead bool
@@ -410,6 +417,12 @@ func (p *PhysicalIndexScan) Clone() (PhysicalPlan, error) {
 	copy(cloned.IdxColLens, p.IdxColLens)
 	cloned.Ranges = cloneRanges(p.Ranges)
 	cloned.Columns = cloneColInfos(p.Columns)
+	if p.ByItems != nil {
+		cloned.ByItems = make([]*util.ByItems, 0, len(p.ByItems))
+		for _, item := range p.ByItems {
+			cloned.ByItems = append(cloned.ByItems, item.Clone())
+		}
+	}
 	if p.dataSourceSchema != nil {
 		cloned.dataSourceSchema = p.dataSourceSchema.Clone()
 	}
@@ -763,6 +776,10 @@ type PhysicalHashJoin struct {
 	// use the outer table to build a hash table when the outer table is smaller.
 	UseOuterToBuild bool
 
+	// IsNullAware indicates the join is a null-aware anti semi join converted from `not in (subq)`, whose
+	// EqualConditions have nullable operands and must be evaluated with the null semantics of `not in`.
+	IsNullAware bool
+
 	// on which store the join executes.
 	storeTp          kv.StoreType
 	globalChildIndex int
@@ -779,6 +796,7 @@ func (p *PhysicalHashJoin) Clone() (PhysicalPlan, error) {
 	cloned.basePhysicalJoin = *base
 	cloned.Concurrency = p.Concurrency
 	cloned.UseOuterToBuild = p.UseOuterToBuild
+	cloned.IsNullAware = p.IsNullAware
 	for _, c := range p.EqualConditions {
 		cloned.EqualConditions = append(cloned.EqualConditions, c.Clone().(*expression.ScalarFunction))
 	}
@@ -884,6 +902,9 @@ type PhysicalMergeJoin struct {
 	CompareFuncs []expression.CompareFunc
 	// Desc means whether inner child keep desc order.
 	Desc bool
+	// Concurrency is the number of the workers joining the ranges of the ordered inputs in parallel, the join is
+	// executed serially if it's not greater than 1.
+	Concurrency int
 }
 
 // PhysicalExchangeReceiver accepts connection and receives data passively.
@@ -944,6 +965,7 @@ func (p *PhysicalMergeJoin) Clone() (PhysicalPlan, error) {
 	cloned.basePhysicalJoin = *base
 	cloned.CompareFuncs = append(cloned.CompareFuncs, p.CompareFuncs...)
 	cloned.Desc = p.Desc
+	cloned.Concurrency = p.Concurrency
 	return cloned, nil
 }
 
@@ -220,6 +220,8 @@ func optimizeByShuffle4MergeJoin(pp *PhysicalMergeJoin, ctx sessionctx.Context)
 		SplitterType: PartitionHashSplitterType,
 		ByItemArrays: [][]expression.Expression{leftByItemArray, rightByItemArray},
 	}.Init(ctx, pp.statsInfo(), pp.SelectBlockOffset(), reqProp)
+	// The merge join is already executed in parallel by the shuffle.
+	pp.Concurrency = 1
 	return shuffle
 }
 
@@ -16,6 +16,7 @@ package core_test
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"strings"
 
 	. "github.com/pingcap/check"
@@ -140,6 +141,31 @@ func (s *testPlanNormalize) TestNormalizedPlan(c *C) {
 	}
 }
 
+func (s *testPlanNormalize) TestNormalizedPlanNodeIDs(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1")
+	tk.MustExec("create table t1 (a int key,b int,c int, index (b));")
+	var ids []map[int]string
+	for i := 0; i < 2; i++ {
+		tk.MustExec("select * from t1 where b > 1 and c > 1")
+		info := tk.Se.ShowProcess()
+		c.Assert(info, NotNil)
+		p, ok := info.Plan.(core.Plan)
+		c.Assert(ok, IsTrue)
+		ids = append(ids, core.NormalizedPlanNodeIDs(p))
+	}
+	// The identities are in the order of the normalized plan, and they don't change with the plan IDs.
+	for _, nodeIDs := range ids {
+		values := make([]string, 0, len(nodeIDs))
+		for _, id := range nodeIDs {
+			values = append(values, id)
+		}
+		sort.Strings(values)
+		c.Assert(values, DeepEquals, []string{"Selection#1", "TableFullScan#2", "TableReader#0"})
+	}
+}
+
 func (s *testPlanNormalize) TestNormalizedPlanForDiffStore(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -535,6 +561,79 @@ func (s *testPlanNormalize) TestNthPlanHint(c *C) {
 		"Warning 1105 The parameter of nth_plan() is out of range."))
 }
 
+func (s *testPlanNormalize) TestExplainStatsTrace(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (a int, b int, index ia(a))")
+	tk.MustExec("create table t2 (a int, b int)")
+	tk.MustExec("insert into t1 values (1, 1), (1, 2), (2, 3), (3, 4), (null, 5)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2), (2, 3)")
+	tk.MustExec("analyze table t1, t2")
+
+	rows := tk.MustQuery("explain format='stats_trace' select * from t1 join t2 on t1.b = t2.b where t1.a = 1 and t2.a > 1").Rows()
+	steps := make(map[string]string, len(rows))
+	for _, row := range rows {
+		steps[fmt.Sprintf("%v %v %v", row[0], row[1], row[2])] = fmt.Sprintf("%v", row[4])
+	}
+	c.Assert(steps["DataSource_1 equal a"], Equals, "2.0000")
+	c.Assert(steps["DataSource_1 selectivity ia"], Equals, "0.4000")
+	c.Assert(steps["DataSource_1 filter t1"], Equals, "2.0000")
+	c.Assert(steps["DataSource_1 access path ia"], Equals, "2.0000")
+	c.Assert(steps["DataSource_2 selectivity a"], Equals, "0.6667")
+	c.Assert(steps["DataSource_2 filter t2"], Equals, "2.0000")
+	c.Assert(steps["Join_7 join equal conditions"], Equals, "2.0000")
+
+	tk.MustQuery("explain format='stats_trace' select count(*) from t2 group by a").Check(testkit.Rows(
+		"DataSource_1 filter t2 conditions: , table rows: 3.0000 * selectivity: 1.0000 3.0000",
+		"DataSource_1 access path t2 access conditions:  3.0000",
+		"Aggregation_2 aggregation  group by columns: test.t2.a, max NDV of the columns 2.0000"))
+	// The trace isn't recorded for the normal statements.
+	tk.MustQuery("explain format='brief' select * from t2 where a > 1")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.StatsTrace, IsNil)
+}
+
+func (s *testPlanNormalize) TestExplainIndexCandidates(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, c int, primary key(a), unique index ub(b), index ic(c), index icb(c, b))")
+
+	decisions := func(sql string) map[string]string {
+		rows := tk.MustQuery("explain format='index_candidates' " + sql).Rows()
+		result := make(map[string]string, len(rows))
+		for _, row := range rows {
+			c.Assert(row, HasLen, 9)
+			// The cost is only estimated for the paths which are not pruned.
+			c.Assert(row[6] == "", Equals, row[7] == "pruned", Commentf("%v", row))
+			result[fmt.Sprintf("%v %v %v", row[0], row[1], row[2])] = fmt.Sprintf("%v %v", row[7], row[8])
+		}
+		return result
+	}
+
+	d := decisions("select * from t where c > 1 and b > 1")
+	c.Assert(d, HasLen, 4)
+	c.Assert(d["t icb rootTask"], Equals, "chosen ")
+	c.Assert(d["t ic rootTask"], Equals, "pruned dominated by icb: no double read")
+	c.Assert(d["t table scan rootTask"], Equals, "pruned dominated by icb: more access condition columns")
+	c.Assert(d["t ub rootTask"], Equals, "rejected the cost is higher than icb")
+
+	d = decisions("select b from t t1 where c > 1 order by c")
+	c.Assert(d["t1 ub rootTask"], Equals, "pruned no access conditions, no order to match and not a covering index")
+	c.Assert(d["t1 table scan rootTask, order by test.t.c"], Equals, "pruned dominated by icb: more access condition columns, matches the required order")
+	c.Assert(d["t1 icb rootTask, order by test.t.c"], Equals, "chosen ")
+
+	tk.MustQuery("explain format='index_candidates' select * from t where b = 1 and c = 1").Check(testkit.Rows(
+		"t table scan   false true  pruned ub has point ranges",
+		"t ic  eq(test.t.c, 1) false false  pruned ub has point ranges",
+		"t icb  eq(test.t.b, 1), eq(test.t.c, 1) false true  pruned ub has point ranges",
+		"t ub rootTask eq(test.t.b, 1) false false 7.40 chosen "))
+
+	// The trace isn't recorded for the normal statements.
+	tk.MustQuery("explain format='brief' select * from t where c > 1")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.IndexCandidatesTrace, IsNil)
+}
+
 func (s *testPlanNormalize) BenchmarkDecodePlan(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
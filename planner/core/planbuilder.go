@@ -54,6 +54,7 @@ import (
 	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tidb/util/sem"
 	"github.com/pingcap/tidb/util/set"
+	"github.com/pingcap/tidb/util/tracing"
 	"github.com/tikv/client-go/v2/oracle"
 	"github.com/tikv/client-go/v2/tikv"
 
@@ -2068,7 +2069,56 @@ func (b *PlanBuilder) buildAnalyze(as *ast.AnalyzeTableStmt) (Plan, error) {
 		}
 		return b.buildAnalyzeIndex(as, opts, statsVersion)
 	}
-	return b.buildAnalyzeTable(as, opts, statsVersion)
+	// The bucket number specified by "UPDATE HISTOGRAM ON ... WITH n BUCKETS" only takes effect on the given columns.
+	var colBuckets map[int64]uint64
+	if as.HistogramOperation == ast.HistogramOperationUpdate && len(as.ColumnNames) > 0 {
+		colBuckets, err = getAnalyzeColumnBuckets(as, opts, statsVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+	p, err := b.buildAnalyzeTable(as, opts, statsVersion)
+	if err != nil {
+		return nil, err
+	}
+	// The bucket number is selected adaptively only if it's not specified by the user.
+	adaptiveBuckets := b.ctx.GetSessionVars().AnalyzeAdaptiveBuckets && (colBuckets != nil || !hasAnalyzeOption(as.AnalyzeOpts, ast.AnalyzeOptNumBuckets))
+	analyze := p.(*Analyze)
+	for i := range analyze.ColTasks {
+		analyze.ColTasks[i].ColumnBuckets = colBuckets
+		analyze.ColTasks[i].AdaptiveBuckets = adaptiveBuckets
+	}
+	return analyze, nil
+}
+
+func hasAnalyzeOption(opts []ast.AnalyzeOpt, tp ast.AnalyzeOptionType) bool {
+	for _, opt := range opts {
+		if opt.Type == tp {
+			return true
+		}
+	}
+	return false
+}
+
+// getAnalyzeColumnBuckets returns the bucket number of the columns in "UPDATE HISTOGRAM ON", and resets the bucket
+// number of opts to the default one, which is used by the other columns and the indexes.
+func getAnalyzeColumnBuckets(as *ast.AnalyzeTableStmt, opts map[ast.AnalyzeOptionType]uint64, statsVer int) (map[int64]uint64, error) {
+	tblInfo := as.TableNames[0].TableInfo
+	numBuckets := opts[ast.AnalyzeOptNumBuckets]
+	if statsVer == statistics.Version1 {
+		opts[ast.AnalyzeOptNumBuckets] = analyzeOptionDefault[ast.AnalyzeOptNumBuckets]
+	} else {
+		opts[ast.AnalyzeOptNumBuckets] = analyzeOptionDefaultV2[ast.AnalyzeOptNumBuckets]
+	}
+	colBuckets := make(map[int64]uint64, len(as.ColumnNames))
+	for _, colName := range as.ColumnNames {
+		col := model.FindColumnInfo(tblInfo.Columns, colName.Name.L)
+		if col == nil || col.Hidden {
+			return nil, ErrUnknownColumn.GenWithStackByArgs(colName.Name.O, "UPDATE HISTOGRAM")
+		}
+		colBuckets[col.ID] = numBuckets
+	}
+	return colBuckets, nil
 }
 
 func buildShowNextRowID() (*expression.Schema, types.NameSlice) {
@@ -3816,11 +3866,42 @@ func (b *PlanBuilder) buildExplain(ctx context.Context, explain *ast.ExplainStmt
 	if show, ok := explain.Stmt.(*ast.ShowStmt); ok {
 		return b.buildShow(ctx, show)
 	}
+	switch strings.ToLower(explain.Format) {
+	case ExplainFormatStatsTrace:
+		sc := b.ctx.GetSessionVars().StmtCtx
+		sc.StatsTrace = tracing.NewStatsTracer()
+		targetPlan, _, err := OptimizeAstNode(ctx, b.ctx, explain.Stmt, b.is)
+		records := sc.StatsTrace.Records()
+		sc.StatsTrace = nil
+		if err != nil {
+			return nil, err
+		}
+		p, err := b.buildExplainPlan(targetPlan, explain.Format, nil, explain.Analyze, explain.Stmt, nil)
+		if err != nil {
+			return nil, err
+		}
+		p.(*Explain).StatsTrace = records
+		return p, nil
+	case ExplainFormatIndexCandidates:
+		sc := b.ctx.GetSessionVars().StmtCtx
+		sc.IndexCandidatesTrace = tracing.NewIndexCandidatesTracer()
+		targetPlan, _, err := OptimizeAstNode(ctx, b.ctx, explain.Stmt, b.is)
+		records := sc.IndexCandidatesTrace.Records()
+		sc.IndexCandidatesTrace = nil
+		if err != nil {
+			return nil, err
+		}
+		p, err := b.buildExplainPlan(targetPlan, explain.Format, nil, explain.Analyze, explain.Stmt, nil)
+		if err != nil {
+			return nil, err
+		}
+		p.(*Explain).IndexCandidates = records
+		return p, nil
+	}
 	targetPlan, _, err := OptimizeAstNode(ctx, b.ctx, explain.Stmt, b.is)
 	if err != nil {
 		return nil, err
 	}
-
 	return b.buildExplainPlan(targetPlan, explain.Format, nil, explain.Analyze, explain.Stmt, nil)
 }
 
@@ -4001,8 +4082,8 @@ func buildShowSchema(s *ast.ShowStmt, isView bool, isSequence bool) (schema *exp
 		names = []string{"NodeID", "Address", "State", "Max_Commit_Ts", "Update_Time"}
 		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLonglong, mysql.TypeVarchar}
 	case ast.ShowStatsMeta:
-		names = []string{"Db_name", "Table_name", "Partition_name", "Update_time", "Modify_count", "Row_count"}
-		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeDatetime, mysql.TypeLonglong, mysql.TypeLonglong}
+		names = []string{"Db_name", "Table_name", "Partition_name", "Update_time", "Modify_count", "Row_count", "Snapshot_ts"}
+		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeDatetime, mysql.TypeLonglong, mysql.TypeLonglong, mysql.TypeLonglong}
 	case ast.ShowStatsExtended:
 		names = []string{"Db_name", "Table_name", "Stats_name", "Column_names", "Stats_type", "Stats_val", "Last_update_version"}
 		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeLonglong}
@@ -453,9 +453,10 @@ func (p *preprocessor) Leave(in ast.Node) (out ast.Node, ok bool) {
 		if _, ok := x.Stmt.(*ast.ShowStmt); ok {
 			break
 		}
-		valid := false
+		format := strings.ToLower(x.Format)
+		valid := format == ExplainFormatStatsTrace || format == ExplainFormatIndexCandidates
 		for i, length := 0, len(ast.ExplainFormats); i < length; i++ {
-			if strings.ToLower(x.Format) == ast.ExplainFormats[i] {
+			if format == ast.ExplainFormats[i] {
 				valid = true
 				break
 			}
@@ -23,6 +23,10 @@ import (
 	"github.com/pingcap/tidb/types"
 )
 
+// aggPushDownMaxNDVRatio is the max ratio of the group-by NDV to the row count of a join child for pushing the
+// aggregation down to it. Above the ratio, the pushed aggregation hardly shrinks the input of the join.
+const aggPushDownMaxNDVRatio = 0.5
+
 type aggregationPushDownSolver struct {
 	aggregationEliminateChecker
 }
@@ -220,6 +224,13 @@ func (a *aggregationPushDownSolver) tryToPushDownAgg(aggFuncs []*aggregation.Agg
 			return child, nil
 		}
 	}
+	// tidb_opt_force_agg_push_down forces the aggregation to be pushed down.
+	if !join.ctx.GetSessionVars().GetForceAggPushDown() {
+		beneficial, err := a.isPushDownBeneficial(child, gbyCols)
+		if err != nil || !beneficial {
+			return child, err
+		}
+	}
 	agg, err := a.makeNewAgg(join.ctx, aggFuncs, gbyCols, aggHints, blockOffset)
 	if err != nil {
 		return nil, err
@@ -242,6 +253,33 @@ func (a *aggregationPushDownSolver) tryToPushDownAgg(aggFuncs []*aggregation.Agg
 	return agg, nil
 }
 
+// isPushDownBeneficial decides whether to push the aggregation down to the join child by the estimated reduction
+// of its rows. If any table of the child only has the pseudo statistics, the aggregation is always pushed down.
+func (a *aggregationPushDownSolver) isPushDownBeneficial(child LogicalPlan, gbyCols []*expression.Column) (bool, error) {
+	if len(gbyCols) == 0 || hasPseudoStats(child) {
+		return true, nil
+	}
+	stats, err := child.recursiveDeriveStats(nil)
+	if err != nil {
+		return false, err
+	}
+	ndv := getCardinality(gbyCols, child.Schema(), stats)
+	return ndv <= stats.RowCount*aggPushDownMaxNDVRatio, nil
+}
+
+// hasPseudoStats checks whether any table in the plan is estimated with the pseudo statistics.
+func hasPseudoStats(p LogicalPlan) bool {
+	if ds, ok := p.(*DataSource); ok {
+		return ds.statisticTable.Pseudo
+	}
+	for _, child := range p.Children() {
+		if hasPseudoStats(child) {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *aggregationPushDownSolver) getDefaultValues(agg *LogicalAggregation) ([]types.Datum, bool) {
 	defaultValues := make([]types.Datum, 0, agg.Schema().Len())
 	for _, aggFunc := range agg.AggFuncs {
@@ -393,7 +431,7 @@ func (a *aggregationPushDownSolver) aggPushDown(p LogicalPlan) (_ LogicalPlan, e
 			p = proj
 		} else {
 			child := agg.children[0]
-			if join, ok1 := child.(*LogicalJoin); ok1 && a.checkValidJoin(join) && p.SCtx().GetSessionVars().AllowAggPushDown {
+			if join, ok1 := child.(*LogicalJoin); ok1 && a.checkValidJoin(join) && p.SCtx().GetSessionVars().GetAllowAggPushDown() {
 				if valid, leftAggFuncs, rightAggFuncs, leftGbyCols, rightGbyCols := a.splitAggFuncsAndGbyCols(agg, join); valid {
 					var lChild, rChild LogicalPlan
 					// If there exist count or sum functions in left join path, we can't push any
@@ -444,7 +482,7 @@ func (a *aggregationPushDownSolver) aggPushDown(p LogicalPlan) (_ LogicalPlan, e
 				// The final plan tree should be 'Aggregation->Union All->Aggregation->X'.
 				child = projChild
 			}
-			if union, ok1 := child.(*LogicalUnionAll); ok1 && p.SCtx().GetSessionVars().AllowAggPushDown {
+			if union, ok1 := child.(*LogicalUnionAll); ok1 && p.SCtx().GetSessionVars().GetAllowAggPushDown() {
 				err := a.tryAggPushDownForUnion(union, agg)
 				if err != nil {
 					return nil, err
@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/planner/property"
 	"github.com/pingcap/tidb/types"
 )
 
@@ -35,6 +36,333 @@ func (la *LogicalApply) canPullUpAgg() bool {
 	return len(la.children[0].Schema().Keys) > 0
 }
 
+// hasNonEqCorCondsBelowAgg checks whether the selection below the aggregation has correlated conditions of this apply
+// that cannot be pulled up as the equal conditions of the join.
+func (la *LogicalApply) hasNonEqCorCondsBelowAgg(agg *LogicalAggregation) bool {
+	sel, ok := agg.children[0].(*LogicalSelection)
+	if !ok {
+		return false
+	}
+	outerSchema := la.children[0].Schema()
+	for _, cond := range sel.Conditions {
+		if la.deCorColFromEqExpr(cond) == nil && containsCorColsOf(expression.ExtractCorColumns(cond), outerSchema) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsCorColsOf checks whether any of the correlated columns is a column of the schema.
+func containsCorColsOf(corCols []*expression.CorrelatedColumn, schema *expression.Schema) bool {
+	for _, corCol := range corCols {
+		if schema.Contains(&corCol.Column) {
+			return true
+		}
+	}
+	return false
+}
+
+// integralDigits returns the number of the digits before the decimal point of the field type.
+func integralDigits(tp *types.FieldType) int {
+	if tp.Decimal > 0 {
+		return tp.Flen - tp.Decimal
+	}
+	return tp.Flen
+}
+
+// rewriteNonEqCorAggToWindow rewrites the apply of an aggregation whose only correlated condition compares an inner
+// column with an outer column, like `select a, (select sum(t2.b) from t2 where t2.a < t1.a) from t1`, into a running
+// window aggregation. The outer rows and the inner rows are put into a union and sorted by the compared columns, the
+// outer rows are sorted before or after the inner rows of the same value according to the comparison, so the frame
+// from the first row to an outer row contains exactly the inner rows matching it. Then the outer rows are selected
+// with the window aggregation results. The plan reads both sides once instead of joining every outer row with all
+// the inner rows. It returns nil if the apply can't be rewritten.
+func (la *LogicalApply) rewriteNonEqCorAggToWindow(agg *LogicalAggregation) (LogicalPlan, error) {
+	if la.JoinType != InnerJoin && la.JoinType != LeftOuterJoin {
+		return nil, nil
+	}
+	if len(la.EqualConditions)+len(la.LeftConditions)+len(la.RightConditions)+len(la.OtherConditions) > 0 || len(agg.GroupByItems) > 0 {
+		return nil, nil
+	}
+	for _, f := range agg.AggFuncs {
+		switch f.Name {
+		case ast.AggFuncCount, ast.AggFuncSum, ast.AggFuncAvg, ast.AggFuncMax, ast.AggFuncMin:
+		default:
+			return nil, nil
+		}
+		if f.HasDistinct || len(f.Args) != 1 || f.Mode != aggregation.CompleteMode {
+			return nil, nil
+		}
+	}
+	sel, ok := agg.children[0].(*LogicalSelection)
+	if !ok {
+		return nil, nil
+	}
+	outerPlan, innerPlan := la.children[0], sel.children[0]
+	outerSchema := outerPlan.Schema()
+	var (
+		innerCol, outerCol *expression.Column
+		cmpOp              string
+		innerConds         []expression.Expression
+	)
+	for _, cond := range sel.Conditions {
+		if !containsCorColsOf(expression.ExtractCorColumns(cond), outerSchema) {
+			innerConds = append(innerConds, cond)
+			continue
+		}
+		sf, ok := cond.(*expression.ScalarFunction)
+		if !ok || innerCol != nil {
+			return nil, nil
+		}
+		cmpOp = sf.FuncName.L
+		args := sf.GetArgs()
+		col, isCol := args[0].(*expression.Column)
+		corCol, isCorCol := args[1].(*expression.CorrelatedColumn)
+		if !isCol || !isCorCol {
+			col, isCol = args[1].(*expression.Column)
+			corCol, isCorCol = args[0].(*expression.CorrelatedColumn)
+			cmpOp = symmetricOp[cmpOp]
+		}
+		if !isCol || !isCorCol {
+			return nil, nil
+		}
+		switch cmpOp {
+		case ast.LT, ast.LE, ast.GT, ast.GE:
+		default:
+			return nil, nil
+		}
+		innerCol, outerCol = col, outerSchema.RetrieveColumn(&corCol.Column)
+		if outerCol == nil || !innerPlan.Schema().Contains(innerCol) {
+			return nil, nil
+		}
+	}
+	if innerCol == nil || containsCorColsOf(ExtractCorrelatedCols4LogicalPlan(innerPlan), outerSchema) {
+		return nil, nil
+	}
+	for _, f := range agg.AggFuncs {
+		if containsCorColsOf(expression.ExtractCorColumns(f.Args[0]), outerSchema) {
+			return nil, nil
+		}
+	}
+	// The columns are sorted as they are compared only if they are of the same type.
+	innerTp, outerTp := innerCol.RetType, outerCol.RetType
+	if innerTp.Tp != outerTp.Tp || innerTp.EvalType() == types.ETJson ||
+		mysql.HasUnsignedFlag(innerTp.Flag) != mysql.HasUnsignedFlag(outerTp.Flag) ||
+		(innerTp.EvalType() == types.ETString && innerTp.Collate != outerTp.Collate) {
+		return nil, nil
+	}
+	// The key type holds the values of both columns, the integral digits and the fractional digits are widened
+	// separately, e.g. DECIMAL(10,1) and DECIMAL(5,4) are compared as DECIMAL(13,4).
+	if innerTp.Decimal != outerTp.Decimal && (innerTp.Decimal == types.UnspecifiedLength || outerTp.Decimal == types.UnspecifiedLength) {
+		return nil, nil
+	}
+	keyTp := innerTp.Clone()
+	if outerTp.Decimal > keyTp.Decimal {
+		keyTp.Decimal = outerTp.Decimal
+	}
+	if innerTp.Flen == types.UnspecifiedLength || outerTp.Flen == types.UnspecifiedLength {
+		keyTp.Flen = types.UnspecifiedLength
+	} else {
+		frac := keyTp.Decimal
+		if frac < 0 {
+			frac = 0
+		}
+		keyTp.Flen = integralDigits(innerTp)
+		if digits := integralDigits(outerTp); digits > keyTp.Flen {
+			keyTp.Flen = digits
+		}
+		keyTp.Flen += frac
+	}
+	if keyTp.Tp == mysql.TypeNewDecimal && keyTp.Flen > mysql.MaxDecimalWidth {
+		return nil, nil
+	}
+	keyTp.Flag &= ^mysql.NotNullFlag
+	castKey := func(col *expression.Column) expression.Expression {
+		if col.RetType.Equal(keyTp) {
+			return col
+		}
+		return expression.BuildCastFunction(la.ctx, col, keyTp)
+	}
+	// The outer rows are sorted before the inner rows of the same value if the inner value must be less or greater
+	// than the outer value, so that they are not in the frames of the outer rows.
+	outerTag, innerTag := expression.NewZero(), expression.NewOne()
+	if cmpOp == ast.LE || cmpOp == ast.GE {
+		outerTag, innerTag = innerTag, outerTag
+	}
+	intTp := types.NewFieldType(mysql.TypeLonglong)
+	nullOf := func(tp *types.FieldType) *expression.Constant {
+		return &expression.Constant{Value: types.NewDatum(nil), RetType: tp}
+	}
+
+	// The union outputs the outer columns, the arguments of the aggregate functions, the compared value, whether the
+	// compared value is null, and the tag of the side.
+	outerExprs := make([]expression.Expression, 0, outerSchema.Len()+len(agg.AggFuncs)+3)
+	innerExprs := make([]expression.Expression, 0, cap(outerExprs))
+	unionCols := make([]*expression.Column, 0, cap(outerExprs))
+	newUnionCol := func(tp *types.FieldType) {
+		tp = tp.Clone()
+		tp.Flag &= ^mysql.NotNullFlag
+		unionCols = append(unionCols, &expression.Column{UniqueID: la.ctx.GetSessionVars().AllocPlanColumnID(), RetType: tp})
+	}
+	for _, col := range outerSchema.Columns {
+		outerExprs = append(outerExprs, col)
+		innerExprs = append(innerExprs, nullOf(col.RetType))
+		newUnionCol(col.RetType)
+	}
+	for _, f := range agg.AggFuncs {
+		outerExprs = append(outerExprs, nullOf(f.Args[0].GetType()))
+		innerExprs = append(innerExprs, f.Args[0])
+		newUnionCol(f.Args[0].GetType())
+	}
+	outerExprs = append(outerExprs, castKey(outerCol),
+		expression.NewFunctionInternal(la.ctx, ast.IsNull, intTp, outerCol), outerTag)
+	innerExprs = append(innerExprs, castKey(innerCol), expression.NewZero(), innerTag)
+	newUnionCol(keyTp)
+	newUnionCol(intTp)
+	newUnionCol(intTp)
+
+	// The children of the union output the same columns as it, like buildProjection4Union does.
+	buildProj := func(child LogicalPlan, exprs []expression.Expression) *LogicalProjection {
+		proj := LogicalProjection{Exprs: exprs}.Init(la.ctx, la.blockOffset)
+		proj.SetSchema(expression.NewSchema(unionCols...).Clone())
+		proj.SetChildren(child)
+		return proj
+	}
+	// The inner rows whose compared value is null never match.
+	innerConds = append(innerConds, expression.NewFunctionInternal(la.ctx, ast.UnaryNot, types.NewFieldType(mysql.TypeTiny),
+		expression.NewFunctionInternal(la.ctx, ast.IsNull, types.NewFieldType(mysql.TypeTiny), innerCol)))
+	innerSel := LogicalSelection{Conditions: innerConds}.Init(la.ctx, sel.blockOffset)
+	innerSel.SetChildren(innerPlan)
+	union := LogicalUnionAll{}.Init(la.ctx, la.blockOffset)
+	union.SetSchema(expression.NewSchema(unionCols...))
+	union.SetChildren(buildProj(outerPlan, outerExprs), buildProj(innerSel, innerExprs))
+
+	keyCol, isNullCol, tagCol := unionCols[len(unionCols)-3], unionCols[len(unionCols)-2], unionCols[len(unionCols)-1]
+	window := LogicalWindow{
+		// The outer rows whose compared value is null are sorted first, so they match no inner rows.
+		OrderBy: []property.SortItem{{Col: isNullCol, Desc: true}, {Col: keyCol, Desc: cmpOp == ast.GT || cmpOp == ast.GE}, {Col: tagCol}},
+		Frame: &WindowFrame{
+			Type:  ast.Rows,
+			Start: &FrameBound{Type: ast.Preceding, UnBounded: true},
+			End:   &FrameBound{Type: ast.CurrentRow},
+		},
+	}.Init(la.ctx, la.blockOffset)
+	windowSchema := union.Schema().Clone()
+	windowFuncs := make([]*aggregation.WindowFuncDesc, 0, len(agg.AggFuncs))
+	for i, f := range agg.AggFuncs {
+		desc, err := aggregation.NewWindowFuncDesc(la.ctx, f.Name, []expression.Expression{unionCols[outerSchema.Len()+i]})
+		if err != nil {
+			return nil, err
+		}
+		desc.WrapCastForAggArgs(la.ctx)
+		windowFuncs = append(windowFuncs, desc)
+		windowSchema.Append(&expression.Column{UniqueID: la.ctx.GetSessionVars().AllocPlanColumnID(), RetType: desc.RetTp})
+	}
+	window.WindowFuncDescs = windowFuncs
+	window.SetSchema(windowSchema)
+	window.SetChildren(union)
+
+	outerSel := LogicalSelection{Conditions: []expression.Expression{
+		expression.NewFunctionInternal(la.ctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), tagCol, outerTag)}}.Init(la.ctx, la.blockOffset)
+	outerSel.SetChildren(window)
+
+	proj := LogicalProjection{}.Init(la.ctx, la.blockOffset)
+	proj.SetSchema(la.Schema().Clone())
+	for i := range outerSchema.Columns {
+		proj.Exprs = append(proj.Exprs, unionCols[i])
+	}
+	for i, col := range agg.Schema().Columns {
+		var expr expression.Expression = windowSchema.Columns[len(unionCols)+i]
+		if !expr.GetType().Equal(col.RetType) {
+			expr = expression.BuildCastFunction(la.ctx, expr, col.RetType)
+		}
+		proj.Exprs = append(proj.Exprs, expr)
+	}
+	proj.SetChildren(outerSel)
+	return proj, nil
+}
+
+// applyLookupCost is the cost of looking up the inner rows of an outer row by an index, measured in the inner rows
+// read, when the apply of an aggregation is executed row by row.
+const applyLookupCost = 10.0
+
+// preferApplyForAgg checks whether executing the apply of the aggregation row by row is cheaper than decorrelating it
+// into a join and an aggregation, which reads all the inner rows. It's cheaper when the outer rows are few and the
+// inner rows of an outer row can be looked up by an index on the column of an equal correlated condition. Since the
+// stats of the plans are derived after the predicates are pushed down, the row counts are estimated by the statistics
+// of the tables, and it returns false if they are pseudo.
+func (la *LogicalApply) preferApplyForAgg(agg *LogicalAggregation) bool {
+	sel, ok := agg.children[0].(*LogicalSelection)
+	if !ok {
+		return false
+	}
+	ds, ok := sel.children[0].(*DataSource)
+	if !ok || ds.statisticTable == nil || ds.statisticTable.Pseudo {
+		return false
+	}
+	outerRows, ok := estimateRowCountByTableStats(la.children[0])
+	if !ok {
+		return false
+	}
+	innerRows := float64(ds.statisticTable.Count)
+	for _, cond := range sel.Conditions {
+		eqCond := la.deCorColFromEqExpr(cond)
+		if eqCond == nil {
+			continue
+		}
+		col, ok := eqCond.(*expression.ScalarFunction).GetArgs()[1].(*expression.Column)
+		if !ok || !ds.hasIndexPrefixedBy(col) {
+			continue
+		}
+		rowsPerLookup := innerRows / math.Max(ds.getColumnNDV(col.ID), 1)
+		if outerRows*(rowsPerLookup+applyLookupCost) < innerRows+outerRows {
+			return true
+		}
+	}
+	return false
+}
+
+// estimateRowCountByTableStats estimates the row count of a DataSource, or a Selection on a DataSource, by the
+// statistics of the table. It returns false for the other plans or the pseudo statistics.
+func estimateRowCountByTableStats(p LogicalPlan) (float64, bool) {
+	var conds []expression.Expression
+	if sel, ok := p.(*LogicalSelection); ok {
+		conds = sel.Conditions
+		p = sel.children[0]
+	}
+	ds, ok := p.(*DataSource)
+	if !ok || ds.statisticTable == nil || ds.statisticTable.Pseudo {
+		return 0, false
+	}
+	rowCount := float64(ds.statisticTable.Count)
+	if len(conds) == 0 {
+		return rowCount, true
+	}
+	histColl := ds.statisticTable.GenerateHistCollFromColumnInfo(ds.Columns, ds.schema.Columns)
+	selectivity, _, err := histColl.Selectivity(ds.ctx, conds, nil)
+	if err != nil {
+		return 0, false
+	}
+	return rowCount * selectivity, true
+}
+
+// hasIndexPrefixedBy checks whether the rows of the DataSource can be looked up by the column, by the int handle or an
+// index whose first column is it.
+func (ds *DataSource) hasIndexPrefixedBy(col *expression.Column) bool {
+	if ds.tableInfo.PKIsHandle && mysql.HasPriKeyFlag(col.RetType.Flag) {
+		return true
+	}
+	for _, path := range ds.possibleAccessPaths {
+		if path.Index == nil || len(path.Index.Columns) == 0 {
+			continue
+		}
+		if ds.tableInfo.Columns[path.Index.Columns[0].Offset].ID == col.ID {
+			return true
+		}
+	}
+	return false
+}
+
 // canPullUp checks if an aggregation can be pulled up. An aggregate function like count(*) cannot be pulled up.
 func (la *LogicalAggregation) canPullUp() bool {
 	if len(la.GroupByItems) > 0 {
@@ -164,7 +492,19 @@ func (s *decorrelateSolver) optimize(ctx context.Context, p LogicalPlan) (Logica
 				return proj, nil
 			}
 			return s.optimize(ctx, p)
-		} else if agg, ok := innerPlan.(*LogicalAggregation); ok {
+		} else if agg, ok := innerPlan.(*LogicalAggregation); ok && apply.ctx.GetSessionVars().GetDecorrelateScalarSubquery() &&
+			!apply.preferApplyForAgg(agg) {
+			// The equal correlated conditions can be pulled up as join keys below, so we only rewrite the aggregation
+			// into a window when there are other correlated conditions.
+			if apply.ctx.GetSessionVars().DecorrelateNonEqAgg && apply.hasNonEqCorCondsBelowAgg(agg) {
+				np, err := apply.rewriteNonEqCorAggToWindow(agg)
+				if err != nil {
+					return nil, err
+				}
+				if np != nil {
+					return s.optimize(ctx, np)
+				}
+			}
 			if apply.canPullUpAgg() && agg.canPullUp() {
 				innerPlan = agg.children[0]
 				apply.JoinType = LeftOuterJoin
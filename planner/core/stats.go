@@ -14,19 +14,24 @@
 package core
 
 import (
+	"fmt"
 	"math"
 	"sort"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/planner/property"
 	"github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
+	"github.com/pingcap/tidb/util/tracing"
 	"go.uber.org/zap"
 )
 
@@ -122,6 +127,9 @@ func (p *baseLogicalPlan) recursiveDeriveStats(colGroups [][]*expression.Column)
 		childStats[i] = childProfile
 		childSchema[i] = child.Schema()
 	}
+	if tracer := p.ctx.GetSessionVars().StmtCtx.StatsTrace; tracer != nil {
+		defer tracer.EnterOperator(p.self.ExplainID().String())()
+	}
 	return p.self.DeriveStats(childStats, p.self.Schema(), childSchema, colGroups)
 }
 
@@ -223,6 +231,7 @@ func (ds *DataSource) initStats(colGroups [][]*expression.Column) {
 	if ds.statisticTable == nil {
 		ds.statisticTable = getStatsTable(ds.ctx, ds.tableInfo, ds.table.Meta().ID)
 	}
+	ds.syncLoadHistograms()
 	tableStats := &property.StatsInfo{
 		RowCount:     float64(ds.statisticTable.Count),
 		Cardinality:  make(map[int64]float64, ds.schema.Len()),
@@ -240,6 +249,52 @@ func (ds *DataSource) initStats(colGroups [][]*expression.Column) {
 	ds.TblColHists = ds.statisticTable.ID2UniqueID(ds.TblCols)
 }
 
+// syncLoadHistograms loads the unloaded histograms of the columns used by the filters, and of the indexes whose first
+// column is used by the filters, before they're used for the estimation. The wait time is bounded by
+// tidb_stats_load_sync_wait, the pseudo estimation is used for the columns and indexes if the loading times out.
+func (ds *DataSource) syncLoadHistograms() {
+	sessVars := ds.ctx.GetSessionVars()
+	if sessVars.StatsLoadSyncWait <= 0 || sessVars.InRestrictedSQL || ds.statisticTable.Pseudo {
+		return
+	}
+	statsHandle := domain.GetDomain(ds.ctx).StatsHandle()
+	if statsHandle == nil {
+		return
+	}
+	var colIDs, idxIDs []int64
+	condCols := make(map[int64]struct{})
+	for _, col := range expression.ExtractColumnsFromExpressions(nil, ds.pushedDownConds, nil) {
+		condCols[col.ID] = struct{}{}
+		if c, ok := ds.statisticTable.Columns[col.ID]; ok && c.IsLoadNeeded() {
+			colIDs = append(colIDs, col.ID)
+		}
+	}
+	for _, path := range ds.possibleAccessPaths {
+		if path.IsTablePath() || path.Index == nil {
+			continue
+		}
+		idx, ok := ds.statisticTable.Indices[path.Index.ID]
+		if !ok || !idx.IsLoadNeeded() {
+			continue
+		}
+		if _, ok := condCols[ds.tableInfo.Columns[path.Index.Columns[0].Offset].ID]; ok {
+			idxIDs = append(idxIDs, path.Index.ID)
+		}
+	}
+	if len(colIDs) == 0 && len(idxIDs) == 0 {
+		return
+	}
+	wait := time.Duration(sessVars.StatsLoadSyncWait) * time.Millisecond
+	statsTbl, err := statsHandle.SyncLoadHistograms(ds.tableInfo, ds.statisticTable.PhysicalID, colIDs, idxIDs, wait)
+	if err != nil {
+		sessVars.StmtCtx.AppendWarning(err)
+		return
+	}
+	if !statsTbl.Pseudo && statsTbl.Count > 0 {
+		ds.statisticTable = statsTbl
+	}
+}
+
 func (ds *DataSource) deriveStatsByFilter(conds expression.CNFExprs, filledPaths []*util.AccessPath) *property.StatsInfo {
 	selectivity, nodes, err := ds.tableStats.HistColl.Selectivity(ds.ctx, conds, filledPaths)
 	if err != nil {
@@ -247,6 +302,10 @@ func (ds *DataSource) deriveStatsByFilter(conds expression.CNFExprs, filledPaths
 		selectivity = SelectionFactor
 	}
 	stats := ds.tableStats.Scale(selectivity)
+	if tracer := ds.ctx.GetSessionVars().StmtCtx.StatsTrace; tracer != nil {
+		tracer.Record("filter", ds.tableInfo.Name.O, stats.RowCount, "conditions: %s, table rows: %.4f * selectivity: %.4f",
+			expression.SortedExplainExpressionList(conds), ds.tableStats.RowCount, selectivity)
+	}
 	if ds.ctx.GetSessionVars().OptimizerSelectivityLevel >= 1 {
 		stats.HistColl = stats.HistColl.NewHistCollBySelectivity(ds.ctx.GetSessionVars().StmtCtx, nodes)
 	}
@@ -285,8 +344,10 @@ func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 			if err != nil {
 				return nil, err
 			}
+			ds.traceAccessPath(path)
 			// If we have point or empty range, just remove other possible paths.
 			if noIntervalRanges || len(path.Ranges) == 0 {
+				ds.tracePrunedByHeuristics(path)
 				ds.possibleAccessPaths[0] = path
 				ds.possibleAccessPaths = ds.possibleAccessPaths[:1]
 				ds.ctx.GetSessionVars().StmtCtx.OptimDependOnMutableConst = true
@@ -295,8 +356,10 @@ func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 			continue
 		}
 		noIntervalRanges := ds.deriveIndexPathStats(path, ds.pushedDownConds, false)
+		ds.traceAccessPath(path)
 		// If we have empty range, or point range on unique index, just remove other possible paths.
 		if (noIntervalRanges && path.Index.Unique) || len(path.Ranges) == 0 {
+			ds.tracePrunedByHeuristics(path)
 			ds.possibleAccessPaths[0] = path
 			ds.possibleAccessPaths = ds.possibleAccessPaths[:1]
 			ds.ctx.GetSessionVars().StmtCtx.OptimDependOnMutableConst = true
@@ -344,6 +407,12 @@ func (ds *DataSource) generateAndPruneIndexMergePath(needPrune bool) error {
 	if err != nil {
 		return err
 	}
+	// The intersection type IndexMerge is considered only if no union type IndexMerge is generated.
+	if regularPathCount == len(ds.possibleAccessPaths) {
+		if indexMergeAndPath := ds.generateIndexMergeAndPaths(regularPathCount); indexMergeAndPath != nil {
+			ds.possibleAccessPaths = append(ds.possibleAccessPaths, indexMergeAndPath)
+		}
+	}
 	// If without hints, it means that `enableIndexMerge` is true
 	if len(ds.indexMergeHints) == 0 {
 		return nil
@@ -361,6 +430,49 @@ func (ds *DataSource) generateAndPruneIndexMergePath(needPrune bool) error {
 	return nil
 }
 
+// traceAccessPath records the row count estimated for the access path.
+func (ds *DataSource) traceAccessPath(path *util.AccessPath) {
+	tracer := ds.ctx.GetSessionVars().StmtCtx.StatsTrace
+	if tracer == nil {
+		return
+	}
+	object := ds.tableInfo.Name.O
+	if path.Index != nil {
+		object = path.Index.Name.O
+	}
+	if len(path.IndexFilters) > 0 {
+		tracer.Record("access path", object, path.CountAfterAccess, "access conditions: %s, index filters: %s, rows after index filters: %.4f",
+			expression.SortedExplainExpressionList(path.AccessConds), expression.SortedExplainExpressionList(path.IndexFilters), path.CountAfterIndex)
+		return
+	}
+	tracer.Record("access path", object, path.CountAfterAccess, "access conditions: %s", expression.SortedExplainExpressionList(path.AccessConds))
+}
+
+// tracePrunedByHeuristics records the other access paths which are pruned because `kept` has point or empty ranges.
+func (ds *DataSource) tracePrunedByHeuristics(kept *util.AccessPath) {
+	if ds.ctx.GetSessionVars().StmtCtx.IndexCandidatesTrace == nil {
+		return
+	}
+	reason := fmt.Sprintf("%s has point ranges", accessPathName(kept))
+	if len(kept.Ranges) == 0 {
+		reason = fmt.Sprintf("%s has empty ranges", accessPathName(kept))
+	}
+	emptyProp := &property.PhysicalProperty{}
+	for _, path := range ds.possibleAccessPaths {
+		if path == kept {
+			continue
+		}
+		var candidate *candidatePath
+		if path.IsTablePath() {
+			candidate = ds.getTableCandidate(path, emptyProp)
+		} else {
+			coveredByIdx := ds.isCoveringIndex(ds.schema.Columns, path.FullIdxCols, path.FullIdxColLens, ds.tableInfo)
+			candidate = ds.getIndexCandidate(path, emptyProp, coveredByIdx)
+		}
+		ds.traceIndexCandidate(nil, candidate, "", tracing.IndexCandidatePruned, reason)
+	}
+}
+
 // DeriveStats implements LogicalPlan DeriveStats interface.
 func (ts *LogicalTableScan) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, _ [][]*expression.Column) (_ *property.StatsInfo, err error) {
 	ts.Source.initStats(nil)
@@ -478,6 +590,76 @@ func (ds *DataSource) generateIndexMergeOrPaths() error {
 	return nil
 }
 
+// generateIndexMergeAndPaths generates the IndexMerge path which intersects the handles fetched by the index paths,
+// e.g. `a = 1 and b = 2` on the separate indexes of a and b. It's only generated when the indexes are specified in
+// the IndexMerge hints, since the cost of the intersection isn't considered well now.
+func (ds *DataSource) generateIndexMergeAndPaths(regularPathCount int) *util.AccessPath {
+	var indexPaths []*util.AccessPath
+	for i := 0; i < regularPathCount; i++ {
+		path := ds.possibleAccessPaths[i]
+		if path.IsTablePath() || len(path.AccessConds) == 0 || ranger.HasFullRange(path.Ranges) {
+			continue
+		}
+		if !ds.isSpecifiedInIndexMergeHints(path.Index.Name.L) {
+			continue
+		}
+		indexPaths = append(indexPaths, path)
+	}
+	if len(indexPaths) < 2 {
+		return nil
+	}
+	sc := ds.ctx.GetSessionVars().StmtCtx
+	// The conditions which can't be evaluated by any partial path are left in the table filters of every index path.
+	filterCounts := make(map[string]int)
+	for _, path := range indexPaths {
+		for _, filter := range path.TableFilters {
+			filterCounts[string(filter.HashCode(sc))]++
+		}
+	}
+	var tableFilters []expression.Expression
+	for _, filter := range indexPaths[0].TableFilters {
+		if filterCounts[string(filter.HashCode(sc))] == len(indexPaths) {
+			tableFilters = append(tableFilters, filter)
+		}
+	}
+	partialPaths := make([]*util.AccessPath, 0, len(indexPaths))
+	partialConds := make([]expression.Expression, 0, len(indexPaths))
+	for _, path := range indexPaths {
+		partialPath := *path
+		partialPath.TableFilters = nil
+		partialPaths = append(partialPaths, &partialPath)
+		partialConds = append(partialConds, path.AccessConds...)
+		partialConds = append(partialConds, path.IndexFilters...)
+	}
+	indexMergePath := &util.AccessPath{
+		PartialIndexPaths:        partialPaths,
+		IndexMergeIsIntersection: true,
+		TableFilters:             tableFilters,
+	}
+	sel, _, err := ds.tableStats.HistColl.Selectivity(ds.ctx, expression.RemoveDupExprs(ds.ctx, partialConds), nil)
+	if err != nil {
+		logutil.BgLogger().Debug("something wrong happened, use the default selectivity", zap.Error(err))
+		sel = SelectionFactor
+	}
+	indexMergePath.CountAfterAccess = sel * ds.tableStats.RowCount
+	return indexMergePath
+}
+
+// isSpecifiedInIndexMergeHints checks whether the index is specified in the IndexMerge hints explicitly.
+func (ds *DataSource) isSpecifiedInIndexMergeHints(name string) bool {
+	for _, hint := range ds.indexMergeHints {
+		if hint.indexHint == nil {
+			continue
+		}
+		for _, hintName := range hint.indexHint.IndexNames {
+			if name == hintName.L {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // isInIndexMergeHints checks whether current index or primary key is in IndexMerge hints.
 func (ds *DataSource) isInIndexMergeHints(name string) bool {
 	if len(ds.indexMergeHints) == 0 {
@@ -607,6 +789,10 @@ func (p *LogicalSelection) DeriveStats(childStats []*property.StatsInfo, selfSch
 	}
 	p.stats = childStats[0].Scale(SelectionFactor)
 	p.stats.GroupNDVs = nil
+	if tracer := p.ctx.GetSessionVars().StmtCtx.StatsTrace; tracer != nil {
+		tracer.Record("selection", "", p.stats.RowCount, "conditions: %s, child rows: %.4f * selection factor: %.2f",
+			expression.SortedExplainExpressionList(p.Conditions), childStats[0].RowCount, SelectionFactor)
+	}
 	return p.stats, nil
 }
 
@@ -644,6 +830,9 @@ func (p *LogicalLimit) DeriveStats(childStats []*property.StatsInfo, selfSchema
 		return p.stats, nil
 	}
 	p.stats = deriveLimitStats(childStats[0], float64(p.Count))
+	if tracer := p.ctx.GetSessionVars().StmtCtx.StatsTrace; tracer != nil {
+		tracer.Record("limit", "", p.stats.RowCount, "min(count: %d, child rows: %.4f)", p.Count, childStats[0].RowCount)
+	}
 	return p.stats, nil
 }
 
@@ -653,6 +842,9 @@ func (lt *LogicalTopN) DeriveStats(childStats []*property.StatsInfo, selfSchema
 		return lt.stats, nil
 	}
 	lt.stats = deriveLimitStats(childStats[0], float64(lt.Count))
+	if tracer := lt.ctx.GetSessionVars().StmtCtx.StatsTrace; tracer != nil {
+		tracer.Record("limit", "", lt.stats.RowCount, "min(count: %d, child rows: %.4f)", lt.Count, childStats[0].RowCount)
+	}
 	return lt.stats, nil
 }
 
@@ -679,6 +871,23 @@ func getGroupNDV4Cols(cols []*expression.Column, stats *property.StatsInfo) *pro
 	return nil
 }
 
+// cardinalityMethod describes how getCardinality estimates the cardinality of the columns for the trace.
+func cardinalityMethod(cols []*expression.Column, profile *property.StatsInfo) string {
+	if getGroupNDV4Cols(cols, profile) != nil {
+		return "NDV of the column group"
+	}
+	return "max NDV of the columns"
+}
+
+// gbyExprs converts the group by columns to expressions for the trace.
+func gbyExprs(cols []*expression.Column) []expression.Expression {
+	exprs := make([]expression.Expression, 0, len(cols))
+	for _, col := range cols {
+		exprs = append(exprs, col)
+	}
+	return exprs
+}
+
 // getCardinality returns the Cardinality of a couple of columns.
 // If the columns match any GroupNDV maintained by child operator, we can get an accurate cardinality.
 // Otherwise, we simply return the max cardinality among the columns, which is a lower bound.
@@ -816,6 +1025,10 @@ func (la *LogicalAggregation) DeriveStats(childStats []*property.StatsInfo, self
 		return la.stats, nil
 	}
 	cardinality := getCardinality(gbyCols, childSchema[0], childProfile)
+	if tracer := la.ctx.GetSessionVars().StmtCtx.StatsTrace; tracer != nil {
+		tracer.Record("aggregation", "", cardinality, "group by columns: %s, %s", expression.SortedExplainExpressionList(gbyExprs(gbyCols)),
+			cardinalityMethod(gbyCols, childProfile))
+	}
 	la.stats = &property.StatsInfo{
 		RowCount:    cardinality,
 		Cardinality: make(map[int64]float64, selfSchema.Len()),
@@ -876,6 +1089,7 @@ func (p *LogicalJoin) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 	leftProfile, rightProfile := childStats[0], childStats[1]
 	leftJoinKeys, rightJoinKeys, _, _ := p.GetJoinKeys()
 	helper := &fullJoinRowCountHelper{
+		sctx:          p.ctx,
 		cartesian:     0 == len(p.EqualConditions),
 		leftProfile:   leftProfile,
 		rightProfile:  rightProfile,
@@ -885,6 +1099,10 @@ func (p *LogicalJoin) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 		rightSchema:   childSchema[1],
 	}
 	p.equalCondOutCnt = helper.estimate()
+	tracer := p.ctx.GetSessionVars().StmtCtx.StatsTrace
+	if tracer != nil {
+		helper.trace(tracer, p.equalCondOutCnt)
+	}
 	if p.JoinType == SemiJoin || p.JoinType == AntiSemiJoin {
 		p.stats = &property.StatsInfo{
 			RowCount:    leftProfile.RowCount * SelectionFactor,
@@ -893,6 +1111,9 @@ func (p *LogicalJoin) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 		for id, c := range leftProfile.Cardinality {
 			p.stats.Cardinality[id] = c * SelectionFactor
 		}
+		if tracer != nil {
+			tracer.Record("join", p.JoinType.String(), p.stats.RowCount, "left rows: %.4f * selection factor: %.2f", leftProfile.RowCount, SelectionFactor)
+		}
 		return p.stats, nil
 	}
 	if p.JoinType == LeftOuterSemiJoin || p.JoinType == AntiLeftOuterSemiJoin {
@@ -905,13 +1126,22 @@ func (p *LogicalJoin) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 		}
 		p.stats.Cardinality[selfSchema.Columns[selfSchema.Len()-1].UniqueID] = 2.0
 		p.stats.GroupNDVs = p.getGroupNDVs(colGroups, childStats)
+		if tracer != nil {
+			tracer.Record("join", p.JoinType.String(), p.stats.RowCount, "left rows: %.4f", leftProfile.RowCount)
+		}
 		return p.stats, nil
 	}
 	count := p.equalCondOutCnt
 	if p.JoinType == LeftOuterJoin {
 		count = math.Max(count, leftProfile.RowCount)
+		if tracer != nil {
+			tracer.Record("join", p.JoinType.String(), count, "max(equal condition rows: %.4f, left rows: %.4f)", p.equalCondOutCnt, leftProfile.RowCount)
+		}
 	} else if p.JoinType == RightOuterJoin {
 		count = math.Max(count, rightProfile.RowCount)
+		if tracer != nil {
+			tracer.Record("join", p.JoinType.String(), count, "max(equal condition rows: %.4f, right rows: %.4f)", p.equalCondOutCnt, rightProfile.RowCount)
+		}
 	}
 	cardinality := make(map[int64]float64, selfSchema.Len())
 	for id, c := range leftProfile.Cardinality {
@@ -955,6 +1185,7 @@ func (p *LogicalJoin) ExtractColGroups(colGroups [][]*expression.Column) [][]*ex
 }
 
 type fullJoinRowCountHelper struct {
+	sctx          sessionctx.Context
 	cartesian     bool
 	leftProfile   *property.StatsInfo
 	rightProfile  *property.StatsInfo
@@ -962,18 +1193,74 @@ type fullJoinRowCountHelper struct {
 	rightJoinKeys []*expression.Column
 	leftSchema    *expression.Schema
 	rightSchema   *expression.Schema
+
+	// byHistograms indicates whether the row count is estimated by aligning the histograms of the join keys.
+	byHistograms bool
 }
 
 func (h *fullJoinRowCountHelper) estimate() float64 {
 	if h.cartesian {
 		return h.leftProfile.RowCount * h.rightProfile.RowCount
 	}
+	if count, ok := h.estimateByHistograms(); ok {
+		h.byHistograms = true
+		return count
+	}
 	leftKeyCardinality := getCardinality(h.leftJoinKeys, h.leftSchema, h.leftProfile)
 	rightKeyCardinality := getCardinality(h.rightJoinKeys, h.rightSchema, h.rightProfile)
 	count := h.leftProfile.RowCount * h.rightProfile.RowCount / math.Max(leftKeyCardinality, rightKeyCardinality)
 	return count
 }
 
+// estimateByHistograms estimates the row count of the equi-join on a single column by aligning the histograms of the
+// join keys, the row count of the full columns is scaled by the selectivity of each side.
+func (h *fullJoinRowCountHelper) estimateByHistograms() (float64, bool) {
+	if h.sctx == nil || !h.sctx.GetSessionVars().EnableHistogramJoinEstimation || len(h.leftJoinKeys) != 1 {
+		return 0, false
+	}
+	leftCol := joinKeyColumnStats(h.leftJoinKeys[0], h.leftProfile)
+	rightCol := joinKeyColumnStats(h.rightJoinKeys[0], h.rightProfile)
+	if leftCol == nil || rightCol == nil {
+		return 0, false
+	}
+	count, ok := statistics.EquiJoinRowCount(h.sctx.GetSessionVars().StmtCtx, leftCol, rightCol)
+	if !ok {
+		return 0, false
+	}
+	count *= h.leftProfile.RowCount / leftCol.TotalRowCount() * h.rightProfile.RowCount / rightCol.TotalRowCount()
+	return count, true
+}
+
+// joinKeyColumnStats returns the statistics of the join key if its histogram is available.
+func joinKeyColumnStats(key *expression.Column, profile *property.StatsInfo) *statistics.Column {
+	if profile.HistColl == nil || profile.HistColl.Pseudo {
+		return nil
+	}
+	c, ok := profile.HistColl.Columns[key.UniqueID]
+	if !ok || c.IsInvalid(nil, false) || c.Histogram.Len() == 0 {
+		return nil
+	}
+	return c
+}
+
+// trace records how the row count of the equal conditions is estimated.
+func (h *fullJoinRowCountHelper) trace(tracer *tracing.StatsTracer, count float64) {
+	if h.cartesian {
+		tracer.Record("join", "cartesian", count, "left rows: %.4f * right rows: %.4f", h.leftProfile.RowCount, h.rightProfile.RowCount)
+		return
+	}
+	if h.byHistograms {
+		tracer.Record("join", "equal conditions", count, "aligned histograms of %s and %s, scaled by left rows: %.4f, right rows: %.4f",
+			h.leftJoinKeys[0], h.rightJoinKeys[0], h.leftProfile.RowCount, h.rightProfile.RowCount)
+		return
+	}
+	leftKeyCardinality := getCardinality(h.leftJoinKeys, h.leftSchema, h.leftProfile)
+	rightKeyCardinality := getCardinality(h.rightJoinKeys, h.rightSchema, h.rightProfile)
+	tracer.Record("join", "equal conditions", count, "left rows: %.4f * right rows: %.4f / max(left keys NDV: %.4f by %s, right keys NDV: %.4f by %s)",
+		h.leftProfile.RowCount, h.rightProfile.RowCount, leftKeyCardinality, cardinalityMethod(h.leftJoinKeys, h.leftProfile),
+		rightKeyCardinality, cardinalityMethod(h.rightJoinKeys, h.rightProfile))
+}
+
 func (la *LogicalApply) getGroupNDVs(colGroups [][]*expression.Column, childStats []*property.StatsInfo) []property.GroupNDV {
 	if len(colGroups) > 0 && (la.JoinType == LeftOuterSemiJoin || la.JoinType == AntiLeftOuterSemiJoin || la.JoinType == LeftOuterJoin) {
 		return childStats[0].GroupNDVs
@@ -89,6 +89,10 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 		}()
 	}
 
+	if restore := injectSQLHints(sctx, node); restore != nil {
+		defer restore()
+	}
+
 	tableHints := hint.ExtractTableHintsFromStmtNode(node, sctx)
 	stmtHints, warns := handleStmtHints(tableHints)
 	sessVars.StmtCtx.StmtHints = stmtHints
@@ -340,6 +344,60 @@ func extractSelectAndNormalizeDigest(stmtNode ast.StmtNode, specifiledDB string)
 	return nil, "", "", nil
 }
 
+// injectSQLHints appends the hints set by sql_hints for the digest of the statement to the hints of its outermost
+// query block, the returned function restores the hints of the statement.
+func injectSQLHints(sctx sessionctx.Context, node ast.Node) func() {
+	sessVars := sctx.GetSessionVars()
+	if len(sessVars.SQLHints) == 0 || sessVars.InRestrictedSQL {
+		return nil
+	}
+	var stmtNode ast.Node
+	var digest *parser.Digest
+	switch x := node.(type) {
+	case *ast.ExplainStmt:
+		// The explained statement has no text of its own, and its restored SQL contains the database names filled by
+		// the preprocessor, so its normalized SQL is cut from the normalized explain SQL at its first word.
+		stmtNode = x.Stmt
+		words := strings.Fields(parser.Normalize(utilparser.RestoreWithDefaultDB(x.Stmt, "", "")))
+		if len(words) == 0 {
+			return nil
+		}
+		normalizedSQL := parser.Normalize(x.Text())
+		idx := strings.Index(normalizedSQL, " "+words[0]+" ")
+		if idx == -1 {
+			return nil
+		}
+		digest = parser.DigestNormalized(normalizedSQL[idx+1:])
+	case ast.StmtNode:
+		stmtNode = x
+		_, digest = parser.NormalizeDigest(x.Text())
+	default:
+		return nil
+	}
+	hints, ok := sessVars.SQLHints[digest.String()]
+	if !ok {
+		return nil
+	}
+	var tableHints *[]*ast.TableOptimizerHint
+	switch x := stmtNode.(type) {
+	case *ast.SelectStmt:
+		tableHints = &x.TableHints
+	case *ast.UpdateStmt:
+		tableHints = &x.TableHints
+	case *ast.DeleteStmt:
+		tableHints = &x.TableHints
+	case *ast.InsertStmt:
+		tableHints = &x.TableHints
+	default:
+		return nil
+	}
+	originHints := *tableHints
+	*tableHints = append(append(make([]*ast.TableOptimizerHint, 0, len(originHints)+len(hints)), originHints...), hints...)
+	return func() {
+		*tableHints = originHints
+	}
+}
+
 func getBindRecord(ctx sessionctx.Context, stmt ast.StmtNode) (*bindinfo.BindRecord, string, error) {
 	// When the domain is initializing, the bind will be nil.
 	if ctx.Value(bindinfo.SessionBindInfoKeyType) == nil {
@@ -46,6 +46,8 @@ type AccessPath struct {
 	// PartialIndexPaths store all index access paths.
 	// If there are extra filters, store them in TableFilters.
 	PartialIndexPaths []*AccessPath
+	// IndexMergeIsIntersection indicates whether the handles of the partial paths are intersected rather than united.
+	IndexMergeIsIntersection bool
 
 	StoreType kv.StoreType
 
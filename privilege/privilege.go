@@ -65,6 +65,9 @@ type Manager interface {
 	// UserPrivilegesTable provide data for INFORMATION_SCHEMA.USER_PRIVILEGES table.
 	UserPrivilegesTable() [][]types.Datum
 
+	// EffectivePrivilegesTable provide data for INFORMATION_SCHEMA.TIDB_EFFECTIVE_PRIVILEGES table.
+	EffectivePrivilegesTable() [][]types.Datum
+
 	// ActiveRoles active roles for current session.
 	// The first illegal role will be returned.
 	ActiveRoles(ctx sessionctx.Context, roleList []*auth.RoleIdentity) (bool, string)
@@ -83,6 +86,9 @@ type Manager interface {
 
 	// Get the authentication plugin for a user
 	GetAuthPlugin(user, host string) (string, error)
+
+	// GetResourceLimits gets the MAX_QUERIES_PER_HOUR and MAX_UPDATES_PER_HOUR limits of the account, 0 means no limit.
+	GetResourceLimits(user, host string) (maxQueries, maxUpdates int64)
 }
 
 const key keyType = 0
@@ -63,7 +63,7 @@ const (
 	References_priv,Alter_priv,Execute_priv,Index_priv,Create_view_priv,Show_view_priv,
 	Create_role_priv,Drop_role_priv,Create_tmp_table_priv,Lock_tables_priv,Create_routine_priv,
 	Alter_routine_priv,Event_priv,Shutdown_priv,Reload_priv,File_priv,Config_priv,Repl_client_priv,Repl_slave_priv,
-	account_locked,plugin FROM mysql.user`
+	account_locked,plugin,max_questions,max_updates FROM mysql.user`
 	sqlLoadGlobalGrantsTable = `SELECT HIGH_PRIORITY Host,User,Priv,With_Grant_Option FROM mysql.global_grants`
 )
 
@@ -97,6 +97,9 @@ type UserRecord struct {
 	Privileges           mysql.PrivilegeType
 	AccountLocked        bool // A role record when this field is true
 	AuthPlugin           string
+	// MaxQueries and MaxUpdates are the MAX_QUERIES_PER_HOUR and MAX_UPDATES_PER_HOUR resource limits, 0 means no limit.
+	MaxQueries int64
+	MaxUpdates int64
 }
 
 // NewUserRecord return a UserRecord, only use for unit test.
@@ -639,6 +642,10 @@ func (p *MySQLPrivilege) decodeUserTableRow(row chunk.Row, fs []*ast.ResultField
 			} else {
 				value.AuthPlugin = mysql.AuthNativePassword
 			}
+		case f.ColumnAsName.L == "max_questions":
+			value.MaxQueries = int64(row.GetUint64(i))
+		case f.ColumnAsName.L == "max_updates":
+			value.MaxUpdates = int64(row.GetUint64(i))
 		case f.Column.Tp == mysql.TypeEnum:
 			if row.GetEnum(i).String() != "Y" {
 				continue
@@ -900,6 +907,17 @@ func (p *MySQLPrivilege) connectionVerification(user, host string) *UserRecord {
 	return nil
 }
 
+// findUser finds the user record of the account, unlike matchUser, the host is not matched as a pattern.
+func (p *MySQLPrivilege) findUser(user, host string) *UserRecord {
+	records := p.UserMap[user]
+	for i := 0; i < len(records); i++ {
+		if records[i].Host == host {
+			return &records[i]
+		}
+	}
+	return nil
+}
+
 func (p *MySQLPrivilege) matchGlobalPriv(user, host string) *globalPrivRecord {
 	uGlobal, exists := p.Global[user]
 	if !exists {
@@ -1472,6 +1490,107 @@ func appendUserPrivilegesTableRow(rows [][]types.Datum, user UserRecord) [][]typ
 	return rows
 }
 
+// rolePath is an account or a role granted to it transitively.
+type rolePath struct {
+	user, host string
+	// path is the chain of the roles from the account to the role, it's empty for the account itself.
+	path []string
+}
+
+// findAllRolePaths finds the account and all the roles granted to it transitively, along with
+// the shortest chain of the roles through which each role is granted.
+func (p *MySQLPrivilege) findAllRolePaths(user, host string) []rolePath {
+	ret := []rolePath{{user: user, host: host}}
+	visited := map[string]bool{user + "@" + host: true}
+	// Using breadth first search so that the shortest chain is found for each role.
+	for head := 0; head < len(ret); head++ {
+		cur := ret[head]
+		edgeTable, ok := p.RoleGraph[cur.user+"@"+cur.host]
+		if !ok {
+			continue
+		}
+		keys := make([]string, 0, len(edgeTable.roleList))
+		for k := range edgeTable.roleList {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if visited[k] {
+				continue
+			}
+			visited[k] = true
+			r := edgeTable.roleList[k]
+			path := make([]string, 0, len(cur.path)+1)
+			path = append(path, cur.path...)
+			path = append(path, fmt.Sprintf("'%s'@'%s'", r.Username, r.Hostname))
+			ret = append(ret, rolePath{user: r.Username, host: r.Hostname, path: path})
+		}
+	}
+	return ret
+}
+
+// EffectivePrivilegesTable provide data for INFORMATION_SCHEMA.TIDB_EFFECTIVE_PRIVILEGES table.
+// Besides the privileges granted to each account directly, the privileges inherited from all the
+// roles granted to it transitively are listed along with the role and the chain of roles they come from.
+func (p *MySQLPrivilege) EffectivePrivilegesTable() [][]types.Datum {
+	var rows [][]types.Datum
+	for _, user := range p.User {
+		for _, rp := range p.findAllRolePaths(user.User, user.Host) {
+			rows = p.appendEffectivePrivileges(rows, user.User, user.Host, rp)
+		}
+	}
+	return rows
+}
+
+func (p *MySQLPrivilege) appendEffectivePrivileges(rows [][]types.Datum, user, host string, rp rolePath) [][]types.Datum {
+	var grantedBy, path interface{}
+	if len(rp.path) > 0 {
+		grantedBy = rp.path[len(rp.path)-1]
+		path = strings.Join(rp.path, " -> ")
+	}
+	appendRows := func(db, table, column interface{}, privs mysql.PrivilegeType, allPrivs []mysql.PrivilegeType) {
+		isGrantable := "NO"
+		if privs&mysql.GrantPriv > 0 {
+			isGrantable = "YES"
+		}
+		for _, priv := range allPrivs {
+			if privs&priv > 0 {
+				rows = append(rows, types.MakeDatums(user, host, db, table, column, strings.ToUpper(mysql.Priv2Str[priv]), isGrantable, grantedBy, path))
+			}
+		}
+	}
+	for _, record := range p.User {
+		if record.fullyMatch(rp.user, rp.host) {
+			appendRows(nil, nil, nil, record.Privileges, mysql.AllGlobalPrivs)
+		}
+	}
+	for _, record := range p.DB {
+		if record.fullyMatch(rp.user, rp.host) {
+			appendRows(record.DB, nil, nil, record.Privileges, mysql.AllDBPrivs)
+		}
+	}
+	for _, record := range p.TablesPriv {
+		if record.fullyMatch(rp.user, rp.host) {
+			appendRows(record.DB, record.TableName, nil, record.TablePriv, mysql.AllTablePrivs)
+		}
+	}
+	for _, record := range p.ColumnsPriv {
+		if record.fullyMatch(rp.user, rp.host) {
+			appendRows(record.DB, record.TableName, record.ColumnName, record.ColumnPriv, mysql.AllColumnPrivs)
+		}
+	}
+	for _, record := range p.Dynamic[rp.user] {
+		if record.fullyMatch(rp.user, rp.host) {
+			isGrantable := "NO"
+			if record.GrantOption {
+				isGrantable = "YES"
+			}
+			rows = append(rows, types.MakeDatums(user, host, nil, nil, nil, record.PrivilegeName, isGrantable, grantedBy, path))
+		}
+	}
+	return rows
+}
+
 func (p *MySQLPrivilege) getDefaultRoles(user, host string) []*auth.RoleIdentity {
 	ret := make([]*auth.RoleIdentity, 0)
 	for _, r := range p.DefaultRoles {
@@ -37,6 +37,10 @@ import (
 // SkipWithGrant causes the server to start without using the privilege system at all.
 var SkipWithGrant = false
 
+// PrivilegeChangesTable is the table in the mysql schema recording the account-management statements. It's
+// append-only, the rows are only inserted by the internal sessions, and no user can modify or drop it.
+const PrivilegeChangesTable = "privilege_changes"
+
 var _ privilege.Manager = (*UserPrivileges)(nil)
 var dynamicPrivs = []string{
 	"BACKUP_ADMIN",
@@ -50,6 +54,7 @@ var dynamicPrivs = []string{
 	"RESTRICTED_VARIABLES_ADMIN",  // Can see all variables when SEM is enabled
 	"RESTRICTED_USER_ADMIN",       // User can not have their access revoked by SUPER users.
 	"RESTRICTED_CONNECTION_ADMIN", // Can not be killed by PROCESS/CONNECTION_ADMIN privilege
+	"DDL_BREAK_GLASS",             // Can run DDL that is forbidden by a policy in mysql.ddl_policy
 }
 var dynamicPrivLock sync.Mutex
 
@@ -118,6 +123,13 @@ func (p *UserPrivileges) RequestVerification(activeRoles []*auth.RoleIdentity, d
 		}
 	}
 
+	if dbLowerName == mysql.SystemDB && tblLowerName == PrivilegeChangesTable {
+		switch priv {
+		case mysql.AlterPriv, mysql.DropPriv, mysql.IndexPriv, mysql.InsertPriv, mysql.UpdatePriv, mysql.DeletePriv:
+			return false
+		}
+	}
+
 	switch dbLowerName {
 	case util.InformationSchemaName.L:
 		switch priv {
@@ -220,6 +232,19 @@ func (p *UserPrivileges) GetAuthPlugin(user, host string) (string, error) {
 	return "", errors.New("Failed to get plugin for user")
 }
 
+// GetResourceLimits implements the Manager interface.
+func (p *UserPrivileges) GetResourceLimits(user, host string) (maxQueries, maxUpdates int64) {
+	if SkipWithGrant {
+		return 0, 0
+	}
+	mysqlPriv := p.Handle.Get()
+	record := mysqlPriv.findUser(user, host)
+	if record == nil {
+		return 0, 0
+	}
+	return record.MaxQueries, record.MaxUpdates
+}
+
 // GetAuthWithoutVerification implements the Manager interface.
 func (p *UserPrivileges) GetAuthWithoutVerification(user, host string) (u string, h string, success bool) {
 	if SkipWithGrant {
@@ -512,6 +537,12 @@ func (p *UserPrivileges) UserPrivilegesTable() [][]types.Datum {
 	return mysqlPriv.UserPrivilegesTable()
 }
 
+// EffectivePrivilegesTable implements the Manager interface.
+func (p *UserPrivileges) EffectivePrivilegesTable() [][]types.Datum {
+	mysqlPriv := p.Handle.Get()
+	return mysqlPriv.EffectivePrivilegesTable()
+}
+
 // ShowGrants implements privilege.Manager ShowGrants interface.
 func (p *UserPrivileges) ShowGrants(ctx sessionctx.Context, user *auth.UserIdentity, roles []*auth.RoleIdentity) (grants []string, err error) {
 	if SkipWithGrant {
@@ -70,6 +70,7 @@ import (
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/plugin"
 	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -95,6 +96,9 @@ const (
 	connStatusWaitShutdown // Notified by server to close.
 )
 
+// probeIdleTimeout is the read timeout of the probe connections, they are expected to ping frequently or quit soon.
+const probeIdleTimeout = 5 * time.Second
+
 var (
 	queryTotalCountOk = [...]prometheus.Counter{
 		mysql.ComSleep:            metrics.QueryTotalCounter.WithLabelValues("Sleep", "OK"),
@@ -185,6 +189,7 @@ type clientConn struct {
 	collation    uint8             // collation used by client, may be different from the collation used by database.
 	lastActive   time.Time         // last active time
 	authPlugin   string            // default authentication plugin
+	probe        bool              // whether the connection is a health check of the probe user, see runProbe.
 
 	// mu is used for cancelling the execution of current transaction.
 	mu struct {
@@ -678,6 +683,16 @@ func (cc *clientConn) readOptionalSSLRequestAndHandshakeResponse(ctx context.Con
 	cc.collation = resp.Collation
 	cc.attrs = resp.Attrs
 
+	if probeUser := config.GetGlobalConfig().Security.ProbeUser; len(probeUser) > 0 && cc.user == probeUser &&
+		cc.server.dom != nil && resp.AuthPlugin == mysql.AuthNativePassword {
+		// The probe connections are authenticated against the privilege cache and answered without the sessions.
+		err = cc.authProbe(resp.Auth)
+		if err != nil {
+			logutil.Logger(ctx).Warn("probe user authentication failure", zap.Error(err))
+		}
+		return err
+	}
+
 	newAuth, err := cc.checkAuthPlugin(ctx, &resp.AuthPlugin)
 	if err != nil {
 		logutil.Logger(ctx).Warn("failed to check the user authplugin", zap.Error(err))
@@ -700,8 +715,14 @@ func (cc *clientConn) readOptionalSSLRequestAndHandshakeResponse(ctx context.Con
 	err = cc.openSessionAndDoAuth(resp.Auth)
 	if err != nil {
 		logutil.Logger(ctx).Warn("open new session or authentication failure", zap.Error(err))
+		return err
 	}
-	return err
+	if probeUser := config.GetGlobalConfig().Security.ProbeUser; len(probeUser) > 0 && cc.user == probeUser {
+		// The probe connections authenticated with the sessions, e.g. by caching_sha2_password or before the
+		// domain is set, only answer COM_PING afterwards as well.
+		cc.probe = true
+	}
+	return nil
 }
 
 func (cc *clientConn) authSha(ctx context.Context) ([]byte, error) {
@@ -765,6 +786,34 @@ func (cc *clientConn) openSession() error {
 	return nil
 }
 
+// authProbe authenticates the connection of the probe user without opening a session. Only the peer IP or
+// localhost is matched against the host of the user, and the requested database is ignored.
+func (cc *clientConn) authProbe(authData []byte) error {
+	err := cc.server.checkConnectionCount()
+	if err != nil {
+		return err
+	}
+	hasPassword := "YES"
+	if len(authData) == 0 {
+		hasPassword = "NO"
+	}
+	host, _, err := cc.PeerHost(hasPassword)
+	if err != nil {
+		return err
+	}
+	var tlsState *tls.ConnectionState
+	if cc.tlsConn != nil {
+		state := cc.tlsConn.ConnectionState()
+		tlsState = &state
+	}
+	pm := &privileges.UserPrivileges{Handle: cc.server.dom.PrivilegeHandle()}
+	if _, _, success := pm.ConnectionVerification(cc.user, host, authData, cc.salt, tlsState); !success {
+		return errAccessDenied.FastGenByArgs(cc.user, host, hasPassword)
+	}
+	cc.probe = true
+	return nil
+}
+
 func (cc *clientConn) openSessionAndDoAuth(authData []byte) error {
 	// Open a context unless this was done before.
 	if cc.ctx == nil {
@@ -866,6 +915,9 @@ func (cc *clientConn) skipInitConnect() bool {
 // initConnect runs the initConnect SQL statement if it has been specified.
 // The semantics are MySQL compatible.
 func (cc *clientConn) initConnect(ctx context.Context) error {
+	if cc.probe {
+		return nil
+	}
 	val, err := cc.ctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.InitConnect)
 	if err != nil {
 		return err
@@ -904,6 +956,35 @@ func (cc *clientConn) initConnect(ctx context.Context) error {
 	return nil
 }
 
+// runProbe serves the connection of the probe user, which is usually the health check of the load balancers. It
+// only answers COM_PING, and the connection is closed on COM_QUIT, any other command or an idle of probeIdleTimeout.
+func (cc *clientConn) runProbe(ctx context.Context) {
+	defer func() {
+		terror.Log(cc.Close())
+	}()
+	for {
+		cc.alloc.Reset()
+		cc.pkt.setReadTimeout(probeIdleTimeout)
+		data, err := cc.readPacket()
+		if err != nil || len(data) == 0 {
+			return
+		}
+		switch data[0] {
+		case mysql.ComPing:
+			err = cc.writeOkWith(ctx, "", 0, 0, mysql.ServerStatusAutocommit, 0)
+		case mysql.ComQuit:
+			return
+		default:
+			terror.Log(cc.writeError(ctx, errNotAllowedCommand))
+			return
+		}
+		if err != nil {
+			return
+		}
+		cc.pkt.sequence = 0
+	}
+}
+
 // Run reads client query and writes query result to client in for loop, if there is a panic during query handling,
 // it will be recovered and log the panic error.
 // This function returns and the connection is closed if there is an IO error or there is a panic.
@@ -939,6 +1020,10 @@ func (cc *clientConn) Run(ctx context.Context) {
 			// The judge below will not be hit by all means,
 			// But keep it stayed as a reminder and for the code reference for connStatusWaitShutdown.
 			atomic.LoadInt32(&cc.status) == connStatusWaitShutdown {
+			// The connection finishing its transaction is closed by the graceful shutdown.
+			if atomic.LoadInt32(&cc.status) == connStatusWaitShutdown && cc.server != nil && cc.server.shouldRedirectClients() {
+				cc.writeShutdownError()
+			}
 			return
 		}
 
@@ -1007,6 +1092,15 @@ func (cc *clientConn) Run(ctx context.Context) {
 	}
 }
 
+// writeShutdownError tells the client that the server is shutting down, so the proxy in front of the server can
+// reconnect to other servers. The error is not a response of any command, so the sequence is reset.
+func (cc *clientConn) writeShutdownError() {
+	cc.pkt.sequence = 0
+	if err := cc.writeError(context.Background(), errServerShutdown); err != nil {
+		logutil.BgLogger().Debug("write shutdown error failed", zap.Uint64("conn", cc.connectionID), zap.Error(err))
+	}
+}
+
 // ShutdownOrNotify will Shutdown this client connection, or do its best to notify.
 func (cc *clientConn) ShutdownOrNotify() bool {
 	if (cc.ctx.Status() & mysql.ServerStatusInTrans) > 0 {
@@ -1677,6 +1771,9 @@ func (cc *clientConn) handleQuery(ctx context.Context, sql string) (err error) {
 			// Save the point plan in Session so we don't need to build the point plan again.
 			cc.ctx.SetValue(plannercore.PointPlanKey, plannercore.PointPlanVal{Plan: pointPlans[i]})
 		}
+		if err = cc.checkResourceLimits(stmt); err != nil {
+			break
+		}
 		retryable, err = cc.handleStmt(ctx, stmt, parserWarns, i == len(stmts)-1)
 		if err != nil {
 			_, allowTiFlashFallback := cc.ctx.GetSessionVars().AllowFallbackToTiKV[kv.TiFlash]
@@ -1803,6 +1900,7 @@ func (cc *clientConn) prefetchPointPlanKeys(ctx context.Context, stmts []ast.Stm
 // Currently the first return value is used to fallback to TiKV when TiFlash is down.
 func (cc *clientConn) handleStmt(ctx context.Context, stmt ast.StmtNode, warns []stmtctx.SQLWarn, lastStmt bool) (bool, error) {
 	ctx = context.WithValue(ctx, execdetails.StmtExecDetailKey, &execdetails.StmtExecDetails{})
+	ctx = context.WithValue(ctx, execdetails.StmtRPCStatsKey, execdetails.NewStmtRPCStats())
 	ctx = context.WithValue(ctx, util.ExecDetailsKey, &util.ExecDetails{})
 	reg := trace.StartRegion(ctx, "ExecuteStmt")
 	rs, err := cc.ctx.ExecuteStmt(ctx, stmt)
@@ -1971,6 +2069,7 @@ func (cc *clientConn) writeChunks(ctx context.Context, rs ResultSet, binary bool
 	req := rs.NewChunk()
 	gotColumnInfo := false
 	firstNext := true
+	var sentRows, sentBytes int64
 	var stmtDetail *execdetails.StmtExecDetails
 	stmtDetailRaw := ctx.Value(execdetails.StmtExecDetailKey)
 	if stmtDetailRaw != nil {
@@ -2021,6 +2120,15 @@ func (cc *clientConn) writeChunks(ctx context.Context, rs ResultSet, binary bool
 				reg.End()
 				return false, err
 			}
+			sentRows++
+			sentBytes += int64(len(data) - 4)
+			if err = cc.checkResultSize(sentRows, sentBytes); err != nil {
+				reg.End()
+				if err == errResultTruncated {
+					return false, cc.writeEOF(serverStatus)
+				}
+				return false, err
+			}
 			if err = cc.writePacket(data); err != nil {
 				reg.End()
 				return false, err
@@ -2034,6 +2142,27 @@ func (cc *clientConn) writeChunks(ctx context.Context, rs ResultSet, binary bool
 	return false, cc.writeEOF(serverStatus)
 }
 
+// errResultTruncated is returned by checkResultSize when the result set should be truncated.
+var errResultTruncated = errors.New("result set truncated")
+
+// checkResultSize checks the rows and bytes to be sent by the statement against tidb_max_result_rows
+// and tidb_max_result_bytes. When either is exceeded, it returns errMaxResultSizeExceeded with the
+// CANCEL action, or appends it as a warning and returns errResultTruncated with the TRUNCATE action.
+func (cc *clientConn) checkResultSize(rows, bytes int64) error {
+	sessVars := cc.ctx.GetSessionVars()
+	var err error
+	if sessVars.MaxResultRows > 0 && rows > sessVars.MaxResultRows {
+		err = errMaxResultSizeExceeded.GenWithStackByArgs(variable.TiDBMaxResultRows, sessVars.MaxResultRows)
+	} else if sessVars.MaxResultBytes > 0 && bytes > sessVars.MaxResultBytes {
+		err = errMaxResultSizeExceeded.GenWithStackByArgs(variable.TiDBMaxResultBytes, sessVars.MaxResultBytes)
+	}
+	if err == nil || sessVars.MaxResultAction == variable.MaxResultActionCancel {
+		return err
+	}
+	sessVars.StmtCtx.AppendWarning(err)
+	return errResultTruncated
+}
+
 // writeChunksWithFetchSize writes data from a Chunk, which filled data by a ResultSet, into a connection.
 // binary specifies the way to dump data. It throws any error while dumping data.
 // serverStatus, a flag bit represents server information.
@@ -2088,12 +2217,27 @@ func (cc *clientConn) writeChunksWithFetchSize(ctx context.Context, rs ResultSet
 	}
 	start := time.Now()
 	var err error
+	sentRows, sentBytes := rs.GetSentSize()
 	for _, row := range curRows {
 		data = data[0:4]
 		data, err = dumpBinaryRow(data, rs.Columns(), row)
 		if err != nil {
 			return err
 		}
+		sentRows++
+		sentBytes += int64(len(data) - 4)
+		rs.StoreSentSize(sentRows, sentBytes)
+		if err = cc.checkResultSize(sentRows, sentBytes); err != nil {
+			if err != errResultTruncated {
+				return err
+			}
+			// The cursor is closed since the rest of the result set is truncated.
+			serverStatus &^= mysql.ServerStatusCursorExists
+			serverStatus |= mysql.ServerStatusLastRowSend
+			rs.StoreFetchedRows(nil)
+			terror.Call(rs.Close)
+			return cc.writeEOF(serverStatus)
+		}
 		if err = cc.writePacket(data); err != nil {
 			return err
 		}
@@ -38,14 +38,18 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
+	"io"
 	"math"
+	"os"
 	"runtime/trace"
 	"strconv"
 	"time"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/metrics"
 	plannercore "github.com/pingcap/tidb/planner/core"
@@ -53,10 +57,14 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	storeerr "github.com/pingcap/tidb/store/driver/error"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/disk"
+	"github.com/pingcap/tidb/util/encrypt"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/hack"
+	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/topsql"
 	"github.com/tikv/client-go/v2/util"
+	"go.uber.org/zap"
 )
 
 func (cc *clientConn) handleStmtPrepare(ctx context.Context, sql string) error {
@@ -133,6 +141,8 @@ func (cc *clientConn) handleStmtExecute(ctx context.Context, data []byte) (err e
 	if variable.TopSQLEnabled() {
 		preparedStmt, _ := cc.preparedStmtID2CachePreparedStmt(stmtID)
 		if preparedStmt != nil && preparedStmt.SQLDigest != nil {
+			vars := cc.ctx.GetSessionVars()
+			ctx = topsql.AttachSessionInfo(ctx, vars.CurrentDB, vars.User)
 			ctx = topsql.AttachSQLInfo(ctx, preparedStmt.NormalizedSQL, preparedStmt.SQLDigest, "", nil)
 		}
 	}
@@ -197,14 +207,21 @@ func (cc *clientConn) handleStmtExecute(ctx context.Context, data []byte) (err e
 			paramValues = data[pos+1:]
 		}
 
-		err = parseExecArgs(cc.ctx.GetSessionVars().StmtCtx, args, stmt.BoundParams(), nullBitmaps, stmt.GetParamsType(), paramValues)
+		boundParams, err := stmt.BoundParams()
+		if err == nil {
+			err = parseExecArgs(cc.ctx.GetSessionVars().StmtCtx, args, boundParams, nullBitmaps, stmt.GetParamsType(), paramValues)
+		}
 		stmt.Reset()
 		if err != nil {
 			return errors.Annotate(err, cc.preparedStmt2String(stmtID))
 		}
 	}
 	ctx = context.WithValue(ctx, execdetails.StmtExecDetailKey, &execdetails.StmtExecDetails{})
+	ctx = context.WithValue(ctx, execdetails.StmtRPCStatsKey, execdetails.NewStmtRPCStats())
 	ctx = context.WithValue(ctx, util.ExecDetailsKey, &util.ExecDetails{})
+	if err = cc.checkResourceLimits(&ast.ExecuteStmt{ExecID: stmtID}); err != nil {
+		return err
+	}
 	retryable, err := cc.executePreparedStmtAndWriteResult(ctx, stmt, args, useCursor)
 	_, allowTiFlashFallback := cc.ctx.GetSessionVars().AllowFallbackToTiKV[kv.TiFlash]
 	if allowTiFlashFallback && err != nil && errors.ErrorEqual(err, storeerr.ErrTiFlashServerTimeout) && retryable {
@@ -277,6 +294,8 @@ func (cc *clientConn) handleStmtFetch(ctx context.Context, data []byte) (err err
 	if variable.TopSQLEnabled() {
 		prepareObj, _ := cc.preparedStmtID2CachePreparedStmt(stmtID)
 		if prepareObj != nil && prepareObj.SQLDigest != nil {
+			vars := cc.ctx.GetSessionVars()
+			ctx = topsql.AttachSessionInfo(ctx, vars.CurrentDB, vars.User)
 			ctx = topsql.AttachSQLInfo(ctx, prepareObj.NormalizedSQL, prepareObj.SQLDigest, "", nil)
 		}
 	}
@@ -623,21 +642,124 @@ func (cc *clientConn) handleStmtClose(data []byte) (err error) {
 	return
 }
 
+// handleStmtSendLongData handles COM_STMT_SEND_LONG_DATA, which has no response. Writing an error packet would
+// desynchronize the protocol, so the errors are kept by the statement and returned by COM_STMT_EXECUTE, and the
+// packets not belonging to any statement are ignored as MySQL does.
 func (cc *clientConn) handleStmtSendLongData(data []byte) (err error) {
 	if len(data) < 6 {
-		return mysql.ErrMalformPacket
+		logutil.BgLogger().Debug("malformed COM_STMT_SEND_LONG_DATA is ignored", zap.Uint64("conn", cc.connectionID))
+		return nil
 	}
 
 	stmtID := int(binary.LittleEndian.Uint32(data[0:4]))
 
 	stmt := cc.ctx.GetStatement(stmtID)
 	if stmt == nil {
-		return mysql.NewErr(mysql.ErrUnknownStmtHandler,
-			strconv.Itoa(stmtID), "stmt_send_longdata")
+		logutil.BgLogger().Debug("COM_STMT_SEND_LONG_DATA of an unknown statement is ignored",
+			zap.Uint64("conn", cc.connectionID), zap.Int("stmtID", stmtID))
+		return nil
 	}
 
 	paramID := int(binary.LittleEndian.Uint16(data[4:6]))
-	return stmt.AppendParam(paramID, data[6:])
+	stmt.AppendParam(paramID, data[6:])
+	return nil
+}
+
+// longDataMemLimit is the max size of a parameter sent by COM_STMT_SEND_LONG_DATA that is buffered in memory, the
+// parameter is spilled to a temporary file once it grows beyond the limit.
+var longDataMemLimit = 1 << 20
+
+const longDataFilePrefix = "server.longData"
+
+// longDataBuffer buffers the chunks of a parameter sent by COM_STMT_SEND_LONG_DATA. Large parameters are streamed to a
+// temporary file chunk by chunk, so receiving them doesn't keep re-allocating a growing buffer in memory. The executor
+// needs the parameter as a single value, so it's read back once into a buffer of the exact size when the statement is
+// executed, the size is limited by max_allowed_packet like the parameters sent by COM_STMT_EXECUTE.
+type longDataBuffer struct {
+	mem  []byte
+	size int64
+
+	file *os.File
+	w    io.Writer
+	// cipherWriter and ctrCipher are used when the spilled file needs to be encrypted.
+	cipherWriter *encrypt.Writer
+	ctrCipher    *encrypt.CtrCipher
+}
+
+func (b *longDataBuffer) append(data []byte) error {
+	if b.file == nil && len(b.mem)+len(data) <= longDataMemLimit {
+		b.mem = append(b.mem, data...)
+		b.size += int64(len(data))
+		return nil
+	}
+	if b.file == nil {
+		if err := b.initFile(); err != nil {
+			return err
+		}
+		if _, err := b.w.Write(b.mem); err != nil {
+			return errors.Trace(err)
+		}
+		b.mem = nil
+	}
+	if _, err := b.w.Write(data); err != nil {
+		return errors.Trace(err)
+	}
+	b.size += int64(len(data))
+	return nil
+}
+
+func (b *longDataBuffer) initFile() (err error) {
+	if err = disk.CheckAndInitTempDir(); err != nil {
+		return err
+	}
+	b.file, err = os.CreateTemp(config.GetGlobalConfig().TempStoragePath, longDataFilePrefix)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	b.w = b.file
+	if config.GetGlobalConfig().Security.SpilledFileEncryptionMethod != config.SpilledFileEncryptionMethodPlaintext {
+		b.ctrCipher, err = encrypt.NewCtrCipher()
+		if err != nil {
+			return err
+		}
+		b.cipherWriter = encrypt.NewWriter(b.file, b.ctrCipher)
+		b.w = b.cipherWriter
+	}
+	return nil
+}
+
+// bytes returns the whole parameter, the returned slice is never nil to distinguish an empty parameter from no
+// parameter.
+func (b *longDataBuffer) bytes() ([]byte, error) {
+	if b.file == nil {
+		if b.mem == nil {
+			return []byte{}, nil
+		}
+		return b.mem, nil
+	}
+	var r io.ReaderAt = b.file
+	if b.cipherWriter != nil {
+		if err := b.cipherWriter.Flush(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		r = encrypt.NewReader(b.file, b.ctrCipher)
+	}
+	data := make([]byte, b.size)
+	if _, err := io.ReadFull(io.NewSectionReader(r, 0, b.size), data); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return data, nil
+}
+
+// close releases the memory and removes the temporary file.
+func (b *longDataBuffer) close() {
+	if b.file != nil {
+		terror.Call(b.file.Close)
+		terror.Log(os.Remove(b.file.Name()))
+		b.file = nil
+	}
+	b.mem = nil
+	b.size = 0
 }
 
 func (cc *clientConn) handleStmtReset(ctx context.Context, data []byte) (err error) {
@@ -14,9 +14,13 @@
 package server
 
 import (
+	"bytes"
+	"os"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 )
@@ -222,3 +226,48 @@ func (ts *ConnTestSuite) TestParseStmtFetchCmd(c *C) {
 		c.Assert(err, Equals, t.err)
 	}
 }
+
+func (ts *ConnTestSuite) TestLongDataBuffer(c *C) {
+	defer config.RestoreFunc()()
+	oriLimit := longDataMemLimit
+	longDataMemLimit = 16
+	defer func() {
+		longDataMemLimit = oriLimit
+	}()
+
+	// No data is different from no parameter.
+	buf := &longDataBuffer{}
+	c.Assert(buf.append(nil), IsNil)
+	data, err := buf.bytes()
+	c.Assert(err, IsNil)
+	c.Assert(data, NotNil)
+	c.Assert(data, HasLen, 0)
+	buf.close()
+
+	for _, method := range []string{config.SpilledFileEncryptionMethodPlaintext, config.SpilledFileEncryptionMethodAES128CTR} {
+		config.UpdateGlobal(func(conf *config.Config) {
+			conf.Security.SpilledFileEncryptionMethod = method
+		})
+		buf = &longDataBuffer{}
+		expected := make([]byte, 0, 100)
+		for i := 0; i < 10; i++ {
+			chunk := bytes.Repeat([]byte{byte('a' + i)}, 10)
+			expected = append(expected, chunk...)
+			c.Assert(buf.append(chunk), IsNil)
+			// The data is kept in memory until it exceeds the limit.
+			c.Assert(buf.file == nil, Equals, len(expected) <= longDataMemLimit)
+		}
+		data, err = buf.bytes()
+		c.Assert(err, IsNil)
+		c.Assert(data, DeepEquals, expected)
+		fileName := buf.file.Name()
+		if method == config.SpilledFileEncryptionMethodPlaintext {
+			onDisk, err := os.ReadFile(fileName)
+			c.Assert(err, IsNil)
+			c.Assert(onDisk, DeepEquals, expected)
+		}
+		buf.close()
+		_, err = os.Stat(fileName)
+		c.Assert(os.IsNotExist(err), IsTrue)
+	}
+}
@@ -20,12 +20,15 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"sync/atomic"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
@@ -691,6 +694,57 @@ func (ts *ConnTestSuite) TestShutdownOrNotify(c *C) {
 	c.Assert(cc.status, Equals, connStatusWaitShutdown)
 }
 
+func (ts *ConnTestSuite) TestGracefulShutdownRedirect(c *C) {
+	cfg := config.NewConfig()
+	cfg.GracefulShutdownRedirect = true
+	server := &Server{
+		cfg:        cfg,
+		capability: defaultCapability,
+		clients:    make(map[uint64]*clientConn),
+	}
+	newConn := func(id uint64, inTxn bool) (*clientConn, *bytes.Buffer) {
+		se, err := session.CreateSession4Test(ts.store)
+		c.Assert(err, IsNil)
+		if inTxn {
+			_, err = se.Execute(context.Background(), "begin")
+			c.Assert(err, IsNil)
+		}
+		out := new(bytes.Buffer)
+		cc := &clientConn{
+			connectionID: id,
+			server:       server,
+			status:       connStatusReading,
+			ctx:          &TiDBContext{Session: se, stmts: make(map[int]*TiDBStatement)},
+			alloc:        arena.NewAllocator(1024),
+			pkt:          &packetIO{bufWriter: bufio.NewWriter(out)},
+			bufReadConn:  newBufferedReadConn(&bytesConn{}),
+		}
+		server.clients[id] = cc
+		return cc, out
+	}
+	_, idleOut := newConn(1, false)
+	txnConn, txnOut := newConn(2, true)
+
+	// The idle connection is closed with the error telling the client to reconnect elsewhere, while the connection
+	// in transaction is kept.
+	server.kickIdleConnection()
+	c.Assert(server.ConnectionCount(), Equals, 1)
+	data := idleOut.Bytes()
+	c.Assert(len(data) > 7, IsTrue)
+	c.Assert(data[3], Equals, byte(0))
+	c.Assert(data[4], Equals, byte(mysql.ErrHeader))
+	c.Assert(binary.LittleEndian.Uint16(data[5:7]), Equals, uint16(mysql.ErrServerShutdown))
+	c.Assert(txnOut.Len(), Equals, 0)
+
+	// The connection is closed after its transaction finishes.
+	_, err := txnConn.ctx.Execute(context.Background(), "commit")
+	c.Assert(err, IsNil)
+	server.kickIdleConnection()
+	c.Assert(server.ConnectionCount(), Equals, 0)
+	data = txnOut.Bytes()
+	c.Assert(binary.LittleEndian.Uint16(data[5:7]), Equals, uint16(mysql.ErrServerShutdown))
+}
+
 type snapshotCache interface {
 	SnapCacheHitCount() int
 }
@@ -751,6 +805,77 @@ func testGetTableByName(c *C, ctx sessionctx.Context, db, table string) table.Ta
 	return tbl
 }
 
+func (ts *ConnTestSuite) TestStmtSendLongDataError(c *C) {
+	cc := &clientConn{
+		alloc: arena.NewAllocator(1024),
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(bytes.NewBuffer(nil)),
+		},
+	}
+	tk := testkit.NewTestKitWithInit(c, ts.store)
+	cc.ctx = &TiDBContext{Session: tk.Se, stmts: make(map[int]*TiDBStatement)}
+	tk.MustExec("set @@max_allowed_packet = 1024")
+	ctx := context.Background()
+	c.Assert(cc.handleStmtPrepare(ctx, "select ?"), IsNil)
+	longData := func(paramID byte, size int) []byte {
+		return append([]byte{0x1, 0x0, 0x0, 0x0, paramID, 0x0}, bytes.Repeat([]byte{'a'}, size)...)
+	}
+	execute := []byte{0x1, 0x0, 0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0, 0x0, 0x1, 0xfc, 0x0}
+
+	// COM_STMT_SEND_LONG_DATA has no response, the errors are returned by COM_STMT_EXECUTE.
+	c.Assert(cc.handleStmtSendLongData(longData(0, 600)), IsNil)
+	c.Assert(cc.handleStmtSendLongData(longData(0, 600)), IsNil)
+	err := cc.handleStmtExecute(ctx, execute)
+	c.Assert(terror.ErrorEqual(err, errNetPacketTooLarge), IsTrue, Commentf("err %v", err))
+	c.Assert(cc.handleStmtSendLongData(longData(1, 10)), IsNil)
+	err = cc.handleStmtExecute(ctx, execute)
+	c.Assert(terror.ErrorEqual(err, mysql.NewErr(mysql.ErrWrongArguments, "stmt_send_longdata")), IsTrue, Commentf("err %v", err))
+	c.Assert(cc.handleStmtSendLongData([]byte{0x1}), IsNil)
+	c.Assert(cc.handleStmtSendLongData(append([]byte{0x2, 0x0, 0x0, 0x0}, longData(0, 10)[4:]...)), IsNil)
+
+	// The error is cleared after the execution.
+	c.Assert(cc.handleStmtSendLongData(longData(0, 1000)), IsNil)
+	c.Assert(cc.handleStmtExecute(ctx, execute), IsNil)
+}
+
+func (ts *ConnTestSuite) TestMaxResultSizeWithCursor(c *C) {
+	cc := &clientConn{
+		alloc: arena.NewAllocator(1024),
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(bytes.NewBuffer(nil)),
+		},
+	}
+	tk := testkit.NewTestKitWithInit(c, ts.store)
+	cc.ctx = &TiDBContext{Session: tk.Se, stmts: make(map[int]*TiDBStatement)}
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int)")
+	tk.MustExec("insert into t values (1), (2), (3), (4), (5)")
+	tk.MustExec("set @@tidb_max_result_rows = 3")
+	ctx := context.Background()
+	c.Assert(cc.handleStmtPrepare(ctx, "select a from t"), IsNil)
+
+	// The rows sent by the cursor are counted across the fetches.
+	c.Assert(cc.handleStmtExecute(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x1, 0x1, 0x0, 0x0, 0x0}), IsNil)
+	c.Assert(cc.handleStmtFetch(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x2, 0x0, 0x0, 0x0}), IsNil)
+	err := cc.handleStmtFetch(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x2, 0x0, 0x0, 0x0})
+	c.Assert(err, NotNil)
+	c.Assert(terror.ErrorEqual(err, errMaxResultSizeExceeded), IsTrue, Commentf("err %v", err))
+
+	// The cursor is closed when the result set is truncated.
+	tk.MustExec("set @@tidb_max_result_action = 'TRUNCATE'")
+	c.Assert(cc.handleStmtExecute(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x1, 0x1, 0x0, 0x0, 0x0}), IsNil)
+	c.Assert(cc.handleStmtFetch(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x2, 0x0, 0x0, 0x0}), IsNil)
+	c.Assert(cc.handleStmtFetch(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x2, 0x0, 0x0, 0x0}), IsNil)
+	rs := cc.ctx.GetStatement(1).GetResultSet().(*tidbResultSet)
+	c.Assert(atomic.LoadInt32(&rs.closed), Equals, int32(1))
+	sentRows, _ := rs.GetSentSize()
+	c.Assert(sentRows, Equals, int64(4))
+	warnings := tk.Se.GetSessionVars().StmtCtx.GetWarnings()
+	c.Assert(warnings, HasLen, 1)
+	c.Assert(terror.ErrorEqual(warnings[0].Err, errMaxResultSizeExceeded), IsTrue)
+}
+
 func (ts *ConnTestSuite) TestTiFlashFallback(c *C) {
 	cc := &clientConn{
 		alloc: arena.NewAllocator(1024),
@@ -35,14 +35,15 @@ type PreparedStatement interface {
 	// Execute executes the statement.
 	Execute(context.Context, []types.Datum) (ResultSet, error)
 
-	// AppendParam appends parameter to the statement.
-	AppendParam(paramID int, data []byte) error
+	// AppendParam appends parameter to the statement. COM_STMT_SEND_LONG_DATA has no response, so the error of
+	// appending is kept by the statement and returned by BoundParams.
+	AppendParam(paramID int, data []byte)
 
 	// NumParams returns number of parameters.
 	NumParams() int
 
-	// BoundParams returns bound parameters.
-	BoundParams() [][]byte
+	// BoundParams returns bound parameters, or the error of appending them.
+	BoundParams() ([][]byte, error)
 
 	// SetParamsType sets type for parameters.
 	SetParamsType([]byte)
@@ -70,6 +71,10 @@ type ResultSet interface {
 	Next(context.Context, *chunk.Chunk) error
 	StoreFetchedRows(rows []chunk.Row)
 	GetFetchedRows() []chunk.Row
+	// StoreSentSize and GetSentSize keep the rows and bytes sent to the client across the COM_STMT_FETCH commands,
+	// so that the result set read by the cursor is limited by tidb_max_result_rows and tidb_max_result_bytes as well.
+	StoreSentSize(rows, bytes int64)
+	GetSentSize() (rows, bytes int64)
 	Close() error
 }
 
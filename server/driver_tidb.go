@@ -16,6 +16,7 @@ package server
 import (
 	"context"
 	"crypto/tls"
+	"strconv"
 	"sync/atomic"
 
 	"github.com/pingcap/errors"
@@ -27,6 +28,7 @@ import (
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/sqlexec"
@@ -56,7 +58,9 @@ type TiDBContext struct {
 type TiDBStatement struct {
 	id          uint32
 	numParams   int
-	boundParams [][]byte
+	boundParams []*longDataBuffer
+	// longDataErr is the first error of appending the parameters, it's returned when the statement is executed.
+	longDataErr error
 	paramsType  []byte
 	ctx         *TiDBContext
 	rs          ResultSet
@@ -85,17 +89,33 @@ func (ts *TiDBStatement) Execute(ctx context.Context, args []types.Datum) (rs Re
 }
 
 // AppendParam implements PreparedStatement AppendParam method.
-func (ts *TiDBStatement) AppendParam(paramID int, data []byte) error {
+func (ts *TiDBStatement) AppendParam(paramID int, data []byte) {
+	// The parameters appended after an error are discarded, since the statement fails anyway.
+	if ts.longDataErr == nil {
+		ts.longDataErr = ts.appendParam(paramID, data)
+	}
+}
+
+func (ts *TiDBStatement) appendParam(paramID int, data []byte) error {
 	if paramID >= len(ts.boundParams) {
 		return mysql.NewErr(mysql.ErrWrongArguments, "stmt_send_longdata")
 	}
-	// If len(data) is 0, append an empty byte slice to the end to distinguish no data and no parameter.
-	if len(data) == 0 {
-		ts.boundParams[paramID] = []byte{}
-	} else {
-		ts.boundParams[paramID] = append(ts.boundParams[paramID], data...)
+	// The buffer is created even if len(data) is 0 to distinguish no data and no parameter.
+	buf := ts.boundParams[paramID]
+	if buf == nil {
+		buf = &longDataBuffer{}
+		ts.boundParams[paramID] = buf
 	}
-	return nil
+	// The parameter is read into memory when the statement is executed, so it's limited as a packet is.
+	valStr, _ := ts.ctx.GetSessionVars().GetSystemVar(variable.MaxAllowedPacket)
+	maxAllowedPacket, err := strconv.ParseInt(valStr, 10, 64)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if buf.size+int64(len(data)) > maxAllowedPacket {
+		return errNetPacketTooLarge
+	}
+	return buf.append(data)
 }
 
 // NumParams implements PreparedStatement NumParams method.
@@ -104,8 +124,22 @@ func (ts *TiDBStatement) NumParams() int {
 }
 
 // BoundParams implements PreparedStatement BoundParams method.
-func (ts *TiDBStatement) BoundParams() [][]byte {
-	return ts.boundParams
+func (ts *TiDBStatement) BoundParams() ([][]byte, error) {
+	if ts.longDataErr != nil {
+		return nil, ts.longDataErr
+	}
+	params := make([][]byte, len(ts.boundParams))
+	for i, buf := range ts.boundParams {
+		if buf == nil {
+			continue
+		}
+		data, err := buf.bytes()
+		if err != nil {
+			return nil, err
+		}
+		params[i] = data
+	}
+	return params, nil
 }
 
 // SetParamsType implements PreparedStatement SetParamsType method.
@@ -137,9 +171,7 @@ func (ts *TiDBStatement) GetResultSet() ResultSet {
 
 // Reset implements PreparedStatement Reset method.
 func (ts *TiDBStatement) Reset() {
-	for i := range ts.boundParams {
-		ts.boundParams[i] = nil
-	}
+	ts.resetBoundParams()
 
 	// closing previous ResultSet if it exists
 	if ts.rs != nil {
@@ -148,6 +180,16 @@ func (ts *TiDBStatement) Reset() {
 	}
 }
 
+func (ts *TiDBStatement) resetBoundParams() {
+	ts.longDataErr = nil
+	for i, buf := range ts.boundParams {
+		if buf != nil {
+			buf.close()
+			ts.boundParams[i] = nil
+		}
+	}
+}
+
 // Close implements PreparedStatement Close method.
 func (ts *TiDBStatement) Close() error {
 	// TODO close at tidb level
@@ -169,6 +211,7 @@ func (ts *TiDBStatement) Close() error {
 		ts.ctx.GetSessionVars().RemovePreparedStmt(ts.id)
 	}
 	delete(ts.ctx.stmts, int(ts.id))
+	ts.resetBoundParams()
 
 	// close ResultSet associated with this statement
 	if ts.rs != nil {
@@ -271,7 +314,7 @@ func (tc *TiDBContext) Prepare(sql string) (statement PreparedStatement, columns
 		sql:         sql,
 		id:          stmtID,
 		numParams:   paramCount,
-		boundParams: make([][]byte, paramCount),
+		boundParams: make([]*longDataBuffer, paramCount),
 		ctx:         tc,
 	}
 	statement = stmt
@@ -293,6 +336,8 @@ type tidbResultSet struct {
 	recordSet    sqlexec.RecordSet
 	columns      []*ColumnInfo
 	rows         []chunk.Row
+	sentRows     int64
+	sentBytes    int64
 	closed       int32
 	preparedStmt *core.CachedPrepareStmt
 }
@@ -316,6 +361,14 @@ func (trs *tidbResultSet) GetFetchedRows() []chunk.Row {
 	return trs.rows
 }
 
+func (trs *tidbResultSet) StoreSentSize(rows, bytes int64) {
+	trs.sentRows, trs.sentBytes = rows, bytes
+}
+
+func (trs *tidbResultSet) GetSentSize() (rows, bytes int64) {
+	return trs.sentRows, trs.sentBytes
+}
+
 func (trs *tidbResultSet) Close() error {
 	if !atomic.CompareAndSwapInt32(&trs.closed, 0, 1) {
 		return nil
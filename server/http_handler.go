@@ -62,6 +62,9 @@ import (
 	"github.com/pingcap/tidb/util/gcutil"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/pdapi"
+	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/pingcap/tidb/util/topsql"
+	"github.com/pingcap/tidb/util/topsql/reporter"
 	"github.com/tikv/client-go/v2/tikv"
 	"go.uber.org/zap"
 )
@@ -85,10 +88,15 @@ const (
 
 // For query string
 const (
-	qTableID   = "table_id"
-	qLimit     = "limit"
-	qOperation = "op"
-	qSeconds   = "seconds"
+	qTableID    = "table_id"
+	qLimit      = "limit"
+	qOperation  = "op"
+	qSeconds    = "seconds"
+	qPlanDigest = "plan_digest"
+	qDigest     = "digest"
+	qUser       = "user"
+	qDB         = "db"
+	qGroupBy    = "group_by"
 )
 
 const (
@@ -358,6 +366,22 @@ type ddlResignOwnerHandler struct {
 	store kv.Storage
 }
 
+// bindingFromHistoryHandler is the handler for creating a global binding from a plan in statements_summary.
+type bindingFromHistoryHandler struct {
+	store kv.Storage
+}
+
+// tzInfoReloadHandler is the handler for reloading the time zone database.
+type tzInfoReloadHandler struct{}
+
+// killQueryHandler is the handler for killing the running queries by the SQL digest.
+type killQueryHandler struct {
+	server *Server
+}
+
+// topSQLRecordsHandler is the handler for dumping the recent Top SQL records.
+type topSQLRecordsHandler struct{}
+
 type serverInfoHandler struct {
 	*tikvHandlerTool
 }
@@ -1106,6 +1130,108 @@ func (h ddlResignOwnerHandler) ServeHTTP(w http.ResponseWriter, req *http.Reques
 	writeData(w, "success!")
 }
 
+// ServeHTTP handles request of creating a global binding by the plan digest in statements_summary.
+func (h bindingFromHistoryHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeError(w, errors.Errorf("This api only support POST method."))
+		return
+	}
+	planDigest := req.FormValue(qPlanDigest)
+	if len(planDigest) == 0 {
+		writeError(w, errors.Errorf("Parameter %s is required.", qPlanDigest))
+		return
+	}
+	dom, err := session.GetDomain(h.store)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	record, err := dom.BindHandle().CreateBindRecordFromHistory(planDigest)
+	if err != nil {
+		log.Error("failed to create binding from history", zap.String("planDigest", planDigest), zap.Error(err))
+		writeError(w, err)
+		return
+	}
+	writeData(w, map[string]string{
+		"original_sql": record.OriginalSQL,
+		"bind_sql":     record.Bindings[0].BindSQL,
+		"default_db":   record.Db,
+	})
+}
+
+// ServeHTTP handles request of reloading the time zone database, the time zones of the sessions are reloaded before
+// their next statements.
+func (h tzInfoReloadHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeError(w, errors.Errorf("This api only support POST method."))
+		return
+	}
+	timeutil.ReloadLocations()
+	writeData(w, "success!")
+}
+
+// killQueryResult is the result of killing the queries on a TiDB server.
+type killQueryResult struct {
+	ConnIDs []uint64 `json:"conn_ids"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// ServeHTTP handles request of killing the running queries of the SQL digest on the requested TiDB server, the queries
+// can be filtered by the user and the database.
+func (h killQueryHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeError(w, errors.Errorf("This api only support POST method."))
+		return
+	}
+	digest := req.FormValue(qDigest)
+	if len(digest) == 0 {
+		writeError(w, errors.Errorf("Parameter %s is required.", qDigest))
+		return
+	}
+	user, db := req.FormValue(qUser), req.FormValue(qDB)
+	connIDs := h.server.KillQueriesByDigest(digest, user, db)
+	logutil.BgLogger().Info("kill queries by digest", zap.String("digest", digest), zap.String("user", user),
+		zap.String("db", db), zap.String("remoteAddr", req.RemoteAddr), zap.Uint64s("connIDs", connIDs))
+	writeData(w, connIDs)
+}
+
+// ServeHTTP handles request of dumping the Top SQL records reported in the recent seconds, along with the normalized
+// SQL and plan texts. The seconds is 60 by default. If group_by is specified, the records are aggregated by the
+// database and/or the user instead.
+func (h topSQLRecordsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	seconds := 60
+	if v := req.FormValue(qSeconds); len(v) > 0 {
+		var err error
+		if seconds, err = strconv.Atoi(v); err != nil || seconds <= 0 {
+			writeError(w, errors.Errorf("Parameter %s is invalid.", qSeconds))
+			return
+		}
+	}
+	var byDB, byUser bool
+	if v := req.FormValue(qGroupBy); len(v) > 0 {
+		for _, dim := range strings.Split(v, ",") {
+			switch strings.ToLower(strings.TrimSpace(dim)) {
+			case qDB:
+				byDB = true
+			case qUser:
+				byUser = true
+			default:
+				writeError(w, errors.Errorf("Parameter %s is invalid, it should be %s, %s or both.", qGroupBy, qDB, qUser))
+				return
+			}
+		}
+	}
+	reports := topsql.GetRecentReports(time.Duration(seconds) * time.Second)
+	if byDB || byUser {
+		writeData(w, reporter.AggregateJSONReports(reports, byDB, byUser))
+		return
+	}
+	if reports == nil {
+		reports = []*reporter.JSONReport{}
+	}
+	writeData(w, reports)
+}
+
 func (h tableHandler) getPDAddr() ([]string, error) {
 	etcd, ok := h.Store.(kv.EtcdBackend)
 	if !ok {
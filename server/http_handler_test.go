@@ -15,6 +15,7 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
@@ -35,8 +36,10 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/kvproto/pkg/coprocessor"
 	"github.com/pingcap/kvproto/pkg/kvrpcpb"
 	"github.com/pingcap/log"
+	"github.com/pingcap/parser"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/config"
@@ -44,6 +47,7 @@ import (
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/binloginfo"
@@ -56,6 +60,8 @@ import (
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/deadlockhistory"
 	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/pingcap/tidb/util/topsql/reporter"
 	"github.com/pingcap/tidb/util/versioninfo"
 	"github.com/tikv/client-go/v2/tikv"
 	"go.uber.org/zap"
@@ -1020,7 +1026,7 @@ func (ts *HTTPHandlerTestSuite) TestGetSchema(c *C) {
 	var dbs []*model.DBInfo
 	err = decoder.Decode(&dbs)
 	c.Assert(err, IsNil)
-	expects := []string{"information_schema", "metrics_schema", "mysql", "performance_schema", "test", "tidb"}
+	expects := []string{"information_schema", "metrics_schema", "mysql", "performance_schema", "sys", "test", "tidb"}
 	names := make([]string, len(dbs))
 	for i, v := range dbs {
 		names[i] = v.Name.L
@@ -1432,6 +1438,209 @@ func (ts *HTTPHandlerTestSuite) TestZipInfoForSQL(c *C) {
 	c.Assert(resp.Body.Close(), IsNil)
 }
 
+func (ts *HTTPHandlerTestSuite) TestTZInfoReload(c *C) {
+	ts.startServer(c)
+	defer ts.stopServer(c)
+
+	resp, err := ts.fetchStatus("/tzinfo/reload")
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+	c.Assert(resp.Body.Close(), IsNil)
+
+	version := timeutil.LocationVersion()
+	resp, err = ts.postStatus("/tzinfo/reload", "application/x-www-form-urlencoded", nil)
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(resp.Body.Close(), IsNil)
+	c.Assert(timeutil.LocationVersion(), Equals, version+1)
+}
+
+func (ts *HTTPHandlerTestSuite) TestBindingFromHistory(c *C) {
+	ts.startServer(c)
+	defer ts.stopServer(c)
+
+	resp, err := ts.postStatus("/bindings/history", "application/x-www-form-urlencoded", nil)
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+	c.Assert(resp.Body.Close(), IsNil)
+
+	resp, err = ts.formStatus("/bindings/history", url.Values{qPlanDigest: {"plan_digest"}})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+	body, err := io.ReadAll(resp.Body)
+	c.Assert(err, IsNil)
+	c.Assert(string(body), Equals, "can't find any plans for 'plan_digest'")
+	c.Assert(resp.Body.Close(), IsNil)
+}
+
+func (ts *HTTPHandlerTestSuite) TestKillQueriesByDigest(c *C) {
+	ts.startServer(c)
+	defer ts.stopServer(c)
+
+	resp, err := ts.postStatus("/queries/kill", "application/x-www-form-urlencoded", nil)
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+	c.Assert(resp.Body.Close(), IsNil)
+
+	db, err := sql.Open("mysql", ts.getDSN())
+	c.Assert(err, IsNil, Commentf("Error connecting"))
+	defer func() {
+		err := db.Close()
+		c.Assert(err, IsNil)
+	}()
+	done := make(chan time.Duration, 1)
+	go func() {
+		start := time.Now()
+		_, err := db.Exec("select sleep(30)")
+		c.Check(err, IsNil)
+		done <- time.Since(start)
+	}()
+
+	_, digest := parser.NormalizeDigest("select sleep(30)")
+	// The queries of the other users are filtered out.
+	var connIDs []uint64
+	resp, err = ts.formStatus("/queries/kill", url.Values{qDigest: {digest.String()}, qUser: {"kill_user"}})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(json.NewDecoder(resp.Body).Decode(&connIDs), IsNil)
+	c.Assert(resp.Body.Close(), IsNil)
+	c.Assert(connIDs, HasLen, 0)
+
+	form := url.Values{qDigest: {digest.String()}}
+	for i := 0; i < 50 && len(connIDs) == 0; i++ {
+		time.Sleep(100 * time.Millisecond)
+		resp, err = ts.formStatus("/queries/kill", form)
+		c.Assert(err, IsNil)
+		c.Assert(resp.StatusCode, Equals, http.StatusOK)
+		c.Assert(json.NewDecoder(resp.Body).Decode(&connIDs), IsNil)
+		c.Assert(resp.Body.Close(), IsNil)
+	}
+	c.Assert(connIDs, HasLen, 1)
+	select {
+	case d := <-done:
+		c.Assert(d < 30*time.Second, IsTrue)
+	case <-time.After(10 * time.Second):
+		c.Fatal("the query is not killed")
+	}
+
+	// The finished query of the same digest isn't killed.
+	resp, err = ts.formStatus("/queries/kill", form)
+	c.Assert(err, IsNil)
+	c.Assert(json.NewDecoder(resp.Body).Decode(&connIDs), IsNil)
+	c.Assert(resp.Body.Close(), IsNil)
+	c.Assert(connIDs, HasLen, 0)
+}
+
+func (ts *HTTPHandlerTestSuite) TestTopSQLRecords(c *C) {
+	ts.startServer(c)
+	defer ts.stopServer(c)
+
+	resp, err := ts.fetchStatus("/topsql/records?seconds=abc")
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+	c.Assert(resp.Body.Close(), IsNil)
+
+	resp, err = ts.fetchStatus("/topsql/records?seconds=120")
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	var reports []*reporter.JSONReport
+	c.Assert(json.NewDecoder(resp.Body).Decode(&reports), IsNil)
+	c.Assert(resp.Body.Close(), IsNil)
+	c.Assert(reports, NotNil)
+
+	resp, err = ts.fetchStatus("/topsql/records?group_by=db,host")
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+	c.Assert(resp.Body.Close(), IsNil)
+
+	resp, err = ts.fetchStatus("/topsql/records?group_by=db,user")
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	var aggregated []*reporter.JSONAggregatedRecord
+	c.Assert(json.NewDecoder(resp.Body).Decode(&aggregated), IsNil)
+	c.Assert(resp.Body.Close(), IsNil)
+	c.Assert(aggregated, NotNil)
+}
+
+func (ts *HTTPHandlerTestSuite) TestCentralAutoIDRPC(c *C) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Experimental.EnableCentralAutoID = true
+	})
+	ts.startServer(c)
+	defer ts.stopServer(c)
+
+	db, err := sql.Open("mysql", ts.getDSN())
+	c.Assert(err, IsNil, Commentf("Error connecting"))
+	defer func() {
+		err := db.Close()
+		c.Assert(err, IsNil)
+	}()
+	dbt := &DBTest{c, db}
+	dbt.mustExec("use test")
+	dbt.mustExec("drop table if exists central_autoid")
+	dbt.mustExec("create table central_autoid (a int auto_increment key) auto_id_cache 1")
+	dbt.mustExec("insert into central_autoid values ()")
+
+	is := ts.domain.InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("central_autoid"))
+	c.Assert(err, IsNil)
+	dbInfo, ok := is.SchemaByName(model.NewCIStr("test"))
+	c.Assert(ok, IsTrue)
+
+	token, err := ts.domain.CentralAutoIDToken(context.Background())
+	c.Assert(err, IsNil)
+	ownerID := ts.domain.DDL().OwnerManager().ID()
+	srv := &rpcServer{dom: ts.domain}
+	call := func(token, ownerID string, reqs ...*autoid.CentralRequest) (*autoid.CentralBatchResponse, string) {
+		data, err := json.Marshal(&autoid.CentralBatchRequest{Token: token, OwnerID: ownerID, Requests: reqs})
+		c.Assert(err, IsNil)
+		resp, err := srv.Coprocessor(context.Background(), &coprocessor.Request{Tp: kv.ReqTypeCentralAutoID, Data: data})
+		c.Assert(err, IsNil)
+		if resp.OtherError != "" {
+			return nil, resp.OtherError
+		}
+		batchResp := &autoid.CentralBatchResponse{}
+		c.Assert(json.Unmarshal(resp.Data, batchResp), IsNil)
+		return batchResp, ""
+	}
+	allocReq := &autoid.CentralRequest{Op: autoid.CentralOpAlloc, DBID: dbInfo.ID, TableID: tbl.Meta().ID,
+		TableUpdateTS: tbl.Meta().UpdateTS, N: 2, Increment: 1, Offset: 1}
+
+	// The requests without the token of the cluster or sent to a wrong owner are rejected.
+	_, errMsg := call("wrong", ownerID, allocReq)
+	c.Assert(errMsg, Matches, ".*unauthorized.*")
+	_, errMsg = call(token, "another", allocReq)
+	c.Assert(errMsg, Matches, ".*not the DDL owner.*")
+
+	// The errors of the allocations are returned in the responses, and rebasing isn't accepted.
+	rebaseReq := &autoid.CentralRequest{Op: autoid.CentralOpRebase, DBID: dbInfo.ID, TableID: tbl.Meta().ID, Base: 10000}
+	batchResp, errMsg := call(token, ownerID, allocReq, &autoid.CentralRequest{Op: autoid.CentralOpAlloc, DBID: 1}, rebaseReq)
+	c.Assert(errMsg, Equals, "")
+	c.Assert(batchResp.Responses, HasLen, 3)
+	c.Assert(*batchResp.Responses[0], Equals, autoid.CentralResponse{Min: 1, Max: 3})
+	c.Assert(batchResp.Responses[1].Error, Matches, ".*Invalid tableID.*")
+	c.Assert(batchResp.Responses[2].Error, Matches, ".*doesn't accept rebasing.*")
+
+	dbt.mustExec("insert into central_autoid values ()")
+	rows := dbt.mustQuery("select a from central_autoid order by a")
+	var ids []int
+	for rows.Next() {
+		var id int
+		c.Assert(rows.Scan(&id), IsNil)
+		ids = append(ids, id)
+	}
+	c.Assert(rows.Close(), IsNil)
+	c.Assert(ids, DeepEquals, []int{1, 4})
+
+	// The requests are rejected if the service is disabled.
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Experimental.EnableCentralAutoID = false
+	})
+	_, errMsg = call(token, ownerID, allocReq)
+	c.Assert(errMsg, Matches, ".*disabled.*")
+}
+
 func (ts *HTTPHandlerTestSuite) TestFailpointHandler(c *C) {
 	defer ts.stopServer(c)
 
@@ -30,6 +30,7 @@ import (
 	rpprof "runtime/pprof"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -39,6 +40,7 @@ import (
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/parser/terror"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/logutil"
@@ -48,6 +50,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/soheilhy/cmux"
 	"github.com/tiancaiamao/appdash/traceapp"
+	"github.com/tikv/client-go/v2/oracle"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/channelz/service"
 	static "sourcegraph.com/sourcegraph/appdash-data"
@@ -110,6 +113,9 @@ func (s *Server) startHTTPServer() {
 	router := mux.NewRouter()
 
 	router.HandleFunc("/status", s.handleStatus).Name("Status")
+	// HTTP path for liveness and readiness probes.
+	router.HandleFunc("/healthz", s.handleHealthz).Name("Healthz")
+	router.HandleFunc("/readyz", s.handleReadyz).Name("Readyz")
 	// HTTP path for prometheus.
 	router.Handle("/metrics", promhttp.Handler()).Name("Metrics")
 
@@ -127,6 +133,10 @@ func (s *Server) startHTTPServer() {
 	router.Handle("/tables/{colID}/{colTp}/{colFlag}/{colLen}", valueHandler{})
 	router.Handle("/ddl/history", ddlHistoryJobHandler{tikvHandlerTool}).Name("DDL_History")
 	router.Handle("/ddl/owner/resign", ddlResignOwnerHandler{tikvHandlerTool.Store.(kv.Storage)}).Name("DDL_Owner_Resign")
+	router.Handle("/bindings/history", bindingFromHistoryHandler{tikvHandlerTool.Store.(kv.Storage)}).Name("Bindings_History")
+	router.Handle("/tzinfo/reload", tzInfoReloadHandler{}).Name("TZInfo_Reload")
+	router.Handle("/queries/kill", killQueryHandler{s}).Name("Queries_Kill")
+	router.Handle("/topsql/records", topSQLRecordsHandler{}).Name("TopSQL_Records")
 
 	// HTTP path for get the TiDB config
 	router.Handle("/config", fn.Wrap(func() (*config.Config, error) {
@@ -422,3 +432,115 @@ func (s *Server) handleStatus(w http.ResponseWriter, req *http.Request) {
 	_, err = w.Write(js)
 	terror.Log(errors.Trace(err))
 }
+
+// readyzPDTimeout is the timeout of checking the PD connectivity in readiness probes.
+const readyzPDTimeout = 3 * time.Second
+
+// readyzPDCheckInterval is the interval of getting a timestamp from PD to check the PD connectivity. The readiness
+// probes within the interval reuse the last result, so frequent probes don't add load to PD.
+var readyzPDCheckInterval = 5 * time.Second
+
+// pdHealth is the last result of checking the PD connectivity.
+type pdHealth struct {
+	sync.Mutex
+	checkedAt time.Time
+	ts        uint64
+	err       error
+}
+
+// readiness of TiDB. Checks maps the name of each check to "ok" or the reason why it fails.
+type readiness struct {
+	Ready  bool              `json:"ready"`
+	Checks map[string]string `json:"checks"`
+}
+
+// handleHealthz is the liveness probe, it only reports the process is able to serve HTTP requests.
+// It keeps returning 200 while the server is shutting down, so that the process is not restarted
+// before it finishes draining connections.
+func (s *Server) handleHealthz(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, err := w.Write([]byte("ok"))
+	terror.Log(errors.Trace(err))
+}
+
+// handleReadyz is the readiness probe, it returns 503 if the server should not receive new traffic,
+// i.e. it is shutting down, its schema lease is not valid, or PD is not reachable.
+func (s *Server) handleReadyz(w http.ResponseWriter, req *http.Request) {
+	st := readiness{Ready: true, Checks: make(map[string]string, 3)}
+	check := func(name string, err error) {
+		if err != nil {
+			st.Ready = false
+			st.Checks[name] = err.Error()
+			return
+		}
+		st.Checks[name] = "ok"
+	}
+	// Don't acquire s.rwlock here, it may already be held by the shutdown process.
+	if s.inShutdownMode {
+		check("shutdown", errors.New("server is shutting down"))
+	} else {
+		check("shutdown", nil)
+	}
+	ts, err := s.checkPDConnectivity(req.Context())
+	check("pd", err)
+	if err == nil {
+		err = s.checkSchemaValidity(ts)
+	} else {
+		err = errors.New("unknown because PD is not reachable")
+	}
+	check("schema", err)
+
+	w.Header().Set("Content-Type", "application/json")
+	if !st.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	js, err := json.Marshal(st)
+	if err != nil {
+		logutil.BgLogger().Error("encode json failed", zap.Error(err))
+		return
+	}
+	_, err = w.Write(js)
+	terror.Log(errors.Trace(err))
+}
+
+// checkPDConnectivity checks whether a timestamp can be got from PD, and returns the timestamp. The result is cached
+// for readyzPDCheckInterval.
+func (s *Server) checkPDConnectivity(ctx context.Context) (uint64, error) {
+	if s.dom == nil {
+		return 0, errors.New("domain is not initialized")
+	}
+	if s.dom.IsLostConnectionToPD() {
+		return 0, errors.New("lost connection to PD")
+	}
+	h := &s.pdHealth
+	h.Lock()
+	defer h.Unlock()
+	if elapsed := time.Since(h.checkedAt); elapsed < readyzPDCheckInterval {
+		if h.err != nil {
+			return 0, h.err
+		}
+		// Advance the cached timestamp by the elapsed time, so that the schema lease is checked at the current time.
+		return oracle.GoTimeToTS(oracle.GetTimeFromTS(h.ts).Add(elapsed)), nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, readyzPDTimeout)
+	defer cancel()
+	ts, err := s.dom.Store().GetOracle().GetTimestamp(ctx, &oracle.Option{TxnScope: oracle.GlobalTxnScope})
+	if err != nil {
+		err = errors.Annotate(err, "get timestamp from PD failed")
+	}
+	h.checkedAt, h.ts, h.err = time.Now(), ts, err
+	return ts, err
+}
+
+// checkSchemaValidity checks whether the schema lease of the server is still valid at ts,
+// transactions fail to commit if it's not.
+func (s *Server) checkSchemaValidity(ts uint64) error {
+	is := s.dom.InfoSchema()
+	if is == nil {
+		return errors.New("schema is not loaded")
+	}
+	if _, result := s.dom.SchemaValidator.Check(ts, is.SchemaMetaVersion(), nil); result != domain.ResultSucc {
+		return errors.New("schema lease is expired")
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/tidb/planner"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx/variable"
+)
+
+const (
+	// resourceLimitWindowBuckets is the number of the buckets in the sliding window of the resource limits, each
+	// bucket counts the statements of one minute, so the window is one hour.
+	resourceLimitWindowBuckets = 60
+	resourceLimitBucketSeconds = 60
+)
+
+// slidingWindowCounter counts the events in the last hour.
+type slidingWindowCounter struct {
+	// counts[i] is the number of the events in the minute minutes[i], which is the unix time divided by a minute.
+	counts  [resourceLimitWindowBuckets]int64
+	minutes [resourceLimitWindowBuckets]int64
+}
+
+func (c *slidingWindowCounter) count(minute int64) int64 {
+	var total int64
+	for i := range c.counts {
+		if c.minutes[i] > minute-resourceLimitWindowBuckets {
+			total += c.counts[i]
+		}
+	}
+	return total
+}
+
+func (c *slidingWindowCounter) add(minute int64) {
+	i := minute % resourceLimitWindowBuckets
+	if c.minutes[i] != minute {
+		c.minutes[i] = minute
+		c.counts[i] = 0
+	}
+	c.counts[i]++
+}
+
+type accountResourceUsage struct {
+	queries slidingWindowCounter
+	updates slidingWindowCounter
+}
+
+// accountResourceLimiter enforces the MAX_QUERIES_PER_HOUR and MAX_UPDATES_PER_HOUR resource limits of the accounts.
+// The statements of an account are counted across all its connections to this tidb-server in a sliding window of
+// one hour.
+type accountResourceLimiter struct {
+	sync.Mutex
+	// accounts maps 'user@host' of the accounts to their usages, only the accounts with limits are counted.
+	accounts map[string]*accountResourceUsage
+}
+
+func newAccountResourceLimiter() *accountResourceLimiter {
+	return &accountResourceLimiter{
+		accounts: make(map[string]*accountResourceUsage),
+	}
+}
+
+// consume counts a statement of the account, isUpdate indicates whether the statement is also counted by
+// MAX_UPDATES_PER_HOUR. If the statement exceeds any limit, an error is returned and the statement is not counted.
+func (l *accountResourceLimiter) consume(user, host string, maxQueries, maxUpdates int64, isUpdate bool, now time.Time) error {
+	l.Lock()
+	defer l.Unlock()
+	account := user + "@" + host
+	usage, ok := l.accounts[account]
+	if !ok {
+		usage = &accountResourceUsage{}
+		l.accounts[account] = usage
+	}
+	minute := now.Unix() / resourceLimitBucketSeconds
+	if maxQueries > 0 && usage.queries.count(minute) >= maxQueries {
+		return errUserLimitReached.GenWithStackByArgs(user, "max_questions", maxQueries)
+	}
+	if isUpdate && maxUpdates > 0 && usage.updates.count(minute) >= maxUpdates {
+		return errUserLimitReached.GenWithStackByArgs(user, "max_updates", maxUpdates)
+	}
+	usage.queries.add(minute)
+	if isUpdate {
+		usage.updates.add(minute)
+	}
+	return nil
+}
+
+// isUpdateStmt checks whether the statement modifies the tables or the databases, which is counted by
+// MAX_UPDATES_PER_HOUR.
+func isUpdateStmt(stmt ast.StmtNode, vars *variable.SessionVars) bool {
+	if execStmt, ok := stmt.(*ast.ExecuteStmt); ok {
+		prepared, err := planner.GetPreparedStmt(execStmt, vars)
+		if err != nil {
+			return false
+		}
+		stmt = prepared.PreparedAst.Stmt
+	}
+	switch stmt.(type) {
+	case *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt, *ast.LoadDataStmt, ast.DDLNode:
+		return true
+	}
+	return false
+}
+
+// checkResourceLimits checks the statement against the resource limits of the account, and counts it if the limits
+// aren't exceeded.
+func (cc *clientConn) checkResourceLimits(stmt ast.StmtNode) error {
+	if cc.server == nil || cc.server.resourceLimiter == nil {
+		return nil
+	}
+	vars := cc.ctx.GetSessionVars()
+	checker := privilege.GetPrivilegeManager(cc.ctx.Session)
+	if checker == nil || vars.User == nil {
+		return nil
+	}
+	maxQueries, maxUpdates := checker.GetResourceLimits(vars.User.AuthUsername, vars.User.AuthHostname)
+	if maxQueries == 0 && maxUpdates == 0 {
+		return nil
+	}
+	return cc.server.resourceLimiter.consume(vars.User.AuthUsername, vars.User.AuthHostname, maxQueries, maxUpdates,
+		isUpdateStmt(stmt, vars), time.Now())
+}
@@ -0,0 +1,79 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/auth"
+	"github.com/pingcap/tidb/util/arena"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func (ts *ConnTestSuite) TestAccountResourceLimiter(c *C) {
+	l := newAccountResourceLimiter()
+	now := time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)
+	c.Assert(l.consume("u", "%", 2, 1, true, now), IsNil)
+	// The updates are limited separately.
+	err := l.consume("u", "%", 2, 1, true, now.Add(time.Minute))
+	c.Assert(err, ErrorMatches, ".*User 'u' has exceeded the 'max_updates' resource \\(current value: 1\\)")
+	c.Assert(l.consume("u", "%", 2, 1, false, now.Add(time.Minute)), IsNil)
+	err = l.consume("u", "%", 2, 1, false, now.Add(30*time.Minute))
+	c.Assert(err, ErrorMatches, ".*User 'u' has exceeded the 'max_questions' resource \\(current value: 2\\)")
+	// The accounts are counted separately.
+	c.Assert(l.consume("u", "localhost", 2, 1, true, now.Add(30*time.Minute)), IsNil)
+
+	// The statements out of the one hour window are not counted.
+	c.Assert(l.consume("u", "%", 2, 1, true, now.Add(time.Hour)), IsNil)
+	err = l.consume("u", "%", 2, 1, false, now.Add(time.Hour))
+	c.Assert(err, ErrorMatches, ".*max_questions.*")
+	c.Assert(l.consume("u", "%", 2, 1, false, now.Add(time.Hour+time.Minute)), IsNil)
+}
+
+func (ts *ConnTestSuite) TestCheckResourceLimits(c *C) {
+	tk := testkit.NewTestKitWithInit(c, ts.store)
+	tk.MustExec("create table t_limits (a int)")
+	tk.MustExec("create user 'test_limits'@'%' with max_queries_per_hour 3 max_updates_per_hour 1")
+	defer tk.MustExec("drop user 'test_limits'@'%'")
+	tk.MustExec("grant all on test.* to 'test_limits'@'%'")
+
+	tk1 := testkit.NewTestKitWithInit(c, ts.store)
+	c.Assert(tk1.Se.Auth(&auth.UserIdentity{Username: "test_limits", Hostname: "localhost"}, nil, nil), IsTrue)
+	cc := &clientConn{
+		server: &Server{resourceLimiter: newAccountResourceLimiter()},
+		alloc:  arena.NewAllocator(1024),
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(bytes.NewBuffer(nil)),
+		},
+		ctx: &TiDBContext{Session: tk1.Se, stmts: make(map[int]*TiDBStatement)},
+	}
+	ctx := context.Background()
+	c.Assert(cc.handleQuery(ctx, "use test"), IsNil)
+	c.Assert(cc.handleQuery(ctx, "insert into t_limits values (1)"), IsNil)
+	err := cc.handleQuery(ctx, "insert into t_limits values (2)")
+	c.Assert(err, ErrorMatches, ".*User 'test_limits' has exceeded the 'max_updates' resource \\(current value: 1\\)")
+	c.Assert(cc.handleQuery(ctx, "select * from t_limits"), IsNil)
+	err = cc.handleQuery(ctx, "select 1")
+	c.Assert(err, ErrorMatches, ".*User 'test_limits' has exceeded the 'max_questions' resource \\(current value: 3\\)")
+	tk.MustQuery("select * from t_limits").Check(testkit.Rows("1"))
+
+	// The accounts without limits are not counted.
+	cc.ctx = &TiDBContext{Session: tk.Se, stmts: make(map[int]*TiDBStatement)}
+	c.Assert(cc.handleQuery(ctx, "select 1"), IsNil)
+	c.Assert(cc.server.resourceLimiter.accounts, HasLen, 1)
+}
@@ -15,6 +15,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 
@@ -25,6 +26,8 @@ import (
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/privilege/privileges"
 	"github.com/pingcap/tidb/session"
@@ -77,10 +80,34 @@ func (s *rpcServer) Coprocessor(ctx context.Context, in *coprocessor.Request) (r
 			resp.OtherError = fmt.Sprintf("panic when RPC server handing coprocessor, stack:%v", v)
 		}
 	}()
+	if in.Tp == kv.ReqTypeCentralAutoID {
+		return s.handleCentralAutoIDRequest(ctx, in), nil
+	}
 	resp = s.handleCopRequest(ctx, in)
 	return resp, nil
 }
 
+// handleCentralAutoIDRequest handles the batch of the requests forwarded to the centralized auto ID allocator service
+// by the other servers. The errors of the allocations are returned in the batch response, so that the caller can
+// distinguish them from the errors of the batch.
+func (s *rpcServer) handleCentralAutoIDRequest(ctx context.Context, req *coprocessor.Request) *coprocessor.Response {
+	resp := &coprocessor.Response{}
+	var batch autoid.CentralBatchRequest
+	if err := json.Unmarshal(req.Data, &batch); err != nil {
+		resp.OtherError = err.Error()
+		return resp
+	}
+	batchResp, err := s.dom.HandleCentralAutoIDBatch(ctx, &batch)
+	if err != nil {
+		resp.OtherError = err.Error()
+		return resp
+	}
+	if resp.Data, err = json.Marshal(batchResp); err != nil {
+		resp.OtherError = err.Error()
+	}
+	return resp
+}
+
 // CoprocessorStream implements the TiKVServer interface.
 func (s *rpcServer) CoprocessorStream(in *coprocessor.Request, stream tikvpb.Tikv_CoprocessorStreamServer) (err error) {
 	resp := &coprocessor.Response{}
@@ -42,6 +42,8 @@ import (
 	_ "net/http/pprof"
 	"os"
 	"os/user"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -101,6 +103,10 @@ var (
 	errSecureTransportRequired = dbterror.ClassServer.NewStd(errno.ErrSecureTransportRequired)
 	errMultiStatementDisabled  = dbterror.ClassServer.NewStd(errno.ErrMultiStatementDisabled)
 	errNewAbortingConnection   = dbterror.ClassServer.NewStd(errno.ErrNewAbortingConnection)
+	errUserLimitReached        = dbterror.ClassServer.NewStd(errno.ErrUserLimitReached)
+	errServerShutdown          = dbterror.ClassServer.NewStd(errno.ErrServerShutdown)
+	errMaxResultSizeExceeded   = dbterror.ClassServer.NewStd(errno.ErrMaxResultSizeExceeded)
+	errNetPacketTooLarge       = dbterror.ClassServer.NewStd(errno.ErrNetPacketTooLarge)
 )
 
 // DefaultCapability is the capability of the server when it is created using the default configuration.
@@ -120,7 +126,9 @@ type Server struct {
 	socket            net.Listener
 	rwlock            sync.RWMutex
 	concurrentLimiter *TokenLimiter
+	resourceLimiter   *accountResourceLimiter
 	clients           map[uint64]*clientConn
+	probeConns        int32 // the number of the probe connections without sessions, accessed atomically.
 	capability        uint32
 	dom               *domain.Domain
 	globalConnID      util.GlobalConnID
@@ -130,6 +138,8 @@ type Server struct {
 	statusServer   *http.Server
 	grpcServer     *grpc.Server
 	inShutdownMode bool
+	// pdHealth caches the PD connectivity checked by the readiness probes.
+	pdHealth pdHealth
 }
 
 // ConnectionCount gets current connection count.
@@ -228,6 +238,7 @@ func NewServer(cfg *config.Config, driver IDriver) (*Server, error) {
 		cfg:               cfg,
 		driver:            driver,
 		concurrentLimiter: NewTokenLimiter(cfg.TokenLimit),
+		resourceLimiter:   newAccountResourceLimiter(),
 		clients:           make(map[uint64]*clientConn),
 		globalConnID:      util.GlobalConnID{ServerID: 0, Is64bits: true},
 	}
@@ -389,6 +400,17 @@ func (s *Server) Run() error {
 	}
 }
 
+// shouldRedirectClients checks whether the server is shutting down and the clients should be told to reconnect to
+// other servers.
+func (s *Server) shouldRedirectClients() bool {
+	if !s.cfg.GracefulShutdownRedirect {
+		return false
+	}
+	s.rwlock.RLock()
+	defer s.rwlock.RUnlock()
+	return s.inShutdownMode
+}
+
 func (s *Server) startShutdown() {
 	s.rwlock.RLock()
 	logutil.BgLogger().Info("setting tidb-server to report unhealthy (shutting-down)")
@@ -455,6 +477,11 @@ func (s *Server) onConn(conn *clientConn) {
 		return
 	}
 
+	if conn.probe && conn.ctx == nil {
+		s.onProbeConn(ctx, conn)
+		return
+	}
+
 	logutil.Logger(ctx).Debug("new connection", zap.String("remoteAddr", conn.bufReadConn.RemoteAddr().String()))
 
 	defer func() {
@@ -482,7 +509,12 @@ func (s *Server) onConn(conn *clientConn) {
 	}
 
 	connectedTime := time.Now()
-	conn.Run(ctx)
+	if conn.probe {
+		metrics.ProbeConnCounter.Inc()
+		conn.runProbe(ctx)
+	} else {
+		conn.Run(ctx)
+	}
 
 	err = plugin.ForeachPlugin(plugin.Audit, func(p *plugin.Plugin) error {
 		// Audit plugin may be disabled before a conn is created, leading no connectionInfo in sessionVars.
@@ -504,6 +536,42 @@ func (s *Server) onConn(conn *clientConn) {
 	}
 }
 
+// onProbeConn serves the probe connection which has no session. It is counted against max-server-connections
+// and audited as the other connections, but it is not shown in the processlist.
+func (s *Server) onProbeConn(ctx context.Context, conn *clientConn) {
+	atomic.AddInt32(&s.probeConns, 1)
+	defer atomic.AddInt32(&s.probeConns, -1)
+	metrics.ProbeConnCounter.Inc()
+
+	connInfo := conn.connectInfo()
+	err := plugin.ForeachPlugin(plugin.Audit, func(p *plugin.Plugin) error {
+		authPlugin := plugin.DeclareAuditManifest(p.Manifest)
+		if authPlugin.OnConnectionEvent != nil {
+			return authPlugin.OnConnectionEvent(context.Background(), plugin.Connected, connInfo)
+		}
+		return nil
+	})
+	if err != nil {
+		terror.Log(conn.Close())
+		return
+	}
+
+	connectedTime := time.Now()
+	conn.runProbe(ctx)
+
+	connInfo.Duration = float64(time.Since(connectedTime)) / float64(time.Millisecond)
+	terror.Log(plugin.ForeachPlugin(plugin.Audit, func(p *plugin.Plugin) error {
+		authPlugin := plugin.DeclareAuditManifest(p.Manifest)
+		if authPlugin.OnConnectionEvent != nil {
+			err := authPlugin.OnConnectionEvent(context.Background(), plugin.Disconnect, connInfo)
+			if err != nil {
+				logutil.BgLogger().Warn("do connection event failed", zap.String("plugin", authPlugin.Name), zap.Error(err))
+			}
+		}
+		return nil
+	}))
+}
+
 func (cc *clientConn) connectInfo() *variable.ConnectionInfo {
 	connType := "Socket"
 	if cc.server.isUnixSocket() {
@@ -537,7 +605,7 @@ func (s *Server) checkConnectionCount() error {
 	}
 
 	s.rwlock.RLock()
-	conns := len(s.clients)
+	conns := len(s.clients) + int(atomic.LoadInt32(&s.probeConns))
 	s.rwlock.RUnlock()
 
 	if conns >= int(s.cfg.MaxServerConnections) {
@@ -608,6 +676,25 @@ func (s *Server) Kill(connectionID uint64, query bool) {
 	killConn(conn)
 }
 
+// KillQueriesByDigest kills the running queries of the SQL digest, the queries are filtered by the user and the database
+// if they are not empty. It returns the connection IDs of the killed queries.
+func (s *Server) KillQueriesByDigest(digest, user, db string) []uint64 {
+	connIDs := make([]uint64, 0)
+	for id, pi := range s.ShowProcessList() {
+		// The digest of the last query is kept after it's finished.
+		if pi.Command == mysql.ComSleep || !strings.EqualFold(pi.Digest, digest) {
+			continue
+		}
+		if (len(user) > 0 && pi.User != user) || (len(db) > 0 && !strings.EqualFold(pi.DB, db)) {
+			continue
+		}
+		s.Kill(id, true)
+		connIDs = append(connIDs, id)
+	}
+	sort.Slice(connIDs, func(i, j int) bool { return connIDs[i] < connIDs[j] })
+	return connIDs
+}
+
 // UpdateTLSConfig implements the SessionManager interface.
 func (s *Server) UpdateTLSConfig(cfg *tls.Config) {
 	atomic.StorePointer(&s.tlsConfig, unsafe.Pointer(cfg))
@@ -643,11 +730,11 @@ func (s *Server) KillAllConnections() {
 	}
 }
 
-var gracefulCloseConnectionsTimeout = 15 * time.Second
-
 // TryGracefulDown will try to gracefully close all connection first with timeout. if timeout, will close all connection directly.
+// The timeout is configured by GracefulShutdownTimeout.
 func (s *Server) TryGracefulDown() {
-	ctx, cancel := context.WithTimeout(context.Background(), gracefulCloseConnectionsTimeout)
+	timeout := time.Duration(s.cfg.GracefulShutdownTimeout) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 	done := make(chan struct{})
 	go func() {
@@ -685,7 +772,9 @@ func (s *Server) GracefulDown(ctx context.Context, done chan struct{}) {
 		case <-ticker:
 		}
 	}
-	close(done)
+	if done != nil {
+		close(done)
+	}
 }
 
 func (s *Server) kickIdleConnection() {
@@ -700,6 +789,9 @@ func (s *Server) kickIdleConnection() {
 	s.rwlock.RUnlock()
 
 	for _, cc := range conns {
+		if s.cfg.GracefulShutdownRedirect {
+			cc.writeShutdownError()
+		}
 		err := cc.Close()
 		if err != nil {
 			logutil.BgLogger().Error("close connection", zap.Error(err))
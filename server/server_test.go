@@ -15,6 +15,7 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -28,6 +29,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -1720,6 +1722,18 @@ func (cli *testServerClient) runTestStatusAPI(c *C) {
 	c.Assert(data.GitHash, Equals, versioninfo.TiDBGitHash)
 }
 
+func (cli *testServerClient) fetchReadiness(c *C, expectedCode int) readiness {
+	resp, err := cli.fetchStatus("/readyz")
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, expectedCode)
+	var data readiness
+	err = json.NewDecoder(resp.Body).Decode(&data)
+	c.Assert(err, IsNil)
+	c.Assert(data.Ready, Equals, expectedCode == http.StatusOK)
+	return data
+}
+
 // The golang sql driver (and most drivers) should have multi-statement
 // disabled by default for security reasons. Lets ensure that the behavior
 // is correct.
@@ -2021,6 +2035,113 @@ func (cli *testServerClient) runTestInitConnect(c *C) {
 	c.Assert(err, NotNil)
 }
 
+func (cli *testServerClient) runTestProbeUser(c *C, server *Server) {
+	defer config.RestoreFunc()()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Security.ProbeUser = "lb_probe"
+	})
+
+	cli.runTests(c, nil, func(dbt *DBTest) {
+		dbt.mustExec("drop user if exists 'lb_probe'@'%'")
+		dbt.mustExec("create user 'lb_probe'@'%' identified by 'probe_pass'")
+	})
+	defer cli.runTests(c, nil, func(dbt *DBTest) {
+		dbt.mustExec("drop user 'lb_probe'@'%'")
+	})
+
+	// The probe user is authenticated as usual.
+	db0, err := sql.Open("mysql", cli.getDSN(func(config *mysql.Config) {
+		config.User = "lb_probe"
+		config.Passwd = "wrong"
+		config.DBName = ""
+	}))
+	c.Assert(err, IsNil)
+	defer db0.Close()
+	c.Assert(db0.Ping(), NotNil)
+
+	db, err := sql.Open("mysql", cli.getDSN(func(config *mysql.Config) {
+		config.User = "lb_probe"
+		config.Passwd = "probe_pass"
+		config.DBName = ""
+	}))
+	c.Assert(err, IsNil)
+	defer db.Close()
+	db.SetMaxIdleConns(1)
+	c.Assert(db.Ping(), IsNil)
+	c.Assert(db.Ping(), IsNil)
+	c.Assert(atomic.LoadInt32(&server.probeConns), Equals, int32(1))
+	for _, info := range server.ShowProcessList() {
+		c.Assert(info.User, Not(Equals), "lb_probe")
+	}
+	// Nothing except pinging is allowed.
+	_, err = db.Exec("SELECT 1")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "Error 1148: The used command is not allowed with this MySQL version")
+
+	// The other users are authenticated as usual.
+	db2, err := sql.Open("mysql", cli.getDSN(func(config *mysql.Config) {
+		config.User = "lb_probe2"
+	}))
+	c.Assert(err, IsNil)
+	defer db2.Close()
+	c.Assert(db2.Ping(), NotNil)
+}
+
+func (cli *testServerClient) runTestMaxResultSize(c *C) {
+	cli.runTests(c, nil, func(dbt *DBTest) {
+		dbt.mustExec("create table max_result (a int)")
+		dbt.mustExec("insert max_result values (1), (2), (3), (4), (5)")
+
+		ctx := context.Background()
+		conn, err := dbt.db.Conn(ctx)
+		c.Assert(err, IsNil)
+		defer conn.Close()
+		countRows := func(sql string) (int, error) {
+			rows, err := conn.QueryContext(ctx, sql)
+			c.Assert(err, IsNil)
+			defer rows.Close()
+			cnt := 0
+			for rows.Next() {
+				cnt++
+			}
+			return cnt, rows.Err()
+		}
+
+		// The statement is aborted after the rows within the limit are sent.
+		_, err = conn.ExecContext(ctx, "set @@tidb_max_result_rows = 3")
+		c.Assert(err, IsNil)
+		cnt, err := countRows("select a from max_result")
+		c.Assert(err, NotNil)
+		c.Assert(err.Error(), Equals, "Error 8138: The result set of the statement exceeds tidb_max_result_rows 3")
+		c.Assert(cnt, Equals, 3)
+		cnt, err = countRows("select a from max_result where a <= 3")
+		c.Assert(err, IsNil)
+		c.Assert(cnt, Equals, 3)
+
+		// The result set is truncated with a warning.
+		_, err = conn.ExecContext(ctx, "set @@tidb_max_result_action = 'TRUNCATE'")
+		c.Assert(err, IsNil)
+		cnt, err = countRows("select a from max_result")
+		c.Assert(err, IsNil)
+		c.Assert(cnt, Equals, 3)
+		rows, err := conn.QueryContext(ctx, "show warnings")
+		c.Assert(err, IsNil)
+		var level, msg string
+		var code int
+		c.Assert(rows.Next(), IsTrue)
+		c.Assert(rows.Scan(&level, &code, &msg), IsNil)
+		c.Assert(msg, Equals, "The result set of the statement exceeds tidb_max_result_rows 3")
+		c.Assert(rows.Close(), IsNil)
+
+		// Each row of an int is sent in 2 bytes by the text protocol.
+		_, err = conn.ExecContext(ctx, "set @@tidb_max_result_rows = 0, @@tidb_max_result_bytes = 4")
+		c.Assert(err, IsNil)
+		cnt, err = countRows("select a from max_result")
+		c.Assert(err, IsNil)
+		c.Assert(cnt, Equals, 2)
+	})
+}
+
 // Client errors are only incremented when using the TiDB Server protocol,
 // and not internal SQL statements. Thus, this test is in the server-test suite.
 func (cli *testServerClient) runTestInfoschemaClientErrors(t *C) {
@@ -124,6 +124,11 @@ func (ts *tidbTestSuiteBase) SetUpSuite(c *C) {
 	cfg.Status.ReportStatus = true
 	cfg.Status.StatusPort = ts.statusPort
 	cfg.Performance.TCPKeepAlive = true
+	// Write the slow log into a temporary directory, so the tests don't leave it in the source tree.
+	cfg.Log.SlowQueryFile = filepath.Join(c.MkDir(), "tidb-slow.log")
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Log.SlowQueryFile = cfg.Log.SlowQueryFile
+	})
 	err = logutil.InitLogger(cfg.Log.ToLogConfig())
 	c.Assert(err, IsNil)
 
@@ -1045,6 +1050,18 @@ func (ts *tidbTestSuite) TestInitConnect(c *C) {
 	ts.runTestInitConnect(c)
 }
 
+func (ts *tidbTestSerialSuite) TestProbeUser(c *C) {
+	// The probe connections are answered without the sessions when the domain is set.
+	ts.server.SetDomain(ts.domain)
+	defer ts.server.SetDomain(nil)
+	ts.runTestProbeUser(c, ts.server)
+}
+
+func (ts *tidbTestSuite) TestMaxResultSize(c *C) {
+	c.Parallel()
+	ts.runTestMaxResultSize(c)
+}
+
 func (ts *tidbTestSuite) TestSumAvg(c *C) {
 	c.Parallel()
 	ts.runTestSumAvg(c)
@@ -1112,12 +1129,39 @@ func (ts *tidbTestSuite) TestGracefulShutdown(c *C) {
 
 	_, err = cli.fetchStatus("/status") // server is up
 	c.Assert(err, IsNil)
+	resp, err := cli.fetchStatus("/readyz") // domain is not set
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusServiceUnavailable)
+	c.Assert(resp.Body.Close(), IsNil)
+	server.SetDomain(ts.domain)
+	readiness := cli.fetchReadiness(c, http.StatusOK)
+	c.Assert(readiness.Checks, DeepEquals, map[string]string{"shutdown": "ok", "pd": "ok", "schema": "ok"})
+	// The PD connectivity is cached, the probes within readyzPDCheckInterval don't get timestamps from PD.
+	server.pdHealth.Lock()
+	checkedAt := server.pdHealth.checkedAt
+	server.pdHealth.Unlock()
+	cli.fetchReadiness(c, http.StatusOK)
+	server.pdHealth.Lock()
+	c.Assert(server.pdHealth.checkedAt, Equals, checkedAt)
+	server.pdHealth.Unlock()
+	ts.domain.SchemaValidator.Stop()
+	readiness = cli.fetchReadiness(c, http.StatusServiceUnavailable)
+	c.Assert(readiness.Checks["schema"], Equals, "schema lease is expired")
+	c.Assert(readiness.Checks["pd"], Equals, "ok")
+	ts.domain.SchemaValidator.Restart()
+	cli.fetchReadiness(c, http.StatusOK)
 
 	go server.Close()
 	time.Sleep(time.Millisecond * 500)
 
-	resp, _ := cli.fetchStatus("/status") // should return 5xx code
+	resp, _ = cli.fetchStatus("/status") // should return 5xx code
 	c.Assert(resp.StatusCode, Equals, 500)
+	readiness = cli.fetchReadiness(c, http.StatusServiceUnavailable)
+	c.Assert(readiness.Checks["shutdown"], Equals, "server is shutting down")
+	resp, err = cli.fetchStatus("/healthz") // still alive while draining
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(resp.Body.Close(), IsNil)
 
 	time.Sleep(time.Second * 2)
 
@@ -89,6 +89,9 @@ const (
 		Create_Tablespace_Priv  ENUM('N','Y') NOT NULL DEFAULT 'N',
 		Repl_slave_priv	    	ENUM('N','Y') NOT NULL DEFAULT 'N',
 		Repl_client_priv		ENUM('N','Y') NOT NULL DEFAULT 'N',
+		User_attributes			JSON,
+		max_questions			INT UNSIGNED NOT NULL DEFAULT 0,
+		max_updates				INT UNSIGNED NOT NULL DEFAULT 0,
 		PRIMARY KEY (Host, User));`
 	// CreateGlobalPrivTable is the SQL statement creates Global scope privilege table in system db.
 	CreateGlobalPrivTable = "CREATE TABLE IF NOT EXISTS mysql.global_priv (" +
@@ -339,6 +342,65 @@ const (
 		WITH_GRANT_OPTION enum('N','Y') NOT NULL DEFAULT 'N',
 		PRIMARY KEY (USER,HOST,PRIV)
 	  );`
+	// CreatePrivilegeChangesTable records the account-management statements and the grants of the
+	// affected users before and after them. It's append-only.
+	CreatePrivilegeChangesTable = `CREATE TABLE IF NOT EXISTS mysql.privilege_changes (
+		ID bigint(64) NOT NULL AUTO_INCREMENT,
+		CHANGE_TIME timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		OPERATOR varchar(288) NOT NULL DEFAULT '',
+		USER char(32) NOT NULL DEFAULT '',
+		HOST char(255) NOT NULL DEFAULT '',
+		STMT text,
+		BEFORE_GRANTS text,
+		AFTER_GRANTS text,
+		ROLLBACK_STMTS text,
+		PRIMARY KEY (ID),
+		KEY idx_user (USER, HOST),
+		KEY idx_time (CHANGE_TIME)
+	);`
+	// CreateHealthReportTable stores the daily health reports of the cluster.
+	CreateHealthReportTable = `CREATE TABLE IF NOT EXISTS mysql.health_report (
+		ID bigint(64) NOT NULL AUTO_INCREMENT,
+		CREATE_TIME timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		START_TIME datetime NOT NULL,
+		END_TIME datetime NOT NULL,
+		REPORT json,
+		PRIMARY KEY (ID),
+		KEY idx_end_time (END_TIME)
+	);`
+	// CreateDDLPolicyTable stores the policies that forbid the DDL on the matched objects in a time window of the day.
+	// OBJECT is a table filter rule like "db_prod.*", the window wraps around midnight if START_TIME > END_TIME.
+	// The window is in the system time zone of the TiDB server rather than the session time zone.
+	CreateDDLPolicyTable = `CREATE TABLE IF NOT EXISTS mysql.ddl_policy (
+		ID bigint(64) NOT NULL AUTO_INCREMENT,
+		OBJECT varchar(256) NOT NULL,
+		START_TIME time NOT NULL,
+		END_TIME time NOT NULL,
+		COMMENT varchar(1024) NOT NULL DEFAULT '',
+		CREATE_TIME timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		PRIMARY KEY (ID)
+	);`
+	// CreateStatsEstimationDeviatedTable stores the tables whose estimated rows deviate from the actual rows in the
+	// executions on any TiDB instance, they're analyzed by the auto analyze on the stats owner.
+	CreateStatsEstimationDeviatedTable = `CREATE TABLE IF NOT EXISTS mysql.stats_estimation_deviated (
+		table_id bigint(64) NOT NULL,
+		mark_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (table_id)
+	);`
+	// CreateStatsBulkDeletedTable stores the tables whose most rows are deleted on any TiDB instance, they're analyzed
+	// by the auto analyze on the stats owner even if their remaining rows are few.
+	CreateStatsBulkDeletedTable = `CREATE TABLE IF NOT EXISTS mysql.stats_bulk_deleted (
+		table_id bigint(64) NOT NULL,
+		mark_time timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (table_id)
+	);`
+	// CreateSchemaUnusedIndexesView lists the indexes never read since the usage is collected, same as the view of
+	// the MySQL sys schema.
+	CreateSchemaUnusedIndexesView = `CREATE DEFINER = 'root'@'%' SQL SECURITY INVOKER VIEW sys.schema_unused_indexes AS
+		SELECT TABLE_SCHEMA AS OBJECT_SCHEMA, TABLE_NAME AS OBJECT_NAME, INDEX_NAME
+		FROM information_schema.tidb_index_usage
+		WHERE QUERY_COUNT = 0 AND INDEX_NAME != 'PRIMARY'
+			AND TABLE_SCHEMA NOT IN ('mysql', 'sys', 'INFORMATION_SCHEMA', 'PERFORMANCE_SCHEMA', 'METRICS_SCHEMA')`
 )
 
 // bootstrap initiates system DB for a store.
@@ -498,11 +560,27 @@ const (
 	version71 = 71
 	// version72 adds snapshot column for mysql.stats_meta
 	version72 = 72
+	// version73 adds mysql.privilege_changes to audit the account-management statements
+	version73 = 73
+	// version74 adds mysql.user.User_attributes to store the user attributes
+	version74 = 74
+	// version75 adds mysql.health_report to store the daily health reports
+	version75 = 75
+	// version76 adds mysql.ddl_policy to restrict the DDL by time window and by object pattern
+	version76 = 76
+	// version77 adds the sys schema and sys.schema_unused_indexes
+	version77 = 77
+	// version78 adds mysql.user.max_questions and mysql.user.max_updates for the account resource limits
+	version78 = 78
+	// version79 adds mysql.stats_estimation_deviated
+	version79 = 79
+	// version80 adds mysql.stats_bulk_deleted
+	version80 = 80
 )
 
 // currentBootstrapVersion is defined as a variable, so we can modify its value for testing.
 // please make sure this is the largest version
-var currentBootstrapVersion int64 = version72
+var currentBootstrapVersion int64 = version80
 
 var (
 	bootstrapVersion = []func(Session, int64){
@@ -578,6 +656,14 @@ var (
 		upgradeToVer70,
 		upgradeToVer71,
 		upgradeToVer72,
+		upgradeToVer73,
+		upgradeToVer74,
+		upgradeToVer75,
+		upgradeToVer76,
+		upgradeToVer77,
+		upgradeToVer78,
+		upgradeToVer79,
+		upgradeToVer80,
 	}
 )
 
@@ -1529,6 +1615,64 @@ func upgradeToVer72(s Session, ver int64) {
 	doReentrantDDL(s, "ALTER TABLE mysql.stats_meta ADD COLUMN snapshot BIGINT(64) UNSIGNED NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
 }
 
+func upgradeToVer73(s Session, ver int64) {
+	if ver >= version73 {
+		return
+	}
+	doReentrantDDL(s, CreatePrivilegeChangesTable)
+}
+
+func upgradeToVer74(s Session, ver int64) {
+	if ver >= version74 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `User_attributes` JSON", infoschema.ErrColumnExists)
+}
+
+func upgradeToVer75(s Session, ver int64) {
+	if ver >= version75 {
+		return
+	}
+	doReentrantDDL(s, CreateHealthReportTable)
+}
+
+func upgradeToVer76(s Session, ver int64) {
+	if ver >= version76 {
+		return
+	}
+	doReentrantDDL(s, CreateDDLPolicyTable)
+}
+
+func upgradeToVer77(s Session, ver int64) {
+	if ver >= version77 {
+		return
+	}
+	doReentrantDDL(s, "CREATE DATABASE IF NOT EXISTS sys")
+	doReentrantDDL(s, CreateSchemaUnusedIndexesView, infoschema.ErrTableExists)
+}
+
+func upgradeToVer78(s Session, ver int64) {
+	if ver >= version78 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `max_questions` INT UNSIGNED NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `max_updates` INT UNSIGNED NOT NULL DEFAULT 0", infoschema.ErrColumnExists)
+}
+
+func upgradeToVer79(s Session, ver int64) {
+	if ver >= version79 {
+		return
+	}
+	doReentrantDDL(s, CreateStatsEstimationDeviatedTable)
+}
+
+func upgradeToVer80(s Session, ver int64) {
+	if ver >= version80 {
+		return
+	}
+	doReentrantDDL(s, CreateStatsBulkDeletedTable)
+}
+
 func writeOOMAction(s Session) {
 	comment := "oom-action is `log` by default in v3.0.x, `cancel` by default in v4.0.11+"
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO %n.%n VALUES (%?, %?, %?) ON DUPLICATE KEY UPDATE VARIABLE_VALUE= %?`,
@@ -1607,6 +1751,20 @@ func doDDLWorks(s Session) {
 	mustExecute(s, CreateStatsFMSketchTable)
 	// Create global_grants
 	mustExecute(s, CreateGlobalGrantsTable)
+	// Create privilege_changes
+	mustExecute(s, CreatePrivilegeChangesTable)
+	// Create health_report
+	mustExecute(s, CreateHealthReportTable)
+	// Create ddl_policy
+	mustExecute(s, CreateDDLPolicyTable)
+	// Create stats_estimation_deviated
+	mustExecute(s, CreateStatsEstimationDeviatedTable)
+	// Create stats_bulk_deleted
+	mustExecute(s, CreateStatsBulkDeletedTable)
+	// Create sys schema and its views.
+	mustExecute(s, "CREATE DATABASE IF NOT EXISTS sys")
+	// The view may have been created if the bootstrap is interrupted and retried.
+	doReentrantDDL(s, CreateSchemaUnusedIndexesView, infoschema.ErrTableExists)
 }
 
 // doDMLWorks executes DML statements in bootstrap stage.
@@ -1617,7 +1775,7 @@ func doDMLWorks(s Session) {
 
 	// Insert a default user with empty password.
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO mysql.user VALUES
-		("%", "root", "", "mysql_native_password", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y")`)
+		("%", "root", "", "mysql_native_password", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y", null, 0, 0)`)
 
 	// Init global system variables table.
 	values := make([]string, 0, len(variable.GetSysVars()))
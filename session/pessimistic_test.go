@@ -1678,6 +1678,25 @@ func (s *testPessimisticSuite) TestPessimisticTxnWithDDLAddDropColumn(c *C) {
 	tk.MustQuery("select * from t1").Check(testkit.Rows("1", "2", "5"))
 }
 
+func (s *testPessimisticSuite) TestPessimisticTxnWithMultiSchemaChange(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk2 := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t1")
+	tk.MustExec("create table t1 (c1 int primary key, c2 int)")
+	tk2.MustExec("set @@global.tidb_enable_change_multi_schema = 1")
+	defer tk2.MustExec("set @@global.tidb_enable_change_multi_schema = 0")
+
+	// The columns added along with the index can't be amended, the transaction can't be committed without the new index.
+	tk.MustExec("set tidb_enable_amend_pessimistic_txn = 1;")
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("insert into t1 values (1, 1)")
+	tk2.MustExec("alter table t1 add column c3 int, add index k2(c2)")
+	err := tk.ExecToErr("commit")
+	c.Assert(err, NotNil)
+	tk2.MustExec("admin check table t1")
+	tk2.MustQuery("select * from t1").Check(testkit.Rows())
+}
+
 func (s *testPessimisticSuite) TestPessimisticTxnWithDDLChangeColumn(c *C) {
 	tk := testkit.NewTestKitWithInit(c, s.store)
 	tk2 := testkit.NewTestKitWithInit(c, s.store)
@@ -649,8 +649,9 @@ func (s *SchemaAmender) AmendTxn(ctx context.Context, startInfoSchema tikv.Schem
 	amendCollector := newAmendCollector()
 	for i, tblID := range change.PhyTblIDS {
 		actionType := change.ActionTypes[i]
-		// Check amendable flags, return if not supported flags exist.
-		if actionType&(^amendableType) != 0 {
+		// Check amendable flags, return if not supported flags exist. The action types which can't be mapped to
+		// the bits are recorded as 0, they are not supported either.
+		if actionType == 0 || actionType&(^amendableType) != 0 {
 			logutil.Logger(ctx).Info("amend action type not supported for txn", zap.Int64("tblID", tblID), zap.Uint64("actionType", actionType))
 			return nil, errors.Trace(table.ErrUnsupportedOp)
 		}
@@ -83,6 +83,7 @@ import (
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/tableutil"
 	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/pingcap/tidb/util/txnsummary"
 	tikvstore "github.com/tikv/client-go/v2/kv"
 	"github.com/tikv/client-go/v2/tikv"
 	tikvutil "github.com/tikv/client-go/v2/util"
@@ -540,7 +541,37 @@ func (s *session) doCommit(ctx context.Context) error {
 		s.txn.SetOption(kv.KVFilter, temporaryTableKVFilter(tables))
 	}
 
-	return s.txn.Commit(tikvutil.SetSessionID(ctx, sessVars.ConnectionID))
+	startTS := s.txn.StartTS()
+	var sqlDigests []string
+	if info := s.txn.getTxnInfo(); info != nil {
+		sqlDigests = info.AllSQLDigests
+	}
+	err = s.txn.Commit(tikvutil.SetSessionID(ctx, sessVars.ConnectionID))
+	if err != nil {
+		return err
+	}
+	s.recordTxnSummary(ctx, startTS, sqlDigests)
+	return nil
+}
+
+// recordTxnSummary records the commit breakdown of the committed transaction into the global transaction summary.
+// The commit detail and the commit info are referenced as they are, and only decoded when the summary is read.
+func (s *session) recordTxnSummary(ctx context.Context, startTS uint64, sqlDigests []string) {
+	if !txnsummary.GlobalTxnSummary.Enabled() {
+		return
+	}
+	record := &txnsummary.TxnRecord{
+		StartTS:      startTS,
+		ConnectionID: s.sessionVars.ConnectionID,
+		FinishTime:   time.Now(),
+		SQLDigests:   sqlDigests,
+		TxnInfo:      s.sessionVars.LastTxnInfo,
+	}
+	// The commit detail is allocated for each statement and isn't changed after the commit.
+	if val, ok := ctx.Value(tikvutil.CommitDetailCtxKey).(**tikvutil.CommitDetails); ok {
+		record.CommitDetail = *val
+	}
+	txnsummary.GlobalTxnSummary.Push(record)
 }
 
 type temporaryTableKVFilter map[int64]tableutil.TempTable
@@ -1448,6 +1479,7 @@ func (s *session) ExecRestrictedStmt(ctx context.Context, stmtNode ast.StmtNode,
 	metrics.SessionRestrictedSQLCounter.Inc()
 
 	ctx = context.WithValue(ctx, execdetails.StmtExecDetailKey, &execdetails.StmtExecDetails{})
+	ctx = context.WithValue(ctx, execdetails.StmtRPCStatsKey, execdetails.NewStmtRPCStats())
 	ctx = context.WithValue(ctx, tikvutil.ExecDetailsKey, &tikvutil.ExecDetails{})
 	rs, err := se.ExecuteStmt(ctx, stmtNode)
 	if err != nil {
@@ -1491,12 +1523,16 @@ func (s *session) ExecuteStmt(ctx context.Context, stmtNode ast.StmtNode) (sqlex
 	}
 	normalizedSQL, digest := s.sessionVars.StmtCtx.SQLDigest()
 	if variable.TopSQLEnabled() {
+		ctx = topsql.AttachSessionInfo(ctx, s.sessionVars.CurrentDB, s.sessionVars.User)
 		ctx = topsql.AttachSQLInfo(ctx, normalizedSQL, digest, "", nil)
 	}
 
 	if err := s.validateStatementReadOnlyInStaleness(stmtNode); err != nil {
 		return nil, err
 	}
+	if err := s.validateStatementInReadOnlyTxn(stmtNode); err != nil {
+		return nil, err
+	}
 
 	// Uncorrelated subqueries will execute once when building plan, so we reset process info before building plan.
 	cmd32 := atomic.LoadUint32(&s.GetSessionVars().CommandValue)
@@ -1556,23 +1592,38 @@ func (s *session) validateStatementReadOnlyInStaleness(stmtNode ast.StmtNode) er
 	if !vars.TxnCtx.IsStaleness && vars.TxnReadTS.PeakTxnReadTS() == 0 {
 		return nil
 	}
-	errMsg := "only support read-only statement during read-only staleness transactions"
+	if !isReadOnlyInTxn(stmtNode, vars) {
+		return errors.New("only support read-only statement during read-only staleness transactions")
+	}
+	return nil
+}
+
+// validateStatementInReadOnlyTxn rejects the writes in the transaction started by START TRANSACTION READ ONLY,
+// whose reads are routed to the followers.
+func (s *session) validateStatementInReadOnlyTxn(stmtNode ast.StmtNode) error {
+	vars := s.GetSessionVars()
+	if !vars.TxnCtx.IsReadOnly || !vars.InTxn() {
+		return nil
+	}
+	if !isReadOnlyInTxn(stmtNode, vars) {
+		return errCantExecuteInReadOnlyTxn
+	}
+	return nil
+}
+
+// isReadOnlyInTxn checks whether the statement doesn't write the data in the transaction.
+func isReadOnlyInTxn(stmtNode ast.StmtNode, vars *variable.SessionVars) bool {
 	node := stmtNode.(ast.Node)
 	switch node.(type) {
 	case *ast.SplitRegionStmt:
-		return nil
+		return true
 	case *ast.SelectStmt, *ast.ExplainStmt, *ast.DoStmt, *ast.ShowStmt, *ast.SetOprStmt, *ast.ExecuteStmt, *ast.SetOprSelectList:
-		if !planner.IsReadOnly(stmtNode, vars) {
-			return errors.New(errMsg)
-		}
-		return nil
+		return planner.IsReadOnly(stmtNode, vars)
 	default:
 	}
 	// covered DeleteStmt/InsertStmt/UpdateStmt/CallStmt/LoadDataStmt
-	if _, ok := stmtNode.(ast.DMLNode); ok {
-		return errors.New(errMsg)
-	}
-	return nil
+	_, ok := stmtNode.(ast.DMLNode)
+	return !ok
 }
 
 // querySpecialKeys contains the keys of special query, the special query will handled by handleQuerySpecial method.
@@ -1645,6 +1696,7 @@ func runStmt(ctx context.Context, se *session, s sqlexec.Statement) (rs sqlexec.
 	}
 
 	err = finishStmt(ctx, se, err, s)
+	err = s.(*executor.ExecStmt).RedactError(err)
 	if se.hasQuerySpecial() {
 		// The special query will be handled later in handleQuerySpecial,
 		// then should call the ExecStmt.FinishExecuteStmt to finish this statement.
@@ -1678,7 +1730,19 @@ type execStmtResult struct {
 	sql sqlexec.Statement
 }
 
+func (rs *execStmtResult) Next(ctx context.Context, req *chunk.Chunk) error {
+	err := rs.RecordSet.Next(ctx, req)
+	if err != nil {
+		err = rs.sql.(*executor.ExecStmt).RedactError(err)
+	}
+	return err
+}
+
 func (rs *execStmtResult) Close() error {
+	return rs.sql.(*executor.ExecStmt).RedactError(rs.close())
+}
+
+func (rs *execStmtResult) close() error {
 	se := rs.se
 	if err := resetCTEStorageMap(se); err != nil {
 		return finishStmt(context.Background(), se, err, rs.sql)
@@ -2360,7 +2424,8 @@ func loadDefOOMAction(se *session) (string, error) {
 }
 
 var (
-	errResultIsEmpty = dbterror.ClassExecutor.NewStd(errno.ErrResultIsEmpty)
+	errResultIsEmpty            = dbterror.ClassExecutor.NewStd(errno.ErrResultIsEmpty)
+	errCantExecuteInReadOnlyTxn = dbterror.ClassSession.NewStd(errno.ErrCantExecuteInReadOnlyTransaction)
 )
 
 // loadParameter loads read-only parameter from mysql.tidb
@@ -2493,6 +2558,12 @@ func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
 	dom.TelemetryReportLoop(se6)
 	dom.TelemetryRotateSubWindowLoop(se6)
 
+	seHealthReport, err := createSession(store)
+	if err != nil {
+		return nil, err
+	}
+	dom.HealthReportLoop(seHealthReport)
+
 	se7, err := createSession(store)
 	if err != nil {
 		return nil, err
@@ -3168,6 +3168,27 @@ func (s *testSessionSuite2) TestReplicaRead(c *C) {
 	c.Assert(tk.Se.GetSessionVars().GetReplicaRead(), Equals, kv.ReplicaReadFollower)
 	tk.MustExec("set @@tidb_replica_read = 'leader';")
 	c.Assert(tk.Se.GetSessionVars().GetReplicaRead(), Equals, kv.ReplicaReadLeader)
+
+	// Only the read-only transactions read from the followers.
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("insert into t values (1)")
+	tk.MustExec("set @@tidb_replica_read = 'follower-in-read-only-txn';")
+	c.Assert(tk.Se.GetSessionVars().GetReplicaRead(), Equals, kv.ReplicaReadLeader)
+	tk.MustExec("begin")
+	c.Assert(tk.Se.GetSessionVars().GetReplicaRead(), Equals, kv.ReplicaReadLeader)
+	tk.MustExec("commit")
+	tk.MustExec("start transaction read only")
+	c.Assert(tk.Se.GetSessionVars().GetReplicaRead(), Equals, kv.ReplicaReadFollower)
+	tk.MustQuery("select a from t").Check(testkit.Rows("1"))
+	_, err = tk.Exec("insert into t values (2)")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[session:1792]Cannot execute statement in a READ ONLY transaction.")
+	tk.MustExec("commit")
+	c.Assert(tk.Se.GetSessionVars().GetReplicaRead(), Equals, kv.ReplicaReadLeader)
+	tk.MustExec("insert into t values (2)")
+	tk.MustQuery("select a from t").Check(testkit.Rows("1", "2"))
 }
 
 func (s *testSessionSuite3) TestIsolationRead(c *C) {
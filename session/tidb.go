@@ -116,11 +116,6 @@ var (
 
 	// statsLease is the time for reload stats table.
 	statsLease = int64(3 * time.Second)
-
-	// indexUsageSyncLease is the time for index usage synchronization.
-	// Because we have not completed GC and other functions, we set it to 0.
-	// TODO: Set indexUsageSyncLease to 60s.
-	indexUsageSyncLease = int64(0 * time.Second)
 )
 
 // ResetStoreForWithTiKVTest is only used in the test code.
@@ -158,12 +153,12 @@ func SetStatsLease(lease time.Duration) {
 
 // SetIndexUsageSyncLease changes the default index usage sync lease time for loading info.
 func SetIndexUsageSyncLease(lease time.Duration) {
-	atomic.StoreInt64(&indexUsageSyncLease, int64(lease))
+	variable.IndexUsageSyncLease.Store(lease)
 }
 
 // GetIndexUsageSyncLease returns the index usage sync lease time.
 func GetIndexUsageSyncLease() time.Duration {
-	return time.Duration(atomic.LoadInt64(&indexUsageSyncLease))
+	return variable.IndexUsageSyncLease.Load()
 }
 
 // DisableStats4Test disables the stats for tests.
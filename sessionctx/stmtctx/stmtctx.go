@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/resourcegrouptag"
+	"github.com/pingcap/tidb/util/tracing"
 	"github.com/tikv/client-go/v2/util"
 	atomic2 "go.uber.org/atomic"
 	"go.uber.org/zap"
@@ -49,6 +50,27 @@ func AllocateTaskID() uint64 {
 	return atomic.AddUint64(&taskIDAlloc, 1)
 }
 
+// instanceID identifies this TiDB instance in the statement instance IDs.
+var instanceID atomic2.String
+
+// SetInstanceID sets the ID of this TiDB instance in the cluster, which prefixes the statement instance IDs.
+func SetInstanceID(id string) {
+	instanceID.Store(id)
+}
+
+// StmtInstanceID returns the ID of this statement execution, which is unique across the cluster. It's the TaskID
+// prefixed by the ID of the TiDB instance, e.g. "c53b0f4b-8cd6-4f8c-9e0b-3d5e1a4e9a5d:42".
+func (sc *StatementContext) StmtInstanceID() string {
+	if sc.TaskID == 0 {
+		return ""
+	}
+	id := strconv.FormatUint(sc.TaskID, 10)
+	if prefix := instanceID.Load(); prefix != "" {
+		return prefix + ":" + id
+	}
+	return id
+}
+
 // SQLWarn relates a sql warning and it's level.
 type SQLWarn struct {
 	Level string
@@ -165,6 +187,13 @@ type StatementContext struct {
 	TaskID                uint64 // unique ID for an execution of a statement
 	TaskMapBakTS          uint64 // counter for
 
+	// SQLMode is the SQL mode of the coprocessor request, it is used to evaluate
+	// the pushed down expressions. TiDB sessions use SessionVars.SQLMode instead.
+	SQLMode mysql.SQLMode
+
+	// AdmissionQueueTime is the time the statement is queued by the admission control.
+	AdmissionQueueTime time.Duration
+
 	// stmtCache is used to store some statement-related values.
 	stmtCache map[StmtCacheKey]interface{}
 	// resourceGroupTag cache for the current statement resource group tag.
@@ -172,6 +201,12 @@ type StatementContext struct {
 	// Map to store all CTE storages of current SQL.
 	// Will clean up at the end of the execution.
 	CTEStorageMap interface{}
+	// StatsTrace records the cardinality estimation steps, it's only set when explaining the statement
+	// in the stats_trace format.
+	StatsTrace *tracing.StatsTracer
+	// IndexCandidatesTrace records the decisions on the access paths, it's only set when explaining the statement
+	// in the index_candidates format.
+	IndexCandidatesTrace *tracing.IndexCandidatesTracer
 }
 
 // StmtHints are SessionVars related sql hints.
@@ -211,6 +246,8 @@ const (
 	StmtNowTsCacheKey StmtCacheKey = iota
 	// StmtSafeTSCacheKey is a variable for safeTS calculation/cache of one stmt.
 	StmtSafeTSCacheKey
+	// StmtCheckConstraintsCacheKey is a variable for the check constraint expressions of the written tables of one stmt.
+	StmtCheckConstraintsCacheKey
 )
 
 // GetOrStoreStmtCache gets the cached value of the given key if it exists, otherwise stores the value.
@@ -577,6 +614,8 @@ func (sc *StatementContext) MergeExecDetails(details *execdetails.ExecDetails, c
 		if sc.mu.execDetails.CommitDetail == nil {
 			sc.mu.execDetails.CommitDetail = commitDetails
 		} else {
+			// The commit details may also be referenced by the transaction summary, so merge them into a copy.
+			sc.mu.execDetails.CommitDetail = sc.mu.execDetails.CommitDetail.Clone()
 			sc.mu.execDetails.CommitDetail.Merge(commitDetails)
 		}
 	}
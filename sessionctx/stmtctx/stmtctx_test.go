@@ -97,3 +97,13 @@ func (s *stmtctxSuit) TestStatementContextPushDownFLags(c *C) {
 		c.Assert(got, Equals, tt.out, Commentf("get %v, want %v", got, tt.out))
 	}
 }
+
+func (s *stmtctxSuit) TestStmtInstanceID(c *C) {
+	sc := new(stmtctx.StatementContext)
+	c.Assert(sc.StmtInstanceID(), Equals, "")
+	sc.TaskID = 42
+	c.Assert(sc.StmtInstanceID(), Equals, "42")
+	stmtctx.SetInstanceID("tidb-1")
+	defer stmtctx.SetInstanceID("")
+	c.Assert(sc.StmtInstanceID(), Equals, "tidb-1:42")
+}
@@ -162,6 +162,9 @@ type TransactionContext struct {
 	IsPessimistic  bool
 	// IsStaleness indicates whether the txn is read only staleness txn.
 	IsStaleness bool
+	// IsReadOnly indicates whether the txn is started by START TRANSACTION READ ONLY with
+	// tidb_replica_read set to follower-in-read-only-txn, whose reads are routed to the followers.
+	IsReadOnly bool
 	// IsExplicit indicates whether the txn is an interactive txn, which is typically started with a BEGIN
 	// or START TRANSACTION statement, or by setting autocommit to 0.
 	IsExplicit bool
@@ -273,6 +276,7 @@ func (tc *TransactionContext) Cleanup() {
 	tc.tdmLock.Unlock()
 	tc.pessimisticLockCache = nil
 	tc.IsStaleness = false
+	tc.IsReadOnly = false
 }
 
 // ClearDelta clears the delta map.
@@ -371,7 +375,10 @@ type SessionVars struct {
 	BatchSize
 	// DMLBatchSize indicates the number of rows batch-committed for a statement.
 	// It will be used when using LOAD DATA or BatchInsert or BatchDelete is on.
-	DMLBatchSize        int
+	DMLBatchSize int
+	// OnDupBatchSize indicates the number of rows checked for duplicate keys in a batch by
+	// INSERT ... ON DUPLICATE KEY UPDATE, 0 means all the rows are checked in one batch.
+	OnDupBatchSize      int
 	RetryLimit          int64
 	DisableTxnAutoRetry bool
 	// UsersLock is a lock for user defined variables.
@@ -485,6 +492,9 @@ type SessionVars struct {
 	// AllowAggPushDown can be set to false to forbid aggregation push down.
 	AllowAggPushDown bool
 
+	// ForceAggPushDown can be set to true to push the aggregation down across the joins without estimating the reduction of rows.
+	ForceAggPushDown bool
+
 	// AllowBCJ means allow broadcast join.
 	AllowBCJ bool
 
@@ -499,6 +509,18 @@ type SessionVars struct {
 	// AllowDistinctAggPushDown can be set true to allow agg with distinct push down to tikv/tiflash.
 	AllowDistinctAggPushDown bool
 
+	// DecorrelateNonEqAgg can be set true to allow rewriting the correlated aggregation whose correlated condition
+	// is a comparison of an inner column and an outer column into a window aggregation.
+	DecorrelateNonEqAgg bool
+
+	// EnableNullAwareAntiJoin can be set true to allow the null-aware hash join for the anti semi joins converted
+	// from `not in (subq)` with nullable operands.
+	EnableNullAwareAntiJoin bool
+
+	// EnableHistogramJoinEstimation can be set true to estimate the row count of the equi-joins on a single column by
+	// aligning the histograms of the join keys, instead of by the NDVs of the join keys.
+	EnableHistogramJoinEstimation bool
+
 	// MultiStatementMode permits incorrect client library usage. Not recommended to be turned on.
 	MultiStatementMode int
 
@@ -534,6 +556,10 @@ type SessionVars struct {
 	// CorrelationThreshold is the guard to enable row count estimation using column order correlation.
 	CorrelationThreshold float64
 
+	// EstimationDeviationRatio is the ratio of the actual rows to the estimated rows of a reader, above which the
+	// statistics of the table are considered stale.
+	EstimationDeviationRatio float64
+
 	// CorrelationExpFactor is used to control the heuristic approach of row count estimation when CorrelationThreshold is not met.
 	CorrelationExpFactor int
 
@@ -569,6 +595,8 @@ type SessionVars struct {
 	// Per-connection time zones. Each client that connects has its own time zone setting, given by the session time_zone variable.
 	// See https://dev.mysql.com/doc/refman/5.7/en/time-zone-support.html
 	TimeZone *time.Location
+	// timeZoneVersion is the version of the cached time zone locations when TimeZone is loaded.
+	timeZoneVersion uint64
 
 	SQLMode mysql.SQLMode
 
@@ -726,6 +754,9 @@ type SessionVars struct {
 	// allowInSubqToJoinAndAgg can be set to false to forbid rewriting the semi join to inner join with agg.
 	allowInSubqToJoinAndAgg bool
 
+	// decorrelateScalarSubquery can be set false to keep the apply of the correlated aggregation of a scalar subquery.
+	decorrelateScalarSubquery bool
+
 	// preferRangeScan allows optimizer to always prefer range scan over table scan.
 	preferRangeScan bool
 
@@ -735,6 +766,9 @@ type SessionVars struct {
 	// replicaRead is used for reading data from replicas, only follower is supported at this time.
 	replicaRead kv.ReplicaReadType
 
+	// replicaReadInReadOnlyTxn indicates reading data from followers in the read-only transactions only.
+	replicaReadInReadOnlyTxn bool
+
 	// IsolationReadEngines is used to isolation read, tidb only read from the stores whose engine type is in the engines.
 	IsolationReadEngines map[kv.StoreType]struct{}
 
@@ -744,6 +778,9 @@ type SessionVars struct {
 	// negative value means nowait, 0 means default behavior, others means actual wait time
 	LockWaitTimeout int64
 
+	// SQLHints are the hints injected to the statements by their digests, see sql_hints.
+	SQLHints map[string][]*ast.TableOptimizerHint
+
 	// MetricSchemaStep indicates the step when query metric schema.
 	MetricSchemaStep int64
 	// MetricSchemaRangeDuration indicates the step when query metric schema.
@@ -827,6 +864,13 @@ type SessionVars struct {
 	// AnalyzeVersion indicates how TiDB collect and use analyzed statistics.
 	AnalyzeVersion int
 
+	// AnalyzeAdaptiveBuckets indicates whether to select the bucket number of the column histograms adaptively.
+	AnalyzeAdaptiveBuckets bool
+
+	// StatsLoadSyncWait is the max time in milliseconds to wait for loading the needed column and index histograms when
+	// building the plan.
+	StatsLoadSyncWait int64
+
 	// EnableIndexMergeJoin indicates whether to enable index merge join.
 	EnableIndexMergeJoin bool
 
@@ -836,6 +880,9 @@ type SessionVars struct {
 	// TiDBEnableExchangePartition indicates whether to enable exchange partition
 	TiDBEnableExchangePartition bool
 
+	// TiDBEnableReorganizePartition indicates whether to enable reorganize partition
+	TiDBEnableReorganizePartition bool
+
 	// AllowFallbackToTiKV indicates the engine types whose unavailability triggers fallback to TiKV.
 	// Now we only support TiFlash.
 	AllowFallbackToTiKV map[kv.StoreType]struct{}
@@ -856,6 +903,28 @@ type SessionVars struct {
 	// EnableStableResultMode if stabilize query results.
 	EnableStableResultMode bool
 
+	// MaxEstimatedCost is the max estimated cost of the optimized plan of a statement, 0 means no limit.
+	MaxEstimatedCost float64
+
+	// MaxEstimatedRows is the max estimated row count of the operators in the optimized plan of a statement, 0 means no limit.
+	MaxEstimatedRows int64
+
+	// MaxEstimatedCostAction indicates what to do when the estimated cost of a statement exceeds MaxEstimatedCost,
+	// or its estimated row count exceeds MaxEstimatedRows.
+	MaxEstimatedCostAction string
+
+	// MaxResultRows is the max number of rows sent to the client by a statement, 0 means no limit.
+	MaxResultRows int64
+
+	// MaxResultBytes is the max size of the rows sent to the client by a statement, 0 means no limit.
+	MaxResultBytes int64
+
+	// MaxResultAction indicates what to do when the result set of a statement exceeds MaxResultRows or MaxResultBytes.
+	MaxResultAction string
+
+	// FullTextTokenizer is the name of the tokenizer used by `MATCH ... AGAINST`.
+	FullTextTokenizer string
+
 	// LocalTemporaryTables is *infoschema.LocalTemporaryTables, use interface to avoid circle dependency.
 	// It's nil if there is no local temporary table.
 	LocalTemporaryTables interface{}
@@ -916,6 +985,20 @@ func (s *SessionVars) BuildParserConfig() parser.ParserConfig {
 	}
 }
 
+const (
+	// MaxEstimatedCostActionCancel indicates rejecting the statement whose estimated cost exceeds the limit.
+	MaxEstimatedCostActionCancel = "CANCEL"
+	// MaxEstimatedCostActionWarn indicates only logging the statement whose estimated cost exceeds the limit.
+	MaxEstimatedCostActionWarn = "WARN"
+)
+
+const (
+	// MaxResultActionCancel indicates aborting the statement whose result set exceeds the limit with an error.
+	MaxResultActionCancel = "CANCEL"
+	// MaxResultActionTruncate indicates truncating the result set at the limit and appending a warning.
+	MaxResultActionTruncate = "TRUNCATE"
+)
+
 // PartitionPruneMode presents the prune mode used.
 type PartitionPruneMode string
 
@@ -1017,6 +1100,7 @@ func NewSessionVars() *SessionVars {
 		DDLReorgPriority:            kv.PriorityLow,
 		allowInSubqToJoinAndAgg:     DefOptInSubqToJoinAndAgg,
 		preferRangeScan:             DefOptPreferRangeScan,
+		decorrelateScalarSubquery:   DefOptDecorrelateScalarSubquery,
 		CorrelationThreshold:        DefOptCorrelationThreshold,
 		CorrelationExpFactor:        DefOptCorrelationExpFactor,
 		CPUFactor:                   DefOptCPUFactor,
@@ -1068,26 +1152,31 @@ func NewSessionVars() *SessionVars {
 		Enable1PC:                   DefTiDBEnable1PC,
 		GuaranteeLinearizability:    DefTiDBGuaranteeLinearizability,
 		AnalyzeVersion:              DefTiDBAnalyzeVersion,
+		StatsLoadSyncWait:           DefTiDBStatsLoadSyncWait,
 		EnableIndexMergeJoin:        DefTiDBEnableIndexMergeJoin,
 		AllowFallbackToTiKV:         make(map[kv.StoreType]struct{}),
 		CTEMaxRecursionDepth:        DefCTEMaxRecursionDepth,
 		TMPTableSize:                DefTMPTableSize,
 		EnableGlobalTemporaryTable:  DefTiDBEnableGlobalTemporaryTable,
+		MaxEstimatedCostAction:      DefTiDBMaxEstimatedCostAction,
+		MaxResultAction:             DefTiDBMaxResultAction,
+		FullTextTokenizer:           DefTiDBFullTextTokenizer,
 	}
 	vars.KVVars = tikvstore.NewVariables(&vars.Killed)
 	vars.Concurrency = Concurrency{
-		indexLookupConcurrency:     DefIndexLookupConcurrency,
-		indexSerialScanConcurrency: DefIndexSerialScanConcurrency,
-		indexLookupJoinConcurrency: DefIndexLookupJoinConcurrency,
-		hashJoinConcurrency:        DefTiDBHashJoinConcurrency,
-		projectionConcurrency:      DefTiDBProjectionConcurrency,
-		distSQLScanConcurrency:     DefDistSQLScanConcurrency,
-		hashAggPartialConcurrency:  DefTiDBHashAggPartialConcurrency,
-		hashAggFinalConcurrency:    DefTiDBHashAggFinalConcurrency,
-		windowConcurrency:          DefTiDBWindowConcurrency,
-		mergeJoinConcurrency:       DefTiDBMergeJoinConcurrency,
-		streamAggConcurrency:       DefTiDBStreamAggConcurrency,
-		ExecutorConcurrency:        DefExecutorConcurrency,
+		indexLookupConcurrency:       DefIndexLookupConcurrency,
+		indexSerialScanConcurrency:   DefIndexSerialScanConcurrency,
+		indexLookupJoinConcurrency:   DefIndexLookupJoinConcurrency,
+		hashJoinConcurrency:          DefTiDBHashJoinConcurrency,
+		projectionConcurrency:        DefTiDBProjectionConcurrency,
+		distSQLScanConcurrency:       DefDistSQLScanConcurrency,
+		hashAggPartialConcurrency:    DefTiDBHashAggPartialConcurrency,
+		hashAggFinalConcurrency:      DefTiDBHashAggFinalConcurrency,
+		windowConcurrency:            DefTiDBWindowConcurrency,
+		mergeJoinConcurrency:         DefTiDBMergeJoinConcurrency,
+		streamAggConcurrency:         DefTiDBStreamAggConcurrency,
+		ExecutorConcurrency:          DefExecutorConcurrency,
+		ParallelMergeJoinConcurrency: DefParallelMergeJoinConcurrency,
 	}
 	vars.MemQuota = MemQuota{
 		MemQuotaQuery:      config.GetGlobalConfig().MemQuotaQuery,
@@ -1110,6 +1199,7 @@ func NewSessionVars() *SessionVars {
 		MaxChunkSize:       DefMaxChunkSize,
 	}
 	vars.DMLBatchSize = DefDMLBatchSize
+	vars.OnDupBatchSize = DefOnDupBatchSize
 	var enableStreaming string
 	if config.GetGlobalConfig().EnableStreaming {
 		enableStreaming = "1"
@@ -1158,6 +1248,35 @@ func (s *SessionVars) SetAllowInSubqToJoinAndAgg(val bool) {
 	s.allowInSubqToJoinAndAgg = val
 }
 
+// GetAllowAggPushDown gets AllowAggPushDown, it can be set for a statement by the SET_VAR hint.
+func (s *SessionVars) GetAllowAggPushDown() bool {
+	if val, ok := s.stmtVars[TiDBOptAggPushDown]; ok {
+		return TiDBOptOn(val)
+	}
+	return s.AllowAggPushDown
+}
+
+// GetForceAggPushDown gets ForceAggPushDown, it can be set for a statement by the SET_VAR hint.
+func (s *SessionVars) GetForceAggPushDown() bool {
+	if val, ok := s.stmtVars[TiDBOptForceAggPushDown]; ok {
+		return TiDBOptOn(val)
+	}
+	return s.ForceAggPushDown
+}
+
+// GetDecorrelateScalarSubquery gets decorrelateScalarSubquery, it can be set for a statement by the SET_VAR hint.
+func (s *SessionVars) GetDecorrelateScalarSubquery() bool {
+	if val, ok := s.stmtVars[TiDBOptDecorrelateScalarSubquery]; ok {
+		return TiDBOptOn(val)
+	}
+	return s.decorrelateScalarSubquery
+}
+
+// SetDecorrelateScalarSubquery sets SessionVars.decorrelateScalarSubquery.
+func (s *SessionVars) SetDecorrelateScalarSubquery(val bool) {
+	s.decorrelateScalarSubquery = val
+}
+
 // GetAllowPreferRangeScan get preferRangeScan from SessionVars.preferRangeScan.
 func (s *SessionVars) GetAllowPreferRangeScan() bool {
 	return s.preferRangeScan
@@ -1196,6 +1315,9 @@ func (s *SessionVars) GetReplicaRead() kv.ReplicaReadType {
 	if s.StmtCtx.HasReplicaReadHint {
 		return kv.ReplicaReadType(s.StmtCtx.ReplicaRead)
 	}
+	if s.TxnCtx.IsReadOnly {
+		return kv.ReplicaReadFollower
+	}
 	return s.replicaRead
 }
 
@@ -1204,6 +1326,12 @@ func (s *SessionVars) SetReplicaRead(val kv.ReplicaReadType) {
 	s.replicaRead = val
 }
 
+// IsReplicaReadInReadOnlyTxn returns whether tidb_replica_read is follower-in-read-only-txn, with which
+// the reads of the transactions started by START TRANSACTION READ ONLY are routed to the followers.
+func (s *SessionVars) IsReplicaReadInReadOnlyTxn() bool {
+	return s.replicaReadInReadOnlyTxn
+}
+
 // GetWriteStmtBufs get pointer of SessionVars.writeStmtBufs.
 func (s *SessionVars) GetWriteStmtBufs() *WriteStmtBufs {
 	return &s.writeStmtBufs
@@ -1343,6 +1471,22 @@ func (s *SessionVars) Location() *time.Location {
 	return loc
 }
 
+// RefreshTimeZone reloads the time zone of the session if the time zone database has been reloaded since it was set,
+// so that the updated time zone rules are applied to the session. It should be called before a statement starts.
+func (s *SessionVars) RefreshTimeZone() {
+	version := timeutil.LocationVersion()
+	if s.TimeZone == nil || s.timeZoneVersion == version {
+		return
+	}
+	s.timeZoneVersion = version
+	// The fixed offset time zones don't have a name.
+	if name := s.TimeZone.String(); name != "" && name != "Local" {
+		if loc, err := timeutil.LoadLocation(name); err == nil {
+			s.TimeZone = loc
+		}
+	}
+}
+
 // GetSystemVar gets the string value of a system variable.
 func (s *SessionVars) GetSystemVar(name string) (string, bool) {
 	if name == WarningCount {
@@ -1576,6 +1720,10 @@ type Concurrency struct {
 	// ExecutorConcurrency is the number of concurrent worker for all executors.
 	ExecutorConcurrency int
 
+	// ParallelMergeJoinConcurrency is the number of workers joining the ranges of the ordered inputs of a merge join
+	// in parallel, the merge join is executed serially if it's 1.
+	ParallelMergeJoinConcurrency int
+
 	// SourceAddr is the source address of request. Available in coprocessor ONLY.
 	SourceAddr net.TCPAddr
 }
@@ -1784,6 +1932,8 @@ const (
 	SlowLogHostStr = "Host"
 	// SlowLogConnIDStr is slow log field name.
 	SlowLogConnIDStr = "Conn_ID"
+	// SlowLogStmtInstanceIDStr is slow log field name.
+	SlowLogStmtInstanceIDStr = "Stmt_instance_id"
 	// SlowLogQueryTimeStr is slow log field name.
 	SlowLogQueryTimeStr = "Query_time"
 	// SlowLogParseTimeStr is the parse sql time.
@@ -1796,6 +1946,8 @@ const (
 	SlowLogOptimizeTimeStr = "Optimize_time"
 	// SlowLogWaitTSTimeStr is the time of waiting TS.
 	SlowLogWaitTSTimeStr = "Wait_TS"
+	// SlowLogAdmissionQueueTimeStr is the time the statement is queued by the admission control.
+	SlowLogAdmissionQueueTimeStr = "Admission_queue_time"
 	// SlowLogPreprocSubQueriesStr is the number of pre-processed sub-queries.
 	SlowLogPreprocSubQueriesStr = "Preproc_subqueries"
 	// SlowLogPreProcSubQueryTimeStr is the total time of pre-processing sub-queries.
@@ -1872,12 +2024,15 @@ const (
 	SlowLogExecRetryTime = "Exec_retry_time"
 	// SlowLogBackoffDetail is the detail of backoff.
 	SlowLogBackoffDetail = "Backoff_Detail"
+	// SlowLogRPCStatsStr is the number and the total time of the RPC requests, grouped by the request type.
+	SlowLogRPCStatsStr = "RPC_stats"
 )
 
 // SlowQueryLogItems is a collection of items that should be included in the
 // slow query log.
 type SlowQueryLogItems struct {
 	TxnTS             uint64
+	StmtInstanceID    string
 	SQL               string
 	Digest            string
 	TimeTotal         time.Duration
@@ -1885,6 +2040,7 @@ type SlowQueryLogItems struct {
 	TimeCompile       time.Duration
 	TimeOptimize      time.Duration
 	TimeWaitTS        time.Duration
+	TimeAdmission     time.Duration
 	IndexNames        string
 	StatsInfos        map[string]uint64
 	CopTasks          *stmtctx.CopTasksDetails
@@ -1906,6 +2062,7 @@ type SlowQueryLogItems struct {
 	WriteSQLRespTotal time.Duration
 	ExecRetryCount    uint
 	ExecRetryTime     time.Duration
+	RPCStats          string
 }
 
 // SlowLogFormat uses for formatting slow log.
@@ -1914,8 +2071,10 @@ type SlowQueryLogItems struct {
 // # Txn_start_ts: 406315658548871171
 // # User@Host: root[root] @ localhost [127.0.0.1]
 // # Conn_ID: 6
+// # Stmt_instance_id: 8a6dbc5e-6c4d-4f5b-9d3a-2c1e0f7b4a91:42
 // # Query_time: 4.895492
 // # Process_time: 0.161 Request_count: 1 Total_keys: 100001 Processed_keys: 100000
+// # RPC_stats: Cop:{num_rpc:1,total_time:2.1ms}
 // # DB: test
 // # Index_names: [t1.idx1,t2.idx2]
 // # Is_internal: false
@@ -1943,6 +2102,9 @@ func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 	if s.ConnectionID != 0 {
 		writeSlowLogItem(&buf, SlowLogConnIDStr, strconv.FormatUint(s.ConnectionID, 10))
 	}
+	if logItems.StmtInstanceID != "" {
+		writeSlowLogItem(&buf, SlowLogStmtInstanceIDStr, logItems.StmtInstanceID)
+	}
 	if logItems.ExecRetryCount > 0 {
 		buf.WriteString(SlowLogRowPrefixStr)
 		buf.WriteString(SlowLogExecRetryTime)
@@ -1968,10 +2130,16 @@ func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 
 	writeSlowLogItem(&buf, SlowLogOptimizeTimeStr, strconv.FormatFloat(logItems.TimeOptimize.Seconds(), 'f', -1, 64))
 	writeSlowLogItem(&buf, SlowLogWaitTSTimeStr, strconv.FormatFloat(logItems.TimeWaitTS.Seconds(), 'f', -1, 64))
+	if logItems.TimeAdmission > 0 {
+		writeSlowLogItem(&buf, SlowLogAdmissionQueueTimeStr, strconv.FormatFloat(logItems.TimeAdmission.Seconds(), 'f', -1, 64))
+	}
 
 	if execDetailStr := logItems.ExecDetail.String(); len(execDetailStr) > 0 {
 		buf.WriteString(SlowLogRowPrefixStr + execDetailStr + "\n")
 	}
+	if len(logItems.RPCStats) > 0 {
+		writeSlowLogItem(&buf, SlowLogRPCStatsStr, logItems.RPCStats)
+	}
 
 	if len(s.CurrentDB) > 0 {
 		writeSlowLogItem(&buf, SlowLogDBStr, s.CurrentDB)
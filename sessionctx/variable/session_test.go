@@ -25,6 +25,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/mock"
+	"github.com/pingcap/tidb/util/timeutil"
 	"github.com/tikv/client-go/v2/util"
 )
 
@@ -122,6 +123,26 @@ func (*testSessionSuite) TestSession(c *C) {
 	c.Assert(ss.WarningCount(), Equals, uint16(0))
 }
 
+func (*testSessionSuite) TestRefreshTimeZone(c *C) {
+	vars := variable.NewSessionVars()
+	c.Assert(variable.SetSessionSystemVar(vars, variable.TimeZone, "Asia/Shanghai"), IsNil)
+	loc := vars.TimeZone
+	vars.RefreshTimeZone()
+	c.Assert(vars.TimeZone, Equals, loc)
+
+	// The time zone is reloaded after the time zone database is reloaded.
+	timeutil.ReloadLocations()
+	vars.RefreshTimeZone()
+	c.Assert(vars.TimeZone, Not(Equals), loc)
+	c.Assert(vars.TimeZone.String(), Equals, "Asia/Shanghai")
+
+	c.Assert(variable.SetSessionSystemVar(vars, variable.TimeZone, "+08:00"), IsNil)
+	loc = vars.TimeZone
+	timeutil.ReloadLocations()
+	vars.RefreshTimeZone()
+	c.Assert(vars.TimeZone, Equals, loc)
+}
+
 func (*testSessionSuite) TestAllocMPPID(c *C) {
 	ctx := mock.NewContext()
 
@@ -196,6 +217,7 @@ func (*testSessionSuite) TestSlowLogFormat(c *C) {
 	resultFields := `# Txn_start_ts: 406649736972468225
 # User@Host: root[root] @ 192.168.0.1 [192.168.0.1]
 # Conn_ID: 1
+# Stmt_instance_id: 8a6dbc5e-6c4d-4f5b-9d3a-2c1e0f7b4a91:42
 # Exec_retry_time: 5.1 Exec_retry_count: 3
 # Query_time: 1
 # Parse_time: 0.00000001
@@ -203,7 +225,9 @@ func (*testSessionSuite) TestSlowLogFormat(c *C) {
 # Rewrite_time: 0.000000003 Preproc_subqueries: 2 Preproc_subqueries_time: 0.000000002
 # Optimize_time: 0.00000001
 # Wait_TS: 0.000000003
+# Admission_queue_time: 0.5
 # Process_time: 2 Wait_time: 60 Backoff_time: 0.001 Request_count: 2 Process_keys: 20001 Total_keys: 10000
+# RPC_stats: Cop:{num_rpc:2,total_time:1.5s},Get:{num_rpc:1,total_time:2ms}
 # DB: test
 # Index_names: [t1:a,t2:b]
 # Is_internal: true
@@ -230,6 +254,7 @@ func (*testSessionSuite) TestSlowLogFormat(c *C) {
 	_, digest := parser.NormalizeDigest(sql)
 	logItems := &variable.SlowQueryLogItems{
 		TxnTS:             txnTS,
+		StmtInstanceID:    "8a6dbc5e-6c4d-4f5b-9d3a-2c1e0f7b4a91:42",
 		SQL:               sql,
 		Digest:            digest.String(),
 		TimeTotal:         costTime,
@@ -237,6 +262,7 @@ func (*testSessionSuite) TestSlowLogFormat(c *C) {
 		TimeCompile:       time.Duration(10),
 		TimeOptimize:      time.Duration(10),
 		TimeWaitTS:        time.Duration(3),
+		TimeAdmission:     500 * time.Millisecond,
 		IndexNames:        "[t1:a,t2:b]",
 		StatsInfos:        statsInfos,
 		CopTasks:          copTasks,
@@ -259,6 +285,7 @@ func (*testSessionSuite) TestSlowLogFormat(c *C) {
 		},
 		ExecRetryCount: 3,
 		ExecRetryTime:  5*time.Second + time.Millisecond*100,
+		RPCStats:       "Cop:{num_rpc:2,total_time:1.5s},Get:{num_rpc:1,total_time:2ms}",
 	}
 	logString := seVar.SlowLogFormat(logItems)
 	c.Assert(logString, Equals, resultFields+"\n"+sql)
@@ -31,8 +31,10 @@ import (
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tidb/util/fulltext"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/stmtsummary"
+	"github.com/pingcap/tidb/util/timeutil"
 	"github.com/pingcap/tidb/util/versioninfo"
 	tikvstore "github.com/tikv/client-go/v2/kv"
 	atomic2 "go.uber.org/atomic"
@@ -663,6 +665,7 @@ var defaultSysVars = []*SysVar{
 			return err
 		}
 		s.TimeZone = tz
+		s.timeZoneVersion = timeutil.LocationVersion()
 		return nil
 	}},
 	{Scope: ScopeNone, Name: SystemTimeZone, Value: "CST"},
@@ -874,10 +877,14 @@ var defaultSysVars = []*SysVar{
 		}
 		return nil
 	}},
-	{Scope: ScopeSession, Name: TiDBOptAggPushDown, Value: BoolToOnOff(DefOptAggPushDown), Type: TypeBool, skipInit: true, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeSession, Name: TiDBOptAggPushDown, Value: BoolToOnOff(DefOptAggPushDown), Type: TypeBool, IsHintUpdatable: true, skipInit: true, SetSession: func(s *SessionVars, val string) error {
 		s.AllowAggPushDown = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeSession, Name: TiDBOptForceAggPushDown, Value: BoolToOnOff(DefOptForceAggPushDown), Type: TypeBool, IsHintUpdatable: true, skipInit: true, SetSession: func(s *SessionVars, val string) error {
+		s.ForceAggPushDown = TiDBOptOn(val)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptBCJ, Value: BoolToOnOff(DefOptBCJ), Type: TypeBool, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
 		if TiDBOptOn(normalizedValue) && vars.AllowBatchCop == 0 {
 			return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs("Can't set Broadcast Join to 1 but tidb_allow_batch_cop is 0, please active batch cop at first.")
@@ -907,6 +914,7 @@ var defaultSysVars = []*SysVar{
 	{Scope: ScopeGlobal, Name: TiDBAutoAnalyzeRatio, Value: strconv.FormatFloat(DefAutoAnalyzeRatio, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64},
 	{Scope: ScopeGlobal, Name: TiDBAutoAnalyzeStartTime, Value: DefAutoAnalyzeStartTime, Type: TypeTime},
 	{Scope: ScopeGlobal, Name: TiDBAutoAnalyzeEndTime, Value: DefAutoAnalyzeEndTime, Type: TypeTime},
+	{Scope: ScopeGlobal, Name: TiDBAutoAnalyzeConcurrency, Value: strconv.Itoa(DefAutoAnalyzeConcurrency), Type: TypeUnsigned, MinValue: 1, MaxValue: 64},
 	{Scope: ScopeSession, Name: TiDBChecksumTableConcurrency, skipInit: true, Value: strconv.Itoa(DefChecksumTableConcurrency)},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBExecutorConcurrency, Value: strconv.Itoa(DefExecutorConcurrency), Type: TypeUnsigned, MinValue: 1, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
 		s.ExecutorConcurrency = tidbOptPositiveInt32(val, DefExecutorConcurrency)
@@ -924,10 +932,30 @@ var defaultSysVars = []*SysVar{
 		s.SetAllowPreferRangeScan(TiDBOptOn(val))
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptDecorrelateNonEqAgg, Value: BoolToOnOff(DefOptDecorrelateNonEqAgg), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.DecorrelateNonEqAgg = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptDecorrelateScalarSubquery, Value: BoolToOnOff(DefOptDecorrelateScalarSubquery), Type: TypeBool, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
+		s.SetDecorrelateScalarSubquery(TiDBOptOn(val))
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableNullAwareAntiJoin, Value: BoolToOnOff(DefTiDBEnableNullAwareAntiJoin), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableNullAwareAntiJoin = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptEnableHistogramJoinEstimation, Value: BoolToOnOff(DefOptHistogramJoinEstimation), Type: TypeBool, IsHintUpdatable: true, SetSession: func(s *SessionVars, val string) error {
+		s.EnableHistogramJoinEstimation = TiDBOptOn(val)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptCorrelationThreshold, Value: strconv.FormatFloat(DefOptCorrelationThreshold, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: 1, SetSession: func(s *SessionVars, val string) error {
 		s.CorrelationThreshold = tidbOptFloat64(val, DefOptCorrelationThreshold)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptEstimationDeviationRatio, Value: strconv.FormatFloat(DefOptEstimationDeviationRatio, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, SetSession: func(s *SessionVars, val string) error {
+		s.EstimationDeviationRatio = tidbOptFloat64(val, DefOptEstimationDeviationRatio)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOptCorrelationExpFactor, Value: strconv.Itoa(DefOptCorrelationExpFactor), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
 		s.CorrelationExpFactor = int(tidbOptInt64(val, DefOptCorrelationExpFactor))
 		return nil
@@ -1022,6 +1050,10 @@ var defaultSysVars = []*SysVar{
 		s.DMLBatchSize = int(tidbOptInt64(val, DefDMLBatchSize))
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBOnDupBatchSize, Value: strconv.Itoa(DefOnDupBatchSize), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
+		s.OnDupBatchSize = int(tidbOptInt64(val, DefOnDupBatchSize))
+		return nil
+	}},
 	{Scope: ScopeSession, Name: TiDBCurrentTS, Value: strconv.Itoa(DefCurretTS), ReadOnly: true, skipInit: true, GetSession: func(s *SessionVars) (string, error) {
 		return fmt.Sprintf("%d", s.TxnCtx.StartTS), nil
 	}},
@@ -1171,6 +1203,10 @@ var defaultSysVars = []*SysVar{
 		appendDeprecationWarning(vars, TiDBMergeJoinConcurrency, TiDBExecutorConcurrency)
 		return normalizedValue, nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBParallelMergeJoinConcurrency, Value: strconv.Itoa(DefParallelMergeJoinConcurrency), Type: TypeInt, MinValue: 1, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
+		s.ParallelMergeJoinConcurrency = tidbOptPositiveInt32(val, DefParallelMergeJoinConcurrency)
+		return nil
+	}},
 
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBStreamAggConcurrency, Value: strconv.Itoa(DefTiDBStreamAggConcurrency), Type: TypeInt, MinValue: 1, MaxValue: math.MaxInt32, AllowAutoValue: true, SetSession: func(s *SessionVars, val string) error {
 		s.streamAggConcurrency = tidbOptPositiveInt32(val, ConcurrencyUnset)
@@ -1290,6 +1326,18 @@ var defaultSysVars = []*SysVar{
 	}, GetSession: func(s *SessionVars) (string, error) {
 		return BoolToOnOff(ProcessGeneralLog.Load()), nil
 	}},
+	{Scope: ScopeSession, Name: TiDBServiceScope, Value: DefTiDBServiceScope, Type: TypeStr, skipInit: true, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+		serviceScope := strings.ToLower(normalizedValue)
+		if serviceScope != DefTiDBServiceScope && serviceScope != ServiceScopeBackground {
+			return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(TiDBServiceScope, originalValue)
+		}
+		return serviceScope, nil
+	}, SetSession: func(s *SessionVars, val string) error {
+		ServiceScope.Store(val)
+		return nil
+	}, GetSession: func(s *SessionVars) (string, error) {
+		return ServiceScope.Load(), nil
+	}},
 	{Scope: ScopeSession, Name: TiDBPProfSQLCPU, Value: strconv.Itoa(DefTiDBPProfSQLCPU), Type: TypeInt, skipInit: true, MinValue: 0, MaxValue: 1, SetSession: func(s *SessionVars, val string) error {
 		EnablePProfSQLCPU.Store(uint32(tidbOptPositiveInt32(val, DefTiDBPProfSQLCPU)) > 0)
 		return nil
@@ -1412,12 +1460,13 @@ var defaultSysVars = []*SysVar{
 		s.EnableNoopFuncs = TiDBOptOn(val)
 		return nil
 	}},
-	{Scope: ScopeSession, Name: TiDBReplicaRead, Value: "leader", Type: TypeEnum, PossibleValues: []string{"leader", "follower", "leader-and-follower"}, skipInit: true, SetSession: func(s *SessionVars, val string) error {
+	{Scope: ScopeSession, Name: TiDBReplicaRead, Value: "leader", Type: TypeEnum, PossibleValues: []string{"leader", "follower", "leader-and-follower", "follower-in-read-only-txn"}, skipInit: true, SetSession: func(s *SessionVars, val string) error {
+		s.replicaReadInReadOnlyTxn = strings.EqualFold(val, "follower-in-read-only-txn")
 		if strings.EqualFold(val, "follower") {
 			s.SetReplicaRead(kv.ReplicaReadFollower)
 		} else if strings.EqualFold(val, "leader-and-follower") {
 			s.SetReplicaRead(kv.ReplicaReadMixed)
-		} else if strings.EqualFold(val, "leader") || len(val) == 0 {
+		} else if strings.EqualFold(val, "leader") || len(val) == 0 || s.replicaReadInReadOnlyTxn {
 			s.SetReplicaRead(kv.ReplicaReadLeader)
 		}
 		return nil
@@ -1456,6 +1505,18 @@ var defaultSysVars = []*SysVar{
 	}, SetGlobal: func(s *SessionVars, val string) error {
 		return stmtsummary.StmtSummaryByDigestMap.SetMaxSQLLength(val, false)
 	}},
+	{Scope: ScopeGlobal, Name: TiDBStmtSummarySLOLatency, Value: strconv.Itoa(DefTiDBStmtSummarySLOLatency), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt32, GetSession: func(s *SessionVars) (string, error) {
+		return strconv.FormatInt(stmtsummary.StmtSummaryByDigestMap.SLOLatency().Milliseconds(), 10), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		stmtsummary.StmtSummaryByDigestMap.SetSLOLatency(time.Duration(tidbOptInt64(val, DefTiDBStmtSummarySLOLatency)) * time.Millisecond)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBStmtSummarySLOObjective, Value: strconv.FormatFloat(DefTiDBStmtSummarySLOObjective, 'f', -1, 64), Type: TypeFloat, MinValue: 0, MaxValue: 1, GetSession: func(s *SessionVars) (string, error) {
+		return strconv.FormatFloat(stmtsummary.StmtSummaryByDigestMap.SLOObjective(), 'f', -1, 64), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		stmtsummary.StmtSummaryByDigestMap.SetSLOObjective(tidbOptFloat64(val, DefTiDBStmtSummarySLOObjective))
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBCapturePlanBaseline, Value: Off, Type: TypeBool, AllowEmptyAll: true, skipInit: true, GetSession: func(s *SessionVars) (string, error) {
 		return CapturePlanBaseline.GetVal(), nil
 	}, SetSession: func(s *SessionVars, val string) error {
@@ -1514,6 +1575,17 @@ var defaultSysVars = []*SysVar{
 		}
 		return nil
 	}},
+	{Scope: ScopeSession, Name: TiDBSQLHints, Value: "", Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+		_, err := parseSQLHints(normalizedValue, vars.SQLMode)
+		return normalizedValue, err
+	}, SetSession: func(s *SessionVars, val string) error {
+		sqlHints, err := parseSQLHints(val, s.SQLMode)
+		if err != nil {
+			return err
+		}
+		s.SQLHints = sqlHints
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBStoreLimit, Value: strconv.FormatInt(atomic.LoadInt64(&config.GetGlobalConfig().TiKVClient.StoreLimit), 10), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt64, AutoConvertOutOfRange: true, SetSession: func(s *SessionVars, val string) error {
 		tikvstore.StoreLimit.Store(tidbOptInt64(val, DefTiDBStoreLimit))
 		return nil
@@ -1597,6 +1669,12 @@ var defaultSysVars = []*SysVar{
 		s.EnableClusteredIndex = TiDBOptEnableClustered(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal, Name: TiDBCheckConstraintEnforcement, Value: Off, Type: TypeEnum, PossibleValues: []string{Off, Warn, On}, GetSession: func(s *SessionVars) (string, error) {
+		return GetCheckConstraintLevel().String(), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		CheckConstraintEnforcement.Store(int32(TiDBOptCheckConstraintLevel(val)))
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBPartitionPruneMode, Value: DefTiDBPartitionPruneMode, Hidden: true, Type: TypeStr, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
 		mode := PartitionPruneMode(normalizedValue).Update()
 		if !mode.Valid() {
@@ -1623,6 +1701,34 @@ var defaultSysVars = []*SysVar{
 		return nil
 	}},
 	{Scope: ScopeGlobal, Name: TiDBEnableTelemetry, Value: BoolToOnOff(DefTiDBEnableTelemetry), Type: TypeBool},
+	{Scope: ScopeGlobal, Name: TiDBEnableHealthReport, Value: BoolToOnOff(DefTiDBEnableHealthReport), Type: TypeBool},
+	{Scope: ScopeGlobal, Name: TiDBHealthReportStorage, Value: "", Type: TypeStr, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+		return normalizedValue, checkStorageURLCredentials(TiDBHealthReportStorage, normalizedValue)
+	}},
+	{Scope: ScopeGlobal, Name: TiDBAdmissionCPUThreshold, Value: strconv.Itoa(DefTiDBAdmissionCPUThreshold), Type: TypeFloat, MinValue: 0, MaxValue: 1, GetSession: func(s *SessionVars) (string, error) {
+		return strconv.FormatFloat(AdmissionCPUThreshold.Load(), 'f', -1, 64), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		AdmissionCPUThreshold.Store(tidbOptFloat64(val, DefTiDBAdmissionCPUThreshold))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBAdmissionMaxQueueTime, Value: strconv.Itoa(DefTiDBAdmissionMaxQueueTime), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt32, GetSession: func(s *SessionVars) (string, error) {
+		return strconv.FormatInt(AdmissionMaxQueueTime.Load(), 10), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		AdmissionMaxQueueTime.Store(tidbOptInt64(val, DefTiDBAdmissionMaxQueueTime))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBEnableGOGCTuner, Value: BoolToOnOff(DefTiDBEnableGOGCTuner), Type: TypeBool, GetSession: func(s *SessionVars) (string, error) {
+		return BoolToOnOff(EnableGOGCTuner.Load()), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		EnableGOGCTuner.Store(TiDBOptOn(val))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBMemQuotaBindingCache, Value: strconv.Itoa(DefTiDBMemQuotaBindingCache), Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt64, GetSession: func(s *SessionVars) (string, error) {
+		return strconv.FormatInt(MemQuotaBindingCache.Load(), 10), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		MemQuotaBindingCache.Store(tidbOptInt64(val, DefTiDBMemQuotaBindingCache))
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableAmendPessimisticTxn, Value: BoolToOnOff(DefTiDBEnableAmendPessimisticTxn), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.EnableAmendPessimisticTxn = TiDBOptOn(val)
 		return nil
@@ -1659,6 +1765,14 @@ var defaultSysVars = []*SysVar{
 		s.AnalyzeVersion = tidbOptPositiveInt32(val, DefTiDBAnalyzeVersion)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBAnalyzeAdaptiveBuckets, Value: BoolToOnOff(DefTiDBAnalyzeAdaptiveBuckets), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.AnalyzeAdaptiveBuckets = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBStatsLoadSyncWait, Value: strconv.Itoa(DefTiDBStatsLoadSyncWait), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
+		s.StatsLoadSyncWait = tidbOptInt64(val, DefTiDBStatsLoadSyncWait)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableIndexMergeJoin, Value: BoolToOnOff(DefTiDBEnableIndexMergeJoin), Hidden: true, Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.EnableIndexMergeJoin = TiDBOptOn(val)
 		return nil
@@ -1675,6 +1789,10 @@ var defaultSysVars = []*SysVar{
 		s.TiDBEnableExchangePartition = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableReorganizePartition, Value: BoolToOnOff(DefTiDBEnableReorganizePartition), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.TiDBEnableReorganizePartition = TiDBOptOn(val)
+		return nil
+	}},
 	{Scope: ScopeNone, Name: TiDBEnableEnhancedSecurity, Value: Off, Type: TypeBool},
 	{Scope: ScopeSession, Name: PluginLoad, Value: "", GetSession: func(s *SessionVars) (string, error) {
 		return config.GetGlobalConfig().Plugin.Dir, nil
@@ -1750,6 +1868,22 @@ var defaultSysVars = []*SysVar{
 		TopSQLVariable.ReportIntervalSeconds.Store(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal, Name: TiDBTopSQLMaxOverheadPercentage, Value: strconv.FormatFloat(DefTiDBTopSQLMaxOverheadPercentage, 'f', -1, 64), Type: TypeFloat, Hidden: true, MinValue: 0, MaxValue: 100, AllowEmpty: true, GetSession: func(s *SessionVars) (string, error) {
+		return strconv.FormatFloat(TopSQLVariable.MaxOverheadPercentage.Load(), 'f', -1, 64), nil
+	}, SetGlobal: func(vars *SessionVars, s string) error {
+		val, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		TopSQLVariable.MaxOverheadPercentage.Store(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBTopSQLAdaptiveInterval, Value: BoolToOnOff(DefTiDBTopSQLAdaptiveInterval), Type: TypeBool, Hidden: true, AllowEmpty: true, GetSession: func(s *SessionVars) (string, error) {
+		return BoolToOnOff(TopSQLVariable.AdaptiveInterval.Load()), nil
+	}, SetGlobal: func(vars *SessionVars, s string) error {
+		TopSQLVariable.AdaptiveInterval.Store(TiDBOptOn(s))
+		return nil
+	}},
 
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableGlobalTemporaryTable, Value: BoolToOnOff(DefTiDBEnableGlobalTemporaryTable), Hidden: true, Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.EnableGlobalTemporaryTable = TiDBOptOn(val)
@@ -1761,6 +1895,34 @@ var defaultSysVars = []*SysVar{
 		s.EnableStableResultMode = TiDBOptOn(val)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMaxEstimatedCost, Value: strconv.Itoa(DefTiDBMaxEstimatedCost), Type: TypeFloat, MinValue: 0, MaxValue: math.MaxUint64, SetSession: func(s *SessionVars, val string) error {
+		s.MaxEstimatedCost = tidbOptFloat64(val, DefTiDBMaxEstimatedCost)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMaxEstimatedRows, Value: strconv.Itoa(DefTiDBMaxEstimatedRows), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
+		s.MaxEstimatedRows = tidbOptInt64(val, DefTiDBMaxEstimatedRows)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMaxEstimatedCostAction, Value: DefTiDBMaxEstimatedCostAction, Type: TypeEnum, PossibleValues: []string{MaxEstimatedCostActionCancel, MaxEstimatedCostActionWarn}, SetSession: func(s *SessionVars, val string) error {
+		s.MaxEstimatedCostAction = val
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMaxResultRows, Value: strconv.Itoa(DefTiDBMaxResultRows), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
+		s.MaxResultRows = tidbOptInt64(val, DefTiDBMaxResultRows)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMaxResultBytes, Value: strconv.Itoa(DefTiDBMaxResultBytes), Type: TypeInt, MinValue: 0, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
+		s.MaxResultBytes = tidbOptInt64(val, DefTiDBMaxResultBytes)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMaxResultAction, Value: DefTiDBMaxResultAction, Type: TypeEnum, PossibleValues: []string{MaxResultActionCancel, MaxResultActionTruncate}, SetSession: func(s *SessionVars, val string) error {
+		s.MaxResultAction = val
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBFullTextTokenizer, Value: DefTiDBFullTextTokenizer, Type: TypeEnum, PossibleValues: []string{fulltext.TokenizerStandard, fulltext.TokenizerNgram}, SetSession: func(s *SessionVars, val string) error {
+		s.FullTextTokenizer = val
+		return nil
+	}},
 }
 
 // FeedbackProbability points to the FeedbackProbability in statistics package.
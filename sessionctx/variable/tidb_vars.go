@@ -18,6 +18,7 @@ import (
 
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/util/fulltext"
 	"github.com/uber-go/atomic"
 )
 
@@ -46,6 +47,10 @@ const (
 	// tidb_opt_agg_push_down is used to enable/disable the optimizer rule of aggregation push down.
 	TiDBOptAggPushDown = "tidb_opt_agg_push_down"
 
+	// TiDBOptForceAggPushDown is used to push the aggregation down across the joins even if it hardly reduces the rows.
+	// It only takes effect when tidb_opt_agg_push_down is on.
+	TiDBOptForceAggPushDown = "tidb_opt_force_agg_push_down"
+
 	// TiDBOptBCJ is used to enable/disable broadcast join in MPP mode
 	TiDBOptBCJ = "tidb_opt_broadcast_join"
 
@@ -75,6 +80,9 @@ const (
 	TiDBAutoAnalyzeStartTime = "tidb_auto_analyze_start_time"
 	TiDBAutoAnalyzeEndTime   = "tidb_auto_analyze_end_time"
 
+	// tidb_auto_analyze_concurrency is the max number of the tables analyzed concurrently by auto analyze in a round.
+	TiDBAutoAnalyzeConcurrency = "tidb_auto_analyze_concurrency"
+
 	// tidb_checksum_table_concurrency is used to speed up the ADMIN CHECKSUM TABLE
 	// statement, when a table has multiple indices, those indices can be
 	// scanned concurrently, with the cost of higher system performance impact.
@@ -111,6 +119,10 @@ const (
 	// User could change it to a smaller one to avoid breaking the transaction size limitation.
 	TiDBDMLBatchSize = "tidb_dml_batch_size"
 
+	// tidb_on_dup_batch_size is used to split the rows of INSERT ... ON DUPLICATE KEY UPDATE into batches,
+	// the duplicate keys of the next batch are prefetched while the current batch is being updated.
+	TiDBOnDupBatchSize = "tidb_on_dup_batch_size"
+
 	// The following session variables controls the memory quota during query execution.
 	// "tidb_mem_quota_query":				control the memory quota of a query.
 	TiDBMemQuotaQuery      = "tidb_mem_quota_query" // Bytes.
@@ -129,6 +141,10 @@ const (
 	// tidb_pprof_sql_cpu is used to add label sql label to pprof result.
 	TiDBPProfSQLCPU = "tidb_pprof_sql_cpu"
 
+	// tidb_service_scope is the service scope of the server, the owners of the background work such as DDL and auto
+	// analyze prefer the servers of the background scope.
+	TiDBServiceScope = "tidb_service_scope"
+
 	// tidb_retry_limit is the maximum number of retries when committing a transaction.
 	TiDBRetryLimit = "tidb_retry_limit"
 
@@ -167,6 +183,7 @@ const (
 	TiDBLowResolutionTSO = "tidb_low_resolution_tso"
 
 	// TiDBReplicaRead is used for reading data from replicas, followers for example.
+	// With follower-in-read-only-txn, only the reads of START TRANSACTION READ ONLY are routed to the followers.
 	TiDBReplicaRead = "tidb_replica_read"
 
 	// TiDBAllowRemoveAutoInc indicates whether a user can drop the auto_increment column attribute or not.
@@ -213,6 +230,10 @@ const (
 
 	// TiDBTxnReadTS indicates the next transaction should be staleness transaction and provide the startTS
 	TiDBTxnReadTS = "tx_read_ts"
+
+	// TiDBSQLHints injects the hints to the statements of the digests in the session without creating bindings.
+	// The value is a list of `digest:hints` separated by ';', the hints are written like the ones in `/*+ ... */`.
+	TiDBSQLHints = "sql_hints"
 )
 
 // TiDB system variable names that both in session and global scope.
@@ -233,9 +254,31 @@ const (
 	// tidb_opt_prefer_range_scan is used to enable/disable the optimizer to always prefer range scan over table scan, ignoring their costs.
 	TiDBOptPreferRangeScan = "tidb_opt_prefer_range_scan"
 
+	// tidb_opt_decorrelate_non_eq_agg is used to enable/disable decorrelating the correlated aggregation whose correlated
+	// conditions are not all equal conditions, like `select (select avg(b) from t2 where t2.a < t1.a) from t1`.
+	TiDBOptDecorrelateNonEqAgg = "tidb_opt_decorrelate_non_eq_agg"
+
+	// tidb_opt_decorrelate_scalar_subquery is used to enable/disable decorrelating the correlated aggregation of a scalar
+	// subquery into a join and an aggregation, like `select a, (select max(b) from t2 where t2.k = t1.k) from t1`. It
+	// can be disabled for a statement by the hint `SET_VAR(tidb_opt_decorrelate_scalar_subquery=OFF)`.
+	TiDBOptDecorrelateScalarSubquery = "tidb_opt_decorrelate_scalar_subquery"
+
+	// tidb_enable_null_aware_anti_join is used to enable/disable using the null-aware hash join for `not in (subq)`
+	// whose operands are nullable, instead of the CARTESIAN anti semi join.
+	TiDBEnableNullAwareAntiJoin = "tidb_enable_null_aware_anti_join"
+
+	// tidb_opt_enable_histogram_join_estimation is used to enable/disable estimating the row count of the equi-joins on
+	// a single column by aligning the histograms of the join keys bucket by bucket, which is more accurate than the NDV
+	// based estimation when the values are skewed.
+	TiDBOptEnableHistogramJoinEstimation = "tidb_opt_enable_histogram_join_estimation"
+
 	// tidb_opt_correlation_threshold is a guard to enable row count estimation using column order correlation.
 	TiDBOptCorrelationThreshold = "tidb_opt_correlation_threshold"
 
+	// tidb_opt_estimation_deviation_ratio is the ratio of the actual rows to the estimated rows of a reader, above which
+	// the statistics of the table are considered stale during the execution and fed back to the auto analyze. 0 disables it.
+	TiDBOptEstimationDeviationRatio = "tidb_opt_estimation_deviation_ratio"
+
 	// tidb_opt_correlation_exp_factor is an exponential factor to control heuristic approach when tidb_opt_correlation_threshold is not satisfied.
 	TiDBOptCorrelationExpFactor = "tidb_opt_correlation_exp_factor"
 
@@ -352,6 +395,10 @@ const (
 	// tidb_merge_join_concurrency is used for merge join parallel executor
 	TiDBMergeJoinConcurrency = "tidb_merge_join_concurrency"
 
+	// tidb_parallel_merge_join_concurrency is the number of workers joining the ranges of the ordered inputs of a
+	// merge join in parallel.
+	TiDBParallelMergeJoinConcurrency = "tidb_parallel_merge_join_concurrency"
+
 	// tidb_stream_agg_concurrency is used for stream aggregation parallel executor.
 	// tidb_stream_agg_concurrency is deprecated, use tidb_executor_concurrency instead.
 	TiDBStreamAggConcurrency = "tidb_streamagg_concurrency"
@@ -460,6 +507,14 @@ const (
 	// TiDBStmtSummaryMaxSQLLength indicates the max length of displayed normalized sql and sample sql.
 	TiDBStmtSummaryMaxSQLLength = "tidb_stmt_summary_max_sql_length"
 
+	// TiDBStmtSummarySLOLatency is the latency objective in milliseconds of the statement SLO, the executions that fail
+	// or exceed it violate the SLO. 0 disables the SLO metrics.
+	TiDBStmtSummarySLOLatency = "tidb_stmt_summary_slo_latency"
+
+	// TiDBStmtSummarySLOObjective is the target ratio of the executions meeting the statement SLO, which decides the
+	// error budget. It's exported as a metric so that the burn rates can be computed by Prometheus.
+	TiDBStmtSummarySLOObjective = "tidb_stmt_summary_slo_objective"
+
 	// TiDBCapturePlanBaseline indicates whether the capture of plan baselines is enabled.
 	TiDBCapturePlanBaseline = "tidb_capture_plan_baselines"
 
@@ -494,6 +549,11 @@ const (
 	// TiDBEnableClusteredIndex indicates if clustered index feature is enabled.
 	TiDBEnableClusteredIndex = "tidb_enable_clustered_index"
 
+	// TiDBCheckConstraintEnforcement indicates how the CHECK constraints are enforced.
+	// OFF: the CHECK constraints are parsed but ignored, WARN: the violations are reported as warnings,
+	// ON: the violations are reported as errors.
+	TiDBCheckConstraintEnforcement = "tidb_check_constraint_enforcement"
+
 	// TiDBPartitionPruneMode indicates the partition prune mode used.
 	TiDBPartitionPruneMode = "tidb_partition_prune_mode"
 
@@ -530,6 +590,14 @@ const (
 	// TiDBAnalyzeVersion indicates the how tidb collects the analyzed statistics and how use to it.
 	TiDBAnalyzeVersion = "tidb_analyze_version"
 
+	// TiDBAnalyzeAdaptiveBuckets indicates whether to select the bucket number of the column histograms adaptively
+	// from the skew of the samples, instead of using the fixed default number.
+	TiDBAnalyzeAdaptiveBuckets = "tidb_analyze_adaptive_buckets"
+
+	// TiDBStatsLoadSyncWait is the max time in milliseconds to wait for loading the unloaded column and index histograms
+	// needed by the optimizer synchronously, 0 means the histograms are only loaded asynchronously.
+	TiDBStatsLoadSyncWait = "tidb_stats_load_sync_wait"
+
 	// TiDBEnableIndexMergeJoin indicates whether to enable index merge join.
 	TiDBEnableIndexMergeJoin = "tidb_enable_index_merge_join"
 
@@ -539,6 +607,9 @@ const (
 	// TiDBEnableExchangePartition indicates whether to enable exchange partition.
 	TiDBEnableExchangePartition = "tidb_enable_exchange_partition"
 
+	// TiDBEnableReorganizePartition indicates whether to enable reorganize partition.
+	TiDBEnableReorganizePartition = "tidb_enable_reorganize_partition"
+
 	// TiDBAllowFallbackToTiKV indicates the engine types whose unavailability triggers fallback to TiKV.
 	// Now we only support TiFlash.
 	TiDBAllowFallbackToTiKV = "tidb_allow_fallback_to_tikv"
@@ -560,6 +631,14 @@ const (
 
 	// TiDBTopSQLReportIntervalSeconds indicates the top SQL report interval seconds.
 	TiDBTopSQLReportIntervalSeconds = "tidb_top_sql_report_interval_seconds"
+
+	// TiDBTopSQLMaxOverheadPercentage indicates the max CPU overhead percentage of the top SQL profiler, the collection
+	// is paused for a while when it's exceeded. 0 means no limit.
+	TiDBTopSQLMaxOverheadPercentage = "tidb_top_sql_max_overhead_percentage"
+
+	// TiDBTopSQLAdaptiveInterval indicates whether to adjust the profiling window of the top SQL profiler by the load,
+	// the window is enlarged under high QPS and the profiling is suspended when there is no statement executed.
+	TiDBTopSQLAdaptiveInterval = "tidb_top_sql_adaptive_interval"
 	// TiDBEnableGlobalTemporaryTable indicates whether to enable global temporary table
 	TiDBEnableGlobalTemporaryTable = "tidb_enable_global_temporary_table"
 	// TiDBEnableLocalTxn indicates whether to enable Local Txn.
@@ -567,6 +646,35 @@ const (
 
 	// TiDBEnableStableResultMode indicates if stabilize query results.
 	TiDBEnableStableResultMode = "tidb_enable_stable_result_mode"
+
+	// TiDBMaxEstimatedCost is the max estimated cost of the optimized plan of a statement, the statement whose
+	// estimated cost exceeds it is handled by TiDBMaxEstimatedCostAction before execution. 0 means no limit.
+	TiDBMaxEstimatedCost = "tidb_max_estimated_cost"
+
+	// TiDBMaxEstimatedRows is the max estimated row count of the operators in the optimized plan of a statement, the
+	// statement whose estimated row count exceeds it is handled by TiDBMaxEstimatedCostAction before execution. 0 means no limit.
+	TiDBMaxEstimatedRows = "tidb_max_estimated_rows"
+
+	// TiDBMaxEstimatedCostAction indicates what to do when the estimated cost of a statement exceeds TiDBMaxEstimatedCost,
+	// or its estimated row count exceeds TiDBMaxEstimatedRows.
+	// CANCEL rejects the statement, WARN only logs it and appends a warning.
+	TiDBMaxEstimatedCostAction = "tidb_max_estimated_cost_action"
+
+	// TiDBMaxResultRows is the max number of rows sent to the client by a statement, the statement whose
+	// result set exceeds it is handled by TiDBMaxResultAction. 0 means no limit.
+	TiDBMaxResultRows = "tidb_max_result_rows"
+
+	// TiDBMaxResultBytes is the max size in bytes of the rows sent to the client by a statement, the statement
+	// whose result set exceeds it is handled by TiDBMaxResultAction. 0 means no limit.
+	TiDBMaxResultBytes = "tidb_max_result_bytes"
+
+	// TiDBMaxResultAction indicates what to do when the result set of a statement exceeds TiDBMaxResultRows or
+	// TiDBMaxResultBytes. CANCEL aborts the statement with an error, TRUNCATE stops at the limit and appends a warning.
+	TiDBMaxResultAction = "tidb_max_result_action"
+
+	// TiDBFullTextTokenizer indicates the tokenizer used by `MATCH ... AGAINST` to split the text into terms.
+	// "standard" splits the text by the non-word characters, "ngram" splits the words into bigrams for CJK texts.
+	TiDBFullTextTokenizer = "tidb_fulltext_tokenizer"
 )
 
 // TiDB vars that have only global scope
@@ -584,6 +692,24 @@ const (
 	TiDBGCScanLockMode = "tidb_gc_scan_lock_mode"
 	// TiDBEnableEnhancedSecurity restricts SUPER users from certain operations.
 	TiDBEnableEnhancedSecurity = "tidb_enable_enhanced_security"
+	// TiDBEnableHealthReport indicates whether the daily health report of the cluster is generated.
+	TiDBEnableHealthReport = "tidb_enable_health_report"
+	// TiDBHealthReportStorage is the URL of the external storage that the health reports are also written to,
+	// e.g. "s3://bucket/prefix". Empty means the reports are only saved in mysql.health_report. The URL can't carry the
+	// credentials, they're read from the environment of the tidb-server.
+	TiDBHealthReportStorage = "tidb_health_report_storage"
+	// TiDBAdmissionCPUThreshold is the CPU usage ratio of the tidb-server above which the new statement executions are
+	// queued by their priorities. 0 disables the admission control.
+	TiDBAdmissionCPUThreshold = "tidb_admission_cpu_threshold"
+	// TiDBAdmissionMaxQueueTime is the max time in milliseconds a statement is queued by the admission control, it's
+	// executed anyway once the time is exceeded. 0 means no limit.
+	TiDBAdmissionMaxQueueTime = "tidb_admission_max_queue_time"
+	// TiDBEnableGOGCTuner indicates whether GOGC is lowered after each GC to keep the heap below the memory limit of
+	// the instance, e.g. the cgroup memory limit in the containers.
+	TiDBEnableGOGCTuner = "tidb_enable_gogc_tuner"
+	// TiDBMemQuotaBindingCache is the memory quota in bytes of the global binding cache, the least recently used
+	// bindings are evicted from the cache when it's exceeded.
+	TiDBMemQuotaBindingCache = "tidb_mem_quota_binding_cache"
 )
 
 // Default TiDB system variable values.
@@ -599,17 +725,20 @@ const (
 	DefAutoAnalyzeRatio                = 0.5
 	DefAutoAnalyzeStartTime            = "00:00 +0000"
 	DefAutoAnalyzeEndTime              = "23:59 +0000"
+	DefAutoAnalyzeConcurrency          = 1
 	DefAutoIncrementIncrement          = 1
 	DefAutoIncrementOffset             = 1
 	DefChecksumTableConcurrency        = 4
 	DefSkipUTF8Check                   = false
 	DefSkipASCIICheck                  = false
 	DefOptAggPushDown                  = false
+	DefOptForceAggPushDown             = false
 	DefOptBCJ                          = false
 	DefOptCartesianBCJ                 = 1
 	DefOptMPPOuterJoinFixedBuildSide   = false
 	DefOptWriteRowID                   = false
 	DefOptCorrelationThreshold         = 0.9
+	DefOptEstimationDeviationRatio     = 0
 	DefOptCorrelationExpFactor         = 1
 	DefOptCPUFactor                    = 3.0
 	DefOptCopCPUFactor                 = 3.0
@@ -623,6 +752,10 @@ const (
 	DefOptConcurrencyFactor            = 3.0
 	DefOptInSubqToJoinAndAgg           = true
 	DefOptPreferRangeScan              = false
+	DefOptDecorrelateNonEqAgg          = false
+	DefOptDecorrelateScalarSubquery    = true
+	DefTiDBEnableNullAwareAntiJoin     = false
+	DefOptHistogramJoinEstimation      = false
 	DefBatchInsert                     = false
 	DefBatchDelete                     = false
 	DefBatchCommit                     = false
@@ -630,6 +763,7 @@ const (
 	DefInitChunkSize                   = 32
 	DefMaxChunkSize                    = 1024
 	DefDMLBatchSize                    = 0
+	DefOnDupBatchSize                  = 0
 	DefMaxPreparedStmtCount            = -1
 	DefWaitTimeout                     = 0
 	DefTiDBMemQuotaApplyCache          = 32 << 20 // 32MB.
@@ -667,6 +801,7 @@ const (
 	DefTiDBHashAggFinalConcurrency     = ConcurrencyUnset
 	DefTiDBWindowConcurrency           = ConcurrencyUnset
 	DefTiDBMergeJoinConcurrency        = 1 // disable optimization by default
+	DefParallelMergeJoinConcurrency    = 1 // disable optimization by default
 	DefTiDBStreamAggConcurrency        = 1
 	DefTiDBForcePriority               = mysql.NoPriority
 	DefEnableWindowFunction            = true
@@ -697,6 +832,7 @@ const (
 	DefTiDBEnableCollectExecutionInfo  = true
 	DefTiDBAllowAutoRandExplicitInsert = false
 	DefTiDBEnableClusteredIndex        = ClusteredIndexDefModeIntOnly
+	DefTiDBCheckConstraintEnforcement  = CheckConstraintLevelOff
 	DefTiDBRedactLog                   = false
 	DefTiDBShardAllocateStep           = math.MaxInt64
 	DefTiDBEnableTelemetry             = true
@@ -708,9 +844,12 @@ const (
 	DefTiDBEnable1PC                   = false
 	DefTiDBGuaranteeLinearizability    = true
 	DefTiDBAnalyzeVersion              = 2
+	DefTiDBAnalyzeAdaptiveBuckets      = false
+	DefTiDBStatsLoadSyncWait           = 0
 	DefTiDBEnableIndexMergeJoin        = false
 	DefTiDBTrackAggregateMemoryUsage   = true
 	DefTiDBEnableExchangePartition     = false
+	DefTiDBEnableReorganizePartition   = false
 	DefCTEMaxRecursionDepth            = 1000
 	DefTiDBTopSQLEnable                = false
 	DefTiDBTopSQLAgentAddress          = ""
@@ -718,12 +857,32 @@ const (
 	DefTiDBTopSQLMaxStatementCount     = 200
 	DefTiDBTopSQLMaxCollect            = 10000
 	DefTiDBTopSQLReportIntervalSeconds = 60
+	DefTiDBTopSQLMaxOverheadPercentage = 10.0
+	DefTiDBTopSQLAdaptiveInterval      = false
 	DefTiDBEnableGlobalTemporaryTable  = false
 	DefTMPTableSize                    = 16777216
 	DefTiDBEnableLocalTxn              = false
 	DefTiDBEnableStableResultMode      = false
+	DefTiDBMaxEstimatedCost            = 0
+	DefTiDBMaxEstimatedRows            = 0
+	DefTiDBMaxEstimatedCostAction      = MaxEstimatedCostActionCancel
+	DefTiDBMaxResultRows               = 0
+	DefTiDBMaxResultBytes              = 0
+	DefTiDBMaxResultAction             = MaxResultActionCancel
+	DefTiDBFullTextTokenizer           = fulltext.TokenizerStandard
+	DefTiDBEnableHealthReport          = false
+	DefTiDBAdmissionCPUThreshold       = 0
+	DefTiDBAdmissionMaxQueueTime       = 5000
+	DefTiDBEnableGOGCTuner             = false
+	DefTiDBServiceScope                = ""
+	DefTiDBMemQuotaBindingCache        = 64 << 20 // 64MB.
+	DefTiDBStmtSummarySLOLatency       = 0
+	DefTiDBStmtSummarySLOObjective     = 0.999
 )
 
+// ServiceScopeBackground is the service scope of the servers dedicated to the background work.
+const ServiceScopeBackground = "background"
+
 // Process global variables.
 var (
 	ProcessGeneralLog            = atomic.NewBool(false)
@@ -753,8 +912,22 @@ var (
 		MaxStatementCount:     atomic.NewInt64(DefTiDBTopSQLMaxStatementCount),
 		MaxCollect:            atomic.NewInt64(DefTiDBTopSQLMaxCollect),
 		ReportIntervalSeconds: atomic.NewInt64(DefTiDBTopSQLReportIntervalSeconds),
+		MaxOverheadPercentage: atomic.NewFloat64(DefTiDBTopSQLMaxOverheadPercentage),
+		AdaptiveInterval:      atomic.NewBool(DefTiDBTopSQLAdaptiveInterval),
 	}
-	EnableLocalTxn = atomic.NewBool(DefTiDBEnableLocalTxn)
+	EnableLocalTxn        = atomic.NewBool(DefTiDBEnableLocalTxn)
+	AdmissionCPUThreshold = atomic.NewFloat64(DefTiDBAdmissionCPUThreshold)
+	AdmissionMaxQueueTime = atomic.NewInt64(DefTiDBAdmissionMaxQueueTime)
+	EnableGOGCTuner       = atomic.NewBool(DefTiDBEnableGOGCTuner)
+	ServiceScope          = atomic.NewString(DefTiDBServiceScope)
+	MemQuotaBindingCache  = atomic.NewInt64(DefTiDBMemQuotaBindingCache)
+	// CheckConstraintEnforcement is the CheckConstraintLevel of the instance, use GetCheckConstraintLevel to read it.
+	CheckConstraintEnforcement = atomic.NewInt32(int32(DefTiDBCheckConstraintEnforcement))
+	// IndexUsageSyncLease is the interval of dumping the index usage to mysql.schema_index_usage, 0 means the index
+	// usage isn't collected. It's set by session.SetIndexUsageSyncLease.
+	// Because we have not completed GC and other functions, it's 0 by default.
+	// TODO: Set IndexUsageSyncLease to 60s.
+	IndexUsageSyncLease = atomic.NewDuration(0)
 )
 
 // TopSQL is the variable for control top sql feature.
@@ -771,6 +944,10 @@ type TopSQL struct {
 	MaxCollect *atomic.Int64
 	// The report data interval of top-sql.
 	ReportIntervalSeconds *atomic.Int64
+	// The max CPU overhead percentage of top-sql, 0 means no limit.
+	MaxOverheadPercentage *atomic.Float64
+	// Adjust the profiling window by the load or not.
+	AdaptiveInterval *atomic.Bool
 }
 
 // TopSQLEnabled uses to check whether enabled the top SQL feature.
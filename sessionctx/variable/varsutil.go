@@ -14,6 +14,7 @@
 package variable
 
 import (
+	"net/url"
 	"strconv"
 	"strings"
 	"sync"
@@ -21,6 +22,8 @@ import (
 	"time"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/charset"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/types"
@@ -148,6 +151,32 @@ func checkReadOnly(vars *SessionVars, normalizedValue string, originalValue stri
 	return normalizedValue, nil
 }
 
+// storageURLCredentialOptions are the options of the external storage URL which carry the credentials.
+var storageURLCredentialOptions = []string{"access-key", "secret-access-key", "session-token", "account-key", "sas-token"}
+
+// checkStorageURLCredentials checks the external storage URL doesn't carry the credentials, which can be read by the
+// users who can read the variable. The value isn't included in the error for the same reason.
+func checkStorageURLCredentials(name string, storageURL string) error {
+	if storageURL == "" {
+		return nil
+	}
+	u, err := url.Parse(storageURL)
+	if err != nil {
+		return errors.Errorf("the value of %s is not a valid URL", name)
+	}
+	hasCredentials := u.User != nil
+	for key := range u.Query() {
+		key = strings.ReplaceAll(strings.ToLower(key), "_", "-")
+		for _, option := range storageURLCredentialOptions {
+			hasCredentials = hasCredentials || key == option
+		}
+	}
+	if hasCredentials {
+		return errors.Errorf("the URL of %s can't contain the credentials, please provide them by the environment of the tidb-server instead", name)
+	}
+	return nil
+}
+
 func checkIsolationLevel(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
 	if normalizedValue == "SERIALIZABLE" || normalizedValue == "READ-UNCOMMITTED" {
 		returnErr := ErrUnsupportedIsolationLevel.GenWithStackByArgs(normalizedValue)
@@ -288,6 +317,47 @@ func TiDBOptEnableClustered(opt string) ClusteredIndexDefMode {
 	}
 }
 
+// CheckConstraintLevel controls how the CHECK constraints are enforced.
+type CheckConstraintLevel int
+
+const (
+	// CheckConstraintLevelOff indicates the CHECK constraints are parsed but ignored.
+	CheckConstraintLevelOff CheckConstraintLevel = iota
+	// CheckConstraintLevelWarn indicates the rows violating the CHECK constraints are written with warnings.
+	CheckConstraintLevelWarn
+	// CheckConstraintLevelOn indicates the rows violating the CHECK constraints are rejected.
+	CheckConstraintLevelOn
+)
+
+// String implements the fmt.Stringer interface.
+func (l CheckConstraintLevel) String() string {
+	switch l {
+	case CheckConstraintLevelOn:
+		return On
+	case CheckConstraintLevelWarn:
+		return Warn
+	default:
+		return Off
+	}
+}
+
+// GetCheckConstraintLevel returns how the CHECK constraints are enforced, see tidb_check_constraint_enforcement.
+func GetCheckConstraintLevel() CheckConstraintLevel {
+	return CheckConstraintLevel(CheckConstraintEnforcement.Load())
+}
+
+// TiDBOptCheckConstraintLevel converts the check constraint enforcement options to CheckConstraintLevel.
+func TiDBOptCheckConstraintLevel(opt string) CheckConstraintLevel {
+	switch opt {
+	case On:
+		return CheckConstraintLevelOn
+	case Warn:
+		return CheckConstraintLevelWarn
+	default:
+		return CheckConstraintLevelOff
+	}
+}
+
 func tidbOptPositiveInt32(opt string, defaultVal int) int {
 	val, err := strconv.Atoi(opt)
 	if err != nil || val <= 0 {
@@ -325,7 +395,7 @@ func parseTimeZone(s string) (*time.Location, error) {
 		return timeutil.SystemLocation(), nil
 	}
 
-	loc, err := time.LoadLocation(s)
+	loc, err := timeutil.LoadLocation(s)
 	if err == nil {
 		return loc, nil
 	}
@@ -401,6 +471,31 @@ func setTxnReadTS(s *SessionVars, sVal string) error {
 	return err
 }
 
+// parseSQLHints parses the `digest:hints` list of sql_hints, the hints of the same digest are merged.
+func parseSQLHints(val string, sqlMode mysql.SQLMode) (map[string][]*ast.TableOptimizerHint, error) {
+	var sqlHints map[string][]*ast.TableOptimizerHint
+	for _, item := range strings.Split(val, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		pos := strings.IndexByte(item, ':')
+		if pos <= 0 {
+			return nil, ErrWrongValueForVar.GenWithStackByArgs(TiDBSQLHints, val)
+		}
+		digest := strings.ToLower(strings.TrimSpace(item[:pos]))
+		hints, errs := parser.ParseHint("/*+ "+item[pos+1:]+" */", sqlMode, parser.Pos{})
+		if len(errs) > 0 || len(hints) == 0 {
+			return nil, ErrWrongValueForVar.GenWithStackByArgs(TiDBSQLHints, val)
+		}
+		if sqlHints == nil {
+			sqlHints = make(map[string][]*ast.TableOptimizerHint)
+		}
+		sqlHints[digest] = append(sqlHints[digest], hints...)
+	}
+	return sqlHints, nil
+}
+
 // serverGlobalVariable is used to handle variables that acts in server and global scope.
 type serverGlobalVariable struct {
 	sync.Mutex
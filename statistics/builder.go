@@ -211,6 +211,42 @@ func BuildColumn(ctx sessionctx.Context, numBuckets, id int64, collector *Sample
 	return BuildColumnHist(ctx, numBuckets, id, collector, tp, collector.Count, collector.FMSketch.NDV(), collector.NullCount)
 }
 
+// AdaptiveBucketCount selects the bucket number of a column histogram from the skew of the samples. The skew is
+// measured by 1 - H/log(k), where H is the entropy of the sampled values and k is the number of the distinct sampled
+// values, so it's 0 for the uniform distribution and close to 1 for the heavily skewed one. The selected number grows
+// linearly with the skew from half of defaultNum to 4 times of defaultNum, since the uniform distribution is well
+// estimated by the interpolation inside the buckets, while the skewed one needs more buckets to be described.
+func AdaptiveBucketCount(sc *stmtctx.StatementContext, samples []*SampleItem, defaultNum int) (int, error) {
+	if len(samples) == 0 || defaultNum <= 1 {
+		return defaultNum, nil
+	}
+	counts := make(map[string]int, len(samples))
+	var key []byte
+	var err error
+	for _, sample := range samples {
+		key, err = codec.EncodeKey(sc, key[:0], sample.Value)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		counts[string(key)]++
+	}
+	if len(counts) <= 1 {
+		return defaultNum, nil
+	}
+	total := float64(len(samples))
+	entropy := 0.0
+	for _, cnt := range counts {
+		p := float64(cnt) / total
+		entropy -= p * math.Log(p)
+	}
+	skew := 1 - entropy/math.Log(float64(len(counts)))
+	if skew < 0 {
+		skew = 0
+	}
+	minNum, maxNum := float64(defaultNum/2), float64(defaultNum*4)
+	return int(math.Round(minNum + (maxNum-minNum)*skew)), nil
+}
+
 // BuildHistAndTopN build a histogram and TopN for a column or an index from samples.
 func BuildHistAndTopN(
 	ctx sessionctx.Context,
@@ -52,13 +52,14 @@ func (h *Handle) initStatsMeta4Chunk(is infoschema.InfoSchema, cache *statsCache
 			HistColl: newHistColl,
 			Version:  row.GetUint64(0),
 			Name:     getFullTableName(is, tableInfo),
+			Snapshot: row.GetUint64(4),
 		}
 		cache.tables[physicalID] = tbl
 	}
 }
 
 func (h *Handle) initStatsMeta(is infoschema.InfoSchema) (statsCache, error) {
-	sql := "select HIGH_PRIORITY version, table_id, modify_count, count from mysql.stats_meta"
+	sql := "select HIGH_PRIORITY version, table_id, modify_count, count, snapshot from mysql.stats_meta"
 	rc, err := h.mu.ctx.(sqlexec.SQLExecutor).ExecuteInternal(context.TODO(), sql)
 	if err != nil {
 		return statsCache{}, errors.Trace(err)
@@ -106,14 +107,17 @@ func (h *Handle) initStatsHistograms4Chunk(is infoschema.InfoSchema, cache *stat
 				cms = nil
 				terror.Log(errors.Trace(err))
 			}
-			hist := statistics.NewHistogram(id, ndv, nullCount, version, types.NewFieldType(mysql.TypeBlob), chunk.InitialCapacity, 0)
+			// The buckets of the index are not loaded at the start, they're loaded by needs.
+			hist := statistics.NewHistogram(id, ndv, nullCount, version, types.NewFieldType(mysql.TypeBlob), 0, 0)
 			index := &statistics.Index{
-				Histogram: *hist,
-				CMSketch:  cms,
-				TopN:      topN,
-				Info:      idxInfo,
-				StatsVer:  statsVer,
-				Flag:      row.GetInt64(10),
+				Histogram:    *hist,
+				CMSketch:     cms,
+				TopN:         topN,
+				Info:         idxInfo,
+				StatsVer:     statsVer,
+				Flag:         row.GetInt64(10),
+				PhysicalID:   table.PhysicalID,
+				HistUnloaded: ndv > 0,
 			}
 			lastAnalyzePos.Copy(&index.LastAnalyzePos)
 			table.Indices[hist.ID] = index
@@ -278,42 +282,33 @@ func (h *Handle) initStatsBuckets4Chunk(cache *statsCache, iter *chunk.Iterator4
 		if !ok {
 			continue
 		}
-		var lower, upper types.Datum
-		var hist *statistics.Histogram
+		// Only the buckets of columns are loaded, the ones of indexes are loaded by needs.
 		if isIndex > 0 {
-			index, ok := table.Indices[histID]
-			if !ok {
-				continue
-			}
-			hist = &index.Histogram
-			lower, upper = types.NewBytesDatum(row.GetBytes(5)), types.NewBytesDatum(row.GetBytes(6))
-		} else {
-			column, ok := table.Columns[histID]
-			if !ok {
-				continue
-			}
-			column.Count += row.GetInt64(3)
-			if !mysql.HasPriKeyFlag(column.Info.Flag) {
-				continue
-			}
-			hist = &column.Histogram
-			d := types.NewBytesDatum(row.GetBytes(5))
-			var err error
-			lower, err = d.ConvertTo(h.mu.ctx.GetSessionVars().StmtCtx, &column.Info.FieldType)
-			if err != nil {
-				logutil.BgLogger().Debug("decode bucket lower bound failed", zap.Error(err))
-				delete(table.Columns, histID)
-				continue
-			}
-			d = types.NewBytesDatum(row.GetBytes(6))
-			upper, err = d.ConvertTo(h.mu.ctx.GetSessionVars().StmtCtx, &column.Info.FieldType)
-			if err != nil {
-				logutil.BgLogger().Debug("decode bucket upper bound failed", zap.Error(err))
-				delete(table.Columns, histID)
-				continue
-			}
+			continue
 		}
-		hist.AppendBucketWithNDV(&lower, &upper, row.GetInt64(3), row.GetInt64(4), row.GetInt64(7))
+		column, ok := table.Columns[histID]
+		if !ok {
+			continue
+		}
+		column.Count += row.GetInt64(3)
+		if !mysql.HasPriKeyFlag(column.Info.Flag) {
+			continue
+		}
+		d := types.NewBytesDatum(row.GetBytes(5))
+		lower, err := d.ConvertTo(h.mu.ctx.GetSessionVars().StmtCtx, &column.Info.FieldType)
+		if err != nil {
+			logutil.BgLogger().Debug("decode bucket lower bound failed", zap.Error(err))
+			delete(table.Columns, histID)
+			continue
+		}
+		d = types.NewBytesDatum(row.GetBytes(6))
+		upper, err := d.ConvertTo(h.mu.ctx.GetSessionVars().StmtCtx, &column.Info.FieldType)
+		if err != nil {
+			logutil.BgLogger().Debug("decode bucket upper bound failed", zap.Error(err))
+			delete(table.Columns, histID)
+			continue
+		}
+		column.Histogram.AppendBucketWithNDV(&lower, &upper, row.GetInt64(3), row.GetInt64(4), row.GetInt64(7))
 	}
 }
 
@@ -342,7 +337,7 @@ func (h *Handle) initTopNCountSum(tableID, colID int64) (int64, error) {
 }
 
 func (h *Handle) initStatsBuckets(cache *statsCache) error {
-	sql := "select HIGH_PRIORITY table_id, is_index, hist_id, count, repeats, lower_bound, upper_bound, ndv from mysql.stats_buckets order by table_id, is_index, hist_id, bucket_id"
+	sql := "select HIGH_PRIORITY table_id, is_index, hist_id, count, repeats, lower_bound, upper_bound, ndv from mysql.stats_buckets where is_index = 0 order by table_id, hist_id, bucket_id"
 	rc, err := h.mu.ctx.(sqlexec.SQLExecutor).ExecuteInternal(context.TODO(), sql)
 	if err != nil {
 		return errors.Trace(err)
@@ -363,12 +358,6 @@ func (h *Handle) initStatsBuckets(cache *statsCache) error {
 	lastVersion := uint64(0)
 	for _, table := range cache.tables {
 		lastVersion = mathutil.MaxUint64(lastVersion, table.Version)
-		for _, idx := range table.Indices {
-			for i := 1; i < idx.Len(); i++ {
-				idx.Buckets[i].Count += idx.Buckets[i-1].Count
-			}
-			idx.PreCalculateScalar()
-		}
 		for _, col := range table.Columns {
 			for i := 1; i < col.Len(); i++ {
 				col.Buckets[i].Count += col.Buckets[i-1].Count
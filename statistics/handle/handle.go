@@ -33,12 +33,14 @@ import (
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
+	tidbutil "github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
@@ -46,6 +48,7 @@ import (
 	"github.com/tikv/client-go/v2/oracle"
 	atomic2 "go.uber.org/atomic"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
@@ -107,6 +110,79 @@ type Handle struct {
 
 	// idxUsageListHead contains all the index usage collectors required by session.
 	idxUsageListHead *SessionIndexUsageCollector
+
+	// estDeviated marks the tables whose estimated rows deviate from the actual rows in execution.
+	estDeviated analyzeMarks
+	// bulkDeleted marks the tables whose most rows are deleted since they were analyzed.
+	bulkDeleted analyzeMarks
+
+	// syncLoadGroup merges the concurrent synchronous loadings of the same histogram.
+	syncLoadGroup singleflight.Group
+}
+
+// analyzeMarks are the marks of the tables to be analyzed by the auto analyze. The marks made on an instance are
+// persisted to a system table when the stats delta is dumped, since the auto analyze only runs on the stats owner.
+type analyzeMarks struct {
+	// table is the system table in the mysql schema which persists the marks.
+	table string
+	// local contains the marks made on this instance which are not persisted yet.
+	local physicalTableSet
+	// loaded contains the persisted marks loaded by the current round of the auto analyze.
+	loaded physicalTableSet
+}
+
+// physicalTableSet is a set of physical table IDs which is safe for concurrent use.
+type physicalTableSet struct {
+	sync.Mutex
+	tables map[int64]struct{}
+}
+
+func (s *physicalTableSet) add(physicalID int64) {
+	s.Lock()
+	defer s.Unlock()
+	if s.tables == nil {
+		s.tables = make(map[int64]struct{})
+	}
+	s.tables[physicalID] = struct{}{}
+}
+
+// take checks and removes the physical ID from the set.
+func (s *physicalTableSet) take(physicalID int64) bool {
+	s.Lock()
+	defer s.Unlock()
+	_, ok := s.tables[physicalID]
+	delete(s.tables, physicalID)
+	return ok
+}
+
+func (s *physicalTableSet) has(physicalID int64) bool {
+	s.Lock()
+	defer s.Unlock()
+	_, ok := s.tables[physicalID]
+	return ok
+}
+
+// takeAll removes and returns all the physical IDs in the set.
+func (s *physicalTableSet) takeAll() []int64 {
+	s.Lock()
+	defer s.Unlock()
+	ids := make([]int64, 0, len(s.tables))
+	for id := range s.tables {
+		ids = append(ids, id)
+	}
+	s.tables = nil
+	return ids
+}
+
+// reset replaces the physical IDs in the set.
+func (s *physicalTableSet) reset(physicalIDs []int64) {
+	tables := make(map[int64]struct{}, len(physicalIDs))
+	for _, id := range physicalIDs {
+		tables[id] = struct{}{}
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.tables = tables
 }
 
 func (h *Handle) withRestrictedSQLExecutor(ctx context.Context, fn func(context.Context, sqlexec.RestrictedSQLExecutor) ([]chunk.Row, []*ast.ResultField, error)) ([]chunk.Row, []*ast.ResultField, error) {
@@ -196,6 +272,8 @@ func NewHandle(ctx sessionctx.Context, lease time.Duration, pool sessionPool) (*
 		feedback:         statistics.NewQueryFeedbackMap(),
 		idxUsageListHead: &SessionIndexUsageCollector{mapper: make(indexUsageMap)},
 		pool:             pool,
+		estDeviated:      analyzeMarks{table: "stats_estimation_deviated"},
+		bulkDeleted:      analyzeMarks{table: "stats_bulk_deleted"},
 	}
 	handle.lease.Store(lease)
 	handle.pool = pool
@@ -249,7 +327,7 @@ func (h *Handle) Update(is infoschema.InfoSchema) error {
 		lastVersion = 0
 	}
 	ctx := context.Background()
-	rows, _, err := h.execRestrictedSQL(ctx, "SELECT version, table_id, modify_count, count from mysql.stats_meta where version > %? order by version", lastVersion)
+	rows, _, err := h.execRestrictedSQL(ctx, "SELECT version, table_id, modify_count, count, snapshot from mysql.stats_meta where version > %? order by version", lastVersion)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -261,6 +339,7 @@ func (h *Handle) Update(is infoschema.InfoSchema) error {
 		physicalID := row.GetInt64(1)
 		modifyCount := row.GetInt64(2)
 		count := row.GetInt64(3)
+		snapshot := row.GetUint64(4)
 		lastVersion = version
 		h.mu.Lock()
 		table, ok := h.getTableByPhysicalID(is, physicalID)
@@ -287,6 +366,7 @@ func (h *Handle) Update(is infoschema.InfoSchema) error {
 		tbl.Version = version
 		tbl.Count = count
 		tbl.ModifyCount = modifyCount
+		tbl.Snapshot = snapshot
 		tbl.Name = getFullTableName(is, tableInfo)
 		tbl.TblInfoUpdateTS = tableInfo.UpdateTS
 		tables = append(tables, tbl)
@@ -587,7 +667,7 @@ func (sc statsCache) update(tables []*statistics.Table, deletedIDs []int64, newV
 	return newCache
 }
 
-// LoadNeededHistograms will load histograms for those needed columns.
+// LoadNeededHistograms will load histograms for those needed columns and indexes.
 func (h *Handle) LoadNeededHistograms() (err error) {
 	cols := statistics.HistogramNeededColumns.AllCols()
 	reader, err := h.getStatsReader(0)
@@ -603,63 +683,189 @@ func (h *Handle) LoadNeededHistograms() (err error) {
 	}()
 
 	for _, col := range cols {
-		oldCache := h.statsCache.Load().(statsCache)
-		tbl, ok := oldCache.tables[col.TableID]
-		if !ok {
-			continue
-		}
-		c, ok := tbl.Columns[col.ColumnID]
-		if !ok || c.Len() > 0 {
-			statistics.HistogramNeededColumns.Delete(col)
-			continue
-		}
-		hg, err := h.histogramFromStorage(reader, col.TableID, c.ID, &c.Info.FieldType, c.Histogram.NDV, 0, c.LastUpdateVersion, c.NullCount, c.TotColSize, c.Correlation)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		cms, topN, err := h.cmSketchAndTopNFromStorage(reader, col.TableID, 0, col.ColumnID)
-		if err != nil {
-			return errors.Trace(err)
-		}
-		fms, err := h.fmSketchFromStorage(reader, col.TableID, 0, col.ColumnID)
-		if err != nil {
-			return errors.Trace(err)
+		var loaded bool
+		if col.IsIndex {
+			loaded, err = h.loadIndexHistogram(reader, col.TableID, col.ColumnID)
+		} else {
+			loaded, err = h.loadColumnHistogram(reader, col.TableID, col.ColumnID)
 		}
-		rows, _, err := reader.read("select stats_ver from mysql.stats_histograms where is_index = 0 and table_id = %? and hist_id = %?", col.TableID, col.ColumnID)
 		if err != nil {
-			return errors.Trace(err)
-		}
-		if len(rows) == 0 {
-			logutil.BgLogger().Error("fail to get stats version for this histogram", zap.Int64("table_id", col.TableID), zap.Int64("hist_id", col.ColumnID))
-		}
-		colHist := &statistics.Column{
-			PhysicalID: col.TableID,
-			Histogram:  *hg,
-			Info:       c.Info,
-			CMSketch:   cms,
-			TopN:       topN,
-			FMSketch:   fms,
-			Count:      int64(hg.TotalRowCount()),
-			IsHandle:   c.IsHandle,
-			StatsVer:   rows[0].GetInt64(0),
-		}
-		colHist.Count = int64(colHist.TotalRowCount())
-		// Reload the latest stats cache, otherwise the `updateStatsCache` may fail with high probability, because functions
-		// like `GetPartitionStats` called in `fmSketchFromStorage` would have modified the stats cache already.
-		oldCache = h.statsCache.Load().(statsCache)
-		tbl, ok = oldCache.tables[col.TableID]
-		if !ok {
-			continue
+			return err
 		}
-		tbl = tbl.Copy()
-		tbl.Columns[c.ID] = colHist
-		if h.updateStatsCache(oldCache.update([]*statistics.Table{tbl}, nil, oldCache.version)) {
+		if loaded {
 			statistics.HistogramNeededColumns.Delete(col)
 		}
 	}
 	return nil
 }
 
+// loadColumnHistogram loads the histogram of the column into the stats cache, it returns false if the histogram needs
+// to be loaded again later.
+func (h *Handle) loadColumnHistogram(reader *statsReader, tableID, colID int64) (bool, error) {
+	oldCache := h.statsCache.Load().(statsCache)
+	tbl, ok := oldCache.tables[tableID]
+	if !ok {
+		return false, nil
+	}
+	c, ok := tbl.Columns[colID]
+	if !ok || c.Len() > 0 {
+		return true, nil
+	}
+	hg, err := h.histogramFromStorage(reader, tableID, c.ID, &c.Info.FieldType, c.Histogram.NDV, 0, c.LastUpdateVersion, c.NullCount, c.TotColSize, c.Correlation)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	cms, topN, err := h.cmSketchAndTopNFromStorage(reader, tableID, 0, colID)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	fms, err := h.fmSketchFromStorage(reader, tableID, 0, colID)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	rows, _, err := reader.read("select stats_ver from mysql.stats_histograms where is_index = 0 and table_id = %? and hist_id = %?", tableID, colID)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if len(rows) == 0 {
+		logutil.BgLogger().Error("fail to get stats version for this histogram", zap.Int64("table_id", tableID), zap.Int64("hist_id", colID))
+		return false, errors.Errorf("fail to get the stats version of the histogram, table id %d, hist id %d", tableID, colID)
+	}
+	colHist := &statistics.Column{
+		PhysicalID: tableID,
+		Histogram:  *hg,
+		Info:       c.Info,
+		CMSketch:   cms,
+		TopN:       topN,
+		FMSketch:   fms,
+		Count:      int64(hg.TotalRowCount()),
+		IsHandle:   c.IsHandle,
+		StatsVer:   rows[0].GetInt64(0),
+	}
+	colHist.Count = int64(colHist.TotalRowCount())
+	// Reload the latest stats cache, otherwise the `updateStatsCache` may fail with high probability, because functions
+	// like `GetPartitionStats` called in `fmSketchFromStorage` would have modified the stats cache already.
+	oldCache = h.statsCache.Load().(statsCache)
+	tbl, ok = oldCache.tables[tableID]
+	if !ok {
+		return false, nil
+	}
+	tbl = tbl.Copy()
+	tbl.Columns[c.ID] = colHist
+	return h.updateStatsCache(oldCache.update([]*statistics.Table{tbl}, nil, oldCache.version)), nil
+}
+
+// loadIndexHistogram loads the histogram of the index into the stats cache, it returns false if the histogram needs
+// to be loaded again later.
+func (h *Handle) loadIndexHistogram(reader *statsReader, tableID, idxID int64) (bool, error) {
+	oldCache := h.statsCache.Load().(statsCache)
+	tbl, ok := oldCache.tables[tableID]
+	if !ok {
+		return false, nil
+	}
+	idx, ok := tbl.Indices[idxID]
+	if !ok || !idx.IsLoadNeeded() {
+		return true, nil
+	}
+	hg, err := h.histogramFromStorage(reader, tableID, idxID, types.NewFieldType(mysql.TypeBlob), idx.NDV, 1, idx.LastUpdateVersion, idx.NullCount, 0, 0)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	newIdx := &statistics.Index{
+		Histogram:  *hg,
+		CMSketch:   idx.CMSketch,
+		TopN:       idx.TopN,
+		FMSketch:   idx.FMSketch,
+		ErrorRate:  idx.ErrorRate,
+		StatsVer:   idx.StatsVer,
+		Info:       idx.Info,
+		Flag:       idx.Flag,
+		PhysicalID: tableID,
+	}
+	idx.LastAnalyzePos.Copy(&newIdx.LastAnalyzePos)
+	// Reload the latest stats cache, the index stats may be updated during the loading.
+	oldCache = h.statsCache.Load().(statsCache)
+	tbl, ok = oldCache.tables[tableID]
+	if !ok {
+		return false, nil
+	}
+	if cur, ok := tbl.Indices[idxID]; !ok || cur.LastUpdateVersion != idx.LastUpdateVersion {
+		return true, nil
+	}
+	tbl = tbl.Copy()
+	tbl.Indices[idxID] = newIdx
+	return h.updateStatsCache(oldCache.update([]*statistics.Table{tbl}, nil, oldCache.version)), nil
+}
+
+// SyncLoadHistograms loads the histograms of the columns and indexes of the physical table synchronously, and returns
+// the table stats with the loaded histograms. An error is returned if the loading doesn't finish within the wait time,
+// the loading still goes on in the background.
+func (h *Handle) SyncLoadHistograms(tblInfo *model.TableInfo, physicalID int64, colIDs, idxIDs []int64, wait time.Duration) (*statistics.Table, error) {
+	start := time.Now()
+	defer func() {
+		metrics.SyncLoadHistogram.Observe(time.Since(start).Seconds())
+	}()
+	results := make([]<-chan singleflight.Result, 0, len(colIDs)+len(idxIDs))
+	for _, colID := range colIDs {
+		results = append(results, h.syncLoadHistogram(physicalID, colID, false))
+	}
+	for _, idxID := range idxIDs {
+		results = append(results, h.syncLoadHistogram(physicalID, idxID, true))
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for _, result := range results {
+		select {
+		case res := <-result:
+			if res.Err != nil {
+				metrics.SyncLoadCounter.WithLabelValues("failed").Inc()
+				return nil, res.Err
+			}
+		case <-timer.C:
+			metrics.SyncLoadCounter.WithLabelValues("timeout").Inc()
+			return nil, errors.Errorf("loading the histograms of table %s timed out after %v, use pseudo stats for the columns and indexes", tblInfo.Name.O, wait)
+		}
+	}
+	metrics.SyncLoadCounter.WithLabelValues("succ").Inc()
+	return h.GetPartitionStats(tblInfo, physicalID), nil
+}
+
+// syncLoadHistogram loads the histogram of the column or index in the background. The queries needing the same
+// histogram share one loading, so the queries timed out don't pile up the loadings behind the stats reader lock.
+func (h *Handle) syncLoadHistogram(physicalID, histID int64, isIndex bool) <-chan singleflight.Result {
+	key := fmt.Sprintf("%d_%d_%v", physicalID, histID, isIndex)
+	return h.syncLoadGroup.DoChan(key, func() (interface{}, error) {
+		var err error
+		tidbutil.WithRecovery(func() {
+			err = h.loadHistogram(physicalID, histID, isIndex)
+		}, func(r interface{}) {
+			if r != nil {
+				err = errors.Errorf("loading the histogram of table %d, hist %d panicked: %v", physicalID, histID, r)
+			}
+		})
+		return nil, err
+	})
+}
+
+func (h *Handle) loadHistogram(physicalID, histID int64, isIndex bool) (err error) {
+	reader, err := h.getStatsReader(0)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err1 := h.releaseStatsReader(reader)
+		if err1 != nil && err == nil {
+			err = err1
+		}
+	}()
+	if isIndex {
+		_, err = h.loadIndexHistogram(reader, physicalID, histID)
+	} else {
+		_, err = h.loadColumnHistogram(reader, physicalID, histID)
+	}
+	return err
+}
+
 // LastUpdateVersion gets the last update version.
 func (h *Handle) LastUpdateVersion() uint64 {
 	return h.statsCache.Load().(statsCache).version
@@ -710,7 +916,7 @@ func (h *Handle) fmSketchFromStorage(reader *statsReader, tblID int64, isIndex,
 	return statistics.DecodeFMSketch(rows[0].GetBytes(0))
 }
 
-func (h *Handle) indexStatsFromStorage(reader *statsReader, row chunk.Row, table *statistics.Table, tableInfo *model.TableInfo) error {
+func (h *Handle) indexStatsFromStorage(reader *statsReader, row chunk.Row, table *statistics.Table, tableInfo *model.TableInfo, loadAll bool) error {
 	histID := row.GetInt64(2)
 	distinct := row.GetInt64(3)
 	histVer := row.GetUint64(4)
@@ -728,7 +934,8 @@ func (h *Handle) indexStatsFromStorage(reader *statsReader, row chunk.Row, table
 		if histID != idxInfo.ID {
 			continue
 		}
-		if idx == nil || idx.LastUpdateVersion < histVer {
+		// The histogram of the index may be left unloaded by `InitStats`, we load it here if loadAll is true.
+		if idx == nil || idx.LastUpdateVersion < histVer || loadAll && idx.IsLoadNeeded() {
 			hg, err := h.histogramFromStorage(reader, table.PhysicalID, histID, types.NewFieldType(mysql.TypeBlob), distinct, 1, histVer, nullCount, 0, 0)
 			if err != nil {
 				return errors.Trace(err)
@@ -741,7 +948,7 @@ func (h *Handle) indexStatsFromStorage(reader *statsReader, row chunk.Row, table
 			if err != nil {
 				return errors.Trace(err)
 			}
-			idx = &statistics.Index{Histogram: *hg, CMSketch: cms, TopN: topN, FMSketch: fmSketch, Info: idxInfo, ErrorRate: errorRate, StatsVer: row.GetInt64(7), Flag: flag}
+			idx = &statistics.Index{Histogram: *hg, CMSketch: cms, TopN: topN, FMSketch: fmSketch, Info: idxInfo, ErrorRate: errorRate, StatsVer: row.GetInt64(7), Flag: flag, PhysicalID: table.PhysicalID}
 			lastAnalyzePos.Copy(&idx.LastAnalyzePos)
 		}
 		break
@@ -898,7 +1105,7 @@ func (h *Handle) TableStatsFromStorage(tableInfo *model.TableInfo, physicalID in
 	}
 	for _, row := range rows {
 		if row.GetInt64(1) > 0 {
-			err = h.indexStatsFromStorage(reader, row, table, tableInfo)
+			err = h.indexStatsFromStorage(reader, row, table, tableInfo, loadAll)
 		} else {
 			err = h.columnStatsFromStorage(reader, row, table, tableInfo, loadAll)
 		}
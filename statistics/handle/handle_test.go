@@ -522,6 +522,16 @@ func (s *testStatsSuite) TestInitStats(c *C) {
 	c.Assert(cols[1].LastAnalyzePos.GetBytes()[0], Equals, uint8(0x36))
 	c.Assert(cols[2].LastAnalyzePos.GetBytes()[0], Equals, uint8(0x37))
 	c.Assert(cols[3].LastAnalyzePos.GetBytes()[0], Equals, uint8(0x38))
+	// The histograms of the indexes are loaded by needs.
+	for _, idx := range table0.Indices {
+		c.Assert(idx.IsLoadNeeded(), IsTrue)
+		c.Assert(idx.IsInvalid(false), IsTrue)
+	}
+	c.Assert(h.LoadNeededHistograms(), IsNil)
+	table0 = h.GetTableStats(tbl.Meta())
+	for _, idx := range table0.Indices {
+		c.Assert(idx.IsLoadNeeded(), IsFalse)
+	}
 	h.Clear()
 	c.Assert(h.Update(is), IsNil)
 	table1 := h.GetTableStats(tbl.Meta())
@@ -552,6 +562,16 @@ func (s *testStatsSuite) TestInitStatsVer2(c *C) {
 	c.Assert(cols[1].LastAnalyzePos.GetBytes()[0], Equals, uint8(0x33))
 	c.Assert(cols[2].LastAnalyzePos.GetBytes()[0], Equals, uint8(0x33))
 	c.Assert(cols[3].LastAnalyzePos.GetBytes()[0], Equals, uint8(0x33))
+	// The histograms of the indexes are loaded by needs.
+	for _, idx := range table0.Indices {
+		c.Assert(idx.IsLoadNeeded(), IsTrue)
+		c.Assert(idx.IsInvalid(false), IsTrue)
+	}
+	c.Assert(h.LoadNeededHistograms(), IsNil)
+	table0 = h.GetTableStats(tbl.Meta())
+	for _, idx := range table0.Indices {
+		c.Assert(idx.IsLoadNeeded(), IsFalse)
+	}
 	h.Clear()
 	c.Assert(h.Update(is), IsNil)
 	table1 := h.GetTableStats(tbl.Meta())
@@ -612,6 +632,69 @@ func (s *testStatsSuite) TestLoadStats(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *testStatsSuite) TestSyncLoadStats(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	testKit := testkit.NewTestKit(c, s.store)
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t(a int, b int, c int, primary key(a), key idx(b))")
+	testKit.MustExec("insert into t values (1,1,1),(2,2,2),(3,3,3)")
+
+	oriLease := s.do.StatsHandle().Lease()
+	s.do.StatsHandle().SetLease(1)
+	defer func() {
+		s.do.StatsHandle().SetLease(oriLease)
+	}()
+	testKit.MustExec("analyze table t")
+
+	is := s.do.InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tableInfo := tbl.Meta()
+	h := s.do.StatsHandle()
+	colID := tableInfo.Columns[2].ID
+	c.Assert(h.GetTableStats(tableInfo).Columns[colID].IsLoadNeeded(), IsTrue)
+
+	// The histograms are not loaded synchronously by default.
+	testKit.MustQuery("explain format = 'brief' select * from t where c > 1").Check(testkit.Rows(
+		"TableReader 1.00 root  data:Selection",
+		"└─Selection 1.00 cop[tikv]  gt(test.t.c, 1)",
+		"  └─TableFullScan 3.00 cop[tikv] table:t keep order:false",
+	))
+	c.Assert(h.GetTableStats(tableInfo).Columns[colID].IsLoadNeeded(), IsTrue)
+
+	testKit.MustExec("set @@tidb_stats_load_sync_wait = 60000")
+	testKit.MustQuery("explain format = 'brief' select * from t where c > 1").Check(testkit.Rows(
+		"TableReader 2.00 root  data:Selection",
+		"└─Selection 2.00 cop[tikv]  gt(test.t.c, 1)",
+		"  └─TableFullScan 3.00 cop[tikv] table:t keep order:false",
+	))
+	testKit.MustQuery("show warnings").Check(testkit.Rows())
+	c.Assert(h.GetTableStats(tableInfo).Columns[colID].IsLoadNeeded(), IsFalse)
+
+	// The histograms of the indexes are not loaded by `InitStats`, they're loaded synchronously when used.
+	h.Clear()
+	c.Assert(h.InitStats(is), IsNil)
+	idxID := tableInfo.Indices[0].ID
+	c.Assert(h.GetTableStats(tableInfo).Indices[idxID].IsLoadNeeded(), IsTrue)
+	testKit.MustQuery("explain format = 'brief' select * from t use index(idx) where b > 1").Check(testkit.Rows(
+		"IndexLookUp 2.00 root  ",
+		"├─IndexRangeScan(Build) 2.00 cop[tikv] table:t, index:idx(b) range:(1,+inf], keep order:false",
+		"└─TableRowIDScan(Probe) 2.00 cop[tikv] table:t keep order:false",
+	))
+	testKit.MustQuery("show warnings").Check(testkit.Rows())
+	c.Assert(h.GetTableStats(tableInfo).Indices[idxID].IsLoadNeeded(), IsFalse)
+
+	// The loading fails instead of panicking if the stats version of the histogram is missing.
+	h.Clear()
+	c.Assert(h.InitStats(is), IsNil)
+	c.Assert(h.GetTableStats(tableInfo).Columns[colID].IsLoadNeeded(), IsTrue)
+	testKit.MustExec(fmt.Sprintf("delete from mysql.stats_histograms where table_id = %d and hist_id = %d and is_index = 0", tableInfo.ID, colID))
+	testKit.MustQuery("explain format = 'brief' select * from t where c > 1")
+	testKit.MustQuery("show warnings").Check(testkit.Rows(
+		fmt.Sprintf("Warning 1105 fail to get the stats version of the histogram, table id %d, hist id %d", tableInfo.ID, colID)))
+	c.Assert(h.GetTableStats(tableInfo).Columns[colID].IsLoadNeeded(), IsTrue)
+}
+
 func newStoreWithBootstrap() (kv.Storage, *domain.Domain, error) {
 	store, err := mockstore.NewMockStore()
 	if err != nil {
@@ -2209,6 +2292,31 @@ func (s *statsSerialSuite) TestIndexUsageInformation(c *C) {
 	))
 }
 
+func (s *statsSerialSuite) TestIndexUsageTable(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	session.SetIndexUsageSyncLease(1)
+	defer session.SetIndexUsageSyncLease(0)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t_idx(a int, b int, c int, unique index idx_a(a), index idx_b(b), index idx_c(c))")
+	tk.MustExec("insert into t_idx values(1, 1, 1), (2, 2, 2), (3, 3, 3)")
+	tk.MustQuery("select a from t_idx where a = 1").Check(testkit.Rows("1"))
+	tk.MustQuery("select b from t_idx use index(idx_b) where b > 1").Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select * from t_idx use index(idx_b) where b > 2").Check(testkit.Rows("3 3 3"))
+	c.Assert(s.do.StatsHandle().DumpIndexUsageToKV(), IsNil)
+	tk.MustQuery("select table_schema, table_name, index_name, query_count, last_used_at is not null from information_schema.tidb_index_usage where table_name = 't_idx'").Sort().Check(testkit.Rows(
+		"test t_idx idx_a 1 1",
+		"test t_idx idx_b 2 1",
+		"test t_idx idx_c 0 0",
+	))
+	tk.MustQuery("select * from sys.schema_unused_indexes where object_name = 't_idx'").Check(testkit.Rows("test t_idx idx_c"))
+
+	// The indexes without recorded usage aren't reported as unused while the collection is disabled.
+	session.SetIndexUsageSyncLease(0)
+	tk.MustQuery("select index_name, query_count from information_schema.tidb_index_usage where table_name = 't_idx' and index_name = 'idx_c'").Check(testkit.Rows("idx_c <nil>"))
+	tk.MustQuery("select * from sys.schema_unused_indexes where object_name = 't_idx'").Check(testkit.Rows())
+}
+
 func (s *statsSerialSuite) TestGCIndexUsageInformation(c *C) {
 	defer cleanEnv(c, s.store, s.do)
 	session.SetIndexUsageSyncLease(1)
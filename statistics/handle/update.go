@@ -15,6 +15,7 @@ package handle
 
 import (
 	"bytes"
+	"container/heap"
 	"context"
 	"fmt"
 	"math"
@@ -35,6 +36,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/logutil"
@@ -49,6 +51,9 @@ type tableDeltaMap map[int64]variable.TableDelta
 
 func (m tableDeltaMap) update(id int64, delta int64, count int64, colSize *map[int64]int64) {
 	item := m[id]
+	if item.InitTime.IsZero() {
+		item.InitTime = time.Now()
+	}
 	item.Delta += delta
 	item.Count += count
 	if item.ColSize == nil {
@@ -334,6 +339,9 @@ var (
 	DumpStatsDeltaRatio = 1 / 10000.0
 	// dumpStatsMaxDuration is the max duration since last update.
 	dumpStatsMaxDuration = time.Hour
+	// StaleDeltaDuration is the max duration to wait for the count of the table to catch up with the delta deleting
+	// more rows than it, the count is regarded as stale and corrected to 0 after that.
+	StaleDeltaDuration = time.Hour
 )
 
 // needDumpStatsDelta returns true when only updates a small portion of the table and the time since last update
@@ -422,12 +430,16 @@ func (h *Handle) DumpStatsDeltaToKV(mode dumpMode) error {
 		if mode == DumpDelta && !needDumpStatsDelta(h, id, item, currentTime) {
 			continue
 		}
+		bulkDelete := h.isBulkDelete(id, item)
 		updated, err := h.dumpTableStatCountToKV(id, item)
 		if err != nil {
 			return errors.Trace(err)
 		}
 		if updated {
 			h.globalMap.update(id, -item.Delta, -item.Count, nil)
+			if bulkDelete {
+				h.handleBulkDelete(id, item)
+			}
 		}
 		if err = h.dumpTableStatColSizeToKV(id, item); err != nil {
 			return errors.Trace(err)
@@ -440,9 +452,56 @@ func (h *Handle) DumpStatsDeltaToKV(mode dumpMode) error {
 			h.globalMap[id] = m
 		}
 	}
+	if err := h.dumpAnalyzeMarksToKV(&h.estDeviated); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(h.dumpAnalyzeMarksToKV(&h.bulkDeleted))
+}
+
+// dumpAnalyzeMarksToKV moves the local marks to the system table of the marks.
+func (h *Handle) dumpAnalyzeMarksToKV(marks *analyzeMarks) error {
+	ids := marks.local.takeAll()
+	if len(ids) == 0 {
+		return nil
+	}
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, fmt.Sprintf("(%d)", id))
+	}
+	sql := fmt.Sprintf("insert ignore into mysql.%s (table_id) values %s", marks.table, strings.Join(values, ","))
+	if _, _, err := h.execRestrictedSQL(context.Background(), sql); err != nil {
+		// Keep the marks to dump them next time.
+		for _, id := range ids {
+			marks.local.add(id)
+		}
+		return err
+	}
 	return nil
 }
 
+// loadAnalyzeMarks loads the persisted marks for a round of the auto analyze.
+func (h *Handle) loadAnalyzeMarks(marks *analyzeMarks) error {
+	rows, _, err := h.execRestrictedSQL(context.Background(), "select table_id from mysql.%n", marks.table)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ids := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.GetInt64(0))
+	}
+	marks.loaded.reset(ids)
+	return nil
+}
+
+// clearAnalyzeMark clears the persisted mark of the table.
+func (h *Handle) clearAnalyzeMark(marks *analyzeMarks, physicalID int64) {
+	marks.loaded.take(physicalID)
+	if _, _, err := h.execRestrictedSQL(context.Background(), "delete from mysql.%n where table_id = %?", marks.table, physicalID); err != nil {
+		logutil.BgLogger().Warn("[stats] clear the auto analyze mark failed", zap.String("table", marks.table),
+			zap.Int64("physicalID", physicalID), zap.Error(err))
+	}
+}
+
 // dumpTableStatDeltaToKV dumps a single delta with some table to KV and updates the version.
 func (h *Handle) dumpTableStatCountToKV(id int64, delta variable.TableDelta) (updated bool, err error) {
 	if delta.Count == 0 {
@@ -467,7 +526,11 @@ func (h *Handle) dumpTableStatCountToKV(id int64, delta variable.TableDelta) (up
 	startTS := txn.StartTS()
 	updateStatsMeta := func(id int64) error {
 		var err error
-		if delta.Delta < 0 {
+		if delta.Delta < 0 && time.Since(delta.InitTime) > StaleDeltaDuration {
+			// The count is still less than the deleted rows after waiting for the deltas of other TiDB instances,
+			// it's stale and corrected to 0, otherwise the delta can never be dumped.
+			_, err = exec.ExecuteInternal(ctx, "update mysql.stats_meta set version = %?, count = if(count > %?, count - %?, 0), modify_count = modify_count + %? where table_id = %?", startTS, -delta.Delta, -delta.Delta, delta.Count, id)
+		} else if delta.Delta < 0 {
 			_, err = exec.ExecuteInternal(ctx, "update mysql.stats_meta set version = %?, count = count - %?, modify_count = modify_count + %? where table_id = %? and count >= %?", startTS, -delta.Delta, delta.Count, id, -delta.Delta)
 		} else {
 			_, err = exec.ExecuteInternal(ctx, "update mysql.stats_meta set version = %?, count = count + %?, modify_count = modify_count + %? where table_id = %?", startTS, delta.Delta, delta.Count, id)
@@ -838,6 +901,10 @@ const (
 // AutoAnalyzeMinCnt means if the count of table is less than this value, we needn't do auto analyze.
 var AutoAnalyzeMinCnt int64 = 1000
 
+// BulkDeleteRatio is the least ratio of the deleted rows to the rows of the table for the delete to be regarded as a
+// bulk delete, the stale stats of the table is analyzed with priority after it.
+var BulkDeleteRatio = 0.5
+
 // TableAnalyzed checks if the table is analyzed.
 func TableAnalyzed(tbl *statistics.Table) bool {
 	for _, col := range tbl.Columns {
@@ -884,8 +951,8 @@ func NeedAnalyzeTable(tbl *statistics.Table, limit time.Duration, autoAnalyzeRat
 
 func (h *Handle) getAutoAnalyzeParameters() map[string]string {
 	ctx := context.Background()
-	sql := "select variable_name, variable_value from mysql.global_variables where variable_name in (%?, %?, %?)"
-	rows, _, err := h.execRestrictedSQL(ctx, sql, variable.TiDBAutoAnalyzeRatio, variable.TiDBAutoAnalyzeStartTime, variable.TiDBAutoAnalyzeEndTime)
+	sql := "select variable_name, variable_value from mysql.global_variables where variable_name in (%?, %?, %?, %?)"
+	rows, _, err := h.execRestrictedSQL(ctx, sql, variable.TiDBAutoAnalyzeRatio, variable.TiDBAutoAnalyzeStartTime, variable.TiDBAutoAnalyzeEndTime, variable.TiDBAutoAnalyzeConcurrency)
 	if err != nil {
 		return map[string]string{}
 	}
@@ -904,6 +971,14 @@ func parseAutoAnalyzeRatio(ratio string) float64 {
 	return math.Max(autoAnalyzeRatio, 0)
 }
 
+func parseAutoAnalyzeConcurrency(concurrency string) int {
+	autoAnalyzeConcurrency, err := strconv.Atoi(concurrency)
+	if err != nil || autoAnalyzeConcurrency < 1 {
+		return variable.DefAutoAnalyzeConcurrency
+	}
+	return autoAnalyzeConcurrency
+}
+
 func parseAnalyzePeriod(start, end string) (time.Time, time.Time, error) {
 	if start == "" {
 		start = variable.DefAutoAnalyzeStartTime
@@ -919,7 +994,60 @@ func parseAnalyzePeriod(start, end string) (time.Time, time.Time, error) {
 	return s, e, err
 }
 
-// HandleAutoAnalyze analyzes the newly created table or index.
+// autoAnalyzeJob is an analyze statement triggered by the auto analyze.
+type autoAnalyzeJob struct {
+	sql      string
+	params   []interface{}
+	statsVer int
+	reason   string
+	// priority is the order of the job in the queue, the job with the higher priority is analyzed first.
+	priority float64
+	// deviatedID is the physical ID of the table analyzed since its estimation deviates, its mark is cleared after
+	// the job is run.
+	deviatedID int64
+	// bulkDeletedIDs are the physical IDs of the tables analyzed after the bulk deletes, their marks are cleared after
+	// the job is run.
+	bulkDeletedIDs []int64
+}
+
+// autoAnalyzeQueue is a priority queue of the auto analyze jobs, it implements heap.Interface.
+type autoAnalyzeQueue []*autoAnalyzeJob
+
+func (q autoAnalyzeQueue) Len() int { return len(q) }
+
+func (q autoAnalyzeQueue) Less(i, j int) bool { return q[i].priority > q[j].priority }
+
+func (q autoAnalyzeQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *autoAnalyzeQueue) Push(x interface{}) { *q = append(*q, x.(*autoAnalyzeJob)) }
+
+func (q *autoAnalyzeQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	job := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return job
+}
+
+// autoAnalyzeDeviatedWeight is multiplied to the priority of the table whose estimation deviates in execution,
+// since it is queried with the stale stats.
+const autoAnalyzeDeviatedWeight = 2
+
+// autoAnalyzePriority computes the priority of analyzing the table by its modification ratio.
+func autoAnalyzePriority(tbl *statistics.Table, deviated bool) float64 {
+	priority := float64(tbl.ModifyCount)
+	if tbl.Count > 0 {
+		priority /= float64(tbl.Count)
+	}
+	if deviated {
+		priority *= autoAnalyzeDeviatedWeight
+	}
+	return priority
+}
+
+// HandleAutoAnalyze analyzes the newly created table or index. The tables which need to be analyzed are ordered by
+// their modification ratio, at most `tidb_auto_analyze_concurrency` of them are analyzed concurrently in a round.
 func (h *Handle) HandleAutoAnalyze(is infoschema.InfoSchema) (analyzed bool) {
 	err := h.UpdateSessionVar()
 	if err != nil {
@@ -934,7 +1062,14 @@ func (h *Handle) HandleAutoAnalyze(is infoschema.InfoSchema) (analyzed bool) {
 		logutil.BgLogger().Error("[stats] parse auto analyze period failed", zap.Error(err))
 		return false
 	}
+	concurrency := parseAutoAnalyzeConcurrency(parameters[variable.TiDBAutoAnalyzeConcurrency])
+	for _, marks := range []*analyzeMarks{&h.estDeviated, &h.bulkDeleted} {
+		if err := h.loadAnalyzeMarks(marks); err != nil {
+			logutil.BgLogger().Warn("[stats] load the auto analyze marks failed", zap.String("table", marks.table), zap.Error(err))
+		}
+	}
 	pruneMode := h.CurrentPruneMode()
+	queue := make(autoAnalyzeQueue, 0)
 	for _, db := range dbs {
 		tbls := is.SchemaTables(model.NewCIStr(db))
 		for _, tbl := range tbls {
@@ -943,78 +1078,189 @@ func (h *Handle) HandleAutoAnalyze(is infoschema.InfoSchema) (analyzed bool) {
 			if pi == nil {
 				statsTbl := h.GetTableStats(tblInfo)
 				sql := "analyze table %n.%n"
-				analyzed := h.autoAnalyzeTable(tblInfo, statsTbl, start, end, autoAnalyzeRatio, sql, db, tblInfo.Name.O)
-				if analyzed {
-					// analyze one table at a time to let it get the freshest parameters.
-					// others will be analyzed next round which is just 3s later.
-					return true
+				if job := h.getAutoAnalyzeTableJob(tblInfo, statsTbl, start, end, autoAnalyzeRatio, sql, db, tblInfo.Name.O); job != nil {
+					heap.Push(&queue, job)
 				}
 				continue
 			}
 			if pruneMode == variable.Dynamic {
-				analyzed := h.autoAnalyzePartitionTable(tblInfo, pi, db, start, end, autoAnalyzeRatio)
-				if analyzed {
-					return true
+				if job := h.getAutoAnalyzePartitionTableJob(tblInfo, pi, db, start, end, autoAnalyzeRatio); job != nil {
+					heap.Push(&queue, job)
 				}
 				continue
 			}
 			for _, def := range pi.Definitions {
 				sql := "analyze table %n.%n partition %n"
 				statsTbl := h.GetPartitionStats(tblInfo, def.ID)
-				analyzed := h.autoAnalyzeTable(tblInfo, statsTbl, start, end, autoAnalyzeRatio, sql, db, tblInfo.Name.O, def.Name.O)
-				if analyzed {
-					return true
+				if job := h.getAutoAnalyzeTableJob(tblInfo, statsTbl, start, end, autoAnalyzeRatio, sql, db, tblInfo.Name.O, def.Name.O); job != nil {
+					heap.Push(&queue, job)
 				}
 			}
 		}
 	}
+	// Only the jobs with the highest priorities are run to let them get the freshest parameters, the others will be
+	// analyzed in the next rounds which are just 3s later.
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && queue.Len() > 0; i++ {
+		job := heap.Pop(&queue).(*autoAnalyzeJob)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			util.WithRecovery(func() { h.runAutoAnalyzeJob(job) }, nil)
+		}()
+		analyzed = true
+	}
+	wg.Wait()
+	return analyzed
+}
+
+func (h *Handle) runAutoAnalyzeJob(job *autoAnalyzeJob) {
+	escaped, err := sqlexec.EscapeSQL(job.sql, job.params...)
+	if err != nil {
+		return
+	}
+	logutil.BgLogger().Info("[stats] auto analyze triggered", zap.String("sql", escaped), zap.String("reason", job.reason), zap.Float64("priority", job.priority))
+	if job.deviatedID != 0 {
+		h.clearAnalyzeMark(&h.estDeviated, job.deviatedID)
+	}
+	for _, id := range job.bulkDeletedIDs {
+		h.clearAnalyzeMark(&h.bulkDeleted, id)
+	}
+	h.execAutoAnalyze(job.statsVer, job.sql, job.params...)
+}
+
+// MarkEstimationDeviated marks the table whose actual rows in execution exceed the estimated rows by far. The mark is
+// persisted by the next DumpStatsDeltaToKV, then the table is analyzed by the auto analyze if it has been modified
+// since it was analyzed.
+func (h *Handle) MarkEstimationDeviated(physicalID int64) {
+	h.estDeviated.local.add(physicalID)
+}
+
+// isEstimationDeviated checks the loaded mark of MarkEstimationDeviated.
+func (h *Handle) isEstimationDeviated(physicalID int64) bool {
+	return h.estDeviated.loaded.has(physicalID)
+}
+
+// isBulkDeleted checks the loaded mark of handleBulkDelete. The mark is cleared if the table has been analyzed since
+// the delete, e.g. by a manual analyze, or its stats are dropped, so it doesn't stay forever.
+func (h *Handle) isBulkDeleted(tbl *statistics.Table) bool {
+	if !h.bulkDeleted.loaded.has(tbl.PhysicalID) {
+		return false
+	}
+	if !tbl.Pseudo && tbl.ModifyCount > 0 {
+		return true
+	}
+	h.clearAnalyzeMark(&h.bulkDeleted, tbl.PhysicalID)
 	return false
 }
 
-func (h *Handle) autoAnalyzeTable(tblInfo *model.TableInfo, statsTbl *statistics.Table, start, end time.Time, ratio float64, sql string, params ...interface{}) bool {
-	if statsTbl.Pseudo || statsTbl.Count < AutoAnalyzeMinCnt {
+// isBulkDelete checks whether the delta deletes most rows of the table which is large enough to be auto analyzed.
+func (h *Handle) isBulkDelete(physicalID int64, delta variable.TableDelta) bool {
+	if delta.Delta >= 0 {
 		return false
 	}
-	if needAnalyze, reason := NeedAnalyzeTable(statsTbl, 20*h.Lease(), ratio, start, end, time.Now()); needAnalyze {
-		escaped, err := sqlexec.EscapeSQL(sql, params...)
-		if err != nil {
-			return false
-		}
-		logutil.BgLogger().Info("[stats] auto analyze triggered", zap.String("sql", escaped), zap.String("reason", reason))
+	tbl, ok := h.statsCache.Load().(statsCache).tables[physicalID]
+	if !ok || tbl.Pseudo || tbl.Count < AutoAnalyzeMinCnt {
+		return false
+	}
+	return float64(-delta.Delta) >= float64(tbl.Count)*BulkDeleteRatio
+}
+
+// handleBulkDelete applies the dumped delta of the bulk delete to the stats cache immediately, so the health of the
+// table is recalculated without waiting for the next update of the stats. The table is also marked to be analyzed by
+// the next round of the auto analyze even if its remaining rows are fewer than AutoAnalyzeMinCnt, the mark is persisted
+// to be seen by the stats owner.
+func (h *Handle) handleBulkDelete(physicalID int64, delta variable.TableDelta) {
+	h.bulkDeleted.local.add(physicalID)
+	oldCache := h.statsCache.Load().(statsCache)
+	tbl, ok := oldCache.tables[physicalID]
+	if !ok {
+		return
+	}
+	tbl = tbl.Copy()
+	tbl.Count += delta.Delta
+	if tbl.Count < 0 {
+		tbl.Count = 0
+	}
+	tbl.ModifyCount += delta.Count
+	h.updateStatsCache(oldCache.update([]*statistics.Table{tbl}, nil, oldCache.version))
+}
+
+// needAnalyzeByEstimationDeviation checks whether the modified table needs to be analyzed since its estimation
+// deviates in execution, the auto analyze must be enabled.
+func (h *Handle) needAnalyzeByEstimationDeviation(tbl *statistics.Table, autoAnalyzeRatio float64, start, end, now time.Time) bool {
+	if autoAnalyzeRatio == 0 || tbl.ModifyCount == 0 || !timeutil.WithinDayTimePeriod(start, end, now) {
+		return false
+	}
+	return h.isEstimationDeviated(tbl.PhysicalID)
+}
+
+// getAutoAnalyzeTableJob returns the job to analyze the table or its unanalyzed index, it returns nil if the table
+// doesn't need to be analyzed.
+func (h *Handle) getAutoAnalyzeTableJob(tblInfo *model.TableInfo, statsTbl *statistics.Table, start, end time.Time, ratio float64, sql string, params ...interface{}) *autoAnalyzeJob {
+	bulkDeleted := h.isBulkDeleted(statsTbl)
+	if statsTbl.Pseudo || statsTbl.Count < AutoAnalyzeMinCnt && !bulkDeleted {
+		return nil
+	}
+	needAnalyze, reason := NeedAnalyzeTable(statsTbl, 20*h.Lease(), ratio, start, end, time.Now())
+	deviated := h.needAnalyzeByEstimationDeviation(statsTbl, ratio, start, end, time.Now())
+	var deviatedID int64
+	if !needAnalyze && deviated {
+		needAnalyze, reason = true, "the estimated rows deviate from the actual rows in execution"
+		deviatedID = statsTbl.PhysicalID
+	}
+	if needAnalyze {
 		tableStatsVer := h.mu.ctx.GetSessionVars().AnalyzeVersion
 		statistics.CheckAnalyzeVerOnTable(statsTbl, &tableStatsVer)
-		h.execAutoAnalyze(tableStatsVer, sql, params...)
-		return true
+		job := &autoAnalyzeJob{
+			sql:        sql,
+			params:     params,
+			statsVer:   tableStatsVer,
+			reason:     reason,
+			priority:   autoAnalyzePriority(statsTbl, deviated || bulkDeleted),
+			deviatedID: deviatedID,
+		}
+		if bulkDeleted {
+			job.bulkDeletedIDs = []int64{statsTbl.PhysicalID}
+		}
+		return job
 	}
 	for _, idx := range tblInfo.Indices {
 		if _, ok := statsTbl.Indices[idx.ID]; !ok && idx.State == model.StatePublic {
-			sqlWithIdx := sql + "index %n"
-			paramsWithIdx := append(params, idx.Name.O)
-			escaped, err := sqlexec.EscapeSQL(sql, params...)
-			if err != nil {
-				return false
-			}
-			logutil.BgLogger().Info("[stats] auto analyze for unanalyzed", zap.String("sql", escaped))
 			tableStatsVer := h.mu.ctx.GetSessionVars().AnalyzeVersion
 			statistics.CheckAnalyzeVerOnTable(statsTbl, &tableStatsVer)
-			h.execAutoAnalyze(tableStatsVer, sqlWithIdx, paramsWithIdx...)
-			return true
+			return &autoAnalyzeJob{
+				sql:      sql + "index %n",
+				params:   append(params, idx.Name.O),
+				statsVer: tableStatsVer,
+				reason:   "index unanalyzed",
+				priority: autoAnalyzePriority(statsTbl, deviated),
+			}
 		}
 	}
-	return false
+	return nil
 }
 
-func (h *Handle) autoAnalyzePartitionTable(tblInfo *model.TableInfo, pi *model.PartitionInfo, db string, start, end time.Time, ratio float64) bool {
+// getAutoAnalyzePartitionTableJob returns the job to analyze the partitions or the unanalyzed index of the partition
+// table in dynamic prune mode, it returns nil if the table doesn't need to be analyzed.
+func (h *Handle) getAutoAnalyzePartitionTableJob(tblInfo *model.TableInfo, pi *model.PartitionInfo, db string, start, end time.Time, ratio float64) *autoAnalyzeJob {
 	tableStatsVer := h.mu.ctx.GetSessionVars().AnalyzeVersion
 	partitionNames := make([]interface{}, 0, len(pi.Definitions))
+	var priority float64
+	var bulkDeletedIDs []int64
 	for _, def := range pi.Definitions {
 		partitionStatsTbl := h.GetPartitionStats(tblInfo, def.ID)
-		if partitionStatsTbl.Pseudo || partitionStatsTbl.Count < AutoAnalyzeMinCnt {
+		bulkDeleted := h.isBulkDeleted(partitionStatsTbl)
+		if partitionStatsTbl.Pseudo || partitionStatsTbl.Count < AutoAnalyzeMinCnt && !bulkDeleted {
 			continue
 		}
 		if needAnalyze, _ := NeedAnalyzeTable(partitionStatsTbl, 20*h.Lease(), ratio, start, end, time.Now()); needAnalyze {
 			partitionNames = append(partitionNames, def.Name.O)
 			statistics.CheckAnalyzeVerOnTable(partitionStatsTbl, &tableStatsVer)
+			priority = math.Max(priority, autoAnalyzePriority(partitionStatsTbl, bulkDeleted))
+			if bulkDeleted {
+				bulkDeletedIDs = append(bulkDeletedIDs, def.ID)
+			}
 		}
 	}
 	getSQL := func(prefix, suffix string, numPartitions int) string {
@@ -1030,13 +1276,16 @@ func (h *Handle) autoAnalyzePartitionTable(tblInfo *model.TableInfo, pi *model.P
 		return sqlBuilder.String()
 	}
 	if len(partitionNames) > 0 {
-		logutil.BgLogger().Info("[stats] auto analyze triggered")
-		sql := getSQL("analyze table %n.%n partition", "", len(partitionNames))
-		params := append([]interface{}{db, tblInfo.Name.O}, partitionNames...)
 		statsTbl := h.GetTableStats(tblInfo)
 		statistics.CheckAnalyzeVerOnTable(statsTbl, &tableStatsVer)
-		h.execAutoAnalyze(tableStatsVer, sql, params...)
-		return true
+		return &autoAnalyzeJob{
+			sql:            getSQL("analyze table %n.%n partition", "", len(partitionNames)),
+			params:         append([]interface{}{db, tblInfo.Name.O}, partitionNames...),
+			statsVer:       tableStatsVer,
+			reason:         "too many modifications of the partitions",
+			priority:       priority,
+			bulkDeletedIDs: bulkDeletedIDs,
+		}
 	}
 	for _, idx := range tblInfo.Indices {
 		if idx.State != model.StatePublic {
@@ -1047,20 +1296,24 @@ func (h *Handle) autoAnalyzePartitionTable(tblInfo *model.TableInfo, pi *model.P
 			if _, ok := partitionStatsTbl.Indices[idx.ID]; !ok {
 				partitionNames = append(partitionNames, def.Name.O)
 				statistics.CheckAnalyzeVerOnTable(partitionStatsTbl, &tableStatsVer)
+				priority = math.Max(priority, autoAnalyzePriority(partitionStatsTbl, false))
 			}
 		}
 		if len(partitionNames) > 0 {
-			logutil.BgLogger().Info("[stats] auto analyze for unanalyzed")
-			sql := getSQL("analyze table %n.%n partition", " index %n", len(partitionNames))
 			params := append([]interface{}{db, tblInfo.Name.O}, partitionNames...)
 			params = append(params, idx.Name.O)
 			statsTbl := h.GetTableStats(tblInfo)
 			statistics.CheckAnalyzeVerOnTable(statsTbl, &tableStatsVer)
-			h.execAutoAnalyze(tableStatsVer, sql, params...)
-			return true
+			return &autoAnalyzeJob{
+				sql:      getSQL("analyze table %n.%n partition", " index %n", len(partitionNames)),
+				params:   params,
+				statsVer: tableStatsVer,
+				reason:   "index unanalyzed",
+				priority: priority,
+			}
 		}
 	}
-	return false
+	return nil
 }
 
 var execOptionForAnalyze = map[int]sqlexec.OptionFuncAlias{
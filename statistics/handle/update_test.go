@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/log"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/metrics"
@@ -509,6 +510,190 @@ func (s *testStatsSuite) TestAutoUpdate(c *C) {
 	})
 }
 
+func (s *testStatsSuite) TestAutoAnalyzeOnEstimationDeviation(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	testKit := testkit.NewTestKit(c, s.store)
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t (a int primary key auto_increment, b int)")
+	testKit.MustExec("set @@tidb_analyze_version = 1")
+	testKit.MustExec("insert into t (b) values (1)")
+	testKit.MustExec("analyze table t")
+	for i := 0; i < 10; i++ {
+		testKit.MustExec("insert into t (b) select b from t")
+	}
+	do := s.do
+	is := do.InfoSchema()
+	h := do.StatsHandle()
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	testKit.MustExec("analyze table t")
+	c.Assert(h.Update(is), IsNil)
+	testKit.MustExec("set global tidb_auto_analyze_ratio = 0.6")
+	defer func() {
+		testKit.MustExec("set global tidb_auto_analyze_ratio = 0.0")
+	}()
+
+	testKit.MustExec("insert into t (b) select b from t")
+	testKit.MustQuery("select * from t")
+	c.Assert(testKit.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(0))
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	c.Assert(h.Update(is), IsNil)
+	// The modifications 1024/2048 don't exceed the ratio.
+	c.Assert(h.HandleAutoAnalyze(is), IsFalse)
+
+	testKit.MustExec("insert into t (b) select b from t limit 256")
+	testKit.MustExec("set @@tidb_opt_estimation_deviation_ratio = 1.1")
+	testKit.MustQuery("select * from t")
+	warnings := testKit.Se.GetSessionVars().StmtCtx.GetWarnings()
+	c.Assert(warnings, HasLen, 1)
+	c.Assert(warnings[0].Err.Error(), Equals, "the actual rows of table t exceed the estimated rows 2048.00 by 1.1 times, its statistics may be stale")
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	c.Assert(h.Update(is), IsNil)
+	// The mark is persisted to be seen by the stats owner.
+	testKit.MustQuery("select count(*) from mysql.stats_estimation_deviated").Check(testkit.Rows("1"))
+	// The modifications 1280/2304 don't exceed the ratio either, but the table is analyzed since the estimation deviates.
+	c.Assert(h.HandleAutoAnalyze(is), IsTrue)
+	testKit.MustQuery("select count(*) from mysql.stats_estimation_deviated").Check(testkit.Rows("0"))
+	c.Assert(h.Update(is), IsNil)
+	c.Assert(h.HandleAutoAnalyze(is), IsFalse)
+}
+
+func (s *testStatsSuite) TestAutoAnalyzePriority(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	testKit := testkit.NewTestKit(c, s.store)
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t1 (a int)")
+	testKit.MustExec("create table t2 (a int)")
+	testKit.MustExec("create table t3 (a int)")
+	do := s.do
+	is := do.InfoSchema()
+	h := do.StatsHandle()
+	for _, tbl := range []string{"t1", "t2", "t3"} {
+		c.Assert(h.HandleDDLEvent(<-h.DDLEventCh()), IsNil)
+		testKit.MustExec("insert into " + tbl + " values (1), (2), (3), (4), (5), (6), (7), (8), (9), (10)")
+	}
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	c.Assert(h.Update(is), IsNil)
+	testKit.MustExec("analyze table t1, t2, t3")
+	c.Assert(h.Update(is), IsNil)
+
+	handle.AutoAnalyzeMinCnt = 0
+	testKit.MustExec("set global tidb_auto_analyze_ratio = 0.2")
+	defer func() {
+		handle.AutoAnalyzeMinCnt = 1000
+		testKit.MustExec("set global tidb_auto_analyze_ratio = 0.0")
+		testKit.MustExec("set global tidb_auto_analyze_concurrency = default")
+	}()
+
+	modifyCount := func(tbl string) int64 {
+		tblInfo, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr(tbl))
+		c.Assert(err, IsNil)
+		return h.GetTableStats(tblInfo.Meta()).ModifyCount
+	}
+	testKit.MustExec("insert into t1 values (11), (12), (13)")
+	testKit.MustExec("insert into t2 values (11), (12), (13), (14), (15), (16)")
+	testKit.MustExec("insert into t3 values (11), (12), (13), (14)")
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	c.Assert(h.Update(is), IsNil)
+
+	// The table with the highest modification ratio is analyzed first.
+	c.Assert(h.HandleAutoAnalyze(is), IsTrue)
+	c.Assert(h.Update(is), IsNil)
+	c.Assert(modifyCount("t1"), Equals, int64(3))
+	c.Assert(modifyCount("t2"), Equals, int64(0))
+	c.Assert(modifyCount("t3"), Equals, int64(4))
+
+	// The tables are analyzed concurrently in a round.
+	testKit.MustExec("set global tidb_auto_analyze_concurrency = 2")
+	c.Assert(h.HandleAutoAnalyze(is), IsTrue)
+	c.Assert(h.Update(is), IsNil)
+	c.Assert(modifyCount("t1"), Equals, int64(0))
+	c.Assert(modifyCount("t3"), Equals, int64(0))
+	c.Assert(h.HandleAutoAnalyze(is), IsFalse)
+}
+
+func (s *testStatsSuite) TestBulkDeleteStats(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	testKit := testkit.NewTestKit(c, s.store)
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t (a int)")
+	do := s.do
+	is := do.InfoSchema()
+	h := do.StatsHandle()
+	c.Assert(h.HandleDDLEvent(<-h.DDLEventCh()), IsNil)
+	testKit.MustExec("insert into t values (1), (2), (3), (4), (5), (6), (7), (8), (9), (10)")
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	c.Assert(h.Update(is), IsNil)
+	testKit.MustExec("analyze table t")
+	c.Assert(h.Update(is), IsNil)
+
+	handle.AutoAnalyzeMinCnt = 10
+	testKit.MustExec("set global tidb_auto_analyze_ratio = 0.5")
+	defer func() {
+		handle.AutoAnalyzeMinCnt = 1000
+		testKit.MustExec("set global tidb_auto_analyze_ratio = 0.0")
+	}()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tableInfo := tbl.Meta()
+
+	// The health of the table is recalculated once the bulk delete is dumped.
+	testKit.MustExec("delete from t where a > 2")
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	statsTbl := h.GetTableStats(tableInfo)
+	c.Assert(statsTbl.Count, Equals, int64(2))
+	c.Assert(statsTbl.ModifyCount, Equals, int64(8))
+	testKit.MustQuery("show stats_healthy where table_name = 't'").Check(testkit.Rows("test t  0"))
+	// The mark is persisted to be seen by the stats owner.
+	testKit.MustQuery("select count(*) from mysql.stats_bulk_deleted").Check(testkit.Rows("1"))
+
+	// The table is analyzed even if its remaining rows are fewer than AutoAnalyzeMinCnt.
+	c.Assert(h.HandleAutoAnalyze(is), IsTrue)
+	c.Assert(h.Update(is), IsNil)
+	statsTbl = h.GetTableStats(tableInfo)
+	c.Assert(statsTbl.Count, Equals, int64(2))
+	c.Assert(statsTbl.ModifyCount, Equals, int64(0))
+	c.Assert(h.HandleAutoAnalyze(is), IsFalse)
+	testKit.MustQuery("select count(*) from mysql.stats_bulk_deleted").Check(testkit.Rows("0"))
+
+	// The batch delete is a bulk delete too, and the mark is cleared once the table is analyzed by other means.
+	testKit.MustExec("insert into t values (3), (4), (5), (6), (7), (8), (9), (10)")
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	testKit.MustExec("analyze table t")
+	c.Assert(h.Update(is), IsNil)
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.EnableBatchDML = true
+	})
+	testKit.MustExec("set @@tidb_batch_delete = 1, @@tidb_dml_batch_size = 2")
+	testKit.MustExec("delete from t where a > 2")
+	testKit.MustExec("set @@tidb_batch_delete = default, @@tidb_dml_batch_size = default")
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.EnableBatchDML = false
+	})
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	statsTbl = h.GetTableStats(tableInfo)
+	c.Assert(statsTbl.Count, Equals, int64(2))
+	testKit.MustQuery("select count(*) from mysql.stats_bulk_deleted").Check(testkit.Rows("1"))
+	testKit.MustExec("analyze table t")
+	c.Assert(h.Update(is), IsNil)
+	c.Assert(h.HandleAutoAnalyze(is), IsFalse)
+	testKit.MustQuery("select count(*) from mysql.stats_bulk_deleted").Check(testkit.Rows("0"))
+
+	// The stale count is corrected to 0 instead of blocking the delta from being dumped forever.
+	testKit.MustExec(fmt.Sprintf("update mysql.stats_meta set count = 1 where table_id = %d", tableInfo.ID))
+	testKit.MustExec("delete from t")
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	testKit.MustQuery(fmt.Sprintf("select count, modify_count from mysql.stats_meta where table_id = %d", tableInfo.ID)).Check(testkit.Rows("1 0"))
+	oriDuration := handle.StaleDeltaDuration
+	handle.StaleDeltaDuration = 0
+	defer func() {
+		handle.StaleDeltaDuration = oriDuration
+	}()
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	testKit.MustQuery(fmt.Sprintf("select count, modify_count from mysql.stats_meta where table_id = %d", tableInfo.ID)).Check(testkit.Rows("0 2"))
+	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	testKit.MustQuery(fmt.Sprintf("select count, modify_count from mysql.stats_meta where table_id = %d", tableInfo.ID)).Check(testkit.Rows("0 2"))
+}
+
 func (s *testStatsSuite) TestAutoUpdatePartition(c *C) {
 	defer cleanEnv(c, s.store, s.do)
 	testKit := testkit.NewTestKit(c, s.store)
@@ -568,9 +753,9 @@ func (s *testSerialStatsSuite) TestAutoAnalyzeOnEmptyTable(c *C) {
 
 	tk.MustExec("use test")
 	tk.MustExec("create table t (a int, index idx(a))")
-	// to pass the stats.Pseudo check in autoAnalyzeTable
+	// to pass the stats.Pseudo check in getAutoAnalyzeTableJob
 	tk.MustExec("analyze table t")
-	// to pass the AutoAnalyzeMinCnt check in autoAnalyzeTable
+	// to pass the AutoAnalyzeMinCnt check in getAutoAnalyzeTableJob
 	tk.MustExec("insert into t values (1)" + strings.Repeat(", (1)", int(handle.AutoAnalyzeMinCnt)))
 	c.Assert(s.do.StatsHandle().DumpStatsDeltaToKV(handle.DumpAll), IsNil)
 	c.Assert(s.do.StatsHandle().Update(s.do.InfoSchema()), IsNil)
@@ -718,6 +903,7 @@ func (s *testStatsSuite) TestUpdateErrorRate(c *C) {
 	testKit.MustExec("insert into t values (1, 3)")
 
 	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	testKit.MustExec("set @@tidb_analyze_version = 1")
 	testKit.MustExec("analyze table t")
 
 	testKit.MustExec("insert into t values (2, 3)")
@@ -795,6 +981,7 @@ func (s *testStatsSuite) TestUpdatePartitionErrorRate(c *C) {
 	testKit.MustExec("insert into t values (1)")
 
 	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	testKit.MustExec("set @@tidb_analyze_version = 1")
 	testKit.MustExec("analyze table t")
 
 	testKit.MustExec("insert into t values (2)")
@@ -1992,6 +2179,7 @@ func (s *testStatsSuite) TestLoadHistCorrelation(c *C) {
 	testKit.MustExec("create table t(c int)")
 	testKit.MustExec("insert into t values(1),(2),(3),(4),(5)")
 	c.Assert(h.DumpStatsDeltaToKV(handle.DumpAll), IsNil)
+	testKit.MustExec("set @@tidb_analyze_version = 1")
 	testKit.MustExec("analyze table t")
 	h.Clear()
 	c.Assert(h.Update(s.do.InfoSchema()), IsNil)
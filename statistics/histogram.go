@@ -950,8 +950,9 @@ func (c *Column) MemoryUsage() (sum int64) {
 	return
 }
 
-// HistogramNeededColumns stores the columns whose Histograms need to be loaded from physical kv layer.
-// Currently, we only load index/pk's Histogram from kv automatically. Columns' are loaded by needs.
+// HistogramNeededColumns stores the columns and indexes whose Histograms need to be loaded from physical kv layer.
+// Currently, we only load index/pk's Histogram from kv automatically, except that the indexes' are not loaded at the
+// start. Columns' and those unloaded indexes' are loaded by needs.
 var HistogramNeededColumns = neededColumnMap{cols: map[tableColumnID]struct{}{}}
 
 // IsInvalid checks if this column is invalid. If this column has histogram but not loaded yet, then we mark it
@@ -960,62 +961,77 @@ func (c *Column) IsInvalid(sc *stmtctx.StatementContext, collPseudo bool) bool {
 	if collPseudo && c.NotAccurate() {
 		return true
 	}
-	if c.Histogram.NDV > 0 && c.notNullCount() == 0 && sc != nil {
+	if c.IsLoadNeeded() && sc != nil {
 		sc.SetHistogramsNotLoad()
 		HistogramNeededColumns.insert(tableColumnID{TableID: c.PhysicalID, ColumnID: c.Info.ID})
 	}
-	return c.TotalRowCount() == 0 || (c.Histogram.NDV > 0 && c.notNullCount() == 0)
+	return c.TotalRowCount() == 0 || c.IsLoadNeeded()
+}
+
+// IsLoadNeeded checks whether the column has been analyzed but its histogram is not loaded yet.
+func (c *Column) IsLoadNeeded() bool {
+	return c.Histogram.NDV > 0 && c.notNullCount() == 0
 }
 
 func (c *Column) equalRowCount(sc *stmtctx.StatementContext, val types.Datum, encodedVal []byte, modifyCount int64) (float64, error) {
+	count, method, err := c.equalRowCountWithMethod(sc, val, encodedVal, modifyCount)
+	if tracer := statsTracer(sc); tracer != nil && err == nil {
+		tracer.Record("equal", colName(c, c.ID), count, "value: %s, NDV: %d, null count: %d, TopN: %d, buckets: %d, by %s",
+			datumString(val), c.Histogram.NDV, c.NullCount, c.TopN.Num(), len(c.Histogram.Buckets), method)
+	}
+	return count, err
+}
+
+// equalRowCountWithMethod estimates the row count of the value, and returns the method used for the trace.
+func (c *Column) equalRowCountWithMethod(sc *stmtctx.StatementContext, val types.Datum, encodedVal []byte, modifyCount int64) (float64, string, error) {
 	if val.IsNull() {
-		return float64(c.NullCount), nil
+		return float64(c.NullCount), "null count", nil
 	}
 	if c.StatsVer < Version2 {
 		// All the values are null.
 		if c.Histogram.Bounds.NumRows() == 0 {
-			return 0.0, nil
+			return 0.0, "empty histogram", nil
 		}
 		if c.Histogram.NDV > 0 && c.outOfRange(val, encodedVal) {
-			return outOfRangeEQSelectivity(c.Histogram.NDV, modifyCount, int64(c.TotalRowCount())) * c.TotalRowCount(), nil
+			return outOfRangeEQSelectivity(c.Histogram.NDV, modifyCount, int64(c.TotalRowCount())) * c.TotalRowCount(), "out of histogram range", nil
 		}
 		if c.CMSketch != nil {
 			count, err := queryValue(sc, c.CMSketch, c.TopN, val)
-			return float64(count), errors.Trace(err)
+			return float64(count), "TopN and CMSketch", errors.Trace(err)
 		}
-		return c.Histogram.equalRowCount(val, false), nil
+		return c.Histogram.equalRowCount(val, false), "histogram", nil
 	}
 	// All the values are null.
 	if c.Histogram.Bounds.NumRows() == 0 && c.TopN.Num() == 0 {
-		return 0, nil
+		return 0, "empty histogram and TopN", nil
 	}
 	if c.Histogram.NDV+int64(c.TopN.Num()) > 0 && c.outOfRange(val, encodedVal) {
-		return outOfRangeEQSelectivity(c.Histogram.NDV, modifyCount, int64(c.TotalRowCount())) * c.TotalRowCount(), nil
+		return outOfRangeEQSelectivity(c.Histogram.NDV, modifyCount, int64(c.TotalRowCount())) * c.TotalRowCount(), "out of histogram range", nil
 	}
 	// Stats version == 2
 	// 1. try to find this value in TopN
 	if c.TopN != nil {
 		rowcount, ok := c.QueryTopN(encodedVal)
 		if ok {
-			return float64(rowcount), nil
+			return float64(rowcount), "TopN", nil
 		}
 	}
 	// 2. try to find this value in bucket.repeats(the last value in every bucket)
 	index, match := c.Histogram.Bounds.LowerBound(0, &val)
 	if index%2 == 1 && match {
-		return float64(c.Histogram.Buckets[index/2].Repeat), nil
+		return float64(c.Histogram.Buckets[index/2].Repeat), "bucket upper bound repeats", nil
 	}
 	if match {
 		cmp := chunk.GetCompareFunc(c.Histogram.Tp)
 		if cmp(c.Histogram.Bounds.GetRow(index), 0, c.Histogram.Bounds.GetRow(index+1), 0) == 0 {
-			return float64(c.Histogram.Buckets[index/2].Repeat), nil
+			return float64(c.Histogram.Buckets[index/2].Repeat), "bucket upper bound repeats", nil
 		}
 	}
 	// 3. use uniform distribution assumption for the rest
 	cnt := c.Histogram.notNullCount()
 	for _, bkt := range c.Histogram.Buckets {
 		if cnt <= float64(bkt.Repeat) {
-			return 0, nil
+			return 0, "no rows left by bucket upper bound repeats", nil
 		}
 		cnt -= float64(bkt.Repeat)
 	}
@@ -1025,9 +1041,9 @@ func (c *Column) equalRowCount(sc *stmtctx.StatementContext, val types.Datum, en
 	}
 	ndv := c.Histogram.NDV - topNLen - int64(len(c.Histogram.Buckets))
 	if ndv <= 0 {
-		return 0, nil
+		return 0, "no NDV left by TopN and bucket upper bounds", nil
 	}
-	return cnt / float64(ndv), nil
+	return cnt / float64(ndv), "uniform distribution (not null count - repeats) / (NDV - TopN - buckets)", nil
 }
 
 // GetColumnRowCount estimates the row count by a slice of Range.
@@ -1085,8 +1101,15 @@ func (c *Column) GetColumnRowCount(sc *stmtctx.StatementContext, ranges []*range
 		}
 		// The interval case.
 		cnt := c.BetweenRowCount(sc, lowVal, highVal, lowEncoded, highEncoded)
+		if tracer := statsTracer(sc); tracer != nil {
+			tracer.Record("range", colName(c, c.ID), cnt, "range: %s, histogram between row count of [low, high)", rg.String())
+		}
 		if (c.outOfRange(lowVal, lowEncoded) && !lowVal.IsNull()) || c.outOfRange(highVal, highEncoded) {
-			cnt += outOfRangeEQSelectivity(outOfRangeBetweenRate, modifyCount, int64(c.TotalRowCount())) * c.TotalRowCount()
+			outOfRangeCnt := outOfRangeEQSelectivity(outOfRangeBetweenRate, modifyCount, int64(c.TotalRowCount())) * c.TotalRowCount()
+			if tracer := statsTracer(sc); tracer != nil {
+				tracer.Record("range", colName(c, c.ID), outOfRangeCnt, "range: %s, out of histogram range, modify count: %d", rg.String(), modifyCount)
+			}
+			cnt += outOfRangeCnt
 		}
 		// `betweenRowCount` returns count for [l, h) range, we adjust cnt for boudaries here.
 		// Note that, `cnt` does not include null values, we need specially handle cases
@@ -1138,6 +1161,10 @@ type Index struct {
 	Info           *model.IndexInfo
 	Flag           int64
 	LastAnalyzePos types.Datum
+	PhysicalID     int64
+	// HistUnloaded indicates the histogram buckets of the index are not loaded yet, only its CMSketch and TopN are
+	// loaded. The histogram is loaded by needs.
+	HistUnloaded bool
 }
 
 func (idx *Index) String() string {
@@ -1152,11 +1179,21 @@ func (idx *Index) TotalRowCount() float64 {
 	return idx.Histogram.TotalRowCount()
 }
 
-// IsInvalid checks if this index is invalid.
+// IsInvalid checks if this index is invalid. If the histogram of the index is not loaded yet, then we mark it as
+// need histogram.
 func (idx *Index) IsInvalid(collPseudo bool) bool {
+	if idx.IsLoadNeeded() {
+		HistogramNeededColumns.insert(tableColumnID{TableID: idx.PhysicalID, ColumnID: idx.Info.ID, IsIndex: true})
+		return true
+	}
 	return (collPseudo && idx.NotAccurate()) || idx.TotalRowCount() == 0
 }
 
+// IsLoadNeeded checks whether the index has been analyzed but its histogram is not loaded yet.
+func (idx *Index) IsLoadNeeded() bool {
+	return idx.HistUnloaded
+}
+
 // MemoryUsage returns the total memory usage of a Histogram and CMSketch in Index.
 // We ignore the size of other metadata in Index.
 func (idx *Index) MemoryUsage() (sum int64) {
@@ -1228,6 +1265,10 @@ func (idx *Index) GetRowCount(sc *stmtctx.StatementContext, coll *HistColl, inde
 					continue
 				}
 				count := idx.equalRowCount(lb, modifyCount)
+				if tracer := statsTracer(sc); tracer != nil {
+					tracer.Record("equal", idxName(idx, idx.ID), count, "value: %s, NDV: %d, null count: %d, TopN: %d, buckets: %d",
+						indexRange.String(), idx.NDV, idx.NullCount, idx.TopN.Num(), len(idx.Buckets))
+				}
 				totalCount += count
 				continue
 			}
@@ -1258,11 +1299,19 @@ func (idx *Index) GetRowCount(sc *stmtctx.StatementContext, coll *HistColl, inde
 				return 0, err
 			}
 			if expBackoffSuccess {
+				if tracer := statsTracer(sc); tracer != nil {
+					tracer.Record("range", idxName(idx, idx.ID), expBackoffSel*idx.TotalRowCount(),
+						"range: %s, exponential backoff selectivity of the columns: %.4f * index rows: %.4f", indexRange.String(), expBackoffSel, idx.TotalRowCount())
+				}
 				totalCount += expBackoffSel * idx.TotalRowCount()
 			}
 		}
 		if !expBackoffSuccess {
-			totalCount += idx.BetweenRowCount(l, r)
+			count := idx.BetweenRowCount(l, r)
+			if tracer := statsTracer(sc); tracer != nil {
+				tracer.Record("range", idxName(idx, idx.ID), count, "range: %s, histogram between row count of [low, high)", indexRange.String())
+			}
+			totalCount += count
 		}
 	}
 	if totalCount > idx.TotalRowCount() {
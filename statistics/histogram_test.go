@@ -15,6 +15,7 @@ package statistics
 
 import (
 	"fmt"
+	"math"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/parser/model"
@@ -464,3 +465,40 @@ func (s *testStatisticsSuite) TestMergeBucketNDV(c *C) {
 		c.Assert(t.result.disjointNDV, Equals, res.disjointNDV)
 	}
 }
+
+func (s *testStatisticsSuite) TestEquiJoinRowCount(c *C) {
+	sc := mock.NewContext().GetSessionVars().StmtCtx
+	buildColumn := func(count, repeat int64, topNCount uint64) *Column {
+		col := &Column{StatsVer: Version2}
+		col.Histogram = *NewHistogram(1, 100, 0, 0, types.NewFieldType(mysql.TypeLonglong), chunk.InitialCapacity, 0)
+		lower, upper := types.NewIntDatum(1), types.NewIntDatum(100)
+		col.AppendBucketWithNDV(&lower, &upper, count, repeat, 100)
+		col.TopN = NewTopN(1)
+		if topNCount > 0 {
+			encoded, err := codec.EncodeKey(sc, nil, types.NewIntDatum(7))
+			c.Assert(err, IsNil)
+			col.TopN.AppendTopN(encoded, topNCount)
+		}
+		return col
+	}
+	// 1~100 have 1 row each on the left and 10 rows each on the right, and 7 has 1000 more rows on both sides.
+	left := buildColumn(100, 1, 1000)
+	right := buildColumn(1000, 10, 1000)
+	count, ok := EquiJoinRowCount(sc, left, right)
+	c.Assert(ok, IsTrue)
+	// 7: 1001 * 1010, the others: 99 * 1 * 10.
+	c.Assert(math.Abs(count-(1001*1010+99*10)) < 1e-6, IsTrue)
+
+	// Without the skewed value, it's the same as the NDV based estimation.
+	left = buildColumn(100, 1, 0)
+	right = buildColumn(1000, 10, 0)
+	count, ok = EquiJoinRowCount(sc, left, right)
+	c.Assert(ok, IsTrue)
+	c.Assert(math.Abs(count-100*1000/100) < 1e-6, IsTrue)
+
+	// The histograms of different types can't be aligned.
+	decimalCol := &Column{}
+	decimalCol.Histogram = *NewHistogram(2, 1, 0, 0, types.NewFieldType(mysql.TypeNewDecimal), chunk.InitialCapacity, 0)
+	_, ok = EquiJoinRowCount(sc, left, decimalCol)
+	c.Assert(ok, IsFalse)
+}
@@ -0,0 +1,225 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/codec"
+)
+
+// joinPoint is a single value of a column and its row count.
+type joinPoint struct {
+	value float64
+	count float64
+}
+
+// joinRange is the values in [lower, upper) of a histogram bucket, which are assumed to be distributed uniformly.
+type joinRange struct {
+	lower float64
+	upper float64
+	count float64
+	ndv   float64
+}
+
+// joinDistribution is the value distribution of a column converted to scalars, the points and the ranges are both
+// ordered by the values, and the ranges don't overlap.
+type joinDistribution struct {
+	points []joinPoint
+	ranges []joinRange
+}
+
+// EquiJoinRowCount estimates the row count of the equi-join on two columns by aligning their histograms and TopN
+// bucket by bucket, which is much more accurate than dividing by the larger NDV when the values are skewed.
+// The result is the row count of joining all the rows of the two columns, so the caller should scale it by the
+// selectivity on each side. It returns false if the two columns can't be aligned, e.g. the types differ.
+func EquiJoinRowCount(sc *stmtctx.StatementContext, left, right *Column) (float64, bool) {
+	if !canAlignColumns(left, right) {
+		return 0, false
+	}
+	leftDist, ok := buildJoinDistribution(sc, left)
+	if !ok {
+		return 0, false
+	}
+	rightDist, ok := buildJoinDistribution(sc, right)
+	if !ok {
+		return 0, false
+	}
+	count := 0.0
+	// The points of the left side match the points and the ranges of the right side.
+	for _, p := range leftDist.points {
+		count += p.count * (rightDist.pointCount(p.value) + rightDist.frequency(p.value))
+	}
+	// The points of the right side match the ranges of the left side.
+	for _, p := range rightDist.points {
+		count += p.count * leftDist.frequency(p.value)
+	}
+	// The overlapped part of two ranges is joined by the larger NDV of them.
+	i, j := 0, 0
+	for i < len(leftDist.ranges) && j < len(rightDist.ranges) {
+		l, r := &leftDist.ranges[i], &rightDist.ranges[j]
+		lower, upper := math.Max(l.lower, r.lower), math.Min(l.upper, r.upper)
+		if upper > lower {
+			leftFrac := (upper - lower) / (l.upper - l.lower)
+			rightFrac := (upper - lower) / (r.upper - r.lower)
+			ndv := math.Max(math.Max(l.ndv*leftFrac, r.ndv*rightFrac), 1)
+			count += l.count * leftFrac * r.count * rightFrac / ndv
+		}
+		if l.upper < r.upper {
+			i++
+		} else {
+			j++
+		}
+	}
+	if tracer := statsTracer(sc); tracer != nil {
+		tracer.Record("join", "histograms", count, "%s: buckets: %d, TopN: %d, %s: buckets: %d, TopN: %d",
+			colName(left, left.ID), left.Histogram.Len(), left.TopN.Num(), colName(right, right.ID), right.Histogram.Len(), right.TopN.Num())
+	}
+	return count, true
+}
+
+// canAlignColumns checks whether the values of the two columns can be converted to the same scalar domain.
+func canAlignColumns(left, right *Column) bool {
+	if left == nil || right == nil || left.Histogram.Tp == nil || right.Histogram.Tp == nil {
+		return false
+	}
+	leftTp, rightTp := left.Histogram.Tp, right.Histogram.Tp
+	switch leftTp.EvalType() {
+	case types.ETInt, types.ETReal, types.ETDecimal:
+		return rightTp.EvalType() == leftTp.EvalType()
+	case types.ETDatetime, types.ETDuration:
+		// The scalars of the DATE, DATETIME and TIMESTAMP values start from different minimum values.
+		return rightTp.Tp == leftTp.Tp
+	}
+	return false
+}
+
+func buildJoinDistribution(sc *stmtctx.StatementContext, c *Column) (*joinDistribution, bool) {
+	hg := &c.Histogram
+	dist := &joinDistribution{
+		points: make([]joinPoint, 0, hg.Len()),
+		ranges: make([]joinRange, 0, hg.Len()),
+	}
+	notNullCount := hg.notNullCount()
+	for i := 0; i < hg.Len(); i++ {
+		lower, ok := joinScalar(hg.GetLower(i))
+		if !ok {
+			return nil, false
+		}
+		upper, ok := joinScalar(hg.GetUpper(i))
+		if !ok {
+			return nil, false
+		}
+		count, repeat := float64(hg.bucketCount(i)), float64(hg.Buckets[i].Repeat)
+		if upper <= lower {
+			dist.points = append(dist.points, joinPoint{value: upper, count: count})
+			continue
+		}
+		dist.points = append(dist.points, joinPoint{value: upper, count: repeat})
+		if count <= repeat {
+			continue
+		}
+		ndv := float64(hg.Buckets[i].NDV)
+		if ndv <= 0 && notNullCount > 0 {
+			// The NDV of each bucket is only collected since the statistics of version 2.
+			ndv = float64(hg.NDV) * count / notNullCount
+		}
+		// The upper bound is counted as a point.
+		ndv = math.Max(math.Min(ndv-1, count-repeat), 1)
+		dist.ranges = append(dist.ranges, joinRange{lower: lower, upper: upper, count: count - repeat, ndv: ndv})
+	}
+	// The TopN values are excluded from the histograms only since the statistics of version 2.
+	if c.StatsVer >= Version2 && c.TopN != nil {
+		for _, meta := range c.TopN.TopN {
+			var d types.Datum
+			var err error
+			if types.IsTypeTime(hg.Tp.Tp) {
+				// The time values are encoded as integers.
+				_, d, err = codec.DecodeAsDateTime(meta.Encoded, hg.Tp.Tp, sc.TimeZone)
+			} else {
+				_, d, err = codec.DecodeOne(meta.Encoded)
+			}
+			if err != nil {
+				return nil, false
+			}
+			value, ok := joinScalar(&d)
+			if !ok {
+				return nil, false
+			}
+			dist.points = append(dist.points, joinPoint{value: value, count: float64(meta.Count)})
+		}
+	}
+	dist.mergePoints()
+	return dist, true
+}
+
+// joinScalar converts the value to a scalar which keeps the order of the values.
+func joinScalar(d *types.Datum) (float64, bool) {
+	switch d.Kind() {
+	case types.KindInt64:
+		return float64(d.GetInt64()), true
+	case types.KindUint64:
+		return float64(d.GetUint64()), true
+	case types.KindFloat32:
+		return float64(d.GetFloat32()), true
+	case types.KindFloat64:
+		return d.GetFloat64(), true
+	case types.KindMysqlDuration:
+		return float64(d.GetMysqlDuration().Duration), true
+	case types.KindMysqlDecimal, types.KindMysqlTime:
+		return convertDatumToScalar(d, 0), true
+	}
+	return 0, false
+}
+
+// mergePoints sorts the points and merges the points of the same value.
+func (d *joinDistribution) mergePoints() {
+	sort.Slice(d.points, func(i, j int) bool {
+		return d.points[i].value < d.points[j].value
+	})
+	merged := d.points[:0]
+	for _, p := range d.points {
+		if n := len(merged); n > 0 && merged[n-1].value == p.value {
+			merged[n-1].count += p.count
+			continue
+		}
+		merged = append(merged, p)
+	}
+	d.points = merged
+}
+
+// pointCount returns the row count of the point of the value.
+func (d *joinDistribution) pointCount(value float64) float64 {
+	i := sort.Search(len(d.points), func(i int) bool {
+		return d.points[i].value >= value
+	})
+	if i < len(d.points) && d.points[i].value == value {
+		return d.points[i].count
+	}
+	return 0
+}
+
+// frequency returns the average row count of each value in the range which contains the value.
+func (d *joinDistribution) frequency(value float64) float64 {
+	i := sort.Search(len(d.ranges), func(i int) bool {
+		return d.ranges[i].upper > value
+	})
+	if i < len(d.ranges) && d.ranges[i].lower <= value {
+		return d.ranges[i].count / d.ranges[i].ndv
+	}
+	return 0
+}
@@ -180,12 +180,17 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 	}
 	// TODO: If len(exprs) is bigger than 63, we could use bitset structure to replace the int64.
 	// This will simplify some code and speed up if we use this rather than a boolean slice.
+	sc := ctx.GetSessionVars().StmtCtx
+	tracer := statsTracer(sc)
 	if len(exprs) > 63 || (len(coll.Columns) == 0 && len(coll.Indices) == 0) {
-		return pseudoSelectivity(coll, exprs), nil, nil
+		ret := pseudoSelectivity(coll, exprs)
+		if tracer != nil {
+			tracer.Record("selectivity", "", ret, "conditions: %s, pseudo selectivity without statistics", expression.SortedExplainExpressionList(exprs))
+		}
+		return ret, nil, nil
 	}
 	ret := 1.0
 	var nodes []*StatsNode
-	sc := ctx.GetSessionVars().StmtCtx
 
 	remainedExprs := make([]expression.Expression, 0, len(exprs))
 
@@ -199,14 +204,23 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 
 		if colHist := coll.Columns[c.UniqueID]; colHist == nil || colHist.IsInvalid(sc, coll.Pseudo) {
 			ret *= 1.0 / pseudoEqualRate
+			if tracer != nil {
+				tracer.Record("selectivity", c.String(), 1.0/pseudoEqualRate, "condition: %s, pseudo equal rate 1 / %d", expr.ExplainInfo(), pseudoEqualRate)
+			}
 			continue
 		}
 
 		colHist := coll.Columns[c.UniqueID]
 		if colHist.Histogram.NDV > 0 {
 			ret *= 1 / float64(colHist.Histogram.NDV)
+			if tracer != nil {
+				tracer.Record("selectivity", colName(colHist, colHist.ID), 1/float64(colHist.Histogram.NDV), "condition: %s, 1 / NDV: %d", expr.ExplainInfo(), colHist.Histogram.NDV)
+			}
 		} else {
 			ret *= 1.0 / pseudoEqualRate
+			if tracer != nil {
+				tracer.Record("selectivity", colName(colHist, colHist.ID), 1.0/pseudoEqualRate, "condition: %s, pseudo equal rate 1 / %d", expr.ExplainInfo(), pseudoEqualRate)
+			}
 		}
 	}
 
@@ -283,12 +297,20 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 	for _, set := range usedSets {
 		mask &^= set.mask
 		ret *= set.Selectivity
+		if tracer != nil {
+			tracer.Record("selectivity", coll.statsNodeName(set), set.Selectivity, "conditions: %s, row count of the ranges / table rows: %d",
+				maskedExprsString(remainedExprs, set.mask), coll.Count)
+		}
 		// If `partCover` is true, it means that the conditions are in DNF form, and only part
 		// of the DNF expressions are extracted as access conditions, so besides from the selectivity
 		// of the extracted access conditions, we multiply another selectionFactor for the residual
 		// conditions.
 		if set.partCover {
 			ret *= selectionFactor
+			if tracer != nil {
+				tracer.Record("selectivity", coll.statsNodeName(set), selectionFactor, "conditions: %s, selection factor of the residual DNF items",
+					maskedExprsString(remainedExprs, set.mask))
+			}
 		}
 	}
 
@@ -349,6 +371,9 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 			if selectivity != 0 {
 				ret *= selectivity
 				mask &^= 1 << uint64(i)
+				if tracer != nil {
+					tracer.Record("selectivity", "", selectivity, "condition: %s, sel(a or b) = sel(a) + sel(b) - sel(a) * sel(b)", expr.ExplainInfo())
+				}
 			}
 		}
 	}
@@ -356,10 +381,36 @@ func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Exp
 	// If there's still conditions which cannot be calculated, we will multiply a selectionFactor.
 	if mask > 0 {
 		ret *= selectionFactor
+		if tracer != nil {
+			tracer.Record("selectivity", "", selectionFactor, "conditions: %s, selection factor of the conditions not covered by the statistics",
+				maskedExprsString(remainedExprs, mask))
+		}
+	}
+	if tracer != nil {
+		tracer.Record("selectivity", "", ret, "conditions: %s, product of the selectivities", expression.SortedExplainExpressionList(exprs))
 	}
 	return ret, nodes, nil
 }
 
+// statsNodeName returns the name of the column or index of the StatsNode for the trace.
+func (coll *HistColl) statsNodeName(node *StatsNode) string {
+	if node.Tp == IndexType {
+		return idxName(coll.Indices[node.ID], node.ID)
+	}
+	return colName(coll.Columns[node.ID], node.ID)
+}
+
+// maskedExprsString formats the expressions selected by the mask for the trace.
+func maskedExprsString(exprs []expression.Expression, mask int64) string {
+	selected := make([]expression.Expression, 0, len(exprs))
+	for i, expr := range exprs {
+		if mask&(1<<uint64(i)) != 0 {
+			selected = append(selected, expr)
+		}
+	}
+	return string(expression.SortedExplainExpressionList(selected))
+}
+
 func getMaskAndRanges(ctx sessionctx.Context, exprs []expression.Expression, rangeType ranger.RangeType, lengths []int, cachedPath *planutil.AccessPath, cols ...*expression.Column) (mask int64, ranges []*ranger.Range, partCover bool, err error) {
 	sc := ctx.GetSessionVars().StmtCtx
 	isDNF := false
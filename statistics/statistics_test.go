@@ -370,6 +370,41 @@ func (s *testStatisticsSuite) TestBuild(c *C) {
 	c.Assert(col.GetLower(0), DeepEquals, col.GetUpper(0))
 }
 
+func (s *testStatisticsSuite) TestAdaptiveBucketCount(c *C) {
+	sc := &stmtctx.StatementContext{TimeZone: time.Local}
+	newSamples := func(values ...int64) []*SampleItem {
+		samples := make([]*SampleItem, 0, len(values))
+		for _, v := range values {
+			samples = append(samples, &SampleItem{Value: types.NewIntDatum(v)})
+		}
+		return samples
+	}
+	uniform := make([]int64, 0, 1000)
+	skewed := make([]int64, 0, 1000)
+	for i := int64(0); i < 1000; i++ {
+		uniform = append(uniform, i)
+		if i%10 == 0 {
+			skewed = append(skewed, i)
+		} else {
+			skewed = append(skewed, 0)
+		}
+	}
+
+	num, err := AdaptiveBucketCount(sc, nil, 256)
+	c.Assert(err, IsNil)
+	c.Assert(num, Equals, 256)
+	num, err = AdaptiveBucketCount(sc, newSamples(1, 1, 1), 256)
+	c.Assert(err, IsNil)
+	c.Assert(num, Equals, 256)
+	num, err = AdaptiveBucketCount(sc, newSamples(uniform...), 256)
+	c.Assert(err, IsNil)
+	c.Assert(num, Equals, 128)
+	num, err = AdaptiveBucketCount(sc, newSamples(skewed...), 256)
+	c.Assert(err, IsNil)
+	c.Assert(num, Greater, 256)
+	c.Assert(num, LessEqual, 1024)
+}
+
 func (s *testStatisticsSuite) TestHistogramProtoConversion(c *C) {
 	ctx := mock.NewContext()
 	c.Assert(s.rc.Close(), IsNil)
@@ -62,6 +62,9 @@ type Table struct {
 	Version       uint64
 	Name          string
 	ExtendedStats *ExtendedStatsColl
+	// Snapshot is the snapshot timestamp at which the last analyze read the data of the table.
+	// All the columns and indexes of an analyze job are read at this timestamp.
+	Snapshot uint64
 	// TblInfoUpdateTS is the UpdateTS of the TableInfo used when filling this struct.
 	// It is the schema version of the corresponding table. It is used to skip redundant
 	// loading of stats, i.e, if the cached stats is already update-to-date with mysql.stats_xxx tables,
@@ -145,6 +148,7 @@ func (t *Table) Copy() *Table {
 		HistColl:        newHistColl,
 		Version:         t.Version,
 		Name:            t.Name,
+		Snapshot:        t.Snapshot,
 		TblInfoUpdateTS: t.TblInfoUpdateTS,
 	}
 	if t.ExtendedStats != nil {
@@ -217,6 +221,8 @@ func (t *Table) GetStatsInfo(ID int64, isIndex bool) (int64, *Histogram, *CMSket
 type tableColumnID struct {
 	TableID  int64
 	ColumnID int64
+	// IsIndex indicates the ColumnID is the ID of an index.
+	IsIndex bool
 }
 
 type neededColumnMap struct {
@@ -319,12 +325,17 @@ func (coll *HistColl) GetRowCountByIntColumnRanges(sc *stmtctx.StatementContext,
 		if len(intRanges) == 0 {
 			return 0, nil
 		}
+		var result float64
 		if intRanges[0].LowVal[0].Kind() == types.KindInt64 {
-			return getPseudoRowCountBySignedIntRanges(intRanges, float64(coll.Count)), nil
+			result = getPseudoRowCountBySignedIntRanges(intRanges, float64(coll.Count))
+		} else {
+			result = getPseudoRowCountByUnsignedIntRanges(intRanges, float64(coll.Count))
 		}
-		return getPseudoRowCountByUnsignedIntRanges(intRanges, float64(coll.Count)), nil
+		coll.tracePseudoRanges(sc, colName(c, colID), intRanges, result)
+		return result, nil
 	}
 	result, err := c.GetColumnRowCount(sc, intRanges, coll.ModifyCount, true)
+	coll.traceRanges(sc, colName(c, colID), intRanges, result, c.GetIncreaseFactor(coll.Count))
 	result *= c.GetIncreaseFactor(coll.Count)
 	return result, errors.Trace(err)
 }
@@ -333,9 +344,14 @@ func (coll *HistColl) GetRowCountByIntColumnRanges(sc *stmtctx.StatementContext,
 func (coll *HistColl) GetRowCountByColumnRanges(sc *stmtctx.StatementContext, colID int64, colRanges []*ranger.Range) (float64, error) {
 	c, ok := coll.Columns[colID]
 	if !ok || c.IsInvalid(sc, coll.Pseudo) {
-		return GetPseudoRowCountByColumnRanges(sc, float64(coll.Count), colRanges, 0)
+		result, err := GetPseudoRowCountByColumnRanges(sc, float64(coll.Count), colRanges, 0)
+		if err == nil {
+			coll.tracePseudoRanges(sc, colName(c, colID), colRanges, result)
+		}
+		return result, err
 	}
 	result, err := c.GetColumnRowCount(sc, colRanges, coll.ModifyCount, false)
+	coll.traceRanges(sc, colName(c, colID), colRanges, result, c.GetIncreaseFactor(coll.Count))
 	result *= c.GetIncreaseFactor(coll.Count)
 	return result, errors.Trace(err)
 }
@@ -348,7 +364,11 @@ func (coll *HistColl) GetRowCountByIndexRanges(sc *stmtctx.StatementContext, idx
 		if idx != nil && idx.Info.Unique {
 			colsLen = len(idx.Info.Columns)
 		}
-		return getPseudoRowCountByIndexRanges(sc, indexRanges, float64(coll.Count), colsLen)
+		result, err := getPseudoRowCountByIndexRanges(sc, indexRanges, float64(coll.Count), colsLen)
+		if err == nil {
+			coll.tracePseudoRanges(sc, idxName(idx, idxID), indexRanges, result)
+		}
+		return result, err
 	}
 	var result float64
 	var err error
@@ -357,10 +377,40 @@ func (coll *HistColl) GetRowCountByIndexRanges(sc *stmtctx.StatementContext, idx
 	} else {
 		result, err = idx.GetRowCount(sc, coll, indexRanges, coll.ModifyCount)
 	}
+	coll.traceRanges(sc, idxName(idx, idxID), indexRanges, result, idx.GetIncreaseFactor(coll.Count))
 	result *= idx.GetIncreaseFactor(coll.Count)
 	return result, errors.Trace(err)
 }
 
+// traceRanges records the row count of the ranges estimated by the statistics.
+func (coll *HistColl) traceRanges(sc *stmtctx.StatementContext, object string, ranges []*ranger.Range, count, increaseFactor float64) {
+	if tracer := statsTracer(sc); tracer != nil {
+		tracer.Record("ranges", object, count*increaseFactor, "ranges: %s, row count: %.4f * increase factor: %.4f (table rows: %d, modify count: %d)",
+			rangesString(ranges), count, increaseFactor, coll.Count, coll.ModifyCount)
+	}
+}
+
+// tracePseudoRanges records the row count of the ranges estimated without statistics.
+func (coll *HistColl) tracePseudoRanges(sc *stmtctx.StatementContext, object string, ranges []*ranger.Range, count float64) {
+	if tracer := statsTracer(sc); tracer != nil {
+		tracer.Record("ranges", object, count, "ranges: %s, pseudo estimation on the table rows: %d", rangesString(ranges), coll.Count)
+	}
+}
+
+func colName(c *Column, colID int64) string {
+	if c == nil || c.Info == nil {
+		return fmt.Sprintf("column#%d", colID)
+	}
+	return c.Info.Name.O
+}
+
+func idxName(idx *Index, idxID int64) string {
+	if idx == nil || idx.Info == nil {
+		return fmt.Sprintf("index#%d", idxID)
+	}
+	return idx.Info.Name.O
+}
+
 // PseudoAvgCountPerValue gets a pseudo average count if histogram not exists.
 func (t *Table) PseudoAvgCountPerValue() float64 {
 	return float64(t.Count) / pseudoEqualRate
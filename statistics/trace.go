@@ -0,0 +1,57 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"strings"
+
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/ranger"
+	"github.com/pingcap/tidb/util/tracing"
+)
+
+// statsTracer returns the tracer of the cardinality estimation, it's nil if the statement isn't traced.
+func statsTracer(sc *stmtctx.StatementContext) *tracing.StatsTracer {
+	if sc == nil {
+		return nil
+	}
+	return sc.StatsTrace
+}
+
+// datumString formats the value for the trace.
+func datumString(d types.Datum) string {
+	switch d.Kind() {
+	case types.KindNull:
+		return "NULL"
+	case types.KindMinNotNull:
+		return "-inf"
+	case types.KindMaxValue:
+		return "+inf"
+	}
+	str, err := d.ToString()
+	if err != nil {
+		return d.String()
+	}
+	return str
+}
+
+// rangesString formats the ranges for the trace.
+func rangesString(ranges []*ranger.Range) string {
+	strs := make([]string, 0, len(ranges))
+	for _, ran := range ranges {
+		strs = append(strs, ran.String())
+	}
+	return strings.Join(strs, ", ")
+}
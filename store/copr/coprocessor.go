@@ -721,7 +721,8 @@ func (worker *copIteratorWorker) handleTaskOnce(bo *Backoffer, task *copTask, ch
 	if len(worker.req.MatchStoreLabels) > 0 {
 		ops = append(ops, tikv.WithMatchLabels(worker.req.MatchStoreLabels))
 	}
-	resp, rpcCtx, storeAddr, err := worker.kvclient.SendReqCtx(bo.TiKVBackoffer(), req, task.region, tikv.ReadTimeoutMedium, getEndPointType(task.storeType), task.storeAddr, ops...)
+	timeout := worker.store.copTimeout.Timeout(worker.targetStoreAddr(bo, task, ops), task.cmdType)
+	resp, rpcCtx, storeAddr, err := worker.kvclient.SendReqCtx(bo.TiKVBackoffer(), req, task.region, timeout, getEndPointType(task.storeType), task.storeAddr, ops...)
 	err = derr.ToTiDBErr(err)
 	if err != nil {
 		if task.storeType == kv.TiDB {
@@ -738,6 +739,7 @@ func (worker *copIteratorWorker) handleTaskOnce(bo *Backoffer, task *copTask, ch
 		worker.logTimeCopTask(costTime, task, bo, resp)
 	}
 	metrics.TiKVCoprocessorHistogram.Observe(costTime.Seconds())
+	worker.store.copTimeout.Observe(storeAddr, task.cmdType, costTime)
 
 	if task.cmdType == tikvrpc.CmdCopStream {
 		return worker.handleCopStreamResult(bo, rpcCtx, resp.Resp.(*tikvrpc.CopStreamResponse), task, ch, costTime)
@@ -752,6 +754,30 @@ const (
 	minLogKVProcessTime = 100
 )
 
+// targetStoreAddr returns the address of the store which the task is going to be sent to, it is looked up
+// from the region cache when the task is not bound to a store. An empty address is returned if the
+// region is not cached, then the request is sent with the default timeout.
+func (worker *copIteratorWorker) targetStoreAddr(bo *Backoffer, task *copTask, ops []tikv.StoreSelectorOption) string {
+	if task.storeAddr != "" {
+		return task.storeAddr
+	}
+	var (
+		rpcCtx *tikv.RPCContext
+		err    error
+	)
+	cache := worker.store.GetRegionCache()
+	switch task.storeType {
+	case kv.TiKV:
+		rpcCtx, err = cache.GetTiKVRPCContext(bo.TiKVBackoffer(), task.region, options.GetTiKVReplicaReadType(worker.req.ReplicaRead), worker.replicaReadSeed, ops...)
+	case kv.TiFlash:
+		rpcCtx, err = cache.GetTiFlashRPCContext(bo.TiKVBackoffer(), task.region, false)
+	}
+	if err != nil || rpcCtx == nil {
+		return ""
+	}
+	return rpcCtx.Addr
+}
+
 func (worker *copIteratorWorker) logTimeCopTask(costTime time.Duration, task *copTask, bo *Backoffer, resp *tikvrpc.Response) {
 	logStr := fmt.Sprintf("[TIME_COP_PROCESS] resp_time:%s txnStartTS:%d region_id:%d store_addr:%s", costTime, worker.req.StartTs, task.region.GetID(), task.storeAddr)
 	if bo.GetTotalSleep() > minLogBackoffTime {
@@ -68,6 +68,7 @@ func (c *tikvClient) SendRequest(ctx context.Context, addr string, req *tikvrpc.
 type Store struct {
 	*kvStore
 	coprCache       *coprCache
+	copTimeout      *adaptiveTimeout
 	replicaReadSeed uint32
 }
 
@@ -80,6 +81,7 @@ func NewStore(s *tikv.KVStore, coprCacheConfig *config.CoprocessorCache) (*Store
 	return &Store{
 		kvStore:         &kvStore{store: s},
 		coprCache:       coprCache,
+		copTimeout:      newAdaptiveTimeout(tikv.ReadTimeoutMedium),
 		replicaReadSeed: rand.Uint32(),
 	}, nil
 }
@@ -0,0 +1,132 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package copr
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tidbmetrics "github.com/pingcap/tidb/metrics"
+	"github.com/tikv/client-go/v2/tikv"
+	"github.com/tikv/client-go/v2/tikvrpc"
+)
+
+const (
+	// copTimeoutFloor and copTimeoutCeiling bound the adaptive timeouts of the coprocessor requests.
+	// The floor is the static timeout used before, so the timeout is only increased when the store is
+	// slow and a request is never retried earlier than it used to be.
+	copTimeoutFloor   = tikv.ReadTimeoutMedium
+	copTimeoutCeiling = 150 * time.Second
+	// copTimeoutFactor is multiplied by the p99 latency to get the timeout, so a request is only
+	// retried when it is much slower than the recent ones.
+	copTimeoutFactor = 5
+	// latencyWindowSize is the number of the recent latencies kept for a store and request type.
+	latencyWindowSize = 256
+	// latencyMinSamples is the number of the latencies needed before the timeout is adapted.
+	latencyMinSamples = 32
+	// latencyRecomputeInterval is the number of the latencies observed between two computations.
+	latencyRecomputeInterval = 16
+)
+
+type timeoutKey struct {
+	storeAddr string
+	cmdType   tikvrpc.CmdType
+}
+
+// latencyWindow keeps the recent latencies of a store and request type in a ring buffer and the
+// timeout computed from them. The timeout is read without holding the lock.
+type latencyWindow struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+	total   int
+	timeout int64 // time.Duration, accessed atomically
+}
+
+func (w *latencyWindow) getTimeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&w.timeout))
+}
+
+func (w *latencyWindow) observe(latency time.Duration) (timeout time.Duration, updated bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.samples) < latencyWindowSize {
+		w.samples = append(w.samples, latency)
+	} else {
+		w.samples[w.next] = latency
+	}
+	w.next = (w.next + 1) % latencyWindowSize
+	w.total++
+	if w.total < latencyMinSamples || w.total%latencyRecomputeInterval != 0 {
+		return 0, false
+	}
+	sorted := make([]time.Duration, len(w.samples))
+	copy(sorted, w.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	p99 := sorted[(len(sorted)*99-1)/100]
+	timeout = p99 * copTimeoutFactor
+	if timeout < copTimeoutFloor {
+		timeout = copTimeoutFloor
+	} else if timeout > copTimeoutCeiling {
+		timeout = copTimeoutCeiling
+	}
+	atomic.StoreInt64(&w.timeout, int64(timeout))
+	return timeout, true
+}
+
+// adaptiveTimeout computes the timeouts of the requests from the recent latencies of each store and
+// request type, instead of using a static timeout which causes premature retries under load.
+// The windows are kept in a sync.Map and locked separately, so the requests sent to different stores
+// do not contend with each other.
+type adaptiveTimeout struct {
+	// windows maps timeoutKey to *latencyWindow.
+	windows sync.Map
+	// defaultTimeout is used before there are enough latencies observed.
+	defaultTimeout time.Duration
+}
+
+func newAdaptiveTimeout(defaultTimeout time.Duration) *adaptiveTimeout {
+	return &adaptiveTimeout{defaultTimeout: defaultTimeout}
+}
+
+// Timeout returns the timeout of a request sent to the store. The default timeout is used if the store
+// address is unknown or there are not enough latencies observed on the store.
+func (t *adaptiveTimeout) Timeout(storeAddr string, cmdType tikvrpc.CmdType) time.Duration {
+	if storeAddr == "" {
+		return t.defaultTimeout
+	}
+	if w, ok := t.windows.Load(timeoutKey{storeAddr, cmdType}); ok {
+		if timeout := w.(*latencyWindow).getTimeout(); timeout > 0 {
+			return timeout
+		}
+	}
+	return t.defaultTimeout
+}
+
+// Observe records the latency of a request which has succeeded on the store.
+func (t *adaptiveTimeout) Observe(storeAddr string, cmdType tikvrpc.CmdType, latency time.Duration) {
+	if storeAddr == "" {
+		return
+	}
+	key := timeoutKey{storeAddr, cmdType}
+	w, ok := t.windows.Load(key)
+	if !ok {
+		w, _ = t.windows.LoadOrStore(key, &latencyWindow{})
+	}
+	if timeout, updated := w.(*latencyWindow).observe(latency); updated {
+		tidbmetrics.DistSQLCopEffectiveTimeoutGauge.WithLabelValues(storeAddr, cmdType.String()).Set(timeout.Seconds())
+	}
+}
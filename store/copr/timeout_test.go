@@ -0,0 +1,62 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package copr
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/tikv/client-go/v2/tikv"
+	"github.com/tikv/client-go/v2/tikvrpc"
+)
+
+func (s *testCoprocessorSuite) TestAdaptiveTimeout(c *C) {
+	t := newAdaptiveTimeout(tikv.ReadTimeoutMedium)
+	c.Assert(t.Timeout("store1", tikvrpc.CmdCop), Equals, tikv.ReadTimeoutMedium)
+
+	// The default timeout is used until there are enough latencies.
+	for i := 0; i < latencyMinSamples-1; i++ {
+		t.Observe("store1", tikvrpc.CmdCop, time.Millisecond)
+	}
+	c.Assert(t.Timeout("store1", tikvrpc.CmdCop), Equals, tikv.ReadTimeoutMedium)
+	t.Observe("store1", tikvrpc.CmdCop, time.Millisecond)
+	// The fast requests are bounded by the floor.
+	c.Assert(t.Timeout("store1", tikvrpc.CmdCop), Equals, copTimeoutFloor)
+	// The unknown store, the other stores and the other request types are not affected.
+	t.Observe("", tikvrpc.CmdCop, time.Minute)
+	c.Assert(t.Timeout("", tikvrpc.CmdCop), Equals, tikv.ReadTimeoutMedium)
+	c.Assert(t.Timeout("store2", tikvrpc.CmdCop), Equals, tikv.ReadTimeoutMedium)
+	c.Assert(t.Timeout("store1", tikvrpc.CmdCopStream), Equals, tikv.ReadTimeoutMedium)
+
+	// The slow requests on a store increase its timeout.
+	for i := 0; i < latencyWindowSize; i++ {
+		t.Observe("store2", tikvrpc.CmdCop, 10*time.Second)
+	}
+	c.Assert(t.Timeout("store2", tikvrpc.CmdCop), Equals, copTimeoutFloor)
+	for i := 0; i < latencyWindowSize; i++ {
+		t.Observe("store2", tikvrpc.CmdCop, 20*time.Second)
+	}
+	c.Assert(t.Timeout("store2", tikvrpc.CmdCop), Equals, 100*time.Second)
+	c.Assert(t.Timeout("store1", tikvrpc.CmdCop), Equals, copTimeoutFloor)
+	for i := 0; i < latencyWindowSize; i++ {
+		t.Observe("store2", tikvrpc.CmdCop, time.Minute)
+	}
+	c.Assert(t.Timeout("store2", tikvrpc.CmdCop), Equals, copTimeoutCeiling)
+
+	// The old latencies are moved out of the window.
+	for i := 0; i < latencyWindowSize; i++ {
+		t.Observe("store2", tikvrpc.CmdCop, time.Millisecond)
+	}
+	c.Assert(t.Timeout("store2", tikvrpc.CmdCop), Equals, copTimeoutFloor)
+}
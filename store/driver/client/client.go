@@ -0,0 +1,44 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/tikv/client-go/v2/tikv"
+	"github.com/tikv/client-go/v2/tikvrpc"
+)
+
+type statsClient struct {
+	tikv.Client
+}
+
+// NewStatsClient wraps a tikv client and records the requests into the
+// execdetails.StmtRPCStats carried by the context of the requests.
+func NewStatsClient(client tikv.Client) tikv.Client {
+	return &statsClient{Client: client}
+}
+
+func (c *statsClient) SendRequest(ctx context.Context, addr string, req *tikvrpc.Request, timeout time.Duration) (*tikvrpc.Response, error) {
+	stats, ok := ctx.Value(execdetails.StmtRPCStatsKey).(*execdetails.StmtRPCStats)
+	if !ok {
+		return c.Client.SendRequest(ctx, addr, req, timeout)
+	}
+	start := time.Now()
+	resp, err := c.Client.SendRequest(ctx, addr, req, timeout)
+	stats.Record(req.Type.String(), time.Since(start))
+	return resp, err
+}
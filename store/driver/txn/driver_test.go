@@ -17,7 +17,9 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/kvproto/pkg/kvrpcpb"
+	"github.com/pingcap/parser/model"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/tablecodec"
 )
 
 func TestT(t *testing.T) {
@@ -66,3 +68,40 @@ func (s *testTXNDriverSuite) TestWriteConflictPrettyFormat(c *C) {
 		kv.TxnRetryableMark
 	c.Assert(newWriteConflictError(conflict).Error(), Equals, expectedStr)
 }
+
+func (s *testTXNDriverSuite) TestWriteConflictWithTableInfo(c *C) {
+	tblInfo := &model.TableInfo{
+		ID:      411,
+		Name:    model.NewCIStr("t"),
+		Indices: []*model.IndexInfo{{ID: 1, Name: model.NewCIStr("idx")}},
+	}
+	find := func(physicalID int64) (string, *model.TableInfo) {
+		if physicalID == tblInfo.ID {
+			return "test", tblInfo
+		}
+		return "", nil
+	}
+	conflict := &kvrpcpb.WriteConflict{
+		StartTs:          399402937522847774,
+		ConflictTs:       399402937719455772,
+		ConflictCommitTs: 399402937719455773,
+		Key:              []byte{116, 128, 0, 0, 0, 0, 0, 1, 155, 95, 105, 128, 0, 0, 0, 0, 0, 0, 1, 1, 82, 87, 48, 49, 0, 0, 0, 0, 251, 1, 55, 54, 56, 50, 50, 49, 49, 48, 255, 57, 0, 0, 0, 0, 0, 0, 0, 248, 1, 0, 0, 0, 0, 0, 0, 0, 0, 247},
+		Primary:          tablecodec.EncodeRowKeyWithHandle(411, kv.IntHandle(1)),
+	}
+	expectedStr := "[kv:9007]Write conflict, " +
+		"txnStartTS=399402937522847774, conflictStartTS=399402937719455772, conflictCommitTS=399402937719455773, " +
+		"key={tableID=411, tableName=test.t, indexID=1, indexName=idx, indexValues={RW01, 768221109, , }} " +
+		"primary={tableID=411, tableName=test.t, handle=1} " +
+		"conflictTxnSQLDigests=[digest1, digest2] " +
+		kv.TxnRetryableMark
+	c.Assert(newWriteConflictErrorWithTableInfo(conflict, find, []string{"digest1", "digest2"}).Error(), Equals, expectedStr)
+
+	// The table which isn't found is printed with the ID only.
+	conflict.Primary = tablecodec.EncodeRowKeyWithHandle(412, kv.IntHandle(1))
+	expectedStr = "[kv:9007]Write conflict, " +
+		"txnStartTS=399402937522847774, conflictStartTS=399402937719455772, conflictCommitTS=399402937719455773, " +
+		"key={tableID=411, tableName=test.t, indexID=1, indexName=idx, indexValues={RW01, 768221109, , }} " +
+		"primary={tableID=412, handle=1} " +
+		kv.TxnRetryableMark
+	c.Assert(newWriteConflictErrorWithTableInfo(conflict, find, nil).Error(), Equals, expectedStr)
+}
@@ -153,23 +153,77 @@ func extractKeyErr(err error) error {
 }
 
 func newWriteConflictError(conflict *kvrpcpb.WriteConflict) error {
+	return newWriteConflictErrorWithTableInfo(conflict, nil, nil)
+}
+
+// tableInfoFinder finds the table info and the database name of the physical table, they are used to decode the
+// keys into the table and index names. A nil table info is returned if the table is unknown.
+type tableInfoFinder func(physicalID int64) (dbName string, tblInfo *model.TableInfo)
+
+// newWriteConflictErrorWithTableInfo generates the write conflict error, the keys are decoded with the table info if
+// it's found, and the digests of the statements of the conflicting transaction are attached if they are known.
+func newWriteConflictErrorWithTableInfo(conflict *kvrpcpb.WriteConflict, find tableInfoFinder, conflictSQLDigests []string) error {
 	if conflict == nil {
 		return kv.ErrWriteConflict
 	}
 	var buf bytes.Buffer
-	prettyWriteKey(&buf, conflict.Key)
+	prettyWriteKeyWithTableInfo(&buf, conflict.Key, find)
 	buf.WriteString(" primary=")
-	prettyWriteKey(&buf, conflict.Primary)
+	prettyWriteKeyWithTableInfo(&buf, conflict.Primary, find)
+	if len(conflictSQLDigests) > 0 {
+		buf.WriteString(" conflictTxnSQLDigests=[")
+		buf.WriteString(strings.Join(conflictSQLDigests, ", "))
+		buf.WriteString("]")
+	}
 	return kv.ErrWriteConflict.FastGenByArgs(conflict.StartTs, conflict.ConflictTs, conflict.ConflictCommitTs, buf.String())
 }
 
 func prettyWriteKey(buf *bytes.Buffer, key []byte) {
+	prettyWriteKeyWithTableInfo(buf, key, nil)
+}
+
+// findTableAndIndexName returns the table name and the index name if the table info is found, otherwise empty
+// strings are returned.
+func findTableAndIndexName(find tableInfoFinder, tableID, indexID int64) (tblName, idxName string) {
+	if find == nil {
+		return "", ""
+	}
+	dbName, tblInfo := find(tableID)
+	if tblInfo == nil {
+		return "", ""
+	}
+	tblName = tblInfo.Name.O
+	if dbName != "" {
+		tblName = dbName + "." + tblName
+	}
+	for _, idxInfo := range tblInfo.Indices {
+		if idxInfo.ID == indexID {
+			idxName = idxInfo.Name.O
+			break
+		}
+	}
+	return tblName, idxName
+}
+
+func prettyWriteKeyWithTableInfo(buf *bytes.Buffer, key []byte, find tableInfoFinder) {
 	tableID, indexID, indexValues, err := tablecodec.DecodeIndexKey(key)
 	if err == nil {
-		_, err1 := fmt.Fprintf(buf, "{tableID=%d, indexID=%d, indexValues={", tableID, indexID)
+		tblName, idxName := findTableAndIndexName(find, tableID, indexID)
+		_, err1 := fmt.Fprintf(buf, "{tableID=%d, ", tableID)
+		if err1 != nil {
+			logutil.BgLogger().Error("error", zap.Error(err1))
+		}
+		if tblName != "" {
+			buf.WriteString("tableName=" + tblName + ", ")
+		}
+		_, err1 = fmt.Fprintf(buf, "indexID=%d, ", indexID)
 		if err1 != nil {
 			logutil.BgLogger().Error("error", zap.Error(err1))
 		}
+		if idxName != "" {
+			buf.WriteString("indexName=" + idxName + ", ")
+		}
+		buf.WriteString("indexValues={")
 		for _, v := range indexValues {
 			_, err2 := fmt.Fprintf(buf, "%s, ", v)
 			if err2 != nil {
@@ -182,7 +236,15 @@ func prettyWriteKey(buf *bytes.Buffer, key []byte) {
 
 	tableID, handle, err := tablecodec.DecodeRecordKey(key)
 	if err == nil {
-		_, err3 := fmt.Fprintf(buf, "{tableID=%d, handle=%d}", tableID, handle)
+		tblName, _ := findTableAndIndexName(find, tableID, 0)
+		_, err3 := fmt.Fprintf(buf, "{tableID=%d, ", tableID)
+		if err3 != nil {
+			logutil.BgLogger().Error("error", zap.Error(err3))
+		}
+		if tblName != "" {
+			buf.WriteString("tableName=" + tblName + ", ")
+		}
+		_, err3 = fmt.Fprintf(buf, "handle=%d}", handle)
 		if err3 != nil {
 			logutil.BgLogger().Error("error", zap.Error(err3))
 		}
@@ -14,26 +14,42 @@
 package txn
 
 import (
+	"bytes"
 	"context"
 	"sync/atomic"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/kvproto/pkg/kvrpcpb"
 	"github.com/pingcap/kvproto/pkg/metapb"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/sessionctx/binloginfo"
 	derr "github.com/pingcap/tidb/store/driver/error"
 	"github.com/pingcap/tidb/store/driver/options"
+	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/util/conflicthistory"
+	"github.com/pingcap/tidb/util/txnsummary"
 	tikverr "github.com/tikv/client-go/v2/error"
 	tikvstore "github.com/tikv/client-go/v2/kv"
 	"github.com/tikv/client-go/v2/tikv"
+	tikvutil "github.com/tikv/client-go/v2/util"
 )
 
 type tikvTxn struct {
 	*tikv.KVTxn
 	idxNameCache map[int64]*model.TableInfo
+	// is is the info schema of the transaction, it's used to decode the keys in the errors.
+	is infoSchema
+}
+
+// infoSchema is the subset of infoschema.InfoSchema to find the tables by the physical IDs.
+type infoSchema interface {
+	TableByID(id int64) (table.Table, bool)
+	SchemaByTable(tableInfo *model.TableInfo) (*model.DBInfo, bool)
+	FindTableByPartitionID(partitionID int64) (table.Table, *model.DBInfo, *model.PartitionDefinition)
 }
 
 // NewTiKVTxn returns a new Transaction.
@@ -44,7 +60,7 @@ func NewTiKVTxn(txn *tikv.KVTxn) kv.Transaction {
 	totalLimit := atomic.LoadUint64(&kv.TxnTotalSizeLimit)
 	txn.GetUnionStore().SetEntrySizeLimit(entryLimit, totalLimit)
 
-	return &tikvTxn{txn, make(map[int64]*model.TableInfo)}
+	return &tikvTxn{KVTxn: txn, idxNameCache: make(map[int64]*model.TableInfo)}
 }
 
 func (txn *tikvTxn) GetTableInfo(id int64) *model.TableInfo {
@@ -64,9 +80,59 @@ func (txn *tikvTxn) LockKeys(ctx context.Context, lockCtx *kv.LockCtx, keysInput
 
 func (txn *tikvTxn) Commit(ctx context.Context) error {
 	err := txn.KVTxn.Commit(ctx)
+	if e, ok := errors.Cause(err).(*tikverr.ErrWriteConflict); ok && e.WriteConflict != nil {
+		connID, _ := ctx.Value(tikvutil.SessionID).(uint64)
+		return txn.newWriteConflictError(e.WriteConflict, connID)
+	}
 	return txn.extractKeyErr(err)
 }
 
+// newWriteConflictError generates the write conflict error met by the commit, the conflicting key is decoded into
+// the table and index names, and the statements of the conflicting transaction are attached if it is committed by
+// this instance recently. The conflict is also recorded in the conflict history for diagnosis.
+func (txn *tikvTxn) newWriteConflictError(conflict *kvrpcpb.WriteConflict, connID uint64) error {
+	var conflictSQLDigests []string
+	if rec := txnsummary.GlobalTxnSummary.FindByStartTS(conflict.ConflictTs); rec != nil {
+		conflictSQLDigests = rec.SQLDigests
+	}
+	var keyInfo bytes.Buffer
+	prettyWriteKeyWithTableInfo(&keyInfo, conflict.Key, txn.findTableInfo)
+	conflicthistory.GlobalConflictHistory.Push(&conflicthistory.ConflictRecord{
+		OccurTime:          time.Now(),
+		StartTS:            conflict.StartTs,
+		ConnectionID:       connID,
+		Key:                conflict.Key,
+		KeyInfo:            keyInfo.String(),
+		ConflictStartTS:    conflict.ConflictTs,
+		ConflictCommitTS:   conflict.ConflictCommitTs,
+		ConflictSQLDigests: conflictSQLDigests,
+	})
+	return newWriteConflictErrorWithTableInfo(conflict, txn.findTableInfo, conflictSQLDigests)
+}
+
+// findTableInfo finds the table info of the physical table, the tables written by the transaction are cached, and
+// the others are found in the info schema of the transaction.
+func (txn *tikvTxn) findTableInfo(physicalID int64) (dbName string, tblInfo *model.TableInfo) {
+	tblInfo = txn.GetTableInfo(physicalID)
+	if txn.is == nil {
+		return "", tblInfo
+	}
+	if tblInfo == nil {
+		if tbl, ok := txn.is.TableByID(physicalID); ok {
+			tblInfo = tbl.Meta()
+		} else if tbl, dbInfo, _ := txn.is.FindTableByPartitionID(physicalID); tbl != nil {
+			return dbInfo.Name.O, tbl.Meta()
+		}
+	}
+	if tblInfo == nil {
+		return "", nil
+	}
+	if dbInfo, ok := txn.is.SchemaByTable(tblInfo); ok {
+		dbName = dbInfo.Name.O
+	}
+	return dbName, tblInfo
+}
+
 // GetSnapshot returns the Snapshot binding to this transaction.
 func (txn *tikvTxn) GetSnapshot() kv.Snapshot {
 	return &tikvSnapshot{txn.KVTxn.GetSnapshot()}
@@ -150,6 +216,7 @@ func (txn *tikvTxn) SetOption(opt int, val interface{}) {
 		txn.KVTxn.GetSnapshot().SetTaskID(val.(uint64))
 	case kv.InfoSchema:
 		txn.SetSchemaVer(val.(tikv.SchemaVer))
+		txn.is, _ = val.(infoSchema)
 	case kv.CollectRuntimeStats:
 		if val == nil {
 			txn.KVTxn.GetSnapshot().SetRuntimeStats(nil)
@@ -16,6 +16,7 @@ package mockstore
 import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/kv"
+	driverclient "github.com/pingcap/tidb/store/driver/client"
 	"github.com/pingcap/tidb/store/mockstore/mockcopr"
 	"github.com/pingcap/tidb/store/mockstore/mockstorage"
 	"github.com/tikv/client-go/v2/mockstore/mocktikv"
@@ -31,7 +32,7 @@ func newMockTikvStore(opt *mockOptions) (kv.Storage, error) {
 	}
 	opt.clusterInspector(cluster)
 
-	kvstore, err := tikv.NewTestTiKVStore(newClientRedirector(client), pdClient, opt.clientHijacker, opt.pdClientHijacker, opt.txnLocalLatches)
+	kvstore, err := tikv.NewTestTiKVStore(driverclient.NewStatsClient(newClientRedirector(client)), pdClient, opt.clientHijacker, opt.pdClientHijacker, opt.txnLocalLatches)
 	if err != nil {
 		return nil, err
 	}
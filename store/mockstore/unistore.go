@@ -16,6 +16,7 @@ package mockstore
 import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/kv"
+	driverclient "github.com/pingcap/tidb/store/driver/client"
 	"github.com/pingcap/tidb/store/mockstore/mockstorage"
 	"github.com/pingcap/tidb/store/mockstore/unistore"
 	"github.com/tikv/client-go/v2/tikv"
@@ -32,7 +33,7 @@ func newUnistore(opts *mockOptions) (kv.Storage, error) {
 		Client: pdClient,
 	}
 
-	kvstore, err := tikv.NewTestTiKVStore(newClientRedirector(client), pdClient, opts.clientHijacker, opts.pdClientHijacker, opts.txnLocalLatches)
+	kvstore, err := tikv.NewTestTiKVStore(driverclient.NewStatsClient(newClientRedirector(client)), pdClient, opts.clientHijacker, opts.pdClientHijacker, opts.txnLocalLatches)
 	if err != nil {
 		return nil, err
 	}
@@ -328,11 +328,13 @@ func HandleMPPDAGReq(dbReader *dbreader.DBReader, req *coprocessor.Request, mppC
 	if err != nil {
 		return &coprocessor.Response{OtherError: err.Error()}
 	}
+	sc := flagsToStatementContext(dagReq.Flags)
+	sc.SQLMode = mysql.SQLMode(dagReq.GetSqlMode())
 	builder := mppExecBuilder{
 		dbReader: dbReader,
 		req:      req,
 		mppCtx:   mppCtx,
-		sc:       flagsToStatementContext(dagReq.Flags),
+		sc:       sc,
 		dagReq:   dagReq,
 	}
 	mppExec, err := builder.buildMPPExecutor(dagReq.RootExecutor)
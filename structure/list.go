@@ -192,6 +192,51 @@ func (t *TxStructure) LSet(key []byte, index int64, value []byte) error {
 	return ErrInvalidListIndex.GenWithStack("invalid list index %d", index)
 }
 
+// LRemoveAt removes and gets the element in the list by its index.
+// The elements after the removed one are moved forward, so it costs O(n).
+func (t *TxStructure) LRemoveAt(key []byte, index int64) ([]byte, error) {
+	if t.readWriter == nil {
+		return nil, ErrWriteOnSnapshot
+	}
+	metaKey := t.encodeListMetaKey(key)
+	meta, err := t.loadListMeta(metaKey)
+	if err != nil || meta.IsEmpty() {
+		return nil, errors.Trace(err)
+	}
+
+	index = adjustIndex(index, meta.LIndex, meta.RIndex)
+	if index < meta.LIndex || index >= meta.RIndex {
+		return nil, ErrInvalidListIndex.GenWithStack("invalid list index %d", index)
+	}
+
+	data, err := t.reader.Get(context.TODO(), t.encodeListDataKey(key, index))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The value may refer to the memory of the transaction buffer, which is reused by the sets below.
+	data = append([]byte(nil), data...)
+	for i := index; i < meta.RIndex-1; i++ {
+		next, err := t.reader.Get(context.TODO(), t.encodeListDataKey(key, i+1))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err = t.readWriter.Set(t.encodeListDataKey(key, i), next); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	meta.RIndex--
+	if err = t.readWriter.Delete(t.encodeListDataKey(key, meta.RIndex)); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !meta.IsEmpty() {
+		err = t.readWriter.Set(metaKey, meta.Value())
+	} else {
+		err = t.readWriter.Delete(metaKey)
+	}
+	return data, errors.Trace(err)
+}
+
 // LClear removes the list of the key.
 func (t *TxStructure) LClear(key []byte) error {
 	if t.readWriter == nil {
@@ -178,6 +178,37 @@ func (s *testTxStructureSuite) TestList(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(l, Equals, int64(0))
 
+	// Test LRemoveAt.
+	err = tx.RPush(key, []byte("1"), []byte("2"), []byte("3"), []byte("4"))
+	c.Assert(err, IsNil)
+
+	value, err = tx.LRemoveAt(key, 1)
+	c.Assert(err, IsNil)
+	c.Assert(value, DeepEquals, []byte("2"))
+
+	values, err = tx.LGetAll(key)
+	c.Assert(err, IsNil)
+	c.Assert(values, DeepEquals, [][]byte{[]byte("4"), []byte("3"), []byte("1")})
+
+	value, err = tx.LRemoveAt(key, -1)
+	c.Assert(err, IsNil)
+	c.Assert(value, DeepEquals, []byte("4"))
+
+	_, err = tx.LRemoveAt(key, 100)
+	c.Assert(err, NotNil)
+
+	value, err = tx.LRemoveAt(key, 0)
+	c.Assert(err, IsNil)
+	c.Assert(value, DeepEquals, []byte("1"))
+
+	value, err = tx.LRemoveAt(key, 0)
+	c.Assert(err, IsNil)
+	c.Assert(value, DeepEquals, []byte("3"))
+
+	l, err = tx.LLen(key)
+	c.Assert(err, IsNil)
+	c.Assert(l, Equals, int64(0))
+
 	err = tx.LPush(key, []byte("1"))
 	c.Assert(err, IsNil)
 
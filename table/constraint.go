@@ -0,0 +1,63 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// Constraint provides meta data and the expression of a check constraint.
+type Constraint struct {
+	*model.ConstraintInfo
+	// ConstraintExpr is built on the columns of the table, whose indices are the column offsets.
+	ConstraintExpr expression.Expression
+}
+
+// CheckConstraintTable is implemented by the tables that can have check constraints.
+type CheckConstraintTable interface {
+	// WritableConstraints returns the enforced check constraints that the written rows should satisfy, the
+	// expressions are built with ctx.
+	WritableConstraints(ctx sessionctx.Context) ([]*Constraint, error)
+}
+
+// CheckRowConstraints checks whether the row satisfies the check constraints. As in MySQL, a constraint is only
+// violated when its expression is evaluated to false, NULL is fine.
+func CheckRowConstraints(ctx sessionctx.Context, constraints []*Constraint, row []types.Datum) error {
+	if len(constraints) == 0 {
+		return nil
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	r := chunk.MutRowFromDatums(row).ToRow()
+	for _, constraint := range constraints {
+		val, err := constraint.ConstraintExpr.Eval(r)
+		if err != nil {
+			return err
+		}
+		if val.IsNull() {
+			continue
+		}
+		ok, err := val.ToBool(sc)
+		if err != nil {
+			return err
+		}
+		if ok == 0 {
+			return ErrCheckConstraintViolated.GenWithStackByArgs(constraint.Name.O)
+		}
+	}
+	return nil
+}
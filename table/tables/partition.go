@@ -80,6 +80,13 @@ type partitionedTable struct {
 	partitions      map[int64]*partition
 	evalBufferTypes []*types.FieldType
 	evalBufferPool  sync.Pool
+
+	// reorganizedTable is the table seen from the other side of an in-progress
+	// ALTER TABLE ... REORGANIZE PARTITION, the writes located in its reorganizing
+	// partitions are also applied to it to keep both sides consistent.
+	reorganizedTable  *partitionedTable
+	reorganizingParts map[int64]struct{}
+	reorganizingState model.SchemaState
 }
 
 func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Table, error) {
@@ -109,9 +116,80 @@ func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Tabl
 		partitions[p.ID] = &t
 	}
 	ret.partitions = partitions
+	if reorgInfo, pids := GetReorganizedTableInfo(tblInfo); reorgInfo != nil {
+		reorgTbl := *tbl
+		reorgTbl.meta = reorgInfo
+		t, err := newPartitionedTable(&reorgTbl, reorgInfo)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ret.reorganizedTable = t.(*partitionedTable)
+		ret.reorganizingParts = make(map[int64]struct{}, len(pids))
+		for _, pid := range pids {
+			ret.reorganizingParts[pid] = struct{}{}
+		}
+		ret.reorganizingState = pi.GetStateByID(pids[0])
+	}
 	return ret, nil
 }
 
+// GetReorganizedTableInfo returns the table info seen from the other side of an in-progress
+// ALTER TABLE ... REORGANIZE PARTITION, in which the partitions being replaced are swapped
+// with the reorganizing ones, and the IDs of the reorganizing partitions.
+// The reorganizing partitions are the ones with a state in the partition info, they are kept
+// in AddingDefinitions before the data is reorganized and in DroppingDefinitions after that.
+// It returns nil if no partition is being reorganized.
+func GetReorganizedTableInfo(tblInfo *model.TableInfo) (*model.TableInfo, []int64) {
+	pi := tblInfo.GetPartitionInfo()
+	if pi == nil || len(pi.States) == 0 {
+		return nil, nil
+	}
+	reorgDefs, replacedDefs := pi.AddingDefinitions, pi.DroppingDefinitions
+	if len(reorgDefs) == 0 || !hasPartitionState(pi, reorgDefs[0].ID) {
+		reorgDefs, replacedDefs = replacedDefs, reorgDefs
+	}
+	if len(reorgDefs) == 0 || len(replacedDefs) == 0 || !hasPartitionState(pi, reorgDefs[0].ID) {
+		return nil, nil
+	}
+	replaced := make(map[int64]struct{}, len(replacedDefs))
+	for _, def := range replacedDefs {
+		replaced[def.ID] = struct{}{}
+	}
+	// The partitions being replaced are consecutive, the reorganizing ones take their place.
+	defs := make([]model.PartitionDefinition, 0, len(pi.Definitions)+len(reorgDefs))
+	spliced := false
+	for _, def := range pi.Definitions {
+		if _, ok := replaced[def.ID]; !ok {
+			defs = append(defs, def)
+		} else if !spliced {
+			defs = append(defs, reorgDefs...)
+			spliced = true
+		}
+	}
+	pids := make([]int64, 0, len(reorgDefs))
+	for _, def := range reorgDefs {
+		pids = append(pids, def.ID)
+	}
+
+	nt := tblInfo.Clone()
+	np := *pi
+	np.Definitions = defs
+	np.AddingDefinitions = nil
+	np.DroppingDefinitions = nil
+	np.States = nil
+	nt.Partition = &np
+	return nt, pids
+}
+
+func hasPartitionState(pi *model.PartitionInfo, pid int64) bool {
+	for _, state := range pi.States {
+		if state.ID == pid {
+			return true
+		}
+	}
+	return false
+}
+
 func newPartitionExpr(tblInfo *model.TableInfo) (*PartitionExpr, error) {
 	ctx := mock.NewContext()
 	dbName := model.NewCIStr(ctx.GetSessionVars().CurrentDB)
@@ -1114,7 +1192,60 @@ func partitionedTableAddRecord(ctx sessionctx.Context, t *partitionedTable, r []
 		}
 	}
 	tbl := t.GetPartition(pid)
-	return tbl.AddRecord(ctx, r, opts...)
+	recordID, err = tbl.AddRecord(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err = t.addReorganizingRecord(ctx, recordID, r, opts); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return recordID, nil
+}
+
+// locateReorganizingPartition returns the reorganizing partition in which the row is located,
+// it returns nil if no partition is being reorganized or the row is out of the reorganized range.
+func (t *partitionedTable) locateReorganizingPartition(ctx sessionctx.Context, r []types.Datum) (*partition, error) {
+	if t.reorganizedTable == nil {
+		return nil, nil
+	}
+	pid, err := t.reorganizedTable.locatePartition(ctx, t.reorganizedTable.meta.GetPartitionInfo(), r)
+	if err != nil {
+		// The range of the last partition may be extended by the reorganization.
+		if table.ErrNoPartitionForGivenValue.Equal(err) {
+			return nil, nil
+		}
+		return nil, errors.Trace(err)
+	}
+	if _, ok := t.reorganizingParts[pid]; !ok {
+		return nil, nil
+	}
+	return t.reorganizedTable.partitions[pid], nil
+}
+
+// addReorganizingRecord writes the added row to the reorganizing partition with the same handle.
+func (t *partitionedTable) addReorganizingRecord(ctx sessionctx.Context, h kv.Handle, r []types.Datum, opts []table.AddRecordOption) error {
+	if t.reorganizingState == model.StateDeleteOnly {
+		return nil
+	}
+	p, err := t.locateReorganizingPartition(ctx, r)
+	if err != nil || p == nil {
+		return err
+	}
+	if !t.meta.PKIsHandle && !t.meta.IsCommonHandle {
+		// The last datum is used as the _tidb_rowid.
+		r = append(r[:len(r):len(r)], types.NewIntDatum(h.IntValue()))
+	}
+	_, err = p.AddRecord(ctx, r, opts...)
+	return err
+}
+
+// removeReorganizingRecord removes the row from the reorganizing partition.
+func (t *partitionedTable) removeReorganizingRecord(ctx sessionctx.Context, h kv.Handle, r []types.Datum) error {
+	p, err := t.locateReorganizingPartition(ctx, r)
+	if err != nil || p == nil {
+		return err
+	}
+	return p.RemoveRecord(ctx, h, r)
 }
 
 // partitionTableWithGivenSets is used for this kind of grammar: partition (p0,p1)
@@ -1158,7 +1289,11 @@ func (t *partitionedTable) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r [
 	}
 
 	tbl := t.GetPartition(pid)
-	return tbl.RemoveRecord(ctx, h, r)
+	err = tbl.RemoveRecord(ctx, h, r)
+	if err != nil {
+		return err
+	}
+	return t.removeReorganizingRecord(ctx, h, r)
 }
 
 func (t *partitionedTable) GetAllPartitionIDs() []int64 {
@@ -1202,8 +1337,9 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context,
 
 	// The old and new data locate in different partitions.
 	// Remove record from old partition and add record to new partition.
+	newHandle := h
 	if from != to {
-		_, err = t.GetPartition(to).AddRecord(ctx, newData)
+		newHandle, err = t.GetPartition(to).AddRecord(ctx, newData)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -1218,11 +1354,22 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context,
 			logutil.BgLogger().Error("update partition record fails", zap.String("message", "new record inserted while old record is not removed"), zap.Error(err))
 			return errors.Trace(err)
 		}
-		return nil
+	} else {
+		tbl := t.GetPartition(to)
+		if err = tbl.UpdateRecord(gctx, ctx, h, currData, newData, touched); err != nil {
+			return err
+		}
 	}
 
-	tbl := t.GetPartition(to)
-	return tbl.UpdateRecord(gctx, ctx, h, currData, newData, touched)
+	if t.reorganizedTable == nil {
+		return nil
+	}
+	// The row may not be copied to the reorganizing partition yet, so it is rewritten
+	// as a whole rather than updating the touched columns only.
+	if err = t.removeReorganizingRecord(ctx, h, currData); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(t.addReorganizingRecord(ctx, newHandle, newData, nil))
 }
 
 // FindPartitionByName finds partition in table meta by name.
@@ -29,6 +29,7 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/meta/autoid"
@@ -41,6 +42,7 @@ import (
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/generatedexpr"
@@ -67,6 +69,8 @@ type TableCommon struct {
 	meta                            *model.TableInfo
 	allocs                          autoid.Allocators
 	sequence                        *sequenceCommon
+	// writableConstraints are the enforced check constraints in the write only or public state.
+	writableConstraints []*model.ConstraintInfo
 
 	// recordPrefix and indexPrefix are generated using physicalTableID.
 	recordPrefix kv.Key
@@ -140,6 +144,7 @@ func TableFromMeta(allocs autoid.Allocators, tblInfo *model.TableInfo) (table.Ta
 
 	var t TableCommon
 	initTableCommon(&t, tblInfo, tblInfo.ID, columns, allocs)
+	initTableConstraints(&t)
 	if tblInfo.GetPartitionInfo() == nil {
 		if err := initTableIndices(&t); err != nil {
 			return nil, err
@@ -150,6 +155,17 @@ func TableFromMeta(allocs autoid.Allocators, tblInfo *model.TableInfo) (table.Ta
 	return newPartitionedTable(&t, tblInfo)
 }
 
+// initTableConstraints collects the writable check constraints of the TableCommon.
+func initTableConstraints(t *TableCommon) {
+	for _, constraintInfo := range t.meta.Constraints {
+		if !constraintInfo.Enforced ||
+			(constraintInfo.State != model.StateWriteOnly && constraintInfo.State != model.StatePublic) {
+			continue
+		}
+		t.writableConstraints = append(t.writableConstraints, constraintInfo)
+	}
+}
+
 // initTableCommon initializes a TableCommon struct.
 func initTableCommon(t *TableCommon, tblInfo *model.TableInfo, physicalTableID int64, cols []*table.Column, allocs autoid.Allocators) {
 	t.tableID = tblInfo.ID
@@ -270,6 +286,33 @@ func (t *TableCommon) HiddenCols() []*table.Column {
 	return t.getCols(hidden)
 }
 
+// WritableConstraints implements table.CheckConstraintTable WritableConstraints interface.
+// The expressions are built with the session context so they follow its sql_mode and time zone, and they're
+// cached in the statement context to be built once per statement.
+func (t *TableCommon) WritableConstraints(ctx sessionctx.Context) ([]*table.Constraint, error) {
+	if len(t.writableConstraints) == 0 {
+		return nil, nil
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	cache := sc.GetOrStoreStmtCache(stmtctx.StmtCheckConstraintsCacheKey, make(map[int64][]*table.Constraint)).(map[int64][]*table.Constraint)
+	if constraints, ok := cache[t.tableID]; ok {
+		return constraints, nil
+	}
+	constraints := make([]*table.Constraint, 0, len(t.writableConstraints))
+	for _, constraintInfo := range t.writableConstraints {
+		expr, err := expression.ParseSimpleExprWithTableInfo(ctx, constraintInfo.ExprString, t.meta)
+		if err != nil {
+			return nil, err
+		}
+		constraints = append(constraints, &table.Constraint{
+			ConstraintInfo: constraintInfo,
+			ConstraintExpr: expr,
+		})
+	}
+	cache[t.tableID] = constraints
+	return constraints, nil
+}
+
 // WritableCols implements table WritableCols interface.
 func (t *TableCommon) WritableCols() []*table.Column {
 	if len(t.WritableColumns) > 0 {
@@ -372,6 +415,12 @@ func (t *TableCommon) UpdateRecord(ctx context.Context, sctx sessionctx.Context,
 				}
 				newData[col.Offset] = value
 				touched[col.Offset] = touched[col.DependencyColumnOffset]
+			} else if isAddingStoredGeneratedColumn(col) {
+				value, err = t.evalAddingStoredGeneratedColumn(sctx, col, newData)
+				if err != nil {
+					return err
+				}
+				newData[col.Offset] = value
 			}
 		} else {
 			value = newData[col.Offset]
@@ -730,7 +779,17 @@ func (t *TableCommon) AddRecord(sctx sessionctx.Context, r []types.Datum, opts .
 			colIDs = append(colIDs, col.ID)
 			continue
 		}
-		if col.State != model.StatePublic &&
+		if isAddingStoredGeneratedColumn(col) {
+			value, err = t.evalAddingStoredGeneratedColumn(sctx, col, r)
+			if err != nil {
+				return nil, err
+			}
+			if col.Offset < len(r) {
+				r[col.Offset] = value
+			} else {
+				r = append(r, value)
+			}
+		} else if col.State != model.StatePublic &&
 			// Update call `AddRecord` will already handle the write only column default value.
 			// Only insert should add default value for write only column.
 			!opt.IsUpdate {
@@ -1023,6 +1082,26 @@ func GetChangingColVal(ctx sessionctx.Context, cols []*table.Column, col *table.
 	return idxColumnVal, true, nil
 }
 
+// isAddingStoredGeneratedColumn checks whether the column is a stored generated column being added. The statements
+// can't see it, so its value is evaluated from the other columns when a row is written.
+func isAddingStoredGeneratedColumn(col *table.Column) bool {
+	return col.State != model.StatePublic && col.IsGenerated() && col.GeneratedStored
+}
+
+// evalAddingStoredGeneratedColumn evaluates the value of the stored generated column being added, r contains the
+// values of the public columns.
+func (t *TableCommon) evalAddingStoredGeneratedColumn(ctx sessionctx.Context, col *table.Column, r []types.Datum) (types.Datum, error) {
+	expr, err := expression.RewriteSimpleExprWithTableInfo(ctx, t.meta, col.GeneratedExpr)
+	if err != nil {
+		return types.Datum{}, errors.Trace(err)
+	}
+	val, err := expr.Eval(chunk.MutRowFromDatums(r).ToRow())
+	if err != nil {
+		return types.Datum{}, errors.Trace(err)
+	}
+	return table.CastValue(ctx, val, col.ColumnInfo, false, false)
+}
+
 // RemoveRecord implements table.Table RemoveRecord interface.
 func (t *TableCommon) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r []types.Datum) error {
 	err := t.removeRowData(ctx, h)
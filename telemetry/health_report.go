@@ -0,0 +1,295 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/pingcap/br/pkg/storage"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+const (
+	// HealthReportOwnerKey is the health report owner path that is saved to etcd.
+	HealthReportOwnerKey = "/tidb/telemetry/health-report/owner"
+	// HealthReportPrompt is the prompt for health report owner manager.
+	HealthReportPrompt = "health-report"
+	// HealthReportInterval is the interval of the health report, which is also the period covered by a report.
+	HealthReportInterval = 24 * time.Hour
+	// HealthReportCheckInterval is the interval of checking whether the next health report is due.
+	HealthReportCheckInterval = 10 * time.Minute
+	// HealthReportRetention is how long the health reports are kept in mysql.health_report.
+	HealthReportRetention = 30 * 24 * time.Hour
+)
+
+const (
+	healthReportTopN     = 10
+	healthReportTimeFmt  = "2006-01-02 15:04:05"
+	healthReportFileTime = "20060102150405"
+)
+
+// HealthReport is the self-diagnostics summary of the cluster in a period.
+type HealthReport struct {
+	GenerateTime      string            `json:"generateTime"`
+	StartTime         string            `json:"startTime"`
+	EndTime           string            `json:"endTime"`
+	InspectionResults []*inspectionItem `json:"inspectionResults"`
+	SlowQueries       []*slowQueryItem  `json:"slowQueries"`
+	HotRegions        []*hotRegionItem  `json:"hotRegions"`
+	// Errors records the sections that failed to be collected, the other sections are still reported.
+	Errors []string `json:"errors,omitempty"`
+}
+
+type inspectionItem struct {
+	Rule      string `json:"rule"`
+	Item      string `json:"item"`
+	Type      string `json:"type"`
+	Instance  string `json:"instance"`
+	Value     string `json:"value"`
+	Reference string `json:"reference"`
+	Severity  string `json:"severity"`
+	Details   string `json:"details"`
+}
+
+type slowQueryItem struct {
+	Digest       string  `json:"digest"`
+	Query        string  `json:"query"`
+	ExecCount    int64   `json:"execCount"`
+	MaxQueryTime float64 `json:"maxQueryTime"`
+	SumQueryTime float64 `json:"sumQueryTime"`
+}
+
+type hotRegionItem struct {
+	Type         string `json:"type"`
+	DBName       string `json:"dbName"`
+	TableName    string `json:"tableName"`
+	IndexName    string `json:"indexName,omitempty"`
+	RegionID     int64  `json:"regionId"`
+	MaxHotDegree int64  `json:"maxHotDegree"`
+	FlowBytes    int64  `json:"flowBytes"`
+}
+
+// IsHealthReportEnabled checks whether the daily health report is enabled.
+func IsHealthReportEnabled(ctx sessionctx.Context) (bool, error) {
+	val, err := ctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBEnableHealthReport)
+	return variable.TiDBOptOn(val), err
+}
+
+func execHealthReportSQL(ctx sessionctx.Context, sql string, args ...interface{}) ([]chunk.Row, error) {
+	exec := ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(context.TODO(), sql, args...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rows, _, err := exec.ExecRestrictedStmt(context.TODO(), stmt)
+	return rows, errors.Trace(err)
+}
+
+// GenerateHealthReport runs the inspection rules and aggregates the slow queries and hot regions of the period
+// [start, end).
+func GenerateHealthReport(ctx sessionctx.Context, start, end time.Time) *HealthReport {
+	startStr, endStr := start.Format(healthReportTimeFmt), end.Format(healthReportTimeFmt)
+	r := &HealthReport{
+		GenerateTime:      time.Now().Format(time.RFC3339),
+		StartTime:         startStr,
+		EndTime:           endStr,
+		InspectionResults: make([]*inspectionItem, 0),
+		SlowQueries:       make([]*slowQueryItem, 0),
+		HotRegions:        make([]*hotRegionItem, 0),
+	}
+
+	// The time range hint can't be parameterized, the times are formatted by ourselves so it's safe.
+	rows, err := execHealthReportSQL(ctx, fmt.Sprintf(`SELECT /*+ time_range('%s','%s') */ RULE, ITEM, TYPE, INSTANCE,
+		VALUE, REFERENCE, SEVERITY, DETAILS FROM information_schema.inspection_result`, startStr, endStr))
+	if err != nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("inspection result: %v", err))
+	}
+	for _, row := range rows {
+		r.InspectionResults = append(r.InspectionResults, &inspectionItem{
+			Rule:      row.GetString(0),
+			Item:      row.GetString(1),
+			Type:      row.GetString(2),
+			Instance:  row.GetString(3),
+			Value:     row.GetString(4),
+			Reference: row.GetString(5),
+			Severity:  row.GetString(6),
+			Details:   row.GetString(7),
+		})
+	}
+
+	rows, err = execHealthReportSQL(ctx, `SELECT DIGEST, ANY_VALUE(QUERY), COUNT(*), MAX(QUERY_TIME), SUM(QUERY_TIME)
+		FROM information_schema.cluster_slow_query WHERE TIME >= %? AND TIME < %? AND IS_INTERNAL = FALSE
+		GROUP BY DIGEST ORDER BY MAX(QUERY_TIME) DESC LIMIT %?`, startStr, endStr, healthReportTopN)
+	if err != nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("slow query: %v", err))
+	}
+	for _, row := range rows {
+		r.SlowQueries = append(r.SlowQueries, &slowQueryItem{
+			Digest:       row.GetString(0),
+			Query:        row.GetString(1),
+			ExecCount:    row.GetInt64(2),
+			MaxQueryTime: row.GetFloat64(3),
+			SumQueryTime: row.GetFloat64(4),
+		})
+	}
+
+	rows, err = execHealthReportSQL(ctx, `SELECT TYPE, DB_NAME, TABLE_NAME, IFNULL(INDEX_NAME, ''), REGION_ID,
+		MAX_HOT_DEGREE, FLOW_BYTES FROM information_schema.tidb_hot_regions ORDER BY FLOW_BYTES DESC LIMIT %?`,
+		healthReportTopN)
+	if err != nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("hot region: %v", err))
+	}
+	for _, row := range rows {
+		r.HotRegions = append(r.HotRegions, &hotRegionItem{
+			Type:         row.GetString(0),
+			DBName:       row.GetString(1),
+			TableName:    row.GetString(2),
+			IndexName:    row.GetString(3),
+			RegionID:     row.GetInt64(4),
+			MaxHotDegree: row.GetInt64(5),
+			FlowBytes:    row.GetInt64(6),
+		})
+	}
+	return r
+}
+
+var healthReportTemplate = template.Must(template.New("health-report").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>TiDB Health Report {{.StartTime}} ~ {{.EndTime}}</title></head>
+<body>
+<h1>TiDB Health Report</h1>
+<p>Period: {{.StartTime}} ~ {{.EndTime}}, generated at {{.GenerateTime}}</p>
+{{- if .Errors}}
+<h2>Errors</h2>
+<ul>{{range .Errors}}<li>{{.}}</li>{{end}}</ul>
+{{- end}}
+<h2>Inspection Results</h2>
+<table border="1">
+<tr><th>Rule</th><th>Item</th><th>Type</th><th>Instance</th><th>Value</th><th>Reference</th><th>Severity</th><th>Details</th></tr>
+{{- range .InspectionResults}}
+<tr><td>{{.Rule}}</td><td>{{.Item}}</td><td>{{.Type}}</td><td>{{.Instance}}</td><td>{{.Value}}</td><td>{{.Reference}}</td><td>{{.Severity}}</td><td>{{.Details}}</td></tr>
+{{- end}}
+</table>
+<h2>Top Slow Queries</h2>
+<table border="1">
+<tr><th>Digest</th><th>Query</th><th>Exec Count</th><th>Max Query Time (s)</th><th>Sum Query Time (s)</th></tr>
+{{- range .SlowQueries}}
+<tr><td>{{.Digest}}</td><td>{{.Query}}</td><td>{{.ExecCount}}</td><td>{{.MaxQueryTime}}</td><td>{{.SumQueryTime}}</td></tr>
+{{- end}}
+</table>
+<h2>Hot Regions</h2>
+<table border="1">
+<tr><th>Type</th><th>DB</th><th>Table</th><th>Index</th><th>Region ID</th><th>Max Hot Degree</th><th>Flow Bytes</th></tr>
+{{- range .HotRegions}}
+<tr><td>{{.Type}}</td><td>{{.DBName}}</td><td>{{.TableName}}</td><td>{{.IndexName}}</td><td>{{.RegionID}}</td><td>{{.MaxHotDegree}}</td><td>{{.FlowBytes}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+// HTML renders the health report as an HTML page.
+func (r *HealthReport) HTML() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := healthReportTemplate.Execute(&buf, r); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return buf.Bytes(), nil
+}
+
+// SaveHealthReport saves the health report to mysql.health_report. If tidb_health_report_storage is set, the JSON
+// and HTML renderings are also written to the external storage.
+func SaveHealthReport(ctx sessionctx.Context, r *HealthReport) error {
+	rawJSON, err := json.Marshal(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err = execHealthReportSQL(ctx, "INSERT HIGH_PRIORITY INTO mysql.health_report (START_TIME, END_TIME, REPORT) VALUES (%?, %?, %?)",
+		r.StartTime, r.EndTime, string(rawJSON)); err != nil {
+		return err
+	}
+
+	storageURL, err := ctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(variable.TiDBHealthReportStorage)
+	if err != nil || storageURL == "" {
+		return errors.Trace(err)
+	}
+	backend, err := storage.ParseBackend(storageURL, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	reqCtx, cancel := context.WithTimeout(context.Background(), uploadTimeout)
+	defer cancel()
+	store, err := storage.New(reqCtx, backend, &storage.ExternalStorageOptions{})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	html, err := r.HTML()
+	if err != nil {
+		return err
+	}
+	endTime, err := time.ParseInLocation(healthReportTimeFmt, r.EndTime, time.Local)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	name := "health-report-" + endTime.Format(healthReportFileTime)
+	if err = store.WriteFile(reqCtx, name+".json", rawJSON); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(store.WriteFile(reqCtx, name+".html", html))
+}
+
+// IsHealthReportDue checks whether HealthReportInterval has passed since the end time of the last report saved in
+// mysql.health_report. The schedule is kept in the table rather than in memory, so it isn't delayed by the restarts
+// of the servers or the changes of the owner.
+func IsHealthReportDue(ctx sessionctx.Context, now time.Time) (bool, error) {
+	rows, err := execHealthReportSQL(ctx, "SELECT MAX(END_TIME) FROM mysql.health_report")
+	if err != nil {
+		return false, err
+	}
+	if len(rows) == 0 || rows[0].IsNull(0) {
+		return true, nil
+	}
+	lastEnd, err := rows[0].GetTime(0).GoTime(time.Local)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return !now.Before(lastEnd.Add(HealthReportInterval)), nil
+}
+
+// RunHealthReport generates the health report of the last HealthReportInterval and saves it, then removes the
+// reports older than HealthReportRetention.
+func RunHealthReport(ctx sessionctx.Context) error {
+	end := time.Now()
+	r := GenerateHealthReport(ctx, end.Add(-HealthReportInterval), end)
+	if err := SaveHealthReport(ctx, r); err != nil {
+		return err
+	}
+	return PruneHealthReports(ctx, end.Add(-HealthReportRetention))
+}
+
+// PruneHealthReports removes the health reports that end before the given time from mysql.health_report.
+func PruneHealthReports(ctx sessionctx.Context, before time.Time) error {
+	_, err := execHealthReportSQL(ctx, "DELETE HIGH_PRIORITY FROM mysql.health_report WHERE END_TIME < %?",
+		before.Format(healthReportTimeFmt))
+	return err
+}
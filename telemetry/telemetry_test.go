@@ -15,8 +15,14 @@ package telemetry_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Jeffail/gabs/v2"
 	. "github.com/pingcap/check"
@@ -181,3 +187,63 @@ func (s *testSuite) TestCTEPreviewAndReport(c *C) {
 	c.Assert(int(jsonParsed.Path("featureUsage.cte.recursiveUsed").Data().(float64)), Equals, 0)
 	c.Assert(int(jsonParsed.Path("featureUsage.cte.nonCTEUsed").Data().(float64)), Equals, 0)
 }
+
+func (s *testSuite) TestHealthReport(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	enabled, err := telemetry.IsHealthReportEnabled(s.se)
+	c.Assert(err, IsNil)
+	c.Assert(enabled, IsFalse)
+	tk.MustExec("set @@global.tidb_enable_health_report = 1")
+	defer tk.MustExec("set @@global.tidb_enable_health_report = default")
+	enabled, err = telemetry.IsHealthReportEnabled(s.se)
+	c.Assert(err, IsNil)
+	c.Assert(enabled, IsTrue)
+
+	// The cluster tables aren't available in the mock store, so the report is built directly.
+	end := time.Now()
+	r := &telemetry.HealthReport{
+		GenerateTime: end.Format(time.RFC3339),
+		StartTime:    end.Add(-telemetry.HealthReportInterval).Format("2006-01-02 15:04:05"),
+		EndTime:      end.Format("2006-01-02 15:04:05"),
+		Errors:       []string{"hot region: <pd unavailable>"},
+	}
+	rawJSON, err := json.Marshal(r)
+	c.Assert(err, IsNil)
+	html, err := r.HTML()
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(string(html), "<h2>Top Slow Queries</h2>"), IsTrue)
+	c.Assert(strings.Contains(string(html), "<li>hot region: &lt;pd unavailable&gt;</li>"), IsTrue)
+
+	// The report is saved to the system table and the external storage.
+	dir := c.MkDir()
+	tk.MustExec(fmt.Sprintf("set @@global.tidb_health_report_storage = 'local://%s'", dir))
+	defer tk.MustExec("set @@global.tidb_health_report_storage = default")
+	c.Assert(telemetry.SaveHealthReport(s.se, r), IsNil)
+	tk.MustQuery("select count(*) from mysql.health_report where json_unquote(json_extract(report, '$.endTime')) = cast(end_time as char)").
+		Check(testkit.Rows("1"))
+	name := filepath.Join(dir, "health-report-"+end.Format("20060102150405"))
+	content, err := os.ReadFile(name + ".json")
+	c.Assert(err, IsNil)
+	c.Assert(content, DeepEquals, rawJSON)
+	content, err = os.ReadFile(name + ".html")
+	c.Assert(err, IsNil)
+	c.Assert(content, DeepEquals, html)
+
+	// The next report is due HealthReportInterval after the end time of the last report.
+	due, err := telemetry.IsHealthReportDue(s.se, end.Add(time.Hour))
+	c.Assert(err, IsNil)
+	c.Assert(due, IsFalse)
+	due, err = telemetry.IsHealthReportDue(s.se, end.Add(telemetry.HealthReportInterval+time.Second))
+	c.Assert(err, IsNil)
+	c.Assert(due, IsTrue)
+
+	// The reports older than the retention are pruned.
+	old := end.Add(-telemetry.HealthReportRetention - time.Hour).Format("2006-01-02 15:04:05")
+	tk.MustExec("insert into mysql.health_report (start_time, end_time) values (?, ?)", old, old)
+	c.Assert(telemetry.PruneHealthReports(s.se, end.Add(-telemetry.HealthReportRetention)), IsNil)
+	tk.MustQuery("select count(*) from mysql.health_report").Check(testkit.Rows("1"))
+	tk.MustExec("delete from mysql.health_report")
+	due, err = telemetry.IsHealthReportDue(s.se, end)
+	c.Assert(err, IsNil)
+	c.Assert(due, IsTrue)
+}
@@ -52,20 +52,25 @@ import (
 	"github.com/pingcap/tidb/store/driver"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/admission"
+	"github.com/pingcap/tidb/util/conflicthistory"
 	"github.com/pingcap/tidb/util/deadlockhistory"
 	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/domainutil"
+	"github.com/pingcap/tidb/util/gctuner"
 	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/printer"
 	"github.com/pingcap/tidb/util/profile"
+	"github.com/pingcap/tidb/util/redact"
 	"github.com/pingcap/tidb/util/sem"
 	"github.com/pingcap/tidb/util/signal"
 	"github.com/pingcap/tidb/util/sys/linux"
 	storageSys "github.com/pingcap/tidb/util/sys/storage"
 	"github.com/pingcap/tidb/util/systimemon"
 	"github.com/pingcap/tidb/util/topsql"
+	"github.com/pingcap/tidb/util/txnsummary"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/push"
 	"github.com/tikv/client-go/v2/tikv"
@@ -196,6 +201,8 @@ func main() {
 		close(exited)
 	})
 	topsql.SetupTopSQL()
+	admission.Setup()
+	setupGCTuner()
 	terror.MustNil(svr.Run())
 	<-exited
 	syncLog()
@@ -582,8 +589,8 @@ func setGlobalVars() {
 	domainutil.RepairInfo.SetRepairTableList(cfg.RepairTableList)
 	executor.GlobalDiskUsageTracker.SetBytesLimit(cfg.TempStorageQuota)
 	if cfg.Performance.ServerMemoryQuota < 1 {
-		// If MaxMemory equals 0, it means unlimited
-		executor.GlobalMemoryUsageTracker.SetBytesLimit(-1)
+		// If MaxMemory equals 0, it means unlimited unless it's derived from the cgroup memory limit.
+		executor.GlobalMemoryUsageTracker.SetBytesLimit(defaultMemoryQuota())
 	} else {
 		executor.GlobalMemoryUsageTracker.SetBytesLimit(int64(cfg.Performance.ServerMemoryQuota))
 	}
@@ -597,6 +604,9 @@ func setGlobalVars() {
 	tikv.SetStoreLivenessTimeout(t)
 	parsertypes.TiDBStrictIntegerDisplayWidth = cfg.DeprecateIntegerDisplayWidth
 	deadlockhistory.GlobalDeadlockHistory.Resize(cfg.PessimisticTxn.DeadlockHistoryCapacity)
+	txnsummary.GlobalTxnSummary.Resize(cfg.Performance.TxnSummaryCapacity)
+	conflicthistory.GlobalConflictHistory.Resize(cfg.Performance.WriteConflictHistoryCapacity)
+	redact.SetGlobalPolicy(redact.NewPolicy(cfg.Log.RedactSensitiveColumns))
 }
 
 func setupLog() {
@@ -671,6 +681,39 @@ func closeDomainAndStorage(storage kv.Storage, dom *domain.Domain) {
 	terror.Log(errors.Trace(err))
 }
 
+// cgroupMemQuotaRatio is the ratio of the cgroup memory limit used as the server memory quota if it's not configured,
+// the rest is left for the memory not tracked and the garbage not collected yet.
+const cgroupMemQuotaRatio = 0.8
+
+// cgroupMemoryQuota returns the server memory quota derived from the cgroup memory limit, -1 means unlimited.
+func cgroupMemoryQuota() int64 {
+	limit, ok := memory.CGroupMemLimit()
+	if !ok {
+		return -1
+	}
+	quota := int64(float64(limit) * cgroupMemQuotaRatio)
+	logutil.BgLogger().Info("set the server memory quota by the cgroup memory limit",
+		zap.Uint64("limit", limit), zap.Int64("quota", quota))
+	return quota
+}
+
+// defaultMemoryQuota returns the server memory quota used when server-memory-quota is 0, -1 means unlimited.
+func defaultMemoryQuota() int64 {
+	if !config.GetGlobalConfig().Performance.ServerMemoryQuotaFromCgroup {
+		return -1
+	}
+	return cgroupMemoryQuota()
+}
+
+func setupGCTuner() {
+	gctuner.Setup(func(uint64) {
+		cfg := config.GetGlobalConfig()
+		if cfg.Performance.ServerMemoryQuota < 1 && cfg.Performance.ServerMemoryQuotaFromCgroup {
+			executor.GlobalMemoryUsageTracker.SetBytesLimit(cgroupMemoryQuota())
+		}
+	})
+}
+
 func cleanup(svr *server.Server, storage kv.Storage, dom *domain.Domain, graceful bool) {
 	if graceful {
 		svr.GracefulDown(context.Background(), nil)
@@ -681,6 +724,8 @@ func cleanup(svr *server.Server, storage kv.Storage, dom *domain.Domain, gracefu
 	closeDomainAndStorage(storage, dom)
 	disk.CleanUp()
 	topsql.Close()
+	admission.Close()
+	gctuner.Close()
 }
 
 func stringToList(repairString string) []string {
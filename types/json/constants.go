@@ -221,6 +221,11 @@ var (
 	ErrInvalidJSONPathArrayCell = dbterror.ClassJSON.NewStd(mysql.ErrInvalidJSONPathArrayCell)
 	// ErrUnsupportedSecondArgumentType means unsupported second argument type in json_objectagg
 	ErrUnsupportedSecondArgumentType = dbterror.ClassJSON.NewStd(mysql.ErrUnsupportedSecondArgumentType)
+	// ErrInvalidJSONType means the JSON argument is not of the required type, such as an object.
+	ErrInvalidJSONType = dbterror.ClassJSON.NewStd(mysql.ErrInvalidJSONType)
+	// ErrUnsupportedJSONSchema means the JSON Schema uses the features which aren't supported, such as the remote
+	// references.
+	ErrUnsupportedJSONSchema = dbterror.ClassJSON.NewStd(mysql.ErrNotSupportedYet)
 	// ErrJSONObjectKeyTooLong means JSON object with key length >= 65536 which is not yet supported.
 	ErrJSONObjectKeyTooLong = dbterror.ClassTypes.NewStdErr(mysql.ErrJSONObjectKeyTooLong, mysql.MySQLErrName[mysql.ErrJSONObjectKeyTooLong])
 )
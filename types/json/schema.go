@@ -0,0 +1,509 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/tidb/util/hack"
+)
+
+// SchemaValidationFailure describes where a JSON document fails to validate against a JSON Schema. The locations are
+// JSON pointers in URI fragment form, such as '#/properties/a'.
+type SchemaValidationFailure struct {
+	// SchemaLocation is the location of the failed sub-schema in the schema.
+	SchemaLocation string
+	// DocumentLocation is the location of the failed value in the document.
+	DocumentLocation string
+	// FailedKeyword is the keyword of the sub-schema that the value fails.
+	FailedKeyword string
+}
+
+// Reason returns the failure in the same words as MySQL.
+func (f *SchemaValidationFailure) Reason() string {
+	return "The JSON document location '" + f.DocumentLocation + "' failed requirement '" + f.FailedKeyword +
+		"' at JSON Schema location '" + f.SchemaLocation + "'"
+}
+
+// ValidateSchema validates the document against the schema of JSON Schema draft 4. It returns nil if the document is
+// valid, or the first failure found otherwise. Only the references in the schema itself are supported, the unknown
+// keywords, including 'format', are ignored.
+func ValidateSchema(schema, doc BinaryJSON) (*SchemaValidationFailure, error) {
+	v := &schemaValidator{root: schema, patterns: make(map[string]*regexp.Regexp)}
+	return v.validate(schema, []string{}, doc, []string{}, nil)
+}
+
+type schemaValidator struct {
+	root BinaryJSON
+	// patterns caches the compiled regular expressions of 'pattern' and 'patternProperties', nil means the pattern
+	// is invalid and ignored.
+	patterns map[string]*regexp.Regexp
+}
+
+func newSchemaValidationFailure(schemaPath, docPath []string, keyword string) *SchemaValidationFailure {
+	return &SchemaValidationFailure{
+		SchemaLocation:   encodeJSONPointer(schemaPath),
+		DocumentLocation: encodeJSONPointer(docPath),
+		FailedKeyword:    keyword,
+	}
+}
+
+var (
+	jsonPointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+func encodeJSONPointer(tokens []string) string {
+	var sb strings.Builder
+	sb.WriteString("#")
+	for _, token := range tokens {
+		sb.WriteString("/")
+		sb.WriteString(jsonPointerEscaper.Replace(token))
+	}
+	return sb.String()
+}
+
+func appendToken(path []string, token string) []string {
+	newPath := make([]string, len(path), len(path)+1)
+	copy(newPath, path)
+	return append(newPath, token)
+}
+
+func (v *schemaValidator) getPattern(pattern string) *regexp.Regexp {
+	re, ok := v.patterns[pattern]
+	if !ok {
+		re, _ = regexp.Compile(pattern)
+		v.patterns[pattern] = re
+	}
+	return re
+}
+
+// resolveRef resolves the reference in the schema itself, which is a JSON pointer such as '#/definitions/a'. The
+// references that can't be resolved are ignored like the ones to an empty schema.
+func (v *schemaValidator) resolveRef(ref string) (BinaryJSON, []string, bool, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return BinaryJSON{}, nil, false, ErrUnsupportedJSONSchema.GenWithStackByArgs("references in JSON Schema")
+	}
+	schema, path := v.root, []string{}
+	pointer := ref[1:]
+	if pointer == "" {
+		return schema, path, true, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return BinaryJSON{}, nil, false, nil
+	}
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = jsonPointerUnescaper.Replace(token)
+		switch schema.TypeCode {
+		case TypeCodeObject:
+			val, ok := schema.objectSearchKey(hack.Slice(token))
+			if !ok {
+				return BinaryJSON{}, nil, false, nil
+			}
+			schema = val
+		case TypeCodeArray:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= schema.GetElemCount() {
+				return BinaryJSON{}, nil, false, nil
+			}
+			schema = schema.arrayGetElem(idx)
+		default:
+			return BinaryJSON{}, nil, false, nil
+		}
+		path = append(path, token)
+	}
+	return schema, path, true, nil
+}
+
+// validate validates the value at docPath against the sub-schema at schemaPath. refs is the references resolved at
+// the same value, a reference met twice is a cycle which can't add any more requirements.
+func (v *schemaValidator) validate(schema BinaryJSON, schemaPath []string, doc BinaryJSON, docPath []string, refs map[string]struct{}) (*SchemaValidationFailure, error) {
+	if schema.TypeCode != TypeCodeObject {
+		return nil, nil
+	}
+	// All the other keywords are ignored if there is a reference in draft 4.
+	if ref, ok := schema.objectSearchKey([]byte("$ref")); ok && ref.TypeCode == TypeCodeString {
+		refStr := string(ref.GetString())
+		if _, ok := refs[refStr]; ok {
+			return nil, nil
+		}
+		refSchema, refPath, ok, err := v.resolveRef(refStr)
+		if err != nil || !ok {
+			return nil, err
+		}
+		newRefs := make(map[string]struct{}, len(refs)+1)
+		for r := range refs {
+			newRefs[r] = struct{}{}
+		}
+		newRefs[refStr] = struct{}{}
+		return v.validate(refSchema, refPath, doc, docPath, newRefs)
+	}
+
+	if tp, ok := schema.objectSearchKey([]byte("type")); ok && !matchSchemaType(tp, doc) {
+		return newSchemaValidationFailure(schemaPath, docPath, "type"), nil
+	}
+	if enum, ok := schema.objectSearchKey([]byte("enum")); ok && enum.TypeCode == TypeCodeArray {
+		found := false
+		for i := 0; i < enum.GetElemCount() && !found; i++ {
+			found = CompareBinary(enum.arrayGetElem(i), doc) == 0
+		}
+		if !found {
+			return newSchemaValidationFailure(schemaPath, docPath, "enum"), nil
+		}
+	}
+	if failure, err := v.validateCombinators(schema, schemaPath, doc, docPath, refs); failure != nil || err != nil {
+		return failure, err
+	}
+
+	switch doc.TypeCode {
+	case TypeCodeInt64, TypeCodeUint64, TypeCodeFloat64:
+		if keyword := validateSchemaNumber(schema, doc); keyword != "" {
+			return newSchemaValidationFailure(schemaPath, docPath, keyword), nil
+		}
+	case TypeCodeString:
+		if keyword := v.validateSchemaString(schema, doc); keyword != "" {
+			return newSchemaValidationFailure(schemaPath, docPath, keyword), nil
+		}
+	case TypeCodeArray:
+		return v.validateArray(schema, schemaPath, doc, docPath)
+	case TypeCodeObject:
+		return v.validateObject(schema, schemaPath, doc, docPath)
+	}
+	return nil, nil
+}
+
+// validateCombinators validates the 'allOf', 'anyOf', 'oneOf' and 'not' keywords, the failures inside them are
+// reported as the failures of these keywords.
+func (v *schemaValidator) validateCombinators(schema BinaryJSON, schemaPath []string, doc BinaryJSON, docPath []string, refs map[string]struct{}) (*SchemaValidationFailure, error) {
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		subSchemas, ok := schema.objectSearchKey(hack.Slice(keyword))
+		if !ok || subSchemas.TypeCode != TypeCodeArray {
+			continue
+		}
+		matched := 0
+		for i := 0; i < subSchemas.GetElemCount(); i++ {
+			subPath := appendToken(appendToken(schemaPath, keyword), strconv.Itoa(i))
+			failure, err := v.validate(subSchemas.arrayGetElem(i), subPath, doc, docPath, refs)
+			if err != nil {
+				return nil, err
+			}
+			if failure == nil {
+				matched++
+			}
+		}
+		count := subSchemas.GetElemCount()
+		if (keyword == "allOf" && matched != count) || (keyword == "anyOf" && matched == 0) || (keyword == "oneOf" && matched != 1) {
+			return newSchemaValidationFailure(schemaPath, docPath, keyword), nil
+		}
+	}
+	if not, ok := schema.objectSearchKey([]byte("not")); ok {
+		failure, err := v.validate(not, appendToken(schemaPath, "not"), doc, docPath, refs)
+		if err != nil {
+			return nil, err
+		}
+		if failure == nil {
+			return newSchemaValidationFailure(schemaPath, docPath, "not"), nil
+		}
+	}
+	return nil, nil
+}
+
+func matchSchemaType(tp BinaryJSON, doc BinaryJSON) bool {
+	switch tp.TypeCode {
+	case TypeCodeString:
+		return matchSchemaTypeName(string(tp.GetString()), doc)
+	case TypeCodeArray:
+		for i := 0; i < tp.GetElemCount(); i++ {
+			elem := tp.arrayGetElem(i)
+			if elem.TypeCode == TypeCodeString && matchSchemaTypeName(string(elem.GetString()), doc) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchSchemaTypeName(name string, doc BinaryJSON) bool {
+	switch name {
+	case "object":
+		return doc.TypeCode == TypeCodeObject
+	case "array":
+		return doc.TypeCode == TypeCodeArray
+	case "string":
+		return doc.TypeCode == TypeCodeString
+	case "number":
+		return doc.TypeCode == TypeCodeInt64 || doc.TypeCode == TypeCodeUint64 || doc.TypeCode == TypeCodeFloat64
+	case "integer":
+		return doc.TypeCode == TypeCodeInt64 || doc.TypeCode == TypeCodeUint64
+	case "boolean":
+		return doc.TypeCode == TypeCodeLiteral && doc.Value[0] != LiteralNil
+	case "null":
+		return doc.TypeCode == TypeCodeLiteral && doc.Value[0] == LiteralNil
+	}
+	return false
+}
+
+func jsonNumberToFloat64(bj BinaryJSON) (float64, bool) {
+	switch bj.TypeCode {
+	case TypeCodeInt64:
+		return float64(bj.GetInt64()), true
+	case TypeCodeUint64:
+		return float64(bj.GetUint64()), true
+	case TypeCodeFloat64:
+		return bj.GetFloat64(), true
+	}
+	return 0, false
+}
+
+// validateSchemaNumber returns the keyword the number fails, or "" if it's valid.
+func validateSchemaNumber(schema, doc BinaryJSON) string {
+	if multipleOf, ok := schema.objectSearchKey([]byte("multipleOf")); ok {
+		if m, ok := jsonNumberToFloat64(multipleOf); ok && m > 0 {
+			if doc.TypeCode != TypeCodeFloat64 && multipleOf.TypeCode != TypeCodeFloat64 {
+				if !isIntegerMultiple(doc, multipleOf) {
+					return "multipleOf"
+				}
+			} else {
+				val, _ := jsonNumberToFloat64(doc)
+				if q := val / m; q != math.Floor(q) {
+					return "multipleOf"
+				}
+			}
+		}
+	}
+	if maximum, ok := schema.objectSearchKey([]byte("maximum")); ok && isJSONNumber(maximum) {
+		cmp := CompareBinary(doc, maximum)
+		if cmp > 0 || (cmp == 0 && isSchemaKeywordTrue(schema, "exclusiveMaximum")) {
+			return "maximum"
+		}
+	}
+	if minimum, ok := schema.objectSearchKey([]byte("minimum")); ok && isJSONNumber(minimum) {
+		cmp := CompareBinary(doc, minimum)
+		if cmp < 0 || (cmp == 0 && isSchemaKeywordTrue(schema, "exclusiveMinimum")) {
+			return "minimum"
+		}
+	}
+	return ""
+}
+
+func isJSONNumber(bj BinaryJSON) bool {
+	_, ok := jsonNumberToFloat64(bj)
+	return ok
+}
+
+// isIntegerMultiple checks whether the integer a is a multiple of the positive integer b without losing precision.
+func isIntegerMultiple(a, b BinaryJSON) bool {
+	divisor := b.GetUint64()
+	if b.TypeCode == TypeCodeInt64 {
+		divisor = uint64(b.GetInt64())
+	}
+	if a.TypeCode == TypeCodeUint64 {
+		return a.GetUint64()%divisor == 0
+	}
+	val := a.GetInt64()
+	if val < 0 {
+		return uint64(-val)%divisor == 0
+	}
+	return uint64(val)%divisor == 0
+}
+
+func isSchemaKeywordTrue(schema BinaryJSON, keyword string) bool {
+	val, ok := schema.objectSearchKey(hack.Slice(keyword))
+	return ok && val.TypeCode == TypeCodeLiteral && val.Value[0] == LiteralTrue
+}
+
+// getSchemaCount gets the non-negative integer value of the keyword, such as 'maxLength'.
+func getSchemaCount(schema BinaryJSON, keyword string) (int64, bool) {
+	val, ok := schema.objectSearchKey(hack.Slice(keyword))
+	if !ok {
+		return 0, false
+	}
+	switch val.TypeCode {
+	case TypeCodeInt64:
+		return val.GetInt64(), val.GetInt64() >= 0
+	case TypeCodeUint64:
+		if val.GetUint64() > math.MaxInt64 {
+			return math.MaxInt64, true
+		}
+		return int64(val.GetUint64()), true
+	}
+	return 0, false
+}
+
+// validateSchemaString returns the keyword the string fails, or "" if it's valid.
+func (v *schemaValidator) validateSchemaString(schema, doc BinaryJSON) string {
+	length := int64(utf8.RuneCount(doc.GetString()))
+	if maxLength, ok := getSchemaCount(schema, "maxLength"); ok && length > maxLength {
+		return "maxLength"
+	}
+	if minLength, ok := getSchemaCount(schema, "minLength"); ok && length < minLength {
+		return "minLength"
+	}
+	if pattern, ok := schema.objectSearchKey([]byte("pattern")); ok && pattern.TypeCode == TypeCodeString {
+		if re := v.getPattern(string(pattern.GetString())); re != nil && !re.Match(doc.GetString()) {
+			return "pattern"
+		}
+	}
+	return ""
+}
+
+func (v *schemaValidator) validateArray(schema BinaryJSON, schemaPath []string, doc BinaryJSON, docPath []string) (*SchemaValidationFailure, error) {
+	count := doc.GetElemCount()
+	if items, ok := schema.objectSearchKey([]byte("items")); ok {
+		for i := 0; i < count; i++ {
+			itemSchema, itemPath := items, appendToken(schemaPath, "items")
+			if items.TypeCode == TypeCodeArray {
+				if i < items.GetElemCount() {
+					itemSchema, itemPath = items.arrayGetElem(i), appendToken(itemPath, strconv.Itoa(i))
+				} else {
+					additional, ok := schema.objectSearchKey([]byte("additionalItems"))
+					if !ok {
+						break
+					}
+					if additional.TypeCode == TypeCodeLiteral {
+						if additional.Value[0] == LiteralFalse {
+							return newSchemaValidationFailure(schemaPath, docPath, "additionalItems"), nil
+						}
+						break
+					}
+					itemSchema, itemPath = additional, appendToken(schemaPath, "additionalItems")
+				}
+			}
+			failure, err := v.validate(itemSchema, itemPath, doc.arrayGetElem(i), appendToken(docPath, strconv.Itoa(i)), nil)
+			if failure != nil || err != nil {
+				return failure, err
+			}
+		}
+	}
+	if maxItems, ok := getSchemaCount(schema, "maxItems"); ok && int64(count) > maxItems {
+		return newSchemaValidationFailure(schemaPath, docPath, "maxItems"), nil
+	}
+	if minItems, ok := getSchemaCount(schema, "minItems"); ok && int64(count) < minItems {
+		return newSchemaValidationFailure(schemaPath, docPath, "minItems"), nil
+	}
+	if isSchemaKeywordTrue(schema, "uniqueItems") {
+		for i := 0; i < count; i++ {
+			for j := i + 1; j < count; j++ {
+				if CompareBinary(doc.arrayGetElem(i), doc.arrayGetElem(j)) == 0 {
+					return newSchemaValidationFailure(schemaPath, docPath, "uniqueItems"), nil
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (v *schemaValidator) validateObject(schema BinaryJSON, schemaPath []string, doc BinaryJSON, docPath []string) (*SchemaValidationFailure, error) {
+	properties, hasProperties := schema.objectSearchKey([]byte("properties"))
+	hasProperties = hasProperties && properties.TypeCode == TypeCodeObject
+	patternProperties, hasPatternProperties := schema.objectSearchKey([]byte("patternProperties"))
+	hasPatternProperties = hasPatternProperties && patternProperties.TypeCode == TypeCodeObject
+	additional, hasAdditional := schema.objectSearchKey([]byte("additionalProperties"))
+
+	count := doc.GetElemCount()
+	for i := 0; i < count; i++ {
+		key, val := doc.objectGetKey(i), doc.objectGetVal(i)
+		valPath := appendToken(docPath, string(key))
+		matched := false
+		if hasProperties {
+			if propSchema, ok := properties.objectSearchKey(key); ok {
+				matched = true
+				failure, err := v.validate(propSchema, appendToken(appendToken(schemaPath, "properties"), string(key)), val, valPath, nil)
+				if failure != nil || err != nil {
+					return failure, err
+				}
+			}
+		}
+		if hasPatternProperties {
+			for j := 0; j < patternProperties.GetElemCount(); j++ {
+				pattern := patternProperties.objectGetKey(j)
+				if re := v.getPattern(string(pattern)); re == nil || !re.Match(key) {
+					continue
+				}
+				matched = true
+				failure, err := v.validate(patternProperties.objectGetVal(j), appendToken(appendToken(schemaPath, "patternProperties"), string(pattern)), val, valPath, nil)
+				if failure != nil || err != nil {
+					return failure, err
+				}
+			}
+		}
+		if matched || !hasAdditional {
+			continue
+		}
+		if additional.TypeCode == TypeCodeLiteral {
+			if additional.Value[0] == LiteralFalse {
+				return newSchemaValidationFailure(schemaPath, docPath, "additionalProperties"), nil
+			}
+			continue
+		}
+		failure, err := v.validate(additional, appendToken(schemaPath, "additionalProperties"), val, valPath, nil)
+		if failure != nil || err != nil {
+			return failure, err
+		}
+	}
+
+	if required, ok := schema.objectSearchKey([]byte("required")); ok && required.TypeCode == TypeCodeArray {
+		for i := 0; i < required.GetElemCount(); i++ {
+			name := required.arrayGetElem(i)
+			if name.TypeCode != TypeCodeString {
+				continue
+			}
+			if _, ok := doc.objectSearchKey(name.GetString()); !ok {
+				return newSchemaValidationFailure(schemaPath, docPath, "required"), nil
+			}
+		}
+	}
+	if maxProperties, ok := getSchemaCount(schema, "maxProperties"); ok && int64(count) > maxProperties {
+		return newSchemaValidationFailure(schemaPath, docPath, "maxProperties"), nil
+	}
+	if minProperties, ok := getSchemaCount(schema, "minProperties"); ok && int64(count) < minProperties {
+		return newSchemaValidationFailure(schemaPath, docPath, "minProperties"), nil
+	}
+
+	// The dependencies are either the names of the required properties or the schemas which the object must also
+	// match, if the object has the property.
+	if dependencies, ok := schema.objectSearchKey([]byte("dependencies")); ok && dependencies.TypeCode == TypeCodeObject {
+		for i := 0; i < dependencies.GetElemCount(); i++ {
+			key, dependency := dependencies.objectGetKey(i), dependencies.objectGetVal(i)
+			if _, ok := doc.objectSearchKey(key); !ok {
+				continue
+			}
+			if dependency.TypeCode == TypeCodeArray {
+				for j := 0; j < dependency.GetElemCount(); j++ {
+					name := dependency.arrayGetElem(j)
+					if name.TypeCode != TypeCodeString {
+						continue
+					}
+					if _, ok := doc.objectSearchKey(name.GetString()); !ok {
+						return newSchemaValidationFailure(schemaPath, docPath, "dependencies"), nil
+					}
+				}
+				continue
+			}
+			failure, err := v.validate(dependency, appendToken(appendToken(schemaPath, "dependencies"), string(key)), doc, docPath, nil)
+			if err != nil {
+				return nil, err
+			}
+			if failure != nil {
+				return newSchemaValidationFailure(schemaPath, docPath, "dependencies"), nil
+			}
+		}
+	}
+	return nil, nil
+}
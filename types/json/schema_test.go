@@ -0,0 +1,112 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	. "github.com/pingcap/check"
+)
+
+func (s *testJSONSuite) TestValidateSchema(c *C) {
+	tests := []struct {
+		schema  string
+		doc     string
+		failure string
+	}{
+		// The failures are described as "keyword schema-location document-location".
+		{`{}`, `[1, "a", null]`, ""},
+		{`{"type": "object"}`, `{}`, ""},
+		{`{"type": "object"}`, `[]`, "type # #"},
+		{`{"type": ["string", "null"]}`, `null`, ""},
+		{`{"type": "integer"}`, `1`, ""},
+		{`{"type": "integer"}`, `1.5`, "type # #"},
+		{`{"type": "number"}`, `18446744073709551615`, ""},
+		{`{"type": "boolean"}`, `null`, "type # #"},
+		{`{"enum": [1, "a", [2]]}`, `[2]`, ""},
+		{`{"enum": [1, "a", [2]]}`, `"b"`, "enum # #"},
+
+		// Numbers.
+		{`{"minimum": 1, "maximum": 10}`, `10`, ""},
+		{`{"minimum": 1, "maximum": 10, "exclusiveMaximum": true}`, `10`, "maximum # #"},
+		{`{"minimum": 1.5}`, `1`, "minimum # #"},
+		{`{"multipleOf": 3}`, `-9`, ""},
+		{`{"multipleOf": 3}`, `10`, "multipleOf # #"},
+		{`{"multipleOf": 0.5}`, `2.5`, ""},
+		{`{"multipleOf": 0.5}`, `2.2`, "multipleOf # #"},
+
+		// Strings, the lengths are counted in characters.
+		{`{"maxLength": 2}`, `"中文"`, ""},
+		{`{"minLength": 3}`, `"中文"`, "minLength # #"},
+		{`{"pattern": "^[a-z]+$"}`, `"abc"`, ""},
+		{`{"pattern": "^[a-z]+$"}`, `"ab1"`, "pattern # #"},
+		{`{"pattern": "^[a-z]+$"}`, `1`, ""},
+
+		// Arrays.
+		{`{"items": {"type": "string"}}`, `["a", 1]`, "type #/items #/1"},
+		{`{"items": [{"type": "string"}, {"type": "integer"}]}`, `["a", 1, null]`, ""},
+		{`{"items": [{"type": "string"}], "additionalItems": false}`, `["a", 1]`, "additionalItems # #"},
+		{`{"items": [{"type": "string"}], "additionalItems": {"type": "string"}}`, `["a", 1]`, "type #/additionalItems #/1"},
+		{`{"minItems": 1, "maxItems": 2}`, `[]`, "minItems # #"},
+		{`{"minItems": 1, "maxItems": 2}`, `[1, 2, 3]`, "maxItems # #"},
+		{`{"uniqueItems": true}`, `[1, {"a": 1}, {"a": 1}]`, "uniqueItems # #"},
+
+		// Objects.
+		{`{"properties": {"a": {"type": "integer"}}}`, `{"a": 1, "b": "x"}`, ""},
+		{`{"properties": {"a/b": {"type": "integer"}}}`, `{"a/b": "x"}`, "type #/properties/a~1b #/a~1b"},
+		{`{"properties": {"a": {"type": "integer"}}, "additionalProperties": false}`, `{"a": 1, "b": "x"}`, "additionalProperties # #"},
+		{`{"patternProperties": {"^x_": {"type": "integer"}}, "additionalProperties": {"type": "string"}}`, `{"x_a": 1, "b": "x"}`, ""},
+		{`{"patternProperties": {"^x_": {"type": "integer"}}}`, `{"x_a": "1"}`, "type #/patternProperties/^x_ #/x_a"},
+		{`{"required": ["a", "b"]}`, `{"a": 1}`, "required # #"},
+		{`{"minProperties": 2}`, `{"a": 1}`, "minProperties # #"},
+		{`{"maxProperties": 1}`, `{"a": 1, "b": 2}`, "maxProperties # #"},
+		{`{"dependencies": {"a": ["b"]}}`, `{"a": 1}`, "dependencies # #"},
+		{`{"dependencies": {"a": ["b"]}}`, `{"b": 1}`, ""},
+		{`{"dependencies": {"a": {"required": ["c"]}}}`, `{"a": 1, "c": 2}`, ""},
+		{`{"properties": {"p": {"properties": {"q": {"maximum": 1}}}}}`, `{"p": {"q": 2}}`, "maximum #/properties/p/properties/q #/p/q"},
+
+		// Combinators.
+		{`{"allOf": [{"type": "integer"}, {"minimum": 2}]}`, `1`, "allOf # #"},
+		{`{"anyOf": [{"type": "integer"}, {"type": "string"}]}`, `"a"`, ""},
+		{`{"anyOf": [{"type": "integer"}, {"type": "string"}]}`, `null`, "anyOf # #"},
+		{`{"oneOf": [{"type": "integer"}, {"minimum": 2}]}`, `3`, "oneOf # #"},
+		{`{"oneOf": [{"type": "integer"}, {"minimum": 2}]}`, `2.5`, ""},
+		{`{"not": {"type": "null"}}`, `null`, "not # #"},
+
+		// References.
+		{`{"definitions": {"pos": {"minimum": 0}}, "items": {"$ref": "#/definitions/pos"}}`, `[1, -1]`, "minimum #/definitions/pos #/1"},
+		{`{"properties": {"child": {"$ref": "#"}}, "required": ["id"]}`, `{"id": 1, "child": {"child": {}}}`, "required # #/child/child"},
+		{`{"anyOf": [{"$ref": "#"}]}`, `1`, ""},
+		{`{"$ref": "#/definitions/missing"}`, `1`, ""},
+	}
+	for _, tt := range tests {
+		schema, err := ParseBinaryFromString(tt.schema)
+		c.Assert(err, IsNil)
+		doc, err := ParseBinaryFromString(tt.doc)
+		c.Assert(err, IsNil)
+		failure, err := ValidateSchema(schema, doc)
+		c.Assert(err, IsNil)
+		comment := Commentf("schema %s, document %s", tt.schema, tt.doc)
+		if tt.failure == "" {
+			c.Assert(failure, IsNil, comment)
+			continue
+		}
+		c.Assert(failure, NotNil, comment)
+		c.Assert(failure.FailedKeyword+" "+failure.SchemaLocation+" "+failure.DocumentLocation, Equals, tt.failure, comment)
+	}
+
+	// The remote references are not supported.
+	schema, err := ParseBinaryFromString(`{"$ref": "http://json-schema.org/draft-04/schema#"}`)
+	c.Assert(err, IsNil)
+	_, err = ValidateSchema(schema, CreateBinary(int64(1)))
+	c.Assert(ErrUnsupportedJSONSchema.Equal(err), IsTrue)
+}
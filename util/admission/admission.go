@@ -0,0 +1,275 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/shirou/gopsutil/process"
+	atomicutil "go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+const (
+	// sampleInterval is the interval to sample the CPU usage and to admit the queued statements.
+	sampleInterval = 100 * time.Millisecond
+	// usageSmoothFactor is the weight of the latest sample in the moving average of the CPU usage, it avoids queuing
+	// the statements because of a short spike.
+	usageSmoothFactor = 0.5
+)
+
+type priorityClass int
+
+const (
+	priorityHigh priorityClass = iota
+	priorityNormal
+	priorityLow
+	priorityClassCount
+)
+
+var priorityClassLabels = [priorityClassCount]string{"high", "normal", "low"}
+
+func toPriorityClass(priority mysql.PriorityEnum) priorityClass {
+	switch priority {
+	case mysql.HighPriority:
+		return priorityHigh
+	case mysql.LowPriority, mysql.DelayedPriority:
+		return priorityLow
+	default:
+		return priorityNormal
+	}
+}
+
+type waiter struct {
+	class    priorityClass
+	admitted chan struct{}
+	// elem is nil once the waiter is admitted or removed from the queue.
+	elem *list.Element
+}
+
+// Controller queues the new statement executions by their priorities when the CPU usage of the instance exceeds
+// variable.AdmissionCPUThreshold. Once the usage falls below the threshold, the new statements are admitted
+// immediately and the queued ones are admitted in batches at the next samples, the higher priority ones first.
+type Controller struct {
+	// cpuTime returns the total CPU time used by the process.
+	cpuTime func() (time.Duration, error)
+	// usage is the smoothed CPU usage of the process, 1 means all the GOMAXPROCS CPUs are busy.
+	usage *atomicutil.Float64
+
+	mu     sync.Mutex
+	queues [priorityClassCount]*list.List
+
+	exitCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewController creates a Controller which samples the CPU usage with cpuTime.
+func NewController(cpuTime func() (time.Duration, error)) *Controller {
+	c := &Controller{
+		cpuTime: cpuTime,
+		usage:   atomicutil.NewFloat64(0),
+	}
+	for i := range c.queues {
+		c.queues[i] = list.New()
+	}
+	return c
+}
+
+// GlobalController is the admission controller of the instance.
+var GlobalController = NewController(processCPUTime)
+
+// Setup starts the sampler of the global admission controller.
+func Setup() {
+	GlobalController.Run()
+}
+
+// Close stops the sampler of the global admission controller.
+func Close() {
+	GlobalController.Stop()
+}
+
+// Admit waits until the statement of the priority is admitted by the global admission controller.
+func Admit(ctx context.Context, priority mysql.PriorityEnum, killed *uint32) time.Duration {
+	return GlobalController.Admit(ctx, priority, killed)
+}
+
+func processCPUTime() (time.Duration, error) {
+	p, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		return 0, err
+	}
+	times, err := p.Times()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration((times.User + times.System) * float64(time.Second)), nil
+}
+
+// Run starts the goroutine to sample the CPU usage and to admit the queued statements.
+func (c *Controller) Run() {
+	c.exitCh = make(chan struct{})
+	c.wg.Add(1)
+	go c.run()
+}
+
+// Stop stops the goroutine started by Run, all the queued statements are admitted.
+func (c *Controller) Stop() {
+	if c.exitCh == nil {
+		return
+	}
+	close(c.exitCh)
+	c.wg.Wait()
+	c.exitCh = nil
+	c.admit(-1)
+}
+
+func (c *Controller) run() {
+	defer c.wg.Done()
+	ticker := time.NewTicker(sampleInterval)
+	defer ticker.Stop()
+	lastCPUTime, err := c.cpuTime()
+	if err != nil {
+		logutil.BgLogger().Warn("sample the CPU time for admission control failed", zap.Error(err))
+	}
+	lastTime := time.Now()
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now()
+			cpuTime, err := c.cpuTime()
+			if err != nil {
+				logutil.BgLogger().Warn("sample the CPU time for admission control failed", zap.Error(err))
+				continue
+			}
+			if elapsed := now.Sub(lastTime); elapsed > 0 {
+				sample := float64(cpuTime-lastCPUTime) / float64(elapsed) / float64(runtime.GOMAXPROCS(0))
+				c.usage.Store(usageSmoothFactor*sample + (1-usageSmoothFactor)*c.usage.Load())
+			}
+			lastCPUTime, lastTime = cpuTime, now
+			c.dispatch()
+		case <-c.exitCh:
+			return
+		}
+	}
+}
+
+// dispatch admits a batch of the queued statements if the CPU usage is below the threshold, or all of them if the
+// admission control is disabled. A batch allows a statement per CPU below the threshold, at least one, the usage is
+// sampled again before admitting the next batch so the CPU is not saturated again by the queued statements at once.
+func (c *Controller) dispatch() {
+	threshold := variable.AdmissionCPUThreshold.Load()
+	if threshold <= 0 {
+		c.admit(-1)
+		return
+	}
+	if usage := c.usage.Load(); usage <= threshold {
+		c.admit(batchSize(threshold, usage, runtime.GOMAXPROCS(0)))
+	}
+}
+
+// batchSize returns the number of the queued statements admitted at a sample, which is the number of the idle CPUs
+// below the threshold, at least one.
+func batchSize(threshold, usage float64, cpus int) int {
+	n := int(math.Ceil((threshold - usage) * float64(cpus)))
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// admit admits at most n queued statements in the order of the priority, n < 0 means all of them.
+func (c *Controller) admit(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for class := priorityHigh; class < priorityClassCount; class++ {
+		for q := c.queues[class]; q.Len() > 0 && n != 0; n-- {
+			w := q.Front().Value.(*waiter)
+			c.removeLocked(w)
+			close(w.admitted)
+		}
+	}
+}
+
+func (c *Controller) removeLocked(w *waiter) {
+	if w.elem == nil {
+		return
+	}
+	c.queues[w.class].Remove(w.elem)
+	w.elem = nil
+	metrics.AdmissionQueueGauge.WithLabelValues(priorityClassLabels[w.class]).Dec()
+}
+
+// Admit waits until the statement of the priority is admitted, and returns the time it's queued. The statement is
+// only queued if the CPU usage exceeds the threshold, so it never waits for the queued ones when there's capacity. It
+// stops waiting once variable.AdmissionMaxQueueTime is exceeded, ctx is done or the statement is killed, the caller
+// should check the latter two.
+func (c *Controller) Admit(ctx context.Context, priority mysql.PriorityEnum, killed *uint32) time.Duration {
+	threshold := variable.AdmissionCPUThreshold.Load()
+	if threshold <= 0 {
+		return 0
+	}
+	if c.usage.Load() <= threshold {
+		return 0
+	}
+	w := &waiter{class: toPriorityClass(priority), admitted: make(chan struct{})}
+	c.mu.Lock()
+	w.elem = c.queues[w.class].PushBack(w)
+	metrics.AdmissionQueueGauge.WithLabelValues(priorityClassLabels[w.class]).Inc()
+	c.mu.Unlock()
+
+	start := time.Now()
+	var timeout <-chan time.Time
+	if maxQueueTime := variable.AdmissionMaxQueueTime.Load(); maxQueueTime > 0 {
+		timer := time.NewTimer(time.Duration(maxQueueTime) * time.Millisecond)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	ticker := time.NewTicker(sampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.admitted:
+			return time.Since(start)
+		case <-timeout:
+			// Admit the statement anyway to bound its latency.
+		case <-ctx.Done():
+		case <-ticker.C:
+			if atomic.LoadUint32(killed) != 1 {
+				continue
+			}
+		}
+		c.mu.Lock()
+		c.removeLocked(w)
+		c.mu.Unlock()
+		return time.Since(start)
+	}
+}
+
+// QueueDepth returns the number of the queued statements of the priority.
+func (c *Controller) QueueDepth(priority mysql.PriorityEnum) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.queues[toPriorityClass(priority)].Len()
+}
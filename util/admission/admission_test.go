@@ -0,0 +1,158 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+type testAdmissionSuite struct{}
+
+var _ = Suite(&testAdmissionSuite{})
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+func (s *testAdmissionSuite) SetUpTest(c *C) {
+	variable.AdmissionCPUThreshold.Store(0.5)
+	variable.AdmissionMaxQueueTime.Store(0)
+}
+
+func (s *testAdmissionSuite) TearDownTest(c *C) {
+	variable.AdmissionCPUThreshold.Store(variable.DefTiDBAdmissionCPUThreshold)
+	variable.AdmissionMaxQueueTime.Store(variable.DefTiDBAdmissionMaxQueueTime)
+}
+
+func waitQueueDepth(c *C, ctl *Controller, priority mysql.PriorityEnum, depth int) {
+	for i := 0; i < 100; i++ {
+		if ctl.QueueDepth(priority) == depth {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Fatalf("the queue depth of %v is %d, expected %d", priority, ctl.QueueDepth(priority), depth)
+}
+
+func (s *testAdmissionSuite) TestAdmitByPriority(c *C) {
+	defer testleak.AfterTest(c)()
+	ctl := NewController(nil)
+	var killed uint32
+	ctx := context.Background()
+
+	ctl.usage.Store(0.9)
+	variable.AdmissionCPUThreshold.Store(0)
+	c.Assert(ctl.Admit(ctx, mysql.LowPriority, &killed), Equals, time.Duration(0))
+	variable.AdmissionCPUThreshold.Store(0.5)
+
+	var wg sync.WaitGroup
+	admitted := make(chan mysql.PriorityEnum, 4)
+	for _, priority := range []mysql.PriorityEnum{mysql.LowPriority, mysql.NoPriority, mysql.HighPriority} {
+		wg.Add(1)
+		go func(priority mysql.PriorityEnum) {
+			defer wg.Done()
+			c.Assert(ctl.Admit(ctx, priority, &killed) > 0, IsTrue)
+			admitted <- priority
+		}(priority)
+		waitQueueDepth(c, ctl, priority, 1)
+	}
+
+	// Nothing is admitted while the CPU is saturated.
+	ctl.dispatch()
+	c.Assert(ctl.QueueDepth(mysql.HighPriority), Equals, 1)
+
+	ctl.usage.Store(0.1)
+	ctl.admit(1)
+	c.Assert(<-admitted, Equals, mysql.HighPriority)
+	c.Assert(ctl.QueueDepth(mysql.NoPriority), Equals, 1)
+	// A new statement doesn't wait for the queued ones when there's capacity.
+	c.Assert(ctl.Admit(ctx, mysql.LowPriority, &killed), Equals, time.Duration(0))
+	c.Assert(ctl.QueueDepth(mysql.LowPriority), Equals, 1)
+
+	// A sample at the threshold admits a single statement, the higher priority one first.
+	ctl.usage.Store(0.5)
+	ctl.dispatch()
+	c.Assert(<-admitted, Equals, mysql.NoPriority)
+	c.Assert(ctl.QueueDepth(mysql.LowPriority), Equals, 1)
+	ctl.dispatch()
+	c.Assert(<-admitted, Equals, mysql.LowPriority)
+	wg.Wait()
+	c.Assert(ctl.QueueDepth(mysql.LowPriority), Equals, 0)
+}
+
+func (s *testAdmissionSuite) TestBatchSize(c *C) {
+	c.Assert(batchSize(0.5, 0.1, 10), Equals, 4)
+	c.Assert(batchSize(0.8, 0.1, 4), Equals, 3)
+	c.Assert(batchSize(0.5, 0.49, 1), Equals, 1)
+	c.Assert(batchSize(0.5, 0.5, 16), Equals, 1)
+}
+
+func (s *testAdmissionSuite) TestStopWaiting(c *C) {
+	defer testleak.AfterTest(c)()
+	ctl := NewController(nil)
+	ctl.usage.Store(0.9)
+	var killed uint32
+
+	variable.AdmissionMaxQueueTime.Store(100)
+	queueTime := ctl.Admit(context.Background(), mysql.NoPriority, &killed)
+	c.Assert(queueTime >= 100*time.Millisecond, IsTrue)
+	c.Assert(ctl.QueueDepth(mysql.NoPriority), Equals, 0)
+	variable.AdmissionMaxQueueTime.Store(0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	c.Assert(ctl.Admit(ctx, mysql.NoPriority, &killed) > 0, IsTrue)
+	c.Assert(ctx.Err(), NotNil)
+	c.Assert(ctl.QueueDepth(mysql.NoPriority), Equals, 0)
+
+	killed = 1
+	c.Assert(ctl.Admit(context.Background(), mysql.NoPriority, &killed) > 0, IsTrue)
+	c.Assert(ctl.QueueDepth(mysql.NoPriority), Equals, 0)
+}
+
+func (s *testAdmissionSuite) TestSampleCPUUsage(c *C) {
+	defer testleak.AfterTest(c)()
+	start := time.Now()
+	// All the CPUs are busy.
+	ctl := NewController(func() (time.Duration, error) {
+		return time.Since(start) * time.Duration(runtime.GOMAXPROCS(0)), nil
+	})
+	ctl.Run()
+	for i := 0; i < 100 && ctl.usage.Load() <= 0.5; i++ {
+		time.Sleep(sampleInterval)
+	}
+	c.Assert(ctl.usage.Load() > 0.5, IsTrue)
+
+	var killed uint32
+	done := make(chan time.Duration)
+	go func() {
+		done <- ctl.Admit(context.Background(), mysql.NoPriority, &killed)
+	}()
+	waitQueueDepth(c, ctl, mysql.NoPriority, 1)
+	// The queued statements are admitted once the controller is stopped.
+	ctl.Stop()
+	c.Assert(<-done > 0, IsTrue)
+
+	_, err := processCPUTime()
+	c.Assert(err, IsNil)
+}
@@ -535,6 +535,19 @@ func (c *Chunk) Append(other *Chunk, begin, end int) {
 	c.numVirtualRows += end - begin
 }
 
+// AppendBySel appends the rows whose indexes are in sel in another Chunk to a Chunk.
+// The consecutive rows in sel are appended by Append in one batch rather than one by one.
+func (c *Chunk) AppendBySel(other *Chunk, sel []int) {
+	for begin := 0; begin < len(sel); {
+		end := begin + 1
+		for end < len(sel) && sel[end] == sel[end-1]+1 {
+			end++
+		}
+		c.Append(other, sel[begin], sel[end-1]+1)
+		begin = end
+	}
+}
+
 // TruncateTo truncates rows from tail to head in a Chunk to "numRows" rows.
 func (c *Chunk) TruncateTo(numRows int) {
 	c.Reconstruct()
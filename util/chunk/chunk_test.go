@@ -154,6 +154,28 @@ func (s *testChunkSuite) TestChunk(c *check.C) {
 	c.Assert(chk.GetRow(2).GetFloat32(0), check.Equals, float32(1))
 }
 
+func (s *testChunkSuite) TestAppendBySel(c *check.C) {
+	fieldTypes := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong), types.NewFieldType(mysql.TypeVarchar)}
+	src := NewChunkWithCapacity(fieldTypes, 32)
+	for i := 0; i < 10; i++ {
+		src.AppendInt64(0, int64(i))
+		src.AppendString(1, strconv.Itoa(i))
+	}
+	src.AppendNull(0)
+	src.AppendNull(1)
+
+	dst := NewChunkWithCapacity(fieldTypes, 32)
+	dst.AppendBySel(src, []int{0, 2, 3, 4, 7, 9, 10})
+	c.Assert(dst.NumRows(), check.Equals, 7)
+	for i, idx := range []int{0, 2, 3, 4, 7, 9} {
+		row := dst.GetRow(i)
+		c.Assert(row.GetInt64(0), check.Equals, int64(idx))
+		c.Assert(row.GetString(1), check.Equals, strconv.Itoa(idx))
+	}
+	c.Assert(dst.GetRow(6).IsNull(0), check.IsTrue)
+	c.Assert(dst.GetRow(6).IsNull(1), check.IsTrue)
+}
+
 func (s *testChunkSuite) TestAppend(c *check.C) {
 	fieldTypes := make([]*types.FieldType, 0, 3)
 	fieldTypes = append(fieldTypes, &types.FieldType{Tp: mysql.TypeFloat})
@@ -1012,3 +1012,43 @@ func BenchmarkMergeNullsNonVectorized(b *testing.B) {
 		}
 	}
 }
+
+func (s *testChunkSuite) TestFilterKernels(c *check.C) {
+	intCol := NewColumn(types.NewFieldType(mysql.TypeLonglong), 16)
+	realCol := NewColumn(types.NewFieldType(mysql.TypeDouble), 16)
+	for i := 0; i < 10; i++ {
+		if i == 5 {
+			intCol.AppendNull()
+			realCol.AppendNull()
+			continue
+		}
+		intCol.AppendInt64(int64(i))
+		realCol.AppendFloat64(float64(i) / 2)
+	}
+	allRows := func() []int { return []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9} }
+	tests := []struct {
+		op       CompareOp
+		int64Val int64
+		realVal  float64
+		expected []int
+	}{
+		{CompareLT, 3, 1.5, []int{0, 1, 2, 5}},
+		{CompareLE, 3, 1.5, []int{0, 1, 2, 3, 5}},
+		{CompareGT, 7, 3.5, []int{5, 8, 9}},
+		{CompareGE, 7, 3.5, []int{5, 7, 8, 9}},
+		{CompareEQ, 4, 2, []int{4, 5}},
+		{CompareNE, 4, 2, []int{0, 1, 2, 3, 5, 6, 7, 8, 9}},
+	}
+	for _, t := range tests {
+		nulls := make([]bool, 10)
+		c.Assert(intCol.FilterInt64(t.op, t.int64Val, allRows(), nulls), check.DeepEquals, t.expected)
+		c.Assert(nulls[5], check.IsTrue)
+		nulls = make([]bool, 10)
+		c.Assert(realCol.FilterFloat64(t.op, t.realVal, allRows(), nulls), check.DeepEquals, t.expected)
+		c.Assert(nulls[5], check.IsTrue)
+	}
+	// Only the rows in sel are filtered.
+	c.Assert(intCol.FilterInt64(CompareLT, 3, []int{1, 3, 7}, make([]bool, 10)), check.DeepEquals, []int{1})
+	c.Assert(CompareLT.Reverse(), check.Equals, CompareGT)
+	c.Assert(CompareEQ.Reverse(), check.Equals, CompareEQ)
+}
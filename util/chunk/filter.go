@@ -0,0 +1,168 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chunk
+
+// CompareOp is the comparison operator used by the fixed-width filter kernels. The kernels are plain Go loops
+// which the compiler doesn't vectorize, they are not implemented by assembly or SIMD instructions.
+type CompareOp int
+
+const (
+	// CompareLT means `value < constant`.
+	CompareLT CompareOp = iota
+	// CompareLE means `value <= constant`.
+	CompareLE
+	// CompareGT means `value > constant`.
+	CompareGT
+	// CompareGE means `value >= constant`.
+	CompareGE
+	// CompareEQ means `value = constant`.
+	CompareEQ
+	// CompareNE means `value != constant`.
+	CompareNE
+)
+
+// Reverse returns the operator got by swapping the two sides of the comparison,
+// e.g. `constant < value` is the same as `value > constant`.
+func (op CompareOp) Reverse() CompareOp {
+	switch op {
+	case CompareLT:
+		return CompareGT
+	case CompareLE:
+		return CompareGE
+	case CompareGT:
+		return CompareLT
+	case CompareGE:
+		return CompareLE
+	}
+	return op
+}
+
+// FilterInt64 filters the rows in sel by comparing the int64 values of the Column with v in place, and returns the
+// remaining rows. The rows with null values are kept and marked in nulls, so the caller can decide how to treat them.
+// The values are read from the underlying slice directly, so it doesn't copy or evaluate the rows one by one.
+func (c *Column) FilterInt64(op CompareOp, v int64, sel []int, nulls []bool) []int {
+	vals := c.Int64s()
+	hasNull := c.nullCount() > 0
+	j := 0
+	switch op {
+	case CompareLT:
+		for _, i := range sel {
+			if vals[i] < v || hasNull && c.IsNull(i) {
+				sel[j] = i
+				j++
+			}
+		}
+	case CompareLE:
+		for _, i := range sel {
+			if vals[i] <= v || hasNull && c.IsNull(i) {
+				sel[j] = i
+				j++
+			}
+		}
+	case CompareGT:
+		for _, i := range sel {
+			if vals[i] > v || hasNull && c.IsNull(i) {
+				sel[j] = i
+				j++
+			}
+		}
+	case CompareGE:
+		for _, i := range sel {
+			if vals[i] >= v || hasNull && c.IsNull(i) {
+				sel[j] = i
+				j++
+			}
+		}
+	case CompareEQ:
+		for _, i := range sel {
+			if vals[i] == v || hasNull && c.IsNull(i) {
+				sel[j] = i
+				j++
+			}
+		}
+	case CompareNE:
+		for _, i := range sel {
+			if vals[i] != v || hasNull && c.IsNull(i) {
+				sel[j] = i
+				j++
+			}
+		}
+	}
+	return c.markNulls(sel[:j], nulls, hasNull)
+}
+
+// FilterFloat64 is like FilterInt64, but compares the float64 values of the Column.
+func (c *Column) FilterFloat64(op CompareOp, v float64, sel []int, nulls []bool) []int {
+	vals := c.Float64s()
+	hasNull := c.nullCount() > 0
+	j := 0
+	switch op {
+	case CompareLT:
+		for _, i := range sel {
+			if vals[i] < v || hasNull && c.IsNull(i) {
+				sel[j] = i
+				j++
+			}
+		}
+	case CompareLE:
+		for _, i := range sel {
+			if vals[i] <= v || hasNull && c.IsNull(i) {
+				sel[j] = i
+				j++
+			}
+		}
+	case CompareGT:
+		for _, i := range sel {
+			if vals[i] > v || hasNull && c.IsNull(i) {
+				sel[j] = i
+				j++
+			}
+		}
+	case CompareGE:
+		for _, i := range sel {
+			if vals[i] >= v || hasNull && c.IsNull(i) {
+				sel[j] = i
+				j++
+			}
+		}
+	case CompareEQ:
+		for _, i := range sel {
+			if vals[i] == v || hasNull && c.IsNull(i) {
+				sel[j] = i
+				j++
+			}
+		}
+	case CompareNE:
+		for _, i := range sel {
+			if vals[i] != v || hasNull && c.IsNull(i) {
+				sel[j] = i
+				j++
+			}
+		}
+	}
+	return c.markNulls(sel[:j], nulls, hasNull)
+}
+
+// markNulls marks the null rows in sel, which are kept by the filter kernels, in nulls.
+func (c *Column) markNulls(sel []int, nulls []bool, hasNull bool) []int {
+	if !hasNull {
+		return sel
+	}
+	for _, i := range sel {
+		if c.IsNull(i) {
+			nulls[i] = true
+		}
+	}
+	return sel
+}
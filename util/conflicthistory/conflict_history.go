@@ -0,0 +1,163 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conflicthistory
+
+import (
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+// ConflictRecord is a write conflict met by an optimistic transaction when committing.
+type ConflictRecord struct {
+	OccurTime    time.Time
+	StartTS      uint64
+	ConnectionID uint64
+	// Key is the conflicting key, and KeyInfo is the key decoded into the table, index and row info.
+	Key     []byte
+	KeyInfo string
+	// ConflictStartTS and ConflictCommitTS are the timestamps of the transaction which has written the key.
+	ConflictStartTS  uint64
+	ConflictCommitTS uint64
+	// ConflictSQLDigests are the digests of the statements executed by the conflicting transaction, they are only
+	// known if the transaction is committed by this instance recently.
+	ConflictSQLDigests []string
+}
+
+// ConflictHistory is a collection for maintaining the recent write conflicts. All its public APIs are thread safe.
+type ConflictHistory struct {
+	sync.RWMutex
+
+	records []*ConflictRecord
+
+	// The `head` and `size` makes the `records` array behaves like a ring buffer. The valid elements are
+	// records[head:head+size], or records[head:] + records[:head+size-len] if `head+size` exceeds the array's length.
+	head int
+	size int
+}
+
+// NewConflictHistory creates an instance of ConflictHistory.
+func NewConflictHistory(capacity uint) *ConflictHistory {
+	return &ConflictHistory{
+		records: make([]*ConflictRecord, capacity),
+	}
+}
+
+// GlobalConflictHistory is the global instance of ConflictHistory, which is used to maintain the recent write
+// conflicts globally.
+// The real capacity should be initialized with `Resize` in `setGlobalVars` in tidb-server/main.go
+var GlobalConflictHistory = NewConflictHistory(0)
+
+// Resize updates the max capacity of the ConflictHistory to newCapacity, the most recent records are kept.
+func (h *ConflictHistory) Resize(newCapacity uint) {
+	h.Lock()
+	defer h.Unlock()
+	if newCapacity != uint(len(h.records)) {
+		current := h.getAll()
+		h.head = 0
+		if uint(len(current)) < newCapacity {
+			h.records = make([]*ConflictRecord, newCapacity)
+			copy(h.records, current)
+		} else {
+			// Use append here to force golang to realloc the underlying array to save memory.
+			h.records = append([]*ConflictRecord{}, current[uint(len(current))-newCapacity:]...)
+			h.size = int(newCapacity)
+		}
+	}
+}
+
+// Push adds a write conflict, the oldest record is evicted if the collection is full. Be aware that do not modify
+// the record's content after pushing.
+func (h *ConflictHistory) Push(record *ConflictRecord) {
+	h.Lock()
+	defer h.Unlock()
+
+	capacity := len(h.records)
+	if capacity == 0 {
+		return
+	}
+	if h.size == capacity {
+		// The current head is evicted and its cell becomes the latest pushed item.
+		h.records[h.head] = record
+		h.head = (h.head + 1) % capacity
+	} else {
+		h.records[(h.head+h.size)%capacity] = record
+		h.size++
+	}
+}
+
+// GetAll gets all the recorded write conflicts, ordered from the oldest to the latest.
+func (h *ConflictHistory) GetAll() []*ConflictRecord {
+	h.RLock()
+	defer h.RUnlock()
+	return h.getAll()
+}
+
+// getAll is a thread unsafe version of GetAll() for internal use.
+func (h *ConflictHistory) getAll() []*ConflictRecord {
+	res := make([]*ConflictRecord, 0, h.size)
+	capacity := len(h.records)
+	if h.head+h.size <= capacity {
+		res = append(res, h.records[h.head:h.head+h.size]...)
+	} else {
+		res = append(res, h.records[h.head:]...)
+		res = append(res, h.records[:(h.head+h.size)%capacity]...)
+	}
+	return res
+}
+
+// GetAllDatum gets all the recorded write conflicts, and makes them into datum that matches the definition of the
+// table `INFORMATION_SCHEMA.WRITE_CONFLICTS`.
+func (h *ConflictHistory) GetAllDatum() [][]types.Datum {
+	records := h.GetAll()
+	rows := make([][]types.Datum, 0, len(records))
+	for _, rec := range records {
+		var key, keyInfo, sqlDigests interface{}
+		if len(rec.Key) > 0 {
+			key = strings.ToUpper(hex.EncodeToString(rec.Key))
+		}
+		if len(rec.KeyInfo) > 0 {
+			keyInfo = rec.KeyInfo
+		}
+		if len(rec.ConflictSQLDigests) > 0 {
+			sqlDigests = "[" + strings.Join(rec.ConflictSQLDigests, ", ") + "]"
+		}
+		rows = append(rows, types.MakeDatums(
+			types.NewTime(types.FromGoTime(rec.OccurTime), mysql.TypeTimestamp, types.MaxFsp),
+			rec.StartTS,
+			rec.ConnectionID,
+			key,
+			keyInfo,
+			rec.ConflictStartTS,
+			rec.ConflictCommitTS,
+			sqlDigests,
+		))
+	}
+	return rows
+}
+
+// Clear clears all the recorded write conflicts.
+func (h *ConflictHistory) Clear() {
+	h.Lock()
+	defer h.Unlock()
+	for i := 0; i < len(h.records); i++ {
+		h.records[i] = nil
+	}
+	h.head = 0
+	h.size = 0
+}
@@ -0,0 +1,92 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conflicthistory
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+)
+
+type testConflictHistorySuite struct{}
+
+var _ = Suite(&testConflictHistorySuite{})
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+func (s *testConflictHistorySuite) TestConflictHistoryCollection(c *C) {
+	h := NewConflictHistory(0)
+	h.Push(&ConflictRecord{StartTS: 1})
+	c.Assert(len(h.GetAll()), Equals, 0)
+
+	h.Resize(2)
+	records := make([]*ConflictRecord, 0, 3)
+	for i := 1; i <= 3; i++ {
+		rec := &ConflictRecord{StartTS: uint64(i)}
+		records = append(records, rec)
+		h.Push(rec)
+	}
+	res := h.GetAll()
+	c.Assert(len(res), Equals, 2)
+	// The oldest record is evicted.
+	c.Assert(res[0], Equals, records[1])
+	c.Assert(res[1], Equals, records[2])
+
+	// The most recent records are kept when shrinking.
+	h.Resize(1)
+	res = h.GetAll()
+	c.Assert(len(res), Equals, 1)
+	c.Assert(res[0], Equals, records[2])
+
+	h.Clear()
+	c.Assert(len(h.GetAll()), Equals, 0)
+}
+
+func (s *testConflictHistorySuite) TestGetAllDatum(c *C) {
+	h := NewConflictHistory(2)
+	occurTime := time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)
+	h.Push(&ConflictRecord{
+		OccurTime:          occurTime,
+		StartTS:            101,
+		ConnectionID:       1,
+		Key:                []byte("k1"),
+		KeyInfo:            "{tableID=1, tableName=test.t, handle=1}",
+		ConflictStartTS:    102,
+		ConflictCommitTS:   103,
+		ConflictSQLDigests: []string{"digest1", "digest2"},
+	})
+	h.Push(&ConflictRecord{
+		OccurTime:        occurTime,
+		StartTS:          201,
+		ConflictStartTS:  202,
+		ConflictCommitTS: 203,
+	})
+
+	rows := h.GetAllDatum()
+	c.Assert(rows, HasLen, 2)
+	c.Assert(rows[0][1].GetUint64(), Equals, uint64(101))
+	c.Assert(rows[0][2].GetUint64(), Equals, uint64(1))
+	c.Assert(rows[0][3].GetString(), Equals, "6B31")
+	c.Assert(rows[0][4].GetString(), Equals, "{tableID=1, tableName=test.t, handle=1}")
+	c.Assert(rows[0][5].GetUint64(), Equals, uint64(102))
+	c.Assert(rows[0][6].GetUint64(), Equals, uint64(103))
+	c.Assert(rows[0][7].GetString(), Equals, "[digest1, digest2]")
+	// The unknown info is NULL.
+	c.Assert(rows[1][3].IsNull(), IsTrue)
+	c.Assert(rows[1][4].IsNull(), IsTrue)
+	c.Assert(rows[1][7].IsNull(), IsTrue)
+}
@@ -34,6 +34,8 @@ var (
 const (
 	lockFile  = "_dir.lock"
 	recordDir = "record"
+	// RegionCacheSnapshotFile persists the recently accessed tables to warm up the region cache after restarts.
+	RegionCacheSnapshotFile = "region_cache_snapshot.json"
 )
 
 // CheckAndInitTempDir check whether the temp directory is existed.
@@ -91,7 +93,7 @@ func InitializeTempDir() error {
 			for _, subDir := range subDirs {
 				// Do not remove the lock file.
 				switch subDir.Name() {
-				case lockFile, recordDir:
+				case lockFile, recordDir, RegionCacheSnapshotFile:
 					continue
 				}
 				err := os.RemoveAll(filepath.Join(tempDir, subDir.Name()))
@@ -54,6 +54,112 @@ type StmtExecDetails struct {
 	WriteSQLRespDuration time.Duration
 }
 
+type stmtRPCStatsKeyType struct{}
+
+// StmtRPCStatsKey used to carry StmtRPCStats info in context.Context.
+var StmtRPCStatsKey = stmtRPCStatsKeyType{}
+
+// RPCStats contains the number and the total time of one type of RPC requests.
+type RPCStats struct {
+	Count   int64
+	Consume time.Duration
+}
+
+// StmtRPCStats contains the RPC requests sent to the storage by a statement, grouped by the request type.
+type StmtRPCStats struct {
+	mu    sync.Mutex
+	stats map[string]*RPCStats
+}
+
+// NewStmtRPCStats creates a new StmtRPCStats.
+func NewStmtRPCStats() *StmtRPCStats {
+	return &StmtRPCStats{stats: make(map[string]*RPCStats)}
+}
+
+// Record records one RPC request of the type.
+func (s *StmtRPCStats) Record(tp string, d time.Duration) {
+	s.mu.Lock()
+	stat, ok := s.stats[tp]
+	if !ok {
+		stat = &RPCStats{}
+		s.stats[tp] = stat
+	}
+	stat.Count++
+	stat.Consume += d
+	s.mu.Unlock()
+}
+
+// Stats returns a copy of the recorded RPC stats.
+func (s *StmtRPCStats) Stats() map[string]RPCStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := make(map[string]RPCStats, len(s.stats))
+	for tp, stat := range s.stats {
+		stats[tp] = *stat
+	}
+	return stats
+}
+
+func (s *StmtRPCStats) format(sep string) string {
+	stats := s.Stats()
+	tps := make([]string, 0, len(stats))
+	for tp := range stats {
+		tps = append(tps, tp)
+	}
+	sort.Strings(tps)
+	buf := bytes.NewBuffer(make([]byte, 0, 32*len(tps)))
+	for i, tp := range tps {
+		if i > 0 {
+			buf.WriteString(",")
+			buf.WriteString(sep)
+		}
+		stat := stats[tp]
+		fmt.Fprintf(buf, "%s:{num_rpc:%d,%stotal_time:%s}", tp, stat.Count, sep, FormatDuration(stat.Consume))
+	}
+	return buf.String()
+}
+
+// String implements the RuntimeStats interface.
+func (s *StmtRPCStats) String() string {
+	return "rpc_stats:{" + s.format(" ") + "}"
+}
+
+// SlowLogString returns the RPC stats without spaces, which is used in the slow log.
+func (s *StmtRPCStats) SlowLogString() string {
+	return s.format("")
+}
+
+// Merge implements the RuntimeStats interface.
+func (s *StmtRPCStats) Merge(rs RuntimeStats) {
+	other, ok := rs.(*StmtRPCStats)
+	if !ok {
+		return
+	}
+	for tp, stat := range other.Stats() {
+		s.mu.Lock()
+		cur, ok := s.stats[tp]
+		if !ok {
+			cur = &RPCStats{}
+			s.stats[tp] = cur
+		}
+		cur.Count += stat.Count
+		cur.Consume += stat.Consume
+		s.mu.Unlock()
+	}
+}
+
+// Clone implements the RuntimeStats interface.
+func (s *StmtRPCStats) Clone() RuntimeStats {
+	newRs := NewStmtRPCStats()
+	newRs.Merge(s)
+	return newRs
+}
+
+// Tp implements the RuntimeStats interface.
+func (s *StmtRPCStats) Tp() int {
+	return TpStmtRPCStats
+}
+
 const (
 	// CopTimeStr represents the sum of cop-task time spend in TiDB distSQL.
 	CopTimeStr = "Cop_time"
@@ -344,6 +450,18 @@ func (crs *CopRuntimeStats) GetActRows() (totalRows int64) {
 	return totalRows
 }
 
+// GetTimeProcessed returns the total processing time of the coprocessor tasks reported by the storage.
+func (crs *CopRuntimeStats) GetTimeProcessed() (totalTime time.Duration) {
+	crs.Lock()
+	defer crs.Unlock()
+	for _, instanceStats := range crs.stats {
+		for _, stat := range instanceStats {
+			totalTime += time.Duration(stat.consume)
+		}
+	}
+	return totalTime
+}
+
 func (crs *CopRuntimeStats) String() string {
 	if len(crs.stats) == 0 {
 		return ""
@@ -424,6 +542,8 @@ const (
 	TpIndexMergeRunTimeStats
 	// TpBasicCopRunTimeStats is the tp for TpBasicCopRunTimeStats
 	TpBasicCopRunTimeStats
+	// TpStmtRPCStats is the tp for StmtRPCStats
+	TpStmtRPCStats
 )
 
 // RuntimeStats is used to express the executor runtime information.
@@ -489,6 +609,20 @@ func (e *RootRuntimeStats) GetActRows() int64 {
 	return num
 }
 
+// GetProcessTime returns the processing time of the coprocessor tasks reported by the storage. Unlike the time of
+// the executors, it excludes the time waiting in the queue of the storage, so it's close to the CPU time.
+func (e *RootRuntimeStats) GetProcessTime() time.Duration {
+	var processTime time.Duration
+	for _, rss := range e.groupRss {
+		for _, rs := range rss {
+			if getter, ok := rs.(interface{ GetProcessTime() time.Duration }); ok {
+				processTime += getter.GetProcessTime()
+			}
+		}
+	}
+	return processTime
+}
+
 // String implements the RuntimeStats interface.
 func (e *RootRuntimeStats) String() string {
 	buf := bytes.NewBuffer(make([]byte, 0, 32))
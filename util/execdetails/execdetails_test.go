@@ -286,6 +286,35 @@ func TestRootRuntimeStats(t *testing.T) {
 	}
 }
 
+func TestStmtRPCStats(t *testing.T) {
+	stats := NewStmtRPCStats()
+	if stats.SlowLogString() != "" {
+		t.Fatalf("%v != %v", stats.SlowLogString(), "")
+	}
+	stats.Record("Get", time.Millisecond)
+	stats.Record("Get", 2*time.Millisecond)
+	stats.Record("Cop", time.Second)
+	expect := "rpc_stats:{Cop:{num_rpc:1, total_time:1s}, Get:{num_rpc:2, total_time:3ms}}"
+	if stats.String() != expect {
+		t.Fatalf("%v != %v", stats.String(), expect)
+	}
+	expect = "Cop:{num_rpc:1,total_time:1s},Get:{num_rpc:2,total_time:3ms}"
+	if stats.SlowLogString() != expect {
+		t.Fatalf("%v != %v", stats.SlowLogString(), expect)
+	}
+
+	cloned := stats.Clone()
+	stats.Record("Prewrite", time.Millisecond)
+	cloned.Merge(stats)
+	expect = "rpc_stats:{Cop:{num_rpc:2, total_time:2s}, Get:{num_rpc:4, total_time:6ms}, Prewrite:{num_rpc:1, total_time:1ms}}"
+	if cloned.String() != expect {
+		t.Fatalf("%v != %v", cloned.String(), expect)
+	}
+	if cloned.Tp() != TpStmtRPCStats {
+		t.Fatalf("%v != %v", cloned.Tp(), TpStmtRPCStats)
+	}
+}
+
 func TestFormatDurationForExplain(t *testing.T) {
 	cases := []struct {
 		t string
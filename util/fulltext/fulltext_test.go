@@ -0,0 +1,103 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulltext
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+type testFullTextSuite struct{}
+
+var _ = Suite(&testFullTextSuite{})
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+func (s *testFullTextSuite) TestTokenizer(c *C) {
+	c.Assert(GetTokenizer("unknown"), IsNil)
+
+	standard := GetTokenizer("Standard")
+	c.Assert(standard.Tokenize("The quick-brown fox, is RUNNING_fast in 2021!"), DeepEquals,
+		[]string{"quick", "brown", "fox", "running_fast", "2021"})
+	c.Assert(standard.Tokenize(""), HasLen, 0)
+
+	ngram := GetTokenizer(TokenizerNgram)
+	c.Assert(ngram.Tokenize("分布式数据库 TiDB"), DeepEquals,
+		[]string{"分布", "布式", "式数", "数据", "据库", "ti", "id", "db"})
+	// The words shorter than the token size are ignored.
+	c.Assert(ngram.Tokenize("a 库"), HasLen, 0)
+}
+
+func (s *testFullTextSuite) TestParseQuery(c *C) {
+	standard := GetTokenizer(TokenizerStandard)
+	q := ParseQuery(standard, "database of the databases DATABASE", false)
+	c.Assert(q.BooleanMode, IsFalse)
+	c.Assert(q.Terms, DeepEquals, []Term{{Words: []string{"database"}}, {Words: []string{"databases"}}})
+
+	q = ParseQuery(standard, `+mysql -oracle data* "distributed database" ~ignored (grouped)`, true)
+	c.Assert(q.BooleanMode, IsTrue)
+	c.Assert(q.Terms, DeepEquals, []Term{
+		{Words: []string{"mysql"}, Required: true},
+		{Words: []string{"oracle"}, Excluded: true},
+		{Words: []string{"data"}, Prefix: true},
+		{Words: []string{"distributed", "database"}},
+		{Words: []string{"ignored"}},
+		{Words: []string{"grouped"}},
+	})
+
+	// The operator of an ignored word doesn't apply to the next word.
+	q = ParseQuery(standard, `+the database "unclosed phrase`, true)
+	c.Assert(q.Terms, DeepEquals, []Term{{Words: []string{"database"}}, {Words: []string{"unclosed", "phrase"}}})
+
+	// A word of several ngrams becomes a phrase.
+	q = ParseQuery(GetTokenizer(TokenizerNgram), "+数据库", true)
+	c.Assert(q.Terms, DeepEquals, []Term{{Words: []string{"数据", "据库"}, Required: true}})
+}
+
+func (s *testFullTextSuite) TestRelevance(c *C) {
+	standard := GetTokenizer(TokenizerStandard)
+	doc := standard.Tokenize("TiDB is a distributed database, the database is compatible with MySQL")
+	cases := []struct {
+		query       string
+		booleanMode bool
+		relevance   float64
+	}{
+		{"database", false, 2},
+		{"database mysql postgres", false, 3},
+		{"postgres", false, 0},
+		{"+database +mysql", true, 3},
+		{"+database +postgres", true, 0},
+		{"database -mysql", true, 0},
+		{"database -postgres", true, 2},
+		{"-postgres", true, 0},
+		{"data*", true, 2},
+		{"dist*", true, 1},
+		{`"distributed database"`, true, 1},
+		{`"database distributed"`, true, 0},
+		{`+"compatible mysql"`, true, 1},
+	}
+	for _, ca := range cases {
+		q := ParseQuery(standard, ca.query, ca.booleanMode)
+		c.Assert(q.Relevance(doc), Equals, ca.relevance, Commentf("query: %s", ca.query))
+	}
+
+	ngram := GetTokenizer(TokenizerNgram)
+	doc = ngram.Tokenize("分布式数据库")
+	c.Assert(ParseQuery(ngram, "数据库", false).Relevance(doc), Equals, float64(2))
+	c.Assert(ParseQuery(ngram, "+数据库", true).Relevance(doc), Equals, float64(1))
+	c.Assert(ParseQuery(ngram, "+数据仓库", true).Relevance(doc), Equals, float64(0))
+}
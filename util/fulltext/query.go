@@ -0,0 +1,146 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fulltext
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Term is a search term of the full-text search query.
+type Term struct {
+	// Words contains more than one word if the term is a phrase, which matches the words appearing contiguously in
+	// the same order.
+	Words []string
+	// Prefix indicates the last word matches the words beginning with it, which is written as `word*`.
+	Prefix bool
+	// Required indicates the matched documents must contain the term, which is written as `+word`.
+	Required bool
+	// Excluded indicates the matched documents must not contain the term, which is written as `-word`.
+	Excluded bool
+}
+
+// Query is the parsed search string of `MATCH ... AGAINST`.
+type Query struct {
+	BooleanMode bool
+	Terms       []Term
+}
+
+// ParseQuery parses the search string with the tokenizer. In the boolean mode, the `+`, `-`, `*` and `"` operators
+// are supported, the other operators like `>`, `<`, `~` and the parentheses are ignored.
+func ParseQuery(tokenizer Tokenizer, text string, booleanMode bool) *Query {
+	q := &Query{BooleanMode: booleanMode}
+	if !booleanMode {
+		seen := make(map[string]struct{})
+		for _, token := range tokenizer.Tokenize(text) {
+			if _, ok := seen[token]; ok {
+				continue
+			}
+			seen[token] = struct{}{}
+			q.Terms = append(q.Terms, Term{Words: []string{token}})
+		}
+		return q
+	}
+
+	var required, excluded bool
+	addTerm := func(term Term) {
+		if len(term.Words) > 0 {
+			term.Required, term.Excluded = required, excluded
+			q.Terms = append(q.Terms, term)
+		}
+		required, excluded = false, false
+	}
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r == '+':
+			required, excluded = true, false
+			i += size
+		case r == '-':
+			required, excluded = false, true
+			i += size
+		case r == '"':
+			end := strings.IndexByte(text[i+size:], '"')
+			if end < 0 {
+				end = len(text) - i - size
+			}
+			addTerm(Term{Words: tokenizer.Tokenize(text[i+size : i+size+end])})
+			i += size + end + 1
+		case isWordChar(r):
+			start := i
+			for i < len(text) {
+				r, size = utf8.DecodeRuneInString(text[i:])
+				if !isWordChar(r) {
+					break
+				}
+				i += size
+			}
+			word := text[start:i]
+			if i < len(text) && text[i] == '*' {
+				i++
+				// The prefix is not tokenized, so that the short prefixes still work.
+				addTerm(Term{Words: []string{strings.ToLower(word)}, Prefix: true})
+			} else {
+				addTerm(Term{Words: tokenizer.Tokenize(word)})
+			}
+		default:
+			i += size
+		}
+	}
+	return q
+}
+
+// Relevance returns the relevance of the tokenized document for the query, 0 means the document doesn't match.
+// The relevance is the total occurrences of the matched terms in the document.
+func (q *Query) Relevance(tokens []string) float64 {
+	var relevance float64
+	for i := range q.Terms {
+		term := &q.Terms[i]
+		cnt := term.occurrences(tokens)
+		if !q.BooleanMode {
+			relevance += float64(cnt)
+			continue
+		}
+		if (term.Excluded && cnt > 0) || (term.Required && cnt == 0) {
+			return 0
+		}
+		if !term.Excluded {
+			relevance += float64(cnt)
+		}
+	}
+	return relevance
+}
+
+func (t *Term) occurrences(tokens []string) int {
+	cnt := 0
+	for i := 0; i+len(t.Words) <= len(tokens); i++ {
+		if t.matchAt(tokens[i : i+len(t.Words)]) {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+func (t *Term) matchAt(tokens []string) bool {
+	last := len(t.Words) - 1
+	for i, word := range t.Words {
+		if i == last && t.Prefix {
+			return strings.HasPrefix(tokens[i], word)
+		}
+		if tokens[i] != word {
+			return false
+		}
+	}
+	return true
+}
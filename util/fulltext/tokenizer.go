@@ -0,0 +1,111 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fulltext tokenizes the texts and parses the search strings for `MATCH ... AGAINST`.
+//
+// There is no inverted index behind it: the terms are computed from the column values of every scanned row when
+// the query is evaluated, so a full-text search always reads the whole table or the rows left by other filters.
+package fulltext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// TokenizerStandard splits the text into words by the non-word characters, which is the same as the built-in
+	// full-text parser of MySQL.
+	TokenizerStandard = "standard"
+	// TokenizerNgram splits the words into the sequences of n contiguous characters, which is suitable for the
+	// ideographic languages like Chinese, Japanese and Korean that don't use word delimiters.
+	TokenizerNgram = "ngram"
+
+	// minTokenSize is the minimum length of the words kept by the standard tokenizer, the same as the
+	// default value of `innodb_ft_min_token_size`.
+	minTokenSize = 3
+	// ngramTokenSize is the length of the tokens of the ngram tokenizer, the same as the default value of
+	// `ngram_token_size`.
+	ngramTokenSize = 2
+)
+
+// stopWords is the default stopword list of InnoDB, see INFORMATION_SCHEMA.INNODB_FT_DEFAULT_STOPWORD.
+var stopWords = map[string]struct{}{
+	"a": {}, "about": {}, "an": {}, "are": {}, "as": {}, "at": {}, "be": {}, "by": {}, "com": {}, "de": {},
+	"en": {}, "for": {}, "from": {}, "how": {}, "i": {}, "in": {}, "is": {}, "it": {}, "la": {}, "of": {},
+	"on": {}, "or": {}, "that": {}, "the": {}, "this": {}, "to": {}, "was": {}, "what": {}, "when": {},
+	"where": {}, "who": {}, "will": {}, "with": {}, "und": {}, "www": {},
+}
+
+// Tokenizer splits the text into the terms of the full-text search.
+type Tokenizer interface {
+	// Tokenize returns the lowercase terms of the text in the order of their occurrences.
+	Tokenize(text string) []string
+}
+
+// GetTokenizer returns the tokenizer of the name, it returns nil if the name is unknown.
+func GetTokenizer(name string) Tokenizer {
+	switch strings.ToLower(name) {
+	case TokenizerStandard:
+		return standardTokenizer{}
+	case TokenizerNgram:
+		return ngramTokenizer{n: ngramTokenSize}
+	}
+	return nil
+}
+
+// isWordChar reports whether the rune is a part of a word.
+func isWordChar(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// splitWords splits the text into the lowercase words.
+func splitWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isWordChar(r)
+	})
+}
+
+type standardTokenizer struct{}
+
+// Tokenize implements the Tokenizer interface. The words shorter than minTokenSize and the stopwords are ignored.
+func (standardTokenizer) Tokenize(text string) []string {
+	words := splitWords(text)
+	tokens := words[:0]
+	for _, word := range words {
+		if utf8.RuneCountInString(word) < minTokenSize {
+			continue
+		}
+		if _, ok := stopWords[word]; ok {
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+type ngramTokenizer struct {
+	n int
+}
+
+// Tokenize implements the Tokenizer interface. The words shorter than n are ignored.
+func (t ngramTokenizer) Tokenize(text string) []string {
+	var tokens []string
+	for _, word := range splitWords(text) {
+		runes := []rune(word)
+		for i := 0; i+t.n <= len(runes); i++ {
+			tokens = append(tokens, string(runes[i:i+t.n]))
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,164 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gctuner
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
+	"go.uber.org/zap"
+)
+
+const (
+	// thresholdRatio is the ratio of the memory limit the heap target is kept below.
+	thresholdRatio = 0.7
+	// minGCPercent avoids running GC all the time once the heap in use approaches the threshold, the memory is
+	// limited by the server memory quota then.
+	minGCPercent = 25
+)
+
+// Tuner lowers GOGC after each GC so the next GC happens before the heap exceeds the threshold of the memory limit,
+// the memory limit is the cgroup memory limit in the containers. GOGC is never raised above the GOGC configured by
+// util.SetGOGC. The memory limit is read again after each GC, and onLimitChange is called once it changes.
+type Tuner struct {
+	// memLimit returns the memory limit of the process.
+	memLimit func() (uint64, error)
+	// heapInuse returns the heap in use after the last GC.
+	heapInuse     func() uint64
+	onLimitChange func(limit uint64)
+
+	mu        sync.Mutex
+	lastLimit uint64
+
+	stopped uint32
+}
+
+// NewTuner creates a Tuner.
+func NewTuner(memLimit func() (uint64, error), heapInuse func() uint64, onLimitChange func(limit uint64)) *Tuner {
+	return &Tuner{
+		memLimit:      memLimit,
+		heapInuse:     heapInuse,
+		onLimitChange: onLimitChange,
+	}
+}
+
+var globalTuner *Tuner
+
+// Setup starts the GC tuner of the process, onLimitChange is called once the memory limit changes.
+func Setup(onLimitChange func(limit uint64)) {
+	globalTuner = NewTuner(memory.MemTotal, readHeapInuse, onLimitChange)
+	globalTuner.Start()
+}
+
+// Close stops the GC tuner of the process and restores the configured GOGC.
+func Close() {
+	if globalTuner != nil {
+		globalTuner.Stop()
+	}
+}
+
+func readHeapInuse() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse
+}
+
+// Start tunes GOGC after each GC.
+func (t *Tuner) Start() {
+	t.mu.Lock()
+	t.lastLimit, _ = t.memLimit()
+	t.mu.Unlock()
+	newFinalizer(t)
+}
+
+// Stop stops tuning GOGC and restores the configured GOGC.
+func (t *Tuner) Stop() {
+	atomic.StoreUint32(&t.stopped, 1)
+	t.setGCPercent(util.GetGOGC())
+}
+
+// tune is called after each GC.
+func (t *Tuner) tune() {
+	limit, err := t.memLimit()
+	if err != nil {
+		logutil.BgLogger().Warn("get the memory limit for the GC tuner failed", zap.Error(err))
+		return
+	}
+	t.mu.Lock()
+	changed := limit != t.lastLimit
+	t.lastLimit = limit
+	t.mu.Unlock()
+	if changed {
+		logutil.BgLogger().Info("the memory limit changes", zap.Uint64("limit", limit))
+		if t.onLimitChange != nil {
+			t.onLimitChange(limit)
+		}
+	}
+
+	gcPercent := util.GetGOGC()
+	if variable.EnableGOGCTuner.Load() {
+		gcPercent = calcGCPercent(t.heapInuse(), uint64(float64(limit)*thresholdRatio), gcPercent)
+	}
+	t.setGCPercent(gcPercent)
+}
+
+func (t *Tuner) setGCPercent(gcPercent int) {
+	if debug.SetGCPercent(gcPercent) != gcPercent {
+		metrics.GOGC.Set(float64(gcPercent))
+	}
+}
+
+// calcGCPercent returns the GOGC which makes the heap target inuse*(1+GOGC/100) equal to the threshold, it's between
+// minGCPercent and maxGCPercent.
+func calcGCPercent(inuse, threshold uint64, maxGCPercent int) int {
+	if inuse == 0 || maxGCPercent <= minGCPercent {
+		return maxGCPercent
+	}
+	if inuse >= threshold {
+		return minGCPercent
+	}
+	gcPercent := int((threshold - inuse) * 100 / inuse)
+	if gcPercent < minGCPercent {
+		return minGCPercent
+	}
+	if gcPercent > maxGCPercent {
+		return maxGCPercent
+	}
+	return gcPercent
+}
+
+// finalizer calls tune in the finalizer of an unreachable object, the finalizer is set again each time, so it's run
+// after each GC.
+type finalizer struct {
+	tuner *Tuner
+}
+
+func newFinalizer(tuner *Tuner) {
+	runtime.SetFinalizer(&finalizer{tuner: tuner}, finalizerHandler)
+}
+
+func finalizerHandler(f *finalizer) {
+	if atomic.LoadUint32(&f.tuner.stopped) == 1 {
+		return
+	}
+	f.tuner.tune()
+	runtime.SetFinalizer(f, finalizerHandler)
+}
@@ -0,0 +1,89 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gctuner
+
+import (
+	"runtime/debug"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util"
+)
+
+var _ = Suite(&testGCTunerSuite{})
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+type testGCTunerSuite struct{}
+
+func (s *testGCTunerSuite) TestCalcGCPercent(c *C) {
+	c.Assert(calcGCPercent(0, 100, 100), Equals, 100)
+	c.Assert(calcGCPercent(10, 100, 100), Equals, 100)
+	c.Assert(calcGCPercent(60, 100, 100), Equals, 66)
+	c.Assert(calcGCPercent(90, 100, 100), Equals, minGCPercent)
+	c.Assert(calcGCPercent(200, 100, 100), Equals, minGCPercent)
+	// The configured GOGC lower than minGCPercent is kept.
+	c.Assert(calcGCPercent(90, 100, 10), Equals, 10)
+}
+
+func (s *testGCTunerSuite) TestTune(c *C) {
+	oriGOGC := util.GetGOGC()
+	defer util.SetGOGC(oriGOGC)
+	util.SetGOGC(100)
+
+	gcPercent := func() int {
+		p := debug.SetGCPercent(100)
+		debug.SetGCPercent(p)
+		return p
+	}
+
+	limit, inuse := uint64(1000), uint64(100)
+	var changedLimit uint64
+	t := NewTuner(func() (uint64, error) { return limit, nil }, func() uint64 { return inuse },
+		func(l uint64) { changedLimit = l })
+	t.mu.Lock()
+	t.lastLimit = limit
+	t.mu.Unlock()
+
+	// The threshold is 700, the heap target 200 is lower.
+	t.tune()
+	c.Assert(gcPercent(), Equals, 100)
+	c.Assert(changedLimit, Equals, uint64(0))
+
+	inuse = 500
+	t.tune()
+	c.Assert(gcPercent(), Equals, 40)
+
+	limit = 2000
+	t.tune()
+	c.Assert(changedLimit, Equals, uint64(2000))
+	c.Assert(gcPercent(), Equals, 100)
+
+	variable.EnableGOGCTuner.Store(false)
+	defer variable.EnableGOGCTuner.Store(variable.DefTiDBEnableGOGCTuner)
+	limit = 600
+	t.tune()
+	c.Assert(changedLimit, Equals, uint64(600))
+	c.Assert(gcPercent(), Equals, 100)
+	variable.EnableGOGCTuner.Store(true)
+
+	t.tune()
+	c.Assert(gcPercent(), Equals, minGCPercent)
+	// Stop restores the configured GOGC.
+	t.Stop()
+	c.Assert(gcPercent(), Equals, 100)
+}
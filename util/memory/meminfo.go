@@ -58,10 +58,13 @@ func MemUsedNormal() (uint64, error) {
 	return v.Used, nil
 }
 
-const (
-	cGroupMemLimitPath = "/sys/fs/cgroup/memory/memory.limit_in_bytes"
-	cGroupMemUsagePath = "/sys/fs/cgroup/memory/memory.usage_in_bytes"
-	selfCGroupPath     = "/proc/self/cgroup"
+// The paths are variables for testing.
+var (
+	cGroupMemLimitPath   = "/sys/fs/cgroup/memory/memory.limit_in_bytes"
+	cGroupMemUsagePath   = "/sys/fs/cgroup/memory/memory.usage_in_bytes"
+	cGroupV2MemLimitPath = "/sys/fs/cgroup/memory.max"
+	cGroupV2MemUsagePath = "/sys/fs/cgroup/memory.current"
+	selfCGroupPath       = "/proc/self/cgroup"
 )
 
 type memInfoCache struct {
@@ -89,18 +92,28 @@ var memLimit *memInfoCache
 // expiration time is 500ms
 var memUsage *memInfoCache
 
-// MemTotalCGroup returns the total amount of RAM on this system in container environment.
+// MemTotalCGroup returns the total amount of RAM on this system in container environment. It's the memory limit of
+// the cgroup, or the physical memory if the cgroup has no limit. The limit is read again every 60s, so a changed
+// limit is picked up without restarting.
 func MemTotalCGroup() (uint64, error) {
-	mem, t := memLimit.get()
+	limit, t := memLimit.get()
 	if time.Since(t) < 60*time.Second {
-		return mem, nil
+		return limit, nil
 	}
-	mem, err := readUint(cGroupMemLimitPath)
+	limit, err := cgroupMemLimit()
 	if err != nil {
-		return mem, err
+		return limit, err
 	}
-	memLimit.set(mem, time.Now())
-	return mem, nil
+	v, err := mem.VirtualMemory()
+	if err != nil {
+		return 0, err
+	}
+	// The limit of the cgroup without a limit is a huge number, such as 9223372036854771712 of cgroup v1.
+	if limit == 0 || limit > v.Total {
+		limit = v.Total
+	}
+	memLimit.set(limit, time.Now())
+	return limit, nil
 }
 
 // MemUsedCGroup returns the total used amount of RAM on this system in container environment.
@@ -109,7 +122,10 @@ func MemUsedCGroup() (uint64, error) {
 	if time.Since(t) < 500*time.Millisecond {
 		return mem, nil
 	}
-	mem, err := readUint(cGroupMemUsagePath)
+	mem, err := readUint(cGroupV2MemUsagePath)
+	if os.IsNotExist(err) {
+		mem, err = readUint(cGroupMemUsagePath)
+	}
 	if err != nil {
 		return mem, err
 	}
@@ -117,8 +133,38 @@ func MemUsedCGroup() (uint64, error) {
 	return mem, nil
 }
 
+// cgroupMemLimit reads the memory limit of the cgroup v2 or v1, 0 means no limit.
+func cgroupMemLimit() (uint64, error) {
+	v, err := os.ReadFile(cGroupV2MemLimitPath)
+	if err == nil {
+		s := strings.TrimSpace(string(v))
+		if s == "max" {
+			return 0, nil
+		}
+		return parseUint(s, 10, 64)
+	}
+	if !os.IsNotExist(err) {
+		return 0, err
+	}
+	return readUint(cGroupMemLimitPath)
+}
+
+// CGroupMemLimit returns the memory limit of the cgroup if the process runs in a cgroup whose memory limit is lower
+// than the physical memory, e.g. a container with the memory limit.
+func CGroupMemLimit() (uint64, bool) {
+	limit, err := cgroupMemLimit()
+	if err != nil || limit == 0 {
+		return 0, false
+	}
+	v, err := mem.VirtualMemory()
+	if err != nil || limit >= v.Total {
+		return 0, false
+	}
+	return limit, true
+}
+
 func init() {
-	if inContainer() {
+	if _, ok := CGroupMemLimit(); ok || inContainer() {
 		MemTotal = MemTotalCGroup
 		MemUsed = MemUsedCGroup
 	} else {
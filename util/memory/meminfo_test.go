@@ -0,0 +1,84 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/shirou/gopsutil/mem"
+)
+
+func (s *testSuite) TestCGroupMemLimit(c *C) {
+	dir := c.MkDir()
+	oriLimitPath, oriV2LimitPath := cGroupMemLimitPath, cGroupV2MemLimitPath
+	oriUsagePath, oriV2UsagePath := cGroupMemUsagePath, cGroupV2MemUsagePath
+	defer func() {
+		cGroupMemLimitPath, cGroupV2MemLimitPath = oriLimitPath, oriV2LimitPath
+		cGroupMemUsagePath, cGroupV2MemUsagePath = oriUsagePath, oriV2UsagePath
+		memLimit.set(0, time.Time{})
+		memUsage.set(0, time.Time{})
+	}()
+	cGroupMemLimitPath = filepath.Join(dir, "memory.limit_in_bytes")
+	cGroupMemUsagePath = filepath.Join(dir, "memory.usage_in_bytes")
+	cGroupV2MemLimitPath = filepath.Join(dir, "memory.max")
+	cGroupV2MemUsagePath = filepath.Join(dir, "memory.current")
+	writeFile := func(path, content string) {
+		c.Assert(os.WriteFile(path, []byte(content), 0600), IsNil)
+		memLimit.set(0, time.Time{})
+		memUsage.set(0, time.Time{})
+	}
+	v, err := mem.VirtualMemory()
+	c.Assert(err, IsNil)
+
+	// cgroup v1
+	writeFile(cGroupMemLimitPath, "1073741824\n")
+	writeFile(cGroupMemUsagePath, "536870912\n")
+	limit, ok := CGroupMemLimit()
+	c.Assert(ok, IsTrue)
+	c.Assert(limit, Equals, uint64(1<<30))
+	total, err := MemTotalCGroup()
+	c.Assert(err, IsNil)
+	c.Assert(total, Equals, uint64(1<<30))
+	used, err := MemUsedCGroup()
+	c.Assert(err, IsNil)
+	c.Assert(used, Equals, uint64(1<<29))
+
+	// The cgroup v1 without a limit.
+	writeFile(cGroupMemLimitPath, "9223372036854771712\n")
+	_, ok = CGroupMemLimit()
+	c.Assert(ok, IsFalse)
+	total, err = MemTotalCGroup()
+	c.Assert(err, IsNil)
+	c.Assert(total, Equals, v.Total)
+
+	// cgroup v2 is preferred.
+	writeFile(cGroupV2MemLimitPath, "2147483648\n")
+	writeFile(cGroupV2MemUsagePath, "1073741824\n")
+	limit, ok = CGroupMemLimit()
+	c.Assert(ok, IsTrue)
+	c.Assert(limit, Equals, uint64(2<<30))
+	used, err = MemUsedCGroup()
+	c.Assert(err, IsNil)
+	c.Assert(used, Equals, uint64(1<<30))
+
+	writeFile(cGroupV2MemLimitPath, "max\n")
+	_, ok = CGroupMemLimit()
+	c.Assert(ok, IsFalse)
+	total, err = MemTotalCGroup()
+	c.Assert(err, IsNil)
+	c.Assert(total, Equals, v.Total)
+}
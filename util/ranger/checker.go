@@ -15,6 +15,7 @@ package ranger
 
 import (
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/collate"
@@ -48,6 +49,12 @@ func (c *conditionChecker) checkScalarFunction(scalar *expression.ScalarFunction
 	case ast.LogicOr, ast.LogicAnd:
 		return c.check(scalar.GetArgs()[0]) && c.check(scalar.GetArgs()[1])
 	case ast.EQ, ast.NE, ast.GE, ast.GT, ast.LE, ast.LT, ast.NullEQ:
+		// The SET values are stored in the index by their numbers, which aren't ordered by their names, so only the
+		// (not) equal comparisons on the SET columns can be converted to the ranges.
+		if scalar.FuncName.L != ast.EQ && scalar.FuncName.L != ast.NE && scalar.FuncName.L != ast.NullEQ &&
+			(isSetColumn(scalar.GetArgs()[0]) || isSetColumn(scalar.GetArgs()[1])) {
+			return false
+		}
 		if _, ok := scalar.GetArgs()[0].(*expression.Constant); ok {
 			if c.checkColumn(scalar.GetArgs()[1]) {
 				// Checks whether the scalar function is calculated use the collation compatible with the column.
@@ -115,6 +122,11 @@ func (c *conditionChecker) checkLikeFunc(scalar *expression.ScalarFunction) bool
 	if !c.checkColumn(scalar.GetArgs()[0]) {
 		return false
 	}
+	// The ENUM/SET values are stored in the index by their numbers, so the prefixes of their names can't be converted
+	// to the ranges.
+	if tp := scalar.GetArgs()[0].GetType().Tp; tp == mysql.TypeEnum || tp == mysql.TypeSet {
+		return false
+	}
 	pattern, ok := scalar.GetArgs()[1].(*expression.Constant)
 	if !ok {
 		return false
@@ -163,3 +175,8 @@ func (c *conditionChecker) checkColumn(expr expression.Expression) bool {
 	}
 	return c.colUniqueID == col.UniqueID
 }
+
+func isSetColumn(expr expression.Expression) bool {
+	col, ok := expr.(*expression.Column)
+	return ok && col.RetType.Tp == mysql.TypeSet
+}
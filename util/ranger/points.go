@@ -102,7 +102,9 @@ func (r *pointSorter) Less(i, j int) bool {
 }
 
 func rangePointLess(sc *stmtctx.StatementContext, a, b *point) (bool, error) {
-	if a.value.Kind() == types.KindMysqlEnum && b.value.Kind() == types.KindMysqlEnum {
+	// The ENUM/SET values are ordered by their numbers in the index.
+	if (a.value.Kind() == types.KindMysqlEnum && b.value.Kind() == types.KindMysqlEnum) ||
+		(a.value.Kind() == types.KindMysqlSet && b.value.Kind() == types.KindMysqlSet) {
 		return rangePointEnumLess(sc, a, b)
 	}
 	cmp, err := a.value.CompareDatum(sc, &b.value)
@@ -334,6 +336,9 @@ func (r *builder) buildFormBinOp(expr *expression.ScalarFunction) []*point {
 	if ft.Tp == mysql.TypeEnum && ft.EvalType() == types.ETString {
 		return handleEnumFromBinOp(r.sc, ft, value, op)
 	}
+	if ft.Tp == mysql.TypeSet && ft.EvalType() == types.ETString && !value.IsNull() {
+		return handleSetFromBinOp(ft, value, op)
+	}
 
 	switch op {
 	case ast.NullEQ:
@@ -498,6 +503,44 @@ func handleEnumFromBinOp(sc *stmtctx.StatementContext, ft *types.FieldType, val
 	return res
 }
 
+// handleSetFromBinOp builds the points of the (not) equal comparison between the SET column and the constant. The
+// constant is compared with the SET values as a string, so it only equals the SET value whose name is the same as it
+// under the collation of the column.
+func handleSetFromBinOp(ft *types.FieldType, val types.Datum, op string) []*point {
+	d, ok := convertToSetDatum(ft, val)
+	switch op {
+	case ast.EQ, ast.NullEQ:
+		if !ok {
+			return nil
+		}
+		return []*point{{value: d, start: true}, {value: d}}
+	case ast.NE:
+		if !ok {
+			return getNotNullFullRange()
+		}
+		startPoint1 := &point{value: types.MinNotNullDatum(), start: true}
+		endPoint1 := &point{value: d, excl: true}
+		startPoint2 := &point{value: d, start: true, excl: true}
+		endPoint2 := &point{value: types.MaxValueDatum()}
+		return []*point{startPoint1, endPoint1, startPoint2, endPoint2}
+	}
+	return getFullRange()
+}
+
+// convertToSetDatum converts the constant to the SET value whose name is the same as it under the collation of the
+// column, it returns false if there is no such value.
+func convertToSetDatum(ft *types.FieldType, val types.Datum) (types.Datum, bool) {
+	str, err := val.ToString()
+	if err != nil {
+		return types.Datum{}, false
+	}
+	set, err := types.ParseSetName(ft.Elems, str, ft.Collate)
+	if err != nil || types.CompareString(set.Name, str, ft.Collate) != 0 {
+		return types.Datum{}, false
+	}
+	return types.NewMysqlSetDatum(set, ft.Collate), true
+}
+
 func (r *builder) buildFromIsTrue(expr *expression.ScalarFunction, isNot int, keepNull bool) []*point {
 	if isNot == 1 {
 		if keepNull {
@@ -569,12 +612,20 @@ func (r *builder) buildFromIn(expr *expression.ScalarFunction) ([]*point, bool)
 		if dt.Kind() == types.KindString || dt.Kind() == types.KindBinaryLiteral {
 			dt.SetString(dt.GetString(), colCollate)
 		}
-		if expr.GetArgs()[0].GetType().Tp == mysql.TypeEnum {
-			dt, err = dt.ConvertTo(r.sc, expr.GetArgs()[0].GetType())
-			if err != nil {
-				// in (..., an impossible value (not valid enum), ...), the range is empty, so skip it.
+		if ft := expr.GetArgs()[0].GetType(); ft.Tp == mysql.TypeEnum && ft.EvalType() == types.ETString {
+			// The constant is compared with the ENUM values as a string or a number, so it's converted in the same way
+			// as the equal comparison rather than by its number in the string.
+			rangePoints = append(rangePoints, handleEnumFromBinOp(r.sc, ft, dt, ast.EQ)...)
+			continue
+		}
+		if ft := expr.GetArgs()[0].GetType(); ft.Tp == mysql.TypeSet && ft.EvalType() == types.ETString {
+			setDatum, ok := convertToSetDatum(ft, dt)
+			if !ok {
+				// in (..., an impossible value (not valid set), ...), the range is empty, so skip it.
 				continue
 			}
+			rangePoints = append(rangePoints, &point{value: setDatum, start: true}, &point{value: setDatum})
+			continue
 		}
 		if expr.GetArgs()[0].GetType().Tp == mysql.TypeYear {
 			dt, err = dt.ConvertToMysqlYear(r.sc, expr.GetArgs()[0].GetType())
@@ -348,13 +348,15 @@ create table t(
 	e binary(10),
 	f varchar(10) collate utf8mb4_general_ci,
 	g enum('A','B','C') collate utf8mb4_general_ci,
+	h set('C','B','A') collate utf8mb4_general_ci,
 	index idx_ab(a(50), b),
 	index idx_cb(c, a),
 	index idx_d(d(2)),
 	index idx_e(e(2)),
 	index idx_f(f),
 	index idx_de(d(2), e),
-	index idx_g(g)
+	index idx_g(g),
+	index idx_h(h)
 )`)
 
 	tests := []struct {
@@ -637,6 +639,69 @@ create table t(
 			filterConds: "[]",
 			resultStr:   "[[\"A\",\"A\"]]",
 		},
+		{
+			indexPos:    6,
+			exprStr:     "g in ('a', '2')",
+			accessConds: "[in(test.t.g, a, 2)]",
+			filterConds: "[]",
+			resultStr:   "[[\"A\",\"A\"]]",
+		},
+		{
+			indexPos:    6,
+			exprStr:     "g like 'a%'",
+			accessConds: "[]",
+			filterConds: "[like(test.t.g, a%, 92)]",
+			resultStr:   "[[NULL,+inf]]",
+		},
+		{
+			indexPos:    7,
+			exprStr:     "h = 'a'",
+			accessConds: "[eq(test.t.h, a)]",
+			filterConds: "[]",
+			resultStr:   "[[\"A\",\"A\"]]",
+		},
+		{
+			indexPos:    7,
+			exprStr:     "h = 'b,a'",
+			accessConds: "[eq(test.t.h, b,a)]",
+			filterConds: "[]",
+			resultStr:   "[[\"B,A\",\"B,A\"]]",
+		},
+		{
+			indexPos:    7,
+			exprStr:     "h = 'a,b'",
+			accessConds: "[eq(test.t.h, a,b)]",
+			filterConds: "[]",
+			resultStr:   "[]",
+		},
+		{
+			indexPos:    7,
+			exprStr:     "h != 'b'",
+			accessConds: "[ne(test.t.h, b)]",
+			filterConds: "[]",
+			resultStr:   "[[-inf,\"B\") (\"B\",+inf]]",
+		},
+		{
+			indexPos:    7,
+			exprStr:     "h in ('a', 'c', 'd')",
+			accessConds: "[in(test.t.h, a, c, d)]",
+			filterConds: "[]",
+			resultStr:   "[[\"C\",\"C\"] [\"A\",\"A\"]]",
+		},
+		{
+			indexPos:    7,
+			exprStr:     "h < 'b'",
+			accessConds: "[]",
+			filterConds: "[lt(test.t.h, b)]",
+			resultStr:   "[[NULL,+inf]]",
+		},
+		{
+			indexPos:    7,
+			exprStr:     "h like 'a%'",
+			accessConds: "[]",
+			filterConds: "[like(test.t.h, a%, 92)]",
+			resultStr:   "[[NULL,+inf]]",
+		},
 	}
 
 	collate.SetNewCollationEnabledForTest(true)
@@ -1539,28 +1604,31 @@ func (s *testRangerSuite) TestIndexRangeForYear(c *C) {
 		{
 			indexPos:    0,
 			exprStr:     `a not in (0, 1, 2)`,
-			accessConds: "[not(in(test.t.a, 0, 1, 2))]",
+			accessConds: "[not(in(test.t.a, 0, 2001, 2002))]",
 			filterConds: "[]",
 			resultStr:   `[(NULL,0) (0,2001) (2002,+inf]]`,
 		},
 		{
 			indexPos:    0,
 			exprStr:     `a not in (-1, 1, 2)`,
-			accessConds: "[not(in(test.t.a, -1, 1, 2))]",
+			accessConds: "[not(in(test.t.a, -1, 2001, 2002))]",
 			filterConds: "[]",
 			resultStr:   `[(NULL,2001) (2002,+inf]]`,
 		},
+		// MySQL converts the constants in the IN list by storing them into the YEAR field when it can be compared
+		// as longlong (convert_constant_item in Item_func_in::resolve_type), so 70 is 1970 here as in `a = 70`.
+		// The ranges built before didn't contain 1970 either, only the access conditions kept the raw constants.
 		{
 			indexPos:    0,
 			exprStr:     `a not in (1, 2, 70)`,
-			accessConds: "[not(in(test.t.a, 1, 2, 70))]", // this is in accordance with MySQL, MySQL won't interpret 70 here as 1970
+			accessConds: "[not(in(test.t.a, 2001, 2002, 1970))]",
 			filterConds: "[]",
 			resultStr:   `[(NULL,1970) (1970,2001) (2002,+inf]]`,
 		},
 		{
 			indexPos:    0,
 			exprStr:     `a = 1 or a = 2 or a = 70`,
-			accessConds: "[or(eq(test.t.a, 2001), or(eq(test.t.a, 2002), eq(test.t.a, 1970)))]", // this is in accordance with MySQL, MySQL won't interpret 70 here as 1970
+			accessConds: "[or(eq(test.t.a, 2001), or(eq(test.t.a, 2002), eq(test.t.a, 1970)))]", // this is in accordance with MySQL, 70 is interpreted as 1970
 			filterConds: "[]",
 			resultStr:   `[[1970,1970] [2001,2002]]`,
 		},
@@ -1574,7 +1642,7 @@ func (s *testRangerSuite) TestIndexRangeForYear(c *C) {
 		{
 			indexPos:    0,
 			exprStr:     `a not in (1, 2, 15698)`,
-			accessConds: "[not(in(test.t.a, 1, 2, 15698))]",
+			accessConds: "[not(in(test.t.a, 2001, 2002, 15698))]",
 			filterConds: "[]",
 			resultStr:   `[(NULL,2001) (2002,+inf]]`,
 		},
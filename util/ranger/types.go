@@ -14,6 +14,7 @@
 package ranger
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"strings"
@@ -188,6 +189,33 @@ func (ran *Range) PrefixEqualLen(sc *stmtctx.StatementContext) (int, error) {
 	return len(ran.LowVal), nil
 }
 
+// GroupRangesByPrefix groups the sorted ranges by the values of their first prefixLen columns, which must be points.
+// e.g. If the ranges are [1 1,1 1], (1 3,1 +inf] and [2 1,2 1], and prefixLen is 1, the groups are
+// {[1 1,1 1], (1 3,1 +inf]} and {[2 1,2 1]}.
+func GroupRangesByPrefix(sc *stmtctx.StatementContext, ranges []*Range, prefixLen int) ([][]*Range, error) {
+	var groups [][]*Range
+	var lastPrefix []byte
+	for _, ran := range ranges {
+		eqLen, err := ran.PrefixEqualLen(sc)
+		if err != nil {
+			return nil, err
+		}
+		if eqLen < prefixLen {
+			return nil, errors.Errorf("the range %s isn't a point on the first %d columns", ran.String(), prefixLen)
+		}
+		prefix, err := codec.EncodeKey(sc, nil, ran.LowVal[:prefixLen]...)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(groups) == 0 || !bytes.Equal(prefix, lastPrefix) {
+			groups = append(groups, nil)
+			lastPrefix = prefix
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], ran)
+	}
+	return groups, nil
+}
+
 func formatDatum(d types.Datum, isLeftSide bool) string {
 	switch d.Kind() {
 	case types.KindNull:
@@ -186,3 +186,23 @@ func (s *testRangeSuite) TestIsFullRange(c *C) {
 		c.Assert(t.ran.IsFullRange(), Equals, t.isFullRange)
 	}
 }
+
+func (s *testRangeSuite) TestGroupRangesByPrefix(c *C) {
+	sc := new(stmtctx.StatementContext)
+	ranges := []*ranger.Range{
+		{LowVal: types.MakeDatums(1, 1), HighVal: types.MakeDatums(1, 1)},
+		{LowVal: types.MakeDatums(1, 3), HighVal: []types.Datum{types.NewIntDatum(1), types.MaxValueDatum()}, LowExclude: true},
+		{LowVal: types.MakeDatums(2, 1), HighVal: types.MakeDatums(2, 1)},
+	}
+	groups, err := ranger.GroupRangesByPrefix(sc, ranges, 1)
+	c.Assert(err, IsNil)
+	c.Assert(groups, DeepEquals, [][]*ranger.Range{ranges[:2], ranges[2:]})
+
+	groups, err = ranger.GroupRangesByPrefix(sc, ranges, 0)
+	c.Assert(err, IsNil)
+	c.Assert(groups, DeepEquals, [][]*ranger.Range{ranges})
+
+	// The second range isn't a point on the first 2 columns.
+	_, err = ranger.GroupRangesByPrefix(sc, ranges, 2)
+	c.Assert(err, ErrorMatches, ".*isn't a point on the first 2 columns")
+}
@@ -0,0 +1,543 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redact
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/opcode"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+)
+
+// maskedValue is what the sensitive literals are replaced by.
+const maskedValue = "?"
+
+// TableInfoGetter gets the table info by the database and table names, it returns nil if the table doesn't exist.
+type TableInfoGetter func(db, tbl string) *model.TableInfo
+
+type tablePolicy struct {
+	// allColumns means all the columns of the table are sensitive.
+	allColumns bool
+	columns    map[string]struct{}
+}
+
+// Policy describes the sensitive columns, the literals bound to which should be masked in the slow log, the
+// statement summary and the error messages.
+type Policy struct {
+	// tables is keyed by "db.table" in lower case.
+	tables map[string]*tablePolicy
+	// tableNames are the names of the tables in lower case, which are used to skip the statements quickly.
+	tableNames []string
+}
+
+// NewPolicy creates a Policy from the items of the config `log.redact-sensitive-columns`. Each item is "db.table" for
+// all the columns of the table, or "db.table.column" for a single column. The malformed items are ignored, they should
+// have been rejected when validating the config.
+func NewPolicy(items []string) *Policy {
+	p := &Policy{tables: make(map[string]*tablePolicy)}
+	for _, item := range items {
+		parts := strings.Split(strings.ToLower(item), ".")
+		if len(parts) < 2 || len(parts) > 3 {
+			continue
+		}
+		key := parts[0] + "." + parts[1]
+		tp, ok := p.tables[key]
+		if !ok {
+			tp = &tablePolicy{columns: make(map[string]struct{})}
+			p.tables[key] = tp
+			p.tableNames = append(p.tableNames, parts[1])
+		}
+		if len(parts) == 2 {
+			tp.allColumns = true
+		} else {
+			tp.columns[parts[2]] = struct{}{}
+		}
+	}
+	return p
+}
+
+var globalPolicy atomic.Value
+
+// SetGlobalPolicy sets the Policy used by the whole instance.
+// It should be initialized in `setGlobalVars` in tidb-server/main.go
+func SetGlobalPolicy(p *Policy) {
+	globalPolicy.Store(p)
+}
+
+// GlobalPolicy gets the Policy used by the whole instance, it's nil if there is no sensitive column.
+func GlobalPolicy() *Policy {
+	p, _ := globalPolicy.Load().(*Policy)
+	if p == nil || len(p.tables) == 0 {
+		return nil
+	}
+	return p
+}
+
+func (p *Policy) hasTable(db, tbl string) bool {
+	_, ok := p.tables[db+"."+tbl]
+	return ok
+}
+
+// mayRefer is a cheap check of whether the statement text may refer to the sensitive tables. The statements which
+// can't refer to them are not walked at all.
+func (p *Policy) mayRefer(text string) bool {
+	text = strings.ToLower(text)
+	for _, name := range p.tableNames {
+		if strings.Contains(text, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// sensitiveTableRefs returns the tables referred by the statement, or nil if none of them is sensitive.
+func (p *Policy) sensitiveTableRefs(stmt ast.StmtNode, defaultDB string) []tableRef {
+	if !p.mayRefer(stmt.Text()) {
+		return nil
+	}
+	refs := collectTableRefs(stmt, defaultDB)
+	for _, ref := range refs {
+		if p.hasTable(ref.db, ref.name) {
+			return refs
+		}
+	}
+	return nil
+}
+
+// IsSensitive returns whether the column is sensitive. The names should be in lower case.
+func (p *Policy) IsSensitive(db, tbl, col string) bool {
+	tp, ok := p.tables[db+"."+tbl]
+	if !ok {
+		return false
+	}
+	if tp.allColumns {
+		return true
+	}
+	_, ok = tp.columns[col]
+	return ok
+}
+
+// tableRef is a table referred by the statement.
+type tableRef struct {
+	alias string
+	db    string
+	name  string
+}
+
+// tableRefCollector collects the tables referred by the statement. The scopes of the subqueries are not
+// distinguished, which only makes the unqualified columns be considered sensitive more easily.
+type tableRefCollector struct {
+	defaultDB string
+	refs      []tableRef
+}
+
+func (c *tableRefCollector) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.TableSource:
+		if tn, ok := x.Source.(*ast.TableName); ok && x.AsName.L != "" {
+			c.refs = append(c.refs, tableRef{alias: x.AsName.L, db: c.dbName(tn), name: tn.Name.L})
+		}
+	case *ast.TableName:
+		c.refs = append(c.refs, tableRef{alias: x.Name.L, db: c.dbName(x), name: x.Name.L})
+	}
+	return in, false
+}
+
+func (c *tableRefCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (c *tableRefCollector) dbName(tn *ast.TableName) string {
+	if tn.Schema.L != "" {
+		return tn.Schema.L
+	}
+	return c.defaultDB
+}
+
+func collectTableRefs(stmt ast.Node, defaultDB string) []tableRef {
+	c := &tableRefCollector{defaultDB: strings.ToLower(defaultDB)}
+	stmt.Accept(c)
+	return c.refs
+}
+
+// literalMarker marks the literals and the parameter markers bound to the sensitive columns.
+type literalMarker struct {
+	policy    *Policy
+	defaultDB string
+	getter    TableInfoGetter
+	refs      []tableRef
+
+	literals map[ast.Node]struct{}
+	params   map[int]struct{}
+}
+
+func (m *literalMarker) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.BinaryOperationExpr:
+		switch x.Op {
+		case opcode.EQ, opcode.NE, opcode.LT, opcode.LE, opcode.GT, opcode.GE, opcode.NullEQ:
+			m.markBound(x.L, x.R)
+			m.markBound(x.R, x.L)
+		}
+	case *ast.PatternInExpr:
+		for _, item := range x.List {
+			m.markBound(x.Expr, item)
+		}
+	case *ast.PatternLikeExpr:
+		m.markBound(x.Expr, x.Pattern)
+	case *ast.BetweenExpr:
+		m.markBound(x.Expr, x.Left)
+		m.markBound(x.Expr, x.Right)
+	case *ast.Assignment:
+		if m.isSensitiveColumn(x.Column) {
+			m.mark(x.Expr)
+		}
+	case *ast.InsertStmt:
+		m.markInsertValues(x)
+	}
+	return in, false
+}
+
+func (m *literalMarker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// markBound marks the literals in the value if they are compared with the sensitive columns in the expr.
+func (m *literalMarker) markBound(expr, value ast.ExprNode) {
+	// Compare the rows column by column, e.g. `(a, b) = (1, 2)`.
+	exprRow, ok1 := expr.(*ast.RowExpr)
+	valueRow, ok2 := value.(*ast.RowExpr)
+	if ok1 && ok2 && len(exprRow.Values) == len(valueRow.Values) {
+		for i := range exprRow.Values {
+			m.markBound(exprRow.Values[i], valueRow.Values[i])
+		}
+		return
+	}
+	if m.containsSensitiveColumn(expr) {
+		m.mark(value)
+	}
+}
+
+func (m *literalMarker) markInsertValues(stmt *ast.InsertStmt) {
+	if stmt.Table == nil || stmt.Table.TableRefs == nil {
+		return
+	}
+	ts, ok := stmt.Table.TableRefs.Left.(*ast.TableSource)
+	if !ok {
+		return
+	}
+	tn, ok := ts.Source.(*ast.TableName)
+	if !ok {
+		return
+	}
+	db := tn.Schema.L
+	if db == "" {
+		db = m.defaultDB
+	}
+	if !m.policy.hasTable(db, tn.Name.L) {
+		return
+	}
+	var columns []string
+	if len(stmt.Columns) > 0 {
+		for _, col := range stmt.Columns {
+			columns = append(columns, col.Name.L)
+		}
+	} else if m.getter != nil {
+		if tblInfo := m.getter(db, tn.Name.L); tblInfo != nil {
+			for _, col := range tblInfo.Cols() {
+				if !col.Hidden {
+					columns = append(columns, col.Name.L)
+				}
+			}
+		}
+	}
+	for _, row := range stmt.Lists {
+		for i, value := range row {
+			// Mask the value anyway if the column is unknown.
+			if i >= len(columns) || m.policy.IsSensitive(db, tn.Name.L, columns[i]) {
+				m.mark(value)
+			}
+		}
+	}
+}
+
+func (m *literalMarker) isSensitiveColumn(col *ast.ColumnName) bool {
+	for _, ref := range m.refs {
+		if col.Table.L != "" && col.Table.L != ref.alias {
+			continue
+		}
+		if col.Schema.L != "" && col.Schema.L != ref.db {
+			continue
+		}
+		if m.policy.IsSensitive(ref.db, ref.name, col.Name.L) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *literalMarker) containsSensitiveColumn(expr ast.ExprNode) bool {
+	finder := &sensitiveColumnFinder{marker: m}
+	expr.Accept(finder)
+	return finder.found
+}
+
+// mark marks all the literals and the parameter markers in the expression.
+func (m *literalMarker) mark(expr ast.ExprNode) {
+	expr.Accept(&literalCollector{marker: m})
+}
+
+type sensitiveColumnFinder struct {
+	marker *literalMarker
+	found  bool
+}
+
+func (f *sensitiveColumnFinder) Enter(in ast.Node) (ast.Node, bool) {
+	if col, ok := in.(*ast.ColumnName); ok && f.marker.isSensitiveColumn(col) {
+		f.found = true
+	}
+	// Only the columns of the expression itself are concerned.
+	_, isSubquery := in.(*ast.SubqueryExpr)
+	return in, f.found || isSubquery
+}
+
+func (f *sensitiveColumnFinder) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+type literalCollector struct {
+	marker *literalMarker
+}
+
+func (c *literalCollector) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *driver.ParamMarkerExpr:
+		c.marker.params[x.Order] = struct{}{}
+	case ast.ValueExpr:
+		c.marker.literals[in] = struct{}{}
+	case *ast.SubqueryExpr:
+		// The literals in the subquery are bound to the columns of the subquery.
+		return in, true
+	}
+	return in, false
+}
+
+func (c *literalCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+// maskedExpr replaces a sensitive literal when restoring the statement.
+type maskedExpr struct {
+	ast.ExprNode
+}
+
+// Restore implements Node interface.
+func (n *maskedExpr) Restore(ctx *format.RestoreCtx) error {
+	ctx.WritePlain(maskedValue)
+	return nil
+}
+
+// Accept implements Node interface.
+func (n *maskedExpr) Accept(v ast.Visitor) (ast.Node, bool) {
+	newNode, _ := v.Enter(n)
+	return v.Leave(newNode)
+}
+
+// literalSwapper replaces the marked literals by maskedExpr.
+type literalSwapper struct {
+	literals map[ast.Node]struct{}
+}
+
+func (s *literalSwapper) Enter(in ast.Node) (ast.Node, bool) {
+	return in, false
+}
+
+func (s *literalSwapper) Leave(in ast.Node) (ast.Node, bool) {
+	if _, ok := s.literals[in]; ok {
+		return &maskedExpr{ExprNode: in.(ast.ExprNode)}, true
+	}
+	return in, true
+}
+
+func newLiteralMarker(p *Policy, defaultDB string, getter TableInfoGetter, refs []tableRef) *literalMarker {
+	return &literalMarker{
+		policy:    p,
+		defaultDB: strings.ToLower(defaultDB),
+		getter:    getter,
+		refs:      refs,
+		literals:  make(map[ast.Node]struct{}),
+		params:    make(map[int]struct{}),
+	}
+}
+
+// RedactSQL returns the text of the statement with the literals bound to the sensitive columns masked, and the orders
+// of the parameter markers bound to the sensitive columns, whose arguments should be masked too. redacted is false if
+// nothing needs to be masked, in which case the original text can be used as it is. The statement itself is never
+// changed, because it may be shared by the prepared statement and the plan cache.
+func (p *Policy) RedactSQL(stmt ast.StmtNode, defaultDB string, sqlMode mysql.SQLMode, getter TableInfoGetter) (sql string, maskedParams map[int]struct{}, redacted bool) {
+	refs := p.sensitiveTableRefs(stmt, defaultDB)
+	if refs == nil {
+		return "", nil, false
+	}
+	// The orders of the parameter markers are only set in the prepared statement, so they are collected from it.
+	marker := newLiteralMarker(p, defaultDB, getter, refs)
+	stmt.Accept(marker)
+	if len(marker.literals) == 0 {
+		if len(marker.params) == 0 {
+			return "", nil, false
+		}
+		return stmt.Text(), marker.params, true
+	}
+
+	// The literals are masked in a copy of the statement parsed from its text.
+	psr := parser.New()
+	psr.SetSQLMode(sqlMode)
+	stmtCopy, err := psr.ParseOneStmt(stmt.Text(), "", "")
+	if err != nil {
+		// Never leak the sensitive literals if the statement can't be copied.
+		return maskedValue, marker.params, true
+	}
+	copyMarker := newLiteralMarker(p, defaultDB, getter, collectTableRefs(stmtCopy, defaultDB))
+	stmtCopy.Accept(copyMarker)
+	stmtCopy.Accept(&literalSwapper{literals: copyMarker.literals})
+	var sb strings.Builder
+	if err := stmtCopy.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return maskedValue, marker.params, true
+	}
+	return sb.String(), marker.params, true
+}
+
+// valueError describes an error whose message contains a value given by or read for the statement.
+type valueError struct {
+	// pattern matches the whole message.
+	pattern *regexp.Regexp
+	// value is the index of the submatch of the value.
+	value int
+	// key or column is the index of the submatch of the key or column name which the value belongs to. If neither
+	// is set, the value is masked as long as the statement refers to a sensitive table.
+	key    int
+	column int
+}
+
+// valueErrors are keyed by the error codes. The values may contain anything, so the patterns are anchored at both
+// ends and the values are matched greedily.
+var valueErrors = map[errors.ErrCode]valueError{
+	mysql.ErrDupEntry: {
+		pattern: regexp.MustCompile(`(?s)^Duplicate entry '(.*)' for key '([^']*)'$`),
+		value:   1, key: 2,
+	},
+	mysql.ErrTruncatedWrongValueForField: {
+		pattern: regexp.MustCompile(`(?s)^Incorrect ([^ ]*) value: '(.*)' for column '([^']*)' at row [0-9]+$`),
+		value:   2, column: 3,
+	},
+	mysql.ErrTruncatedWrongValue: {
+		pattern: regexp.MustCompile(`(?s)^Truncated incorrect ([^ ]*) value: '(.*)'$`),
+		value:   2,
+	},
+	mysql.ErrWrongValueForType: {
+		pattern: regexp.MustCompile(`(?s)^Incorrect ([^ ]*) value: '(.*)' for function [^ ]*$`),
+		value:   2,
+	},
+	mysql.ErrWrongValue: {
+		pattern: regexp.MustCompile(`(?s)^Incorrect ([^ ]*) value: '(.*)'$`),
+		value:   2,
+	},
+	mysql.ErrNoPartitionForGivenValue: {
+		pattern: regexp.MustCompile(`(?s)^Table has no partition for value (.*)$`),
+		value:   1,
+	},
+	mysql.ErrDataOutOfRange: {
+		pattern: regexp.MustCompile(`(?s)^([^ ]*) value is out of range in '(.*)'$`),
+		value:   2,
+	},
+}
+
+// RedactError masks the value in the error if it may come from a sensitive column of the tables referred by the
+// statement, e.g. the duplicate entry of a key on a sensitive column, or the truncated value of a sensitive column.
+// The values in the errors which don't tell the column, e.g. the truncated value in a cast, are masked if the
+// statement refers to a sensitive table at all. Other errors are returned as they are.
+func (p *Policy) RedactError(err error, stmt ast.StmtNode, defaultDB string, getter TableInfoGetter) error {
+	if err == nil {
+		return nil
+	}
+	terr, ok := errors.Cause(err).(*errors.Error)
+	if !ok {
+		return err
+	}
+	ve, ok := valueErrors[terr.Code()]
+	if !ok {
+		return err
+	}
+	msg := terr.GetMsg()
+	match := ve.pattern.FindStringSubmatchIndex(msg)
+	if match == nil {
+		return err
+	}
+	refs := p.sensitiveTableRefs(stmt, defaultDB)
+	if refs == nil {
+		return err
+	}
+	sensitive := ve.key == 0 && ve.column == 0
+	for _, ref := range refs {
+		if sensitive || !p.hasTable(ref.db, ref.name) {
+			continue
+		}
+		var cols []string
+		if ve.column > 0 {
+			cols = []string{strings.ToLower(msg[match[2*ve.column]:match[2*ve.column+1]])}
+		} else if tblInfo := getter(ref.db, ref.name); tblInfo != nil {
+			cols = findKeyColumns(tblInfo, msg[match[2*ve.key]:match[2*ve.key+1]])
+		}
+		for _, col := range cols {
+			if p.IsSensitive(ref.db, ref.name, col) {
+				sensitive = true
+				break
+			}
+		}
+	}
+	if !sensitive {
+		return err
+	}
+	masked := msg[:match[2*ve.value]] + maskedValue + msg[match[2*ve.value+1]:]
+	return terr.FastGen("%s", masked)
+}
+
+// findKeyColumns returns the names of the columns of the key, the key name is "PRIMARY" for the primary key.
+func findKeyColumns(tblInfo *model.TableInfo, keyName string) []string {
+	keyName = strings.ToLower(keyName)
+	if keyName == "primary" && tblInfo.PKIsHandle {
+		if pkCol := tblInfo.GetPkColInfo(); pkCol != nil {
+			return []string{pkCol.Name.L}
+		}
+		return nil
+	}
+	for _, idx := range tblInfo.Indices {
+		if (keyName == "primary" && idx.Primary) || idx.Name.L == keyName {
+			cols := make([]string, 0, len(idx.Columns))
+			for _, col := range idx.Columns {
+				cols = append(cols, col.Name.L)
+			}
+			return cols
+		}
+	}
+	return nil
+}
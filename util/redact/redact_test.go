@@ -0,0 +1,167 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redact
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+)
+
+type testRedactSuite struct{}
+
+var _ = Suite(&testRedactSuite{})
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+func (s *testRedactSuite) TestPolicy(c *C) {
+	SetGlobalPolicy(NewPolicy(nil))
+	c.Assert(GlobalPolicy(), IsNil)
+
+	p := NewPolicy([]string{"test.t.SSN", "Test.T2", "malformed"})
+	SetGlobalPolicy(p)
+	c.Assert(GlobalPolicy(), Equals, p)
+	c.Assert(p.IsSensitive("test", "t", "ssn"), IsTrue)
+	c.Assert(p.IsSensitive("test", "t", "name"), IsFalse)
+	c.Assert(p.IsSensitive("test", "t2", "name"), IsTrue)
+	c.Assert(p.IsSensitive("test2", "t", "ssn"), IsFalse)
+	SetGlobalPolicy(nil)
+	c.Assert(GlobalPolicy(), IsNil)
+}
+
+func (s *testRedactSuite) TestRedactSQL(c *C) {
+	p := NewPolicy([]string{"test.t.ssn", "test.t2"})
+	getter := func(db, tbl string) *model.TableInfo {
+		if db == "test" && tbl == "t" {
+			return &model.TableInfo{Columns: []*model.ColumnInfo{
+				{Name: model.NewCIStr("id"), State: model.StatePublic},
+				{Name: model.NewCIStr("ssn"), Offset: 1, State: model.StatePublic},
+			}}
+		}
+		return nil
+	}
+	tests := []struct {
+		sql      string
+		redacted bool
+		expected string
+	}{
+		{"select * from t1 where ssn = '123'", false, ""},
+		{"select * from t where id = 1", false, ""},
+		{"select * from t where ssn = '123' and id = 1", true, "SELECT * FROM `t` WHERE `ssn`=? AND `id`=1"},
+		{"select * from t a where 1 < a.ssn", true, "SELECT * FROM `t` AS `a` WHERE ?<`a`.`ssn`"},
+		{"select * from test.t where lower(ssn) in ('a', 'b') or ssn like 'c%'", true, "SELECT * FROM `test`.`t` WHERE LOWER(`ssn`) IN (?,?) OR `ssn` LIKE ?"},
+		{"select * from t where ssn between 1 and 2 and (id, ssn) = (3, 4)", true, "SELECT * FROM `t` WHERE `ssn` BETWEEN ? AND ? AND ROW(`id`,`ssn`)=ROW(3,?)"},
+		{"select * from t join t1 on t.id = t1.id where t1.ssn = 1", false, ""},
+		{"update t set ssn = '1', id = 2 where id = 3", true, "UPDATE `t` SET `ssn`=?, `id`=2 WHERE `id`=3"},
+		{"insert into t values (1, '123'), (2, '456')", true, "INSERT INTO `t` VALUES (1,?),(2,?)"},
+		{"insert into t (ssn, id) values ('123', 1) on duplicate key update ssn = '456'", true, "INSERT INTO `t` (`ssn`,`id`) VALUES (?,1) ON DUPLICATE KEY UPDATE `ssn`=?"},
+		{"insert into t2 set a = 1, b = 'x'", true, "INSERT INTO `t2` SET `a`=?,`b`=?"},
+		{"delete from t2 where a = 1", true, "DELETE FROM `t2` WHERE `a`=?"},
+	}
+	for _, tt := range tests {
+		stmt, err := parser.New().ParseOneStmt(tt.sql, "", "")
+		c.Assert(err, IsNil)
+		sql, _, redacted := p.RedactSQL(stmt, "test", mysql.ModeNone, getter)
+		c.Assert(redacted, Equals, tt.redacted, Commentf("%s", tt.sql))
+		c.Assert(sql, Equals, tt.expected, Commentf("%s", tt.sql))
+	}
+
+	// The statement itself isn't changed.
+	stmt, err := parser.New().ParseOneStmt("select * from t where ssn = '123'", "", "")
+	c.Assert(err, IsNil)
+	_, _, redacted := p.RedactSQL(stmt, "test", mysql.ModeNone, getter)
+	c.Assert(redacted, IsTrue)
+	where := stmt.(*ast.SelectStmt).Where.(*ast.BinaryOperationExpr)
+	c.Assert(where.R.(ast.ValueExpr).GetString(), Equals, "123")
+
+	// The parameter markers are kept in the text, their arguments should be masked.
+	stmt, err = parser.New().ParseOneStmt("select * from t where id = ? and ssn = ?", "", "")
+	c.Assert(err, IsNil)
+	// The orders are set when preparing the statement.
+	where = stmt.(*ast.SelectStmt).Where.(*ast.BinaryOperationExpr)
+	for i, expr := range []ast.ExprNode{where.L, where.R} {
+		expr.(*ast.BinaryOperationExpr).R.(ast.ParamMarkerExpr).SetOrder(i)
+	}
+	sql, maskedParams, redacted := p.RedactSQL(stmt, "test", mysql.ModeNone, getter)
+	c.Assert(redacted, IsTrue)
+	c.Assert(sql, Equals, "select * from t where id = ? and ssn = ?")
+	c.Assert(maskedParams, DeepEquals, map[int]struct{}{1: {}})
+
+	// The copy is parsed in the SQL mode of the session.
+	psr := parser.New()
+	psr.SetSQLMode(mysql.ModeANSIQuotes)
+	stmt, err = psr.ParseOneStmt(`select * from t where "ssn" = '123'`, "", "")
+	c.Assert(err, IsNil)
+	sql, _, redacted = p.RedactSQL(stmt, "test", mysql.ModeANSIQuotes, getter)
+	c.Assert(redacted, IsTrue)
+	c.Assert(sql, Equals, "SELECT * FROM `t` WHERE `ssn`=?")
+}
+
+func (s *testRedactSuite) TestRedactError(c *C) {
+	p := NewPolicy([]string{"test.t.ssn"})
+	tblInfo := &model.TableInfo{
+		Columns: []*model.ColumnInfo{{Name: model.NewCIStr("id")}, {Name: model.NewCIStr("ssn")}},
+		Indices: []*model.IndexInfo{
+			{Name: model.NewCIStr("uk_ssn"), Columns: []*model.IndexColumn{{Name: model.NewCIStr("ssn")}}},
+			{Name: model.NewCIStr("uk_id"), Columns: []*model.IndexColumn{{Name: model.NewCIStr("id")}}},
+		},
+	}
+	getter := func(db, tbl string) *model.TableInfo {
+		if db == "test" && tbl == "t" {
+			return tblInfo
+		}
+		return nil
+	}
+	stmt, err := parser.New().ParseOneStmt("insert into t values (1, '123')", "", "")
+	c.Assert(err, IsNil)
+
+	err = p.RedactError(kv.ErrKeyExists.FastGenByArgs("123", "uk_ssn"), stmt, "test", getter)
+	c.Assert(err.Error(), Equals, "[kv:1062]Duplicate entry '?' for key 'uk_ssn'")
+	err = p.RedactError(kv.ErrKeyExists.FastGenByArgs("1", "uk_id"), stmt, "test", getter)
+	c.Assert(err.Error(), Equals, "[kv:1062]Duplicate entry '1' for key 'uk_id'")
+	err = p.RedactError(kv.ErrKeyExists.FastGenByArgs("123", "uk_ssn"), stmt, "test2", getter)
+	c.Assert(err.Error(), Equals, "[kv:1062]Duplicate entry '123' for key 'uk_ssn'")
+	c.Assert(p.RedactError(nil, stmt, "test", getter), IsNil)
+
+	// The primary key which is the handle.
+	tblInfo.PKIsHandle = true
+	tblInfo.Columns[1].Flag = mysql.PriKeyFlag
+	err = p.RedactError(kv.ErrKeyExists.FastGenByArgs("123", "PRIMARY"), stmt, "test", getter)
+	c.Assert(err.Error(), Equals, "[kv:1062]Duplicate entry '?' for key 'PRIMARY'")
+
+	// The values of the other errors.
+	tblInfo.PKIsHandle = false
+	tblInfo.Columns[1].Flag = 0
+	err = p.RedactError(table.ErrTruncatedWrongValueForField.FastGenByArgs("DOUBLE", "abc", "ssn", 1), stmt, "test", getter)
+	c.Assert(err.Error(), Equals, "[table:1366]Incorrect DOUBLE value: '?' for column 'ssn' at row 1")
+	err = p.RedactError(table.ErrTruncatedWrongValueForField.FastGenByArgs("DOUBLE", "abc", "id", 1), stmt, "test", getter)
+	c.Assert(err.Error(), Equals, "[table:1366]Incorrect DOUBLE value: 'abc' for column 'id' at row 1")
+	err = p.RedactError(types.ErrTruncatedWrongVal.FastGenByArgs("DOUBLE", "a'b"), stmt, "test", getter)
+	c.Assert(err.Error(), Equals, "[types:1292]Truncated incorrect DOUBLE value: '?'")
+	err = p.RedactError(types.ErrTruncatedWrongVal.FastGenByArgs("DOUBLE", "abc"), stmt, "test2", getter)
+	c.Assert(err.Error(), Equals, "[types:1292]Truncated incorrect DOUBLE value: 'abc'")
+	err = p.RedactError(types.ErrOverflow.FastGenByArgs("BIGINT", "(9223372036854775807 + 1)"), stmt, "test", getter)
+	c.Assert(err.Error(), Equals, "[types:1690]BIGINT value is out of range in '?'")
+	err = p.RedactError(types.ErrDataTooLong.FastGenByArgs("ssn", 1), stmt, "test", getter)
+	c.Assert(err.Error(), Equals, "[types:1406]Data too long for column 'ssn' at row 1")
+}
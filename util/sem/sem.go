@@ -153,7 +153,8 @@ func IsInvisibleSysVar(varNameInLower string) bool {
 		variable.TiDBEnableCollectExecutionInfo,
 		variable.TiDBMemoryUsageAlarmRatio,
 		variable.TiDBRedactLog,
-		variable.TiDBSlowLogMasking:
+		variable.TiDBSlowLogMasking,
+		variable.TiDBHealthReportStorage:
 		return true
 	}
 	return false
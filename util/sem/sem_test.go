@@ -100,4 +100,5 @@ func (s *testSecurity) TestIsInvisibleSysVar(c *C) {
 	c.Assert(IsInvisibleSysVar(variable.TiDBRowFormatVersion), IsTrue)
 	c.Assert(IsInvisibleSysVar(variable.TiDBRedactLog), IsTrue)
 	c.Assert(IsInvisibleSysVar(variable.TiDBSlowLogMasking), IsTrue)
+	c.Assert(IsInvisibleSysVar(variable.TiDBHealthReportStorage), IsTrue)
 }
@@ -237,6 +237,7 @@ func addInfo(addTo *stmtSummaryByDigestElement, addWith *stmtSummaryByDigestElem
 	addTo.sumLatency += addWith.sumLatency
 	if addTo.maxLatency < addWith.maxLatency {
 		addTo.maxLatency = addWith.maxLatency
+		addTo.maxLatencyStmtInstanceID = addWith.maxLatencyStmtInstanceID
 	}
 	if addTo.minLatency > addWith.minLatency {
 		addTo.minLatency = addWith.minLatency
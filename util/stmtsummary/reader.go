@@ -201,6 +201,7 @@ const (
 	SumWarningsStr                  = "SUM_WARNINGS"
 	SumLatencyStr                   = "SUM_LATENCY"
 	MaxLatencyStr                   = "MAX_LATENCY"
+	MaxLatencyStmtInstanceIDStr     = "MAX_LATENCY_STMT_INSTANCE_ID"
 	MinLatencyStr                   = "MIN_LATENCY"
 	AvgLatencyStr                   = "AVG_LATENCY"
 	AvgParseLatencyStr              = "AVG_PARSE_LATENCY"
@@ -327,6 +328,9 @@ var columnValueFactoryMap = map[string]columnValueFactory{
 	MaxLatencyStr: func(ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) interface{} {
 		return int64(ssElement.maxLatency)
 	},
+	MaxLatencyStmtInstanceIDStr: func(ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) interface{} {
+		return ssElement.maxLatencyStmtInstanceID
+	},
 	MinLatencyStr: func(ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) interface{} {
 		return int64(ssElement.minLatency)
 	},
@@ -0,0 +1,129 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stmtsummary
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/tidb/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// defSLOObjective is the default target ratio of the executions meeting the SLO.
+const defSLOObjective = 0.999
+
+// sloOtherDigest is the digest label of the executions whose digests are beyond the max statement count.
+const sloOtherDigest = "other"
+
+// sloTracker checks each execution against the statement SLO, and counts the good and the bad executions of each SQL
+// digest in metrics.StmtSLOCounter. The counters are monotonic, so the compliance and the error budget burn rate of
+// any time range can be computed by Prometheus, e.g. the burn rate of the last hour is
+// `rate(bad[1h]) / (rate(good[1h]) + rate(bad[1h])) / (1 - objective)`, where the objective is
+// metrics.StmtSLOObjective.
+// An execution violates the SLO if it fails or its latency exceeds the latency objective.
+type sloTracker struct {
+	// latency is the latency objective in nanoseconds, 0 disables the tracker.
+	latency int64
+	// objective is the float64 bits of the target ratio of the executions meeting the SLO.
+	objective uint64
+
+	// digests maps the SQL digests to their *sloCounters, numDigests is the size of it.
+	digests    sync.Map
+	numDigests int64
+}
+
+// sloCounters are the good and the bad counters of a SQL digest.
+type sloCounters struct {
+	good prometheus.Counter
+	bad  prometheus.Counter
+}
+
+func newSLOCounters(digest string) *sloCounters {
+	return &sloCounters{
+		good: metrics.StmtSLOCounter.WithLabelValues(digest, metrics.LblGood),
+		bad:  metrics.StmtSLOCounter.WithLabelValues(digest, metrics.LblBad),
+	}
+}
+
+func newSLOTracker() *sloTracker {
+	t := &sloTracker{}
+	t.setObjective(defSLOObjective)
+	return t
+}
+
+func (t *sloTracker) setLatency(latency time.Duration) {
+	atomic.StoreInt64(&t.latency, int64(latency))
+	if latency <= 0 {
+		t.reset()
+	}
+}
+
+func (t *sloTracker) getLatency() time.Duration {
+	return time.Duration(atomic.LoadInt64(&t.latency))
+}
+
+func (t *sloTracker) setObjective(objective float64) {
+	atomic.StoreUint64(&t.objective, math.Float64bits(objective))
+	metrics.StmtSLOObjective.Set(objective)
+}
+
+func (t *sloTracker) getObjective() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&t.objective))
+}
+
+// add checks the execution against the SLO. The digests beyond maxDigests are counted in the sloOtherDigest
+// counters.
+func (t *sloTracker) add(sei *StmtExecInfo, maxDigests int) {
+	latency := t.getLatency()
+	if latency <= 0 || sei.IsInternal {
+		return
+	}
+	counters := t.getCounters(sei.Digest, maxDigests)
+	if !sei.Succeed || sei.TotalLatency > latency {
+		counters.bad.Inc()
+	} else {
+		counters.good.Inc()
+	}
+}
+
+func (t *sloTracker) getCounters(digest string, maxDigests int) *sloCounters {
+	if counters, ok := t.digests.Load(digest); ok {
+		return counters.(*sloCounters)
+	}
+	// Reserve a place for the digest before adding it, so the concurrent executions can't exceed maxDigests.
+	if atomic.AddInt64(&t.numDigests, 1) > int64(maxDigests) {
+		atomic.AddInt64(&t.numDigests, -1)
+		// They are not cached, since reset removes them from metrics.StmtSLOCounter.
+		return newSLOCounters(sloOtherDigest)
+	}
+	counters, loaded := t.digests.LoadOrStore(digest, newSLOCounters(digest))
+	if loaded {
+		atomic.AddInt64(&t.numDigests, -1)
+	}
+	return counters.(*sloCounters)
+}
+
+// reset removes the counters of all the digests, it's called when the SLO is disabled or the statement summary is
+// cleared.
+func (t *sloTracker) reset() {
+	t.digests.Range(func(key, _ interface{}) bool {
+		t.digests.Delete(key)
+		atomic.AddInt64(&t.numDigests, -1)
+		return true
+	})
+	metrics.StmtSLOCounter.Reset()
+}
@@ -71,6 +71,9 @@ type stmtSummaryByDigestMap struct {
 
 	// other stores summary of evicted data.
 	other *stmtSummaryByDigestEvicted
+
+	// slo tracks the statement SLO in the current window.
+	slo *sloTracker
 }
 
 // StmtSummaryByDigestMap is a global map containing all statement summaries.
@@ -120,6 +123,11 @@ type stmtSummaryByDigestElement struct {
 	maxParseLatency   time.Duration
 	sumCompileLatency time.Duration
 	maxCompileLatency time.Duration
+
+	// maxLatencyStmtInstanceID is the statement instance ID of the execution with max latency,
+	// which is used to join with slow log and Top SQL records.
+	maxLatencyStmtInstanceID string
+
 	// coprocessor
 	sumNumCopTasks       int64
 	maxCopProcessTime    time.Duration
@@ -241,6 +249,7 @@ func newStmtSummaryByDigestMap() *stmtSummaryByDigestMap {
 		summaryMap: kvcache.NewSimpleLRUCache(maxStmtCount, 0, 0),
 		sysVars:    sysVars,
 		other:      ssbde,
+		slo:        newSLOTracker(),
 	}
 	newSsMap.summaryMap.SetOnEvict(func(k kvcache.Key, v kvcache.Value) {
 		historySize := newSsMap.historySize()
@@ -301,6 +310,7 @@ func (ssMap *stmtSummaryByDigestMap) AddStatement(sei *StmtExecInfo) {
 	// Lock a single entry, not the whole cache.
 	if summary != nil {
 		summary.add(sei, beginTime, intervalSeconds, historySize)
+		ssMap.slo.add(sei, ssMap.maxStmtCount())
 	}
 }
 
@@ -312,6 +322,7 @@ func (ssMap *stmtSummaryByDigestMap) Clear() {
 	ssMap.summaryMap.DeleteAll()
 	ssMap.other.Clear()
 	ssMap.beginTimeForCurInterval = 0
+	ssMap.slo.reset()
 }
 
 // clearInternal removes all statement summaries which are internal summaries.
@@ -381,6 +392,64 @@ func (ssMap *stmtSummaryByDigestMap) GetMoreThanOnceBindableStmt() []*BindableSt
 	return stmts
 }
 
+// GetBindableStmtByPlanDigest gets the latest users' select/update/delete SQL whose plan digest is `planDigest`.
+// It returns nil if no such statement is found in the current or history intervals.
+func (ssMap *stmtSummaryByDigestMap) GetBindableStmtByPlanDigest(planDigest string) *BindableStmt {
+	if len(planDigest) == 0 {
+		return nil
+	}
+	ssMap.Lock()
+	values := ssMap.summaryMap.Values()
+	ssMap.Unlock()
+
+	for _, value := range values {
+		ssbd := value.(*stmtSummaryByDigest)
+		if stmt := ssbd.bindableStmtByPlanDigest(planDigest); stmt != nil {
+			return stmt
+		}
+	}
+	return nil
+}
+
+func (ssbd *stmtSummaryByDigest) bindableStmtByPlanDigest(planDigest string) *BindableStmt {
+	ssbd.Lock()
+	defer ssbd.Unlock()
+	if !ssbd.initialized || ssbd.planDigest != planDigest || ssbd.history.Len() == 0 {
+		return nil
+	}
+	if ssbd.stmtType != "Select" && ssbd.stmtType != "Delete" && ssbd.stmtType != "Update" {
+		return nil
+	}
+	// Scan from the latest interval, because the latest one may only contain the internal queries.
+	for e := ssbd.history.Back(); e != nil; e = e.Prev() {
+		if stmt := e.Value.(*stmtSummaryByDigestElement).bindableStmt(ssbd); stmt != nil {
+			return stmt
+		}
+	}
+	return nil
+}
+
+func (ssElement *stmtSummaryByDigestElement) bindableStmt(ssbd *stmtSummaryByDigest) *BindableStmt {
+	ssElement.Lock()
+	defer ssElement.Unlock()
+	// Empty auth users means that it is an internal queries.
+	if len(ssElement.authUsers) == 0 || len(ssElement.planHint) == 0 {
+		return nil
+	}
+	stmt := &BindableStmt{
+		Schema:    ssbd.schemaName,
+		Query:     ssElement.sampleSQL,
+		PlanHint:  ssElement.planHint,
+		Charset:   ssElement.charset,
+		Collation: ssElement.collation,
+	}
+	// If it is SQL command prepare / execute, the ssElement.sampleSQL is `execute ...`, we should get the original select query.
+	if ssElement.prepared {
+		stmt.Query = ssbd.normalizedSQL
+	}
+	return stmt
+}
+
 // SetEnabled enables or disables statement summary in global(cluster) or session(server) scope.
 func (ssMap *stmtSummaryByDigestMap) SetEnabled(value string, inSession bool) error {
 	if err := ssMap.sysVars.setVariable(typeEnable, value, inSession); err != nil {
@@ -462,6 +531,26 @@ func (ssMap *stmtSummaryByDigestMap) maxSQLLength() int {
 	return int(ssMap.sysVars.getVariable(typeMaxSQLLength))
 }
 
+// SetSLOLatency sets the latency objective of the statement SLO, 0 disables the SLO tracking.
+func (ssMap *stmtSummaryByDigestMap) SetSLOLatency(latency time.Duration) {
+	ssMap.slo.setLatency(latency)
+}
+
+// SLOLatency returns the latency objective of the statement SLO.
+func (ssMap *stmtSummaryByDigestMap) SLOLatency() time.Duration {
+	return ssMap.slo.getLatency()
+}
+
+// SetSLOObjective sets the target ratio of the executions meeting the statement SLO.
+func (ssMap *stmtSummaryByDigestMap) SetSLOObjective(objective float64) {
+	ssMap.slo.setObjective(objective)
+}
+
+// SLOObjective returns the target ratio of the executions meeting the statement SLO.
+func (ssMap *stmtSummaryByDigestMap) SLOObjective() float64 {
+	return ssMap.slo.getObjective()
+}
+
 // newStmtSummaryByDigest creates a stmtSummaryByDigest from StmtExecInfo.
 func (ssbd *stmtSummaryByDigest) init(sei *StmtExecInfo, beginTime int64, intervalSeconds int64, historySize int) {
 	// Use "," to separate table names to support FIND_IN_SET.
@@ -627,6 +716,7 @@ func (ssElement *stmtSummaryByDigestElement) add(sei *StmtExecInfo, intervalSeco
 	ssElement.sumLatency += sei.TotalLatency
 	if sei.TotalLatency > ssElement.maxLatency {
 		ssElement.maxLatency = sei.TotalLatency
+		ssElement.maxLatencyStmtInstanceID = sei.StmtCtx.StmtInstanceID()
 	}
 	if sei.TotalLatency < ssElement.minLatency {
 		ssElement.minLatency = sei.TotalLatency
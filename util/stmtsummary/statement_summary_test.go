@@ -27,10 +27,12 @@ import (
 	"github.com/pingcap/parser/model"
 	"github.com/pingcap/parser/mysql"
 	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/plancodec"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/tikv/client-go/v2/util"
 )
 
@@ -1152,6 +1154,52 @@ func (s *testStmtSummarySuite) TestGetMoreThanOnceBindableStmt(c *C) {
 	c.Assert(len(stmts), Equals, 1)
 }
 
+// Test GetBindableStmtByPlanDigest.
+func (s *testStmtSummarySuite) TestGetBindableStmtByPlanDigest(c *C) {
+	s.ssMap.Clear()
+
+	stmtExecInfo1 := generateAnyExecInfo()
+	stmtExecInfo1.NormalizedSQL = "select ?"
+	stmtExecInfo1.StmtCtx.StmtType = "Select"
+	stmtExecInfo1.PlanDigest = "plan_digest1"
+	stmtExecInfo1.PlanGenerator = func() (string, string) {
+		return "", "use_index(@`sel_1` `t` `a`)"
+	}
+	s.ssMap.AddStatement(stmtExecInfo1)
+	c.Assert(s.ssMap.GetBindableStmtByPlanDigest(""), IsNil)
+	c.Assert(s.ssMap.GetBindableStmtByPlanDigest("plan_digest2"), IsNil)
+	stmt := s.ssMap.GetBindableStmtByPlanDigest("plan_digest1")
+	c.Assert(stmt, NotNil)
+	c.Assert(stmt.Query, Equals, stmtExecInfo1.OriginalSQL)
+	c.Assert(stmt.PlanHint, Equals, "use_index(@`sel_1` `t` `a`)")
+
+	// The plans in the earlier intervals can also be bound.
+	s.ssMap.beginTimeForCurInterval = time.Now().Unix() + 60
+	stmtExecInfo1.User = ""
+	s.ssMap.AddStatement(stmtExecInfo1)
+	stmt = s.ssMap.GetBindableStmtByPlanDigest("plan_digest1")
+	c.Assert(stmt, NotNil)
+	c.Assert(stmt.Query, Equals, stmtExecInfo1.OriginalSQL)
+
+	// Statements without plan hints can't be bound.
+	stmtExecInfo1.PlanDigest = "plan_digest2"
+	stmtExecInfo1.PlanGenerator = emptyPlanGenerator
+	s.ssMap.AddStatement(stmtExecInfo1)
+	c.Assert(s.ssMap.GetBindableStmtByPlanDigest("plan_digest2"), IsNil)
+
+	// Only the select/update/delete statements can be bound.
+	stmtExecInfo2 := generateAnyExecInfo()
+	stmtExecInfo2.NormalizedSQL = "insert into t select ?"
+	stmtExecInfo2.Digest = "digest2"
+	stmtExecInfo2.StmtCtx.StmtType = "Insert"
+	stmtExecInfo2.PlanDigest = "plan_digest3"
+	stmtExecInfo2.PlanGenerator = func() (string, string) {
+		return "", "use_index(@`sel_1` `t` `a`)"
+	}
+	s.ssMap.AddStatement(stmtExecInfo2)
+	c.Assert(s.ssMap.GetBindableStmtByPlanDigest("plan_digest3"), IsNil)
+}
+
 // Test `formatBackoffTypes`.
 func (s *testStmtSummarySuite) TestFormatBackoffTypes(c *C) {
 	backoffMap := make(map[string]int)
@@ -1430,3 +1478,83 @@ func (s *testStmtSummarySuite) TestAccessPrivilege(c *C) {
 	datums = reader.GetStmtSummaryHistoryRows()
 	c.Assert(len(datums), Equals, loops)
 }
+
+func (s *testStmtSummarySuite) TestSLOTracker(c *C) {
+	s.ssMap.Clear()
+	defer s.ssMap.SetSLOLatency(0)
+	s.ssMap.SetSLOObjective(0.9)
+	defer s.ssMap.SetSLOObjective(defSLOObjective)
+
+	getCounter := func(digest, result string) float64 {
+		pb := &dto.Metric{}
+		err := metrics.StmtSLOCounter.WithLabelValues(digest, result).Write(pb)
+		c.Assert(err, IsNil)
+		return pb.GetCounter().GetValue()
+	}
+	numDigests := func() int {
+		n := 0
+		s.ssMap.slo.digests.Range(func(_, _ interface{}) bool {
+			n++
+			return true
+		})
+		return n
+	}
+
+	// The SLO is not tracked until the latency objective is set.
+	stmtExecInfo1 := generateAnyExecInfo()
+	s.ssMap.AddStatement(stmtExecInfo1)
+	c.Assert(numDigests(), Equals, 0)
+
+	s.ssMap.SetSLOLatency(time.Second)
+	c.Assert(s.ssMap.SLOLatency(), Equals, time.Second)
+	c.Assert(s.ssMap.SLOObjective(), Equals, 0.9)
+	for i := 0; i < 8; i++ {
+		s.ssMap.AddStatement(stmtExecInfo1)
+	}
+	// Too slow.
+	stmtExecInfo1.TotalLatency = 2 * time.Second
+	s.ssMap.AddStatement(stmtExecInfo1)
+	// Failed.
+	stmtExecInfo1.TotalLatency = time.Millisecond
+	stmtExecInfo1.Succeed = false
+	s.ssMap.AddStatement(stmtExecInfo1)
+	c.Assert(numDigests(), Equals, 1)
+	c.Assert(getCounter(stmtExecInfo1.Digest, metrics.LblGood), Equals, 8.0)
+	c.Assert(getCounter(stmtExecInfo1.Digest, metrics.LblBad), Equals, 2.0)
+
+	// Internal statements are not tracked.
+	stmtExecInfo2 := generateAnyExecInfo()
+	stmtExecInfo2.Digest = "internal_digest"
+	stmtExecInfo2.IsInternal = true
+	err := s.ssMap.SetEnabledInternalQuery("1", false)
+	c.Assert(err, IsNil)
+	s.ssMap.AddStatement(stmtExecInfo2)
+	err = s.ssMap.SetEnabledInternalQuery("0", false)
+	c.Assert(err, IsNil)
+	c.Assert(numDigests(), Equals, 1)
+
+	// The counters keep counting in a new window.
+	s.ssMap.beginTimeForCurInterval -= 1800
+	stmtExecInfo1.Succeed = true
+	s.ssMap.AddStatement(stmtExecInfo1)
+	c.Assert(getCounter(stmtExecInfo1.Digest, metrics.LblGood), Equals, 9.0)
+
+	// The digests beyond the max statement count are counted as other.
+	err = s.ssMap.SetMaxStmtCount("1", false)
+	c.Assert(err, IsNil)
+	defer func() {
+		err := s.ssMap.SetMaxStmtCount("", false)
+		c.Assert(err, IsNil)
+	}()
+	stmtExecInfo3 := generateAnyExecInfo()
+	stmtExecInfo3.Digest = "another_digest"
+	s.ssMap.AddStatement(stmtExecInfo3)
+	c.Assert(numDigests(), Equals, 1)
+	c.Assert(getCounter(sloOtherDigest, metrics.LblGood), Equals, 1.0)
+
+	// Disabling the SLO clears the counters.
+	s.ssMap.SetSLOLatency(0)
+	c.Assert(numDigests(), Equals, 0)
+	c.Assert(s.ssMap.slo.numDigests, Equals, int64(0))
+	c.Assert(getCounter(stmtExecInfo1.Digest, metrics.LblGood), Equals, 0.0)
+}
@@ -0,0 +1,189 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tdigest
+
+import (
+	"math"
+	"sort"
+	"unsafe"
+)
+
+// centroid is a cluster of the values summarized by their mean and their count.
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+const centroidSize = int64(unsafe.Sizeof(centroid{}))
+
+// emptyTDigestSize is the memory size of an empty TDigest.
+var emptyTDigestSize = int64(unsafe.Sizeof(TDigest{}))
+
+// TDigest is a merging t-digest which estimates the quantiles of a stream of values in bounded memory, the estimation
+// is more accurate near the tails. Two digests can be merged, so the values can be summarized in parallel.
+// Source paper: https://arxiv.org/abs/1902.04023
+type TDigest struct {
+	// compression bounds the number of the centroids, the larger the more accurate.
+	compression float64
+	// centroids are sorted by their means.
+	centroids []centroid
+	// unmerged are the centroids which are not merged into the centroids yet.
+	unmerged []centroid
+	count    float64
+	min      float64
+	max      float64
+}
+
+// New creates a TDigest with the compression, it keeps at most about 2*compression centroids.
+func New(compression float64) *TDigest {
+	return &TDigest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Add adds a value to the digest.
+func (t *TDigest) Add(x float64) {
+	t.addCentroid(centroid{mean: x, weight: 1})
+}
+
+func (t *TDigest) addCentroid(c centroid) {
+	if math.IsNaN(c.mean) || c.weight <= 0 {
+		return
+	}
+	t.unmerged = append(t.unmerged, c)
+	t.count += c.weight
+	t.min = math.Min(t.min, c.mean)
+	t.max = math.Max(t.max, c.mean)
+	if len(t.unmerged) >= t.bufferSize() {
+		t.compress()
+	}
+}
+
+func (t *TDigest) bufferSize() int {
+	return int(4*t.compression) + 1
+}
+
+// Merge merges the values summarized by other into the digest.
+func (t *TDigest) Merge(other *TDigest) {
+	other.compress()
+	for _, c := range other.centroids {
+		t.addCentroid(c)
+	}
+	if other.count > 0 {
+		t.min = math.Min(t.min, other.min)
+		t.max = math.Max(t.max, other.max)
+	}
+}
+
+// Count returns the number of the values added to the digest.
+func (t *TDigest) Count() float64 {
+	return t.count
+}
+
+// Reset clears the values of the digest.
+func (t *TDigest) Reset() {
+	t.centroids = t.centroids[:0]
+	t.unmerged = t.unmerged[:0]
+	t.count = 0
+	t.min = math.Inf(1)
+	t.max = math.Inf(-1)
+}
+
+// MemSize returns the memory size of the digest.
+func (t *TDigest) MemSize() int64 {
+	return emptyTDigestSize + int64(cap(t.centroids)+cap(t.unmerged))*centroidSize
+}
+
+// scale is the k1 scale function, the centroids spanning at most 1 in it are small near the tails.
+func (t *TDigest) scale(q float64) float64 {
+	return t.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+func (t *TDigest) inverseScale(k float64) float64 {
+	return (math.Sin(k*2*math.Pi/t.compression) + 1) / 2
+}
+
+// compress merges the unmerged centroids into the centroids.
+func (t *TDigest) compress() {
+	if len(t.unmerged) == 0 {
+		return
+	}
+	all := append(t.unmerged, t.centroids...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	merged := t.centroids[:0]
+	cur := all[0]
+	weightSoFar := 0.0
+	qLimit := t.inverseScale(t.scale(0) + 1)
+	for _, c := range all[1:] {
+		if (weightSoFar+cur.weight+c.weight)/t.count <= qLimit {
+			cur.weight += c.weight
+			cur.mean += (c.mean - cur.mean) * c.weight / cur.weight
+			continue
+		}
+		weightSoFar += cur.weight
+		merged = append(merged, cur)
+		qLimit = t.inverseScale(t.scale(weightSoFar/t.count) + 1)
+		cur = c
+	}
+	t.centroids = append(merged, cur)
+	t.unmerged = all[:0]
+}
+
+// Quantile returns the estimated value at the quantile q in [0, 1], it returns NaN if the digest is empty.
+func (t *TDigest) Quantile(q float64) float64 {
+	t.compress()
+	if len(t.centroids) == 0 {
+		return math.NaN()
+	}
+	if q <= 0 {
+		return t.min
+	}
+	if q >= 1 {
+		return t.max
+	}
+	// The values of a centroid are assumed to spread evenly around its mean, the value is interpolated between the
+	// means of the neighbouring centroids, or between a mean and the min or the max at the tails.
+	index := q * t.count
+	first := t.centroids[0]
+	if index < first.weight/2 {
+		if first.weight == 1 {
+			return t.min
+		}
+		return t.min + (first.mean-t.min)*index/(first.weight/2)
+	}
+	weightSoFar := first.weight / 2
+	for i := 0; i < len(t.centroids)-1; i++ {
+		left, right := t.centroids[i], t.centroids[i+1]
+		delta := (left.weight + right.weight) / 2
+		if weightSoFar+delta > index {
+			// A singleton centroid is a value, it's not interpolated.
+			if left.weight == 1 && index-weightSoFar < 0.5 {
+				return left.mean
+			}
+			if right.weight == 1 && weightSoFar+delta-index <= 0.5 {
+				return right.mean
+			}
+			return left.mean + (right.mean-left.mean)*(index-weightSoFar)/delta
+		}
+		weightSoFar += delta
+	}
+	last := t.centroids[len(t.centroids)-1]
+	if last.weight == 1 {
+		return t.max
+	}
+	return last.mean + (t.max-last.mean)*(index-weightSoFar)/(last.weight/2)
+}
@@ -0,0 +1,95 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tdigest
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+var _ = Suite(&testTDigestSuite{})
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+type testTDigestSuite struct{}
+
+// checkQuantiles checks the rank errors of the estimated quantiles of the sorted values.
+func checkQuantiles(c *C, t *TDigest, sorted []float64) {
+	for _, q := range []float64{0.001, 0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99, 0.999} {
+		v := t.Quantile(q)
+		rank := float64(sort.SearchFloat64s(sorted, v)) / float64(len(sorted))
+		c.Assert(math.Abs(rank-q) < 0.01, IsTrue, Commentf("quantile %v, estimated %v, rank %v", q, v, rank))
+	}
+}
+
+func (s *testTDigestSuite) TestQuantile(c *C) {
+	defer testleak.AfterTest(c)()
+	t := New(100)
+	c.Assert(math.IsNaN(t.Quantile(0.5)), IsTrue)
+
+	for _, v := range []float64{3, 1, 2} {
+		t.Add(v)
+	}
+	c.Assert(t.Count(), Equals, float64(3))
+	c.Assert(t.Quantile(0), Equals, float64(1))
+	c.Assert(t.Quantile(0.5), Equals, float64(2))
+	c.Assert(t.Quantile(1), Equals, float64(3))
+	t.Add(math.NaN())
+	c.Assert(t.Count(), Equals, float64(3))
+
+	t.Reset()
+	c.Assert(t.Count(), Equals, float64(0))
+	r := rand.New(rand.NewSource(1))
+	values := make([]float64, 100000)
+	for i := range values {
+		values[i] = r.ExpFloat64()
+		t.Add(values[i])
+	}
+	sort.Float64s(values)
+	checkQuantiles(c, t, values)
+	c.Assert(t.Quantile(0), Equals, values[0])
+	c.Assert(t.Quantile(1), Equals, values[len(values)-1])
+	// The memory is bounded by the compression.
+	c.Assert(len(t.centroids) <= 200, IsTrue)
+	c.Assert(t.MemSize() < 64*1024, IsTrue)
+}
+
+func (s *testTDigestSuite) TestMerge(c *C) {
+	defer testleak.AfterTest(c)()
+	r := rand.New(rand.NewSource(1))
+	values := make([]float64, 0, 100000)
+	merged := New(100)
+	for i := 0; i < 10; i++ {
+		t := New(100)
+		for j := 0; j < 10000; j++ {
+			v := r.NormFloat64() + float64(i)
+			values = append(values, v)
+			t.Add(v)
+		}
+		merged.Merge(t)
+	}
+	merged.Merge(New(100))
+	sort.Float64s(values)
+	c.Assert(merged.Count(), Equals, float64(len(values)))
+	checkQuantiles(c, merged, values)
+	c.Assert(merged.Quantile(0), Equals, values[0])
+	c.Assert(merged.Quantile(1), Equals, values[len(values)-1])
+}
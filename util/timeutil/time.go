@@ -43,6 +43,9 @@ var locCa *locCache
 // systemTZ is current TiDB's system timezone name.
 var systemTZ atomic.String
 
+// locVersion is increased every time the cached locations are dropped by ReloadLocations.
+var locVersion atomic.Uint64
+
 // locCache is a simple map with lock. It stores all used timezone during the lifetime of tidb instance.
 // Talked with Golang team about whether they can have some forms of cache policy available for programmer,
 // they suggests that only programmers knows which one is best for their use case.
@@ -181,6 +184,20 @@ func LoadLocation(name string) (*time.Location, error) {
 	return locCa.getLoc(name)
 }
 
+// ReloadLocations drops the cached locations, so that they're loaded from the time zone database again. It applies the
+// updated time zone rules, like the daylight saving time changes, without restarting TiDB.
+func ReloadLocations() {
+	locCa.Lock()
+	locCa.locMap = make(map[string]*time.Location)
+	locCa.Unlock()
+	locVersion.Inc()
+}
+
+// LocationVersion returns the version of the cached locations, it's changed by ReloadLocations.
+func LocationVersion() uint64 {
+	return locVersion.Load()
+}
+
 // Zone returns the current timezone name and timezone offset in seconds.
 // In compatible with MySQL, we change `SystemLocation` to `System`.
 func Zone(loc *time.Location) (string, int64) {
@@ -67,6 +67,22 @@ func (s *testTimeSuite) TestLocal(c *C) {
 	os.Unsetenv("TZ")
 }
 
+func (s *testTimeSuite) TestReloadLocations(c *C) {
+	loc, err := LoadLocation("Asia/Shanghai")
+	c.Assert(err, IsNil)
+	loc1, err := LoadLocation("Asia/Shanghai")
+	c.Assert(err, IsNil)
+	c.Assert(loc1, Equals, loc)
+
+	version := LocationVersion()
+	ReloadLocations()
+	c.Assert(LocationVersion(), Equals, version+1)
+	loc1, err = LoadLocation("Asia/Shanghai")
+	c.Assert(err, IsNil)
+	c.Assert(loc1, Not(Equals), loc)
+	c.Assert(loc1.String(), Equals, loc.String())
+}
+
 func (s *testTimeSuite) TestInferOneStepLinkForPath(c *C) {
 	os.Remove(filepath.Join(os.TempDir(), "testlink1"))
 	os.Remove(filepath.Join(os.TempDir(), "testlink2"))
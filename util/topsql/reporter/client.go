@@ -15,11 +15,18 @@ package reporter
 
 import (
 	"context"
+	"encoding/hex"
+	"encoding/json"
 	"math"
+	"os"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/topsql/tracecpu"
 	"github.com/pingcap/tipb/go-tipb"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -27,12 +34,13 @@ import (
 )
 
 // ReportClient send data to the target server.
+// Several clients can be registered to a RemoteTopSQLReporter at the same time, each of them receives all the data.
 type ReportClient interface {
-	Send(ctx context.Context, addr string, data reportData) error
+	Send(ctx context.Context, data ReportData) error
 	Close()
 }
 
-// GRPCReportClient reports data to grpc servers.
+// GRPCReportClient reports data to grpc servers, whose address is specified by the tidb_top_sql_agent_address variable.
 type GRPCReportClient struct {
 	curRPCAddr string
 	conn       *grpc.ClientConn
@@ -51,7 +59,8 @@ var _ ReportClient = &GRPCReportClient{}
 
 // Send implements the ReportClient interface.
 // Currently the implementation will establish a new connection every time, which is suitable for a per-minute sending period
-func (r *GRPCReportClient) Send(ctx context.Context, targetRPCAddr string, data reportData) error {
+func (r *GRPCReportClient) Send(ctx context.Context, data ReportData) error {
+	targetRPCAddr := variable.TopSQLVariable.AgentAddress.Load()
 	if targetRPCAddr == "" {
 		return nil
 	}
@@ -66,15 +75,15 @@ func (r *GRPCReportClient) Send(ctx context.Context, targetRPCAddr string, data
 
 	go func() {
 		defer wg.Done()
-		errCh <- r.sendBatchSQLMeta(ctx, data.normalizedSQLMap)
+		errCh <- r.sendBatchSQLMeta(ctx, data.SQLMetas)
 	}()
 	go func() {
 		defer wg.Done()
-		errCh <- r.sendBatchPlanMeta(ctx, data.normalizedPlanMap)
+		errCh <- r.sendBatchPlanMeta(ctx, data.PlanMetas)
 	}()
 	go func() {
 		defer wg.Done()
-		errCh <- r.sendBatchCPUTimeRecord(ctx, data.collectedData)
+		errCh <- r.sendBatchCPUTimeRecord(ctx, data.CPUTimeRecords)
 	}()
 	wg.Wait()
 	close(errCh)
@@ -99,7 +108,7 @@ func (r *GRPCReportClient) Close() {
 }
 
 // sendBatchCPUTimeRecord sends a batch of TopSQL records by stream.
-func (r *GRPCReportClient) sendBatchCPUTimeRecord(ctx context.Context, records []*dataPoints) error {
+func (r *GRPCReportClient) sendBatchCPUTimeRecord(ctx context.Context, records []*DataPoints) error {
 	if len(records) == 0 {
 		return nil
 	}
@@ -110,6 +119,8 @@ func (r *GRPCReportClient) sendBatchCPUTimeRecord(ctx context.Context, records [
 		return err
 	}
 	for _, record := range records {
+		// TODO: send StmtInstanceIDsList, CopCPUTimeMsByPlanNode, the execution statistics and the wait time after
+		// tipb.CPUTimeRecord supports them, they are only emitted by FileReportClient for now.
 		record := &tipb.CPUTimeRecord{
 			RecordListTimestampSec: record.TimestampList,
 			RecordListCpuTimeMs:    record.CPUTimeMsList,
@@ -247,3 +258,284 @@ func (r *GRPCReportClient) dial(ctx context.Context, targetRPCAddr string) (*grp
 		}),
 	)
 }
+
+// FileReportClient reports data to a local file, each report is appended as a line of JSON.
+type FileReportClient struct {
+	path string
+	// calling decodePlan this can take a while, so should not block critical paths
+	decodePlan planBinaryDecodeFunc
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileReportClient returns a new FileReportClient which appends the reports to the file of the path.
+func NewFileReportClient(path string, decodePlan planBinaryDecodeFunc) *FileReportClient {
+	return &FileReportClient{
+		path:       path,
+		decodePlan: decodePlan,
+	}
+}
+
+var _ ReportClient = &FileReportClient{}
+
+// JSONReport is the JSON form of a ReportData, in which the plans are decoded.
+type JSONReport struct {
+	Timestamp int64               `json:"timestamp"`
+	Records   []*JSONReportRecord `json:"records"`
+	// SQLMetas and PlanMetas are keyed by the hex encoded digests.
+	SQLMetas  map[string]string `json:"sql_metas"`
+	PlanMetas map[string]string `json:"plan_metas"`
+}
+
+// JSONReportRecord is the JSON form of a DataPoints. StmtInstanceIDsList is zipped with TimestampList, the IDs can be
+// joined with `Stmt_instance_id` in slow log.
+type JSONReportRecord struct {
+	SQLDigest              string            `json:"sql_digest"`
+	PlanDigest             string            `json:"plan_digest"`
+	DB                     string            `json:"db,omitempty"`
+	User                   string            `json:"user,omitempty"`
+	TimestampList          []uint64          `json:"timestamp_list"`
+	CPUTimeMsList          []uint32          `json:"cpu_time_ms_list"`
+	StmtInstanceIDsList    [][]string        `json:"stmt_instance_ids_list,omitempty"`
+	CopCPUTimeMsByPlanNode map[string]uint64 `json:"cop_cpu_time_ms_by_plan_node,omitempty"`
+	ExecCount              uint64            `json:"exec_count,omitempty"`
+	SumDurationNs          uint64            `json:"sum_duration_ns,omitempty"`
+	SumRows                uint64            `json:"sum_rows,omitempty"`
+	// WaitTimeMsByType is keyed by the name of tracecpu.WaitType, the types without wait time are omitted.
+	WaitTimeMsByType map[string]uint64 `json:"wait_time_ms_by_type,omitempty"`
+}
+
+func newJSONReport(data ReportData, decodePlan planBinaryDecodeFunc) *JSONReport {
+	report := &JSONReport{
+		Timestamp: time.Now().Unix(),
+		Records:   make([]*JSONReportRecord, 0, len(data.CPUTimeRecords)),
+		SQLMetas:  make(map[string]string),
+		PlanMetas: make(map[string]string),
+	}
+	for _, record := range data.CPUTimeRecords {
+		report.Records = append(report.Records, &JSONReportRecord{
+			SQLDigest:              hex.EncodeToString(record.SQLDigest),
+			PlanDigest:             hex.EncodeToString(record.PlanDigest),
+			DB:                     record.DB,
+			User:                   record.User,
+			TimestampList:          record.TimestampList,
+			CPUTimeMsList:          record.CPUTimeMsList,
+			StmtInstanceIDsList:    record.StmtInstanceIDsList,
+			CopCPUTimeMsByPlanNode: record.CopCPUTimeMsByPlanNode,
+			ExecCount:              record.ExecCount,
+			SumDurationNs:          record.SumDurationNs,
+			SumRows:                record.SumRows,
+			WaitTimeMsByType:       waitTimeMsByType(record.SumWaitTimeNs),
+		})
+	}
+	data.SQLMetas.Range(func(key, value interface{}) bool {
+		report.SQLMetas[hex.EncodeToString([]byte(key.(string)))] = value.(string)
+		return true
+	})
+	data.PlanMetas.Range(func(key, value interface{}) bool {
+		planDecoded, errDecode := decodePlan(value.(string))
+		if errDecode != nil {
+			logutil.BgLogger().Warn("[top-sql] decode plan failed", zap.Error(errDecode))
+			return true
+		}
+		report.PlanMetas[hex.EncodeToString([]byte(key.(string)))] = planDecoded
+		return true
+	})
+	return report
+}
+
+func waitTimeMsByType(waitTimeNs [tracecpu.NumWaitTypes]uint64) map[string]uint64 {
+	var m map[string]uint64
+	for i, ns := range waitTimeNs {
+		if ms := ns / uint64(time.Millisecond); ms > 0 {
+			if m == nil {
+				m = make(map[string]uint64, tracecpu.NumWaitTypes)
+			}
+			m[tracecpu.WaitType(i).String()] = ms
+		}
+	}
+	return m
+}
+
+// Send implements the ReportClient interface.
+func (r *FileReportClient) Send(_ context.Context, data ReportData) error {
+	line, err := json.Marshal(newJSONReport(data, r.decodePlan))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		r.file, err = os.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	_, err = r.file.Write(line)
+	return errors.Trace(err)
+}
+
+// Close closes the file.
+func (r *FileReportClient) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return
+	}
+	err := r.file.Close()
+	if err != nil {
+		logutil.BgLogger().Warn("[top-sql] file client close file failed", zap.Error(err))
+	}
+	r.file = nil
+}
+
+// MemoryReportClient keeps the reported data in memory, it's mainly used for tests.
+type MemoryReportClient struct {
+	mu       sync.Mutex
+	reported []ReportData
+}
+
+// NewMemoryReportClient returns a new MemoryReportClient.
+func NewMemoryReportClient() *MemoryReportClient {
+	return &MemoryReportClient{}
+}
+
+var _ ReportClient = &MemoryReportClient{}
+
+// Send implements the ReportClient interface.
+func (r *MemoryReportClient) Send(_ context.Context, data ReportData) error {
+	r.mu.Lock()
+	r.reported = append(r.reported, data)
+	r.mu.Unlock()
+	return nil
+}
+
+// Close implements the ReportClient interface.
+func (r *MemoryReportClient) Close() {}
+
+// GetReportedData returns all the data reported so far.
+func (r *MemoryReportClient) GetReportedData() []ReportData {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ReportData(nil), r.reported...)
+}
+
+// LocalReportClient keeps the reports of the recent period in memory, so that they can be queried locally without
+// deploying the agent, e.g. by the HTTP API of the status port.
+type LocalReportClient struct {
+	// calling decodePlan this can take a while, so should not block critical paths
+	decodePlan planBinaryDecodeFunc
+	retention  time.Duration
+
+	mu      sync.RWMutex
+	reports []*JSONReport
+}
+
+// NewLocalReportClient returns a new LocalReportClient which keeps the reports in the recent retention period.
+func NewLocalReportClient(decodePlan planBinaryDecodeFunc, retention time.Duration) *LocalReportClient {
+	return &LocalReportClient{
+		decodePlan: decodePlan,
+		retention:  retention,
+	}
+}
+
+var _ ReportClient = &LocalReportClient{}
+
+// Send implements the ReportClient interface.
+func (r *LocalReportClient) Send(_ context.Context, data ReportData) error {
+	report := newJSONReport(data, r.decodePlan)
+	expired := report.Timestamp - int64(r.retention/time.Second)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := 0
+	for i < len(r.reports) && r.reports[i].Timestamp <= expired {
+		i++
+	}
+	r.reports = append(r.reports[i:], report)
+	return nil
+}
+
+// Close implements the ReportClient interface.
+func (r *LocalReportClient) Close() {
+	r.mu.Lock()
+	r.reports = nil
+	r.mu.Unlock()
+}
+
+// GetReports returns the reports which are reported since the given time, in the order of the report time.
+func (r *LocalReportClient) GetReports(since time.Time) []*JSONReport {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := sort.Search(len(r.reports), func(i int) bool {
+		return r.reports[i].Timestamp >= since.Unix()
+	})
+	return append([]*JSONReport(nil), r.reports[i:]...)
+}
+
+// JSONAggregatedRecord is the Top SQL data of the records aggregated by the database and/or the user.
+type JSONAggregatedRecord struct {
+	DB            string `json:"db,omitempty"`
+	User          string `json:"user,omitempty"`
+	CPUTimeMs     uint64 `json:"cpu_time_ms"`
+	CopCPUTimeMs  uint64 `json:"cop_cpu_time_ms"`
+	WaitTimeMs    uint64 `json:"wait_time_ms"`
+	ExecCount     uint64 `json:"exec_count"`
+	SumDurationNs uint64 `json:"sum_duration_ns"`
+	SumRows       uint64 `json:"sum_rows"`
+}
+
+// AggregateJSONReports aggregates the records of the reports by the database if byDB is true, and by the user if
+// byUser is true. The results are ordered by the CPU time in descending order.
+func AggregateJSONReports(reports []*JSONReport, byDB, byUser bool) []*JSONAggregatedRecord {
+	type aggKey struct {
+		db   string
+		user string
+	}
+	aggMap := make(map[aggKey]*JSONAggregatedRecord)
+	for _, report := range reports {
+		for _, record := range report.Records {
+			var key aggKey
+			if byDB {
+				key.db = record.DB
+			}
+			if byUser {
+				key.user = record.User
+			}
+			agg, ok := aggMap[key]
+			if !ok {
+				agg = &JSONAggregatedRecord{DB: key.db, User: key.user}
+				aggMap[key] = agg
+			}
+			for _, cpuTimeMs := range record.CPUTimeMsList {
+				agg.CPUTimeMs += uint64(cpuTimeMs)
+			}
+			for _, cpuTimeMs := range record.CopCPUTimeMsByPlanNode {
+				agg.CopCPUTimeMs += cpuTimeMs
+			}
+			for _, waitTimeMs := range record.WaitTimeMsByType {
+				agg.WaitTimeMs += waitTimeMs
+			}
+			agg.ExecCount += record.ExecCount
+			agg.SumDurationNs += record.SumDurationNs
+			agg.SumRows += record.SumRows
+		}
+	}
+	result := make([]*JSONAggregatedRecord, 0, len(aggMap))
+	for _, agg := range aggMap {
+		result = append(result, agg)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CPUTimeMs != result[j].CPUTimeMs {
+			return result[i].CPUTimeMs > result[j].CPUTimeMs
+		}
+		if result[i].DB != result[j].DB {
+			return result[i].DB < result[j].DB
+		}
+		return result[i].User < result[j].User
+	})
+	return result
+}
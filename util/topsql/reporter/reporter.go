@@ -16,6 +16,7 @@ package reporter
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -36,6 +37,9 @@ const (
 	reportTimeout             = 40 * time.Second
 	grpcInitialWindowSize     = 1 << 30
 	grpcInitialConnWindowSize = 1 << 30
+	// collectCopCPUDataChanSize is larger than the other channels because the coprocessor CPU time is collected once
+	// per statement execution instead of once per second.
+	collectCopCPUDataChanSize = 1024
 )
 
 var _ TopSQLReporter = &RemoteTopSQLReporter{}
@@ -45,24 +49,54 @@ type TopSQLReporter interface {
 	tracecpu.Collector
 	RegisterSQL(sqlDigest []byte, normalizedSQL string)
 	RegisterPlan(planDigest []byte, normalizedPlan string)
+	CollectCopCPUTime(record CopCPUTimeRecord)
 	Close()
 }
 
+// CopCPUTimeRecord is the CPU time of the coprocessor tasks of a statement execution that is reported by the
+// storage, broken down by the pushed down plan nodes.
+type CopCPUTimeRecord struct {
+	SQLDigest  []byte
+	PlanDigest []byte
+	DB         string
+	User       string
+	// CPUTimeMsByPlanNode is keyed by the type of the plan node and its row in the normalized plan, e.g.
+	// "TableFullScan#3", which is stable for the same plan digest while the explain ID is not.
+	CPUTimeMsByPlanNode map[string]uint32
+}
+
 type cpuData struct {
 	timestamp uint64
 	records   []tracecpu.SQLCPUTimeRecord
 }
 
-// dataPoints represents the cumulative SQL plan CPU time in current minute window
-type dataPoints struct {
-	SQLDigest      []byte
-	PlanDigest     []byte
+// DataPoints represents the cumulative SQL plan CPU time in current minute window.
+type DataPoints struct {
+	SQLDigest  []byte
+	PlanDigest []byte
+	// DB and User are the current database and the user of the sessions which execute the statement.
+	DB             string
+	User           string
 	TimestampList  []uint64
 	CPUTimeMsList  []uint32
 	CPUTimeMsTotal uint64
+	// StmtInstanceIDsList is the IDs of the statement executions finished at each timestamp in TimestampList.
+	StmtInstanceIDsList [][]string
+	// CopCPUTimeMsByPlanNode is the cumulative CPU time of the coprocessor tasks, keyed by the identity of the pushed
+	// down plan node in the normalized plan, see CopCPUTimeRecord. It isn't counted in CPUTimeMsTotal, which is the
+	// CPU time of TiDB.
+	CopCPUTimeMsByPlanNode map[string]uint64
+	// ExecCount, SumDurationNs and SumRows are the cumulative statistics of the finished executions.
+	ExecCount     uint64
+	SumDurationNs uint64
+	SumRows       uint64
+	// SumWaitTimeNs is the cumulative wait time of the finished executions, indexed by tracecpu.WaitType.
+	SumWaitTimeNs [tracecpu.NumWaitTypes]uint64
 }
 
-type dataPointsOrderByCPUTime []*dataPoints
+// dataPointsOrderByCPUTime ranks the statements by the CPU time only. The wait time isn't sent by GRPCReportClient yet,
+// ranking by it would drop the CPU intensive statements from the CPU report without showing the reason.
+type dataPointsOrderByCPUTime []*DataPoints
 
 func (t dataPointsOrderByCPUTime) Len() int {
 	return len(t)
@@ -92,12 +126,14 @@ func (t sqlCPUTimeRecordSlice) Swap(i, j int) {
 
 type planBinaryDecodeFunc func(string) (string, error)
 
-// RemoteTopSQLReporter implements a TopSQL reporter that sends data to a remote agent
-// This should be called periodically to collect TopSQL resource usage metrics
+// RemoteTopSQLReporter implements a TopSQL reporter that sends data to the registered report clients, e.g. a remote
+// agent. This should be called periodically to collect TopSQL resource usage metrics
 type RemoteTopSQLReporter struct {
 	ctx    context.Context
 	cancel context.CancelFunc
-	client ReportClient
+
+	clientsMu sync.RWMutex
+	clients   []ReportClient
 
 	// normalizedSQLMap is an map, whose keys are SQL digest strings and values are normalized SQL strings
 	normalizedSQLMap atomic.Value // sync.Map
@@ -108,22 +144,24 @@ type RemoteTopSQLReporter struct {
 	normalizedPlanMap atomic.Value // sync.Map
 	planMapLength     atomic2.Int64
 
-	collectCPUDataChan chan cpuData
-	reportDataChan     chan reportData
+	collectCPUDataChan    chan cpuData
+	collectCopCPUDataChan chan CopCPUTimeRecord
+	reportDataChan        chan ReportData
 }
 
 // NewRemoteTopSQLReporter creates a new TopSQL reporter
 //
-// planBinaryDecoder is a decoding function which will be called asynchronously to decode the plan binary to string
+// clients are the sinks which the collected data is reported to, more clients can be added by RegisterClient later.
 // MaxStatementsNum is the maximum SQL and plan number, which will restrict the memory usage of the internal LFU cache
-func NewRemoteTopSQLReporter(client ReportClient) *RemoteTopSQLReporter {
+func NewRemoteTopSQLReporter(clients ...ReportClient) *RemoteTopSQLReporter {
 	ctx, cancel := context.WithCancel(context.Background())
 	tsr := &RemoteTopSQLReporter{
-		ctx:                ctx,
-		cancel:             cancel,
-		client:             client,
-		collectCPUDataChan: make(chan cpuData, 1),
-		reportDataChan:     make(chan reportData, 1),
+		ctx:                   ctx,
+		cancel:                cancel,
+		clients:               clients,
+		collectCPUDataChan:    make(chan cpuData, 1),
+		collectCopCPUDataChan: make(chan CopCPUTimeRecord, collectCopCPUDataChanSize),
+		reportDataChan:        make(chan ReportData, 1),
 	}
 	tsr.normalizedSQLMap.Store(&sync.Map{})
 	tsr.normalizedPlanMap.Store(&sync.Map{})
@@ -138,6 +176,7 @@ var (
 	ignoreExceedSQLCounter              = metrics.TopSQLIgnoredCounter.WithLabelValues("ignore_exceed_sql")
 	ignoreExceedPlanCounter             = metrics.TopSQLIgnoredCounter.WithLabelValues("ignore_exceed_plan")
 	ignoreCollectChannelFullCounter     = metrics.TopSQLIgnoredCounter.WithLabelValues("ignore_collect_channel_full")
+	ignoreCollectCopChannelFullCounter  = metrics.TopSQLIgnoredCounter.WithLabelValues("ignore_collect_cop_channel_full")
 	ignoreReportChannelFullCounter      = metrics.TopSQLIgnoredCounter.WithLabelValues("ignore_report_channel_full")
 	reportAllDurationSuccHistogram      = metrics.TopSQLReportDurationHistogram.WithLabelValues("all", metrics.LblOK)
 	reportAllDurationFailedHistogram    = metrics.TopSQLReportDurationHistogram.WithLabelValues("all", metrics.LblError)
@@ -152,6 +191,20 @@ var (
 	topSQLReportPlanCountHistogram      = metrics.TopSQLReportDataHistogram.WithLabelValues("plan")
 )
 
+// RegisterClient adds a client which the collected data is reported to, along with the existing ones.
+// This function is thread-safe.
+func (tsr *RemoteTopSQLReporter) RegisterClient(client ReportClient) {
+	tsr.clientsMu.Lock()
+	tsr.clients = append(tsr.clients, client)
+	tsr.clientsMu.Unlock()
+}
+
+func (tsr *RemoteTopSQLReporter) getClients() []ReportClient {
+	tsr.clientsMu.RLock()
+	defer tsr.clientsMu.RUnlock()
+	return tsr.clients[:len(tsr.clients):len(tsr.clients)]
+}
+
 // RegisterSQL registers a normalized SQL string to a SQL digest.
 // This function is thread-safe and efficient.
 //
@@ -203,16 +256,35 @@ func (tsr *RemoteTopSQLReporter) Collect(timestamp uint64, records []tracecpu.SQ
 	}
 }
 
+// CollectCopCPUTime receives the coprocessor CPU time of a statement execution. WARN: It will drop the record if the
+// processing is not in time.
+// This function is thread-safe and efficient.
+func (tsr *RemoteTopSQLReporter) CollectCopCPUTime(record CopCPUTimeRecord) {
+	if len(record.CPUTimeMsByPlanNode) == 0 {
+		return
+	}
+	select {
+	case tsr.collectCopCPUDataChan <- record:
+	default:
+		// ignore if chan blocked
+		ignoreCollectCopChannelFullCounter.Inc()
+	}
+}
+
 // Close uses to close and release the reporter resource.
 func (tsr *RemoteTopSQLReporter) Close() {
 	tsr.cancel()
-	tsr.client.Close()
+	for _, client := range tsr.getClients() {
+		client.Close()
+	}
 }
 
 func (tsr *RemoteTopSQLReporter) collectWorker() {
 	defer util.Recover("top-sql", "collectWorker", nil, false)
 
-	collectedData := make(map[string]*dataPoints)
+	collectedData := make(map[string]*DataPoints)
+	// collectedCopData is keyed the same as collectedData, it's merged into collectedData when reporting.
+	collectedCopData := make(map[string]map[string]uint64)
 
 	currentReportInterval := variable.TopSQLVariable.ReportIntervalSeconds.Load()
 	reportTicker := time.NewTicker(time.Second * time.Duration(currentReportInterval))
@@ -222,7 +294,11 @@ func (tsr *RemoteTopSQLReporter) collectWorker() {
 		case data := <-tsr.collectCPUDataChan:
 			// On receiving data to collect: Write to local data array, and retain records with most CPU time.
 			tsr.doCollect(collectedData, data.timestamp, data.records)
+		case record := <-tsr.collectCopCPUDataChan:
+			tsr.doCollectCopCPUTime(collectedCopData, record)
 		case <-reportTicker.C:
+			mergeCopCPUTime(collectedData, collectedCopData)
+			collectedCopData = make(map[string]map[string]uint64)
 			tsr.takeDataAndSendToReportChan(&collectedData)
 
 			// Update `reportTicker` if report interval changed.
@@ -236,10 +312,18 @@ func (tsr *RemoteTopSQLReporter) collectWorker() {
 	}
 }
 
-func encodeKey(buf *bytes.Buffer, sqlDigest, planDigest []byte) string {
+func encodeKey(buf *bytes.Buffer, sqlDigest, planDigest []byte, db, user string) string {
 	buf.Reset()
 	buf.Write(sqlDigest)
 	buf.Write(planDigest)
+	if len(db) > 0 || len(user) > 0 {
+		// The plan digest may be empty, so its length is written to tell it from the database name. The names can't
+		// contain '\x00'.
+		buf.WriteByte(byte(len(planDigest)))
+		buf.WriteString(db)
+		buf.WriteByte(0)
+		buf.WriteString(user)
+	}
 	return buf.String()
 }
 
@@ -255,7 +339,7 @@ func getTopNRecords(records []tracecpu.SQLCPUTimeRecord) (topN, shouldEvict []tr
 	return records[:maxStmt], records[maxStmt:]
 }
 
-func getTopNDataPoints(records []*dataPoints) (topN, shouldEvict []*dataPoints) {
+func getTopNDataPoints(records []*DataPoints) (topN, shouldEvict []*DataPoints) {
 	maxStmt := int(variable.TopSQLVariable.MaxStatementCount.Load())
 	if len(records) <= maxStmt {
 		return records, nil
@@ -269,7 +353,7 @@ func getTopNDataPoints(records []*dataPoints) (topN, shouldEvict []*dataPoints)
 
 // doCollect collects top N records of each round into collectTarget, and evict the data that is not in top N.
 func (tsr *RemoteTopSQLReporter) doCollect(
-	collectTarget map[string]*dataPoints, timestamp uint64, records []tracecpu.SQLCPUTimeRecord) {
+	collectTarget map[string]*DataPoints, timestamp uint64, records []tracecpu.SQLCPUTimeRecord) {
 	defer util.Recover("top-sql", "doCollect", nil, false)
 
 	// Get top N records of each round records.
@@ -283,30 +367,41 @@ func (tsr *RemoteTopSQLReporter) doCollect(
 	}
 	// Collect the top N records to collectTarget for each round.
 	for _, record := range records {
-		key := encodeKey(keyBuf, record.SQLDigest, record.PlanDigest)
+		key := encodeKey(keyBuf, record.SQLDigest, record.PlanDigest, record.DB, record.User)
 		entry, exist := collectTarget[key]
 		if !exist {
-			entry = &dataPoints{
-				SQLDigest:     record.SQLDigest,
-				PlanDigest:    record.PlanDigest,
-				CPUTimeMsList: make([]uint32, 1, listCapacity),
-				TimestampList: make([]uint64, 1, listCapacity),
+			entry = &DataPoints{
+				SQLDigest:           record.SQLDigest,
+				PlanDigest:          record.PlanDigest,
+				DB:                  record.DB,
+				User:                record.User,
+				CPUTimeMsList:       make([]uint32, 1, listCapacity),
+				TimestampList:       make([]uint64, 1, listCapacity),
+				StmtInstanceIDsList: make([][]string, 1, listCapacity),
 			}
 			entry.CPUTimeMsList[0] = record.CPUTimeMs
 			entry.TimestampList[0] = timestamp
+			entry.StmtInstanceIDsList[0] = record.StmtInstanceIDs
 			collectTarget[key] = entry
 		} else {
 			entry.CPUTimeMsList = append(entry.CPUTimeMsList, record.CPUTimeMs)
 			entry.TimestampList = append(entry.TimestampList, timestamp)
+			entry.StmtInstanceIDsList = append(entry.StmtInstanceIDsList, record.StmtInstanceIDs)
 		}
 		entry.CPUTimeMsTotal += uint64(record.CPUTimeMs)
+		entry.ExecCount += uint64(record.ExecCount)
+		entry.SumDurationNs += record.SumDurationNs
+		entry.SumRows += record.SumRows
+		for i, waitTimeNs := range record.SumWaitTimeNs {
+			entry.SumWaitTimeNs[i] += waitTimeNs
+		}
 	}
 
 	// Evict redundant data.
 	normalizedSQLMap := tsr.normalizedSQLMap.Load().(*sync.Map)
 	normalizedPlanMap := tsr.normalizedPlanMap.Load().(*sync.Map)
 	for _, evict := range evicted {
-		key := encodeKey(keyBuf, evict.SQLDigest, evict.PlanDigest)
+		key := encodeKey(keyBuf, evict.SQLDigest, evict.PlanDigest, evict.DB, evict.User)
 		_, ok := collectTarget[key]
 		if ok {
 			continue
@@ -322,11 +417,42 @@ func (tsr *RemoteTopSQLReporter) doCollect(
 	}
 }
 
+// doCollectCopCPUTime accumulates the coprocessor CPU time of the plan nodes into collectTarget, which is keyed the
+// same as the collected DataPoints.
+func (tsr *RemoteTopSQLReporter) doCollectCopCPUTime(collectTarget map[string]map[string]uint64, record CopCPUTimeRecord) {
+	defer util.Recover("top-sql", "doCollectCopCPUTime", nil, false)
+
+	key := encodeKey(bytes.NewBuffer(make([]byte, 0, 64)), record.SQLDigest, record.PlanDigest, record.DB, record.User)
+	cpuTimeMsByPlanNode, exist := collectTarget[key]
+	if !exist {
+		if int64(len(collectTarget)) >= variable.TopSQLVariable.MaxCollect.Load() {
+			ignoreExceedSQLCounter.Inc()
+			return
+		}
+		cpuTimeMsByPlanNode = make(map[string]uint64, len(record.CPUTimeMsByPlanNode))
+		collectTarget[key] = cpuTimeMsByPlanNode
+	}
+	for planNode, cpuTimeMs := range record.CPUTimeMsByPlanNode {
+		cpuTimeMsByPlanNode[planNode] += uint64(cpuTimeMs)
+	}
+}
+
+// mergeCopCPUTime merges the collected coprocessor CPU time into the DataPoints of the statements. The CPU time of
+// the statements which aren't sampled on TiDB in the report window is dropped, so that every reported DataPoints has
+// the TiDB CPU time as before.
+func mergeCopCPUTime(collectedData map[string]*DataPoints, collectedCopData map[string]map[string]uint64) {
+	for key, cpuTimeMsByPlanNode := range collectedCopData {
+		if entry, ok := collectedData[key]; ok {
+			entry.CopCPUTimeMsByPlanNode = cpuTimeMsByPlanNode
+		}
+	}
+}
+
 // takeDataAndSendToReportChan takes out (resets) collected data. These data will be send to a report channel
 // for reporting later.
-func (tsr *RemoteTopSQLReporter) takeDataAndSendToReportChan(collectedDataPtr *map[string]*dataPoints) {
-	// Fetch TopN dataPoints.
-	records := make([]*dataPoints, 0, len(*collectedDataPtr))
+func (tsr *RemoteTopSQLReporter) takeDataAndSendToReportChan(collectedDataPtr *map[string]*DataPoints) {
+	// Fetch TopN DataPoints.
+	records := make([]*DataPoints, 0, len(*collectedDataPtr))
 	for _, v := range *collectedDataPtr {
 		records = append(records, v)
 	}
@@ -334,24 +460,24 @@ func (tsr *RemoteTopSQLReporter) takeDataAndSendToReportChan(collectedDataPtr *m
 	normalizedPlanMap := tsr.normalizedPlanMap.Load().(*sync.Map)
 
 	// Reset data for next report.
-	*collectedDataPtr = make(map[string]*dataPoints)
+	*collectedDataPtr = make(map[string]*DataPoints)
 	tsr.normalizedSQLMap.Store(&sync.Map{})
 	tsr.normalizedPlanMap.Store(&sync.Map{})
 	tsr.sqlMapLength.Store(0)
 	tsr.planMapLength.Store(0)
 
 	// Evict redundant data.
-	var evicted []*dataPoints
+	var evicted []*DataPoints
 	records, evicted = getTopNDataPoints(records)
 	for _, evict := range evicted {
 		normalizedSQLMap.LoadAndDelete(string(evict.SQLDigest))
 		normalizedPlanMap.LoadAndDelete(string(evict.PlanDigest))
 	}
 
-	data := reportData{
-		collectedData:     records,
-		normalizedSQLMap:  normalizedSQLMap,
-		normalizedPlanMap: normalizedPlanMap,
+	data := ReportData{
+		CPUTimeRecords: records,
+		SQLMetas:       normalizedSQLMap,
+		PlanMetas:      normalizedPlanMap,
 	}
 
 	// Send to report channel. When channel is full, data will be dropped.
@@ -363,33 +489,37 @@ func (tsr *RemoteTopSQLReporter) takeDataAndSendToReportChan(collectedDataPtr *m
 	}
 }
 
-// reportData contains data that reporter sends to the agent
-type reportData struct {
-	collectedData     []*dataPoints
-	normalizedSQLMap  *sync.Map
-	normalizedPlanMap *sync.Map
+// ReportData contains data that reporter sends to the report clients.
+// The same ReportData is shared by all the clients, so the clients must not modify it.
+type ReportData struct {
+	// CPUTimeRecords are the collected records of the top N statements.
+	CPUTimeRecords []*DataPoints
+	// SQLMetas is a map, whose keys are SQL digest strings and values are normalized SQL strings.
+	SQLMetas *sync.Map
+	// PlanMetas is a map, whose keys are plan digest strings and values are normalized plans **in binary**.
+	PlanMetas *sync.Map
 }
 
-func (d *reportData) hasData() bool {
-	if len(d.collectedData) > 0 {
+func (d *ReportData) hasData() bool {
+	if len(d.CPUTimeRecords) > 0 {
 		return true
 	}
 	cnt := 0
-	d.normalizedSQLMap.Range(func(key, value interface{}) bool {
+	d.SQLMetas.Range(func(key, value interface{}) bool {
 		cnt++
 		return false
 	})
 	if cnt > 0 {
 		return true
 	}
-	d.normalizedPlanMap.Range(func(key, value interface{}) bool {
+	d.PlanMetas.Range(func(key, value interface{}) bool {
 		cnt++
 		return false
 	})
 	return cnt > 0
 }
 
-// reportWorker sends data to the gRPC endpoint from the `reportDataChan` one by one.
+// reportWorker sends data to the report clients from the `reportDataChan` one by one.
 func (tsr *RemoteTopSQLReporter) reportWorker() {
 	defer util.Recover("top-sql", "reportWorker", nil, false)
 
@@ -407,14 +537,13 @@ func (tsr *RemoteTopSQLReporter) reportWorker() {
 	}
 }
 
-func (tsr *RemoteTopSQLReporter) doReport(data reportData) {
+func (tsr *RemoteTopSQLReporter) doReport(data ReportData) {
 	defer util.Recover("top-sql", "doReport", nil, false)
 
 	if !data.hasData() {
 		return
 	}
 
-	agentAddr := variable.TopSQLVariable.AgentAddress.Load()
 	timeout := reportTimeout
 	failpoint.Inject("resetTimeoutForTest", func(val failpoint.Value) {
 		if val.(bool) {
@@ -425,13 +554,25 @@ func (tsr *RemoteTopSQLReporter) doReport(data reportData) {
 		}
 	})
 	ctx, cancel := context.WithTimeout(tsr.ctx, timeout)
-	start := time.Now()
-	err := tsr.client.Send(ctx, agentAddr, data)
-	if err != nil {
-		logutil.BgLogger().Warn("[top-sql] client failed to send data", zap.Error(err))
-		reportAllDurationFailedHistogram.Observe(time.Since(start).Seconds())
-	} else {
-		reportAllDurationSuccHistogram.Observe(time.Since(start).Seconds())
+	defer cancel()
+
+	// Send to the clients concurrently, so that a slow sink doesn't delay the others.
+	var wg sync.WaitGroup
+	for _, client := range tsr.getClients() {
+		wg.Add(1)
+		go func(client ReportClient) {
+			defer wg.Done()
+			defer util.Recover("top-sql", "doReport", nil, false)
+			start := time.Now()
+			err := client.Send(ctx, data)
+			if err != nil {
+				logutil.BgLogger().Warn("[top-sql] client failed to send data",
+					zap.String("client", fmt.Sprintf("%T", client)), zap.Error(err))
+				reportAllDurationFailedHistogram.Observe(time.Since(start).Seconds())
+			} else {
+				reportAllDurationSuccHistogram.Observe(time.Since(start).Seconds())
+			}
+		}(client)
 	}
-	cancel()
+	wg.Wait()
 }
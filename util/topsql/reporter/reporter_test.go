@@ -14,8 +14,15 @@
 package reporter
 
 import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -289,7 +296,7 @@ func (s *testTopSQLReporter) TestCollectCapacity(c *C) {
 	c.Assert(tsr.planMapLength.Load(), Equals, int64(20000))
 
 	variable.TopSQLVariable.MaxStatementCount.Store(5000)
-	collectedData := make(map[string]*dataPoints)
+	collectedData := make(map[string]*DataPoints)
 	tsr.doCollect(collectedData, 1, genRecord(20000))
 	c.Assert(len(collectedData), Equals, 5000)
 	c.Assert(tsr.sqlMapLength.Load(), Equals, int64(5000))
@@ -318,3 +325,252 @@ func BenchmarkTopSQL_CollectAndEvict(b *testing.B) {
 		populateCache(tsr, begin, end, uint64(i))
 	}
 }
+
+func (s *testTopSQLReporter) TestCollectStmtInstanceIDs(c *C) {
+	tsr := setupRemoteTopSQLReporter(maxSQLNum, 60, "")
+	defer tsr.Close()
+
+	collectedData := make(map[string]*DataPoints)
+	tsr.doCollect(collectedData, 1, []tracecpu.SQLCPUTimeRecord{
+		{SQLDigest: []byte("sqlDigest1"), PlanDigest: []byte("planDigest1"), CPUTimeMs: 1, StmtInstanceIDs: []string{"tidb-1:1", "tidb-1:2"}},
+	})
+	tsr.doCollect(collectedData, 2, []tracecpu.SQLCPUTimeRecord{
+		{SQLDigest: []byte("sqlDigest1"), PlanDigest: []byte("planDigest1"), CPUTimeMs: 2},
+	})
+	tsr.doCollect(collectedData, 3, []tracecpu.SQLCPUTimeRecord{
+		{SQLDigest: []byte("sqlDigest1"), PlanDigest: []byte("planDigest1"), CPUTimeMs: 3, StmtInstanceIDs: []string{"tidb-1:3"}},
+	})
+	c.Assert(collectedData, HasLen, 1)
+	for _, data := range collectedData {
+		c.Assert(data.TimestampList, DeepEquals, []uint64{1, 2, 3})
+		c.Assert(data.StmtInstanceIDsList, DeepEquals, [][]string{{"tidb-1:1", "tidb-1:2"}, nil, {"tidb-1:3"}})
+		report := newJSONReport(ReportData{CPUTimeRecords: []*DataPoints{data}, SQLMetas: &sync.Map{}, PlanMetas: &sync.Map{}}, nil)
+		c.Assert(report.Records, HasLen, 1)
+		c.Assert(report.Records[0].StmtInstanceIDsList, DeepEquals, [][]string{{"tidb-1:1", "tidb-1:2"}, nil, {"tidb-1:3"}})
+	}
+}
+
+func (s *testTopSQLReporter) TestCollectCopCPUTime(c *C) {
+	tsr := setupRemoteTopSQLReporter(maxSQLNum, 60, "")
+	defer tsr.Close()
+
+	collectedData := make(map[string]*DataPoints)
+	collectedCopData := make(map[string]map[string]uint64)
+	tsr.doCollectCopCPUTime(collectedCopData, CopCPUTimeRecord{
+		SQLDigest:           []byte("sqlDigest1"),
+		PlanDigest:          []byte("planDigest1"),
+		CPUTimeMsByPlanNode: map[string]uint32{"TableFullScan#2": 10, "Selection#1": 2},
+	})
+	// The TiDB CPU time may be sampled after the coprocessor CPU time is collected.
+	tsr.doCollect(collectedData, 1, []tracecpu.SQLCPUTimeRecord{
+		{SQLDigest: []byte("sqlDigest1"), PlanDigest: []byte("planDigest1"), CPUTimeMs: 1},
+	})
+	tsr.doCollectCopCPUTime(collectedCopData, CopCPUTimeRecord{
+		SQLDigest:           []byte("sqlDigest1"),
+		PlanDigest:          []byte("planDigest1"),
+		CPUTimeMsByPlanNode: map[string]uint32{"TableFullScan#2": 5},
+	})
+	// The statement which isn't sampled on TiDB isn't reported.
+	tsr.doCollectCopCPUTime(collectedCopData, CopCPUTimeRecord{
+		SQLDigest:           []byte("sqlDigest2"),
+		PlanDigest:          []byte("planDigest2"),
+		CPUTimeMsByPlanNode: map[string]uint32{"IndexRangeScan#1": 3},
+	})
+	mergeCopCPUTime(collectedData, collectedCopData)
+	c.Assert(collectedData, HasLen, 1)
+	data := collectedData[encodeKey(&bytes.Buffer{}, []byte("sqlDigest1"), []byte("planDigest1"), "", "")]
+	c.Assert(data.TimestampList, DeepEquals, []uint64{1})
+	c.Assert(data.CPUTimeMsTotal, Equals, uint64(1))
+	c.Assert(data.CopCPUTimeMsByPlanNode, DeepEquals, map[string]uint64{"TableFullScan#2": 15, "Selection#1": 2})
+}
+
+func (s *testTopSQLReporter) TestCollectExecStats(c *C) {
+	tsr := setupRemoteTopSQLReporter(maxSQLNum, 60, "")
+	defer tsr.Close()
+
+	collectedData := make(map[string]*DataPoints)
+	tsr.doCollect(collectedData, 1, []tracecpu.SQLCPUTimeRecord{
+		{SQLDigest: []byte("sqlDigest1"), PlanDigest: []byte("planDigest1"), CPUTimeMs: 1, ExecCount: 2, SumDurationNs: 30, SumRows: 4},
+	})
+	tsr.doCollect(collectedData, 2, []tracecpu.SQLCPUTimeRecord{
+		{SQLDigest: []byte("sqlDigest1"), PlanDigest: []byte("planDigest1"), ExecCount: 1, SumDurationNs: 10, SumRows: 1},
+	})
+	data := collectedData[encodeKey(&bytes.Buffer{}, []byte("sqlDigest1"), []byte("planDigest1"), "", "")]
+	c.Assert(data.CPUTimeMsTotal, Equals, uint64(1))
+	c.Assert(data.ExecCount, Equals, uint64(3))
+	c.Assert(data.SumDurationNs, Equals, uint64(40))
+	c.Assert(data.SumRows, Equals, uint64(5))
+
+	report := newJSONReport(ReportData{CPUTimeRecords: []*DataPoints{data}, SQLMetas: &sync.Map{}, PlanMetas: &sync.Map{}}, nil)
+	c.Assert(report.Records, HasLen, 1)
+	c.Assert(report.Records[0].ExecCount, Equals, uint64(3))
+	c.Assert(report.Records[0].SumDurationNs, Equals, uint64(40))
+	c.Assert(report.Records[0].SumRows, Equals, uint64(5))
+}
+
+func (s *testTopSQLReporter) TestCollectWaitTime(c *C) {
+	tsr := setupRemoteTopSQLReporter(maxSQLNum, 60, "")
+	defer tsr.Close()
+
+	collectedData := make(map[string]*DataPoints)
+	var waitTimeNs [tracecpu.NumWaitTypes]uint64
+	waitTimeNs[tracecpu.WaitLock] = uint64(3 * time.Second)
+	tsr.doCollect(collectedData, 1, []tracecpu.SQLCPUTimeRecord{
+		{SQLDigest: []byte("sqlDigest1"), PlanDigest: []byte("planDigest1"), CPUTimeMs: 1, ExecCount: 1, SumWaitTimeNs: waitTimeNs},
+	})
+	waitTimeNs[tracecpu.WaitTSO] = uint64(2 * time.Millisecond)
+	tsr.doCollect(collectedData, 2, []tracecpu.SQLCPUTimeRecord{
+		{SQLDigest: []byte("sqlDigest1"), PlanDigest: []byte("planDigest1"), ExecCount: 1, SumWaitTimeNs: waitTimeNs},
+	})
+	data := collectedData[encodeKey(&bytes.Buffer{}, []byte("sqlDigest1"), []byte("planDigest1"), "", "")]
+	c.Assert(data.SumWaitTimeNs[tracecpu.WaitLock], Equals, uint64(6*time.Second))
+	c.Assert(data.SumWaitTimeNs[tracecpu.WaitTSO], Equals, uint64(2*time.Millisecond))
+
+	report := newJSONReport(ReportData{CPUTimeRecords: []*DataPoints{data}, SQLMetas: &sync.Map{}, PlanMetas: &sync.Map{}}, nil)
+	c.Assert(report.Records, HasLen, 1)
+	c.Assert(report.Records[0].WaitTimeMsByType, DeepEquals, map[string]uint64{"lock": 6000, "tso": 2})
+	agg := AggregateJSONReports([]*JSONReport{report}, false, false)
+	c.Assert(agg, HasLen, 1)
+	c.Assert(agg[0].WaitTimeMs, Equals, uint64(6002))
+
+	// The wait time isn't reported to the gRPC agent, so the statements are still ranked by the CPU time.
+	variable.TopSQLVariable.MaxStatementCount.Store(1)
+	defer variable.TopSQLVariable.MaxStatementCount.Store(maxSQLNum)
+	cpuIntensive := &DataPoints{CPUTimeMsTotal: 100}
+	topN, evicted := getTopNDataPoints([]*DataPoints{cpuIntensive, data})
+	c.Assert(topN, HasLen, 1)
+	c.Assert(topN[0], Equals, cpuIntensive)
+	c.Assert(evicted, HasLen, 1)
+}
+
+func (s *testTopSQLReporter) TestMultipleReportClients(c *C) {
+	agentServer, err := mock.StartMockAgentServer()
+	c.Assert(err, IsNil)
+	defer agentServer.Stop()
+
+	memClient := NewMemoryReportClient()
+	filePath := filepath.Join(c.MkDir(), "top_sql.log")
+	tsr := setupRemoteTopSQLReporter(maxSQLNum, 1, agentServer.Address())
+	tsr.RegisterClient(memClient)
+	tsr.RegisterClient(NewFileReportClient(filePath, mockPlanBinaryDecoderFunc))
+	populateCache(tsr, 0, 10, 1)
+
+	// Each client receives all the data.
+	agentServer.WaitCollectCnt(1, time.Second*5)
+	c.Assert(agentServer.GetLatestRecords(), HasLen, 10)
+	var reported []ReportData
+	for i := 0; i < 50 && len(reported) == 0; i++ {
+		time.Sleep(100 * time.Millisecond)
+		reported = memClient.GetReportedData()
+	}
+	c.Assert(reported, HasLen, 1)
+	c.Assert(reported[0].CPUTimeRecords, HasLen, 10)
+	normalizedSQL, ok := reported[0].SQLMetas.Load("sqlDigest1")
+	c.Assert(ok, IsTrue)
+	c.Assert(normalizedSQL, Equals, "sqlNormalized1")
+
+	// The file is closed along with the reporter.
+	tsr.Close()
+	content, err := os.ReadFile(filePath)
+	c.Assert(err, IsNil)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	c.Assert(lines, HasLen, 1)
+	var report JSONReport
+	c.Assert(json.Unmarshal([]byte(lines[0]), &report), IsNil)
+	c.Assert(report.Records, HasLen, 10)
+	c.Assert(report.SQLMetas, HasLen, 10)
+	c.Assert(report.SQLMetas[hex.EncodeToString([]byte("sqlDigest1"))], Equals, "sqlNormalized1")
+	c.Assert(report.PlanMetas[hex.EncodeToString([]byte("planDigest1"))], Equals, "planNormalized1")
+}
+
+func (s *testTopSQLReporter) TestLocalReportClient(c *C) {
+	client := NewLocalReportClient(mockPlanBinaryDecoderFunc, time.Minute)
+	newReportData := func(i int) ReportData {
+		data := ReportData{
+			CPUTimeRecords: []*DataPoints{{
+				SQLDigest:     []byte("sqlDigest" + strconv.Itoa(i)),
+				PlanDigest:    []byte("planDigest" + strconv.Itoa(i)),
+				TimestampList: []uint64{uint64(i)},
+				CPUTimeMsList: []uint32{uint32(i)},
+			}},
+			SQLMetas:  &sync.Map{},
+			PlanMetas: &sync.Map{},
+		}
+		data.SQLMetas.Store("sqlDigest"+strconv.Itoa(i), "sqlNormalized"+strconv.Itoa(i))
+		data.PlanMetas.Store("planDigest"+strconv.Itoa(i), "planNormalized"+strconv.Itoa(i))
+		return data
+	}
+
+	start := time.Now()
+	c.Assert(client.Send(context.Background(), newReportData(1)), IsNil)
+	c.Assert(client.Send(context.Background(), newReportData(2)), IsNil)
+	reports := client.GetReports(start.Add(-time.Second))
+	c.Assert(reports, HasLen, 2)
+	c.Assert(reports[0].Records, HasLen, 1)
+	c.Assert(reports[0].Records[0].SQLDigest, Equals, hex.EncodeToString([]byte("sqlDigest1")))
+	c.Assert(reports[0].Records[0].CPUTimeMsList, DeepEquals, []uint32{1})
+	c.Assert(reports[1].SQLMetas[hex.EncodeToString([]byte("sqlDigest2"))], Equals, "sqlNormalized2")
+	c.Assert(reports[1].PlanMetas[hex.EncodeToString([]byte("planDigest2"))], Equals, "planNormalized2")
+	c.Assert(client.GetReports(time.Now().Add(time.Minute)), HasLen, 0)
+
+	// The reports out of the retention are removed.
+	client.mu.Lock()
+	for _, report := range client.reports {
+		report.Timestamp -= 120
+	}
+	client.mu.Unlock()
+	c.Assert(client.Send(context.Background(), newReportData(3)), IsNil)
+	reports = client.GetReports(start.Add(-time.Hour))
+	c.Assert(reports, HasLen, 1)
+	c.Assert(reports[0].Records[0].SQLDigest, Equals, hex.EncodeToString([]byte("sqlDigest3")))
+
+	client.Close()
+	c.Assert(client.GetReports(start.Add(-time.Hour)), HasLen, 0)
+}
+
+func (s *testTopSQLReporter) TestCollectByDBAndUser(c *C) {
+	tsr := setupRemoteTopSQLReporter(maxSQLNum, 60, "")
+	defer tsr.Close()
+
+	collectedData := make(map[string]*DataPoints)
+	tsr.doCollect(collectedData, 1, []tracecpu.SQLCPUTimeRecord{
+		{SQLDigest: []byte("sqlDigest1"), PlanDigest: []byte("planDigest1"), DB: "db1", User: "u1", CPUTimeMs: 1},
+		{SQLDigest: []byte("sqlDigest1"), PlanDigest: []byte("planDigest1"), DB: "db1", User: "u2", CPUTimeMs: 2},
+		{SQLDigest: []byte("sqlDigest1"), PlanDigest: []byte("planDigest1"), DB: "db2", User: "u1", CPUTimeMs: 4, ExecCount: 1},
+	})
+	collectedCopData := make(map[string]map[string]uint64)
+	tsr.doCollectCopCPUTime(collectedCopData, CopCPUTimeRecord{
+		SQLDigest:           []byte("sqlDigest1"),
+		PlanDigest:          []byte("planDigest1"),
+		DB:                  "db1",
+		User:                "u2",
+		CPUTimeMsByPlanNode: map[string]uint32{"TableFullScan#1": 8},
+	})
+	mergeCopCPUTime(collectedData, collectedCopData)
+	// The same statement of different databases and users is collected separately.
+	c.Assert(collectedData, HasLen, 3)
+	data := collectedData[encodeKey(&bytes.Buffer{}, []byte("sqlDigest1"), []byte("planDigest1"), "db1", "u2")]
+	c.Assert(data.DB, Equals, "db1")
+	c.Assert(data.User, Equals, "u2")
+	c.Assert(data.CPUTimeMsTotal, Equals, uint64(2))
+	c.Assert(data.CopCPUTimeMsByPlanNode, DeepEquals, map[string]uint64{"TableFullScan#1": 8})
+
+	records := make([]*DataPoints, 0, len(collectedData))
+	for _, data := range collectedData {
+		records = append(records, data)
+	}
+	report := newJSONReport(ReportData{CPUTimeRecords: records, SQLMetas: &sync.Map{}, PlanMetas: &sync.Map{}}, nil)
+	reports := []*JSONReport{report}
+	byDB := AggregateJSONReports(reports, true, false)
+	c.Assert(byDB, DeepEquals, []*JSONAggregatedRecord{
+		{DB: "db2", CPUTimeMs: 4, ExecCount: 1},
+		{DB: "db1", CPUTimeMs: 3, CopCPUTimeMs: 8},
+	})
+	byUser := AggregateJSONReports(reports, false, true)
+	c.Assert(byUser, DeepEquals, []*JSONAggregatedRecord{
+		{User: "u1", CPUTimeMs: 5, ExecCount: 1},
+		{User: "u2", CPUTimeMs: 2, CopCPUTimeMs: 8},
+	})
+	c.Assert(AggregateJSONReports(reports, true, true), HasLen, 3)
+	c.Assert(AggregateJSONReports(nil, true, true), HasLen, 0)
+}
@@ -17,10 +17,12 @@ import (
 	"context"
 	"runtime/pprof"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/plancodec"
 	"github.com/pingcap/tidb/util/topsql/reporter"
@@ -33,18 +35,49 @@ const (
 	MaxSQLTextSize = 4 * 1024
 	// MaxPlanTextSize exports for testing.
 	MaxPlanTextSize = 32 * 1024
+	// localReportRetention is how long the reports are kept for querying locally.
+	localReportRetention = 30 * time.Minute
 )
 
-var globalTopSQLReport reporter.TopSQLReporter
+var (
+	globalTopSQLReport *reporter.RemoteTopSQLReporter
+	// globalLocalReportClient keeps the recent reports, which can be queried by GetRecentReports.
+	globalLocalReportClient = reporter.NewLocalReportClient(plancodec.DecodeNormalizedPlan, localReportRetention)
+
+	// pendingReportClients are the clients registered before SetupTopSQL.
+	pendingReportClientsMu sync.Mutex
+	pendingReportClients   []reporter.ReportClient
+)
 
 // SetupTopSQL sets up the top-sql worker.
 func SetupTopSQL() {
 	rc := reporter.NewGRPCReportClient(plancodec.DecodeNormalizedPlan)
-	globalTopSQLReport = reporter.NewRemoteTopSQLReporter(rc)
+	pendingReportClientsMu.Lock()
+	globalTopSQLReport = reporter.NewRemoteTopSQLReporter(append([]reporter.ReportClient{rc, globalLocalReportClient}, pendingReportClients...)...)
+	pendingReportClients = nil
+	pendingReportClientsMu.Unlock()
 	tracecpu.GlobalSQLCPUProfiler.SetCollector(globalTopSQLReport)
 	tracecpu.GlobalSQLCPUProfiler.Run()
 }
 
+// RegisterReportClient registers an additional client which the Top SQL data is reported to, besides the gRPC agent.
+// It can be called either before or after SetupTopSQL.
+func RegisterReportClient(client reporter.ReportClient) {
+	pendingReportClientsMu.Lock()
+	defer pendingReportClientsMu.Unlock()
+	if globalTopSQLReport != nil {
+		globalTopSQLReport.RegisterClient(client)
+		return
+	}
+	pendingReportClients = append(pendingReportClients, client)
+}
+
+// GetRecentReports returns the Top SQL data reported in the recent duration, which is at most 30 minutes.
+// Note that the data which is still being collected in the current report interval is not included.
+func GetRecentReports(duration time.Duration) []*reporter.JSONReport {
+	return globalLocalReportClient.GetReports(time.Now().Add(-duration))
+}
+
 // Close uses to close and release the top sql resource.
 func Close() {
 	if globalTopSQLReport != nil {
@@ -67,6 +100,7 @@ func AttachSQLInfo(ctx context.Context, normalizedSQL string, sqlDigest *parser.
 
 	if len(normalizedPlan) == 0 || len(planDigestBytes) == 0 {
 		// If plan digest is '', indicate it is the first time to attach the SQL info, since it only know the sql digest.
+		tracecpu.GlobalSQLCPUProfiler.RecordStatementStart()
 		linkSQLTextWithDigest(sqlDigestBytes, normalizedSQL)
 	} else {
 		linkPlanTextWithDigest(planDigestBytes, normalizedPlan)
@@ -105,6 +139,59 @@ func AttachSQLInfo(ctx context.Context, normalizedSQL string, sqlDigest *parser.
 	return ctx
 }
 
+// AttachSessionInfo attach the current database and the user of the session into the context, which takes effect on
+// the goroutine labels in the next AttachSQLInfo, so that the Top SQL records can be aggregated by them.
+func AttachSessionInfo(ctx context.Context, db string, user *auth.UserIdentity) context.Context {
+	return tracecpu.CtxWithSessionInfo(ctx, db, UserName(user))
+}
+
+// UserName returns the user name which the Top SQL records are aggregated by, it's empty for the internal sessions.
+func UserName(user *auth.UserIdentity) string {
+	if user == nil {
+		return ""
+	}
+	return user.Username
+}
+
+// RecordCopCPUTime records the CPU time of the coprocessor tasks of a statement execution, which is broken down by
+// the identities of the pushed down plan nodes in the normalized plan, see plannercore.NormalizedPlanNodeIDs.
+func RecordCopCPUTime(sqlDigest, planDigest []byte, db, user string, cpuTimeByPlanNode map[string]time.Duration) {
+	if len(sqlDigest) == 0 || len(cpuTimeByPlanNode) == 0 {
+		return
+	}
+	c := tracecpu.GlobalSQLCPUProfiler.GetCollector()
+	if c == nil {
+		return
+	}
+	topc, ok := c.(reporter.TopSQLReporter)
+	if !ok {
+		return
+	}
+	cpuTimeMsByPlanNode := make(map[string]uint32, len(cpuTimeByPlanNode))
+	for planNode, cpuTime := range cpuTimeByPlanNode {
+		if ms := cpuTime.Milliseconds(); ms > 0 {
+			cpuTimeMsByPlanNode[planNode] = uint32(ms)
+		}
+	}
+	topc.CollectCopCPUTime(reporter.CopCPUTimeRecord{
+		SQLDigest:           sqlDigest,
+		PlanDigest:          planDigest,
+		DB:                  db,
+		User:                user,
+		CPUTimeMsByPlanNode: cpuTimeMsByPlanNode,
+	})
+}
+
+// RecordExecution records the latency, the processed rows and the wait time of a finished statement execution, the
+// wait time is indexed by tracecpu.WaitType. The statement instance ID is reported with the statistics, so that the
+// Top SQL records can be correlated with slow log and statements_summary.
+func RecordExecution(sqlDigest, planDigest []byte, db, user, stmtInstanceID string, latency time.Duration, rows uint64, waitTime [tracecpu.NumWaitTypes]time.Duration) {
+	if len(sqlDigest) == 0 {
+		return
+	}
+	tracecpu.GlobalSQLCPUProfiler.RecordExecution(sqlDigest, planDigest, db, user, stmtInstanceID, latency, rows, waitTime)
+}
+
 func linkSQLTextWithDigest(sqlDigest []byte, normalizedSQL string) {
 	if len(normalizedSQL) > MaxSQLTextSize {
 		normalizedSQL = normalizedSQL[:MaxSQLTextSize]
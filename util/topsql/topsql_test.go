@@ -22,6 +22,7 @@ import (
 	"github.com/google/pprof/profile"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/auth"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/topsql"
 	"github.com/pingcap/tidb/util/topsql/reporter"
@@ -222,12 +223,134 @@ func (s *testSuite) TestMaxSQLAndPlanTest(c *C) {
 	c.Assert(cPlan, Equals, "")
 }
 
+func (s *testSuite) TestRecordCopCPUTime(c *C) {
+	collector := mock.NewTopSQLCollector()
+	tracecpu.GlobalSQLCPUProfiler.SetCollector(&collectorWrapper{collector})
+
+	sqlDigest := mock.GenSQLDigest("select count(*) from t where a > ?")
+	planDigest := genDigest("StreamAgg Selection TableFullScan")
+	topsql.RecordCopCPUTime(sqlDigest.Bytes(), planDigest.Bytes(), "", "", map[string]time.Duration{
+		"TableFullScan#2": 20 * time.Millisecond,
+		"Selection#1":     3 * time.Millisecond,
+		// The time less than 1ms is ignored.
+		"StreamAgg#0": 500 * time.Microsecond,
+	})
+	topsql.RecordCopCPUTime(sqlDigest.Bytes(), planDigest.Bytes(), "", "", map[string]time.Duration{
+		"TableFullScan#2": 10 * time.Millisecond,
+	})
+	c.Assert(collector.GetCopCPUTime(sqlDigest.Bytes(), planDigest.Bytes()), DeepEquals,
+		map[string]uint32{"TableFullScan#2": 30, "Selection#1": 3})
+
+	// The statement without SQL digest is ignored.
+	topsql.RecordCopCPUTime(nil, planDigest.Bytes(), "", "", map[string]time.Duration{"TableFullScan#2": time.Second})
+	c.Assert(collector.GetCopCPUTime(nil, planDigest.Bytes()), IsNil)
+}
+
+func (s *testSuite) TestRecordExecution(c *C) {
+	collector := mock.NewTopSQLCollector()
+	tracecpu.GlobalSQLCPUProfiler.SetCollector(&collectorWrapper{collector})
+	s.setTopSQLEnable(true)
+
+	sql := "select * from t where a = ?"
+	sqlDigest := mock.GenSQLDigest(sql)
+	planDigest := genDigest("Point_Get")
+	collector.RegisterSQL(sqlDigest.Bytes(), sql)
+	collector.RegisterPlan(planDigest.Bytes(), "Point_Get")
+	var waitTime [tracecpu.NumWaitTypes]time.Duration
+	waitTime[tracecpu.WaitLock] = time.Millisecond
+	topsql.RecordExecution(sqlDigest.Bytes(), planDigest.Bytes(), "test", "root", "tidb-1:1", 2*time.Millisecond, 1, waitTime)
+	waitTime[tracecpu.WaitTSO] = time.Millisecond
+	topsql.RecordExecution(sqlDigest.Bytes(), planDigest.Bytes(), "test", "root", "tidb-1:2", 3*time.Millisecond, 0, waitTime)
+	// The statement without SQL digest is ignored.
+	topsql.RecordExecution(nil, planDigest.Bytes(), "test", "root", "tidb-1:3", time.Second, 1, waitTime)
+
+	// The statement is collected even if it isn't sampled by the CPU profiler.
+	stats := collector.GetSQLStatsBySQLWithRetry(sql, true)
+	c.Assert(stats, HasLen, 1)
+	c.Assert(stats[0].DB, Equals, "test")
+	c.Assert(stats[0].User, Equals, "root")
+	c.Assert(stats[0].ExecCount, Equals, uint32(2))
+	c.Assert(stats[0].SumDurationNs, Equals, uint64(5*time.Millisecond))
+	c.Assert(stats[0].SumRows, Equals, uint64(1))
+	c.Assert(stats[0].SumWaitTimeNs, Equals, [tracecpu.NumWaitTypes]uint64{uint64(2 * time.Millisecond), 0, uint64(time.Millisecond), 0})
+	c.Assert(stats[0].StmtInstanceIDs, DeepEquals, []string{"tidb-1:1", "tidb-1:2"})
+}
+
+func (s *testSuite) TestSessionInfo(c *C) {
+	collector := mock.NewTopSQLCollector()
+	tracecpu.GlobalSQLCPUProfiler.SetCollector(&collectorWrapper{collector})
+	s.setTopSQLEnable(true)
+
+	sql := "select * from t where b=?"
+	plan := "table-scan"
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, user := range []string{"tenant1", "tenant2"} {
+		go func(user string) {
+			sessCtx := topsql.AttachSessionInfo(context.Background(), "db_"+user, &auth.UserIdentity{Username: user, Hostname: "%"})
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+					s.mockExecuteSQLWithCtx(sessCtx, sql, plan)
+				}
+			}
+		}(user)
+	}
+
+	// The CPU time of the same statement is recorded separately for each database and user.
+	var stats []*tracecpu.SQLCPUTimeRecord
+	for i := 0; i < 10 && len(stats) < 2; i++ {
+		collector.WaitCollectCnt(1)
+		stats = collector.GetSQLStatsBySQL(sql, true)
+	}
+	c.Assert(stats, HasLen, 2)
+	users := make(map[string]string)
+	for _, stat := range stats {
+		c.Assert(stat.CPUTimeMs, Greater, uint32(0))
+		users[stat.User] = stat.DB
+	}
+	c.Assert(users, DeepEquals, map[string]string{"tenant1": "db_tenant1", "tenant2": "db_tenant2"})
+}
+
+func (s *testSuite) TestOverheadShedding(c *C) {
+	collector := mock.NewTopSQLCollector()
+	tracecpu.GlobalSQLCPUProfiler.SetCollector(&collectorWrapper{collector})
+	s.setTopSQLEnable(true)
+
+	// Any overhead exceeds the tiny limit.
+	variable.TopSQLVariable.MaxOverheadPercentage.Store(1e-9)
+	shedding := false
+	for i := 0; i < 50 && !shedding; i++ {
+		time.Sleep(100 * time.Millisecond)
+		shedding = tracecpu.GlobalSQLCPUProfiler.IsShedding()
+	}
+	c.Assert(shedding, IsTrue)
+
+	// The collection isn't paused while exporting the profile.
+	c.Assert(tracecpu.StartCPUProfile(bytes.NewBuffer(nil)), IsNil)
+	c.Assert(tracecpu.GlobalSQLCPUProfiler.IsShedding(), IsFalse)
+	c.Assert(tracecpu.StopCPUProfile(), IsNil)
+
+	// 0 means no limit.
+	variable.TopSQLVariable.MaxOverheadPercentage.Store(0)
+	c.Assert(tracecpu.GlobalSQLCPUProfiler.IsShedding(), IsFalse)
+	// The pause is cleared once the overhead is within the limit.
+	collector.WaitCollectCnt(2)
+	variable.TopSQLVariable.MaxOverheadPercentage.Store(variable.DefTiDBTopSQLMaxOverheadPercentage)
+	c.Assert(tracecpu.GlobalSQLCPUProfiler.IsShedding(), IsFalse)
+}
+
 func (s *testSuite) setTopSQLEnable(enabled bool) {
 	variable.TopSQLVariable.Enable.Store(enabled)
 }
 
 func (s *testSuite) mockExecuteSQL(sql, plan string) {
-	ctx := context.Background()
+	s.mockExecuteSQLWithCtx(context.Background(), sql, plan)
+}
+
+func (s *testSuite) mockExecuteSQLWithCtx(ctx context.Context, sql, plan string) {
 	sqlDigest := mock.GenSQLDigest(sql)
 	topsql.AttachSQLInfo(ctx, sql, sqlDigest, "", nil)
 	s.mockExecute(time.Millisecond * 100)
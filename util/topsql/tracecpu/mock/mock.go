@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/parser"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/topsql/reporter"
 	"github.com/pingcap/tidb/util/topsql/tracecpu"
 	"github.com/uber-go/atomic"
 	"go.uber.org/zap"
@@ -35,15 +36,18 @@ type TopSQLCollector struct {
 	planMap map[string]string
 	// (sql + plan_digest) -> sql stats
 	sqlStatsMap map[string]*tracecpu.SQLCPUTimeRecord
-	collectCnt  atomic.Int64
+	// (sql + plan_digest) -> plan node -> coprocessor cpu time
+	copCPUTimeMap map[string]map[string]uint32
+	collectCnt    atomic.Int64
 }
 
 // NewTopSQLCollector uses for testing.
 func NewTopSQLCollector() *TopSQLCollector {
 	return &TopSQLCollector{
-		sqlMap:      make(map[string]string),
-		planMap:     make(map[string]string),
-		sqlStatsMap: make(map[string]*tracecpu.SQLCPUTimeRecord),
+		sqlMap:        make(map[string]string),
+		planMap:       make(map[string]string),
+		sqlStatsMap:   make(map[string]*tracecpu.SQLCPUTimeRecord),
+		copCPUTimeMap: make(map[string]map[string]uint32),
 	}
 }
 
@@ -62,10 +66,19 @@ func (c *TopSQLCollector) Collect(ts uint64, stats []tracecpu.SQLCPUTimeRecord)
 			stats = &tracecpu.SQLCPUTimeRecord{
 				SQLDigest:  stmt.SQLDigest,
 				PlanDigest: stmt.PlanDigest,
+				DB:         stmt.DB,
+				User:       stmt.User,
 			}
 			c.sqlStatsMap[hash] = stats
 		}
 		stats.CPUTimeMs += stmt.CPUTimeMs
+		stats.ExecCount += stmt.ExecCount
+		stats.SumDurationNs += stmt.SumDurationNs
+		stats.SumRows += stmt.SumRows
+		for i, waitTimeNs := range stmt.SumWaitTimeNs {
+			stats.SumWaitTimeNs[i] += waitTimeNs
+		}
+		stats.StmtInstanceIDs = append(stats.StmtInstanceIDs, stmt.StmtInstanceIDs...)
 		logutil.BgLogger().Info("mock top sql collector collected sql",
 			zap.String("sql", c.sqlMap[string(stmt.SQLDigest)]),
 			zap.Bool("has-plan", len(c.planMap[string(stmt.PlanDigest)]) > 0))
@@ -149,6 +162,28 @@ func (c *TopSQLCollector) RegisterPlan(planDigest []byte, normalizedPlan string)
 	c.Unlock()
 }
 
+// CollectCopCPUTime uses for testing.
+func (c *TopSQLCollector) CollectCopCPUTime(record reporter.CopCPUTimeRecord) {
+	hash := string(record.SQLDigest) + string(record.PlanDigest)
+	c.Lock()
+	defer c.Unlock()
+	cpuTimeByPlanNode, ok := c.copCPUTimeMap[hash]
+	if !ok {
+		cpuTimeByPlanNode = make(map[string]uint32)
+		c.copCPUTimeMap[hash] = cpuTimeByPlanNode
+	}
+	for planNode, cpuTimeMs := range record.CPUTimeMsByPlanNode {
+		cpuTimeByPlanNode[planNode] += cpuTimeMs
+	}
+}
+
+// GetCopCPUTime uses for testing.
+func (c *TopSQLCollector) GetCopCPUTime(sqlDigest, planDigest []byte) map[string]uint32 {
+	c.Lock()
+	defer c.Unlock()
+	return c.copCPUTimeMap[string(sqlDigest)+string(planDigest)]
+}
+
 // WaitCollectCnt uses for testing.
 func (c *TopSQLCollector) WaitCollectCnt(count int64) {
 	timeout := time.After(time.Second * 10)
@@ -171,7 +206,7 @@ func (c *TopSQLCollector) WaitCollectCnt(count int64) {
 func (c *TopSQLCollector) Close() {}
 
 func (c *TopSQLCollector) hash(stat tracecpu.SQLCPUTimeRecord) string {
-	return string(stat.SQLDigest) + string(stat.PlanDigest)
+	return string(stat.SQLDigest) + string(stat.PlanDigest) + "\x00" + stat.DB + "\x00" + stat.User
 }
 
 // GenSQLDigest uses for testing.
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/google/pprof/profile"
+	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/hack"
@@ -38,6 +39,22 @@ const (
 	labelSQL        = "sql"
 	labelSQLDigest  = "sql_digest"
 	labelPlanDigest = "plan_digest"
+	labelDB         = "db"
+	labelUser       = "user"
+)
+
+// MaxStmtInstanceIDsPerRecord is the max number of statement instance IDs kept in one SQLCPUTimeRecord.
+const MaxStmtInstanceIDsPerRecord = 64
+
+// maxShedLevel is the max level of shedding, the collection is paused for at most 2^maxShedLevel profiling windows.
+const maxShedLevel = 5
+
+const (
+	// adaptiveHighQPS is the QPS above which the profiling window is enlarged in the adaptive mode, and the window is
+	// shrunk when the QPS falls below the half of it.
+	adaptiveHighQPS = 1000
+	// maxWindowFactor is the max times of the profiling window to TopSQLVariable.PrecisionSeconds.
+	maxWindowFactor = 8
 )
 
 // GlobalSQLCPUProfiler is the global SQL stats profiler.
@@ -50,6 +67,29 @@ type Collector interface {
 	Collect(ts uint64, stats []SQLCPUTimeRecord)
 }
 
+// WaitType is the category of the time that a statement execution spends on waiting rather than running on CPU.
+type WaitType int
+
+const (
+	// WaitLock is the time of acquiring the pessimistic locks and the local latches.
+	WaitLock WaitType = iota
+	// WaitCopQueue is the time that the coprocessor tasks wait in the queue of the storage.
+	WaitCopQueue
+	// WaitTSO is the time of fetching the timestamps from PD.
+	WaitTSO
+	// WaitBackoff is the time of backing off on the retryable errors, e.g. the region errors.
+	WaitBackoff
+	// NumWaitTypes is the number of the wait types.
+	NumWaitTypes
+)
+
+var waitTypeNames = [NumWaitTypes]string{"lock", "cop_queue", "tso", "backoff"}
+
+// String implements the fmt.Stringer interface.
+func (t WaitType) String() string {
+	return waitTypeNames[t]
+}
+
 // SQLCPUTimeRecord represents a single record of how much cpu time a sql plan consumes in one second.
 //
 // PlanDigest can be empty, because:
@@ -58,7 +98,23 @@ type Collector interface {
 type SQLCPUTimeRecord struct {
 	SQLDigest  []byte
 	PlanDigest []byte
-	CPUTimeMs  uint32
+	// DB and User are the current database and the user of the session which executes the statement, so that the
+	// records can be aggregated by the tenants.
+	DB        string
+	User      string
+	CPUTimeMs uint32
+	// ExecCount, SumDurationNs and SumRows are the statistics of the executions finished in this second, so that a
+	// statement that is hot because it is frequent can be told from the one that is hot because it is slow.
+	// The record of a statement which finishes quickly may have no CPU time.
+	ExecCount     uint32
+	SumDurationNs uint64
+	SumRows       uint64
+	// SumWaitTimeNs is the wait time of the executions finished in this second, indexed by WaitType.
+	SumWaitTimeNs [NumWaitTypes]uint64
+	// StmtInstanceIDs are the IDs of the executions finished in this second, it is the same ID as the
+	// `Stmt_instance_id` in slow log, which can be used to join a record with full statement context exactly.
+	// At most MaxStmtInstanceIDsPerRecord IDs are kept.
+	StmtInstanceIDs []string
 }
 
 type sqlCPUProfiler struct {
@@ -69,8 +125,33 @@ type sqlCPUProfiler struct {
 		ept *exportProfileTask
 	}
 	collector atomic.Value
+
+	// execStats are the statistics of the finished executions since the last profiling window.
+	execStats struct {
+		sync.Mutex
+		m map[execStatsKey]*execStats
+	}
+
+	// shedLevel is the number of consecutive profiling windows whose overhead exceeds the limit, it is only accessed
+	// by the analyze worker.
+	shedLevel uint
+	// pauseUntil is the Unix time in nanoseconds until which the collection is paused.
+	pauseUntil int64
+
+	// stmtCount is the number of the statements started in the current profiling window.
+	stmtCount int64
+	// windowFactor is the times of the profiling window to TopSQLVariable.PrecisionSeconds, it's adjusted by the QPS
+	// in the adaptive mode and only accessed by the profile worker.
+	windowFactor int64
+	// idle is 1 if there is no SQL CPU time or execution in the last profiling window.
+	idle int32
 }
 
+var (
+	overheadCPUGauge    = metrics.TopSQLOverheadGauge.WithLabelValues("cpu")
+	overheadMemoryGauge = metrics.TopSQLOverheadGauge.WithLabelValues("memory")
+)
+
 var (
 	defaultProfileBufSize = 100 * 1024
 	profileBufPool        = sync.Pool{
@@ -83,7 +164,8 @@ var (
 // newSQLCPUProfiler create a sqlCPUProfiler.
 func newSQLCPUProfiler() *sqlCPUProfiler {
 	return &sqlCPUProfiler{
-		taskCh: make(chan *profileData, 128),
+		taskCh:       make(chan *profileData, 128),
+		windowFactor: 1,
 	}
 }
 
@@ -108,7 +190,7 @@ func (sp *sqlCPUProfiler) GetCollector() Collector {
 func (sp *sqlCPUProfiler) startCPUProfileWorker() {
 	defer util.Recover("top-sql", "profileWorker", nil, false)
 	for {
-		if sp.IsEnabled() {
+		if sp.IsEnabled() && !sp.IsShedding() && !sp.IsIdle() {
 			sp.doCPUProfile()
 		} else {
 			time.Sleep(time.Second)
@@ -117,8 +199,10 @@ func (sp *sqlCPUProfiler) startCPUProfileWorker() {
 }
 
 func (sp *sqlCPUProfiler) doCPUProfile() {
-	intervalSecond := variable.TopSQLVariable.PrecisionSeconds.Load()
+	precisionSeconds := variable.TopSQLVariable.PrecisionSeconds.Load()
+	intervalSecond := precisionSeconds * sp.windowFactor
 	task := sp.newProfileTask()
+	task.precisionSeconds, task.windowFactor = precisionSeconds, sp.windowFactor
 	if err := pprof.StartCPUProfile(task.buf); err != nil {
 		// Sleep a while before retry.
 		time.Sleep(time.Second)
@@ -133,12 +217,45 @@ func (sp *sqlCPUProfiler) doCPUProfile() {
 		task.end = 0
 	}
 	sp.taskCh <- task
+	sp.adjustWindowFactor(atomic.SwapInt64(&sp.stmtCount, 0), intervalSecond)
+}
+
+// adjustWindowFactor adjusts the profiling window by the QPS of the last window in the adaptive mode. The window is
+// doubled under high QPS to reduce the times of analyzing the profiles, and halved when the QPS falls back. The
+// records of a widened window are still collected per PrecisionSeconds, see spreadCPUTime.
+func (sp *sqlCPUProfiler) adjustWindowFactor(stmtCount, intervalSecond int64) {
+	if !variable.TopSQLVariable.AdaptiveInterval.Load() || intervalSecond <= 0 {
+		sp.windowFactor = 1
+		return
+	}
+	qps := stmtCount / intervalSecond
+	switch {
+	case qps >= adaptiveHighQPS && sp.windowFactor < maxWindowFactor:
+		sp.windowFactor *= 2
+	case qps < adaptiveHighQPS/2 && sp.windowFactor > 1:
+		sp.windowFactor /= 2
+	}
+}
+
+// RecordStatementStart records a started statement, which is used to measure the load in the adaptive mode.
+func (sp *sqlCPUProfiler) RecordStatementStart() {
+	atomic.AddInt64(&sp.stmtCount, 1)
+}
+
+// IsIdle returns true if the profiling is suspended in the adaptive mode, because there was no SQL CPU time or
+// execution in the last profiling window and no statement has started since then. It exports for tests.
+func (sp *sqlCPUProfiler) IsIdle() bool {
+	if !variable.TopSQLVariable.AdaptiveInterval.Load() || sp.hasExportProfileTask() {
+		return false
+	}
+	return atomic.LoadInt32(&sp.idle) == 1 && atomic.LoadInt64(&sp.stmtCount) == 0
 }
 
 func (sp *sqlCPUProfiler) startAnalyzeProfileWorker() {
 	defer util.Recover("top-sql", "analyzeProfileWorker", nil, false)
 	for {
 		task := <-sp.taskCh
+		start := time.Now()
 		p, err := profile.ParseData(task.buf.Bytes())
 		if err != nil {
 			logutil.BgLogger().Error("parse profile error", zap.Error(err))
@@ -146,17 +263,101 @@ func (sp *sqlCPUProfiler) startAnalyzeProfileWorker() {
 			continue
 		}
 		stats := sp.parseCPUProfileBySQLLabels(p)
+		stats = sp.mergeExecStats(stats)
+		if len(stats) == 0 {
+			atomic.StoreInt32(&sp.idle, 1)
+		} else {
+			atomic.StoreInt32(&sp.idle, 0)
+		}
 		sp.handleExportProfileTask(p)
 		if c := sp.GetCollector(); c != nil {
-			c.Collect(uint64(task.end), stats)
+			// Keep a timestamp for each PrecisionSeconds when the window is widened in the adaptive mode.
+			spread := spreadCPUTime(stats, task.windowFactor)
+			for i, s := range spread {
+				ts := task.end - int64(len(spread)-1-i)*task.precisionSeconds
+				if ts >= 0 && (len(s) > 0 || i == len(spread)-1) {
+					c.Collect(uint64(ts), s)
+				}
+			}
 		}
+		sp.handleOverhead(time.Since(start), p.DurationNanos, task.buf.Len())
 		sp.putTaskToBuffer(task)
 	}
 }
 
+// handleOverhead measures the overhead of analyzing a profile, and pauses the collection if the overhead exceeds
+// TopSQLVariable.MaxOverheadPercentage. The CPU overhead is the time spent on analyzing in percentage of the profiling
+// window, the pause is doubled each time the overhead of the consecutive profiling windows exceeds the limit.
+func (sp *sqlCPUProfiler) handleOverhead(cost time.Duration, windowNanos int64, profileSize int) {
+	if windowNanos <= 0 {
+		return
+	}
+	percentage := float64(cost) / float64(windowNanos) * 100
+	overheadCPUGauge.Set(percentage)
+	overheadMemoryGauge.Set(float64(profileSize))
+
+	maxPercentage := variable.TopSQLVariable.MaxOverheadPercentage.Load()
+	if maxPercentage <= 0 || percentage <= maxPercentage {
+		sp.shedLevel = 0
+		atomic.StoreInt64(&sp.pauseUntil, 0)
+		return
+	}
+	if sp.shedLevel < maxShedLevel {
+		sp.shedLevel++
+	}
+	pause := time.Duration(windowNanos) << sp.shedLevel
+	atomic.StoreInt64(&sp.pauseUntil, time.Now().Add(pause).UnixNano())
+	metrics.TopSQLShedCounter.Inc()
+	logutil.BgLogger().Warn("top sql overhead exceeds the limit, pause the collection",
+		zap.Float64("overhead-percentage", percentage),
+		zap.Float64("max-overhead-percentage", maxPercentage),
+		zap.Int("profile-size", profileSize),
+		zap.Duration("pause", pause))
+}
+
+// IsShedding returns true if the collection is paused because of the overhead. It exports for tests.
+func (sp *sqlCPUProfiler) IsShedding() bool {
+	if variable.TopSQLVariable.MaxOverheadPercentage.Load() <= 0 || sp.hasExportProfileTask() {
+		return false
+	}
+	return time.Now().UnixNano() < atomic.LoadInt64(&sp.pauseUntil)
+}
+
 type profileData struct {
 	buf *bytes.Buffer
 	end int64
+	// The profiling window is windowFactor * precisionSeconds seconds.
+	precisionSeconds int64
+	windowFactor     int64
+}
+
+// spreadCPUTime spreads the records of a profiling window evenly over its windowFactor sub-windows, so the series
+// keeps a point for each PrecisionSeconds rather than a spike for the whole window. The CPU samples don't carry the
+// time, so the CPU time of each sub-window is the average of the window, and the remainder goes to the last one.
+// The other fields, e.g. the execution statistics, are kept in the last sub-window, which ends with the window.
+func spreadCPUTime(stats []SQLCPUTimeRecord, windowFactor int64) [][]SQLCPUTimeRecord {
+	if windowFactor <= 1 {
+		return [][]SQLCPUTimeRecord{stats}
+	}
+	result := make([][]SQLCPUTimeRecord, windowFactor)
+	for i := range stats {
+		avg := stats[i].CPUTimeMs / uint32(windowFactor)
+		if avg == 0 {
+			continue
+		}
+		for j := int64(0); j < windowFactor-1; j++ {
+			result[j] = append(result[j], SQLCPUTimeRecord{
+				SQLDigest:  stats[i].SQLDigest,
+				PlanDigest: stats[i].PlanDigest,
+				DB:         stats[i].DB,
+				User:       stats[i].User,
+				CPUTimeMs:  avg,
+			})
+		}
+		stats[i].CPUTimeMs -= avg * uint32(windowFactor-1)
+	}
+	result[windowFactor-1] = stats
+	return result
 }
 
 func (sp *sqlCPUProfiler) newProfileTask() *profileData {
@@ -171,28 +372,36 @@ func (sp *sqlCPUProfiler) putTaskToBuffer(task *profileData) {
 	profileBufPool.Put(task.buf)
 }
 
-// parseCPUProfileBySQLLabels uses to aggregate the cpu-profile sample data by sql_digest and plan_digest labels,
+// parseCPUProfileBySQLLabels uses to aggregate the cpu-profile sample data by sql_digest, plan_digest, db and user labels,
 // output the TopSQLCPUTimeRecord slice. Want to know more information about profile labels, see https://rakyll.org/profiler-labels/
 // The sql_digest label is been set by `SetSQLLabels` function after parse the SQL.
 // The plan_digest label is been set by `SetSQLAndPlanLabels` function after build the SQL plan.
 // Since `sqlCPUProfiler` only care about the cpu time that consume by (sql_digest,plan_digest), the other sample data
 // without those label will be ignore.
 func (sp *sqlCPUProfiler) parseCPUProfileBySQLLabels(p *profile.Profile) []SQLCPUTimeRecord {
-	sqlMap := make(map[string]*sqlStats)
+	sqlMap := make(map[sqlStatsKey]*sqlStats)
 	idx := len(p.SampleType) - 1
 	for _, s := range p.Sample {
 		digests, ok := s.Label[labelSQLDigest]
 		if !ok || len(digests) == 0 {
 			continue
 		}
+		var db, user string
+		if dbs := s.Label[labelDB]; len(dbs) > 0 {
+			db = dbs[0]
+		}
+		if users := s.Label[labelUser]; len(users) > 0 {
+			user = users[0]
+		}
 		for _, digest := range digests {
-			stmt, ok := sqlMap[digest]
+			key := sqlStatsKey{sqlDigest: digest, db: db, user: user}
+			stmt, ok := sqlMap[key]
 			if !ok {
 				stmt = &sqlStats{
 					plans: make(map[string]int64),
 					total: 0,
 				}
-				sqlMap[digest] = stmt
+				sqlMap[key] = stmt
 			}
 			stmt.total += s.Value[idx]
 
@@ -205,14 +414,16 @@ func (sp *sqlCPUProfiler) parseCPUProfileBySQLLabels(p *profile.Profile) []SQLCP
 	return sp.createSQLStats(sqlMap)
 }
 
-func (sp *sqlCPUProfiler) createSQLStats(sqlMap map[string]*sqlStats) []SQLCPUTimeRecord {
+func (sp *sqlCPUProfiler) createSQLStats(sqlMap map[sqlStatsKey]*sqlStats) []SQLCPUTimeRecord {
 	stats := make([]SQLCPUTimeRecord, 0, len(sqlMap))
-	for sqlDigest, stmt := range sqlMap {
+	for key, stmt := range sqlMap {
 		stmt.tune()
 		for planDigest, val := range stmt.plans {
 			stats = append(stats, SQLCPUTimeRecord{
-				SQLDigest:  []byte(sqlDigest),
+				SQLDigest:  []byte(key.sqlDigest),
 				PlanDigest: []byte(planDigest),
+				DB:         key.db,
+				User:       key.user,
 				CPUTimeMs:  uint32(time.Duration(val).Milliseconds()),
 			})
 		}
@@ -220,6 +431,104 @@ func (sp *sqlCPUProfiler) createSQLStats(sqlMap map[string]*sqlStats) []SQLCPUTi
 	return stats
 }
 
+// sqlStatsKey is the dimensions the CPU time of the statements is aggregated by, besides the plan digest.
+type sqlStatsKey struct {
+	sqlDigest string
+	db        string
+	user      string
+}
+
+type execStatsKey struct {
+	sqlDigest  string
+	planDigest string
+	db         string
+	user       string
+}
+
+type execStats struct {
+	count         uint32
+	sumDurationNs uint64
+	sumRows       uint64
+	sumWaitTimeNs [NumWaitTypes]uint64
+	// stmtInstanceIDs are the IDs of the finished executions, at most MaxStmtInstanceIDsPerRecord IDs are kept.
+	stmtInstanceIDs []string
+}
+
+// RecordExecution records the statistics of a finished statement execution, which are attached to the
+// SQLCPUTimeRecord of the statement in the current profiling window. waitTime is indexed by WaitType.
+func (sp *sqlCPUProfiler) RecordExecution(sqlDigest, planDigest []byte, db, user, stmtInstanceID string, duration time.Duration, rows uint64, waitTime [NumWaitTypes]time.Duration) {
+	if !sp.IsEnabled() {
+		return
+	}
+	key := execStatsKey{sqlDigest: string(sqlDigest), planDigest: string(planDigest), db: db, user: user}
+	sp.execStats.Lock()
+	defer sp.execStats.Unlock()
+	if sp.execStats.m == nil {
+		sp.execStats.m = make(map[execStatsKey]*execStats)
+	}
+	stats, ok := sp.execStats.m[key]
+	if !ok {
+		// Limit the memory usage when the collection is paused.
+		if int64(len(sp.execStats.m)) >= variable.TopSQLVariable.MaxCollect.Load() {
+			return
+		}
+		stats = &execStats{}
+		sp.execStats.m[key] = stats
+	}
+	stats.count++
+	stats.sumDurationNs += uint64(duration.Nanoseconds())
+	stats.sumRows += rows
+	for i, d := range waitTime {
+		stats.sumWaitTimeNs[i] += uint64(d.Nanoseconds())
+	}
+	if stmtInstanceID != "" && len(stats.stmtInstanceIDs) < MaxStmtInstanceIDsPerRecord {
+		stats.stmtInstanceIDs = append(stats.stmtInstanceIDs, stmtInstanceID)
+	}
+}
+
+// mergeExecStats takes out the execution statistics and attaches them to the records of the same digests, a new
+// record is appended if the statement has no CPU time in this profiling window.
+func (sp *sqlCPUProfiler) mergeExecStats(records []SQLCPUTimeRecord) []SQLCPUTimeRecord {
+	sp.execStats.Lock()
+	m := sp.execStats.m
+	sp.execStats.m = nil
+	sp.execStats.Unlock()
+	if len(m) == 0 {
+		return records
+	}
+	for i := range records {
+		key := execStatsKey{
+			sqlDigest:  string(records[i].SQLDigest),
+			planDigest: string(records[i].PlanDigest),
+			db:         records[i].DB,
+			user:       records[i].User,
+		}
+		if stats, ok := m[key]; ok {
+			records[i].ExecCount = stats.count
+			records[i].SumDurationNs = stats.sumDurationNs
+			records[i].SumRows = stats.sumRows
+			records[i].SumWaitTimeNs = stats.sumWaitTimeNs
+			records[i].StmtInstanceIDs = stats.stmtInstanceIDs
+			delete(m, key)
+		}
+	}
+	for key, stats := range m {
+		records = append(records, SQLCPUTimeRecord{
+			SQLDigest:     []byte(key.sqlDigest),
+			PlanDigest:    []byte(key.planDigest),
+			DB:            key.db,
+			User:          key.user,
+			ExecCount:     stats.count,
+			SumDurationNs: stats.sumDurationNs,
+			SumRows:       stats.sumRows,
+			SumWaitTimeNs: stats.sumWaitTimeNs,
+
+			StmtInstanceIDs: stats.stmtInstanceIDs,
+		})
+	}
+	return records
+}
+
 type sqlStats struct {
 	plans map[string]int64
 	total int64
@@ -309,6 +618,11 @@ func CtxWithDigest(ctx context.Context, sqlDigest, planDigest []byte) context.Co
 		labelPlanDigest, string(hack.String(planDigest))))
 }
 
+// CtxWithSessionInfo wrap the ctx with the current database and the user of the session.
+func CtxWithSessionInfo(ctx context.Context, db, user string) context.Context {
+	return pprof.WithLabels(ctx, pprof.Labels(labelDB, db, labelUser, user))
+}
+
 func (sp *sqlCPUProfiler) startExportCPUProfile(w io.Writer) error {
 	sp.mu.Lock()
 	defer sp.mu.Unlock()
@@ -349,7 +663,7 @@ func (sp *sqlCPUProfiler) removeLabel(p *profile.Profile) {
 				if !keepLabelSQL {
 					delete(s.Label, k)
 				}
-			case labelSQLDigest, labelPlanDigest:
+			case labelSQLDigest, labelPlanDigest, labelDB, labelUser:
 				delete(s.Label, k)
 			}
 		}
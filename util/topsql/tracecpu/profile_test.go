@@ -0,0 +1,98 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracecpu
+
+import (
+	"sync/atomic"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/sessionctx/variable"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = SerialSuites(&testProfileSuite{})
+
+type testProfileSuite struct{}
+
+func (s *testProfileSuite) TestAdaptiveWindow(c *C) {
+	variable.TopSQLVariable.AdaptiveInterval.Store(true)
+	defer variable.TopSQLVariable.AdaptiveInterval.Store(variable.DefTiDBTopSQLAdaptiveInterval)
+
+	sp := newSQLCPUProfiler()
+	// The window is doubled under high QPS, up to maxWindowFactor times.
+	for i := 0; i < 5; i++ {
+		sp.adjustWindowFactor(adaptiveHighQPS*sp.windowFactor, sp.windowFactor)
+	}
+	c.Assert(sp.windowFactor, Equals, int64(maxWindowFactor))
+	// The window isn't changed if the QPS is between the thresholds.
+	sp.adjustWindowFactor(adaptiveHighQPS/2*sp.windowFactor, sp.windowFactor)
+	c.Assert(sp.windowFactor, Equals, int64(maxWindowFactor))
+	// The window is halved when the QPS falls back.
+	sp.adjustWindowFactor(0, sp.windowFactor)
+	c.Assert(sp.windowFactor, Equals, int64(maxWindowFactor/2))
+
+	// The window is reset if the adaptive mode is off.
+	variable.TopSQLVariable.AdaptiveInterval.Store(false)
+	sp.adjustWindowFactor(adaptiveHighQPS, 1)
+	c.Assert(sp.windowFactor, Equals, int64(1))
+}
+
+func (s *testProfileSuite) TestSpreadCPUTime(c *C) {
+	stats := []SQLCPUTimeRecord{
+		{SQLDigest: []byte("sql1"), CPUTimeMs: 10, ExecCount: 3},
+		{SQLDigest: []byte("sql2"), CPUTimeMs: 2, ExecCount: 1},
+	}
+	spread := spreadCPUTime(stats, 1)
+	c.Assert(spread, HasLen, 1)
+	c.Assert(spread[0], HasLen, 2)
+
+	spread = spreadCPUTime(stats, 4)
+	c.Assert(spread, HasLen, 4)
+	for i := 0; i < 3; i++ {
+		// The CPU time of sql2 is less than 1ms per second, it's kept in the last second.
+		c.Assert(spread[i], HasLen, 1)
+		c.Assert(spread[i][0].SQLDigest, DeepEquals, []byte("sql1"))
+		c.Assert(spread[i][0].CPUTimeMs, Equals, uint32(2))
+		c.Assert(spread[i][0].ExecCount, Equals, uint32(0))
+	}
+	// The remainder and the execution statistics are in the last second.
+	c.Assert(spread[3], HasLen, 2)
+	c.Assert(spread[3][0].CPUTimeMs, Equals, uint32(4))
+	c.Assert(spread[3][0].ExecCount, Equals, uint32(3))
+	c.Assert(spread[3][1].CPUTimeMs, Equals, uint32(2))
+	c.Assert(spread[3][1].ExecCount, Equals, uint32(1))
+}
+
+func (s *testProfileSuite) TestIdle(c *C) {
+	sp := newSQLCPUProfiler()
+	atomic.StoreInt32(&sp.idle, 1)
+	variable.TopSQLVariable.AdaptiveInterval.Store(false)
+	c.Assert(sp.IsIdle(), IsFalse)
+
+	variable.TopSQLVariable.AdaptiveInterval.Store(true)
+	defer variable.TopSQLVariable.AdaptiveInterval.Store(variable.DefTiDBTopSQLAdaptiveInterval)
+	c.Assert(sp.IsIdle(), IsTrue)
+	// The profiling is resumed once a statement starts.
+	sp.RecordStatementStart()
+	c.Assert(sp.IsIdle(), IsFalse)
+	atomic.StoreInt64(&sp.stmtCount, 0)
+	c.Assert(sp.IsIdle(), IsTrue)
+	// The profiling isn't suspended while exporting the profile.
+	sp.mu.ept = &exportProfileTask{}
+	c.Assert(sp.IsIdle(), IsFalse)
+}
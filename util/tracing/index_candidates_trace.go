@@ -0,0 +1,73 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+const (
+	// IndexCandidateChosen means the access path has the least cost for the required property.
+	IndexCandidateChosen = "chosen"
+	// IndexCandidatePruned means the access path is pruned before its cost is estimated.
+	IndexCandidatePruned = "pruned"
+	// IndexCandidateRejected means the cost of the access path is estimated but it isn't chosen.
+	IndexCandidateRejected = "rejected"
+)
+
+// IndexCandidateRecord is the decision made by the optimizer on an access path of a table.
+type IndexCandidateRecord struct {
+	// Table is the table, or its alias, that the access path reads.
+	Table string
+	// Candidate is the access path, e.g. the index name or "table scan".
+	Candidate string
+	// RequiredProp is the task type and the order required by the parent operators, the same access path may be
+	// decided several times for different required properties.
+	RequiredProp string
+	// AccessConds is the conditions used to build the ranges of the access path.
+	AccessConds string
+	// MatchOrder indicates whether the access path can keep the required order.
+	MatchOrder bool
+	// SingleScan indicates whether the access path needs no double read.
+	SingleScan bool
+	// Cost is the estimated cost, it's empty if the path is pruned before the cost is estimated.
+	Cost string
+	// Result is one of IndexCandidateChosen, IndexCandidatePruned and IndexCandidateRejected.
+	Result string
+	// Reason describes why the access path is pruned or rejected.
+	Reason string
+}
+
+// IndexCandidatesTracer records the decisions made on the access paths of a statement, so users can understand
+// why an index isn't chosen without trial-and-error hints.
+type IndexCandidatesTracer struct {
+	records []*IndexCandidateRecord
+	// recorded is used to dedup the decisions, the optimizer may find the best task for the same property many times.
+	recorded map[IndexCandidateRecord]struct{}
+}
+
+// NewIndexCandidatesTracer creates an IndexCandidatesTracer.
+func NewIndexCandidatesTracer() *IndexCandidatesTracer {
+	return &IndexCandidatesTracer{recorded: make(map[IndexCandidateRecord]struct{})}
+}
+
+// Record records a decision on an access path.
+func (t *IndexCandidatesTracer) Record(r IndexCandidateRecord) {
+	if _, ok := t.recorded[r]; ok {
+		return
+	}
+	t.recorded[r] = struct{}{}
+	t.records = append(t.records, &r)
+}
+
+// Records returns the recorded decisions in order.
+func (t *IndexCandidatesTracer) Records() []*IndexCandidateRecord {
+	return t.records
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import "fmt"
+
+// StatsTraceRecord is a step of the cardinality estimation.
+type StatsTraceRecord struct {
+	// Operator is the logical operator whose row count is being estimated.
+	Operator string
+	// Step is the kind of the estimation, e.g. "equal", "range" or "selectivity".
+	Step string
+	// Object is the table, column or index whose statistics are consulted.
+	Object string
+	// Detail describes the statistics consulted and the formula applied.
+	Detail string
+	// Result is the row count or the selectivity estimated by the step.
+	Result float64
+}
+
+// StatsTracer records the cardinality estimation steps of a statement, so the estimation can be diagnosed
+// without reading the optimizer source.
+type StatsTracer struct {
+	operator string
+	records  []*StatsTraceRecord
+	// recorded is used to dedup the steps, the optimizer may estimate the same conditions many times.
+	recorded map[StatsTraceRecord]struct{}
+}
+
+// NewStatsTracer creates a StatsTracer.
+func NewStatsTracer() *StatsTracer {
+	return &StatsTracer{recorded: make(map[StatsTraceRecord]struct{})}
+}
+
+// EnterOperator sets the operator which the following steps belong to, and returns the function to restore
+// the previous one.
+func (t *StatsTracer) EnterOperator(operator string) func() {
+	prev := t.operator
+	t.operator = operator
+	return func() {
+		t.operator = prev
+	}
+}
+
+// Record records a step of the current operator.
+func (t *StatsTracer) Record(step, object string, result float64, format string, args ...interface{}) {
+	r := StatsTraceRecord{
+		Operator: t.operator,
+		Step:     step,
+		Object:   object,
+		Detail:   fmt.Sprintf(format, args...),
+		Result:   result,
+	}
+	if _, ok := t.recorded[r]; ok {
+		return
+	}
+	t.recorded[r] = struct{}{}
+	t.records = append(t.records, &r)
+}
+
+// Records returns the recorded steps in order.
+func (t *StatsTracer) Records() []*StatsTraceRecord {
+	return t.records
+}
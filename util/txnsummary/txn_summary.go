@@ -0,0 +1,201 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txnsummary
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/tikv/client-go/v2/tikv"
+	"github.com/tikv/client-go/v2/util"
+)
+
+// TxnRecord is the commit breakdown of a committed transaction.
+// The primary key of the two-phase commit isn't recorded, client-go doesn't report the key chosen by its committer.
+type TxnRecord struct {
+	StartTS uint64
+	// CommitTS is decoded from TxnInfo when the record is read if it's not set.
+	CommitTS     uint64
+	ConnectionID uint64
+	FinishTime   time.Time
+	CommitDetail *util.CommitDetails
+	// TxnInfo is the info of the commit in JSON, which is reported by the commit hook of the transaction.
+	TxnInfo string
+	// SQLDigests are the digests of the statements executed in the transaction, which are used to diagnose the
+	// write conflicts caused by it.
+	SQLDigests []string
+}
+
+func (rec *TxnRecord) commitTS() uint64 {
+	if rec.CommitTS > 0 || len(rec.TxnInfo) == 0 {
+		return rec.CommitTS
+	}
+	// The commit hook isn't called for the internal sessions, so check the start ts before trusting the info.
+	var info tikv.TxnInfo
+	if err := json.Unmarshal([]byte(rec.TxnInfo), &info); err == nil && info.StartTS == rec.StartTS {
+		return info.CommitTS
+	}
+	return 0
+}
+
+// txnRing is a fixed size ring buffer of the records. The records are pushed without locks, the slot of a record is
+// taken by increasing `next`, so the valid elements are records[(next-size)%len:next%len].
+type txnRing struct {
+	records []unsafe.Pointer // *TxnRecord
+	next    uint64
+}
+
+func (r *txnRing) getAll() []*TxnRecord {
+	capacity := uint64(len(r.records))
+	next := atomic.LoadUint64(&r.next)
+	first := uint64(0)
+	if next > capacity {
+		first = next - capacity
+	}
+	res := make([]*TxnRecord, 0, next-first)
+	for i := first; i < next; i++ {
+		// The slot may still be empty if the push is in progress.
+		if rec := (*TxnRecord)(atomic.LoadPointer(&r.records[i%capacity])); rec != nil {
+			res = append(res, rec)
+		}
+	}
+	return res
+}
+
+// TxnSummary is a collection for maintaining the recently committed transactions. All its public APIs are thread
+// safe, and Push and Enabled, which are called by every commit, take no locks.
+type TxnSummary struct {
+	// mu serializes Resize and Clear, which replace the ring.
+	mu   sync.Mutex
+	ring unsafe.Pointer // *txnRing
+}
+
+// NewTxnSummary creates an instance of TxnSummary.
+func NewTxnSummary(capacity uint) *TxnSummary {
+	return &TxnSummary{
+		ring: unsafe.Pointer(&txnRing{records: make([]unsafe.Pointer, capacity)}),
+	}
+}
+
+// GlobalTxnSummary is the global instance of TxnSummary, which is used to maintain the recently committed
+// transactions globally.
+// The real capacity should be initialized with `Resize` in `setGlobalVars` in tidb-server/main.go
+var GlobalTxnSummary = NewTxnSummary(0)
+
+func (s *TxnSummary) loadRing() *txnRing {
+	return (*txnRing)(atomic.LoadPointer(&s.ring))
+}
+
+// Resize updates the max capacity of the TxnSummary to newCapacity, the most recent records are kept. The records
+// pushed concurrently with the resizing may be lost.
+func (s *TxnSummary) Resize(newCapacity uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old := s.loadRing()
+	if newCapacity == uint(len(old.records)) {
+		return
+	}
+	current := old.getAll()
+	if uint(len(current)) > newCapacity {
+		current = current[uint(len(current))-newCapacity:]
+	}
+	ring := &txnRing{records: make([]unsafe.Pointer, newCapacity), next: uint64(len(current))}
+	for i, rec := range current {
+		ring.records[i] = unsafe.Pointer(rec)
+	}
+	atomic.StorePointer(&s.ring, unsafe.Pointer(ring))
+}
+
+// Enabled indicates whether the committed transactions are recorded.
+func (s *TxnSummary) Enabled() bool {
+	return len(s.loadRing().records) > 0
+}
+
+// Push adds the record of a committed transaction, the oldest record is evicted if the collection is full. Be
+// aware that do not modify the record's content after pushing.
+func (s *TxnSummary) Push(record *TxnRecord) {
+	ring := s.loadRing()
+	capacity := uint64(len(ring.records))
+	if capacity == 0 {
+		return
+	}
+	slot := atomic.AddUint64(&ring.next, 1) - 1
+	atomic.StorePointer(&ring.records[slot%capacity], unsafe.Pointer(record))
+}
+
+// GetAll gets all the recorded transactions, ordered from the oldest to the latest.
+func (s *TxnSummary) GetAll() []*TxnRecord {
+	return s.loadRing().getAll()
+}
+
+// FindByStartTS finds the recorded transaction of the start ts, nil is returned if it isn't recorded.
+func (s *TxnSummary) FindByStartTS(startTS uint64) *TxnRecord {
+	ring := s.loadRing()
+	for i := range ring.records {
+		if rec := (*TxnRecord)(atomic.LoadPointer(&ring.records[i])); rec != nil && rec.StartTS == startTS {
+			return rec
+		}
+	}
+	return nil
+}
+
+// GetAllDatum gets all the recorded transactions, and makes them into datum that matches the definition of the
+// table `INFORMATION_SCHEMA.TRANSACTION_SUMMARY`.
+func (s *TxnSummary) GetAllDatum() [][]types.Datum {
+	records := s.GetAll()
+	rows := make([][]types.Datum, 0, len(records))
+	for _, rec := range records {
+		row := []interface{}{
+			rec.StartTS,
+			rec.commitTS(),
+			rec.ConnectionID,
+			types.NewTime(types.FromGoTime(rec.FinishTime), mysql.TypeTimestamp, types.MaxFsp),
+		}
+		detail := rec.CommitDetail
+		if detail == nil {
+			detail = &util.CommitDetails{}
+		}
+		detail.Mu.Lock()
+		row = append(row,
+			detail.GetCommitTsTime.Seconds(),
+			detail.PrewriteTime.Seconds(),
+			detail.CommitTime.Seconds(),
+			time.Duration(detail.ResolveLockTime).Seconds(),
+			detail.LocalLatchTime.Seconds(),
+			time.Duration(detail.Mu.CommitBackoffTime).Seconds(),
+			strings.Join(detail.Mu.BackoffTypes, ","),
+			int64(detail.WriteKeys),
+			int64(detail.WriteSize),
+			int64(detail.PrewriteRegionNum),
+			int64(detail.TxnRetry),
+		)
+		detail.Mu.Unlock()
+		rows = append(rows, types.MakeDatums(row...))
+	}
+	return rows
+}
+
+// Clear clears all the recorded transactions.
+func (s *TxnSummary) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	capacity := len(s.loadRing().records)
+	atomic.StorePointer(&s.ring, unsafe.Pointer(&txnRing{records: make([]unsafe.Pointer, capacity)}))
+}
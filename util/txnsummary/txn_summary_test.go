@@ -0,0 +1,122 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txnsummary
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/pingcap/check"
+	"github.com/tikv/client-go/v2/util"
+)
+
+type testTxnSummarySuite struct{}
+
+var _ = Suite(&testTxnSummarySuite{})
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+func (s *testTxnSummarySuite) TestTxnSummaryCollection(c *C) {
+	summary := NewTxnSummary(0)
+	c.Assert(summary.Enabled(), IsFalse)
+	summary.Push(&TxnRecord{StartTS: 1})
+	c.Assert(len(summary.GetAll()), Equals, 0)
+
+	summary.Resize(2)
+	c.Assert(summary.Enabled(), IsTrue)
+	records := make([]*TxnRecord, 0, 3)
+	for i := 1; i <= 3; i++ {
+		rec := &TxnRecord{StartTS: uint64(i)}
+		records = append(records, rec)
+		summary.Push(rec)
+	}
+	res := summary.GetAll()
+	c.Assert(len(res), Equals, 2)
+	// The oldest record is evicted.
+	c.Assert(res[0], Equals, records[1])
+	c.Assert(res[1], Equals, records[2])
+
+	summary.Resize(3)
+	summary.Push(records[0])
+	res = summary.GetAll()
+	c.Assert(len(res), Equals, 3)
+	c.Assert(res[0], Equals, records[1])
+	c.Assert(res[2], Equals, records[0])
+
+	// The most recent records are kept when shrinking.
+	summary.Resize(1)
+	res = summary.GetAll()
+	c.Assert(len(res), Equals, 1)
+	c.Assert(res[0], Equals, records[0])
+
+	c.Assert(summary.FindByStartTS(1), Equals, records[0])
+	c.Assert(summary.FindByStartTS(2), IsNil)
+
+	summary.Clear()
+	c.Assert(len(summary.GetAll()), Equals, 0)
+	c.Assert(summary.FindByStartTS(1), IsNil)
+}
+
+func (s *testTxnSummarySuite) TestGetAllDatum(c *C) {
+	summary := NewTxnSummary(2)
+	finishTime := time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)
+	detail := &util.CommitDetails{
+		GetCommitTsTime:   time.Second,
+		PrewriteTime:      2 * time.Second,
+		CommitTime:        3 * time.Second,
+		LocalLatchTime:    4 * time.Second,
+		ResolveLockTime:   int64(5 * time.Second),
+		WriteKeys:         6,
+		WriteSize:         7,
+		PrewriteRegionNum: 8,
+		TxnRetry:          9,
+	}
+	detail.Mu.CommitBackoffTime = int64(10 * time.Second)
+	detail.Mu.BackoffTypes = []string{"txnLock", "regionMiss"}
+	summary.Push(&TxnRecord{
+		StartTS:      100,
+		CommitTS:     101,
+		ConnectionID: 1,
+		FinishTime:   finishTime,
+		CommitDetail: detail,
+	})
+	summary.Push(&TxnRecord{StartTS: 200, FinishTime: finishTime, TxnInfo: `{"start_ts":200,"commit_ts":201}`})
+
+	rows := summary.GetAllDatum()
+	c.Assert(len(rows), Equals, 2)
+	c.Assert(len(rows[0]), Equals, 15)
+	c.Assert(rows[0][0].GetUint64(), Equals, uint64(100))
+	c.Assert(rows[0][1].GetUint64(), Equals, uint64(101))
+	c.Assert(rows[0][2].GetUint64(), Equals, uint64(1))
+	c.Assert(rows[0][3].GetMysqlTime().String(), Equals, "2021-07-01 12:00:00.000000")
+	for i, expected := range []float64{1, 2, 3, 5, 4, 10} {
+		c.Assert(rows[0][4+i].GetFloat64(), Equals, expected)
+	}
+	c.Assert(rows[0][10].GetString(), Equals, "txnLock,regionMiss")
+	for i, expected := range []int64{6, 7, 8, 9} {
+		c.Assert(rows[0][11+i].GetInt64(), Equals, expected)
+	}
+
+	// The commit ts is decoded from the commit info, and the transaction without the commit detail fills zero.
+	c.Assert(len(rows[1]), Equals, 15)
+	c.Assert(rows[1][1].GetUint64(), Equals, uint64(201))
+	c.Assert(rows[1][5].GetFloat64(), Equals, float64(0))
+
+	// The commit info of another transaction is ignored.
+	summary.Push(&TxnRecord{StartTS: 300, FinishTime: finishTime, TxnInfo: `{"start_ts":200,"commit_ts":201}`})
+	rows = summary.GetAllDatum()
+	c.Assert(rows[1][1].GetUint64(), Equals, uint64(0))
+}